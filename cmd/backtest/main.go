@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"osrs-flipping/pkg/backtest"
+	"osrs-flipping/pkg/collector"
+	"osrs-flipping/pkg/database"
+	"osrs-flipping/pkg/logging"
+	"osrs-flipping/pkg/storage"
+)
+
+const VERSION = "0.0.1"
+
+var (
+	itemsFlag    = flag.String("items", "", "Comma-separated item IDs to backtest (required)")
+	bucketSize   = flag.String("bucket", "5m", "Bucket table to replay: 5m, 1h, or 24h")
+	fromStr      = flag.String("from", "", "Start of the backtest window, as YYYY-MM-DD (required)")
+	toStr        = flag.String("to", "", "End of the backtest window, as YYYY-MM-DD (required)")
+	strategyName = flag.String("strategy", "margin", "Reference strategy to run: margin or ewo")
+	outDir       = flag.String("out", "output/backtest", "Directory to write the equity JSON and (if --graph) PNG chart into")
+	graph        = flag.Bool("graph", false, "Render equity.png into --out")
+)
+
+func main() {
+	flag.Parse()
+
+	logLevel := os.Getenv("LOG_LEVEL")
+	if logLevel == "" {
+		logLevel = "info"
+	}
+	logFormat := os.Getenv("LOG_FORMAT")
+	if logFormat == "" {
+		logFormat = "json"
+	}
+	logger := logging.NewLogger(logLevel, logFormat)
+
+	logger.WithComponent("backtest").WithField("version", VERSION).Info("starting strategy backtester")
+
+	if *itemsFlag == "" || *fromStr == "" || *toStr == "" {
+		log.Fatal("Usage: backtest --items <id,id,...> --from <YYYY-MM-DD> --to <YYYY-MM-DD> [--strategy margin|ewo] [--bucket 5m|1h|24h]")
+	}
+
+	from, err := time.Parse("2006-01-02", *fromStr)
+	if err != nil {
+		log.Fatalf("Invalid --from date %q: %v", *fromStr, err)
+	}
+	to, err := time.Parse("2006-01-02", *toStr)
+	if err != nil {
+		log.Fatalf("Invalid --to date %q: %v", *toStr, err)
+	}
+
+	var itemIDs []int
+	for _, s := range strings.Split(*itemsFlag, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			log.Fatalf("Invalid item ID %q: %v", s, err)
+		}
+		itemIDs = append(itemIDs, id)
+	}
+
+	// Load database configuration from environment
+	dbConfig, err := database.ConfigFromEnv()
+	if err != nil {
+		logger.WithComponent("backtest").WithError(err).Fatal("failed to load database configuration")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	db, err := database.Connect(ctx, dbConfig)
+	cancel()
+	if err != nil {
+		logger.WithComponent("backtest").WithError(err).Fatal("failed to connect to database")
+	}
+	defer db.Close()
+
+	logger.WithComponent("backtest").Info("connected to database")
+
+	itemRepo := collector.NewRepository(db.Pool)
+	queryRepo := storage.NewQueryRepository(db.Pool)
+
+	runCtx, runCancel := context.WithTimeout(context.Background(), 20*time.Minute)
+	defer runCancel()
+
+	var items []backtest.ItemMeta
+	for _, id := range itemIDs {
+		item, err := itemRepo.GetItem(runCtx, id)
+		if err != nil {
+			logger.WithComponent("backtest").WithError(err).Fatalf("failed to load item %d", id)
+		}
+		if item == nil {
+			logger.WithComponent("backtest").WithField("item_id", id).Warn("item not found, skipping")
+			continue
+		}
+		buyLimit := 0
+		if item.BuyLimit != nil {
+			buyLimit = *item.BuyLimit
+		}
+		items = append(items, backtest.ItemMeta{ItemID: item.ItemID, Name: item.Name, BuyLimit: buyLimit})
+	}
+	if len(items) == 0 {
+		log.Fatal("No valid items to backtest")
+	}
+
+	var strategy backtest.Strategy
+	switch *strategyName {
+	case "margin":
+		strategy = backtest.NewMarginThresholdStrategy(0.02, 100, 0.03, 2*time.Hour)
+	case "ewo":
+		strategy = backtest.NewEWOCrossoverStrategy(3, 19)
+	default:
+		log.Fatalf("Unknown --strategy %q: expected margin or ewo", *strategyName)
+	}
+
+	fmt.Printf("📊 Replaying %d item(s) against the %q strategy (%s to %s, %s buckets)...\n",
+		len(items), *strategyName, *fromStr, *toStr, *bucketSize)
+
+	result, err := backtest.Run(runCtx, queryRepo, items, strategy, backtest.Config{
+		BucketSize: *bucketSize,
+		From:       from,
+		To:         to,
+	})
+	if err != nil {
+		logger.WithComponent("backtest").WithError(err).Fatal("backtest run failed")
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		log.Fatalf("Failed to create output directory %s: %v", *outDir, err)
+	}
+
+	jsonPath := filepath.Join(*outDir, fmt.Sprintf("%s_%s.json", *strategyName, from.Format("2006-01-02")))
+	if err := result.WriteEquityJSON(jsonPath); err != nil {
+		log.Fatalf("Failed to write backtest equity JSON: %v", err)
+	}
+
+	if *graph {
+		pngPath := filepath.Join(*outDir, fmt.Sprintf("%s_%s_equity.png", *strategyName, from.Format("2006-01-02")))
+		if err := backtest.RenderEquityGraph(result, pngPath); err != nil {
+			logger.WithComponent("backtest").WithError(err).Warn("failed to render equity graph")
+		}
+	}
+
+	fmt.Printf("✅ Backtest complete: %d closed trades, win rate %.1f%%, PNL/hour %.1f GP, max drawdown %d GP\n",
+		result.Stats.ClosedTrades, result.Stats.WinRate*100, result.Stats.PNLPerHour, result.Stats.MaxDrawdownGP)
+	fmt.Printf("   Equity JSON: %s\n", jsonPath)
+}
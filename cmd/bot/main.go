@@ -3,16 +3,18 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"osrs-flipping/pkg/config"
 	"osrs-flipping/pkg/discord"
 	"osrs-flipping/pkg/jobs"
+	"osrs-flipping/pkg/llm"
 	"osrs-flipping/pkg/logging"
 	"osrs-flipping/pkg/scheduler"
+	"osrs-flipping/pkg/scheduler/docker"
+	"osrs-flipping/pkg/shutdown"
 )
 
 const VERSION = "0.0.11"
@@ -30,6 +32,11 @@ func main() {
 
 	logger.WithComponent("main").WithField("version", VERSION).Info("starting_osrs_flips_bot")
 
+	// Coordinate graceful shutdown: cancel rootCtx on SIGINT/SIGTERM, then
+	// close registered hooks in LIFO order once in-flight work drains.
+	// SIGHUP instead triggers a config reload (see cfg.Watch below).
+	shutdownCoordinator, rootCtx := shutdown.New(logger, 30*time.Second)
+
 	// Initialize job runner (unified with main program)
 	jobRunner, err := jobs.NewJobRunner(cfg)
 	if err != nil {
@@ -70,16 +77,29 @@ func main() {
 		if _, err := discordBot.SendMessage(fmt.Sprintf("🏰 **osrs-flips v%s** has logged in.", VERSION)); err != nil {
 			logger.WithDiscord().WithError(err).Warn("Failed to send startup message")
 		}
+
+		discordBot.SetJobCanceler(jobRunner)
+		discordBot.SubscribeProgress(convertProgress(jobRunner.Progress()))
 	} else {
 		logger.WithComponent("main").Warn("Discord configuration missing - bot will run without Discord integration")
 	}
 
+	// Exec-kind jobs need a Docker client; this is optional infrastructure
+	// (most deployments only run analysis jobs), so a failure to connect
+	// just disables exec-kind jobs rather than failing startup.
+	execRunner, err := docker.NewExecRunner()
+	if err != nil {
+		logger.WithComponent("main").WithError(err).Warn("Docker client unavailable, exec-kind jobs will fail if scheduled")
+		execRunner = nil
+	}
+
 	// Initialize bot executor that wraps the job runner
 	botExecutor := &BotExecutor{
 		jobRunner:  jobRunner,
 		formatter:  formatter,
 		discordBot: discordBot,
 		logger:     logger,
+		execRunner: execRunner,
 	}
 
 	// Initialize and start scheduler
@@ -89,6 +109,71 @@ func main() {
 	}
 	sched.Start()
 
+	if discordBot != nil {
+		sched.SetBreakerNotifier(discordBot)
+		discordBot.SetJobStatusProvider(&schedulerStatusAdapter{sched: sched})
+		discordBot.SetCronDescriber(&schedulerCronAdapter{sched: sched})
+	}
+
+	shutdownCoordinator.Register("scheduler", func(ctx context.Context) error {
+		sched.Stop()
+		return nil
+	})
+
+	if discordBot != nil {
+		shutdownCoordinator.Register("discord_bot", func(ctx context.Context) error {
+			if _, err := discordBot.SendMessage("☠️ Oh dear, **osrs-flips** has died. a q p "); err != nil {
+				logger.WithDiscord().WithError(err).Warn("Failed to send shutdown message")
+			}
+			// Give a moment for the message to send
+			time.Sleep(2 * time.Second)
+			return discordBot.Stop()
+		})
+	}
+
+	shutdownCoordinator.Register("logger", func(ctx context.Context) error {
+		// logrus writes synchronously, so there's nothing to flush, but the
+		// hook keeps the shutdown log ordering explicit.
+		logger.WithComponent("main").Info("osrs-flips shutdown complete")
+		return nil
+	})
+
+	// Expose Prometheus metrics for the LLM client(s) jobRunner built and
+	// the scheduler, mirroring how pkg/collector's cmd mounts Repository's
+	// metrics handler.
+	metricsAddr := os.Getenv("METRICS_ADDR")
+	if metricsAddr == "" {
+		metricsAddr = ":9090"
+	}
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", llm.NewMetricsHandler())
+	metricsMux.Handle("/metrics/scheduler", sched.MetricsHandler())
+	metricsServer := &http.Server{Addr: metricsAddr, Handler: metricsMux}
+
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.WithComponent("main").WithError(err).Error("Metrics server failed")
+		}
+	}()
+
+	shutdownCoordinator.Register("metrics_server", func(ctx context.Context) error {
+		return metricsServer.Shutdown(ctx)
+	})
+
+	// Hot-reload config.yml on SIGHUP or a file-system change, so the log
+	// level can be tuned without a restart. A reload that fails validation
+	// is logged and discarded by cfg.Watch itself; the process keeps
+	// running on the previously loaded config either way.
+	cfgWatcher, err := cfg.Watch(rootCtx, "config.yml", logger)
+	if err != nil {
+		logger.WithComponent("main").WithError(err).Warn("Config hot-reload unavailable")
+	} else {
+		cfgWatcher.OnReload(func(old, new *config.Config) {
+			logger.SetLevel(new.Logging.Level)
+			logger.WithComponent("config").WithField("level", new.Logging.Level).Info("Config reloaded")
+		})
+	}
+
 	logger.WithComponent("main").WithFields(map[string]interface{}{
 		"jobs_loaded":      len(cfg.Jobs),
 		"schedules_active": len(cfg.Schedules),
@@ -99,32 +184,81 @@ func main() {
 	// logger.WithComponent("main").Info("Running initial job execution")
 	// sched.ExecuteAllJobs()
 
-	// Set up graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-	// Wait for shutdown signal
-	<-sigChan
-	logger.WithComponent("main").Info("Shutdown signal received, gracefully stopping...")
-
-	// Stop scheduler
-	sched.Stop()
+	// Block until a shutdown signal is received, then close hooks in LIFO
+	// order. This mode has no in-flight-job channel of its own (the
+	// scheduler runs jobs on its own goroutines), so drained is nil.
+	shutdownCoordinator.Wait(rootCtx, nil)
+}
 
-	// Stop Discord bot
-	if discordBot != nil {
-		if _, err := discordBot.SendMessage("☠️ Oh dear, **osrs-flips** has died. a q p "); err != nil {
-			logger.WithDiscord().WithError(err).Warn("Failed to send shutdown message")
+// convertProgress translates a channel of jobs.ProgressEvent into a channel
+// of discord.ProgressEvent, closing the returned channel once events closes,
+// so pkg/discord doesn't need to import pkg/jobs (which itself imports
+// pkg/discord for Executor's discordBot field -- see
+// schedulerStatusAdapter/schedulerCronAdapter for the same pattern against
+// pkg/scheduler).
+func convertProgress(events <-chan jobs.ProgressEvent) <-chan discord.ProgressEvent {
+	out := make(chan discord.ProgressEvent)
+	go func() {
+		defer close(out)
+		for event := range events {
+			out <- discord.ProgressEvent{
+				JobName:    event.JobName,
+				Stage:      discord.ProgressStage(event.Stage),
+				PercentPct: event.PercentPct,
+				ItemsDone:  event.ItemsDone,
+				ItemsTotal: event.ItemsTotal,
+				BytesDone:  event.BytesDone,
+				StartedAt:  event.StartedAt,
+				Emitted:    event.Emitted,
+			}
 		}
+	}()
+	return out
+}
 
-		// Give a moment for the message to send
-		time.Sleep(2 * time.Second)
+// schedulerStatusAdapter adapts *scheduler.Scheduler's JobStatus to
+// discord.JobStatusProvider, translating scheduler.BreakerStatus into
+// discord.JobBreakerStatus so pkg/discord doesn't need to import
+// pkg/scheduler.
+type schedulerStatusAdapter struct {
+	sched *scheduler.Scheduler
+}
 
-		if err := discordBot.Stop(); err != nil {
-			logger.WithDiscord().WithError(err).Error("Error stopping Discord bot")
-		}
+func (a *schedulerStatusAdapter) JobStatus(name string) (discord.JobBreakerStatus, bool) {
+	status, ok := a.sched.JobStatus(name)
+	if !ok {
+		return discord.JobBreakerStatus{}, false
+	}
+	out := discord.JobBreakerStatus{Enabled: status.Enabled}
+	if status.Breaker != nil {
+		out.ConsecutiveFailures = status.Breaker.ConsecutiveFailures
+		out.Paused = status.Breaker.Paused
+		out.SkipRemaining = status.Breaker.SkipRemaining
 	}
+	return out, true
+}
 
-	logger.WithComponent("main").Info("osrs-flips shutdown complete")
+// schedulerCronAdapter adapts *scheduler.Scheduler's DescribeSchedules to
+// discord.CronDescriber, translating scheduler.ScheduleDescription into
+// discord.ScheduleDescription so pkg/discord doesn't need to import
+// pkg/scheduler.
+type schedulerCronAdapter struct {
+	sched *scheduler.Scheduler
+}
+
+func (a *schedulerCronAdapter) DescribeSchedules() []discord.ScheduleDescription {
+	descs := a.sched.DescribeSchedules()
+	out := make([]discord.ScheduleDescription, 0, len(descs))
+	for _, d := range descs {
+		out = append(out, discord.ScheduleDescription{
+			JobName:  d.JobName,
+			Cron:     d.Cron,
+			Human:    d.Human,
+			Warnings: d.Warnings,
+			Next:     d.Next,
+		})
+	}
+	return out
 }
 
 // BotExecutor wraps the JobRunner to provide the interface expected by the scheduler
@@ -133,10 +267,15 @@ type BotExecutor struct {
 	formatter  *jobs.OutputFormatter
 	discordBot *discord.Bot
 	logger     *logging.Logger
+	execRunner *docker.ExecRunner
 }
 
 // ExecuteJob runs a job and posts results to Discord
 func (be *BotExecutor) ExecuteJob(ctx context.Context, job config.JobConfig) error {
+	if job.Kind == "exec" {
+		return be.executeExecJob(ctx, job)
+	}
+
 	// Run the job using the unified job runner
 	result, err := be.jobRunner.RunJob(ctx, job.Name)
 	if err != nil {
@@ -187,6 +326,47 @@ func (be *BotExecutor) ExecuteJob(ctx context.Context, job config.JobConfig) err
 	return nil
 }
 
+// executeExecJob runs an "exec"-kind job's command inside its configured
+// container and pipes the captured output into the logger and Discord,
+// mirroring how a failed/successful analysis job is reported.
+func (be *BotExecutor) executeExecJob(ctx context.Context, job config.JobConfig) error {
+	if job.Exec == nil {
+		return fmt.Errorf("job %s is kind=exec but has no exec config", job.Name)
+	}
+	if be.execRunner == nil {
+		return fmt.Errorf("job %s is kind=exec but no Docker client is configured", job.Name)
+	}
+
+	stdout, stderr, err := be.execRunner.Run(ctx, job.Exec.Container, job.Exec.Command)
+	if err != nil {
+		be.logger.WithComponent("bot").WithField("job", job.Name).WithField("container", job.Exec.Container).WithError(err).Error("Exec job failed")
+		if be.discordBot != nil {
+			if sendErr := be.discordBot.SendError(job.Name, err); sendErr != nil {
+				be.logger.WithDiscord().WithError(sendErr).Error("Failed to send exec job error to Discord")
+			}
+		}
+		return err
+	}
+
+	be.logger.WithComponent("bot").WithField("job", job.Name).WithField("container", job.Exec.Container).Info("Exec job completed successfully")
+
+	if be.discordBot != nil {
+		output := stdout
+		if stderr != "" {
+			output = fmt.Sprintf("%s\n--- stderr ---\n%s", output, stderr)
+		}
+		if output == "" {
+			output = "(no output)"
+		}
+		message := fmt.Sprintf("🔧 **%s** (exec in `%s`)\n```\n%s\n```", job.Name, job.Exec.Container, output)
+		if _, err := be.discordBot.SendMessage(message); err != nil {
+			be.logger.WithDiscord().WithError(err).Error("Failed to send exec job output to Discord")
+		}
+	}
+
+	return nil
+}
+
 // ExecuteAllJobs runs all enabled jobs
 func (be *BotExecutor) ExecuteAllJobs(ctx context.Context) error {
 	results, err := be.jobRunner.RunAllJobs(ctx)
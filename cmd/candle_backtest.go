@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"osrs-flipping/pkg/config"
+	"osrs-flipping/pkg/jobs"
+	"osrs-flipping/pkg/osrs/backtest"
+)
+
+// runCandleBacktest implements `osrs-flipping candle-backtest --filters
+// <path> --from <date> --to <date>`: it replays a standalone FilterOptions
+// YAML file against the wiki API's live /timeseries history, candle by
+// candle, simulating buy-limit-constrained fills with GE tax -- unlike
+// `backtest`, which only covers whatever window the analyzer's rolling
+// store has retained, this fetches history directly so any window the wiki
+// API still serves is fair game.
+func runCandleBacktest(args []string) {
+	fs := flag.NewFlagSet("candle-backtest", flag.ExitOnError)
+	filtersPath := fs.String("filters", "", "Path to a FilterOptions YAML file (same schema as a job's `filters:` block)")
+	fromStr := fs.String("from", "", "Start of the backtest window, as YYYY-MM-DD")
+	toStr := fs.String("to", "", "End of the backtest window, as YYYY-MM-DD")
+	timestep := fs.String("timestep", "1h", "Candle resolution to replay: 1h, 6h, or 24h")
+	outDir := fs.String("out", "output/backtest", "Directory to write the CSV, summary JSON, and (if --graph) PNG graphs into")
+	graph := fs.Bool("graph", false, "Render pnl.png and cumulative_pnl.png into --out")
+	archiveDir := fs.String("archive-dir", "", "Replay from local JSON timeseries files in this directory instead of the live wiki API (see backtest.FileCandleSource)")
+	fillModel := fs.String("fill-model", string(backtest.FillModelInstant), "Fill model: instant (quoted price) or midpoint_slippage (midpoint +/- --slippage-pct)")
+	slippagePct := fs.Float64("slippage-pct", 0, "Slippage applied to the midpoint under --fill-model=midpoint_slippage (e.g. 0.01 for 1%)")
+	fs.Parse(args)
+
+	if *filtersPath == "" || *fromStr == "" || *toStr == "" {
+		log.Fatal("Usage: osrs-flipping candle-backtest --filters <path.yml> --from <YYYY-MM-DD> --to <YYYY-MM-DD>")
+	}
+
+	from, err := time.Parse("2006-01-02", *fromStr)
+	if err != nil {
+		log.Fatalf("Invalid --from date %q: %v", *fromStr, err)
+	}
+	to, err := time.Parse("2006-01-02", *toStr)
+	if err != nil {
+		log.Fatalf("Invalid --to date %q: %v", *toStr, err)
+	}
+
+	data, err := os.ReadFile(*filtersPath)
+	if err != nil {
+		log.Fatalf("Failed to read filters file %s: %v", *filtersPath, err)
+	}
+	var filterConfig config.FilterConfig
+	if err := yaml.Unmarshal(data, &filterConfig); err != nil {
+		log.Fatalf("Failed to parse filters file %s: %v", *filtersPath, err)
+	}
+
+	cfg, err := config.LoadConfigForCLI("config.yml")
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	jobRunner, err := jobs.NewJobRunner(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create job runner: %v", err)
+	}
+
+	filterOpts, err := jobRunner.ConvertFilters(filterConfig)
+	if err != nil {
+		log.Fatalf("Failed to convert filters: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Minute)
+	defer cancel()
+
+	fmt.Printf("📊 Loading OSRS data for candle backtest (%s candles, %s to %s)...\n", *timestep, *fromStr, *toStr)
+	if err := jobRunner.LoadData(ctx); err != nil {
+		log.Fatalf("Failed to load OSRS data: %v", err)
+	}
+
+	analyzer := jobRunner.Analyzer()
+	items, err := analyzer.ApplyPrimaryFilter(filterOpts, false)
+	if err != nil {
+		log.Fatalf("Failed to apply primary filters: %v", err)
+	}
+	if len(items) == 0 {
+		log.Fatal("No items matched the given filters; nothing to backtest")
+	}
+
+	var source backtest.CandleSource
+	if *archiveDir != "" {
+		source = backtest.NewFileCandleSource(*archiveDir)
+	} else {
+		source = backtest.NewTimeseriesClient(analyzer.Client())
+	}
+	result, err := backtest.RunCandleReplay(ctx, source, items, backtest.CandleConfig{
+		JobName:     *filtersPath,
+		Filters:     filterOpts,
+		Timestep:    *timestep,
+		From:        from,
+		To:          to,
+		FillModel:   backtest.FillModel(*fillModel),
+		SlippagePct: *slippagePct,
+	})
+	if err != nil {
+		log.Fatalf("Candle backtest failed: %v", err)
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		log.Fatalf("Failed to create output directory %s: %v", *outDir, err)
+	}
+	if *graph {
+		if err := backtest.RenderGraphs(result, *outDir); err != nil {
+			log.Printf("⚠️  failed to render graphs: %v", err)
+		}
+	}
+
+	fmt.Printf("✅ Candle backtest complete: %d trades, win rate %.1f%%, realized profit %d GP (%.1f GP/hour), unrealized %d GP, max drawdown %d GP, Sharpe %.2f\n",
+		len(result.Trades), result.WinRate*100, result.RealizedProfitGP, result.GPPerHour, result.UnrealizedProfitGP, result.MaxDrawdownGP, result.SharpeRatio)
+	for _, item := range result.PerItem {
+		fmt.Printf("   %-30s realized %8d GP  unrealized %8d GP  win rate %.1f%%\n", item.Name, item.RealizedProfitGP, item.UnrealizedProfitGP, item.WinRate*100)
+	}
+}
@@ -4,26 +4,36 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"osrs-flipping/pkg/collector"
+	"osrs-flipping/pkg/config"
 	"osrs-flipping/pkg/database"
 	"osrs-flipping/pkg/logging"
 	"osrs-flipping/pkg/osrs"
+	"osrs-flipping/pkg/storage"
 )
 
 const VERSION = "0.0.1"
 
 var (
-	backfillMode   = flag.Bool("backfill", false, "Run historical backfill instead of continuous polling")
-	backfillOnly   = flag.String("backfill-bucket", "", "Backfill only specific bucket size (5m, 1h, 24h)")
-	gapFillMode    = flag.Bool("gap-fill", false, "Run gap filling to repair missing buckets within retention windows")
-	gapFillBucket  = flag.String("gap-fill-bucket", "", "Gap fill only specific bucket size (5m, 1h, 24h)")
-	gapFillItems   = flag.Int("gap-fill-items", 150, "Maximum items to process per gap fill run")
-	skipItemSync   = flag.Bool("skip-item-sync", false, "Skip initial item metadata sync from API")
+	backfillMode    = flag.Bool("backfill", false, "Run historical backfill instead of continuous polling")
+	backfillOnly    = flag.String("backfill-bucket", "", "Backfill only specific bucket size (5m, 1h, 24h)")
+	backfillSource  = flag.String("backfill-source", "api", "Historical data source for backfill mode: \"api\" or \"dump\"")
+	backfillDumpDir = flag.String("backfill-dump-dir", "", "Local directory of gzipped CSV dumps (see collector.DumpSource) for -backfill-source=dump. Parquet and fetching directly from an S3-compatible URL are not implemented yet; sync an S3 bucket into this layout with a tool like `aws s3 sync`/`rclone` first.")
+	gapFillMode     = flag.Bool("gap-fill", false, "Run gap filling to repair missing buckets within retention windows")
+	gapFillBucket   = flag.String("gap-fill-bucket", "", "Gap fill only specific bucket size (5m, 1h, 24h)")
+	gapFillItems    = flag.Int("gap-fill-items", 150, "Maximum items to process per gap fill run")
+	skipItemSync    = flag.Bool("skip-item-sync", false, "Skip initial item metadata sync from API")
+	forceItemSync   = flag.Bool("force-item-sync", false, "Bypass the cached ETag/Last-Modified and force a full item metadata re-sync")
+
+	backfillAggregates     = flag.Bool("backfill-aggregates", false, "Backfill the price_buckets_1h/24h rollup tables from their source tables instead of running normal collection")
+	backfillAggregatesFrom = flag.String("backfill-aggregates-from", "", "RFC3339 start of the backfill-aggregates window (required with -backfill-aggregates)")
+	backfillAggregatesTo   = flag.String("backfill-aggregates-to", "", "RFC3339 end of the backfill-aggregates window (defaults to now)")
 )
 
 func main() {
@@ -98,17 +108,81 @@ func main() {
 	osrsClient := osrs.NewClient(userAgent)
 
 	// Initialize repository
-	repo := collector.NewRepository(db.Pool)
+	repoOpts := []collector.RepositoryOption{collector.WithLogger(logger)}
+	if thresholdStr := os.Getenv("SLOW_QUERY_THRESHOLD_MS"); thresholdStr != "" {
+		if ms, err := time.ParseDuration(thresholdStr + "ms"); err == nil {
+			repoOpts = append(repoOpts, collector.WithSlowQueryThreshold(ms))
+		} else {
+			logger.WithComponent("collector").WithError(err).Warn("invalid SLOW_QUERY_THRESHOLD_MS, slow query logging disabled")
+		}
+	}
+	repo := collector.NewRepository(db.Pool, repoOpts...)
+
+	// QueryRepository backs the gap filler's and any future read-path
+	// tooling's bucket/observation lookups; instantiated here so its
+	// query-latency metrics are reachable even though the collector's own
+	// write path goes through collector.Repository, not QueryRepository.
+	queryRepoOpts := []storage.QueryRepositoryOption{storage.WithLogger(logger)}
+	if thresholdStr := os.Getenv("SLOW_QUERY_THRESHOLD_MS"); thresholdStr != "" {
+		if ms, err := time.ParseDuration(thresholdStr + "ms"); err == nil {
+			queryRepoOpts = append(queryRepoOpts, storage.WithSlowQueryThreshold(ms))
+		}
+	}
+	queryRepo := storage.NewQueryRepository(db.Pool, queryRepoOpts...)
+
+	// Created here (rather than down in the continuous-polling branch) so
+	// its DebugHandler can be mounted below before metricsServer starts
+	// serving; only continuous-polling mode actually Starts it.
+	volumePoller := collector.NewVolumePoller(osrsClient, repo, nil, logger)
+
+	// Shared across VolumePoller, Backfiller, and GapFiller -- see
+	// CollectorMetrics's doc comment for why this one isn't built
+	// internally by each type the way Repository's and QueryRepository's
+	// metrics are.
+	collectorMetrics := collector.NewCollectorMetrics()
+	volumePoller.SetMetrics(collectorMetrics)
+
+	// Expose Repository's and QueryRepository's Prometheus metrics,
+	// mirroring how cmd/bot mounts the LLM client's and scheduler's metrics
+	// handlers under distinct /metrics/* paths.
+	metricsAddr := os.Getenv("METRICS_ADDR")
+	if metricsAddr == "" {
+		metricsAddr = ":9090"
+	}
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", repo.NewMetricsHandler())
+	metricsMux.Handle("/metrics/storage", queryRepo.NewMetricsHandler())
+	metricsMux.Handle("/metrics/collector", collectorMetrics.NewMetricsHandler())
+	metricsMux.Handle("/debug/volume_poller", volumePoller.DebugHandler())
+	metricsServer := &http.Server{Addr: metricsAddr, Handler: metricsMux}
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.WithComponent("collector").WithError(err).Error("metrics server failed")
+		}
+	}()
 
 	// Sync item metadata from API (unless skipped)
 	if !*skipItemSync {
+		itemSource, err := collector.NewItemSourceFromKind(os.Getenv("ITEM_SOURCE"), os.Getenv("ITEM_SOURCE_PATH"), osrsClient)
+		if err != nil {
+			logger.WithComponent("collector").WithError(err).Fatal("invalid ITEM_SOURCE")
+		}
+		itemStore, err := collector.NewItemStoreFromKind(os.Getenv("ITEM_STORE"), repo)
+		if err != nil {
+			logger.WithComponent("collector").WithError(err).Fatal("invalid ITEM_STORE")
+		}
+
 		itemSyncerConfig := collector.DefaultItemSyncerConfig()
 		itemSyncerConfig.SyncInterval = 0 // Disable periodic sync for now; just sync on start
-		itemSyncer := collector.NewItemSyncer(osrsClient, repo, itemSyncerConfig, logger)
+		itemSyncer := collector.NewItemSyncer(itemSource, itemStore, itemSyncerConfig, logger)
 
 		logger.WithComponent("collector").Info("syncing item metadata from API")
 		syncCtx, syncCancel := context.WithTimeout(context.Background(), 60*time.Second)
-		if err := itemSyncer.Start(syncCtx); err != nil {
+		if *forceItemSync {
+			if err := itemSyncer.ForceSync(syncCtx); err != nil {
+				logger.WithComponent("collector").WithError(err).Warn("item sync failed, continuing without item metadata")
+			}
+		} else if err := itemSyncer.Start(syncCtx); err != nil {
 			logger.WithComponent("collector").WithError(err).Warn("item sync failed, continuing without item metadata")
 		}
 		syncCancel()
@@ -125,18 +199,79 @@ func main() {
 		runCancel()
 	}()
 
-	if *backfillMode {
+	// Per-grain retention defaults to BucketRetention's hardcoded values;
+	// config.yml's storage.bucket_retention section overrides them (see
+	// config.LoadStorageConfig, which reads just that section rather than
+	// the rest of Config's Discord/Jobs-oriented fields this binary has no
+	// use for), and BUCKET_RETENTION_5M/_1H/_24H layer on top of that for a
+	// per-deployment override, mirroring every other env-var knob in this
+	// file.
+	configPath := os.Getenv("CONFIG_PATH")
+	if configPath == "" {
+		configPath = "config.yml"
+	}
+	storageConfig, err := config.LoadStorageConfig(configPath)
+	if err != nil {
+		logger.WithComponent("collector").WithError(err).Fatal("failed to load storage config")
+	}
+	bucketRetentionOverrides := make(map[string]time.Duration, len(storageConfig.BucketRetention))
+	for size, d := range storageConfig.BucketRetention {
+		bucketRetentionOverrides[size] = d.Dur()
+	}
+	storage.LoadBucketRetentionFromConfig(bucketRetentionOverrides)
+	storage.LoadBucketRetentionFromEnv()
+
+	if *backfillAggregates {
+		if *backfillAggregatesFrom == "" {
+			logger.WithComponent("collector").Fatal("-backfill-aggregates-from is required with -backfill-aggregates")
+		}
+		from, err := time.Parse(time.RFC3339, *backfillAggregatesFrom)
+		if err != nil {
+			logger.WithComponent("collector").WithError(err).Fatal("invalid -backfill-aggregates-from")
+		}
+		to := time.Now().UTC()
+		if *backfillAggregatesTo != "" {
+			to, err = time.Parse(time.RFC3339, *backfillAggregatesTo)
+			if err != nil {
+				logger.WithComponent("collector").WithError(err).Fatal("invalid -backfill-aggregates-to")
+			}
+		}
+
+		logger.WithComponent("collector").WithFields(map[string]interface{}{
+			"from": from,
+			"to":   to,
+		}).Info("starting aggregate backfill")
+
+		for _, spec := range storage.DefaultAggregateChain() {
+			if err := storage.BackfillAggregate(runCtx, db.Pool, spec, from, to); err != nil {
+				logger.WithComponent("collector").WithField("spec", spec.Name).WithError(err).Error("aggregate backfill failed")
+			}
+		}
+	} else if *backfillMode {
 		// Run backfill mode
 		backfillerConfig := collector.DefaultBackfillerConfig()
 		if *backfillOnly != "" {
 			backfillerConfig.BucketSizes = []string{*backfillOnly}
 		}
 
-		backfiller := collector.NewBackfiller(osrsClient, repo, backfillerConfig, logger)
+		var backfiller *collector.Backfiller
+		switch *backfillSource {
+		case "", "api":
+			backfiller = collector.NewBackfiller(osrsClient, repo, backfillerConfig, logger)
+		case "dump":
+			if *backfillDumpDir == "" {
+				logger.WithComponent("collector").Fatal("-backfill-dump-dir is required with -backfill-source=dump")
+			}
+			backfiller = collector.NewBackfillerWithSource(osrsClient, repo, backfillerConfig, logger, collector.NewDumpSource(*backfillDumpDir))
+		default:
+			logger.WithComponent("collector").WithField("source", *backfillSource).Fatal("unknown -backfill-source")
+		}
+		backfiller.SetMetrics(collectorMetrics)
 
 		logger.WithComponent("collector").WithFields(map[string]interface{}{
 			"bucket_sizes": backfillerConfig.BucketSizes,
 			"rate_limit":   backfillerConfig.RateLimit.String(),
+			"source":       *backfillSource,
 		}).Info("starting backfill mode")
 
 		if err := backfiller.Run(runCtx); err != nil && err != context.Canceled {
@@ -151,11 +286,11 @@ func main() {
 		}
 
 		gapFiller := collector.NewGapFiller(osrsClient, repo, gapFillerConfig, logger)
+		gapFiller.SetMetrics(collectorMetrics)
 
 		logger.WithComponent("collector").WithFields(map[string]interface{}{
 			"bucket_sizes":  gapFillerConfig.BucketSizes,
 			"items_per_run": gapFillerConfig.ItemsPerRun,
-			"rate_limit":    gapFillerConfig.RateLimit.String(),
 		}).Info("starting gap fill mode")
 
 		if err := gapFiller.Run(runCtx); err != nil && err != context.Canceled {
@@ -171,7 +306,28 @@ func main() {
 		}
 
 		poller := collector.NewPoller(osrsClient, repo, pollerConfig, logger)
-		poller.Start()
+
+		// supervisor lets us Start/Stop/Stats poller and volumePoller
+		// uniformly instead of calling each by hand, and additionally
+		// restarts either one if its HealthCheck reports unhealthy -- see
+		// collector.Supervisor. Stop runs in reverse registration order, so
+		// volumePoller (registered second) stops before poller.
+		supervisor := collector.NewSupervisor(nil, logger)
+		supervisor.Register("poller", poller)
+		supervisor.Register("volume_poller", volumePoller)
+		supervisor.Start()
+		metricsMux.Handle("/status", supervisor.StatusHandler())
+
+		// Keep price_buckets_1h/24h rolled up and all three bucket tables
+		// pruned to BucketRetention -- this repo's Go-driven equivalent of
+		// TimescaleDB's continuous aggregate + retention policies.
+		aggregateRefresher := storage.NewAggregateRefresher(db.Pool, storage.DefaultAggregateChain(), logger)
+		aggregateRefresher.Start(runCtx)
+
+		// Keep flip_candidates current for jobs.JobRunner.RunJob's candidate
+		// queries, same fire-and-forget shape as aggregateRefresher above.
+		candidateRefresher := storage.NewCandidateRefresher(db.Pool, 0, logger)
+		candidateRefresher.Start(runCtx)
 
 		logger.WithComponent("collector").WithFields(map[string]interface{}{
 			"poll_interval": pollerConfig.Interval.String(),
@@ -180,11 +336,16 @@ func main() {
 		// Wait for context cancellation
 		<-runCtx.Done()
 
-		// Stop poller
-		poller.Stop()
+		// Stop poller and volumePoller
+		supervisor.Stop()
 	}
 
-	// Close database connection
+	// Shut down the metrics server and database connection
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		logger.WithComponent("collector").WithError(err).Warn("metrics server shutdown failed")
+	}
+	shutdownCancel()
 	db.Close()
 
 	logger.WithComponent("collector").Info("collector shutdown complete")
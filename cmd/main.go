@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,14 +13,35 @@ import (
 
 	"osrs-flipping/pkg/config"
 	"osrs-flipping/pkg/jobs"
+	"osrs-flipping/pkg/logging"
+	"osrs-flipping/pkg/osrs/metrics"
+	"osrs-flipping/pkg/shutdown"
 )
 
 func main() {
+	// "backtest" is a subcommand rather than a flag since it takes its own
+	// flag set (--job, --from, --to) and doesn't fit the run-jobs flow below.
+	if len(os.Args) > 1 && os.Args[1] == "backtest" {
+		runBacktest(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "candle-backtest" {
+		runCandleBacktest(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "positions" {
+		runPositions(os.Args[2:])
+		return
+	}
+
 	// Parse command line arguments
 	var (
-		jobName = flag.String("job", "", "Name of specific job to run (must match a job name in config.yml)")
-		runAll  = flag.Bool("all", false, "Run all enabled jobs")
-		help    = flag.Bool("help", false, "Show help message")
+		jobName      = flag.String("job", "", "Name of specific job to run (must match a job name in config.yml)")
+		runAll       = flag.Bool("all", false, "Run all enabled jobs")
+		forceRefresh = flag.Bool("force-refresh", false, "Bypass the on-disk timeseries cache and always refetch from the API")
+		metricsAddr  = flag.String("metrics-addr", "", "Address to serve Prometheus metrics on (e.g. :9090); when set, the process keeps running as a long-lived exporter after the job(s) finish")
+		report       = flag.Bool("report", false, "Render a margin-vs-volume PNG chart of each job's results into output/reports/<timestamp>/ alongside the usual JSON/markdown output")
+		help         = flag.Bool("help", false, "Show help message")
 	)
 	flag.Parse()
 
@@ -31,6 +53,9 @@ func main() {
 		fmt.Println("Usage:")
 		fmt.Println("  -job=\"Job Name\"  Run a specific job (must match job name in config.yml)")
 		fmt.Println("  -all             Run all enabled jobs")
+		fmt.Println("  -force-refresh   Bypass the on-disk timeseries cache and always refetch from the API")
+		fmt.Println("  -metrics-addr    Serve Prometheus metrics on this address and keep running as a long-lived exporter")
+		fmt.Println("  -report          Render a margin-vs-volume PNG chart of each job's results into output/reports/<timestamp>/")
 		fmt.Println("  -help            Show this help message")
 		fmt.Println()
 		fmt.Println("Examples:")
@@ -86,11 +111,47 @@ func main() {
 		fmt.Printf("   Running all enabled jobs\n")
 	}
 
+	// Coordinate graceful shutdown: a Ctrl-C here still lets an in-flight
+	// RunJob finish writing its markdown output instead of leaving a
+	// half-written file.
+	logger := logging.NewLogger(cfg.Logging.Level, cfg.Logging.Format)
+	shutdownCoordinator, rootCtx := shutdown.New(logger, 30*time.Second)
+	drained := make(chan struct{})
+	go func() {
+		shutdownCoordinator.Wait(rootCtx, drained)
+		logger.WithComponent("main").Info("CLI shutdown complete")
+	}()
+
 	// Create job runner
 	jobRunner, err := jobs.NewJobRunner(cfg)
 	if err != nil {
 		log.Fatalf("Failed to create job runner: %v", err)
 	}
+	jobRunner.Analyzer().SetForceRefresh(*forceRefresh)
+	if *report {
+		jobRunner.SetReportDir("output/reports")
+	}
+
+	// Expose the analyzer's Prometheus metrics, mirroring how cmd/bot mounts
+	// the LLM client's and scheduler's metrics handlers.
+	if *metricsAddr != "" {
+		analyzerMetrics := metrics.New(jobRunner.Analyzer())
+		jobRunner.Analyzer().SetScrapeRecorder(analyzerMetrics)
+
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", analyzerMetrics.Handler())
+		metricsServer := &http.Server{Addr: *metricsAddr, Handler: metricsMux}
+
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.WithComponent("main").WithError(err).Error("Metrics server failed")
+			}
+		}()
+
+		shutdownCoordinator.Register("metrics_server", func(ctx context.Context) error {
+			return metricsServer.Shutdown(ctx)
+		})
+	}
 
 	// Load base OSRS data
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -104,8 +165,9 @@ func main() {
 	// Create output formatter
 	formatter := jobs.NewOutputFormatter()
 
-	// Run job(s)
-	jobCtx, jobCancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	// Run job(s), deriving from rootCtx so a shutdown signal cancels
+	// in-flight work instead of leaving it running past process exit.
+	jobCtx, jobCancel := context.WithTimeout(rootCtx, 30*time.Minute)
 	defer jobCancel()
 
 	var results []*jobs.JobResult
@@ -127,6 +189,7 @@ func main() {
 			log.Fatalf("Failed to run jobs: %v", err)
 		}
 	}
+	close(drained)
 
 	// Process results
 	for _, result := range results {
@@ -171,4 +234,11 @@ func main() {
 	fmt.Printf("   Total jobs: %d\n", len(results))
 	fmt.Printf("   Successful: %d\n", successCount)
 	fmt.Printf("   Failed: %d\n", len(results)-successCount)
+
+	// With -metrics-addr set, stick around serving the metrics we just
+	// populated instead of exiting like a normal one-shot run.
+	if *metricsAddr != "" {
+		fmt.Printf("\n📈 Serving Prometheus metrics on %s/metrics (Ctrl-C to stop)\n", *metricsAddr)
+		<-rootCtx.Done()
+	}
 }
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"osrs-flipping/pkg/database"
+	"osrs-flipping/pkg/logging"
+	"osrs-flipping/pkg/osrs/position"
+	"osrs-flipping/pkg/positions"
+	"osrs-flipping/pkg/reporting"
+)
+
+// defaultPositionStorePath mirrors jobs.defaultPositionStorePath; the CLI
+// doesn't import the jobs package's unexported constant, so it keeps its
+// own copy.
+const defaultPositionStorePath = "output/data/positions.json"
+
+// runPositions implements `osrs-flipping positions <open|close|list|serve|report>`.
+// open/close/list are a small CLI around package position's JSON-file
+// Store so manually-tracked flips can be recorded without hand-editing the
+// file; serve instead starts the pkg/positions HTTP fill recorder backed by
+// Postgres, for an external order tracker to report fills into, and report
+// charts that store's closed trades.
+func runPositions(args []string) {
+	if len(args) == 0 {
+		log.Fatal("Usage: osrs-flipping positions <open|close|list|serve|report> [flags]")
+	}
+
+	switch args[0] {
+	case "open":
+		runPositionsOpen(args[1:])
+	case "close":
+		runPositionsClose(args[1:])
+	case "list":
+		runPositionsList(args[1:])
+	case "serve":
+		runPositionsServe(args[1:])
+	case "report":
+		runPositionsReport(args[1:])
+	default:
+		log.Fatalf("Unknown positions subcommand %q (want open, close, list, serve, or report)", args[0])
+	}
+}
+
+func runPositionsOpen(args []string) {
+	fs := flag.NewFlagSet("positions open", flag.ExitOnError)
+	storePath := fs.String("store", defaultPositionStorePath, "Path to the position store JSON file")
+	itemID := fs.Int("item", 0, "Item ID")
+	name := fs.String("name", "", "Item name")
+	quantity := fs.Int("qty", 0, "Quantity bought")
+	buyPrice := fs.Int("price", 0, "Buy price per unit, in GP")
+	fs.Parse(args)
+
+	if *itemID == 0 || *quantity == 0 || *buyPrice == 0 {
+		log.Fatal("Usage: osrs-flipping positions open --item <id> --name <name> --qty <n> --price <gp>")
+	}
+
+	store := position.NewStore(*storePath)
+	if err := store.Load(); err != nil {
+		log.Fatalf("Failed to load position store: %v", err)
+	}
+
+	store.Open(position.Position{
+		ItemID:   *itemID,
+		Name:     *name,
+		Quantity: *quantity,
+		BuyPrice: *buyPrice,
+		BuyTime:  time.Now(),
+	})
+
+	if err := store.Save(); err != nil {
+		log.Fatalf("Failed to save position store: %v", err)
+	}
+	fmt.Printf("✅ Opened position: %dx %s @ %d GP\n", *quantity, *name, *buyPrice)
+}
+
+func runPositionsClose(args []string) {
+	fs := flag.NewFlagSet("positions close", flag.ExitOnError)
+	storePath := fs.String("store", defaultPositionStorePath, "Path to the position store JSON file")
+	itemID := fs.Int("item", 0, "Item ID")
+	sellPrice := fs.Int("price", 0, "Sell price per unit, in GP")
+	reason := fs.String("reason", string(position.ReasonTakeProfit), "Exit reason: trailing, stop_loss, take_profit, or stale")
+	fs.Parse(args)
+
+	if *itemID == 0 || *sellPrice == 0 {
+		log.Fatal("Usage: osrs-flipping positions close --item <id> --price <gp> [--reason trailing|stop_loss|take_profit|stale]")
+	}
+
+	store := position.NewStore(*storePath)
+	if err := store.Load(); err != nil {
+		log.Fatalf("Failed to load position store: %v", err)
+	}
+
+	closed, err := store.Close(*itemID, *sellPrice, time.Now(), position.Reason(*reason))
+	if err != nil {
+		log.Fatalf("Failed to close position: %v", err)
+	}
+
+	if err := store.Save(); err != nil {
+		log.Fatalf("Failed to save position store: %v", err)
+	}
+	fmt.Printf("✅ Closed position: %dx %s @ %d GP (%s), profit %d GP\n",
+		closed.Quantity, closed.Name, closed.SellPrice, closed.Reason, closed.ProfitGP)
+}
+
+func runPositionsList(args []string) {
+	fs := flag.NewFlagSet("positions list", flag.ExitOnError)
+	storePath := fs.String("store", defaultPositionStorePath, "Path to the position store JSON file")
+	fs.Parse(args)
+
+	store := position.NewStore(*storePath)
+	if err := store.Load(); err != nil {
+		log.Fatalf("Failed to load position store: %v", err)
+	}
+
+	positions := store.Positions()
+	if len(positions) == 0 {
+		fmt.Println("No open positions.")
+	}
+	for _, p := range positions {
+		fmt.Printf("- %dx %s (item %d) @ %d GP, opened %s\n", p.Quantity, p.Name, p.ItemID, p.BuyPrice, p.BuyTime.Format(time.RFC3339))
+	}
+
+	stats := store.Stats()
+	fmt.Printf("\nClosed: %d | Realized profit: %d GP | Win rate: %.1f%%\n", stats.ClosedCount, stats.RealizedProfitGP, stats.WinRate*100)
+}
+
+// runPositionsServe starts an HTTP server exposing POST /positions/fill,
+// backed by the pkg/positions Postgres store.
+func runPositionsServe(args []string) {
+	fs := flag.NewFlagSet("positions serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8081", "Address to serve the fill-recording endpoint on")
+	fs.Parse(args)
+
+	logger := logging.NewLogger("info", "json")
+
+	dbConfig, err := database.ConfigFromEnv()
+	if err != nil {
+		logger.WithComponent("positions").WithError(err).Fatal("failed to load database configuration")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	db, err := database.Connect(ctx, dbConfig)
+	cancel()
+	if err != nil {
+		logger.WithComponent("positions").WithError(err).Fatal("failed to connect to database")
+	}
+	defer db.Close()
+
+	store := positions.NewPositionStore(db.Pool)
+
+	mux := http.NewServeMux()
+	mux.Handle("/positions/fill", positions.NewFillHandler(store))
+
+	logger.WithComponent("positions").WithField("addr", *addr).Info("serving fill recorder")
+	if err := http.ListenAndServe(*addr, mux); err != nil && err != http.ErrServerClosed {
+		logger.WithComponent("positions").WithError(err).Fatal("fill recorder server failed")
+	}
+}
+
+// runPositionsReport renders the trades table's equity curve to a PNG under
+// --out, via reporting.EquityCurve. It's the Postgres-backed counterpart to
+// `osrs-flipping backtest`/`candle-backtest`'s own equity-curve charts,
+// since pkg/positions has no GenerateGraph of its own.
+func runPositionsReport(args []string) {
+	fs := flag.NewFlagSet("positions report", flag.ExitOnError)
+	outDir := fs.String("out", "output/reports", "Directory to write the equity curve chart into")
+	fs.Parse(args)
+
+	logger := logging.NewLogger("info", "json")
+
+	dbConfig, err := database.ConfigFromEnv()
+	if err != nil {
+		logger.WithComponent("positions").WithError(err).Fatal("failed to load database configuration")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	db, err := database.Connect(ctx, dbConfig)
+	cancel()
+	if err != nil {
+		logger.WithComponent("positions").WithError(err).Fatal("failed to connect to database")
+	}
+	defer db.Close()
+
+	store := positions.NewPositionStore(db.Pool)
+
+	reportCtx, reportCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer reportCancel()
+	trades, err := store.ListClosedTrades(reportCtx)
+	if err != nil {
+		log.Fatalf("Failed to list closed trades: %v", err)
+	}
+
+	paths, err := reporting.GenerateReports(*outDir, time.Now(), trades, nil, nil)
+	if err != nil {
+		log.Fatalf("Failed to generate report: %v", err)
+	}
+	if len(paths) == 0 {
+		fmt.Println("No closed trades to report on yet.")
+		return
+	}
+	for _, p := range paths {
+		fmt.Printf("📈 Wrote %s\n", p)
+	}
+}
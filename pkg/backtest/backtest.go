@@ -0,0 +1,307 @@
+// Package backtest replays an item's DB-persisted 5m/1h/24h price buckets
+// against a pluggable Strategy, simulating GE-style limit order fills
+// against each bucket's observed insta-buy/insta-sell volume. This is the
+// DB-backed counterpart to osrs/backtest, which only replays whatever
+// window the analyzer's in-process store (or the live wiki API) still
+// holds -- LoadBucketsForBacktest reaches straight into the repository, so
+// any retained history is fair game.
+//
+// Strategy is defined here rather than under cmd/backtest so pkg/collector's
+// poller can drive the same implementations at runtime to generate live
+// recommendations, not just replay historical ones.
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"osrs-flipping/pkg/osrs/portfolio"
+	"osrs-flipping/pkg/storage"
+)
+
+// buyLimitResetEvery matches the GE's rolling 4-hour buy limit window, the
+// same constant osrs/backtest.buyLimitResetEvery uses for candle replay.
+const buyLimitResetEvery = 4 * time.Hour
+
+// Side is which side of the book an Order rests on.
+type Side string
+
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+// Order is one GE-style limit order a Strategy requests in response to a
+// bucket tick. Run evaluates it against that same bucket's observed
+// volume only -- it is not carried over to the next tick, so a Strategy
+// that wants a price to keep resting must keep returning it every OnBucket
+// call until it fills or the Strategy lets it lapse.
+type Order struct {
+	Side       Side
+	Qty        int
+	LimitPrice int
+}
+
+// State is the read-only snapshot of an item's simulated holdings and
+// buy-limit usage a Strategy sees at each bucket. Run owns the mutable
+// copy; a Strategy must not retain it past the call.
+type State struct {
+	Position          int // units currently held, not yet sold
+	AvgCost           int // average buy price of Position units; 0 if Position == 0
+	BuyLimitRemaining int // units still purchasable before the rolling 4h window resets; 0 is only a real cap when the item has a BuyLimit
+}
+
+// Strategy decides what orders to place as an item's bucket history
+// replays, one tick at a time. An implementation that needs to remember
+// things across ticks (an open sell target, a running indicator) should
+// keep that state in its own fields and back exactly one item's replay per
+// instance -- Run does not share or reset a Strategy itself, only the
+// State it's handed each call.
+type Strategy interface {
+	OnBucket(ctx context.Context, state State, bucket storage.Bucket) []Order
+}
+
+// ItemMeta is the static per-item metadata Run needs alongside its bucket
+// history: buy limit enforcement and a human-readable label for Result.
+type ItemMeta struct {
+	ItemID   int
+	Name     string
+	BuyLimit int // <= 0 means no buy limit is enforced
+}
+
+// BucketSource loads one item's bucket history for Run, letting callers
+// pass a *storage.QueryRepository in production and a fixed slice in tests
+// without this package depending on pgx directly.
+type BucketSource interface {
+	LoadBucketsForBacktest(ctx context.Context, itemID int, bucketSize string, from, to time.Time) ([]storage.Bucket, error)
+}
+
+// Config controls one Run call.
+type Config struct {
+	// BucketSize selects which bucket table to replay: "5m", "1h", or
+	// "24h". Empty defaults to "5m".
+	BucketSize string
+	From, To   time.Time
+}
+
+const defaultBucketSize = "5m"
+
+// ClosedTrade is one round trip Run closed while replaying an item: a
+// FIFO-matched buy lot against the sell order that (fully or partially)
+// closed it.
+type ClosedTrade struct {
+	BuyTime   time.Time
+	BuyPrice  int
+	SellTime  time.Time
+	SellPrice int
+	Quantity  int
+	TaxPaid   int
+	ProfitGP  int // net of TaxPaid
+}
+
+// ItemResult is one item's outcome across its whole replay window.
+type ItemResult struct {
+	ItemID int
+	Name   string
+	Trades []ClosedTrade
+	// OpenQty is units still held, unsold, at the end of the replay
+	// window -- excluded from TradeStats since they have no close time or
+	// realized profit yet.
+	OpenQty int
+}
+
+// EquityPoint is one sample of cumulative realized PnL across every item in
+// a Result, ordered by the closing trade's sell time.
+type EquityPoint struct {
+	Timestamp time.Time
+	EquityGP  int
+}
+
+// TradeStats summarizes a Result's closed trades across every item replayed.
+type TradeStats struct {
+	ClosedTrades  int
+	WinRate       float64     // fraction of ClosedTrades with ProfitGP > 0
+	AvgProfitGP   float64     // mean ProfitGP per closed trade
+	MaxDrawdownGP int         // largest peak-to-trough drop in cumulative realized PnL
+	PNLPerHour    float64     // total realized profit / hours between Result.From and Result.To
+	PNLByItem     map[int]int // realized profit per item ID
+}
+
+// Result is the outcome of a Config replay across every item passed to Run.
+type Result struct {
+	From, To time.Time
+	Items    []ItemResult
+	Stats    TradeStats
+	Equity   []EquityPoint
+}
+
+// Run replays each item's bucket history between cfg.From and cfg.To
+// against strategy, independently of one another, then summarizes the
+// closed trades into Result.Stats and Result.Equity.
+func Run(ctx context.Context, source BucketSource, items []ItemMeta, strategy Strategy, cfg Config) (*Result, error) {
+	bucketSize := cfg.BucketSize
+	if bucketSize == "" {
+		bucketSize = defaultBucketSize
+	}
+
+	result := &Result{From: cfg.From, To: cfg.To}
+	for _, item := range items {
+		buckets, err := source.LoadBucketsForBacktest(ctx, item.ItemID, bucketSize, cfg.From, cfg.To)
+		if err != nil {
+			return nil, fmt.Errorf("loading buckets for item %d: %w", item.ItemID, err)
+		}
+		result.Items = append(result.Items, replayItem(ctx, item, buckets, strategy))
+	}
+
+	summarize(result)
+	return result, nil
+}
+
+// openLot is one not-yet-fully-sold buy fill, tracked FIFO per item so a
+// closing sell can compute realized profit against the price it was
+// actually bought at -- the same FIFO approach osrs/portfolio.Portfolio
+// uses for live exposure accounting.
+type openLot struct {
+	quantity int
+	price    int
+	boughtAt time.Time
+}
+
+// currentState derives the State a Strategy sees from lots and the buy
+// limit window's usage so far.
+func currentState(item ItemMeta, lots []openLot, boughtSinceReset int) State {
+	state := State{}
+	if item.BuyLimit > 0 {
+		state.BuyLimitRemaining = item.BuyLimit - boughtSinceReset
+		if state.BuyLimitRemaining < 0 {
+			state.BuyLimitRemaining = 0
+		}
+	}
+
+	var qty, cost int
+	for _, lot := range lots {
+		qty += lot.quantity
+		cost += lot.quantity * lot.price
+	}
+	state.Position = qty
+	if qty > 0 {
+		state.AvgCost = cost / qty
+	}
+	return state
+}
+
+// fillQty returns how many units of order would fill against bucket b,
+// before clamping to order.Qty -- a buy fills against the insta-sell side
+// (AvgLowPrice/LowPriceVolume) when it's at or below the order's limit, a
+// sell fills against the insta-buy side (AvgHighPrice/HighPriceVolume) when
+// it's at or above.
+func fillQty(order Order, b storage.Bucket) int {
+	switch order.Side {
+	case Buy:
+		if b.AvgLowPrice == nil || b.LowPriceVolume == nil || *b.AvgLowPrice > order.LimitPrice {
+			return 0
+		}
+		return int(*b.LowPriceVolume)
+	case Sell:
+		if b.AvgHighPrice == nil || b.HighPriceVolume == nil || *b.AvgHighPrice < order.LimitPrice {
+			return 0
+		}
+		return int(*b.HighPriceVolume)
+	default:
+		return 0
+	}
+}
+
+// closeLots removes up to qty units from lots' oldest entries first
+// (FIFO), returning the portions actually closed -- so the caller can
+// compute profit against each lot's own buy price -- and what remains open.
+func closeLots(lots []openLot, qty int) (closed, remaining []openLot) {
+	for _, lot := range lots {
+		if qty <= 0 {
+			remaining = append(remaining, lot)
+			continue
+		}
+		if lot.quantity <= qty {
+			closed = append(closed, lot)
+			qty -= lot.quantity
+			continue
+		}
+		closed = append(closed, openLot{quantity: qty, price: lot.price, boughtAt: lot.boughtAt})
+		remaining = append(remaining, openLot{quantity: lot.quantity - qty, price: lot.price, boughtAt: lot.boughtAt})
+		qty = 0
+	}
+	return closed, remaining
+}
+
+// replayItem runs one item's buy-limit-constrained strategy simulation
+// across its buckets, oldest first.
+func replayItem(ctx context.Context, item ItemMeta, buckets []storage.Bucket, strategy Strategy) ItemResult {
+	ir := ItemResult{ItemID: item.ItemID, Name: item.Name}
+
+	var lots []openLot
+	var resetAt time.Time
+	boughtSinceReset := 0
+
+	for _, b := range buckets {
+		if resetAt.IsZero() || !b.BucketStart.Before(resetAt) {
+			resetAt = b.BucketStart.Add(buyLimitResetEvery)
+			boughtSinceReset = 0
+		}
+
+		state := currentState(item, lots, boughtSinceReset)
+
+		for _, order := range strategy.OnBucket(ctx, state, b) {
+			switch order.Side {
+			case Buy:
+				qty := order.Qty
+				if item.BuyLimit > 0 {
+					remaining := item.BuyLimit - boughtSinceReset
+					if remaining <= 0 {
+						continue
+					}
+					if qty > remaining {
+						qty = remaining
+					}
+				}
+				filled := fillQty(order, b)
+				if filled > qty {
+					filled = qty
+				}
+				if filled <= 0 {
+					continue
+				}
+				lots = append(lots, openLot{quantity: filled, price: order.LimitPrice, boughtAt: b.BucketStart})
+				boughtSinceReset += filled
+
+			case Sell:
+				filled := fillQty(order, b)
+				if filled > order.Qty {
+					filled = order.Qty
+				}
+				if filled <= 0 {
+					continue
+				}
+				var closed []openLot
+				closed, lots = closeLots(lots, filled)
+				for _, c := range closed {
+					tax := portfolio.GeTax(order.LimitPrice, c.quantity)
+					ir.Trades = append(ir.Trades, ClosedTrade{
+						BuyTime:   c.boughtAt,
+						BuyPrice:  c.price,
+						SellTime:  b.BucketStart,
+						SellPrice: order.LimitPrice,
+						Quantity:  c.quantity,
+						TaxPaid:   tax,
+						ProfitGP:  (order.LimitPrice-c.price)*c.quantity - tax,
+					})
+				}
+			}
+		}
+	}
+
+	for _, lot := range lots {
+		ir.OpenQty += lot.quantity
+	}
+	return ir
+}
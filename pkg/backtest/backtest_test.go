@@ -0,0 +1,118 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"osrs-flipping/pkg/storage"
+)
+
+func intPtr(i int) *int       { return &i }
+func int64Ptr(i int64) *int64 { return &i }
+
+// fixedBucketSource serves a fixed, in-memory set of buckets per item, for
+// replaying Run without a database.
+type fixedBucketSource struct {
+	buckets map[int][]storage.Bucket
+}
+
+func (f fixedBucketSource) LoadBucketsForBacktest(ctx context.Context, itemID int, bucketSize string, from, to time.Time) ([]storage.Bucket, error) {
+	return f.buckets[itemID], nil
+}
+
+// alwaysBuyThenSellStrategy buys on the first tick it sees Position == 0
+// and sells everything once it's held for holdAfter buckets, to exercise
+// Run's fill/buy-limit/FIFO bookkeeping independent of any reference
+// strategy's entry logic.
+type alwaysBuyThenSellStrategy struct {
+	holdAfter int
+	ticksHeld int
+}
+
+func (s *alwaysBuyThenSellStrategy) OnBucket(ctx context.Context, state State, b storage.Bucket) []Order {
+	if state.Position == 0 {
+		s.ticksHeld = 0
+		if state.BuyLimitRemaining <= 0 {
+			return nil
+		}
+		return []Order{{Side: Buy, Qty: state.BuyLimitRemaining, LimitPrice: *b.AvgLowPrice}}
+	}
+	s.ticksHeld++
+	if s.ticksHeld >= s.holdAfter {
+		return []Order{{Side: Sell, Qty: state.Position, LimitPrice: *b.AvgHighPrice}}
+	}
+	return nil
+}
+
+func TestRun_BuyThenSellClosesTradeWithTax(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	buckets := []storage.Bucket{
+		{ItemID: 1, BucketStart: base, AvgHighPrice: intPtr(110), HighPriceVolume: int64Ptr(100), AvgLowPrice: intPtr(100), LowPriceVolume: int64Ptr(100)},
+		{ItemID: 1, BucketStart: base.Add(time.Hour), AvgHighPrice: intPtr(115), HighPriceVolume: int64Ptr(100), AvgLowPrice: intPtr(105), LowPriceVolume: int64Ptr(100)},
+	}
+	source := fixedBucketSource{buckets: map[int][]storage.Bucket{1: buckets}}
+	items := []ItemMeta{{ItemID: 1, Name: "Test Item", BuyLimit: 50}}
+	strategy := &alwaysBuyThenSellStrategy{holdAfter: 1}
+
+	result, err := Run(context.Background(), source, items, strategy, Config{From: base, To: base.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(result.Items) != 1 {
+		t.Fatalf("expected 1 item result, got %d", len(result.Items))
+	}
+	trades := result.Items[0].Trades
+	if len(trades) != 1 {
+		t.Fatalf("expected 1 closed trade, got %d", len(trades))
+	}
+
+	trade := trades[0]
+	if trade.BuyPrice != 100 || trade.SellPrice != 115 {
+		t.Errorf("trade = %+v, want buy 100 sell 115", trade)
+	}
+	wantTax := trade.Quantity * 115 / 100 // 1% GE tax, floored
+	if trade.TaxPaid != wantTax {
+		t.Errorf("TaxPaid = %d, want %d", trade.TaxPaid, wantTax)
+	}
+	wantProfit := (115-100)*trade.Quantity - trade.TaxPaid
+	if trade.ProfitGP != wantProfit {
+		t.Errorf("ProfitGP = %d, want %d", trade.ProfitGP, wantProfit)
+	}
+
+	if result.Stats.ClosedTrades != 1 || result.Stats.WinRate != 1 {
+		t.Errorf("Stats = %+v, want 1 closed trade at 100%% win rate", result.Stats)
+	}
+}
+
+func TestRun_BuyOrderRespectsBuyLimit(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	buckets := []storage.Bucket{
+		{ItemID: 1, BucketStart: base, AvgHighPrice: intPtr(110), HighPriceVolume: int64Ptr(1000), AvgLowPrice: intPtr(100), LowPriceVolume: int64Ptr(1000)},
+	}
+	source := fixedBucketSource{buckets: map[int][]storage.Bucket{1: buckets}}
+	items := []ItemMeta{{ItemID: 1, Name: "Test Item", BuyLimit: 10}}
+	strategy := &alwaysBuyThenSellStrategy{holdAfter: 100}
+
+	result, err := Run(context.Background(), source, items, strategy, Config{From: base, To: base.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if got := result.Items[0].OpenQty; got != 10 {
+		t.Errorf("OpenQty = %d, want 10 (capped by BuyLimit despite 1000 units of liquidity and Qty requested)", got)
+	}
+}
+
+func TestFillQty_NoFillWhenPriceMisses(t *testing.T) {
+	b := storage.Bucket{AvgLowPrice: intPtr(105), LowPriceVolume: int64Ptr(50), AvgHighPrice: intPtr(110), HighPriceVolume: int64Ptr(50)}
+
+	if got := fillQty(Order{Side: Buy, Qty: 10, LimitPrice: 100}, b); got != 0 {
+		t.Errorf("buy limit 100 below bucket's avg low price 105 should not fill, got %d", got)
+	}
+	if got := fillQty(Order{Side: Sell, Qty: 10, LimitPrice: 120}, b); got != 0 {
+		t.Errorf("sell limit 120 above bucket's avg high price 110 should not fill, got %d", got)
+	}
+	if got := fillQty(Order{Side: Buy, Qty: 10, LimitPrice: 105}, b); got != 50 {
+		t.Errorf("buy limit at the bucket's avg low price should fill up to its volume, got %d", got)
+	}
+}
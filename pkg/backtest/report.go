@@ -0,0 +1,145 @@
+package backtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// summarize fills in result's Stats and Equity from every item's closed
+// trades, ordered across items by sell time.
+func summarize(result *Result) {
+	var allTrades []ClosedTrade
+	pnlByItem := make(map[int]int)
+	for _, ir := range result.Items {
+		for _, t := range ir.Trades {
+			allTrades = append(allTrades, t)
+			pnlByItem[ir.ItemID] += t.ProfitGP
+		}
+	}
+	sort.Slice(allTrades, func(i, j int) bool { return allTrades[i].SellTime.Before(allTrades[j].SellTime) })
+
+	var stats TradeStats
+	stats.PNLByItem = pnlByItem
+
+	var totalProfit, wins, cumulative, peak int
+	equity := make([]EquityPoint, 0, len(allTrades))
+	for _, t := range allTrades {
+		stats.ClosedTrades++
+		totalProfit += t.ProfitGP
+		if t.ProfitGP > 0 {
+			wins++
+		}
+
+		cumulative += t.ProfitGP
+		if cumulative > peak {
+			peak = cumulative
+		}
+		if drawdown := peak - cumulative; drawdown > stats.MaxDrawdownGP {
+			stats.MaxDrawdownGP = drawdown
+		}
+		equity = append(equity, EquityPoint{Timestamp: t.SellTime, EquityGP: cumulative})
+	}
+
+	if stats.ClosedTrades > 0 {
+		stats.WinRate = float64(wins) / float64(stats.ClosedTrades)
+		stats.AvgProfitGP = float64(totalProfit) / float64(stats.ClosedTrades)
+	}
+	if hours := result.To.Sub(result.From).Hours(); hours > 0 {
+		stats.PNLPerHour = float64(totalProfit) / hours
+	}
+
+	result.Stats = stats
+	result.Equity = equity
+}
+
+// equityJSON is the JSON-serializable form of a Result, written by
+// WriteEquityJSON alongside the optional PNG chart.
+type equityJSON struct {
+	From   string            `json:"from"`
+	To     string            `json:"to"`
+	Stats  tradeStatsJSON    `json:"stats"`
+	Equity []equityPointJSON `json:"equity"`
+}
+
+type tradeStatsJSON struct {
+	ClosedTrades  int         `json:"closed_trades"`
+	WinRate       float64     `json:"win_rate"`
+	AvgProfitGP   float64     `json:"avg_profit_gp"`
+	MaxDrawdownGP int         `json:"max_drawdown_gp"`
+	PNLPerHour    float64     `json:"pnl_per_hour_gp"`
+	PNLByItem     map[int]int `json:"pnl_by_item_gp"`
+}
+
+type equityPointJSON struct {
+	Timestamp string `json:"timestamp"`
+	EquityGP  int    `json:"equity_gp"`
+}
+
+// WriteEquityJSON writes result's TradeStats and equity curve to path as
+// JSON, for cmd/backtest's --out directory.
+func (result *Result) WriteEquityJSON(path string) error {
+	out := equityJSON{
+		From: result.From.Format(time.RFC3339),
+		To:   result.To.Format(time.RFC3339),
+		Stats: tradeStatsJSON{
+			ClosedTrades:  result.Stats.ClosedTrades,
+			WinRate:       result.Stats.WinRate,
+			AvgProfitGP:   result.Stats.AvgProfitGP,
+			MaxDrawdownGP: result.Stats.MaxDrawdownGP,
+			PNLPerHour:    result.Stats.PNLPerHour,
+			PNLByItem:     result.Stats.PNLByItem,
+		},
+	}
+	for _, e := range result.Equity {
+		out.Equity = append(out.Equity, equityPointJSON{
+			Timestamp: e.Timestamp.Format(time.RFC3339),
+			EquityGP:  e.EquityGP,
+		})
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling backtest equity: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing backtest equity: %w", err)
+	}
+	return nil
+}
+
+// RenderEquityGraph writes a cumulative realized-PnL line chart for result
+// to path, mirroring osrs/backtest.RenderGraphs' cumulative_pnl.png.
+func RenderEquityGraph(result *Result, path string) error {
+	if len(result.Equity) == 0 {
+		return fmt.Errorf("no closed trades to graph")
+	}
+
+	pts := make(plotter.XYs, len(result.Equity))
+	for i, e := range result.Equity {
+		pts[i].X = float64(e.Timestamp.Unix())
+		pts[i].Y = float64(e.EquityGP)
+	}
+
+	p := plot.New()
+	p.Title.Text = "backtest: cumulative PnL (GP)"
+	p.X.Label.Text = "time (unix seconds)"
+	p.Y.Label.Text = "cumulative GP"
+
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		return fmt.Errorf("building equity line: %w", err)
+	}
+	p.Add(line)
+
+	if err := p.Save(8*vg.Inch, 4*vg.Inch, path); err != nil {
+		return fmt.Errorf("saving equity graph: %w", err)
+	}
+	return nil
+}
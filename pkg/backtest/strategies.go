@@ -0,0 +1,127 @@
+package backtest
+
+import (
+	"context"
+	"time"
+
+	"osrs-flipping/pkg/osrs/indicator"
+	"osrs-flipping/pkg/storage"
+)
+
+// MarginThresholdStrategy buys whenever a bucket's margin percentage and 1h
+// (insta-buy) volume both clear a configured minimum, then lists the
+// position for sale at a target margin above its average cost, shaving the
+// target down by RelistStepPct every RelistAfter if it hasn't sold yet.
+//
+// One instance should back exactly one item's replay -- it tracks that
+// item's current sell target itself, since Run hands Strategy a fresh
+// State every call rather than remembering orders across ticks.
+type MarginThresholdStrategy struct {
+	MinMarginPct    float64       // e.g. 0.02 for 2%
+	MinVolume1h     int64         // minimum HighPriceVolume to treat a bucket as liquid enough to enter
+	TargetMarginPct float64       // sell target above average cost, e.g. 0.03 for 3%
+	RelistAfter     time.Duration // how long to hold the target price before shaving it down; zero disables relisting
+	RelistStepPct   float64       // fraction of the remaining margin to shave off at each relist
+
+	sellPrice int
+	listedAt  time.Time
+}
+
+// NewMarginThresholdStrategy returns a MarginThresholdStrategy with a 25%
+// relist step, a reasonable default for re-listing a stale offer without
+// giving up the whole margin at once.
+func NewMarginThresholdStrategy(minMarginPct float64, minVolume1h int64, targetMarginPct float64, relistAfter time.Duration) *MarginThresholdStrategy {
+	return &MarginThresholdStrategy{
+		MinMarginPct:    minMarginPct,
+		MinVolume1h:     minVolume1h,
+		TargetMarginPct: targetMarginPct,
+		RelistAfter:     relistAfter,
+		RelistStepPct:   0.25,
+	}
+}
+
+// OnBucket implements Strategy.
+func (s *MarginThresholdStrategy) OnBucket(ctx context.Context, state State, b storage.Bucket) []Order {
+	if state.Position > 0 {
+		if s.sellPrice == 0 {
+			s.sellPrice = int(float64(state.AvgCost) * (1 + s.TargetMarginPct))
+			s.listedAt = b.BucketStart
+		} else if s.RelistAfter > 0 && b.BucketStart.Sub(s.listedAt) >= s.RelistAfter {
+			s.sellPrice -= int(float64(s.sellPrice-state.AvgCost) * s.RelistStepPct)
+			s.listedAt = b.BucketStart
+		}
+		return []Order{{Side: Sell, Qty: state.Position, LimitPrice: s.sellPrice}}
+	}
+	s.sellPrice = 0
+
+	if b.AvgHighPrice == nil || b.AvgLowPrice == nil || b.HighPriceVolume == nil || *b.AvgLowPrice <= 0 {
+		return nil
+	}
+	if state.BuyLimitRemaining <= 0 {
+		return nil
+	}
+
+	marginPct := float64(*b.AvgHighPrice-*b.AvgLowPrice) / float64(*b.AvgLowPrice)
+	if marginPct < s.MinMarginPct || *b.HighPriceVolume < s.MinVolume1h {
+		return nil
+	}
+
+	return []Order{{Side: Buy, Qty: state.BuyLimitRemaining, LimitPrice: *b.AvgLowPrice}}
+}
+
+// EWOCrossoverStrategy enters when the Elliott-Wave Oscillator crosses from
+// negative to positive (momentum turning up) and exits at market on the
+// reverse crossover, using the same indicator.EWO streaming oscillator
+// Analyzer.applyVolatilitySignal computes for the LLM-facing EWO5m signal.
+//
+// Like MarginThresholdStrategy, one instance should back exactly one item's
+// replay, since it owns a running EWO across buckets.
+type EWOCrossoverStrategy struct {
+	ewo      *indicator.EWO
+	prevSign int
+}
+
+// NewEWOCrossoverStrategy returns an EWOCrossoverStrategy driven by an
+// EWO(fastWindow, slowWindow), e.g. NewEWOCrossoverStrategy(3, 19) to match
+// the indicator periods Analyzer.applyVolatilitySignal uses.
+func NewEWOCrossoverStrategy(fastWindow, slowWindow int) *EWOCrossoverStrategy {
+	return &EWOCrossoverStrategy{ewo: indicator.NewEWO(fastWindow, slowWindow)}
+}
+
+// OnBucket implements Strategy.
+func (s *EWOCrossoverStrategy) OnBucket(ctx context.Context, state State, b storage.Bucket) []Order {
+	if b.AvgHighPrice == nil || b.AvgLowPrice == nil {
+		return nil
+	}
+	mid := (float64(*b.AvgHighPrice) + float64(*b.AvgLowPrice)) / 2
+	value := s.ewo.Update(mid)
+
+	sign := 0
+	switch {
+	case value > 0:
+		sign = 1
+	case value < 0:
+		sign = -1
+	}
+	crossedUp := sign == 1 && s.prevSign <= 0
+	crossedDown := sign == -1 && s.prevSign >= 0
+	if sign != 0 {
+		s.prevSign = sign
+	}
+
+	if state.Position > 0 {
+		if crossedDown {
+			// Exit at market: a sell limit at the current insta-buy price
+			// is guaranteed to clear this bucket's high_price_volume.
+			return []Order{{Side: Sell, Qty: state.Position, LimitPrice: *b.AvgHighPrice}}
+		}
+		return nil
+	}
+
+	if crossedUp && state.BuyLimitRemaining > 0 {
+		// Enter at market: a buy limit at the current insta-sell price is
+		// guaranteed to clear this bucket's low_price_volume.
+		return []Order{{Side: Buy, Qty: state.BuyLimitRemaining, LimitPrice: *b.AvgLowPrice}}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"osrs-flipping/pkg/storage"
+)
+
+func TestMarginThresholdStrategy_EntersOnlyAboveMinMargin(t *testing.T) {
+	s := NewMarginThresholdStrategy(0.05, 10, 0.1, time.Hour)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	thin := storage.Bucket{BucketStart: base, AvgHighPrice: intPtr(102), HighPriceVolume: int64Ptr(100), AvgLowPrice: intPtr(100), LowPriceVolume: int64Ptr(100)}
+	if orders := s.OnBucket(context.Background(), State{BuyLimitRemaining: 10}, thin); orders != nil {
+		t.Errorf("expected no entry at ~2%% margin below the 5%% minimum, got %v", orders)
+	}
+
+	wide := storage.Bucket{BucketStart: base, AvgHighPrice: intPtr(110), HighPriceVolume: int64Ptr(100), AvgLowPrice: intPtr(100), LowPriceVolume: int64Ptr(100)}
+	orders := s.OnBucket(context.Background(), State{BuyLimitRemaining: 10}, wide)
+	if len(orders) != 1 || orders[0].Side != Buy || orders[0].LimitPrice != 100 {
+		t.Errorf("expected a buy at 100 for a 10%% margin bucket, got %v", orders)
+	}
+}
+
+func TestMarginThresholdStrategy_SellsAtTargetThenRelists(t *testing.T) {
+	s := NewMarginThresholdStrategy(0, 0, 0.1, time.Hour)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	held := State{Position: 10, AvgCost: 100}
+	orders := s.OnBucket(context.Background(), held, storage.Bucket{BucketStart: base})
+	if len(orders) != 1 || orders[0].LimitPrice != 110 {
+		t.Fatalf("expected first sell target at 110 (10%% above cost 100), got %v", orders)
+	}
+
+	// Not yet past RelistAfter: same price.
+	orders = s.OnBucket(context.Background(), held, storage.Bucket{BucketStart: base.Add(30 * time.Minute)})
+	if orders[0].LimitPrice != 110 {
+		t.Errorf("expected target to hold before RelistAfter elapses, got %d", orders[0].LimitPrice)
+	}
+
+	// Past RelistAfter: price shaved down.
+	orders = s.OnBucket(context.Background(), held, storage.Bucket{BucketStart: base.Add(2 * time.Hour)})
+	if orders[0].LimitPrice >= 110 {
+		t.Errorf("expected relist to shave the target price down, got %d", orders[0].LimitPrice)
+	}
+}
+
+func TestEWOCrossoverStrategy_EntersOnUpCrossAndExitsOnDownCross(t *testing.T) {
+	s := NewEWOCrossoverStrategy(1, 2)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tick := func(mid int, state State) []Order {
+		b := storage.Bucket{BucketStart: base, AvgHighPrice: intPtr(mid + 1), AvgLowPrice: intPtr(mid - 1)}
+		return s.OnBucket(context.Background(), state, b)
+	}
+
+	if orders := tick(100, State{BuyLimitRemaining: 10}); orders != nil {
+		t.Errorf("first tick seeds the oscillator at zero, expected no entry, got %v", orders)
+	}
+	orders := tick(120, State{BuyLimitRemaining: 10})
+	if len(orders) != 1 || orders[0].Side != Buy {
+		t.Fatalf("rising price should cross the EWO up and trigger a buy, got %v", orders)
+	}
+
+	// Now simulate holding the position and a falling price.
+	orders = tick(80, State{Position: 10})
+	if len(orders) != 1 || orders[0].Side != Sell {
+		t.Fatalf("falling price should cross the EWO down and trigger a sell, got %v", orders)
+	}
+}
@@ -28,16 +28,34 @@ func DefaultBackfillerConfig() *BackfillerConfig {
 }
 
 // Backfiller fetches historical timeseries data and populates price_buckets.
+// Start/Stop/HealthCheck are thin wrappers over an embedded BaseService
+// (see pkg/collector/base_service.go), the same lifecycle GapFiller and
+// VolumePoller use, rather than Backfiller hand-rolling its own
+// mu/running/stopCh bookkeeping.
 type Backfiller struct {
 	client *osrs.Client
 	repo   *Repository
 	config *BackfillerConfig
 	logger *logging.Logger
+	base   *BaseService
+
+	// source supplies the historical data itself; fallback, if non-nil, is
+	// tried whenever source has nothing for an item (see NewBackfillerWithSource).
+	source   HistoricalSource
+	fallback HistoricalSource
 
 	mu       sync.Mutex
-	running  bool
-	stopCh   chan struct{}
 	progress BackfillProgress
+
+	metrics *CollectorMetrics
+}
+
+// SetMetrics wires m into b, so subsequent backfillItem/backfillBucketSize
+// calls record collector_buckets_inserted_total/collector_backfill_progress_ratio
+// (see CollectorMetrics). Optional -- a Backfiller left without SetMetrics
+// just skips recording.
+func (b *Backfiller) SetMetrics(m *CollectorMetrics) {
+	b.metrics = m
 }
 
 // BackfillProgress tracks backfill status.
@@ -51,76 +69,116 @@ type BackfillProgress struct {
 	StartTime       time.Time
 }
 
-// NewBackfiller creates a new Backfiller.
+// NewBackfiller creates a new Backfiller that fetches directly from the
+// live API, preserving this package's original backfill behavior.
 func NewBackfiller(client *osrs.Client, repo *Repository, config *BackfillerConfig, logger *logging.Logger) *Backfiller {
+	return NewBackfillerWithSource(client, repo, config, logger, newAPIHistoricalSource(client))
+}
+
+// NewBackfillerWithSource creates a Backfiller that reads from source
+// instead of hardcoding the live API, e.g. a DumpSource seeding a fresh
+// database from a bulk snapshot. client is still used as a fallback: any
+// item source has no data for is fetched from the API instead, so an
+// incomplete dump doesn't leave gaps the way a dump-only backfill would.
+func NewBackfillerWithSource(client *osrs.Client, repo *Repository, config *BackfillerConfig, logger *logging.Logger, source HistoricalSource) *Backfiller {
 	if config == nil {
 		config = DefaultBackfillerConfig()
 	}
-	return &Backfiller{
+	b := &Backfiller{
 		client: client,
 		repo:   repo,
 		config: config,
 		logger: logger,
-		stopCh: make(chan struct{}),
+		source: source,
 	}
+	if _, isAPISource := source.(*apiHistoricalSource); !isAPISource {
+		b.fallback = newAPIHistoricalSource(client)
+	}
+	b.base = NewBaseService("backfiller", b, logger)
+	return b
 }
 
-// Run executes the backfill process. Blocks until complete or stopped.
+// Run executes one full backfill pass -- every configured bucket size --
+// and blocks until it completes, fails, or ctx is canceled. A thin
+// Start+Wait wrapper over BaseService, kept for cmd/collector's
+// -backfill-mode one-shot mode, which wants a single blocking call rather
+// than a long-running service.
 func (b *Backfiller) Run(ctx context.Context) error {
-	b.mu.Lock()
-	if b.running {
-		b.mu.Unlock()
-		return nil
-	}
-	b.running = true
-	b.stopCh = make(chan struct{})
-	b.mu.Unlock()
+	b.base.Start(ctx)
+	return b.base.Wait()
+}
 
-	defer func() {
-		b.mu.Lock()
-		b.running = false
-		b.mu.Unlock()
-	}()
+// Start begins one backfill pass in a goroutine without blocking for it
+// to finish, so ServiceRegistry/Supervisor can drive Backfiller the same
+// uniform way they drive VolumePoller and GapFiller.
+func (b *Backfiller) Start() {
+	b.base.Start(context.Background())
+}
+
+// Stop cancels an in-progress backfill pass and waits for it to return.
+func (b *Backfiller) Stop() {
+	b.base.Stop()
+}
+
+// HealthCheck reports the error that failed the most recent backfill pass,
+// nil if it hasn't failed.
+func (b *Backfiller) HealthCheck() error {
+	return b.base.HealthCheck()
+}
 
+// OnStart implements ServiceImpl: it resets progress for a fresh pass.
+func (b *Backfiller) OnStart(ctx context.Context) error {
+	b.mu.Lock()
 	b.progress = BackfillProgress{StartTime: time.Now()}
+	b.mu.Unlock()
+	return nil
+}
 
+// OnLoop implements ServiceImpl: it runs one full pass over every
+// configured bucket size and then returns ErrServiceDone, since a backfill
+// pass -- like GapFiller's -- is one-shot rather than continuously
+// repeating.
+func (b *Backfiller) OnLoop(ctx context.Context) error {
 	// Get list of items to backfill from observations or mapping
 	items, err := b.getItemsToBackfill(ctx)
 	if err != nil {
 		return err
 	}
+	b.mu.Lock()
 	b.progress.TotalItems = len(items)
+	b.mu.Unlock()
 
 	b.logger.WithComponent("backfiller").WithFields(map[string]interface{}{
 		"total_items":  len(items),
 		"bucket_sizes": b.config.BucketSizes,
 	}).Info("starting backfill")
 
-	// Process each bucket size
 	for _, bucketSize := range b.config.BucketSizes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if err := b.backfillBucketSize(ctx, items, bucketSize); err != nil {
 			return err
 		}
 	}
 
-	elapsed := time.Since(b.progress.StartTime)
-	b.logger.WithComponent("backfiller").WithFields(map[string]interface{}{
-		"elapsed":          elapsed.String(),
-		"items_processed":  b.progress.ProcessedItems,
-		"buckets_inserted": b.progress.BucketsInserted,
-		"errors":           b.progress.Errors,
-	}).Info("backfill complete")
-
-	return nil
+	return ErrServiceDone
 }
 
-// Stop signals the backfiller to stop.
-func (b *Backfiller) Stop() {
+// OnStop implements ServiceImpl: it logs the pass's summary, mirroring
+// the old Run's completion log line.
+func (b *Backfiller) OnStop() {
 	b.mu.Lock()
-	defer b.mu.Unlock()
-	if b.running {
-		close(b.stopCh)
-	}
+	progress := b.progress
+	b.mu.Unlock()
+
+	elapsed := time.Since(progress.StartTime)
+	b.logger.WithComponent("backfiller").WithFields(map[string]interface{}{
+		"elapsed":          elapsed.String(),
+		"items_processed":  progress.ProcessedItems,
+		"buckets_inserted": progress.BucketsInserted,
+		"errors":           progress.Errors,
+	}).Info("backfill complete")
 }
 
 // Progress returns current backfill progress.
@@ -140,7 +198,7 @@ func (b *Backfiller) getItemsToBackfill(ctx context.Context) ([]int, error) {
 	// If no observations yet, fetch item mapping from API
 	if len(items) == 0 {
 		b.logger.WithComponent("backfiller").Info("no observations found, fetching item mapping from API")
-		mappings, err := b.client.GetItemMapping(ctx)
+		mappings, _, _, _, err := b.client.GetItemMapping(ctx, "", "")
 		if err != nil {
 			return nil, err
 		}
@@ -160,6 +218,15 @@ func (b *Backfiller) backfillBucketSize(ctx context.Context, items []int, bucket
 		return err
 	}
 
+	// Pull the whole bucketSize snapshot from source in one pass, if it
+	// supports bulk enumeration (see HistoricalSource.BulkFetch). Items
+	// missing from the result still fall through to backfillItem's
+	// per-item fetch/fallback.
+	bulk, _, err := b.source.BulkFetch(ctx, bucketSize)
+	if err != nil {
+		return err
+	}
+
 	// Filter to items that need backfilling
 	var toBackfill []int
 	for _, itemID := range items {
@@ -176,9 +243,6 @@ func (b *Backfiller) backfillBucketSize(ctx context.Context, items []int, bucket
 
 	for i, itemID := range toBackfill {
 		select {
-		case <-b.stopCh:
-			b.logger.WithComponent("backfiller").Info("backfill stopped by signal")
-			return nil
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
@@ -189,7 +253,7 @@ func (b *Backfiller) backfillBucketSize(ctx context.Context, items []int, bucket
 		b.progress.CurrentBucket = bucketSize
 		b.mu.Unlock()
 
-		if err := b.backfillItem(ctx, itemID, bucketSize); err != nil {
+		if err := b.backfillItem(ctx, itemID, bucketSize, bulk); err != nil {
 			b.logger.WithComponent("backfiller").WithError(err).WithFields(map[string]interface{}{
 				"item_id":     itemID,
 				"bucket_size": bucketSize,
@@ -204,6 +268,10 @@ func (b *Backfiller) backfillBucketSize(ctx context.Context, items []int, bucket
 		b.progress.ProcessedItems++
 		b.mu.Unlock()
 
+		if b.metrics != nil {
+			b.metrics.SetBackfillProgressRatio(bucketSize, float64(i+1)/float64(len(toBackfill)))
+		}
+
 		// Log progress periodically
 		if (i+1)%b.config.BatchSize == 0 {
 			b.logger.WithComponent("backfiller").WithFields(map[string]interface{}{
@@ -221,45 +289,53 @@ func (b *Backfiller) backfillBucketSize(ctx context.Context, items []int, bucket
 	return nil
 }
 
-func (b *Backfiller) backfillItem(ctx context.Context, itemID int, bucketSize string) error {
-	// Fetch timeseries from API
-	resp, err := b.client.GetTimeseriesTyped(ctx, itemID, bucketSize)
-	if err != nil {
-		return err
+// backfillItem fetches itemID's bucketSize timeseries and inserts it as
+// price buckets. bulk, if non-nil, is the whole-bucketSize snapshot
+// source.BulkFetch already pulled for this run; itemID is only fetched
+// individually (via source.FetchTimeseries, then b.fallback) when bulk has
+// nothing for it.
+func (b *Backfiller) backfillItem(ctx context.Context, itemID int, bucketSize string, bulk map[int][]TimeseriesPoint) error {
+	points, found := bulk[itemID]
+	sourceName := b.source.Name()
+	if !found {
+		var err error
+		points, err = b.source.FetchTimeseries(ctx, itemID, bucketSize)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(points) == 0 && b.fallback != nil {
+		var err error
+		points, err = b.fallback.FetchTimeseries(ctx, itemID, bucketSize)
+		if err != nil {
+			return err
+		}
+		sourceName = b.fallback.Name()
 	}
 
-	if len(resp.Data) == 0 {
+	if len(points) == 0 {
 		return nil // No data for this item
 	}
 
 	// Convert to price buckets
-	buckets := make([]PriceBucket, 0, len(resp.Data))
-	for _, dp := range resp.Data {
+	buckets := make([]PriceBucket, 0, len(points))
+	for _, p := range points {
 		// Skip empty data points
-		if dp.AvgHighPrice == nil && dp.AvgLowPrice == nil {
+		if p.AvgHighPrice == nil && p.AvgLowPrice == nil {
 			continue
 		}
 
-		bucket := PriceBucket{
-			ItemID:       itemID,
-			BucketStart:  time.Unix(dp.Timestamp, 0).UTC(),
-			BucketSize:   bucketSize,
-			AvgHighPrice: dp.AvgHighPrice,
-			AvgLowPrice:  dp.AvgLowPrice,
-			Source:       "api",
-		}
-
-		// Convert volume to int64 pointers (VolumeDataPoint uses HighPriceVol/LowPriceVol)
-		if dp.HighPriceVol != nil {
-			v := int64(*dp.HighPriceVol)
-			bucket.HighPriceVolume = &v
-		}
-		if dp.LowPriceVol != nil {
-			v := int64(*dp.LowPriceVol)
-			bucket.LowPriceVolume = &v
-		}
-
-		buckets = append(buckets, bucket)
+		buckets = append(buckets, PriceBucket{
+			ItemID:          itemID,
+			BucketStart:     p.Timestamp,
+			BucketSize:      bucketSize,
+			AvgHighPrice:    p.AvgHighPrice,
+			AvgLowPrice:     p.AvgLowPrice,
+			HighPriceVolume: p.HighPriceVolume,
+			LowPriceVolume:  p.LowPriceVolume,
+			Source:          sourceName,
+		})
 	}
 
 	// Insert buckets
@@ -272,5 +348,9 @@ func (b *Backfiller) backfillItem(ctx context.Context, itemID int, bucketSize st
 	b.progress.BucketsInserted += inserted
 	b.mu.Unlock()
 
+	if b.metrics != nil {
+		b.metrics.RecordBucketsInserted(bucketSize, sourceName, inserted)
+	}
+
 	return nil
 }
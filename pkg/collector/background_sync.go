@@ -2,6 +2,8 @@ package collector
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
 	"strconv"
 	"sync"
 	"time"
@@ -22,21 +24,31 @@ var RetentionPolicy = map[string]time.Duration{
 
 // BackgroundSyncConfig configures the background sync service.
 type BackgroundSyncConfig struct {
-	BucketSizes        []string      // Bucket sizes to sync (default: ["5m", "1h", "24h"])
-	RunInterval        time.Duration // How often to run a full sync cycle (default: 5m)
-	TimestampsPerCycle int           // Max timestamps to process per bucket per cycle (default: 50)
-	MinItemThreshold   int           // Timestamps with fewer items than this are re-fetched (default: 100)
-	RateLimit          time.Duration // Minimum delay between API calls (default: 100ms)
+	BucketSizes         []string      // Bucket sizes to sync (default: ["5m", "1h", "24h"])
+	RunInterval         time.Duration // How often to run a full sync cycle (default: 5m)
+	TimestampsPerCycle  int           // Max timestamps to process per bucket per cycle (default: 50)
+	MinItemThreshold    int           // Timestamps with fewer items than this are re-fetched (default: 100)
+	RateLimit           time.Duration // Minimum delay between API calls (default: 100ms)
+	BackoffBase         time.Duration // Initial backoff delay after a retryable API error (default: 500ms)
+	BackoffCap          time.Duration // Maximum backoff delay (default: 30s)
+	RetryBudgetPerCycle int           // Max retryable-error retries across a single cycle (default: 20)
+	Workers             int           // Number of timestamps fetched concurrently per bucket size (default: 4)
+	FlushBatchSize      int           // Buckets accumulated before an InsertPriceBuckets flush (default: 100)
 }
 
 // DefaultBackgroundSyncConfig returns sensible defaults.
 func DefaultBackgroundSyncConfig() *BackgroundSyncConfig {
 	return &BackgroundSyncConfig{
-		BucketSizes:        []string{"5m", "1h", "24h"},
-		RunInterval:        5 * time.Minute,
-		TimestampsPerCycle: 50,
-		MinItemThreshold:   100,
-		RateLimit:          100 * time.Millisecond,
+		BucketSizes:         []string{"5m", "1h", "24h"},
+		RunInterval:         5 * time.Minute,
+		TimestampsPerCycle:  50,
+		MinItemThreshold:    100,
+		RateLimit:           100 * time.Millisecond,
+		BackoffBase:         500 * time.Millisecond,
+		BackoffCap:          30 * time.Second,
+		RetryBudgetPerCycle: 20,
+		Workers:             4,
+		FlushBatchSize:      100,
 	}
 }
 
@@ -48,6 +60,7 @@ type BackgroundSyncProgress struct {
 	Errors           int
 	LastCycleStart   time.Time
 	LastCycleEnd     time.Time
+	RateBreaker      RateBreakerStatus
 }
 
 // BackgroundSync continuously syncs historical price data in the background.
@@ -59,12 +72,17 @@ type BackgroundSync struct {
 	config  *BackgroundSyncConfig
 	logger  *logging.Logger
 	limiter *rate.Limiter
+	metrics *syncMetrics
 
 	mu       sync.Mutex
 	running  bool
 	stopCh   chan struct{}
 	doneCh   chan struct{}
 	progress BackgroundSyncProgress
+	breaker  rateBreakerState
+
+	observerMu sync.RWMutex
+	observers  []*observerSub
 }
 
 // NewBackgroundSync creates a new BackgroundSync.
@@ -84,13 +102,16 @@ func NewBackgroundSync(client *osrs.Client, repo *Repository, config *Background
 		limiter = rate.NewLimiter(rate.Every(config.RateLimit), 1)
 	}
 
-	return &BackgroundSync{
+	b := &BackgroundSync{
 		client:  client,
 		repo:    repo,
 		config:  config,
 		logger:  logger,
 		limiter: limiter,
+		metrics: newSyncMetrics(),
 	}
+	b.registerRateBreakerCollector()
+	return b
 }
 
 // Start begins the background sync loop in a goroutine.
@@ -126,7 +147,9 @@ func (b *BackgroundSync) Stop() {
 func (b *BackgroundSync) Progress() BackgroundSyncProgress {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	return b.progress
+	progress := b.progress
+	progress.RateBreaker = b.rateBreakerStatusLocked()
+	return progress
 }
 
 // Running returns whether the sync is currently running.
@@ -136,6 +159,36 @@ func (b *BackgroundSync) Running() bool {
 	return b.running
 }
 
+// Stats merges this sync's progress counters and rate-breaker state,
+// matching the shape Poller/VolumePoller/GapFiller/Backfiller's Stats
+// return, so a Supervisor can treat BackgroundSync uniformly alongside
+// them if it's ever registered in place of Backfiller+GapFiller (see this
+// type's doc comment).
+func (b *BackgroundSync) Stats() map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return map[string]interface{}{
+		"running":           b.running,
+		"cycles_completed":  b.progress.CyclesCompleted,
+		"timestamps_synced": b.progress.TimestampsSynced,
+		"buckets_filled":    b.progress.BucketsFilled,
+		"errors":            b.progress.Errors,
+		"rate_breaker_open": b.rateBreakerStatusLocked().Open,
+	}
+}
+
+// HealthCheck reports an error once the rate breaker's consecutive-error
+// count reaches errorRateWindow's failure threshold, so Supervisor knows
+// to restart the sync instead of leaving it stuck against a broken API.
+func (b *BackgroundSync) HealthCheck() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if consecutive := b.breaker.consecutiveErrors; consecutive >= int(errorRateWindow*errorRateThreshold) {
+		return fmt.Errorf("background_sync: %d consecutive API errors", consecutive)
+	}
+	return nil
+}
+
 func (b *BackgroundSync) run() {
 	defer func() {
 		b.mu.Lock()
@@ -188,6 +241,7 @@ func (b *BackgroundSync) runCycle() {
 	cycleTimestampsSynced := int64(0)
 	cycleBucketsFilled := int64(0)
 	cycleErrors := 0
+	budget := &retryBudget{n: b.config.RetryBudgetPerCycle}
 
 	for _, bucketSize := range b.config.BucketSizes {
 		select {
@@ -196,10 +250,14 @@ func (b *BackgroundSync) runCycle() {
 		default:
 		}
 
-		timestampsSynced, bucketsFilled, errors := b.syncBucketSize(ctx, bucketSize)
+		timestampsSynced, bucketsFilled, errors, fatalErr := b.syncBucketSize(ctx, bucketSize, budget)
 		cycleTimestampsSynced += timestampsSynced
 		cycleBucketsFilled += bucketsFilled
 		cycleErrors += errors
+		if fatalErr != nil {
+			b.logger.WithComponent("background_sync").WithError(fatalErr).WithField("bucket_size", bucketSize).Error("sync cycle aborted by a fatal error")
+			break
+		}
 	}
 
 	b.mu.Lock()
@@ -210,6 +268,7 @@ func (b *BackgroundSync) runCycle() {
 	b.progress.LastCycleEnd = time.Now()
 	cycleDuration := b.progress.LastCycleEnd.Sub(b.progress.LastCycleStart)
 	cycleNum := b.progress.CyclesCompleted
+	b.metrics.lastCycleEnd.Set(float64(b.progress.LastCycleEnd.Unix()))
 	b.mu.Unlock()
 
 	b.logger.WithComponent("background_sync").WithFields(map[string]interface{}{
@@ -219,66 +278,193 @@ func (b *BackgroundSync) runCycle() {
 		"errors":            cycleErrors,
 		"duration":          cycleDuration.String(),
 	}).Info("sync cycle completed")
+
+	b.notifyCycleComplete(b.Progress())
 }
 
-func (b *BackgroundSync) syncBucketSize(ctx context.Context, bucketSize string) (timestampsSynced int64, bucketsFilled int64, errors int) {
+// syncBucketSize fans syncTimestamp work for bucketSize out across
+// b.config.Workers goroutines (API access stays serialized through the
+// shared b.limiter, which is goroutine-safe), batches the resulting
+// PriceBucket slices, and flushes them to InsertPriceBuckets every
+// b.config.FlushBatchSize buckets to amortize round-trips. The pool drains
+// (every worker exits) before this returns, whether that's because the
+// timestamps ran out or ctx was canceled.
+func (b *BackgroundSync) syncBucketSize(ctx context.Context, bucketSize string, budget *retryBudget) (timestampsSynced int64, bucketsFilled int64, errors int, fatalErr error) {
+	cycleStart := time.Now()
+	defer func() {
+		b.metrics.cycleDuration.WithLabelValues(bucketSize).Observe(time.Since(cycleStart).Seconds())
+	}()
+
 	retention := RetentionPolicy[bucketSize]
 
-	// Get timestamps that need sync (missing or incomplete)
-	timestamps, err := b.repo.GetMissingBucketTimestamps(ctx, bucketSize, retention, b.config.MinItemThreshold, b.config.TimestampsPerCycle)
+	// Get timestamps that need sync, preferring a forward scan from the
+	// persisted checkpoint over a full missing-timestamps scan.
+	timestamps, priorCheckpoint, usedFullScan, err := b.resolveTimestamps(ctx, bucketSize, retention)
 	if err != nil {
-		b.logger.WithComponent("background_sync").WithError(err).WithField("bucket_size", bucketSize).Error("failed to get missing timestamps")
-		return 0, 0, 1
+		b.logger.WithComponent("background_sync").WithError(err).WithField("bucket_size", bucketSize).Error("failed to resolve timestamps to sync")
+		b.recordError(bucketSize, "resolve_timestamps")
+		return 0, 0, 1, nil
+	}
+
+	b.metrics.missingTotal.WithLabelValues(bucketSize).Set(float64(len(timestamps)))
+
+	newCheckpoint := priorCheckpoint
+	newCheckpoint.BucketSize = bucketSize
+	if usedFullScan {
+		newCheckpoint.CyclesSinceFullScan = 0
+	} else {
+		newCheckpoint.CyclesSinceFullScan++
+	}
+	// saveCheckpoint persists newCheckpoint, advancing LastSyncedAt to
+	// maxSyncedTs when this cycle made any forward progress. Note: since
+	// workers complete out of order, this can advance past a timestamp that
+	// failed -- the periodic full scan (fullScanInterval) catches that drift.
+	saveCheckpoint := func(maxSyncedTs time.Time) {
+		if !maxSyncedTs.IsZero() {
+			newCheckpoint.LastSyncedAt = maxSyncedTs
+		}
+		if err := b.repo.SaveCheckpoint(ctx, newCheckpoint); err != nil {
+			b.logger.WithComponent("background_sync").WithError(err).WithField("bucket_size", bucketSize).Warn("failed to persist sync checkpoint")
+		}
 	}
 
 	if len(timestamps) == 0 {
 		b.logger.WithComponent("background_sync").WithField("bucket_size", bucketSize).Debug("no timestamps need sync")
-		return 0, 0, 0
+		saveCheckpoint(time.Time{})
+		return 0, 0, 0, nil
 	}
 
 	b.logger.WithComponent("background_sync").WithFields(map[string]interface{}{
 		"bucket_size":      bucketSize,
 		"timestamps_count": len(timestamps),
+		"workers":          b.config.Workers,
 	}).Debug("syncing timestamps")
 
-	for _, ts := range timestamps {
-		select {
-		case <-ctx.Done():
-			return timestampsSynced, bucketsFilled, errors
-		default:
+	workers := b.config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan time.Time)
+	results := make(chan timestampResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ts := range jobs {
+				buckets, err := b.fetchTimestampBuckets(ctx, bucketSize, ts, budget)
+				select {
+				case results <- timestampResult{ts: ts, buckets: buckets, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, ts := range timestamps {
+			select {
+			case jobs <- ts:
+			case <-ctx.Done():
+				return
+			}
 		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	batchSize := b.config.FlushBatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	pending := make([]PriceBucket, 0, batchSize)
+	pendingGroups := make([]pendingGroup, 0, batchSize)
 
-		filled, err := b.syncTimestamp(ctx, bucketSize, ts)
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		inserted, err := b.repo.InsertPriceBuckets(ctx, pending)
+		pending = pending[:0]
+		groups := pendingGroups
+		pendingGroups = pendingGroups[:0]
 		if err != nil {
-			b.logger.WithComponent("background_sync").WithError(err).WithFields(map[string]interface{}{
-				"timestamp":   ts.Format(time.RFC3339),
+			b.logger.WithComponent("background_sync").WithError(err).WithField("bucket_size", bucketSize).Error("failed to flush price buckets")
+			b.recordError(bucketSize, "insert_price_buckets")
+			if fatalErr == nil {
+				fatalErr = err
+			}
+			return
+		}
+		bucketsFilled += inserted
+		b.metrics.bucketsInserted.WithLabelValues(bucketSize).Add(float64(inserted))
+		for _, g := range groups {
+			b.notifyBucketsInserted(bucketSize, g.ts, g.buckets)
+		}
+	}
+
+	var maxSyncedTs time.Time
+	for res := range results {
+		if res.err != nil {
+			b.logger.WithComponent("background_sync").WithError(res.err).WithFields(map[string]interface{}{
+				"timestamp":   res.ts.Format(time.RFC3339),
 				"bucket_size": bucketSize,
 			}).Warn("failed to sync timestamp")
+			b.recordError(bucketSize, "sync_timestamp")
 			errors++
+			if fatalErr == nil && ctx.Err() != nil {
+				fatalErr = ctx.Err()
+			}
 			continue
 		}
 
 		timestampsSynced++
-		bucketsFilled += filled
-	}
+		b.metrics.timestampsSynced.WithLabelValues(bucketSize).Inc()
+		if res.ts.After(maxSyncedTs) {
+			maxSyncedTs = res.ts
+		}
 
-	return timestampsSynced, bucketsFilled, errors
-}
+		if len(res.buckets) == 0 {
+			if perr := b.repo.MarkPoisoned(ctx, bucketSize, res.ts); perr != nil {
+				b.logger.WithComponent("background_sync").WithError(perr).WithField("bucket_size", bucketSize).Warn("failed to record poisoned timestamp")
+			}
+		}
 
-func (b *BackgroundSync) syncTimestamp(ctx context.Context, bucketSize string, ts time.Time) (int64, error) {
-	// Wait for rate limiter
-	if err := b.limiter.Wait(ctx); err != nil {
-		return 0, err
+		pending = append(pending, res.buckets...)
+		if len(res.buckets) > 0 {
+			pendingGroups = append(pendingGroups, pendingGroup{ts: res.ts, buckets: res.buckets})
+		}
+		if len(pending) >= batchSize {
+			flush()
+		}
 	}
+	flush()
+
+	saveCheckpoint(maxSyncedTs)
 
-	// Fetch all items for this timestamp from the bulk endpoint
-	resp, err := b.client.GetBulkPrices(ctx, bucketSize, &ts)
+	return timestampsSynced, bucketsFilled, errors, fatalErr
+}
+
+// fetchTimestampBuckets fetches and converts one timestamp's bulk prices
+// into PriceBucket rows, for syncBucketSize's worker pool to batch and
+// flush. Unlike the sequential version this replaced, it doesn't insert --
+// callers own batching so a single slow InsertPriceBuckets call doesn't
+// serialize the whole pool.
+func (b *BackgroundSync) fetchTimestampBuckets(ctx context.Context, bucketSize string, ts time.Time, budget *retryBudget) ([]PriceBucket, error) {
+	resp, err := b.fetchBulkPrices(ctx, bucketSize, ts, budget)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	if len(resp.Data) == 0 {
-		return 0, nil
+		return nil, nil
 	}
 
 	// The response timestamp is the canonical bucket start time
@@ -318,15 +504,56 @@ func (b *BackgroundSync) syncTimestamp(ctx context.Context, bucketSize string, t
 		buckets = append(buckets, bucket)
 	}
 
-	if len(buckets) == 0 {
-		return 0, nil
-	}
+	return buckets, nil
+}
 
-	// Insert buckets (upsert handles conflicts)
-	inserted, err := b.repo.InsertPriceBuckets(ctx, buckets)
-	if err != nil {
-		return 0, err
+// fetchBulkPrices calls the bulk prices endpoint, retrying retryable
+// failures (rate limit, server error, network) with exponential backoff and
+// jitter until they succeed, budget is exhausted, or ctx is canceled. Every
+// attempt -- success or failure -- updates the shared rate breaker.
+func (b *BackgroundSync) fetchBulkPrices(ctx context.Context, bucketSize string, ts time.Time, budget *retryBudget) (*osrs.BulkPriceResponse, error) {
+	backoff := b.config.BackoffBase
+
+	for {
+		b.checkRateBreakerProbe()
+
+		if err := b.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		apiStart := time.Now()
+		resp, err := b.client.GetBulkPrices(ctx, bucketSize, &ts)
+		b.recordAPICall("bulk_prices", apiStart)
+		b.recordCallOutcome(err == nil)
+
+		if err == nil {
+			return resp, nil
+		}
+
+		class := osrs.ClassifyError(err)
+		if !class.Retryable() || !budget.take() {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(jitteredDelay(backoff)):
+		}
+
+		backoff *= 2
+		if backoff > b.config.BackoffCap {
+			backoff = b.config.BackoffCap
+		}
 	}
+}
 
-	return inserted, nil
+// jitteredDelay returns a random duration in [d/2, d), so retrying workers
+// don't all wake up and hit the API in lockstep.
+func jitteredDelay(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)))
 }
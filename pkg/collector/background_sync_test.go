@@ -1,6 +1,10 @@
 package collector
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -46,6 +50,14 @@ func TestDefaultBackgroundSyncConfig(t *testing.T) {
 		t.Errorf("RateLimit = %v, want 100ms", cfg.RateLimit)
 	}
 
+	if cfg.Workers != 4 {
+		t.Errorf("Workers = %d, want 4", cfg.Workers)
+	}
+
+	if cfg.FlushBatchSize != 100 {
+		t.Errorf("FlushBatchSize = %d, want 100", cfg.FlushBatchSize)
+	}
+
 	expectedBuckets := []string{"5m", "1h", "24h"}
 	if len(cfg.BucketSizes) != len(expectedBuckets) {
 		t.Errorf("BucketSizes length = %d, want %d", len(cfg.BucketSizes), len(expectedBuckets))
@@ -139,3 +151,99 @@ func TestBackgroundSync_StartStop_NoOp(t *testing.T) {
 	// Double stop should be no-op
 	bs.Stop() // Should not panic or block
 }
+
+func TestBackgroundSync_MetricsHandlerServesRegistry(t *testing.T) {
+	bs := NewBackgroundSync(nil, nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	bs.NewMetricsHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 from the metrics handler, got %d", rec.Code)
+	}
+}
+
+func TestBackgroundSync_RateLimiterHonoredAcrossWorkers(t *testing.T) {
+	// Stress test for syncBucketSize's worker-pool contract: however many
+	// goroutines fan out fetchTimestampBuckets calls, they all draw from
+	// the same rate.Limiter, so the aggregate call rate across all workers
+	// is still bounded by it.
+	const (
+		workers = 4
+		calls   = 20
+		every   = 10 * time.Millisecond
+	)
+	bs := NewBackgroundSync(nil, nil, nil, nil, rate.NewLimiter(rate.Every(every), 1))
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < calls/workers; j++ {
+				if err := bs.limiter.Wait(context.Background()); err != nil {
+					t.Errorf("Wait: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	// Burst is 1, so calls-1 intervals of `every` must elapse no matter
+	// how the calls are distributed across workers.
+	minExpected := time.Duration(calls-1) * every
+	if elapsed < minExpected {
+		t.Errorf("rate limiter not honored across %d workers: %d calls completed in %v, want at least %v", workers, calls, elapsed, minExpected)
+	}
+}
+
+func TestBackgroundSync_RateBreakerOpensOnHighErrorRate(t *testing.T) {
+	bs := NewBackgroundSync(nil, nil, nil, nil, rate.NewLimiter(rate.Every(time.Millisecond), 1))
+	baseRate := bs.limiter.Limit()
+
+	// 40 successes then 20 failures: the 11th failure pushes the trailing
+	// 50-call window to 11/50, over the 20% threshold.
+	for i := 0; i < 40; i++ {
+		bs.recordCallOutcome(true)
+	}
+	for i := 0; i < 20; i++ {
+		bs.recordCallOutcome(false)
+	}
+
+	status := bs.RateBreakerStatus()
+	if !status.Open {
+		t.Fatal("expected the rate breaker to open after a high error rate")
+	}
+	if status.EffectiveRate != float64(baseRate)/2 {
+		t.Errorf("EffectiveRate = %v, want %v (half of base rate)", status.EffectiveRate, float64(baseRate)/2)
+	}
+	if status.ConsecutiveErrors != 20 {
+		t.Errorf("ConsecutiveErrors = %d, want 20", status.ConsecutiveErrors)
+	}
+}
+
+func TestBackgroundSync_RecordErrorIncrementsCounter(t *testing.T) {
+	bs := NewBackgroundSync(nil, nil, nil, nil, nil)
+	bs.recordError("5m", "sync_timestamp")
+
+	families, err := bs.metrics.registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+
+	var saw bool
+	for _, family := range families {
+		if family.GetName() == "osrs_sync_errors_total" {
+			saw = true
+			if len(family.GetMetric()) != 1 {
+				t.Errorf("expected 1 errors_total series, got %d", len(family.GetMetric()))
+			}
+		}
+	}
+	if !saw {
+		t.Error("expected osrs_sync_errors_total to be recorded")
+	}
+}
@@ -0,0 +1,292 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"osrs-flipping/pkg/logging"
+)
+
+// ServiceState is a BaseService's lifecycle stage, surfaced via Stats so
+// callers can tell "never started" apart from "stopped cleanly" and
+// "stopped because OnStart/OnLoop errored or panicked".
+type ServiceState string
+
+const (
+	StateIdle    ServiceState = "idle"
+	StateRunning ServiceState = "running"
+	StateStopped ServiceState = "stopped"
+	StateFailed  ServiceState = "failed"
+)
+
+// ErrServiceDone is OnLoop's signal that it finished its work and the
+// service should stop cleanly, for one-shot services like GapFiller as
+// opposed to continuously-ticking ones like VolumePoller, whose OnLoop
+// only returns when ctx is canceled.
+var ErrServiceDone = errors.New("service done")
+
+// ServiceImpl is what a type embedding BaseService provides. OnStart runs
+// once before the first OnLoop call. OnLoop runs repeatedly until it
+// returns a non-nil error: ErrServiceDone or context.Canceled stop the
+// service cleanly, anything else marks it Failed. OnStop always runs
+// exactly once on the way out, success or failure, to release whatever
+// OnStart acquired (tickers, in-progress counters, etc).
+type ServiceImpl interface {
+	OnStart(ctx context.Context) error
+	OnLoop(ctx context.Context) error
+	OnStop()
+}
+
+// BaseService is tendermint's libs/service pattern adapted to this repo's
+// needs: idempotent Start/Stop, a context derived from Start's parent so
+// Stop cancels whatever ctx-aware call an OnLoop iteration is blocked in,
+// panic recovery that fails the service instead of crashing the process,
+// and Wait() for the terminal error. VolumePoller and GapFiller embed one
+// instead of each hand-rolling their own stopCh/doneCh/running/mu quartet.
+type BaseService struct {
+	name   string
+	impl   ServiceImpl
+	logger *logging.Logger
+
+	mu     sync.Mutex
+	state  ServiceState
+	cancel context.CancelFunc
+	doneCh chan struct{}
+	err    error
+}
+
+// NewBaseService creates a BaseService named name (used in log lines and
+// Stats) that drives impl's OnStart/OnLoop/OnStop hooks.
+func NewBaseService(name string, impl ServiceImpl, logger *logging.Logger) *BaseService {
+	return &BaseService{name: name, impl: impl, logger: logger, state: StateIdle}
+}
+
+// Start launches impl's lifecycle in a goroutine running off a context
+// derived from parent, so Stop's cancel reaches any ctx-aware call an
+// OnLoop iteration is blocked in. Idempotent -- a second Start while
+// already running is a no-op.
+func (s *BaseService) Start(parent context.Context) {
+	s.mu.Lock()
+	if s.state == StateRunning {
+		s.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(parent)
+	s.cancel = cancel
+	s.doneCh = make(chan struct{})
+	s.state = StateRunning
+	s.err = nil
+	s.mu.Unlock()
+
+	go s.run(ctx)
+}
+
+// Stop cancels the running service's context and blocks until its
+// goroutine has returned from OnStop. A no-op if not running.
+func (s *BaseService) Stop() {
+	s.mu.Lock()
+	if s.state != StateRunning {
+		s.mu.Unlock()
+		return
+	}
+	cancel := s.cancel
+	doneCh := s.doneCh
+	s.mu.Unlock()
+
+	cancel()
+	<-doneCh
+}
+
+// Wait blocks until the service stops -- cleanly, via Stop, or by failing
+// -- and returns its terminal error (nil on a clean stop). Returns
+// immediately with a nil error if Start was never called.
+func (s *BaseService) Wait() error {
+	s.mu.Lock()
+	doneCh := s.doneCh
+	s.mu.Unlock()
+	if doneCh == nil {
+		return nil
+	}
+	<-doneCh
+	return s.Err()
+}
+
+// Running reports whether the service is currently in StateRunning.
+func (s *BaseService) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state == StateRunning
+}
+
+// State returns the service's current lifecycle stage.
+func (s *BaseService) State() ServiceState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Err returns the error that stopped the service, nil if it hasn't
+// stopped yet or stopped cleanly.
+func (s *BaseService) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// HealthCheck reports the error that put the service into StateFailed, nil
+// otherwise (including when it's idle, running, or cleanly stopped). Used
+// by Supervisor to decide which registered services need restarting.
+func (s *BaseService) HealthCheck() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == StateFailed {
+		return s.err
+	}
+	return nil
+}
+
+// Stats returns BaseService's own fields ("state", "running", and
+// "error" when failed); embedders merge their own progress fields in
+// alongside these.
+func (s *BaseService) Stats() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := map[string]interface{}{
+		"state":   string(s.state),
+		"running": s.state == StateRunning,
+	}
+	if s.err != nil {
+		stats["error"] = s.err.Error()
+	}
+	return stats
+}
+
+func (s *BaseService) run(ctx context.Context) {
+	defer close(s.doneCh)
+	defer s.cancel()
+
+	if err := s.callOnStart(ctx); err != nil {
+		s.logger.WithComponent(s.name).WithError(err).Error("service failed to start")
+		s.stopWith(StateFailed, err)
+		s.impl.OnStop()
+		return
+	}
+
+	for {
+		if ctx.Err() != nil {
+			s.stopWith(StateStopped, nil)
+			s.impl.OnStop()
+			return
+		}
+
+		err := s.callOnLoop(ctx)
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, ErrServiceDone) || errors.Is(err, context.Canceled) {
+			s.stopWith(StateStopped, nil)
+		} else {
+			s.logger.WithComponent(s.name).WithError(err).Error("service failed")
+			s.stopWith(StateFailed, err)
+		}
+		s.impl.OnStop()
+		return
+	}
+}
+
+func (s *BaseService) callOnStart(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in OnStart: %v", r)
+		}
+	}()
+	return s.impl.OnStart(ctx)
+}
+
+func (s *BaseService) callOnLoop(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in OnLoop: %v", r)
+		}
+	}()
+	return s.impl.OnLoop(ctx)
+}
+
+func (s *BaseService) stopWith(state ServiceState, err error) {
+	s.mu.Lock()
+	s.state = state
+	s.err = err
+	s.mu.Unlock()
+}
+
+// Service is the minimal uniform surface ServiceRegistry and Supervisor
+// need to Start/Stop/Stats/HealthCheck a long-running component
+// generically -- Poller, VolumePoller, Backfiller, and GapFiller all
+// satisfy it, the last three via their BaseService-backed
+// Start/Stop/HealthCheck. Deliberately omits a Name() method: callers
+// already supply a name to Register, so a second source of truth for it
+// on the interface would be redundant.
+type Service interface {
+	Start()
+	Stop()
+	Stats() map[string]interface{}
+	HealthCheck() error
+}
+
+// ServiceRegistry lets the main binary Start/Stop a fixed set of
+// BaseService-backed components uniformly and read all of their Stats
+// together, instead of threading each one through by hand.
+type ServiceRegistry struct {
+	mu       sync.Mutex
+	services []namedService
+}
+
+type namedService struct {
+	name string
+	svc  Service
+}
+
+// NewServiceRegistry creates an empty ServiceRegistry.
+func NewServiceRegistry() *ServiceRegistry {
+	return &ServiceRegistry{}
+}
+
+// Register adds svc under name. Start/Stop/Stats operate on every
+// registered service in registration order.
+func (r *ServiceRegistry) Register(name string, svc Service) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.services = append(r.services, namedService{name: name, svc: svc})
+}
+
+// Start starts every registered service.
+func (r *ServiceRegistry) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ns := range r.services {
+		ns.svc.Start()
+	}
+}
+
+// Stop stops every registered service, in reverse registration order.
+func (r *ServiceRegistry) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.services) - 1; i >= 0; i-- {
+		r.services[i].svc.Stop()
+	}
+}
+
+// Stats returns every registered service's Stats, keyed by its
+// registered name.
+func (r *ServiceRegistry) Stats() map[string]map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make(map[string]map[string]interface{}, len(r.services))
+	for _, ns := range r.services {
+		stats[ns.name] = ns.svc.Stats()
+	}
+	return stats
+}
@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Rate-breaker tuning: if more than errorRateThreshold of the last
+// errorRateWindow API calls failed, halve the shared rate limiter's rate for
+// breakerCooldown, then let exactly one call through as a probe -- closing
+// (restoring the rate) on success, or starting another cooldown on failure.
+const (
+	errorRateWindow    = 50
+	errorRateThreshold = 0.2
+	breakerCooldown    = 30 * time.Second
+)
+
+// RateBreakerStatus reports BackgroundSync's adaptive rate limiting state,
+// for Progress() and metrics.
+type RateBreakerStatus struct {
+	EffectiveRate     float64
+	Open              bool
+	ConsecutiveErrors int
+}
+
+// rateBreakerState is the mutable bookkeeping behind RateBreakerStatus,
+// guarded by BackgroundSync.mu like the rest of BackgroundSync's state.
+type rateBreakerState struct {
+	baseRate          rate.Limit
+	outcomes          []bool // rolling window of recent call results, true = success
+	consecutiveErrors int
+	open              bool
+	openedAt          time.Time
+	probing           bool
+}
+
+// checkRateBreakerProbe marks the breaker as probing if it's open and its
+// cooldown has elapsed, so the next recordCallOutcome decides whether to
+// close it. Called before each API attempt.
+func (b *BackgroundSync) checkRateBreakerProbe() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rb := &b.breaker
+	if rb.open && !rb.probing && time.Since(rb.openedAt) >= breakerCooldown {
+		rb.probing = true
+	}
+}
+
+// recordCallOutcome updates the rolling error-rate window and opens/closes
+// the rate breaker in response, halving or restoring b.limiter's rate as
+// needed.
+func (b *BackgroundSync) recordCallOutcome(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rb := &b.breaker
+	if rb.baseRate == 0 {
+		rb.baseRate = b.limiter.Limit()
+	}
+
+	rb.outcomes = append(rb.outcomes, success)
+	if len(rb.outcomes) > errorRateWindow {
+		rb.outcomes = rb.outcomes[len(rb.outcomes)-errorRateWindow:]
+	}
+
+	if success {
+		rb.consecutiveErrors = 0
+	} else {
+		rb.consecutiveErrors++
+	}
+
+	switch {
+	case rb.open && rb.probing:
+		rb.probing = false
+		if success {
+			rb.open = false
+			rb.outcomes = nil
+			b.limiter.SetLimit(rb.baseRate)
+			b.logger.WithComponent("background_sync").Info("rate breaker closed after a successful probe")
+		} else {
+			rb.openedAt = time.Now()
+			b.logger.WithComponent("background_sync").Warn("rate breaker probe failed, reopening cooldown")
+		}
+
+	case rb.open:
+		// Still within cooldown; nothing to do until checkRateBreakerProbe
+		// flips probing.
+
+	default:
+		if len(rb.outcomes) >= errorRateWindow && failureRate(rb.outcomes) > errorRateThreshold {
+			rb.open = true
+			rb.openedAt = time.Now()
+			halved := b.limiter.Limit() / 2
+			b.limiter.SetLimit(halved)
+			b.logger.WithComponent("background_sync").WithField("new_rate", float64(halved)).Warn("rate breaker opened, halving API rate limit")
+		}
+	}
+}
+
+// RateBreakerStatus returns the current adaptive rate limiting state.
+func (b *BackgroundSync) RateBreakerStatus() RateBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rateBreakerStatusLocked()
+}
+
+// rateBreakerStatusLocked builds a RateBreakerStatus snapshot. Callers must
+// hold b.mu.
+func (b *BackgroundSync) rateBreakerStatusLocked() RateBreakerStatus {
+	return RateBreakerStatus{
+		EffectiveRate:     float64(b.limiter.Limit()),
+		Open:              b.breaker.open,
+		ConsecutiveErrors: b.breaker.consecutiveErrors,
+	}
+}
+
+func failureRate(outcomes []bool) float64 {
+	if len(outcomes) == 0 {
+		return 0
+	}
+	failures := 0
+	for _, ok := range outcomes {
+		if !ok {
+			failures++
+		}
+	}
+	return float64(failures) / float64(len(outcomes))
+}
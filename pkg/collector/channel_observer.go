@@ -0,0 +1,35 @@
+package collector
+
+import (
+	"context"
+	"time"
+)
+
+// ChannelObserver is a SyncObserver that republishes events on a Go channel,
+// for in-process consumers (e.g. flip detection) that want to select on new
+// data instead of polling. It's already dispatched through observerSub's
+// own single-goroutine queue, so Events only needs to be drained by one
+// reader.
+type ChannelObserver struct {
+	Events chan SyncEvent
+}
+
+// NewChannelObserver creates a ChannelObserver whose Events channel has the
+// given buffer size.
+func NewChannelObserver(bufferSize int) *ChannelObserver {
+	return &ChannelObserver{Events: make(chan SyncEvent, bufferSize)}
+}
+
+func (c *ChannelObserver) OnBucketsInserted(_ context.Context, bucketSize string, ts time.Time, buckets []PriceBucket) {
+	select {
+	case c.Events <- SyncEvent{Kind: "buckets_inserted", BucketSize: bucketSize, Timestamp: ts, Buckets: buckets}:
+	default:
+	}
+}
+
+func (c *ChannelObserver) OnCycleComplete(_ context.Context, progress BackgroundSyncProgress) {
+	select {
+	case c.Events <- SyncEvent{Kind: "cycle_complete", Progress: progress}:
+	default:
+	}
+}
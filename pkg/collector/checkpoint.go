@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"time"
+)
+
+// Checkpoint is BackgroundSync's resume point for one bucket size, persisted
+// via Repository.SaveCheckpoint so a restart doesn't have to re-derive
+// missing timestamps from scratch via GetMissingBucketTimestamps, which
+// re-scans the whole retention window.
+type Checkpoint struct {
+	BucketSize          string
+	LastSyncedAt        time.Time
+	CyclesSinceFullScan int
+}
+
+// fullScanInterval is how many cycles syncBucketSize goes between
+// checkpoint-based forward scans before falling back to a full
+// GetMissingBucketTimestamps scan, to catch drift (late-arriving data,
+// manual corrections) a pure forward scan would miss.
+const fullScanInterval = 10
+
+// bucketInterval is the fixed spacing between the OSRS wiki API's bucket
+// timestamps for bucketSize.
+func bucketInterval(bucketSize string) time.Duration {
+	switch bucketSize {
+	case "5m":
+		return 5 * time.Minute
+	case "1h":
+		return time.Hour
+	case "24h":
+		return 24 * time.Hour
+	default:
+		return 5 * time.Minute
+	}
+}
+
+// resolveTimestamps returns the timestamps syncBucketSize should attempt
+// this cycle for bucketSize, along with the checkpoint that was loaded (the
+// zero value if none existed yet) and whether a full scan was used. It
+// prefers a cheap forward scan from the persisted checkpoint, falling back
+// to GetMissingBucketTimestamps when there's no checkpoint yet or
+// fullScanInterval cycles have passed since the last one.
+func (b *BackgroundSync) resolveTimestamps(ctx context.Context, bucketSize string, retention time.Duration) ([]time.Time, Checkpoint, bool, error) {
+	cp, err := b.repo.GetCheckpoint(ctx, bucketSize)
+	if err != nil {
+		b.logger.WithComponent("background_sync").WithError(err).WithField("bucket_size", bucketSize).Warn("failed to load sync checkpoint, falling back to a full scan")
+		cp = nil
+	}
+
+	if cp == nil || cp.CyclesSinceFullScan >= fullScanInterval {
+		timestamps, err := b.repo.GetMissingBucketTimestamps(ctx, bucketSize, retention, b.config.MinItemThreshold, b.config.TimestampsPerCycle)
+		prior := Checkpoint{BucketSize: bucketSize}
+		if cp != nil {
+			prior = *cp
+		}
+		return timestamps, prior, true, err
+	}
+
+	return b.forwardScanTimestamps(ctx, bucketSize, cp), *cp, false, nil
+}
+
+// forwardScanTimestamps steps forward from cp.LastSyncedAt in bucketInterval
+// increments up to now, skipping any timestamp on the poison list, capped at
+// b.config.TimestampsPerCycle.
+func (b *BackgroundSync) forwardScanTimestamps(ctx context.Context, bucketSize string, cp *Checkpoint) []time.Time {
+	interval := bucketInterval(bucketSize)
+	now := time.Now().UTC()
+
+	timestamps := make([]time.Time, 0, b.config.TimestampsPerCycle)
+	for ts := cp.LastSyncedAt.Add(interval); !ts.After(now) && len(timestamps) < b.config.TimestampsPerCycle; ts = ts.Add(interval) {
+		poisoned, err := b.repo.IsPoisoned(ctx, bucketSize, ts)
+		if err != nil {
+			b.logger.WithComponent("background_sync").WithError(err).WithField("bucket_size", bucketSize).Warn("failed to check poison list, attempting timestamp anyway")
+		}
+		if poisoned {
+			continue
+		}
+		timestamps = append(timestamps, ts)
+	}
+	return timestamps
+}
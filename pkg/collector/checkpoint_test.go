@@ -0,0 +1,26 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketInterval(t *testing.T) {
+	tests := []struct {
+		bucketSize string
+		want       time.Duration
+	}{
+		{"5m", 5 * time.Minute},
+		{"1h", time.Hour},
+		{"24h", 24 * time.Hour},
+		{"unknown", 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bucketSize, func(t *testing.T) {
+			if got := bucketInterval(tt.bucketSize); got != tt.want {
+				t.Errorf("bucketInterval(%q) = %v, want %v", tt.bucketSize, got, tt.want)
+			}
+		})
+	}
+}
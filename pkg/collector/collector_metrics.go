@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"osrs-flipping/pkg/osrs"
+)
+
+// CollectorMetrics is the shared Prometheus registry and instrument set for
+// VolumePoller, Backfiller, and GapFiller -- unlike syncMetrics (metrics.go)
+// and repositoryMetrics (repository_metrics.go), which each type builds for
+// itself, this one is built once by cmd/collector and wired into all three
+// via SetMetrics, since several of its instruments (buckets inserted, API
+// rate-limit/failure state) are shared across them rather than owned by a
+// single type. A type left without SetMetrics just skips recording.
+type CollectorMetrics struct {
+	registry *prometheus.Registry
+
+	pollTotal                *prometheus.CounterVec
+	pollDuration             prometheus.Histogram
+	bucketsInserted          *prometheus.CounterVec
+	gapFillErrors            *prometheus.CounterVec
+	backfillProgressRatio    *prometheus.GaugeVec
+	apiRatelimitEffectiveRPS prometheus.Gauge
+	consecutiveFails         prometheus.Gauge
+}
+
+// NewCollectorMetrics creates a CollectorMetrics with its own registry, for
+// cmd/collector to build once and wire into VolumePoller, Backfiller, and
+// GapFiller via SetMetrics.
+func NewCollectorMetrics() *CollectorMetrics {
+	registry := prometheus.NewRegistry()
+
+	m := &CollectorMetrics{
+		registry: registry,
+		pollTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "collector_poll_total",
+			Help: "Number of VolumePoller.pollItem calls, by result (ok/error).",
+		}, []string{"result"}),
+		pollDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "collector_poll_duration_seconds",
+			Help:    "Duration of VolumePoller.pollItem calls.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		bucketsInserted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "collector_buckets_inserted_total",
+			Help: "Price buckets inserted by Backfiller and GapFiller, by bucket size and source.",
+		}, []string{"bucket_size", "source"}),
+		gapFillErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "collector_gap_fill_errors_total",
+			Help: "Errors from GapFiller.fillGapsForItem, by bucket size.",
+		}, []string{"bucket_size"}),
+		backfillProgressRatio: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "collector_backfill_progress_ratio",
+			Help: "Backfiller.backfillBucketSize's processed/total items ratio, by bucket size.",
+		}, []string{"bucket_size"}),
+		apiRatelimitEffectiveRPS: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "collector_api_ratelimit_effective_rps",
+			Help: "osrs.Client's current effective rate limit in requests/sec for the bucket size most recently processed (see RateLimiter.EffectiveRPS).",
+		}),
+		consecutiveFails: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "collector_consecutive_fails",
+			Help: "Consecutive failed items in the collector loop (poll, backfill, or gap fill) most recently active.",
+		}),
+	}
+
+	registry.MustRegister(m.pollTotal, m.pollDuration, m.bucketsInserted, m.gapFillErrors,
+		m.backfillProgressRatio, m.apiRatelimitEffectiveRPS, m.consecutiveFails)
+	return m
+}
+
+// NewMetricsHandler returns an http.Handler serving these metrics in the
+// Prometheus text exposition format.
+func (m *CollectorMetrics) NewMetricsHandler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RecordPoll records one VolumePoller.pollItem call's outcome and duration.
+func (m *CollectorMetrics) RecordPoll(err error, duration time.Duration) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.pollTotal.WithLabelValues(result).Inc()
+	m.pollDuration.Observe(duration.Seconds())
+}
+
+// RecordBucketsInserted adds count to the bucketSize/source series. A
+// no-op for count <= 0, so callers don't need to guard every call site.
+func (m *CollectorMetrics) RecordBucketsInserted(bucketSize, source string, count int64) {
+	if count <= 0 {
+		return
+	}
+	m.bucketsInserted.WithLabelValues(bucketSize, source).Add(float64(count))
+}
+
+// RecordGapFillError increments the gap-fill error count for bucketSize.
+func (m *CollectorMetrics) RecordGapFillError(bucketSize string) {
+	m.gapFillErrors.WithLabelValues(bucketSize).Inc()
+}
+
+// SetBackfillProgressRatio sets Backfiller's processed/total items ratio
+// for bucketSize (0..1).
+func (m *CollectorMetrics) SetBackfillProgressRatio(bucketSize string, ratio float64) {
+	m.backfillProgressRatio.WithLabelValues(bucketSize).Set(ratio)
+}
+
+// RecordClientStats pushes stats' effective RPS for bucketSize's
+// /timeseries endpoint, and the caller's own running consecutive-failure
+// count, into the shared rate-limit/failure gauges.
+func (m *CollectorMetrics) RecordClientStats(bucketSize string, stats osrs.ClientStats, consecutiveFails int) {
+	if rps, ok := stats.EffectiveRPS["/timeseries/"+bucketSize]; ok {
+		m.apiRatelimitEffectiveRPS.Set(rps)
+	}
+	m.consecutiveFails.Set(float64(consecutiveFails))
+}
@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// EventPublisher publishes price observations to external subscribers as
+// they're written, so a consumer (a Discord alert bot, a websocket
+// dashboard, a secondary computed-bucket worker) doesn't have to poll
+// Postgres for fresh data. Repository treats a nil EventPublisher as a
+// no-op.
+type EventPublisher interface {
+	PublishObservations(ctx context.Context, observations []PriceObservation) error
+}
+
+// PriceObservationEvent is the JSON payload published for a single
+// observation.
+type PriceObservationEvent struct {
+	ItemID     int        `json:"item_id"`
+	ObservedAt time.Time  `json:"observed_at"`
+	HighPrice  *int       `json:"high_price,omitempty"`
+	HighTime   *time.Time `json:"high_time,omitempty"`
+	LowPrice   *int       `json:"low_price,omitempty"`
+	LowTime    *time.Time `json:"low_time,omitempty"`
+}
+
+// priceSubject is the JetStream subject a given item's observations are
+// published/subscribed on.
+func priceSubject(itemID int) string {
+	return fmt.Sprintf("osrs.prices.%d", itemID)
+}
+
+// priceConsumerDurable names the durable consumer SubscribePrices' queue
+// subscribers share, so multiple replicas split the workload instead of
+// each receiving every message.
+const priceConsumerDurable = "osrs-price-consumers"
+
+// NATSPublisher publishes price observations to a NATS JetStream subject
+// per item, with the observation timestamp carried as a message header so
+// subscribers can filter/dedupe without parsing the body.
+type NATSPublisher struct {
+	js nats.JetStreamContext
+}
+
+// NewNATSPublisher wraps an already-connected JetStream context. Callers
+// own the underlying *nats.Conn's lifecycle.
+func NewNATSPublisher(js nats.JetStreamContext) *NATSPublisher {
+	return &NATSPublisher{js: js}
+}
+
+// PublishObservations publishes one JetStream message per observation.
+// Publish failures are collected and returned together so one bad message
+// doesn't stop the rest of the batch from going out.
+func (p *NATSPublisher) PublishObservations(ctx context.Context, observations []PriceObservation) error {
+	var errs []error
+	for _, obs := range observations {
+		payload, err := json.Marshal(PriceObservationEvent{
+			ItemID:     obs.ItemID,
+			ObservedAt: obs.ObservedAt,
+			HighPrice:  obs.HighPrice,
+			HighTime:   obs.HighTime,
+			LowPrice:   obs.LowPrice,
+			LowTime:    obs.LowTime,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("marshal observation for item %d: %w", obs.ItemID, err))
+			continue
+		}
+
+		msg := nats.NewMsg(priceSubject(obs.ItemID))
+		msg.Data = payload
+		msg.Header.Set("Observed-At", obs.ObservedAt.UTC().Format(time.RFC3339Nano))
+
+		if _, err := p.js.PublishMsg(msg, nats.Context(ctx)); err != nil {
+			errs = append(errs, fmt.Errorf("publish observation for item %d: %w", obs.ItemID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("publish %d/%d observations failed: %w", len(errs), len(observations), errors.Join(errs...))
+	}
+	return nil
+}
+
+// SubscribePrices creates a durable JetStream queue subscription per item
+// ID, shared across replicas via priceConsumerDurable so they split the
+// workload rather than each receiving every message. handler is invoked
+// with the decoded event; messages are acked only after handler returns
+// nil, so a crash or handler error redelivers the message (at-least-once
+// delivery) instead of silently dropping it.
+func SubscribePrices(js nats.JetStreamContext, itemIDs []int, handler func(PriceObservationEvent) error) ([]*nats.Subscription, error) {
+	subs := make([]*nats.Subscription, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		subject := priceSubject(itemID)
+		sub, err := js.QueueSubscribe(subject, priceConsumerDurable, func(msg *nats.Msg) {
+			var event PriceObservationEvent
+			if err := json.Unmarshal(msg.Data, &event); err != nil {
+				msg.Nak()
+				return
+			}
+			if err := handler(event); err != nil {
+				msg.Nak()
+				return
+			}
+			msg.Ack()
+		}, nats.Durable(priceConsumerDurable), nats.ManualAck())
+		if err != nil {
+			return subs, fmt.Errorf("subscribe to %s: %w", subject, err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
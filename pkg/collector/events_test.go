@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPriceSubject(t *testing.T) {
+	tests := []struct {
+		itemID int
+		want   string
+	}{
+		{4151, "osrs.prices.4151"},
+		{0, "osrs.prices.0"},
+	}
+
+	for _, tt := range tests {
+		if got := priceSubject(tt.itemID); got != tt.want {
+			t.Errorf("priceSubject(%d) = %q, want %q", tt.itemID, got, tt.want)
+		}
+	}
+}
+
+// fakePublisher is a minimal EventPublisher used to verify Repository wires
+// WithPublisher through correctly without requiring a live NATS connection.
+type fakePublisher struct {
+	published []PriceObservation
+}
+
+func (f *fakePublisher) PublishObservations(_ context.Context, observations []PriceObservation) error {
+	f.published = append(f.published, observations...)
+	return nil
+}
+
+func TestWithPublisher_SetsRepositoryPublisher(t *testing.T) {
+	pub := &fakePublisher{}
+	r := NewRepository(nil, WithPublisher(pub))
+
+	if r.publisher != pub {
+		t.Fatal("expected WithPublisher to set Repository.publisher")
+	}
+}
@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"container/heap"
 	"testing"
 	"time"
 )
@@ -32,10 +33,6 @@ func TestDefaultGapFillerConfig(t *testing.T) {
 		t.Errorf("ItemsPerRun = %d, want 150", cfg.ItemsPerRun)
 	}
 
-	if cfg.RateLimit != 100*time.Millisecond {
-		t.Errorf("RateLimit = %v, want 100ms", cfg.RateLimit)
-	}
-
 	if cfg.MaxConcurrent != 1 {
 		t.Errorf("MaxConcurrent = %d, want 1", cfg.MaxConcurrent)
 	}
@@ -78,3 +75,101 @@ func TestGapFillerProgress_Initial(t *testing.T) {
 		t.Errorf("BucketsFilled = %d, want 0", progress.BucketsFilled)
 	}
 }
+
+func TestBucketTimeInRanges(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ranges := []TimeRange{
+		{Start: base, End: base.Add(10 * time.Minute)},
+		{Start: base.Add(time.Hour), End: base.Add(time.Hour + 5*time.Minute)},
+	}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"at first range's start", base, true},
+		{"inside first range", base.Add(5 * time.Minute), true},
+		{"at first range's end (half-open, excluded)", base.Add(10 * time.Minute), false},
+		{"between ranges", base.Add(30 * time.Minute), false},
+		{"inside second range", base.Add(time.Hour + time.Minute), true},
+		{"before any range", base.Add(-time.Minute), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bucketTimeInRanges(tt.t, ranges); got != tt.want {
+				t.Errorf("bucketTimeInRanges(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+
+	if bucketTimeInRanges(base, nil) {
+		t.Error("bucketTimeInRanges with no ranges should always be false")
+	}
+}
+
+func TestGapFillHeap_PopsHighestScoreFirst(t *testing.T) {
+	queue := &gapFillHeap{}
+	heap.Init(queue)
+	for _, e := range []*gapFillEntry{
+		{itemID: 1, score: 3.5},
+		{itemID: 2, score: 10.0},
+		{itemID: 3, score: -1.0},
+		{itemID: 4, score: 7.2},
+	} {
+		heap.Push(queue, e)
+	}
+
+	var order []int
+	for queue.Len() > 0 {
+		order = append(order, heap.Pop(queue).(*gapFillEntry).itemID)
+	}
+
+	want := []int{2, 4, 1, 3}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("order = %v, want %v", order, want)
+			break
+		}
+	}
+}
+
+func TestGapFiller_RecordAndClearFailure(t *testing.T) {
+	gf := NewGapFiller(nil, nil, nil, nil)
+
+	if gf.gapFillBackoffActive(42) {
+		t.Error("item with no recorded failures should not be in backoff")
+	}
+
+	gf.recordGapFillFailure(42)
+	if !gf.gapFillBackoffActive(42) {
+		t.Error("item should be in backoff immediately after a recorded failure")
+	}
+
+	gf.clearGapFillFailure(42)
+	if gf.gapFillBackoffActive(42) {
+		t.Error("item should not be in backoff after clearGapFillFailure")
+	}
+}
+
+func TestGapFiller_RecordFailure_BackoffGrows(t *testing.T) {
+	gf := NewGapFiller(nil, nil, nil, nil)
+
+	gf.recordGapFillFailure(7)
+	gf.mu.Lock()
+	first := gf.failures[7].retryAfter
+	gf.mu.Unlock()
+
+	gf.recordGapFillFailure(7)
+	gf.mu.Lock()
+	second := gf.failures[7].retryAfter
+	gf.mu.Unlock()
+
+	if !second.After(first) {
+		t.Errorf("retryAfter should grow with consecutive failures: first=%v second=%v", first, second)
+	}
+}
@@ -0,0 +1,265 @@
+package collector
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"osrs-flipping/pkg/osrs"
+)
+
+// TimeseriesPoint is one bucket of historical price/volume data, shaped the
+// same whether it came from the live API (osrs.VolumeDataPoint) or a bulk
+// dump file, so Backfiller can convert either into a PriceBucket the same
+// way.
+type TimeseriesPoint struct {
+	Timestamp       time.Time
+	AvgHighPrice    *int
+	AvgLowPrice     *int
+	HighPriceVolume *int64
+	LowPriceVolume  *int64
+}
+
+// HistoricalSource supplies historical timeseries data for Backfiller, so
+// seeding a fresh database isn't hardcoded to one slow, rate-limited path.
+// apiHistoricalSource wraps the live API (the default, see NewBackfiller);
+// DumpSource reads a bulk CSV snapshot instead, letting an operator seed
+// thousands of items in minutes without touching the API's rate limit.
+type HistoricalSource interface {
+	// Name identifies the source for logging and PriceBucket.Source.
+	Name() string
+
+	// FetchTimeseries returns itemID's bucketSize-granularity points. A nil
+	// slice with a nil error means the source simply has no data for
+	// itemID -- not a failure -- so Backfiller can fall back to another
+	// source.
+	FetchTimeseries(ctx context.Context, itemID int, bucketSize string) ([]TimeseriesPoint, error)
+
+	// BulkFetch returns every item this source has on hand for bucketSize,
+	// keyed by item ID, in one pass. The bool reports whether the source
+	// supports bulk enumeration at all: the live API can't (per-item
+	// pagination is all it offers), so apiHistoricalSource always returns
+	// (nil, false, nil) and callers fall back to per-item FetchTimeseries.
+	BulkFetch(ctx context.Context, bucketSize string) (map[int][]TimeseriesPoint, bool, error)
+}
+
+// apiHistoricalSource adapts osrs.Client.GetTimeseriesTyped to
+// HistoricalSource, preserving Backfiller's original behavior as the
+// default source.
+type apiHistoricalSource struct {
+	client *osrs.Client
+}
+
+// newAPIHistoricalSource wraps client as the API-backed HistoricalSource.
+func newAPIHistoricalSource(client *osrs.Client) *apiHistoricalSource {
+	return &apiHistoricalSource{client: client}
+}
+
+func (s *apiHistoricalSource) Name() string {
+	return "api"
+}
+
+func (s *apiHistoricalSource) FetchTimeseries(ctx context.Context, itemID int, bucketSize string) ([]TimeseriesPoint, error) {
+	resp, err := s.client.GetTimeseriesTyped(ctx, itemID, bucketSize)
+	if err != nil {
+		return nil, err
+	}
+	return volumeDataPointsToTimeseries(resp.Data), nil
+}
+
+// BulkFetch always reports unsupported: the API offers no way to enumerate
+// every item's timeseries in one request, only per-item pagination.
+func (s *apiHistoricalSource) BulkFetch(ctx context.Context, bucketSize string) (map[int][]TimeseriesPoint, bool, error) {
+	return nil, false, nil
+}
+
+func volumeDataPointsToTimeseries(data []osrs.VolumeDataPoint) []TimeseriesPoint {
+	points := make([]TimeseriesPoint, 0, len(data))
+	for _, dp := range data {
+		p := TimeseriesPoint{
+			Timestamp:    time.Unix(dp.Timestamp, 0).UTC(),
+			AvgHighPrice: dp.AvgHighPrice,
+			AvgLowPrice:  dp.AvgLowPrice,
+		}
+		if dp.HighPriceVol != nil {
+			v := int64(*dp.HighPriceVol)
+			p.HighPriceVolume = &v
+		}
+		if dp.LowPriceVol != nil {
+			v := int64(*dp.LowPriceVol)
+			p.LowPriceVolume = &v
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// DumpSource reads historical timeseries data from gzipped CSV snapshots on
+// local disk -- an S3-compatible URL can be seeded into the same layout with
+// any standard sync tool (e.g. `aws s3 sync`/`rclone`); DumpSource itself
+// only ever reads a local directory. Files are laid out
+// <Dir>/<bucketSize>/<itemID>.csv.gz, one file per item, each a
+// headerless CSV of
+// timestamp,avg_high_price,avg_low_price,high_price_volume,low_price_volume
+// rows (an empty field means NULL, not zero). Parquet is not implemented:
+// this tree has no go.mod/vendored dependencies to pull in a Parquet
+// reader, so only the CSV layout is supported today.
+type DumpSource struct {
+	Dir string
+}
+
+// NewDumpSource creates a DumpSource reading from dir (see DumpSource's doc
+// comment for the expected layout).
+func NewDumpSource(dir string) *DumpSource {
+	return &DumpSource{Dir: dir}
+}
+
+func (s *DumpSource) Name() string {
+	return "dump"
+}
+
+func (s *DumpSource) FetchTimeseries(ctx context.Context, itemID int, bucketSize string) ([]TimeseriesPoint, error) {
+	path := s.itemPath(itemID, bucketSize)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening dump file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	return readDumpCSV(f)
+}
+
+// BulkFetch reads every <itemID>.csv.gz file under <Dir>/<bucketSize>,
+// returning the whole bucketSize snapshot in one pass. A missing directory
+// is treated as an empty dump rather than an error, so a source configured
+// for a bucket size that hasn't been snapshotted yet just falls back to
+// per-item fetches for everything.
+func (s *DumpSource) BulkFetch(ctx context.Context, bucketSize string) (map[int][]TimeseriesPoint, bool, error) {
+	dir := filepath.Join(s.Dir, bucketSize)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[int][]TimeseriesPoint{}, true, nil
+		}
+		return nil, true, fmt.Errorf("reading dump directory %s: %w", dir, err)
+	}
+
+	result := make(map[int][]TimeseriesPoint, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		itemID, ok := itemIDFromDumpFilename(entry.Name())
+		if !ok {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		default:
+		}
+
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, true, fmt.Errorf("opening dump file %s: %w", entry.Name(), err)
+		}
+		points, err := readDumpCSV(f)
+		f.Close()
+		if err != nil {
+			return nil, true, fmt.Errorf("reading dump file %s: %w", entry.Name(), err)
+		}
+		result[itemID] = points
+	}
+	return result, true, nil
+}
+
+func (s *DumpSource) itemPath(itemID int, bucketSize string) string {
+	return filepath.Join(s.Dir, bucketSize, fmt.Sprintf("%d.csv.gz", itemID))
+}
+
+// itemIDFromDumpFilename extracts the item ID from a "<itemID>.csv.gz"
+// filename, as produced by itemPath.
+func itemIDFromDumpFilename(name string) (int, bool) {
+	const suffix = ".csv.gz"
+	if !strings.HasSuffix(name, suffix) {
+		return 0, false
+	}
+	id, err := strconv.Atoi(strings.TrimSuffix(name, suffix))
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// readDumpCSV parses a gzipped, headerless CSV of
+// timestamp,avg_high_price,avg_low_price,high_price_volume,low_price_volume
+// rows into TimeseriesPoints (see DumpSource's doc comment).
+func readDumpCSV(r io.Reader) ([]TimeseriesPoint, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("opening gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	reader := csv.NewReader(bufio.NewReader(gz))
+	reader.FieldsPerRecord = 5
+
+	var points []TimeseriesPoint
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing dump CSV: %w", err)
+		}
+
+		ts, err := strconv.ParseInt(record[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing timestamp %q: %w", record[0], err)
+		}
+
+		point := TimeseriesPoint{Timestamp: time.Unix(ts, 0).UTC()}
+		if v, ok := parseDumpIntField(record[1]); ok {
+			point.AvgHighPrice = &v
+		}
+		if v, ok := parseDumpIntField(record[2]); ok {
+			point.AvgLowPrice = &v
+		}
+		if v, ok := parseDumpIntField(record[3]); ok {
+			v64 := int64(v)
+			point.HighPriceVolume = &v64
+		}
+		if v, ok := parseDumpIntField(record[4]); ok {
+			v64 := int64(v)
+			point.LowPriceVolume = &v64
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// parseDumpIntField parses field as an int, treating an empty string as
+// "no value" (ok=false) rather than an error.
+func parseDumpIntField(field string) (int, bool) {
+	if field == "" {
+		return 0, false
+	}
+	v, err := strconv.Atoi(field)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
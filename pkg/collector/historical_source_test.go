@@ -0,0 +1,142 @@
+package collector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"osrs-flipping/pkg/osrs"
+)
+
+func TestItemIDFromDumpFilename(t *testing.T) {
+	if id, ok := itemIDFromDumpFilename("4151.csv.gz"); !ok || id != 4151 {
+		t.Errorf("itemIDFromDumpFilename(4151.csv.gz) = (%d, %v), want (4151, true)", id, ok)
+	}
+	if _, ok := itemIDFromDumpFilename("notes.txt"); ok {
+		t.Error("itemIDFromDumpFilename(notes.txt) should reject a non-.csv.gz name")
+	}
+	if _, ok := itemIDFromDumpFilename("abc.csv.gz"); ok {
+		t.Error("itemIDFromDumpFilename(abc.csv.gz) should reject a non-numeric item ID")
+	}
+}
+
+func TestParseDumpIntField(t *testing.T) {
+	if v, ok := parseDumpIntField("123"); !ok || v != 123 {
+		t.Errorf("parseDumpIntField(123) = (%d, %v), want (123, true)", v, ok)
+	}
+	if _, ok := parseDumpIntField(""); ok {
+		t.Error("parseDumpIntField(\"\") should report ok=false, not zero")
+	}
+	if _, ok := parseDumpIntField("not-a-number"); ok {
+		t.Error("parseDumpIntField(not-a-number) should report ok=false")
+	}
+}
+
+func writeGzipCSV(t *testing.T, path, csv string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(csv)); err != nil {
+		t.Fatalf("writing gzip fixture: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("closing gzip fixture: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("writing fixture file: %v", err)
+	}
+}
+
+func TestDumpSource_FetchTimeseries(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "5m"), 0o755)
+	writeGzipCSV(t, filepath.Join(dir, "5m", "4151.csv.gz"), "1700000000,100,90,10,20\n1700000300,,95,,5\n")
+
+	s := NewDumpSource(dir)
+	points, err := s.FetchTimeseries(context.Background(), 4151, "5m")
+	if err != nil {
+		t.Fatalf("FetchTimeseries failed: %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("len(points) = %d, want 2", len(points))
+	}
+	if *points[0].AvgHighPrice != 100 || *points[0].AvgLowPrice != 90 {
+		t.Errorf("points[0] prices = %d/%d, want 100/90", *points[0].AvgHighPrice, *points[0].AvgLowPrice)
+	}
+	if points[1].AvgHighPrice != nil {
+		t.Errorf("points[1].AvgHighPrice = %v, want nil for an empty CSV field", *points[1].AvgHighPrice)
+	}
+}
+
+func TestDumpSource_FetchTimeseries_MissingFile(t *testing.T) {
+	s := NewDumpSource(t.TempDir())
+	points, err := s.FetchTimeseries(context.Background(), 9999, "5m")
+	if err != nil {
+		t.Errorf("FetchTimeseries for a missing file = %v, want nil error (missing data, not a failure)", err)
+	}
+	if points != nil {
+		t.Errorf("FetchTimeseries for a missing file = %v, want nil", points)
+	}
+}
+
+func TestDumpSource_BulkFetch(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "5m"), 0o755)
+	writeGzipCSV(t, filepath.Join(dir, "5m", "1.csv.gz"), "1700000000,100,90,10,20\n")
+	writeGzipCSV(t, filepath.Join(dir, "5m", "2.csv.gz"), "1700000000,200,190,10,20\n")
+
+	s := NewDumpSource(dir)
+	bulk, ok, err := s.BulkFetch(context.Background(), "5m")
+	if err != nil {
+		t.Fatalf("BulkFetch failed: %v", err)
+	}
+	if !ok {
+		t.Fatal("BulkFetch ok = false, want true (DumpSource always supports bulk enumeration)")
+	}
+	if len(bulk) != 2 || len(bulk[1]) != 1 || len(bulk[2]) != 1 {
+		t.Errorf("bulk = %+v, want one point each for items 1 and 2", bulk)
+	}
+}
+
+func TestDumpSource_BulkFetch_MissingDir(t *testing.T) {
+	s := NewDumpSource(t.TempDir())
+	bulk, ok, err := s.BulkFetch(context.Background(), "24h")
+	if err != nil || !ok {
+		t.Fatalf("BulkFetch for an un-snapshotted bucket size = (%v, %v, %v), want (empty map, true, nil)", bulk, ok, err)
+	}
+	if len(bulk) != 0 {
+		t.Errorf("bulk = %+v, want empty", bulk)
+	}
+}
+
+func TestAPIHistoricalSource_BulkFetchUnsupported(t *testing.T) {
+	s := newAPIHistoricalSource(nil)
+	bulk, ok, err := s.BulkFetch(context.Background(), "5m")
+	if bulk != nil || ok || err != nil {
+		t.Errorf("BulkFetch = (%v, %v, %v), want (nil, false, nil)", bulk, ok, err)
+	}
+	if s.Name() != "api" {
+		t.Errorf("Name() = %q, want \"api\"", s.Name())
+	}
+}
+
+func TestVolumeDataPointsToTimeseries(t *testing.T) {
+	high, low, highVol := 100, 90, 10
+	data := []osrs.VolumeDataPoint{
+		{Timestamp: 1700000000, AvgHighPrice: &high, AvgLowPrice: &low, HighPriceVol: &highVol},
+	}
+
+	points := volumeDataPointsToTimeseries(data)
+	if len(points) != 1 {
+		t.Fatalf("len(points) = %d, want 1", len(points))
+	}
+	if *points[0].AvgHighPrice != 100 || *points[0].HighPriceVolume != 10 {
+		t.Errorf("points[0] = %+v, want AvgHighPrice=100 HighPriceVolume=10", points[0])
+	}
+	if points[0].LowPriceVolume != nil {
+		t.Errorf("points[0].LowPriceVolume = %v, want nil (source had no LowPriceVol)", *points[0].LowPriceVolume)
+	}
+}
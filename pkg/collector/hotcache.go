@@ -0,0 +1,104 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultHotCacheCapacity is how many distinct bucket_start timestamps each
+// bucket size's hot cache retains by default, e.g. 32 "5m" timestamps is
+// about 2h40m of hot data.
+var defaultHotCacheCapacity = map[string]int{
+	"5m":  32,
+	"1h":  24,
+	"24h": 7,
+}
+
+// hotCacheKey identifies one cached bucket row.
+type hotCacheKey struct {
+	itemID      int
+	bucketSize  string
+	bucketStart time.Time
+}
+
+// hotCache is a bounded, strictly-FIFO cache of recently-inserted
+// PriceBuckets, keyed by (itemID, bucketSize, bucketStart). It exists to
+// offload the read-heavy "current price" queries Repository.GetRecentBuckets
+// serves during flip discovery. Eviction drops the oldest bucket_start
+// timestamp (and every item's row for it) once more than capacity distinct
+// timestamps have been cached, which keeps the implementation lock-cheap at
+// the cost of evicting in whole-timestamp batches rather than per key.
+type hotCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    []time.Time // distinct bucket_start values, oldest first
+	seen     map[time.Time]struct{}
+	entries  map[hotCacheKey]PriceBucket
+}
+
+func newHotCache(capacity int) *hotCache {
+	return &hotCache{
+		capacity: capacity,
+		seen:     make(map[time.Time]struct{}),
+		entries:  make(map[hotCacheKey]PriceBucket),
+	}
+}
+
+// put caches buckets, all of which must share bucketSize and ts, evicting
+// the oldest cached timestamp if this pushes the cache past capacity.
+func (c *hotCache) put(bucketSize string, ts time.Time, buckets []PriceBucket) {
+	if c.capacity <= 0 || len(buckets) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.seen[ts]; !ok {
+		c.order = append(c.order, ts)
+		c.seen[ts] = struct{}{}
+
+		for len(c.order) > c.capacity {
+			oldest := c.order[0]
+			c.order = c.order[1:]
+			delete(c.seen, oldest)
+			for k := range c.entries {
+				if k.bucketSize == bucketSize && k.bucketStart.Equal(oldest) {
+					delete(c.entries, k)
+				}
+			}
+		}
+	}
+
+	for _, b := range buckets {
+		c.entries[hotCacheKey{itemID: b.ItemID, bucketSize: bucketSize, bucketStart: ts}] = b
+	}
+}
+
+// getRecent returns up to limit of itemID's most recent cached buckets for
+// bucketSize, newest first, reporting false if the cache can't guarantee
+// completeness (it may be missing rows for timestamps it has already
+// evicted).
+func (c *hotCache) getRecent(itemID int, bucketSize string, limit int) ([]PriceBucket, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if limit <= 0 || len(c.order) == 0 {
+		return nil, false
+	}
+
+	result := make([]PriceBucket, 0, limit)
+	for i := len(c.order) - 1; i >= 0 && len(result) < limit; i-- {
+		if b, ok := c.entries[hotCacheKey{itemID: itemID, bucketSize: bucketSize, bucketStart: c.order[i]}]; ok {
+			result = append(result, b)
+		}
+	}
+
+	// A short result is only trustworthy if the cache hasn't evicted
+	// anything yet -- otherwise the item may have older rows we no longer
+	// hold, and the caller must fall back to the database.
+	if len(result) < limit && len(c.order) >= c.capacity {
+		return nil, false
+	}
+	return result, true
+}
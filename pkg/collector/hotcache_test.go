@@ -0,0 +1,94 @@
+package collector
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHotCache_PutAndGetRecent(t *testing.T) {
+	c := newHotCache(2)
+	base := time.Unix(1700000000, 0).UTC()
+
+	c.put("5m", base, []PriceBucket{{ItemID: 2, BucketStart: base}})
+	c.put("5m", base.Add(5*time.Minute), []PriceBucket{{ItemID: 2, BucketStart: base.Add(5 * time.Minute)}})
+
+	got, hit := c.getRecent(2, "5m", 2)
+	if !hit {
+		t.Fatal("expected a cache hit")
+	}
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2", len(got))
+	}
+	if !got[0].BucketStart.Equal(base.Add(5 * time.Minute)) {
+		t.Errorf("got[0].BucketStart = %v, want the newest timestamp first", got[0].BucketStart)
+	}
+}
+
+func TestHotCache_EvictsOldestTimestamp(t *testing.T) {
+	c := newHotCache(1)
+	base := time.Unix(1700000000, 0).UTC()
+
+	c.put("5m", base, []PriceBucket{{ItemID: 2, BucketStart: base}})
+	c.put("5m", base.Add(5*time.Minute), []PriceBucket{{ItemID: 2, BucketStart: base.Add(5 * time.Minute)}})
+
+	// Capacity 1 means the first timestamp should have been evicted, so a
+	// 2-row request can no longer be answered from cache alone.
+	if _, hit := c.getRecent(2, "5m", 2); hit {
+		t.Error("expected a miss once the cache can no longer guarantee completeness")
+	}
+
+	got, hit := c.getRecent(2, "5m", 1)
+	if !hit || len(got) != 1 || !got[0].BucketStart.Equal(base.Add(5*time.Minute)) {
+		t.Errorf("getRecent(limit=1) = %v, hit=%v, want the surviving newest row", got, hit)
+	}
+}
+
+func TestHotCache_MissForUncachedItem(t *testing.T) {
+	c := newHotCache(4)
+	base := time.Unix(1700000000, 0).UTC()
+	c.put("5m", base, []PriceBucket{{ItemID: 2, BucketStart: base}})
+
+	// Cache isn't full, so a miss for a different item is authoritative --
+	// not a dropped row, the item just isn't in any cached timestamp.
+	got, hit := c.getRecent(999, "5m", 1)
+	if !hit || len(got) != 0 {
+		t.Errorf("getRecent for an absent item = %v, hit=%v, want hit=true with no rows", got, hit)
+	}
+}
+
+func TestHotCache_ConcurrentPutAndGet(t *testing.T) {
+	c := newHotCache(32)
+	base := time.Unix(1700000000, 0).UTC()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ts := base.Add(time.Duration(i) * 5 * time.Minute)
+			c.put("5m", ts, []PriceBucket{{ItemID: 2, BucketStart: ts}})
+			c.getRecent(2, "5m", 5)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// BenchmarkHotCache_GetRecent measures the cached read path under concurrent
+// load. A from-database comparison requires a live Postgres instance and
+// belongs in integration tests (see the Note on GetRecentBuckets), not here.
+func BenchmarkHotCache_GetRecent(b *testing.B) {
+	c := newHotCache(32)
+	base := time.Unix(1700000000, 0).UTC()
+	for i := 0; i < 32; i++ {
+		ts := base.Add(time.Duration(i) * 5 * time.Minute)
+		c.put("5m", ts, []PriceBucket{{ItemID: 2, BucketStart: ts}})
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.getRecent(2, "5m", 10)
+		}
+	})
+}
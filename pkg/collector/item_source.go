@@ -0,0 +1,100 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"osrs-flipping/pkg/osrs"
+)
+
+// ItemSource supplies the item catalog that ItemSyncer upserts into an
+// ItemStore. *osrs.Client satisfies this directly; FileItemSource and
+// MockItemSource exist so the syncer can run without hitting the live OSRS
+// Wiki API, for offline development and deterministic tests.
+type ItemSource interface {
+	// GetItemMapping fetches the item catalog. ifNoneMatch/ifModifiedSince
+	// are the ETag/Last-Modified values the caller last saw (either may be
+	// empty to force an unconditional fetch); notModified reports that the
+	// source's data hasn't changed since then, in which case mappings is
+	// nil and the caller should keep using what it already has.
+	GetItemMapping(ctx context.Context, ifNoneMatch, ifModifiedSince string) (mappings []osrs.ItemMapping, etag, lastModified string, notModified bool, err error)
+}
+
+// FileItemSource reads a JSON item mapping (the same shape as the OSRS Wiki
+// API's /mapping response) from a local file, so the collector can run
+// against a fixed catalog without network access.
+type FileItemSource struct {
+	Path string
+}
+
+// NewFileItemSource creates a FileItemSource reading from path.
+func NewFileItemSource(path string) *FileItemSource {
+	return &FileItemSource{Path: path}
+}
+
+// GetItemMapping reads and parses Path on every call, mirroring
+// *osrs.Client's fetch-on-demand behavior. A local file read is cheap
+// enough that conditional-GET caching isn't worth it: ifNoneMatch and
+// ifModifiedSince are ignored, and notModified is always false.
+func (f *FileItemSource) GetItemMapping(ctx context.Context, ifNoneMatch, ifModifiedSince string) ([]osrs.ItemMapping, string, string, bool, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return nil, "", "", false, fmt.Errorf("read item source file %s: %w", f.Path, err)
+	}
+
+	var mappings []osrs.ItemMapping
+	if err := json.Unmarshal(data, &mappings); err != nil {
+		return nil, "", "", false, fmt.Errorf("parse item source file %s: %w", f.Path, err)
+	}
+	return mappings, "", "", false, nil
+}
+
+// NewItemSourceFromKind builds the ItemSource named by kind ("wiki", "file",
+// or "mock"; "" defaults to "wiki"). client is used for "wiki"; path is used
+// for "file" (and ignored otherwise). "mock" returns an empty
+// MockItemSource, since a mock's mappings are normally set directly in Go
+// rather than selected via config.
+func NewItemSourceFromKind(kind, path string, client *osrs.Client) (ItemSource, error) {
+	switch kind {
+	case "", "wiki":
+		return client, nil
+	case "file":
+		return NewFileItemSource(path), nil
+	case "mock":
+		return NewMockItemSource(nil), nil
+	default:
+		return nil, fmt.Errorf("unknown item source %q", kind)
+	}
+}
+
+// MockItemSource returns a fixed, in-memory set of mappings. It exists for
+// tests that need a deterministic ItemSource without touching the
+// filesystem or network, including exercising ItemSyncer's conditional-GET
+// caching via NotModified.
+type MockItemSource struct {
+	Mappings     []osrs.ItemMapping
+	ETag         string
+	LastModified string
+	NotModified  bool
+	Err          error
+}
+
+// NewMockItemSource creates a MockItemSource that always returns mappings.
+func NewMockItemSource(mappings []osrs.ItemMapping) *MockItemSource {
+	return &MockItemSource{Mappings: mappings}
+}
+
+// GetItemMapping returns m.Mappings, or m.Err if set, or reports a cache
+// hit if m.NotModified is set. ifNoneMatch and ifModifiedSince are ignored;
+// set m.NotModified directly to simulate a 304.
+func (m *MockItemSource) GetItemMapping(ctx context.Context, ifNoneMatch, ifModifiedSince string) ([]osrs.ItemMapping, string, string, bool, error) {
+	if m.Err != nil {
+		return nil, "", "", false, m.Err
+	}
+	if m.NotModified {
+		return nil, m.ETag, m.LastModified, true, nil
+	}
+	return m.Mappings, m.ETag, m.LastModified, false, nil
+}
@@ -0,0 +1,99 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"osrs-flipping/pkg/osrs"
+)
+
+func TestFileItemSource_GetItemMapping(t *testing.T) {
+	mappings := []osrs.ItemMapping{{ID: 2, Name: "Cannonball"}}
+	data, err := json.Marshal(mappings)
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "items.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	source := NewFileItemSource(path)
+	got, _, _, notModified, err := source.GetItemMapping(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("GetItemMapping: %v", err)
+	}
+	if notModified {
+		t.Error("expected FileItemSource to never report a cache hit")
+	}
+	if len(got) != 1 || got[0].Name != "Cannonball" {
+		t.Errorf("expected [Cannonball], got %v", got)
+	}
+}
+
+func TestFileItemSource_MissingFile(t *testing.T) {
+	source := NewFileItemSource(filepath.Join(t.TempDir(), "missing.json"))
+	if _, _, _, _, err := source.GetItemMapping(context.Background(), "", ""); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestMockItemSource(t *testing.T) {
+	mappings := []osrs.ItemMapping{{ID: 1, Name: "Coins"}}
+	source := NewMockItemSource(mappings)
+
+	got, _, _, notModified, err := source.GetItemMapping(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("GetItemMapping: %v", err)
+	}
+	if notModified {
+		t.Error("expected a fresh MockItemSource to not report a cache hit")
+	}
+	if len(got) != 1 || got[0].Name != "Coins" {
+		t.Errorf("expected [Coins], got %v", got)
+	}
+}
+
+func TestMockItemSource_NotModified(t *testing.T) {
+	source := &MockItemSource{NotModified: true, ETag: `"abc"`}
+
+	mappings, etag, _, notModified, err := source.GetItemMapping(context.Background(), `"abc"`, "")
+	if err != nil {
+		t.Fatalf("GetItemMapping: %v", err)
+	}
+	if !notModified {
+		t.Error("expected notModified to be true")
+	}
+	if mappings != nil {
+		t.Errorf("expected nil mappings on a cache hit, got %v", mappings)
+	}
+	if etag != `"abc"` {
+		t.Errorf("expected etag %q, got %q", `"abc"`, etag)
+	}
+}
+
+func TestNewItemSourceFromKind(t *testing.T) {
+	if _, err := NewItemSourceFromKind("bogus", "", nil); err == nil {
+		t.Error("expected an error for an unknown item source kind")
+	}
+
+	source, err := NewItemSourceFromKind("mock", "", nil)
+	if err != nil {
+		t.Fatalf("NewItemSourceFromKind(mock): %v", err)
+	}
+	if _, ok := source.(*MockItemSource); !ok {
+		t.Errorf("expected a *MockItemSource, got %T", source)
+	}
+
+	source, err = NewItemSourceFromKind("file", "/tmp/items.json", nil)
+	if err != nil {
+		t.Fatalf("NewItemSourceFromKind(file): %v", err)
+	}
+	if _, ok := source.(*FileItemSource); !ok {
+		t.Errorf("expected a *FileItemSource, got %T", source)
+	}
+}
@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"osrs-flipping/pkg/osrs"
+)
+
+// ItemStore persists the item catalog ItemSyncer fetches from an
+// ItemSource. *Repository satisfies this directly against Postgres;
+// MemoryItemStore exists for CI and small deployments that don't want a
+// database just to hold static item metadata.
+type ItemStore interface {
+	UpsertItems(ctx context.Context, mappings []osrs.ItemMapping) (int64, error)
+
+	// GetSyncMetadata and SetSyncMetadata persist small sync bookkeeping
+	// (the ETag/Last-Modified pair from the last successful item sync)
+	// alongside the catalog itself, so ItemSyncer can send conditional-GET
+	// headers on its next run. GetSyncMetadata's ok is false when key has
+	// never been set.
+	GetSyncMetadata(ctx context.Context, key string) (value string, ok bool, err error)
+	SetSyncMetadata(ctx context.Context, key, value string) error
+}
+
+// NewItemStoreFromKind builds the ItemStore named by kind ("sql" or
+// "memory"; "" defaults to "sql"). repo is used for "sql" and ignored
+// otherwise.
+func NewItemStoreFromKind(kind string, repo *Repository) (ItemStore, error) {
+	switch kind {
+	case "", "sql":
+		return repo, nil
+	case "memory":
+		return NewMemoryItemStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown item store %q", kind)
+	}
+}
+
+// MemoryItemStore is an in-memory ItemStore keyed by item ID. It is safe
+// for concurrent use. Its sync metadata is held in memory alongside the
+// catalog, so -- like the catalog itself -- it doesn't survive a process
+// restart; that's an acceptable tradeoff for the CI/small-deployment use
+// case MemoryItemStore targets, where a cold restart paying for one
+// unconditional sync is cheap.
+type MemoryItemStore struct {
+	mu       sync.RWMutex
+	items    map[int]osrs.ItemMapping
+	metadata map[string]string
+}
+
+// NewMemoryItemStore creates an empty MemoryItemStore.
+func NewMemoryItemStore() *MemoryItemStore {
+	return &MemoryItemStore{
+		items:    make(map[int]osrs.ItemMapping),
+		metadata: make(map[string]string),
+	}
+}
+
+// UpsertItems inserts or replaces mappings by ID, returning the number
+// written, matching Repository.UpsertItems' return value.
+func (m *MemoryItemStore) UpsertItems(ctx context.Context, mappings []osrs.ItemMapping) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, item := range mappings {
+		m.items[item.ID] = item
+	}
+	return int64(len(mappings)), nil
+}
+
+// Get returns the stored mapping for itemID, if any.
+func (m *MemoryItemStore) Get(itemID int) (osrs.ItemMapping, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	item, ok := m.items[itemID]
+	return item, ok
+}
+
+// Len returns the number of items currently stored.
+func (m *MemoryItemStore) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.items)
+}
+
+// GetSyncMetadata returns key's stored value, if any.
+func (m *MemoryItemStore) GetSyncMetadata(ctx context.Context, key string) (string, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	value, ok := m.metadata[key]
+	return value, ok, nil
+}
+
+// SetSyncMetadata stores value under key, replacing any previous value.
+func (m *MemoryItemStore) SetSyncMetadata(ctx context.Context, key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.metadata[key] = value
+	return nil
+}
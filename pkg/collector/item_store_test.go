@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"osrs-flipping/pkg/osrs"
+)
+
+func TestMemoryItemStore_UpsertItems(t *testing.T) {
+	store := NewMemoryItemStore()
+
+	affected, err := store.UpsertItems(context.Background(), []osrs.ItemMapping{
+		{ID: 1, Name: "Coins"},
+		{ID: 2, Name: "Cannonball"},
+	})
+	if err != nil {
+		t.Fatalf("UpsertItems: %v", err)
+	}
+	if affected != 2 {
+		t.Errorf("expected 2 rows affected, got %d", affected)
+	}
+	if store.Len() != 2 {
+		t.Errorf("expected 2 items stored, got %d", store.Len())
+	}
+
+	item, ok := store.Get(1)
+	if !ok || item.Name != "Coins" {
+		t.Errorf("expected item 1 to be Coins, got %+v (ok=%v)", item, ok)
+	}
+
+	// Re-upserting the same ID replaces rather than duplicates.
+	if _, err := store.UpsertItems(context.Background(), []osrs.ItemMapping{{ID: 1, Name: "Gold coins"}}); err != nil {
+		t.Fatalf("UpsertItems (update): %v", err)
+	}
+	if store.Len() != 2 {
+		t.Errorf("expected 2 items after update, got %d", store.Len())
+	}
+	item, _ = store.Get(1)
+	if item.Name != "Gold coins" {
+		t.Errorf("expected item 1 to be updated to Gold coins, got %q", item.Name)
+	}
+}
+
+func TestMemoryItemStore_SyncMetadata(t *testing.T) {
+	store := NewMemoryItemStore()
+
+	if _, ok, err := store.GetSyncMetadata(context.Background(), "etag"); err != nil || ok {
+		t.Errorf("expected no value for an unset key, got ok=%v err=%v", ok, err)
+	}
+
+	if err := store.SetSyncMetadata(context.Background(), "etag", `"v1"`); err != nil {
+		t.Fatalf("SetSyncMetadata: %v", err)
+	}
+	value, ok, err := store.GetSyncMetadata(context.Background(), "etag")
+	if err != nil || !ok || value != `"v1"` {
+		t.Errorf("expected %q, got %q (ok=%v err=%v)", `"v1"`, value, ok, err)
+	}
+
+	if err := store.SetSyncMetadata(context.Background(), "etag", `"v2"`); err != nil {
+		t.Fatalf("SetSyncMetadata (update): %v", err)
+	}
+	value, _, _ = store.GetSyncMetadata(context.Background(), "etag")
+	if value != `"v2"` {
+		t.Errorf("expected the value to be replaced with %q, got %q", `"v2"`, value)
+	}
+}
+
+func TestNewItemStoreFromKind(t *testing.T) {
+	if _, err := NewItemStoreFromKind("bogus", nil); err == nil {
+		t.Error("expected an error for an unknown item store kind")
+	}
+
+	store, err := NewItemStoreFromKind("memory", nil)
+	if err != nil {
+		t.Fatalf("NewItemStoreFromKind(memory): %v", err)
+	}
+	if _, ok := store.(*MemoryItemStore); !ok {
+		t.Errorf("expected a *MemoryItemStore, got %T", store)
+	}
+}
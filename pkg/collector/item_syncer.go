@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"osrs-flipping/pkg/logging"
-	"osrs-flipping/pkg/osrs"
 )
 
 // ItemSyncerConfig configures the item syncer service.
@@ -24,56 +23,123 @@ func DefaultItemSyncerConfig() *ItemSyncerConfig {
 	}
 }
 
-// ItemSyncer populates and refreshes the items table from the OSRS Wiki API.
+// ItemSyncer populates and refreshes the item catalog from an ItemSource
+// into an ItemStore. Both are interfaces so the syncer can run against the
+// live OSRS Wiki API and Postgres, or against a local file/in-memory store
+// for offline development and tests.
 type ItemSyncer struct {
-	client *osrs.Client
-	repo   *Repository
+	source ItemSource
+	store  ItemStore
 	config *ItemSyncerConfig
 	logger *logging.Logger
 }
 
 // NewItemSyncer creates a new ItemSyncer.
-func NewItemSyncer(client *osrs.Client, repo *Repository, config *ItemSyncerConfig, logger *logging.Logger) *ItemSyncer {
+func NewItemSyncer(source ItemSource, store ItemStore, config *ItemSyncerConfig, logger *logging.Logger) *ItemSyncer {
 	if config == nil {
 		config = DefaultItemSyncerConfig()
 	}
 	return &ItemSyncer{
-		client: client,
-		repo:   repo,
+		source: source,
+		store:  store,
 		config: config,
 		logger: logger,
 	}
 }
 
-// Sync fetches item mappings from the API and upserts them into the database.
-// This is idempotent and safe to call multiple times.
+// syncMetadataETagKey and syncMetadataLastModifiedKey are the ItemStore
+// sync-metadata keys Sync persists the source's cache validators under.
+const (
+	syncMetadataETagKey         = "item_mapping_etag"
+	syncMetadataLastModifiedKey = "item_mapping_last_modified"
+)
+
+// Sync fetches item mappings from the source and upserts them into the
+// store. It sends the ETag/Last-Modified learned from the previous sync (if
+// any) as conditional-GET headers; if the source reports the catalog is
+// unchanged, the upsert is skipped entirely. This is idempotent and safe to
+// call multiple times.
 func (s *ItemSyncer) Sync(ctx context.Context) error {
+	return s.sync(ctx, false)
+}
+
+// ForceSync behaves like Sync but bypasses any cached ETag/Last-Modified,
+// for an operator-triggered refresh that must not trust a stale 304.
+func (s *ItemSyncer) ForceSync(ctx context.Context) error {
+	return s.sync(ctx, true)
+}
+
+func (s *ItemSyncer) sync(ctx context.Context, force bool) error {
 	s.logger.WithComponent("item_syncer").Info("starting item sync")
 
-	// Fetch mappings from API
-	mappings, err := s.client.GetItemMapping(ctx)
+	var ifNoneMatch, ifModifiedSince string
+	if !force {
+		ifNoneMatch = s.loadSyncMetadata(ctx, syncMetadataETagKey)
+		ifModifiedSince = s.loadSyncMetadata(ctx, syncMetadataLastModifiedKey)
+	}
+
+	mappings, etag, lastModified, notModified, err := s.source.GetItemMapping(ctx, ifNoneMatch, ifModifiedSince)
 	if err != nil {
 		s.logger.WithComponent("item_syncer").WithError(err).Error("failed to fetch item mappings")
 		return err
 	}
 
-	s.logger.WithComponent("item_syncer").WithField("items_fetched", len(mappings)).Debug("fetched item mappings from API")
+	if notModified {
+		s.logger.WithComponent("item_syncer").WithFields(map[string]interface{}{
+			"items_fetched": 0,
+			"cache_hit":     true,
+		}).Info("item sync skipped, catalog unchanged")
+		return nil
+	}
 
-	// Upsert into database
-	affected, err := s.repo.UpsertItems(ctx, mappings)
+	s.logger.WithComponent("item_syncer").WithField("items_fetched", len(mappings)).Debug("fetched item mappings from source")
+
+	// Upsert into the store
+	affected, err := s.store.UpsertItems(ctx, mappings)
 	if err != nil {
 		s.logger.WithComponent("item_syncer").WithError(err).Error("failed to upsert items")
 		return err
 	}
 
+	s.saveSyncMetadata(ctx, syncMetadataETagKey, etag)
+	s.saveSyncMetadata(ctx, syncMetadataLastModifiedKey, lastModified)
+
 	s.logger.WithComponent("item_syncer").WithFields(map[string]interface{}{
 		"items_fetched": len(mappings),
 		"rows_affected": affected,
+		"cache_hit":     false,
 	}).Info("item sync completed")
 
 	return nil
 }
 
+// loadSyncMetadata returns key's stored value, or "" if it's unset or
+// fails to load -- either way, the caller falls back to an unconditional
+// fetch rather than failing the sync over a cache-bookkeeping problem.
+func (s *ItemSyncer) loadSyncMetadata(ctx context.Context, key string) string {
+	value, ok, err := s.store.GetSyncMetadata(ctx, key)
+	if err != nil {
+		s.logger.WithComponent("item_syncer").WithError(err).WithField("key", key).Warn("failed to load cached sync metadata, fetching unconditionally")
+		return ""
+	}
+	if !ok {
+		return ""
+	}
+	return value
+}
+
+// saveSyncMetadata persists value under key, logging (but not failing the
+// sync over) a write error -- the next sync just falls back to an
+// unconditional fetch.
+func (s *ItemSyncer) saveSyncMetadata(ctx context.Context, key, value string) {
+	if value == "" {
+		return
+	}
+	if err := s.store.SetSyncMetadata(ctx, key, value); err != nil {
+		s.logger.WithComponent("item_syncer").WithError(err).WithField("key", key).Warn("failed to persist sync metadata")
+	}
+}
+
 // Start begins the item syncer with optional auto-refresh.
 // Returns immediately after triggering initial sync (if configured).
 // For periodic sync, call RunPeriodic in a goroutine.
@@ -1,8 +1,12 @@
 package collector
 
 import (
+	"context"
 	"testing"
 	"time"
+
+	"osrs-flipping/pkg/logging"
+	"osrs-flipping/pkg/osrs"
 )
 
 func TestDefaultItemSyncerConfig(t *testing.T) {
@@ -29,3 +33,72 @@ func TestNewItemSyncer_NilConfig(t *testing.T) {
 		t.Error("Expected default SyncOnStart to be true")
 	}
 }
+
+func TestItemSyncer_Sync_PersistsETagAndSkipsOnCacheHit(t *testing.T) {
+	source := NewMockItemSource([]osrs.ItemMapping{{ID: 1, Name: "Coins"}})
+	source.ETag = `"v1"`
+	store := NewMemoryItemStore()
+	syncer := NewItemSyncer(source, store, nil, logging.NewLogger("error", "text"))
+
+	if err := syncer.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if store.Len() != 1 {
+		t.Fatalf("expected 1 item stored, got %d", store.Len())
+	}
+	if etag, ok, _ := store.GetSyncMetadata(context.Background(), syncMetadataETagKey); !ok || etag != `"v1"` {
+		t.Errorf("expected the ETag to be persisted as %q, got %q (ok=%v)", `"v1"`, etag, ok)
+	}
+
+	// A second sync that reports the catalog unchanged must not touch the store again.
+	source.NotModified = true
+	source.Mappings = nil
+	if err := syncer.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync (cache hit): %v", err)
+	}
+	if store.Len() != 1 {
+		t.Errorf("expected the store to be untouched on a cache hit, got %d items", store.Len())
+	}
+}
+
+// recordingItemSource records the conditional-GET headers it was called
+// with, so tests can assert ForceSync bypasses a cached ETag/Last-Modified
+// while Sync sends it.
+type recordingItemSource struct {
+	mappings            []osrs.ItemMapping
+	lastIfNoneMatch     string
+	lastIfModifiedSince string
+}
+
+func (r *recordingItemSource) GetItemMapping(ctx context.Context, ifNoneMatch, ifModifiedSince string) ([]osrs.ItemMapping, string, string, bool, error) {
+	r.lastIfNoneMatch = ifNoneMatch
+	r.lastIfModifiedSince = ifModifiedSince
+	return r.mappings, `"v2"`, "", false, nil
+}
+
+func TestItemSyncer_ForceSync_BypassesCachedETag(t *testing.T) {
+	store := NewMemoryItemStore()
+	if err := store.SetSyncMetadata(context.Background(), syncMetadataETagKey, `"v1"`); err != nil {
+		t.Fatalf("seed cached ETag: %v", err)
+	}
+
+	source := &recordingItemSource{mappings: []osrs.ItemMapping{{ID: 1, Name: "Coins"}}}
+	syncer := NewItemSyncer(source, store, nil, logging.NewLogger("error", "text"))
+
+	if err := syncer.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if source.lastIfNoneMatch != `"v1"` {
+		t.Errorf("expected Sync to send the cached ETag %q, got %q", `"v1"`, source.lastIfNoneMatch)
+	}
+
+	if err := store.SetSyncMetadata(context.Background(), syncMetadataETagKey, `"v2"`); err != nil {
+		t.Fatalf("reseed cached ETag: %v", err)
+	}
+	if err := syncer.ForceSync(context.Background()); err != nil {
+		t.Fatalf("ForceSync: %v", err)
+	}
+	if source.lastIfNoneMatch != "" {
+		t.Errorf("expected ForceSync to send no If-None-Match, got %q", source.lastIfNoneMatch)
+	}
+}
@@ -0,0 +1,141 @@
+package collector
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// syncMetrics is BackgroundSync's self-contained Prometheus registry and
+// instrument set. It doesn't run its own HTTP server -- the rest of the app
+// mounts NewMetricsHandler() wherever it already serves HTTP (e.g. at
+// /metrics), the same convention pkg/scheduler/metrics.go uses.
+type syncMetrics struct {
+	registry *prometheus.Registry
+
+	timestampsSynced *prometheus.CounterVec
+	bucketsInserted  *prometheus.CounterVec
+	errorsTotal      *prometheus.CounterVec
+	cycleDuration    *prometheus.HistogramVec
+	apiLatency       *prometheus.HistogramVec
+	missingTotal     *prometheus.GaugeVec
+	lastCycleEnd     prometheus.Gauge
+}
+
+func newSyncMetrics() *syncMetrics {
+	registry := prometheus.NewRegistry()
+
+	m := &syncMetrics{
+		registry: registry,
+		timestampsSynced: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "osrs_sync_timestamps_synced_total",
+			Help: "Number of bucket timestamps successfully synced from the OSRS wiki API.",
+		}, []string{"bucket_size"}),
+		bucketsInserted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "osrs_sync_buckets_inserted_total",
+			Help: "Number of price buckets upserted into the database.",
+		}, []string{"bucket_size"}),
+		errorsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "osrs_sync_errors_total",
+			Help: "Number of sync errors, by the stage that produced them.",
+		}, []string{"bucket_size", "reason"}),
+		cycleDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "osrs_sync_cycle_duration_seconds",
+			Help:    "Wall-clock duration of syncBucketSize for one bucket size within a cycle.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"bucket_size"}),
+		apiLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "osrs_sync_api_latency_seconds",
+			Help:    "Latency of OSRS wiki API calls made during sync.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		missingTotal: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "osrs_sync_missing_timestamps",
+			Help: "Number of timestamps GetMissingBucketTimestamps returned for the most recent cycle, by bucket size.",
+		}, []string{"bucket_size"}),
+		lastCycleEnd: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "osrs_sync_last_cycle_end_timestamp",
+			Help: "Unix timestamp of the end of the most recently completed sync cycle.",
+		}),
+	}
+
+	registry.MustRegister(m.timestampsSynced, m.bucketsInserted, m.errorsTotal,
+		m.cycleDuration, m.apiLatency, m.missingTotal, m.lastCycleEnd)
+	return m
+}
+
+// registerRateBreakerCollector wires b's live rate-breaker state into b's
+// registry. Separate from newSyncMetrics because it needs the *BackgroundSync
+// itself, which doesn't exist yet when newSyncMetrics runs during
+// NewBackgroundSync.
+func (b *BackgroundSync) registerRateBreakerCollector() {
+	b.metrics.registry.MustRegister(newRateBreakerCollector(b))
+}
+
+// rateBreakerCollector computes the effective-rate, breaker-open, and
+// consecutive-error gauges from BackgroundSync's live state at scrape time,
+// the same approach pkg/scheduler/metrics.go's schedulerCollector uses.
+type rateBreakerCollector struct {
+	b *BackgroundSync
+
+	effectiveRate     *prometheus.Desc
+	breakerOpen       *prometheus.Desc
+	consecutiveErrors *prometheus.Desc
+}
+
+func newRateBreakerCollector(b *BackgroundSync) *rateBreakerCollector {
+	return &rateBreakerCollector{
+		b: b,
+		effectiveRate: prometheus.NewDesc(
+			"osrs_sync_rate_limiter_effective_rate",
+			"Current effective rate (requests/sec) of the shared API rate limiter.",
+			nil, nil,
+		),
+		breakerOpen: prometheus.NewDesc(
+			"osrs_sync_rate_breaker_open",
+			"1 if the adaptive rate breaker has halved the rate limiter, 0 otherwise.",
+			nil, nil,
+		),
+		consecutiveErrors: prometheus.NewDesc(
+			"osrs_sync_rate_breaker_consecutive_errors",
+			"Consecutive failed API calls recorded by the rate breaker.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *rateBreakerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.effectiveRate
+	ch <- c.breakerOpen
+	ch <- c.consecutiveErrors
+}
+
+func (c *rateBreakerCollector) Collect(ch chan<- prometheus.Metric) {
+	status := c.b.RateBreakerStatus()
+
+	ch <- prometheus.MustNewConstMetric(c.effectiveRate, prometheus.GaugeValue, status.EffectiveRate)
+	open := 0.0
+	if status.Open {
+		open = 1.0
+	}
+	ch <- prometheus.MustNewConstMetric(c.breakerOpen, prometheus.GaugeValue, open)
+	ch <- prometheus.MustNewConstMetric(c.consecutiveErrors, prometheus.GaugeValue, float64(status.ConsecutiveErrors))
+}
+
+// NewMetricsHandler returns an http.Handler serving this BackgroundSync's
+// metrics in the Prometheus text exposition format.
+func (b *BackgroundSync) NewMetricsHandler() http.Handler {
+	return promhttp.HandlerFor(b.metrics.registry, promhttp.HandlerOpts{})
+}
+
+// recordError increments the error counter for bucketSize/reason.
+func (b *BackgroundSync) recordError(bucketSize, reason string) {
+	b.metrics.errorsTotal.WithLabelValues(bucketSize, reason).Inc()
+}
+
+// recordAPICall observes the latency of an OSRS wiki API call.
+func (b *BackgroundSync) recordAPICall(endpoint string, start time.Time) {
+	b.metrics.apiLatency.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,124 @@
+package collector
+
+import (
+	"context"
+	"time"
+)
+
+// SyncObserver receives notifications about freshly-synced price data, so
+// downstream consumers (alerting, flip detection) can react in near-real-time
+// instead of polling the database.
+type SyncObserver interface {
+	// OnBucketsInserted is called after InsertPriceBuckets successfully
+	// persists the buckets fetched for one timestamp.
+	OnBucketsInserted(ctx context.Context, bucketSize string, ts time.Time, buckets []PriceBucket)
+	// OnCycleComplete is called once at the end of every runCycle.
+	OnCycleComplete(ctx context.Context, progress BackgroundSyncProgress)
+}
+
+// SyncEvent is the payload an observer receives, used directly by
+// ChannelObserver and marshaled to JSON by WebhookObserver. Kind is
+// "buckets_inserted" or "cycle_complete"; only the fields relevant to that
+// kind are populated.
+type SyncEvent struct {
+	Kind       string                 `json:"kind"`
+	BucketSize string                 `json:"bucket_size,omitempty"`
+	Timestamp  time.Time              `json:"timestamp,omitempty"`
+	Buckets    []PriceBucket          `json:"buckets,omitempty"`
+	Progress   BackgroundSyncProgress `json:"progress,omitempty"`
+}
+
+// observerQueueSize bounds how many pending notifications an observer can
+// fall behind by before events start getting dropped.
+const observerQueueSize = 32
+
+// observerSub runs one observer's notifications on its own goroutine and
+// queue, so a slow OnBucketsInserted/OnCycleComplete implementation only
+// ever delays itself, never the sync loop or other observers.
+type observerSub struct {
+	observer SyncObserver
+	events   chan func()
+	done     chan struct{}
+}
+
+func newObserverSub(o SyncObserver) *observerSub {
+	s := &observerSub{
+		observer: o,
+		events:   make(chan func(), observerQueueSize),
+		done:     make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *observerSub) run() {
+	defer close(s.done)
+	for fn := range s.events {
+		fn()
+	}
+}
+
+// dispatch enqueues fn for the observer's goroutine, reporting false if the
+// queue was full and fn had to be dropped.
+func (s *observerSub) dispatch(fn func()) bool {
+	select {
+	case s.events <- fn:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop closes the queue and waits for the goroutine to drain it.
+func (s *observerSub) stop() {
+	close(s.events)
+	<-s.done
+}
+
+// RegisterObserver subscribes o to this BackgroundSync's events. o runs on
+// its own goroutine, so it never blocks the sync loop or other observers.
+func (b *BackgroundSync) RegisterObserver(o SyncObserver) {
+	b.observerMu.Lock()
+	defer b.observerMu.Unlock()
+	b.observers = append(b.observers, newObserverSub(o))
+}
+
+// UnregisterObserver removes o, if registered, and waits for its queue to
+// drain before returning.
+func (b *BackgroundSync) UnregisterObserver(o SyncObserver) {
+	b.observerMu.Lock()
+	defer b.observerMu.Unlock()
+	for i, sub := range b.observers {
+		if sub.observer == o {
+			sub.stop()
+			b.observers = append(b.observers[:i], b.observers[i+1:]...)
+			return
+		}
+	}
+}
+
+// notifyBucketsInserted fans OnBucketsInserted out to every registered
+// observer, recording a dropped-event error for any whose queue is full.
+func (b *BackgroundSync) notifyBucketsInserted(bucketSize string, ts time.Time, buckets []PriceBucket) {
+	b.observerMu.RLock()
+	defer b.observerMu.RUnlock()
+	for _, sub := range b.observers {
+		sub := sub
+		if !sub.dispatch(func() { sub.observer.OnBucketsInserted(context.Background(), bucketSize, ts, buckets) }) {
+			b.recordError(bucketSize, "observer_dropped")
+		}
+	}
+}
+
+// notifyCycleComplete fans OnCycleComplete out to every registered observer,
+// recording a dropped-event error for any whose queue is full.
+func (b *BackgroundSync) notifyCycleComplete(progress BackgroundSyncProgress) {
+	b.observerMu.RLock()
+	defer b.observerMu.RUnlock()
+	for _, sub := range b.observers {
+		sub := sub
+		if !sub.dispatch(func() { sub.observer.OnCycleComplete(context.Background(), progress) }) {
+			b.recordError("", "observer_dropped")
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestObserverSub_DispatchRunsOnOwnGoroutine(t *testing.T) {
+	sub := newObserverSub(NewChannelObserver(1))
+	defer sub.stop()
+
+	done := make(chan struct{})
+	if !sub.dispatch(func() { close(done) }) {
+		t.Fatal("expected dispatch to succeed with an empty queue")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatched fn never ran")
+	}
+}
+
+func TestObserverSub_DropsWhenQueueFull(t *testing.T) {
+	block := make(chan struct{})
+	sub := newObserverSub(NewChannelObserver(1))
+	defer func() {
+		close(block)
+		sub.stop()
+	}()
+
+	// Occupy the goroutine so the queue backs up behind it.
+	if !sub.dispatch(func() { <-block }) {
+		t.Fatal("expected first dispatch to succeed")
+	}
+
+	ok := true
+	for i := 0; i < observerQueueSize+1; i++ {
+		if !sub.dispatch(func() {}) {
+			ok = false
+			break
+		}
+	}
+	if ok {
+		t.Error("expected dispatch to eventually report a full queue")
+	}
+}
+
+func TestBackgroundSync_RegisterObserver_NotifiesBucketsInserted(t *testing.T) {
+	bs := NewBackgroundSync(nil, nil, nil, nil, nil)
+	obs := NewChannelObserver(1)
+	bs.RegisterObserver(obs)
+	defer bs.UnregisterObserver(obs)
+
+	ts := time.Unix(1700000000, 0).UTC()
+	buckets := []PriceBucket{{ItemID: 2, BucketSize: "5m", BucketStart: ts}}
+	bs.notifyBucketsInserted("5m", ts, buckets)
+
+	select {
+	case ev := <-obs.Events:
+		if ev.Kind != "buckets_inserted" || ev.BucketSize != "5m" || len(ev.Buckets) != 1 {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("observer never received the event")
+	}
+}
+
+func TestBackgroundSync_UnregisterObserver_StopsNotifications(t *testing.T) {
+	bs := NewBackgroundSync(nil, nil, nil, nil, nil)
+	obs := NewChannelObserver(1)
+	bs.RegisterObserver(obs)
+	bs.UnregisterObserver(obs)
+
+	bs.notifyCycleComplete(BackgroundSyncProgress{})
+
+	select {
+	case ev := <-obs.Events:
+		t.Errorf("unregistered observer should not receive events, got %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestBackgroundSync_DroppedObserverEventIncrementsErrorCounter(t *testing.T) {
+	bs := NewBackgroundSync(nil, nil, nil, nil, nil)
+	block := make(chan struct{})
+
+	obs := NewChannelObserver(0)
+	bs.RegisterObserver(obs)
+	// Release the blocked goroutine before UnregisterObserver waits for it.
+	defer func() {
+		close(block)
+		bs.UnregisterObserver(obs)
+	}()
+
+	sub := bs.observers[0]
+	if !sub.dispatch(func() { <-block }) {
+		t.Fatal("expected to occupy the observer goroutine")
+	}
+	for i := 0; i < observerQueueSize; i++ {
+		sub.dispatch(func() {})
+	}
+
+	bs.notifyBucketsInserted("5m", time.Now(), nil)
+
+	families, err := bs.metrics.registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	var saw bool
+	for _, family := range families {
+		if family.GetName() == "osrs_sync_errors_total" {
+			saw = true
+		}
+	}
+	if !saw {
+		t.Error("expected a dropped observer event to record osrs_sync_errors_total")
+	}
+}
+
+func TestWebhookObserver_ReturnsOnCanceledContext(t *testing.T) {
+	// No HTTP server is wired up here; just confirm OnBucketsInserted
+	// doesn't block when ctx is already canceled, instead of hanging.
+	w := NewWebhookObserver("http://127.0.0.1:0/webhook")
+	w.MaxRetries = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.OnBucketsInserted(ctx, "5m", time.Now(), nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnBucketsInserted did not return")
+	}
+}
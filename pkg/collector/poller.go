@@ -2,6 +2,7 @@ package collector
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"sync"
 	"time"
@@ -180,6 +181,18 @@ func (p *Poller) handleError(err error) {
 	}
 }
 
+// HealthCheck reports an error once consecutive poll failures reach
+// config.MaxRetries, so Supervisor knows to restart the poller instead of
+// leaving it stuck retrying the same broken state indefinitely.
+func (p *Poller) HealthCheck() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.consecutiveFails >= p.config.MaxRetries {
+		return fmt.Errorf("poller: %d consecutive poll failures", p.consecutiveFails)
+	}
+	return nil
+}
+
 // Stats returns current poller statistics.
 func (p *Poller) Stats() map[string]interface{} {
 	p.mu.Lock()
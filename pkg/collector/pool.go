@@ -0,0 +1,41 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudget is a mutex-guarded retry counter shared across syncBucketSize's
+// worker pool, so concurrent workers draw from the same per-cycle budget
+// instead of each getting their own.
+type retryBudget struct {
+	mu sync.Mutex
+	n  int
+}
+
+// take consumes one retry from the budget, reporting false if it's exhausted.
+func (b *retryBudget) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.n <= 0 {
+		return false
+	}
+	b.n--
+	return true
+}
+
+// timestampResult is one worker's outcome for a single timestamp, read by
+// syncBucketSize's batching/flush loop.
+type timestampResult struct {
+	ts      time.Time
+	buckets []PriceBucket
+	err     error
+}
+
+// pendingGroup tracks which timestamp a run of buckets in syncBucketSize's
+// pending batch came from, so a successful flush can still notify observers
+// per-timestamp even though the insert itself is batched.
+type pendingGroup struct {
+	ts      time.Time
+	buckets []PriceBucket
+}
@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRetryBudgetTake(t *testing.T) {
+	b := &retryBudget{n: 2}
+
+	if !b.take() {
+		t.Fatal("expected first take to succeed")
+	}
+	if !b.take() {
+		t.Fatal("expected second take to succeed")
+	}
+	if b.take() {
+		t.Fatal("expected third take to fail, budget exhausted")
+	}
+}
+
+func TestRetryBudgetConcurrentTake(t *testing.T) {
+	b := &retryBudget{n: 50}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	taken := 0
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b.take() {
+				mu.Lock()
+				taken++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if taken != 50 {
+		t.Errorf("taken = %d, want 50 (budget should cap total successful takes)", taken)
+	}
+}
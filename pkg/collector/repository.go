@@ -3,11 +3,14 @@ package collector
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"osrs-flipping/pkg/logging"
 	"osrs-flipping/pkg/osrs"
 )
 
@@ -23,14 +26,222 @@ type PriceObservation struct {
 
 // Repository handles database operations for the collector.
 type Repository struct {
-	pool *pgxpool.Pool
+	pool      *pgxpool.Pool
+	hotCaches map[string]*hotCache
+	metrics   *repositoryMetrics
+	publisher EventPublisher
+
+	timeIndexesMu     sync.Mutex
+	timeIndexes       map[int]*TimeIndex
+	timeIndexesLoaded map[int]bool
+
+	logger             *logging.Logger
+	slowQueryThreshold time.Duration
+}
+
+// RepositoryOption configures optional Repository behavior not needed by
+// every caller, following the same pattern as pkg/scheduler's SchedulerOption.
+type RepositoryOption func(*Repository)
+
+// WithLogger sets the logger used for slow-query warnings. Without it,
+// slow queries are not logged even if WithSlowQueryThreshold is set.
+func WithLogger(logger *logging.Logger) RepositoryOption {
+	return func(r *Repository) {
+		r.logger = logger
+	}
+}
+
+// WithSlowQueryThreshold logs the full query (and, where applicable, the
+// triggering bucket size) via WithLogger's logger whenever
+// InsertPriceObservations, insertBucketsToTable, or GetItemsNeedingSync
+// takes longer than threshold. Zero (the default) disables slow-query
+// logging.
+func WithSlowQueryThreshold(threshold time.Duration) RepositoryOption {
+	return func(r *Repository) {
+		r.slowQueryThreshold = threshold
+	}
+}
+
+// WithPublisher sets the EventPublisher InsertPriceObservations notifies
+// after a successful COPY. Without it, Repository doesn't publish events.
+func WithPublisher(publisher EventPublisher) RepositoryOption {
+	return func(r *Repository) {
+		r.publisher = publisher
+	}
+}
+
+// NewRepository creates a new Repository, with a hot-bucket cache per bucket
+// size sized from defaultHotCacheCapacity. Use SetHotCacheCapacity to retune
+// a bucket size's cache.
+func NewRepository(pool *pgxpool.Pool, opts ...RepositoryOption) *Repository {
+	hotCaches := make(map[string]*hotCache, len(defaultHotCacheCapacity))
+	for bucketSize, capacity := range defaultHotCacheCapacity {
+		hotCaches[bucketSize] = newHotCache(capacity)
+	}
+	r := &Repository{pool: pool, hotCaches: hotCaches, timeIndexes: make(map[int]*TimeIndex), timeIndexesLoaded: make(map[int]bool), metrics: newRepositoryMetrics()}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// logSlowQuery logs query (with args) via r.logger if duration exceeds
+// r.slowQueryThreshold. A no-op when either is unset.
+func (r *Repository) logSlowQuery(query string, duration time.Duration, args ...interface{}) {
+	if r.logger == nil || r.slowQueryThreshold == 0 || duration < r.slowQueryThreshold {
+		return
+	}
+	r.logger.WithComponent("repository").WithFields(map[string]interface{}{
+		"duration_ms":  duration.Milliseconds(),
+		"threshold_ms": r.slowQueryThreshold.Milliseconds(),
+		"query":        query,
+		"args":         args,
+	}).Warn("slow query")
+}
+
+// SetHotCacheCapacity replaces bucketSize's hot cache with an empty one of
+// the given capacity (distinct bucket_start timestamps retained).
+func (r *Repository) SetHotCacheCapacity(bucketSize string, capacity int) {
+	r.hotCaches[bucketSize] = newHotCache(capacity)
+}
+
+// cacheBuckets populates bucketSize's hot cache with buckets just written by
+// InsertPriceBuckets, the single path all collector writes flow through, so
+// the cache stays trivially coherent with the database.
+func (r *Repository) cacheBuckets(bucketSize string, buckets []PriceBucket) {
+	cache, ok := r.hotCaches[bucketSize]
+	if !ok {
+		return
+	}
+
+	byTs := make(map[time.Time][]PriceBucket)
+	for _, b := range buckets {
+		byTs[b.BucketStart] = append(byTs[b.BucketStart], b)
+	}
+	for ts, bs := range byTs {
+		cache.put(bucketSize, ts, bs)
+	}
+}
+
+// indexBuckets feeds buckets just written by InsertPriceBuckets into each
+// item's TimeIndex, the same way cacheBuckets feeds the hot cache, so
+// GapFiller.Gaps stays coherent with the database without a rescan.
+func (r *Repository) indexBuckets(buckets []PriceBucket) {
+	byItem := make(map[int][]PriceBucket)
+	for _, b := range buckets {
+		byItem[b.ItemID] = append(byItem[b.ItemID], b)
+	}
+	for itemID, bs := range byItem {
+		r.TimeIndexFor(itemID).InsertBuckets(bs)
+	}
+}
+
+// TimeIndexFor returns itemID's in-memory TimeIndex, creating an empty one
+// the first time this process sees itemID. Callers that want an index
+// seeded from a prior process's data should call LoadTimeIndex first.
+func (r *Repository) TimeIndexFor(itemID int) *TimeIndex {
+	r.timeIndexesMu.Lock()
+	defer r.timeIndexesMu.Unlock()
+	ti, ok := r.timeIndexes[itemID]
+	if !ok {
+		ti = NewTimeIndex()
+		r.timeIndexes[itemID] = ti
+	}
+	return ti
 }
 
-// NewRepository creates a new Repository.
-func NewRepository(pool *pgxpool.Pool) *Repository {
-	return &Repository{pool: pool}
+// timeIndexMetadataKey is the sync_metadata key SaveTimeIndex/LoadTimeIndex
+// persist itemID's serialized TimeIndex under, following the same
+// key-per-concern convention as item_syncer.go's syncMetadataETagKey.
+func timeIndexMetadataKey(itemID int) string {
+	return "gap_index:" + strconv.Itoa(itemID)
 }
 
+// LoadTimeIndex populates itemID's in-memory TimeIndex from its persisted
+// sync_metadata entry, if any, merging it into whatever this process has
+// already indexed for itemID rather than replacing it -- TimeIndexFor hands
+// out (and indexBuckets populates) an empty entry as soon as the first
+// InsertPriceBuckets call for itemID lands, which can easily happen before
+// LoadTimeIndex is ever called for it. It's a no-op once this process has
+// already loaded itemID's persisted index once (tracked separately from
+// merely having an in-memory entry), so repeated calls don't keep re-reading
+// sync_metadata.
+func (r *Repository) LoadTimeIndex(ctx context.Context, itemID int) error {
+	r.timeIndexesMu.Lock()
+	alreadyLoaded := r.timeIndexesLoaded[itemID]
+	r.timeIndexesMu.Unlock()
+	if alreadyLoaded {
+		return nil
+	}
+
+	value, ok, err := r.GetSyncMetadata(ctx, timeIndexMetadataKey(itemID))
+	if err != nil {
+		return fmt.Errorf("load time index for item %d: %w", itemID, err)
+	}
+	if ok {
+		persisted, err := DeserializeTimeIndex(value)
+		if err != nil {
+			return fmt.Errorf("parse time index for item %d: %w", itemID, err)
+		}
+		ti := r.TimeIndexFor(itemID)
+		for _, entry := range persisted.entriesSnapshot() {
+			ti.Insert(entry)
+		}
+	}
+
+	r.timeIndexesMu.Lock()
+	r.timeIndexesLoaded[itemID] = true
+	r.timeIndexesMu.Unlock()
+	return nil
+}
+
+// SaveTimeIndex persists itemID's current in-memory TimeIndex so a future
+// restart's LoadTimeIndex can pick it up instead of rescanning.
+func (r *Repository) SaveTimeIndex(ctx context.Context, itemID int) error {
+	if err := r.SetSyncMetadata(ctx, timeIndexMetadataKey(itemID), r.TimeIndexFor(itemID).Serialize()); err != nil {
+		return fmt.Errorf("save time index for item %d: %w", itemID, err)
+	}
+	return nil
+}
+
+// GetRecentBuckets returns itemID's up-to-limit most recent buckets for
+// bucketSize, newest first, serving from the hot cache when it can
+// guarantee a complete answer and falling back to the database otherwise.
+func (r *Repository) GetRecentBuckets(ctx context.Context, itemID int, bucketSize string, limit int) ([]PriceBucket, error) {
+	if cache, ok := r.hotCaches[bucketSize]; ok {
+		if buckets, hit := cache.getRecent(itemID, bucketSize, limit); hit {
+			return buckets, nil
+		}
+	}
+
+	tableName := bucketTableName(bucketSize)
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
+		SELECT item_id, bucket_start, avg_high_price, high_price_volume, avg_low_price, low_price_volume, source
+		FROM %s
+		WHERE item_id = $1
+		ORDER BY bucket_start DESC
+		LIMIT $2
+	`, tableName), itemID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query recent buckets: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := make([]PriceBucket, 0, limit)
+	for rows.Next() {
+		b := PriceBucket{ItemID: itemID, BucketSize: bucketSize}
+		if err := rows.Scan(&b.ItemID, &b.BucketStart, &b.AvgHighPrice, &b.HighPriceVolume, &b.AvgLowPrice, &b.LowPriceVolume, &b.Source); err != nil {
+			return nil, fmt.Errorf("scan recent bucket: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+// Note: GetRecentBuckets' database fallback requires database integration
+// tests; only the hot-cache fast path is covered at the unit level (see
+// hotcache_test.go).
+
 // InsertPriceObservations batch inserts price observations.
 // Uses COPY for efficient bulk insertion.
 func (r *Repository) InsertPriceObservations(ctx context.Context, observations []PriceObservation) (int64, error) {
@@ -41,6 +252,7 @@ func (r *Repository) InsertPriceObservations(ctx context.Context, observations [
 	// Use COPY for bulk insert (much faster than individual INSERTs)
 	columns := []string{"item_id", "observed_at", "high_price", "high_time", "low_price", "low_time"}
 
+	start := time.Now()
 	copyCount, err := r.pool.CopyFrom(
 		ctx,
 		pgx.Identifier{"price_observations"},
@@ -57,9 +269,19 @@ func (r *Repository) InsertPriceObservations(ctx context.Context, observations [
 			}, nil
 		}),
 	)
+	duration := time.Since(start)
+	r.metrics.copyDuration.Observe(duration.Seconds())
+	r.logSlowQuery("COPY price_observations", duration, len(observations))
 	if err != nil {
 		return 0, fmt.Errorf("copy from: %w", err)
 	}
+	r.metrics.copyRowsInserted.Observe(float64(copyCount))
+
+	if r.publisher != nil {
+		if err := r.publisher.PublishObservations(ctx, observations); err != nil && r.logger != nil {
+			r.logger.WithComponent("repository").WithError(err).Warn("failed to publish price observations")
+		}
+	}
 
 	return copyCount, nil
 }
@@ -82,6 +304,28 @@ func (r *Repository) GetLatestObservationTime(ctx context.Context) (*time.Time,
 	return &t, nil
 }
 
+// GetItemLastObservedTime returns itemID's most recent price_observations
+// timestamp, or nil if it has none. Unlike GetLatestObservationTime (the
+// latest observation across every item), this is scoped to one item, for
+// GapFiller's priority scoring (see GapFiller.scoreItem).
+func (r *Repository) GetItemLastObservedTime(ctx context.Context, itemID int) (*time.Time, error) {
+	var t time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT observed_at FROM price_observations
+		WHERE item_id = $1
+		ORDER BY observed_at DESC
+		LIMIT 1
+	`, itemID).Scan(&t)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query item last observation: %w", err)
+	}
+	return &t, nil
+}
+
 // GetObservationCount returns the total number of observations.
 func (r *Repository) GetObservationCount(ctx context.Context) (int64, error) {
 	var count int64
@@ -103,7 +347,7 @@ type PriceBucket struct {
 	HighPriceVolume *int64
 	AvgLowPrice     *int
 	LowPriceVolume  *int64
-	Source          string // "api" or "computed"
+	Source          string // "api", "dump" (see collector.DumpSource), or "computed"
 }
 
 // bucketTableName returns the table name for a given bucket size.
@@ -120,6 +364,21 @@ func bucketTableName(bucketSize string) string {
 	}
 }
 
+// bucketSizeForTable is bucketTableName's inverse, used to label metrics
+// from callers that only have the table name on hand.
+func bucketSizeForTable(tableName string) string {
+	switch tableName {
+	case "price_buckets_5m":
+		return "5m"
+	case "price_buckets_1h":
+		return "1h"
+	case "price_buckets_24h":
+		return "24h"
+	default:
+		return "unknown"
+	}
+}
+
 // InsertPriceBuckets batch inserts price buckets using upsert logic.
 // Routes to the appropriate table based on bucket size.
 // On conflict, updates if the new data is from API (preferred over computed).
@@ -142,6 +401,8 @@ func (r *Repository) InsertPriceBuckets(ctx context.Context, buckets []PriceBuck
 			return totalInserted, fmt.Errorf("insert to %s: %w", tableName, err)
 		}
 		totalInserted += inserted
+		r.cacheBuckets(bucketSize, sizeBuckets)
+		r.indexBuckets(sizeBuckets)
 	}
 
 	return totalInserted, nil
@@ -149,6 +410,14 @@ func (r *Repository) InsertPriceBuckets(ctx context.Context, buckets []PriceBuck
 
 // insertBucketsToTable inserts buckets to a specific table.
 func (r *Repository) insertBucketsToTable(ctx context.Context, tableName string, buckets []PriceBucket) (int64, error) {
+	bucketSize := bucketSizeForTable(tableName)
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start)
+		r.metrics.bucketBatchSeconds.WithLabelValues(bucketSize).Observe(duration.Seconds())
+		r.logSlowQuery(fmt.Sprintf("batch insert into %s", tableName), duration, len(buckets))
+	}()
+
 	batch := &pgx.Batch{}
 	for _, b := range buckets {
 		// Note: table name is from our controlled bucketTableName(), not user input
@@ -193,6 +462,81 @@ func (r *Repository) GetBucketCount(ctx context.Context, bucketSize string) (int
 	return count, nil
 }
 
+// GetMissingBucketRanges returns itemID's missing bucketSize intervals
+// within its retention window, as contiguous TimeRanges -- a true
+// bucket-by-bucket diff against the expected grid (start = floor((now -
+// retention) / bucketDur) * bucketDur, stepped by bucketDur), rather than
+// GetItemsNeedingSync's coarser "actual count < 90% of expected count"
+// heuristic. retention=0 means no limit; the grid starts at itemID's first
+// observed bucket in that case, since there's no earlier expected bucket to
+// be missing. Unlike GapFiller.Gaps (TimeIndex.Gaps, in-memory, doesn't
+// know about buckets before the first or after the last one it has
+// indexed), this queries the bucket table directly, so it also reports a
+// gap at the very start or end of the window.
+func (r *Repository) GetMissingBucketRanges(ctx context.Context, itemID int, bucketSize string, retention time.Duration) ([]TimeRange, error) {
+	interval := bucketInterval(bucketSize)
+	tableName := bucketTableName(bucketSize)
+
+	now := time.Now().UTC()
+	var gridStart time.Time
+	if retention > 0 {
+		gridStart = now.Add(-retention).Truncate(interval)
+	} else {
+		var first *time.Time
+		err := r.pool.QueryRow(ctx, fmt.Sprintf(`
+			SELECT MIN(bucket_start) FROM %s WHERE item_id = $1
+		`, tableName), itemID).Scan(&first)
+		if err != nil {
+			return nil, fmt.Errorf("query first bucket: %w", err)
+		}
+		if first == nil {
+			return nil, nil
+		}
+		gridStart = *first
+	}
+
+	query := fmt.Sprintf(`
+		WITH expected AS (
+			SELECT generate_series($2::timestamptz, $3::timestamptz, $4::interval) AS bucket_start
+		)
+		SELECT e.bucket_start
+		FROM expected e
+		LEFT JOIN %s a ON a.item_id = $1 AND a.bucket_start = e.bucket_start
+		WHERE a.bucket_start IS NULL
+		ORDER BY e.bucket_start
+	`, tableName)
+
+	start := time.Now()
+	rows, err := r.pool.Query(ctx, query, itemID, gridStart, now, interval)
+	r.logSlowQuery(query, time.Since(start), itemID, gridStart, now, interval)
+	if err != nil {
+		return nil, fmt.Errorf("query missing bucket ranges: %w", err)
+	}
+	defer rows.Close()
+
+	var ranges []TimeRange
+	var rangeStart, rangeEnd time.Time
+	inGap := false
+	for rows.Next() {
+		var t time.Time
+		if err := rows.Scan(&t); err != nil {
+			return nil, fmt.Errorf("scan missing bucket: %w", err)
+		}
+		if inGap && t.Equal(rangeEnd) {
+			rangeEnd = rangeEnd.Add(interval)
+			continue
+		}
+		if inGap {
+			ranges = append(ranges, TimeRange{Start: rangeStart, End: rangeEnd})
+		}
+		rangeStart, rangeEnd, inGap = t, t.Add(interval), true
+	}
+	if inGap {
+		ranges = append(ranges, TimeRange{Start: rangeStart, End: rangeEnd})
+	}
+	return ranges, rows.Err()
+}
+
 // Item represents a row in the items table.
 type Item struct {
 	ItemID     int
@@ -377,7 +721,11 @@ func (r *Repository) GetItemsNeedingSync(ctx context.Context, bucketSize string,
 		LIMIT $3
 	`, tableName)
 
+	start := time.Now()
 	rows, err := r.pool.Query(ctx, query, windowStart, interval, limit)
+	duration := time.Since(start)
+	r.metrics.syncQuerySeconds.WithLabelValues(bucketSize).Observe(duration.Seconds())
+	r.logSlowQuery(query, duration, windowStart, interval, limit)
 	if err != nil {
 		return nil, fmt.Errorf("query items needing sync: %w", err)
 	}
@@ -413,3 +761,96 @@ func (r *Repository) GetItem(ctx context.Context, itemID int) (*Item, error) {
 	}
 	return &item, nil
 }
+
+// GetCheckpoint returns bucketSize's persisted sync checkpoint, or nil if
+// none has been saved yet.
+func (r *Repository) GetCheckpoint(ctx context.Context, bucketSize string) (*Checkpoint, error) {
+	var cp Checkpoint
+	err := r.pool.QueryRow(ctx, `
+		SELECT bucket_size, last_synced_at, cycles_since_full_scan
+		FROM sync_checkpoints
+		WHERE bucket_size = $1
+	`, bucketSize).Scan(&cp.BucketSize, &cp.LastSyncedAt, &cp.CyclesSinceFullScan)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query sync checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// SaveCheckpoint upserts cp, keyed by cp.BucketSize.
+func (r *Repository) SaveCheckpoint(ctx context.Context, cp Checkpoint) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO sync_checkpoints (bucket_size, last_synced_at, cycles_since_full_scan, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (bucket_size) DO UPDATE SET
+			last_synced_at = EXCLUDED.last_synced_at,
+			cycles_since_full_scan = EXCLUDED.cycles_since_full_scan,
+			updated_at = NOW()
+	`, cp.BucketSize, cp.LastSyncedAt, cp.CyclesSinceFullScan)
+	if err != nil {
+		return fmt.Errorf("save sync checkpoint: %w", err)
+	}
+	return nil
+}
+
+// GetSyncMetadata returns key's persisted value from the sync_metadata
+// table, or ok=false if it has never been set (see ItemStore).
+func (r *Repository) GetSyncMetadata(ctx context.Context, key string) (string, bool, error) {
+	var value string
+	err := r.pool.QueryRow(ctx, `SELECT value FROM sync_metadata WHERE key = $1`, key).Scan(&value)
+	if err == pgx.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("query sync metadata %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// SetSyncMetadata upserts value under key in the sync_metadata table.
+func (r *Repository) SetSyncMetadata(ctx context.Context, key, value string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO sync_metadata (key, value, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			value = EXCLUDED.value,
+			updated_at = NOW()
+	`, key, value)
+	if err != nil {
+		return fmt.Errorf("save sync metadata %s: %w", key, err)
+	}
+	return nil
+}
+
+// IsPoisoned reports whether ts has been recorded as permanently returning
+// zero data for bucketSize, so callers can skip retrying it.
+func (r *Repository) IsPoisoned(ctx context.Context, bucketSize string, ts time.Time) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM sync_poison_timestamps WHERE bucket_size = $1 AND bucket_start = $2)
+	`, bucketSize, ts).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("query poison timestamp: %w", err)
+	}
+	return exists, nil
+}
+
+// MarkPoisoned records ts as having returned zero data for bucketSize,
+// incrementing its attempt count if it was already on the list.
+func (r *Repository) MarkPoisoned(ctx context.Context, bucketSize string, ts time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO sync_poison_timestamps (bucket_size, bucket_start, attempts, last_seen_at)
+		VALUES ($1, $2, 1, NOW())
+		ON CONFLICT (bucket_size, bucket_start) DO UPDATE SET
+			attempts = sync_poison_timestamps.attempts + 1,
+			last_seen_at = NOW()
+	`, bucketSize, ts)
+	if err != nil {
+		return fmt.Errorf("mark poisoned timestamp: %w", err)
+	}
+	return nil
+}
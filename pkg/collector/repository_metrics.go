@@ -0,0 +1,57 @@
+package collector
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// repositoryMetrics is Repository's self-contained Prometheus registry and
+// instrument set, following the same per-component convention as
+// syncMetrics in metrics.go and pkg/scheduler/metrics.go.
+type repositoryMetrics struct {
+	registry *prometheus.Registry
+
+	copyDuration       prometheus.Histogram
+	copyRowsInserted   prometheus.Histogram
+	bucketBatchSeconds *prometheus.HistogramVec
+	syncQuerySeconds   *prometheus.HistogramVec
+}
+
+func newRepositoryMetrics() *repositoryMetrics {
+	registry := prometheus.NewRegistry()
+
+	m := &repositoryMetrics{
+		registry: registry,
+		copyDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "osrs_repo_insert_observations_duration_seconds",
+			Help:    "Duration of InsertPriceObservations' CopyFrom call.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		copyRowsInserted: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "osrs_repo_insert_observations_rows",
+			Help:    "Rows inserted per InsertPriceObservations call.",
+			Buckets: []float64{1, 10, 50, 100, 500, 1000, 5000, 10000},
+		}),
+		bucketBatchSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "osrs_repo_insert_buckets_duration_seconds",
+			Help:    "Duration of insertBucketsToTable's batch exec, by bucket size.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"bucket_size"}),
+		syncQuerySeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "osrs_repo_items_needing_sync_duration_seconds",
+			Help:    "Duration of GetItemsNeedingSync's query, by bucket size.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"bucket_size"}),
+	}
+
+	registry.MustRegister(m.copyDuration, m.copyRowsInserted, m.bucketBatchSeconds, m.syncQuerySeconds)
+	return m
+}
+
+// NewMetricsHandler returns an http.Handler serving this Repository's
+// metrics in the Prometheus text exposition format.
+func (r *Repository) NewMetricsHandler() http.Handler {
+	return promhttp.HandlerFor(r.metrics.registry, promhttp.HandlerOpts{})
+}
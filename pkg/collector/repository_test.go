@@ -1,6 +1,41 @@
 package collector
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketSizeForTable(t *testing.T) {
+	tests := []struct {
+		tableName string
+		want      string
+	}{
+		{"price_buckets_5m", "5m"},
+		{"price_buckets_1h", "1h"},
+		{"price_buckets_24h", "24h"},
+		{"price_buckets_9000m", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tableName, func(t *testing.T) {
+			got := bucketSizeForTable(tt.tableName)
+			if got != tt.want {
+				t.Errorf("bucketSizeForTable(%q) = %q, want %q", tt.tableName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_LogSlowQuery_RequiresLoggerAndThreshold(t *testing.T) {
+	r := NewRepository(nil)
+
+	// No logger and no threshold configured: must not panic.
+	r.logSlowQuery("SELECT 1", time.Hour)
+
+	r = NewRepository(nil, WithSlowQueryThreshold(10*time.Millisecond))
+	// Threshold set but no logger: still must not panic.
+	r.logSlowQuery("SELECT 1", time.Hour)
+}
 
 func TestBucketTableName(t *testing.T) {
 	tests := []struct {
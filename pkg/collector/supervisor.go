@@ -0,0 +1,196 @@
+package collector
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"osrs-flipping/pkg/logging"
+)
+
+// SupervisorConfig tunes Supervisor's health-check polling and restart backoff.
+type SupervisorConfig struct {
+	CheckInterval  time.Duration // How often to poll HealthCheck on every registered service (default: 30s)
+	InitialBackoff time.Duration // Wait after the first restart before another is allowed; the first restart itself is immediate (default: 5s)
+	MaxBackoff     time.Duration // Backoff ceiling, doubled on each consecutive restart (default: 5m)
+}
+
+// DefaultSupervisorConfig returns sensible defaults.
+func DefaultSupervisorConfig() *SupervisorConfig {
+	return &SupervisorConfig{
+		CheckInterval:  30 * time.Second,
+		InitialBackoff: 5 * time.Second,
+		MaxBackoff:     5 * time.Minute,
+	}
+}
+
+// restartState tracks one registered service's consecutive-restart count
+// and when it's next eligible to be restarted again.
+type restartState struct {
+	consecutiveRestarts int
+	nextRestart         time.Time
+}
+
+// Supervisor builds on ServiceRegistry (which already gives uniform
+// Start/Stop/Stats across a fixed set of services, in
+// registration/reverse-registration order) by adding the two pieces that
+// were still missing for cmd/collector to orchestrate Poller, VolumePoller,
+// Backfiller, and GapFiller as one unit: polling each service's
+// HealthCheck on an interval and restarting (Stop then Start) any that
+// report unhealthy, backing off exponentially per service so a service
+// stuck in a crash loop doesn't spin continuously; and one aggregated HTTP
+// status endpoint, where ServiceRegistry.Stats alone would need a caller
+// to assemble that by hand. Dependency order is still just registration
+// order, the same as ServiceRegistry -- this package's services don't
+// depend on each other at startup beyond "poller before volume_poller",
+// which registration order already expresses.
+type Supervisor struct {
+	registry *ServiceRegistry
+	config   *SupervisorConfig
+	logger   *logging.Logger
+
+	mu       sync.Mutex
+	restarts map[string]*restartState
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// NewSupervisor creates a Supervisor using config, or DefaultSupervisorConfig if nil.
+func NewSupervisor(config *SupervisorConfig, logger *logging.Logger) *Supervisor {
+	if config == nil {
+		config = DefaultSupervisorConfig()
+	}
+	return &Supervisor{
+		registry: NewServiceRegistry(),
+		config:   config,
+		logger:   logger,
+		restarts: make(map[string]*restartState),
+	}
+}
+
+// Register adds svc under name. Start starts services in registration
+// order; Stop and the restart loop both address services by this name.
+func (s *Supervisor) Register(name string, svc Service) {
+	s.registry.Register(name, svc)
+}
+
+// Start starts every registered service (via ServiceRegistry) and begins
+// the background health-check/restart loop.
+func (s *Supervisor) Start() {
+	s.registry.Start()
+
+	s.mu.Lock()
+	s.stopCh = make(chan struct{})
+	s.doneCh = make(chan struct{})
+	s.mu.Unlock()
+
+	go s.watch()
+}
+
+// Stop stops the health-check loop, then every registered service (via
+// ServiceRegistry, in reverse registration order).
+func (s *Supervisor) Stop() {
+	s.mu.Lock()
+	stopCh := s.stopCh
+	doneCh := s.doneCh
+	s.mu.Unlock()
+	if stopCh != nil {
+		close(stopCh)
+		<-doneCh
+	}
+	s.registry.Stop()
+}
+
+func (s *Supervisor) watch() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.checkAndRestart()
+		}
+	}
+}
+
+func (s *Supervisor) checkAndRestart() {
+	s.registry.mu.Lock()
+	services := append([]namedService(nil), s.registry.services...)
+	s.registry.mu.Unlock()
+
+	now := time.Now()
+	for _, ns := range services {
+		err := ns.svc.HealthCheck()
+		if err == nil {
+			s.mu.Lock()
+			delete(s.restarts, ns.name)
+			s.mu.Unlock()
+			continue
+		}
+		s.restartIfDue(ns, now, err)
+	}
+}
+
+func (s *Supervisor) restartIfDue(ns namedService, now time.Time, healthErr error) {
+	s.mu.Lock()
+	rs := s.restarts[ns.name]
+	if rs == nil {
+		rs = &restartState{}
+		s.restarts[ns.name] = rs
+	}
+	if now.Before(rs.nextRestart) {
+		s.mu.Unlock()
+		return
+	}
+	rs.consecutiveRestarts++
+	backoff := s.config.InitialBackoff << uint(rs.consecutiveRestarts-1)
+	if backoff <= 0 || backoff > s.config.MaxBackoff {
+		backoff = s.config.MaxBackoff
+	}
+	rs.nextRestart = now.Add(backoff)
+	consecutive := rs.consecutiveRestarts
+	s.mu.Unlock()
+
+	s.logger.WithComponent("supervisor").WithError(healthErr).WithFields(map[string]interface{}{
+		"service":              ns.name,
+		"consecutive_restarts": consecutive,
+		"backoff":              backoff.String(),
+	}).Warn("service unhealthy, restarting")
+
+	ns.svc.Stop()
+	ns.svc.Start()
+}
+
+// StatusHandler serves every registered service's Stats and HealthCheck
+// result as one aggregated JSON document, so operators have a single
+// endpoint to check instead of piecing one together from each service's
+// own debug/metrics surface.
+func (s *Supervisor) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.registry.mu.Lock()
+		services := append([]namedService(nil), s.registry.services...)
+		s.registry.mu.Unlock()
+
+		status := make(map[string]interface{}, len(services))
+		for _, ns := range services {
+			entry := map[string]interface{}{
+				"stats": ns.svc.Stats(),
+			}
+			if err := ns.svc.HealthCheck(); err != nil {
+				entry["healthy"] = false
+				entry["health_error"] = err.Error()
+			} else {
+				entry["healthy"] = true
+			}
+			status[ns.name] = entry
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+	})
+}
@@ -0,0 +1,174 @@
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeIndexEntry is one bucket's position in a TimeIndex.
+type TimeIndexEntry struct {
+	BucketStart time.Time
+	BucketSize  string
+}
+
+// TimeRange is a half-open [Start, End) span of missing bucket coverage.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// TimeIndex is a sorted-by-BucketStart index of the buckets Repository has
+// written for one item, across every bucket size. It lets GapFiller find
+// missing ranges with a binary search over buckets already in memory
+// instead of a per-item SQL scan -- the same role hotCache plays for
+// "most recent bucket" reads, but for gap detection.
+type TimeIndex struct {
+	mu      sync.RWMutex
+	entries []TimeIndexEntry // sorted by BucketStart
+}
+
+// NewTimeIndex creates an empty TimeIndex.
+func NewTimeIndex() *TimeIndex {
+	return &TimeIndex{}
+}
+
+// Insert adds entry, or replaces the existing entry for the same
+// (BucketStart, BucketSize), keeping entries sorted by BucketStart.
+func (ti *TimeIndex) Insert(entry TimeIndexEntry) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	ti.insertLocked(entry)
+}
+
+// InsertBuckets is Insert for every bucket in buckets, taken under a
+// single lock -- the path InsertPriceBuckets drives on every write.
+func (ti *TimeIndex) InsertBuckets(buckets []PriceBucket) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	for _, b := range buckets {
+		ti.insertLocked(TimeIndexEntry{BucketStart: b.BucketStart, BucketSize: b.BucketSize})
+	}
+}
+
+// entriesSnapshot returns a copy of ti's entries, for merging into another
+// TimeIndex (see Repository.LoadTimeIndex).
+func (ti *TimeIndex) entriesSnapshot() []TimeIndexEntry {
+	ti.mu.RLock()
+	defer ti.mu.RUnlock()
+	out := make([]TimeIndexEntry, len(ti.entries))
+	copy(out, ti.entries)
+	return out
+}
+
+func (ti *TimeIndex) insertLocked(entry TimeIndexEntry) {
+	i := sort.Search(len(ti.entries), func(i int) bool {
+		return !ti.entries[i].BucketStart.Before(entry.BucketStart)
+	})
+	for j := i; j < len(ti.entries) && ti.entries[j].BucketStart.Equal(entry.BucketStart); j++ {
+		if ti.entries[j].BucketSize == entry.BucketSize {
+			ti.entries[j] = entry
+			return
+		}
+	}
+	ti.entries = append(ti.entries, TimeIndexEntry{})
+	copy(ti.entries[i+1:], ti.entries[i:])
+	ti.entries[i] = entry
+}
+
+// IndexNear returns the index of the entry whose BucketStart is closest to
+// t: binary-search for t's insertion point, then compare the two entries
+// it falls between and pick the closer one. Returns -1 if ti is empty.
+func (ti *TimeIndex) IndexNear(t time.Time) int {
+	ti.mu.RLock()
+	defer ti.mu.RUnlock()
+
+	n := len(ti.entries)
+	if n == 0 {
+		return -1
+	}
+
+	i := sort.Search(n, func(i int) bool {
+		return !ti.entries[i].BucketStart.Before(t)
+	})
+	if i == 0 {
+		return 0
+	}
+	if i == n {
+		return n - 1
+	}
+	if t.Sub(ti.entries[i-1].BucketStart) <= ti.entries[i].BucketStart.Sub(t) {
+		return i - 1
+	}
+	return i
+}
+
+// Gaps walks bucketSize's entries within [from, to) in order and returns
+// every span between adjacent buckets whose delta exceeds interval. A
+// missing span at the very edge of [from, to) -- before the first indexed
+// bucket or after the last -- isn't reported, since the index only knows
+// about buckets it has actually seen; the caller's own retention window is
+// what establishes those outer bounds.
+func (ti *TimeIndex) Gaps(bucketSize string, interval time.Duration, from, to time.Time) []TimeRange {
+	ti.mu.RLock()
+	defer ti.mu.RUnlock()
+
+	var gaps []TimeRange
+	var prev time.Time
+	havePrev := false
+
+	for _, e := range ti.entries {
+		if e.BucketSize != bucketSize || e.BucketStart.Before(from) || !e.BucketStart.Before(to) {
+			continue
+		}
+		if havePrev && e.BucketStart.Sub(prev) > interval {
+			gaps = append(gaps, TimeRange{Start: prev.Add(interval), End: e.BucketStart})
+		}
+		prev = e.BucketStart
+		havePrev = true
+	}
+
+	return gaps
+}
+
+// Serialize encodes ti as a compact "bucketSize@unixSeconds" list, joined
+// by commas, for persistence via Repository.SetSyncMetadata.
+func (ti *TimeIndex) Serialize() string {
+	ti.mu.RLock()
+	defer ti.mu.RUnlock()
+
+	parts := make([]string, len(ti.entries))
+	for i, e := range ti.entries {
+		parts[i] = e.BucketSize + "@" + strconv.FormatInt(e.BucketStart.Unix(), 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// DeserializeTimeIndex parses Serialize's output back into a TimeIndex. An
+// empty string yields an empty index, so an item with no persisted
+// metadata yet loads cleanly.
+func DeserializeTimeIndex(s string) (*TimeIndex, error) {
+	ti := NewTimeIndex()
+	if s == "" {
+		return ti, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		at := strings.LastIndex(part, "@")
+		if at < 0 {
+			return nil, fmt.Errorf("malformed time index entry %q", part)
+		}
+		sec, err := strconv.ParseInt(part[at+1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed time index timestamp %q: %w", part, err)
+		}
+		ti.entries = append(ti.entries, TimeIndexEntry{
+			BucketSize:  part[:at],
+			BucketStart: time.Unix(sec, 0).UTC(),
+		})
+	}
+	return ti, nil
+}
@@ -0,0 +1,132 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeIndex_InsertKeepsSortedOrder(t *testing.T) {
+	base := time.Unix(1700000000, 0).UTC()
+	ti := NewTimeIndex()
+
+	ti.Insert(TimeIndexEntry{BucketStart: base.Add(10 * time.Minute), BucketSize: "5m"})
+	ti.Insert(TimeIndexEntry{BucketStart: base, BucketSize: "5m"})
+	ti.Insert(TimeIndexEntry{BucketStart: base.Add(5 * time.Minute), BucketSize: "5m"})
+
+	if len(ti.entries) != 3 {
+		t.Fatalf("len(entries) = %d, want 3", len(ti.entries))
+	}
+	for i := 1; i < len(ti.entries); i++ {
+		if ti.entries[i].BucketStart.Before(ti.entries[i-1].BucketStart) {
+			t.Fatalf("entries not sorted: %v before %v", ti.entries[i].BucketStart, ti.entries[i-1].BucketStart)
+		}
+	}
+}
+
+func TestTimeIndex_InsertReplacesSameBucket(t *testing.T) {
+	base := time.Unix(1700000000, 0).UTC()
+	ti := NewTimeIndex()
+
+	ti.Insert(TimeIndexEntry{BucketStart: base, BucketSize: "5m"})
+	ti.Insert(TimeIndexEntry{BucketStart: base, BucketSize: "5m"})
+
+	if len(ti.entries) != 1 {
+		t.Fatalf("len(entries) = %d, want 1 (duplicate should replace, not append)", len(ti.entries))
+	}
+}
+
+func TestTimeIndex_IndexNear(t *testing.T) {
+	base := time.Unix(1700000000, 0).UTC()
+	ti := NewTimeIndex()
+	ti.InsertBuckets([]PriceBucket{
+		{BucketStart: base, BucketSize: "5m"},
+		{BucketStart: base.Add(10 * time.Minute), BucketSize: "5m"},
+	})
+
+	if i := ti.IndexNear(base.Add(2 * time.Minute)); i != 0 {
+		t.Errorf("IndexNear(base+2m) = %d, want 0 (closer to base)", i)
+	}
+	if i := ti.IndexNear(base.Add(8 * time.Minute)); i != 1 {
+		t.Errorf("IndexNear(base+8m) = %d, want 1 (closer to base+10m)", i)
+	}
+}
+
+func TestTimeIndex_IndexNearEmpty(t *testing.T) {
+	ti := NewTimeIndex()
+	if i := ti.IndexNear(time.Now()); i != -1 {
+		t.Errorf("IndexNear on empty index = %d, want -1", i)
+	}
+}
+
+func TestTimeIndex_GapsFindsMissingSpan(t *testing.T) {
+	base := time.Unix(1700000000, 0).UTC()
+	ti := NewTimeIndex()
+	ti.InsertBuckets([]PriceBucket{
+		{BucketStart: base, BucketSize: "5m"},
+		{BucketStart: base.Add(5 * time.Minute), BucketSize: "5m"},
+		// base+10m is missing
+		{BucketStart: base.Add(15 * time.Minute), BucketSize: "5m"},
+	})
+
+	gaps := ti.Gaps("5m", 5*time.Minute, base.Add(-time.Hour), base.Add(time.Hour))
+	if len(gaps) != 1 {
+		t.Fatalf("len(gaps) = %d, want 1", len(gaps))
+	}
+	if !gaps[0].Start.Equal(base.Add(10*time.Minute)) || !gaps[0].End.Equal(base.Add(15*time.Minute)) {
+		t.Errorf("gaps[0] = %+v, want [base+10m, base+15m)", gaps[0])
+	}
+}
+
+func TestTimeIndex_GapsIgnoresOtherBucketSizes(t *testing.T) {
+	base := time.Unix(1700000000, 0).UTC()
+	ti := NewTimeIndex()
+	ti.InsertBuckets([]PriceBucket{
+		{BucketStart: base, BucketSize: "5m"},
+		{BucketStart: base.Add(5 * time.Minute), BucketSize: "1h"},
+		{BucketStart: base.Add(10 * time.Minute), BucketSize: "5m"},
+	})
+
+	gaps := ti.Gaps("5m", 5*time.Minute, base.Add(-time.Hour), base.Add(time.Hour))
+	if len(gaps) != 1 {
+		t.Fatalf("len(gaps) = %d, want 1 (the 1h entry shouldn't count as a 5m neighbor)", len(gaps))
+	}
+}
+
+func TestTimeIndex_SerializeRoundTrip(t *testing.T) {
+	base := time.Unix(1700000000, 0).UTC()
+	ti := NewTimeIndex()
+	ti.InsertBuckets([]PriceBucket{
+		{BucketStart: base, BucketSize: "5m"},
+		{BucketStart: base.Add(time.Hour), BucketSize: "1h"},
+	})
+
+	roundTripped, err := DeserializeTimeIndex(ti.Serialize())
+	if err != nil {
+		t.Fatalf("DeserializeTimeIndex returned error: %v", err)
+	}
+	if len(roundTripped.entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(roundTripped.entries))
+	}
+	for i, e := range roundTripped.entries {
+		want := ti.entries[i]
+		if !e.BucketStart.Equal(want.BucketStart) || e.BucketSize != want.BucketSize {
+			t.Errorf("entries[%d] = %+v, want %+v", i, e, want)
+		}
+	}
+}
+
+func TestDeserializeTimeIndex_Empty(t *testing.T) {
+	ti, err := DeserializeTimeIndex("")
+	if err != nil {
+		t.Fatalf("DeserializeTimeIndex(\"\") returned error: %v", err)
+	}
+	if len(ti.entries) != 0 {
+		t.Errorf("len(entries) = %d, want 0", len(ti.entries))
+	}
+}
+
+func TestDeserializeTimeIndex_Malformed(t *testing.T) {
+	if _, err := DeserializeTimeIndex("not-a-valid-entry"); err == nil {
+		t.Error("expected an error for a malformed entry")
+	}
+}
@@ -1,33 +1,39 @@
 package collector
 
 import (
+	"container/heap"
 	"context"
+	"encoding/json"
+	"math"
+	"net/http"
 	"sync"
 	"time"
 
-	"golang.org/x/time/rate"
-
 	"osrs-flipping/pkg/logging"
 	"osrs-flipping/pkg/osrs"
 )
 
+// signalLookback is how many trailing 5m buckets computeSignalScore
+// looks at to establish an item's volume/price baseline.
+const signalLookback = 12 // 1 hour of 5m buckets
+
 // VolumePollerConfig configures the volume polling service.
 type VolumePollerConfig struct {
-	PollInterval time.Duration // How often to poll (default: 5m)
-	RateLimit    time.Duration // Delay between API calls (default: 100ms)
-	RetryDelay   time.Duration // Delay between retries on failure (default: 10s)
-	MaxRetries   int           // Max consecutive failures before backing off (default: 5)
-	BackoffMax   time.Duration // Maximum backoff duration (default: 5m)
+	PollInterval    time.Duration // Interval assigned to an item with no signal history yet (default: 5m)
+	MinInterval     time.Duration // Floor an active item's interval shrinks to (default: 1m)
+	MaxInterval     time.Duration // Ceiling a quiet item's interval grows to (default: 30m)
+	SignalThreshold float64       // Score above which an item's interval shrinks toward MinInterval (default: 2.0)
+	CoalesceWindow  time.Duration // Items due within this window of the earliest due item are polled in the same batch (default: 10s)
 }
 
 // DefaultVolumePollerConfig returns sensible defaults.
 func DefaultVolumePollerConfig() *VolumePollerConfig {
 	return &VolumePollerConfig{
-		PollInterval: 5 * time.Minute,
-		RateLimit:    100 * time.Millisecond,
-		RetryDelay:   10 * time.Second,
-		MaxRetries:   5,
-		BackoffMax:   5 * time.Minute,
+		PollInterval:    5 * time.Minute,
+		MinInterval:     1 * time.Minute,
+		MaxInterval:     30 * time.Minute,
+		SignalThreshold: 2.0,
+		CoalesceWindow:  10 * time.Second,
 	}
 }
 
@@ -39,28 +45,88 @@ type VolumePollerProgress struct {
 	Errors          int
 	LastPollStart   time.Time
 	LastPollEnd     time.Time
+
+	// Effective interval stats across the current schedule, recomputed
+	// after every poll cycle; zero until the first cycle completes.
+	AvgEffectiveInterval time.Duration
+	MinEffectiveInterval time.Duration
+	MaxEffectiveInterval time.Duration
+}
+
+// pollEntry is one item's slot in VolumePoller's due-time min-heap:
+// when it's next due, and the interval it was scheduled at.
+type pollEntry struct {
+	itemID   int
+	dueAt    time.Time
+	interval time.Duration
+}
+
+// pollHeap is a container/heap.Interface ordering pollEntries by dueAt
+// ascending, so VolumePoller always knows the next item due without
+// scanning the whole schedule.
+type pollHeap []*pollEntry
+
+func (h pollHeap) Len() int           { return len(h) }
+func (h pollHeap) Less(i, j int) bool { return h[i].dueAt.Before(h[j].dueAt) }
+func (h pollHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *pollHeap) Push(x interface{}) {
+	*h = append(*h, x.(*pollEntry))
+}
+
+func (h *pollHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return entry
 }
 
 // VolumePoller polls 5m timeseries data for items with poll_volume=true.
 // This enables volume-based signal detection for high-priority items.
+// Rate limiting, retry backoff, and circuit breaking all now live in
+// osrs.Client itself (see pkg/osrs/ratelimiter.go and
+// pkg/osrs/circuit_breaker.go), shared across every caller, so
+// VolumePoller no longer keeps its own limiter or failure-backoff state.
+//
+// Items are scheduled adaptively rather than polled on one shared tick:
+// each item has its own next-due-time in a min-heap, shortened toward
+// MinInterval when its recent volume/price activity crosses
+// SignalThreshold and lengthened toward MaxInterval when it's quiet. A
+// single timer always wakes for the earliest due item, and items due
+// within CoalesceWindow of each other are polled together so the shared
+// rate limiter in osrs.Client sees batched requests instead of one at a
+// time.
 type VolumePoller struct {
-	client  *osrs.Client
-	repo    *Repository
-	config  *VolumePollerConfig
-	logger  *logging.Logger
-	limiter *rate.Limiter
+	client *osrs.Client
+	repo   *Repository
+	config *VolumePollerConfig
+	logger *logging.Logger
+	base   *BaseService
+	timer  *time.Timer
+
+	scheduleMu sync.Mutex
+	schedule   pollHeap
+	lastResync time.Time
 
 	mu               sync.Mutex
-	running          bool
-	stopCh           chan struct{}
-	doneCh           chan struct{}
 	progress         VolumePollerProgress
 	consecutiveFails int
+
+	metrics *CollectorMetrics
+}
+
+// SetMetrics wires m into v, so subsequent pollItem calls record
+// collector_poll_total/collector_poll_duration_seconds and related
+// collector_* series (see CollectorMetrics). Optional -- a VolumePoller
+// left without SetMetrics just skips recording.
+func (v *VolumePoller) SetMetrics(m *CollectorMetrics) {
+	v.metrics = m
 }
 
 // NewVolumePoller creates a new VolumePoller.
-// If limiter is nil, an internal rate limiter is created from config.RateLimit.
-func NewVolumePoller(client *osrs.Client, repo *Repository, config *VolumePollerConfig, logger *logging.Logger, limiter *rate.Limiter) *VolumePoller {
+func NewVolumePoller(client *osrs.Client, repo *Repository, config *VolumePollerConfig, logger *logging.Logger) *VolumePoller {
 	if config == nil {
 		config = DefaultVolumePollerConfig()
 	}
@@ -69,46 +135,31 @@ func NewVolumePoller(client *osrs.Client, repo *Repository, config *VolumePoller
 		logger = logging.NewLogger("error", "json")
 	}
 
-	if limiter == nil {
-		limiter = rate.NewLimiter(rate.Every(config.RateLimit), 1)
-	}
-
-	return &VolumePoller{
-		client:  client,
-		repo:    repo,
-		config:  config,
-		logger:  logger,
-		limiter: limiter,
+	v := &VolumePoller{
+		client: client,
+		repo:   repo,
+		config: config,
+		logger: logger,
 	}
+	v.base = NewBaseService("volume_poller", v, logger)
+	return v
 }
 
 // Start begins the polling loop in a goroutine.
 // Non-blocking - returns immediately.
 func (v *VolumePoller) Start() {
-	v.mu.Lock()
-	if v.running {
-		v.mu.Unlock()
-		return
-	}
-	v.running = true
-	v.stopCh = make(chan struct{})
-	v.doneCh = make(chan struct{})
-	v.mu.Unlock()
-
-	go v.run()
+	v.base.Start(context.Background())
 }
 
 // Stop signals the poller to stop and waits for it to finish.
 func (v *VolumePoller) Stop() {
-	v.mu.Lock()
-	if !v.running {
-		v.mu.Unlock()
-		return
-	}
-	v.mu.Unlock()
+	v.base.Stop()
+}
 
-	close(v.stopCh)
-	<-v.doneCh // Wait for run() to finish
+// HealthCheck reports the error that failed the most recent poll cycle,
+// nil if it hasn't failed.
+func (v *VolumePoller) HealthCheck() error {
+	return v.base.HealthCheck()
 }
 
 // Progress returns current polling progress.
@@ -120,101 +171,185 @@ func (v *VolumePoller) Progress() VolumePollerProgress {
 
 // Running returns whether the poller is currently running.
 func (v *VolumePoller) Running() bool {
-	v.mu.Lock()
-	defer v.mu.Unlock()
-	return v.running
+	return v.base.Running()
 }
 
-func (v *VolumePoller) run() {
-	defer func() {
-		v.mu.Lock()
-		v.running = false
-		v.mu.Unlock()
-		close(v.doneCh)
-	}()
-
+// OnStart implements ServiceImpl: it logs startup, seeds the schedule
+// from the current poll_volume item list, and arms the due-time timer.
+func (v *VolumePoller) OnStart(ctx context.Context) error {
 	v.logger.WithComponent("volume_poller").WithFields(map[string]interface{}{
-		"poll_interval": v.config.PollInterval.String(),
-		"rate_limit":    v.config.RateLimit.String(),
+		"poll_interval":    v.config.PollInterval.String(),
+		"min_interval":     v.config.MinInterval.String(),
+		"max_interval":     v.config.MaxInterval.String(),
+		"signal_threshold": v.config.SignalThreshold,
 	}).Info("starting volume poller")
 
-	// Run immediately on start
-	v.poll()
+	v.resyncSchedule(ctx)
+	v.rearmTimer()
+	return nil
+}
 
-	ticker := time.NewTicker(v.config.PollInterval)
-	defer ticker.Stop()
+// OnLoop implements ServiceImpl: it blocks until the next due item's
+// timer fires or ctx is canceled, polling whatever batch is due, then
+// rearming for the new earliest due-time. Returning ctx.Err() on
+// cancellation lets BaseService.run stop cleanly instead of busy-looping.
+func (v *VolumePoller) OnLoop(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-v.timer.C:
+		v.pollDue(ctx)
+		v.maybeResync(ctx)
+		v.rearmTimer()
+		return nil
+	}
+}
 
-	for {
-		select {
-		case <-v.stopCh:
-			v.logger.WithComponent("volume_poller").Info("volume poller stopped")
-			return
-		case <-ticker.C:
-			v.poll()
-		}
+// OnStop implements ServiceImpl.
+func (v *VolumePoller) OnStop() {
+	if v.timer != nil {
+		v.timer.Stop()
 	}
+	v.logger.WithComponent("volume_poller").Info("volume poller stopped")
 }
 
-func (v *VolumePoller) poll() {
-	// Guard against nil dependencies (for testing)
-	if v.repo == nil || v.client == nil {
+// resyncSchedule adds any poll_volume item not already in the schedule,
+// due immediately at VolumePoller.config.PollInterval. Existing entries
+// are left alone so an item mid-cycle doesn't lose its adapted interval.
+func (v *VolumePoller) resyncSchedule(ctx context.Context) {
+	if v.repo == nil {
 		return
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	items, err := v.repo.GetItemsToPollVolume(ctx)
+	if err != nil {
+		v.handleError(err)
+		return
+	}
 
-	// Allow cancellation via stopCh
-	go func() {
-		select {
-		case <-v.stopCh:
-			cancel()
-		case <-ctx.Done():
+	v.scheduleMu.Lock()
+	defer v.scheduleMu.Unlock()
+
+	known := make(map[int]bool, len(v.schedule))
+	for _, e := range v.schedule {
+		known[e.itemID] = true
+	}
+
+	now := time.Now()
+	for _, itemID := range items {
+		if known[itemID] {
+			continue
 		}
-	}()
+		heap.Push(&v.schedule, &pollEntry{itemID: itemID, dueAt: now, interval: v.config.PollInterval})
+	}
+	v.lastResync = now
+}
 
-	v.mu.Lock()
-	v.progress.LastPollStart = time.Now()
-	v.mu.Unlock()
+// maybeResync re-scans the poll_volume item list once MinInterval has
+// passed since the last scan, so newly-flagged items join the schedule
+// without every wake paying for a full item-list query.
+func (v *VolumePoller) maybeResync(ctx context.Context) {
+	v.scheduleMu.Lock()
+	stale := time.Since(v.lastResync) >= v.config.MinInterval
+	v.scheduleMu.Unlock()
 
-	// Get items to poll
-	items, err := v.repo.GetItemsToPollVolume(ctx)
-	if err != nil {
-		v.handleError(err)
+	if stale {
+		v.resyncSchedule(ctx)
+	}
+}
+
+// rearmTimer points v.timer at the schedule's earliest due-time, or
+// PollInterval out if the schedule is empty.
+func (v *VolumePoller) rearmTimer() {
+	v.scheduleMu.Lock()
+	wait := v.config.PollInterval
+	if len(v.schedule) > 0 {
+		wait = time.Until(v.schedule[0].dueAt)
+		if wait < 0 {
+			wait = 0
+		}
+	}
+	v.scheduleMu.Unlock()
+
+	if v.timer == nil {
+		v.timer = time.NewTimer(wait)
 		return
 	}
+	if !v.timer.Stop() {
+		select {
+		case <-v.timer.C:
+		default:
+		}
+	}
+	v.timer.Reset(wait)
+}
 
-	if len(items) == 0 {
-		v.logger.WithComponent("volume_poller").Debug("no items with poll_volume=true")
-		v.consecutiveFails = 0
+// pollDue pops every entry due within CoalesceWindow of the earliest due
+// item, polls them as one batch, and requeues each at its newly adapted
+// interval.
+func (v *VolumePoller) pollDue(ctx context.Context) {
+	if v.repo == nil || v.client == nil {
 		return
 	}
 
-	v.logger.WithComponent("volume_poller").WithField("items_count", len(items)).Debug("polling volume data")
+	v.scheduleMu.Lock()
+	if len(v.schedule) == 0 {
+		v.scheduleMu.Unlock()
+		return
+	}
+	cutoff := v.schedule[0].dueAt.Add(v.config.CoalesceWindow)
+	batch := make([]*pollEntry, 0, len(v.schedule))
+	for len(v.schedule) > 0 && !v.schedule[0].dueAt.After(cutoff) {
+		batch = append(batch, heap.Pop(&v.schedule).(*pollEntry))
+	}
+	v.scheduleMu.Unlock()
+
+	v.logger.WithComponent("volume_poller").WithField("items_count", len(batch)).Debug("polling volume data")
+
+	v.mu.Lock()
+	v.progress.LastPollStart = time.Now()
+	v.mu.Unlock()
 
 	var itemsPolled int64
 	var bucketsFilled int64
 	var errors int
 
-	for _, itemID := range items {
+	for _, entry := range batch {
 		select {
 		case <-ctx.Done():
-			return
+			v.requeue(entry)
+			continue
 		default:
 		}
 
-		filled, err := v.pollItem(ctx, itemID)
+		filled, err := v.pollItem(ctx, entry.itemID)
 		if err != nil {
-			v.logger.WithComponent("volume_poller").WithError(err).WithField("item_id", itemID).Warn("failed to poll item")
+			v.logger.WithComponent("volume_poller").WithError(err).WithField("item_id", entry.itemID).Warn("failed to poll item")
 			errors++
+			v.mu.Lock()
+			v.consecutiveFails++
+			v.mu.Unlock()
+			entry.dueAt = time.Now().Add(entry.interval)
+			v.requeue(entry)
 			continue
 		}
+		v.mu.Lock()
+		v.consecutiveFails = 0
+		v.mu.Unlock()
+
+		score, err := v.computeSignalScore(ctx, entry.itemID)
+		if err != nil {
+			v.logger.WithComponent("volume_poller").WithError(err).WithField("item_id", entry.itemID).Debug("failed to compute signal score")
+			score = 0
+		}
+		entry.interval = v.nextInterval(entry.interval, score)
+		entry.dueAt = time.Now().Add(entry.interval)
+		v.requeue(entry)
 
 		itemsPolled++
 		bucketsFilled += filled
 	}
 
-	// Update progress
 	v.mu.Lock()
 	v.progress.CyclesCompleted++
 	v.progress.ItemsPolled += itemsPolled
@@ -223,10 +358,14 @@ func (v *VolumePoller) poll() {
 	v.progress.LastPollEnd = time.Now()
 	duration := v.progress.LastPollEnd.Sub(v.progress.LastPollStart)
 	cycleNum := v.progress.CyclesCompleted
+	consecutiveFails := v.consecutiveFails
 	v.mu.Unlock()
 
-	// Reset failure counter on successful cycle
-	v.consecutiveFails = 0
+	if v.metrics != nil && v.client != nil {
+		v.metrics.RecordClientStats("5m", v.client.Stats(), consecutiveFails)
+	}
+
+	v.updateIntervalStats()
 
 	v.logger.WithComponent("volume_poller").WithFields(map[string]interface{}{
 		"cycle":          cycleNum,
@@ -237,13 +376,177 @@ func (v *VolumePoller) poll() {
 	}).Info("volume poll completed")
 }
 
-func (v *VolumePoller) pollItem(ctx context.Context, itemID int) (int64, error) {
-	// Wait for rate limiter
-	if err := v.limiter.Wait(ctx); err != nil {
+// requeue pushes entry back onto the schedule under lock.
+func (v *VolumePoller) requeue(e *pollEntry) {
+	v.scheduleMu.Lock()
+	heap.Push(&v.schedule, e)
+	v.scheduleMu.Unlock()
+}
+
+// nextInterval adapts current toward MinInterval when score crosses
+// SignalThreshold (an active item should be checked again soon) and
+// toward MaxInterval otherwise (a quiet item can wait longer).
+func (v *VolumePoller) nextInterval(current time.Duration, score float64) time.Duration {
+	if score >= v.config.SignalThreshold {
+		next := current / 2
+		if next < v.config.MinInterval {
+			next = v.config.MinInterval
+		}
+		return next
+	}
+
+	next := current * 3 / 2
+	if next > v.config.MaxInterval {
+		next = v.config.MaxInterval
+	}
+	return next
+}
+
+// updateIntervalStats recomputes Progress's average/min/max effective
+// interval from the current schedule.
+func (v *VolumePoller) updateIntervalStats() {
+	v.scheduleMu.Lock()
+	n := len(v.schedule)
+	var sum time.Duration
+	var min, max time.Duration
+	for i, e := range v.schedule {
+		sum += e.interval
+		if i == 0 || e.interval < min {
+			min = e.interval
+		}
+		if i == 0 || e.interval > max {
+			max = e.interval
+		}
+	}
+	v.scheduleMu.Unlock()
+
+	if n == 0 {
+		return
+	}
+
+	v.mu.Lock()
+	v.progress.AvgEffectiveInterval = sum / time.Duration(n)
+	v.progress.MinEffectiveInterval = min
+	v.progress.MaxEffectiveInterval = max
+	v.mu.Unlock()
+}
+
+// computeSignalScore scores itemID's most recent 5m bucket against its
+// trailing baseline: how far its volume sits above the recent mean, and
+// how many standard deviations its price sits from the recent mean. The
+// schedule shortens an item's interval when this score crosses
+// SignalThreshold and lengthens it otherwise.
+func (v *VolumePoller) computeSignalScore(ctx context.Context, itemID int) (float64, error) {
+	buckets, err := v.repo.GetRecentBuckets(ctx, itemID, "5m", signalLookback)
+	if err != nil {
 		return 0, err
 	}
+	if len(buckets) < 2 {
+		return 0, nil
+	}
+
+	// GetRecentBuckets orders newest-first.
+	latest := buckets[0]
+	history := buckets[1:]
+
+	volumeScore := volumeActivityScore(latest, history)
+	priceScore := priceZScore(latest, history)
+
+	return math.Max(volumeScore, math.Abs(priceScore)), nil
+}
+
+// bucketVolume sums b's high and low trade volume.
+func bucketVolume(b PriceBucket) int64 {
+	var total int64
+	if b.HighPriceVolume != nil {
+		total += *b.HighPriceVolume
+	}
+	if b.LowPriceVolume != nil {
+		total += *b.LowPriceVolume
+	}
+	return total
+}
+
+// volumeActivityScore is latest's volume divided by history's mean
+// volume, e.g. 3.0 means latest traded 3x the recent baseline. Returns 0
+// if history has no volume to compare against.
+func volumeActivityScore(latest PriceBucket, history []PriceBucket) float64 {
+	var sum int64
+	for _, b := range history {
+		sum += bucketVolume(b)
+	}
+	baseline := float64(sum) / float64(len(history))
+	if baseline == 0 {
+		return 0
+	}
+	return float64(bucketVolume(latest)) / baseline
+}
+
+// bucketPrice returns b's midpoint price, falling back to whichever side
+// is present, and false if neither AvgHighPrice nor AvgLowPrice is set.
+func bucketPrice(b PriceBucket) (float64, bool) {
+	switch {
+	case b.AvgHighPrice != nil && b.AvgLowPrice != nil:
+		return (float64(*b.AvgHighPrice) + float64(*b.AvgLowPrice)) / 2, true
+	case b.AvgHighPrice != nil:
+		return float64(*b.AvgHighPrice), true
+	case b.AvgLowPrice != nil:
+		return float64(*b.AvgLowPrice), true
+	default:
+		return 0, false
+	}
+}
+
+// priceZScore is the number of standard deviations latest's price sits
+// from history's mean price, 0 if there isn't enough price data or
+// history shows no variance.
+func priceZScore(latest PriceBucket, history []PriceBucket) float64 {
+	latestPrice, ok := bucketPrice(latest)
+	if !ok {
+		return 0
+	}
+
+	prices := make([]float64, 0, len(history))
+	for _, b := range history {
+		if p, ok := bucketPrice(b); ok {
+			prices = append(prices, p)
+		}
+	}
+	if len(prices) < 2 {
+		return 0
+	}
+
+	var sum float64
+	for _, p := range prices {
+		sum += p
+	}
+	mean := sum / float64(len(prices))
+
+	var variance float64
+	for _, p := range prices {
+		d := p - mean
+		variance += d * d
+	}
+	variance /= float64(len(prices))
 
-	// Fetch 5m timeseries from API
+	stddev := math.Sqrt(variance)
+	if stddev == 0 {
+		return 0
+	}
+	return (latestPrice - mean) / stddev
+}
+
+func (v *VolumePoller) pollItem(ctx context.Context, itemID int) (inserted int64, err error) {
+	if v.metrics != nil {
+		start := time.Now()
+		defer func() {
+			v.metrics.RecordPoll(err, time.Since(start))
+			v.metrics.RecordBucketsInserted("5m", "api", inserted)
+		}()
+	}
+
+	// Fetch 5m timeseries from API -- v.client rate-limits, retries, and
+	// circuit-breaks this call internally, so pollItem doesn't need to.
 	resp, err := v.client.GetTimeseriesTyped(ctx, itemID, "5m")
 	if err != nil {
 		return 0, err
@@ -309,33 +612,60 @@ func (v *VolumePoller) pollItem(ctx context.Context, itemID int) (int64, error)
 	return inserted, nil
 }
 
+// handleError logs a failure fetching the poll list itself. Per-item
+// failures (and the backoff/circuit-breaking response to repeated ones)
+// are v.client's job now, not VolumePoller's.
 func (v *VolumePoller) handleError(err error) {
-	v.consecutiveFails++
-
-	v.logger.WithComponent("volume_poller").WithError(err).WithField("consecutive_fails", v.consecutiveFails).Error("poll failed")
-
-	// Implement exponential backoff if too many failures
-	if v.consecutiveFails >= v.config.MaxRetries {
-		backoff := time.Duration(v.consecutiveFails-v.config.MaxRetries+1) * v.config.RetryDelay
-		if backoff > v.config.BackoffMax {
-			backoff = v.config.BackoffMax
-		}
-		v.logger.WithComponent("volume_poller").WithField("backoff", backoff).Warn("backing off due to repeated failures")
-		time.Sleep(backoff)
-	}
+	v.logger.WithComponent("volume_poller").WithError(err).Error("poll failed")
 }
 
-// Stats returns current poller statistics.
+// Stats returns current poller statistics, merging BaseService's
+// state/running/error fields, v.client's shared rate-limit/circuit-breaker
+// stats, and this poller's own progress counters.
 func (v *VolumePoller) Stats() map[string]interface{} {
 	v.mu.Lock()
-	defer v.mu.Unlock()
-	return map[string]interface{}{
-		"running":           v.running,
-		"consecutive_fails": v.consecutiveFails,
-		"cycles_completed":  v.progress.CyclesCompleted,
-		"items_polled":      v.progress.ItemsPolled,
-		"buckets_filled":    v.progress.BucketsFilled,
-		"errors":            v.progress.Errors,
-		"poll_interval":     v.config.PollInterval.String(),
+	progress := v.progress
+	v.mu.Unlock()
+
+	stats := v.base.Stats()
+	if v.client != nil {
+		clientStats := v.client.Stats()
+		stats["client_circuit_state"] = string(clientStats.CircuitState)
+		stats["client_throttled_count"] = clientStats.ThrottledCount
 	}
+	stats["cycles_completed"] = progress.CyclesCompleted
+	stats["items_polled"] = progress.ItemsPolled
+	stats["buckets_filled"] = progress.BucketsFilled
+	stats["errors"] = progress.Errors
+	stats["poll_interval"] = v.config.PollInterval.String()
+	stats["avg_effective_interval"] = progress.AvgEffectiveInterval.String()
+	stats["min_effective_interval"] = progress.MinEffectiveInterval.String()
+	stats["max_effective_interval"] = progress.MaxEffectiveInterval.String()
+	return stats
+}
+
+// debugEntry is one pollEntry's JSON representation for DebugHandler.
+type debugEntry struct {
+	ItemID   int       `json:"item_id"`
+	DueAt    time.Time `json:"due_at"`
+	Interval string    `json:"interval"`
+}
+
+// DebugHandler returns an http.Handler serving a JSON snapshot of the
+// current due-time heap, so an operator can see which items are about
+// to poll and how the adaptive scheduler has spread out their intervals.
+func (v *VolumePoller) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v.scheduleMu.Lock()
+		entries := make([]debugEntry, len(v.schedule))
+		for i, e := range v.schedule {
+			entries[i] = debugEntry{ItemID: e.itemID, DueAt: e.dueAt, Interval: e.interval.String()}
+		}
+		v.scheduleMu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(entries); err != nil {
+			http.Error(w, "encoding schedule snapshot: "+err.Error(), http.StatusInternalServerError)
+		}
+	})
 }
@@ -1,10 +1,9 @@
 package collector
 
 import (
+	"container/heap"
 	"testing"
 	"time"
-
-	"golang.org/x/time/rate"
 )
 
 func TestDefaultVolumePollerConfig(t *testing.T) {
@@ -13,52 +12,113 @@ func TestDefaultVolumePollerConfig(t *testing.T) {
 	if cfg.PollInterval != 5*time.Minute {
 		t.Errorf("PollInterval = %v, want 5m", cfg.PollInterval)
 	}
+	if cfg.MinInterval != time.Minute {
+		t.Errorf("MinInterval = %v, want 1m", cfg.MinInterval)
+	}
+	if cfg.MaxInterval != 30*time.Minute {
+		t.Errorf("MaxInterval = %v, want 30m", cfg.MaxInterval)
+	}
+}
+
+func TestPollHeap_OrdersByDueAt(t *testing.T) {
+	base := time.Unix(1700000000, 0).UTC()
+	h := &pollHeap{}
+	heap.Init(h)
+
+	heap.Push(h, &pollEntry{itemID: 1, dueAt: base.Add(10 * time.Minute)})
+	heap.Push(h, &pollEntry{itemID: 2, dueAt: base})
+	heap.Push(h, &pollEntry{itemID: 3, dueAt: base.Add(5 * time.Minute)})
+
+	var order []int
+	for h.Len() > 0 {
+		order = append(order, heap.Pop(h).(*pollEntry).itemID)
+	}
 
-	if cfg.RateLimit != 100*time.Millisecond {
-		t.Errorf("RateLimit = %v, want 100ms", cfg.RateLimit)
+	want := []int{2, 3, 1}
+	for i, id := range want {
+		if order[i] != id {
+			t.Errorf("order[%d] = %d, want %d (full order %v)", i, order[i], id, order)
+		}
 	}
+}
+
+func TestVolumePoller_NextInterval(t *testing.T) {
+	vp := NewVolumePoller(nil, nil, nil, nil)
 
-	if cfg.RetryDelay != 10*time.Second {
-		t.Errorf("RetryDelay = %v, want 10s", cfg.RetryDelay)
+	if got := vp.nextInterval(10*time.Minute, 5.0); got != 5*time.Minute {
+		t.Errorf("nextInterval(10m, 5.0) = %v, want 5m (halved toward the floor)", got)
+	}
+	if got := vp.nextInterval(time.Minute, 5.0); got != vp.config.MinInterval {
+		t.Errorf("nextInterval(1m, 5.0) = %v, want MinInterval %v", got, vp.config.MinInterval)
+	}
+	if got := vp.nextInterval(10*time.Minute, 0.1); got != 15*time.Minute {
+		t.Errorf("nextInterval(10m, 0.1) = %v, want 15m (grown toward the ceiling)", got)
 	}
+	if got := vp.nextInterval(25*time.Minute, 0.1); got != vp.config.MaxInterval {
+		t.Errorf("nextInterval(25m, 0.1) = %v, want MaxInterval %v", got, vp.config.MaxInterval)
+	}
+}
 
-	if cfg.MaxRetries != 5 {
-		t.Errorf("MaxRetries = %d, want 5", cfg.MaxRetries)
+func TestVolumeActivityScore(t *testing.T) {
+	highVol := int64(100)
+	lowVol := int64(0)
+	latest := PriceBucket{HighPriceVolume: &highVol, LowPriceVolume: &lowVol}
+
+	baselineVol := int64(25)
+	history := []PriceBucket{
+		{HighPriceVolume: &baselineVol},
+		{HighPriceVolume: &baselineVol},
 	}
 
-	if cfg.BackoffMax != 5*time.Minute {
-		t.Errorf("BackoffMax = %v, want 5m", cfg.BackoffMax)
+	if got := volumeActivityScore(latest, history); got != 4.0 {
+		t.Errorf("volumeActivityScore() = %v, want 4.0 (100 / mean(25))", got)
 	}
 }
 
-func TestNewVolumePoller_NilConfig(t *testing.T) {
-	vp := NewVolumePoller(nil, nil, nil, nil, nil)
+func TestVolumeActivityScore_NoBaseline(t *testing.T) {
+	latest := PriceBucket{}
+	history := []PriceBucket{{}, {}}
 
-	if vp.config == nil {
-		t.Fatal("config should not be nil when passed nil")
+	if got := volumeActivityScore(latest, history); got != 0 {
+		t.Errorf("volumeActivityScore() = %v, want 0 when history has no volume", got)
 	}
+}
 
-	if vp.config.PollInterval != 5*time.Minute {
-		t.Errorf("PollInterval = %v, want 5m", vp.config.PollInterval)
+func TestPriceZScore(t *testing.T) {
+	price := func(p int) PriceBucket {
+		v := p
+		return PriceBucket{AvgHighPrice: &v}
+	}
+
+	latest := price(130)
+	history := []PriceBucket{price(100), price(100), price(100), price(100)}
+
+	// history is constant (stddev 0), so a change should report 0 rather
+	// than dividing by zero.
+	if got := priceZScore(latest, history); got != 0 {
+		t.Errorf("priceZScore() = %v, want 0 when history has no variance", got)
 	}
 
-	if vp.limiter == nil {
-		t.Error("limiter should be created when passed nil")
+	varied := []PriceBucket{price(90), price(100), price(110), price(100)}
+	if got := priceZScore(latest, varied); got <= 0 {
+		t.Errorf("priceZScore() = %v, want > 0 for a price well above a varied history's mean", got)
 	}
 }
 
-func TestNewVolumePoller_ExternalLimiter(t *testing.T) {
-	externalLimiter := rate.NewLimiter(rate.Every(time.Millisecond), 1)
+func TestNewVolumePoller_NilConfig(t *testing.T) {
+	vp := NewVolumePoller(nil, nil, nil, nil)
 
-	vp := NewVolumePoller(nil, nil, nil, nil, externalLimiter)
+	if vp.config == nil {
+		t.Fatal("config should not be nil when passed nil")
+	}
 
-	if vp.limiter != externalLimiter {
-		t.Error("should use external limiter when provided")
+	if vp.config.PollInterval != 5*time.Minute {
+		t.Errorf("PollInterval = %v, want 5m", vp.config.PollInterval)
 	}
 }
 
 func TestVolumePollerProgress_Initial(t *testing.T) {
-	vp := NewVolumePoller(nil, nil, nil, nil, nil)
+	vp := NewVolumePoller(nil, nil, nil, nil)
 	progress := vp.Progress()
 
 	if progress.CyclesCompleted != 0 {
@@ -76,7 +136,7 @@ func TestVolumePollerProgress_Initial(t *testing.T) {
 }
 
 func TestVolumePoller_Running(t *testing.T) {
-	vp := NewVolumePoller(nil, nil, nil, nil, nil)
+	vp := NewVolumePoller(nil, nil, nil, nil)
 
 	if vp.Running() {
 		t.Error("should not be running initially")
@@ -84,15 +144,12 @@ func TestVolumePoller_Running(t *testing.T) {
 }
 
 func TestVolumePoller_Stats(t *testing.T) {
-	vp := NewVolumePoller(nil, nil, nil, nil, nil)
+	vp := NewVolumePoller(nil, nil, nil, nil)
 	stats := vp.Stats()
 
 	if stats["running"] != false {
 		t.Errorf("running = %v, want false", stats["running"])
 	}
-	if stats["consecutive_fails"] != 0 {
-		t.Errorf("consecutive_fails = %v, want 0", stats["consecutive_fails"])
-	}
 	if stats["cycles_completed"] != 0 {
 		t.Errorf("cycles_completed = %v, want 0", stats["cycles_completed"])
 	}
@@ -106,11 +163,7 @@ func TestVolumePoller_StartStop_NoOp(t *testing.T) {
 	// Use a long interval so it doesn't try to poll during test
 	vp := NewVolumePoller(nil, nil, &VolumePollerConfig{
 		PollInterval: time.Hour,
-		RateLimit:    time.Millisecond,
-		RetryDelay:   time.Second,
-		MaxRetries:   1,
-		BackoffMax:   time.Second,
-	}, nil, nil)
+	}, nil)
 
 	// Double start should be no-op
 	vp.Start()
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// WebhookObserver is a SyncObserver that POSTs each event as JSON to a
+// configured URL, retrying transient failures with the same jittered-backoff
+// approach fetchBulkPrices uses against the OSRS API.
+type WebhookObserver struct {
+	URL        string
+	Client     *http.Client
+	MaxRetries int
+	Backoff    time.Duration
+}
+
+// NewWebhookObserver creates a WebhookObserver posting to url, with sensible
+// retry defaults.
+func NewWebhookObserver(url string) *WebhookObserver {
+	return &WebhookObserver{
+		URL:        url,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+		MaxRetries: 3,
+		Backoff:    500 * time.Millisecond,
+	}
+}
+
+func (w *WebhookObserver) OnBucketsInserted(ctx context.Context, bucketSize string, ts time.Time, buckets []PriceBucket) {
+	w.post(ctx, SyncEvent{Kind: "buckets_inserted", BucketSize: bucketSize, Timestamp: ts, Buckets: buckets})
+}
+
+func (w *WebhookObserver) OnCycleComplete(ctx context.Context, progress BackgroundSyncProgress) {
+	w.post(ctx, SyncEvent{Kind: "cycle_complete", Progress: progress})
+}
+
+// post marshals event and attempts delivery once plus up to w.MaxRetries retries, giving
+// up silently on a persistent failure -- a webhook subscriber's outage isn't
+// allowed to affect the sync loop it's observing.
+func (w *WebhookObserver) post(ctx context.Context, event SyncEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	backoff := w.Backoff
+	for attempt := 0; attempt <= w.MaxRetries; attempt++ {
+		if w.attempt(ctx, body) {
+			return
+		}
+
+		if attempt == w.MaxRetries {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(jitteredDelay(backoff)):
+		}
+		backoff *= 2
+	}
+}
+
+// attempt makes one delivery attempt, reporting whether it succeeded.
+func (w *WebhookObserver) attempt(ctx context.Context, body []byte) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode < 500
+}
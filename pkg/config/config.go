@@ -19,35 +19,71 @@ type Config struct {
 	Logging   LoggingConfig    `yaml:"logging"`
 	Jobs      []JobConfig      `yaml:"jobs"`
 	Schedules []ScheduleConfig `yaml:"schedules,omitempty"`
+	Scheduler SchedulerConfig  `yaml:"scheduler,omitempty"`
+	Storage   StorageConfig    `yaml:"storage,omitempty"`
+}
+
+// StorageConfig configures pkg/storage's bucket-retention policy -- see
+// storage.LoadBucketRetentionFromConfig.
+type StorageConfig struct {
+	// BucketRetention overrides storage.BucketRetention per grain ("5m",
+	// "1h", "24h"); a grain left out of the YAML keeps its hardcoded
+	// default, and an explicit zero duration means unlimited retention for
+	// that grain.
+	BucketRetention map[string]Duration `yaml:"bucket_retention,omitempty"`
+}
+
+// SchedulerConfig tunes the scheduler package's shared rate limiter (see
+// scheduler.Scheduler.executeJob), which paces every job's executor call
+// against a single token bucket regardless of how many jobs fire at once.
+type SchedulerConfig struct {
+	APIRequestsPerSecond float64 `yaml:"api_requests_per_second,omitempty"`
+	APIBurst             int     `yaml:"api_burst,omitempty"`
 }
 
 // DiscordConfig holds Discord bot configuration
 type DiscordConfig struct {
-	Token     string `yaml:"token" env:"DISCORD_TOKEN"`
-	ChannelID string `yaml:"channel_id" env:"DISCORD_CHANNEL_ID"`
-	GuildID   string `yaml:"guild_id,omitempty" env:"DISCORD_GUILD_ID"`
+	Token     string `yaml:"token"`
+	ChannelID string `yaml:"channel_id"`
+	GuildID   string `yaml:"guild_id,omitempty"`
 }
 
 // LLMConfig holds LLM configuration
 type LLMConfig struct {
-	BaseURL string `yaml:"base_url" env:"LLM_BASE_URL"`
-	Model   string `yaml:"model" env:"LLM_MODEL"`
-	Timeout string `yaml:"timeout" env:"LLM_TIMEOUT"`
-	NumCtx  int    `yaml:"num_ctx" env:"LLM_NUM_CTX"`
+	BaseURL string   `yaml:"base_url"`
+	Model   string   `yaml:"model"`
+	Timeout Duration `yaml:"timeout"`
+	NumCtx  int      `yaml:"num_ctx"`
+
+	// Provider selects the llm.Provider backend ("ollama" or "openai").
+	// Empty means "ollama", matching llm.ModelConfig.Provider.
+	Provider string `yaml:"provider,omitempty"`
+
+	// APIKey is the bearer token sent to an "openai" provider. Ignored by
+	// "ollama".
+	APIKey string `yaml:"api_key,omitempty"`
 }
 
 // OSRSConfig holds OSRS API configuration
 type OSRSConfig struct {
-	UserAgent          string `yaml:"user_agent"`
-	MaxConcurrentCalls int    `yaml:"max_concurrent_calls"`
-	RateLimitDelayMs   int    `yaml:"rate_limit_delay_ms"`
-	VolumeDataMaxItems int    `yaml:"volume_data_max_items"`
+	UserAgent          string   `yaml:"user_agent"`
+	MaxConcurrentCalls int      `yaml:"max_concurrent_calls"`
+	RateLimitDelay     Duration `yaml:"rate_limit_delay"`
+	VolumeDataMaxItems int      `yaml:"volume_data_max_items"`
+	// ItemSource selects where collector.ItemSyncer reads the item catalog
+	// from: "wiki" (default), "file", or "mock".
+	ItemSource string `yaml:"item_source,omitempty"`
+	// ItemSourcePath is the file path read by ItemSource "file".
+	ItemSourcePath string `yaml:"item_source_path,omitempty"`
+	// ItemStore selects where collector.ItemSyncer writes the item catalog
+	// to: "sql" (default) or "memory".
+	ItemStore string `yaml:"item_store,omitempty"`
 }
 
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
-	Level  string `yaml:"level" env:"LOG_LEVEL"`
-	Format string `yaml:"format" env:"LOG_FORMAT"`
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
 }
 
 // JobConfig represents a trading analysis job
@@ -57,20 +93,100 @@ type JobConfig struct {
 	Filters     FilterConfig    `yaml:"filters"`
 	Output      OutputConfig    `yaml:"output,omitempty"`
 	Model       *JobModelConfig `yaml:"model,omitempty"`
+	Risk        *RiskConfig     `yaml:"risk,omitempty"`
+	Positions   *PositionConfig `yaml:"positions,omitempty"`
+	Retry       *RetryPolicy    `yaml:"retry,omitempty"`
 	Enabled     bool            `yaml:"enabled"`
+
+	// MaxConcurrent caps how many executions of this job can run at once,
+	// enforced by a semaphore keyed on job name (see
+	// scheduler.Scheduler.executeJob). Two cron ticks (or a cron tick
+	// overlapping a manual trigger) landing on the same heavy job block on
+	// each other instead of piling up. Zero or unset means 1.
+	MaxConcurrent int `yaml:"max_concurrent,omitempty"`
+
+	// Kind selects what running this job actually does. Empty (or
+	// "analysis") means the usual flip-analysis job; "exec" means Exec must
+	// be set and BotExecutor runs a command inside a container instead.
+	Kind string `yaml:"kind,omitempty"`
+
+	// Exec configures an "exec"-kind job. Ignored for analysis jobs.
+	Exec *ExecConfig `yaml:"exec,omitempty"`
+}
+
+// ExecConfig names a command to run inside an already-running container on
+// each trigger of an "exec"-kind JobConfig, e.g. for maintenance tasks
+// (DB vacuum, cache warmups, price-refresh scripts) that don't fit the
+// flip-analysis job shape.
+type ExecConfig struct {
+	Container string   `yaml:"container"`
+	Command   []string `yaml:"command"`
 }
 
 // JobModelConfig represents job-specific model configuration overrides
 type JobModelConfig struct {
-	Name        *string  `yaml:"name,omitempty"`
-	NumCtx      *int     `yaml:"num_ctx,omitempty"`
-	Temperature *float64 `yaml:"temperature,omitempty"`
-	TopK        *int     `yaml:"top_k,omitempty"`
-	TopP        *float64 `yaml:"top_p,omitempty"`
-	Seed        *int64   `yaml:"seed,omitempty"`
-	NumPredict  *int     `yaml:"num_predict,omitempty"`
-	NumGPU      *int     `yaml:"num_gpu,omitempty"`
-	Timeout     *string  `yaml:"timeout,omitempty"`
+	Name        *string   `yaml:"name,omitempty"`
+	NumCtx      *int      `yaml:"num_ctx,omitempty"`
+	Temperature *float64  `yaml:"temperature,omitempty"`
+	TopK        *int      `yaml:"top_k,omitempty"`
+	TopP        *float64  `yaml:"top_p,omitempty"`
+	Seed        *int64    `yaml:"seed,omitempty"`
+	NumPredict  *int      `yaml:"num_predict,omitempty"`
+	NumGPU      *int      `yaml:"num_gpu,omitempty"`
+	Timeout     *Duration `yaml:"timeout,omitempty"`
+
+	// Provider overrides the global LLM.Provider ("ollama" or "openai") in
+	// this job's ModelConfig, where it labels the LLM metrics. Every job still
+	// runs on the single backend built from the global LLM.Provider; this
+	// field does not switch it.
+	Provider *string `yaml:"provider,omitempty"`
+}
+
+// RiskConfig tunes the trailing-stop ladder and layered-entry plan attached
+// to each item's analysis (see osrs.ComputeTrailingLevels/ComputeEntryLayers).
+// Unset fields fall back to the osrs package defaults.
+type RiskConfig struct {
+	ActivationRatios []float64 `yaml:"activation_ratios,omitempty"`
+	CallbackRatios   []float64 `yaml:"callback_ratios,omitempty"`
+	EntryLayerCount  *int      `yaml:"entry_layer_count,omitempty"`
+}
+
+// PositionConfig tunes the persisted open-flip tracker and the exit-signal
+// thresholds Analyzer.EvaluateExitSignals checks them against (see
+// position.ExitConfig). Unset fields fall back to position.DefaultExitConfig.
+// StorePath defaults to defaultPositionStorePath if empty.
+type PositionConfig struct {
+	StorePath               string    `yaml:"store_path,omitempty"`
+	TrailingActivationRatio []float64 `yaml:"trailing_activation_ratio,omitempty"`
+	TrailingCallbackRate    []float64 `yaml:"trailing_callback_rate,omitempty"`
+	StopLossPct             *float64  `yaml:"stop_loss_pct,omitempty"`
+	TakeProfitFactor        *float64  `yaml:"take_profit_factor,omitempty"`
+
+	// StaleAfter is a time.ParseDuration string, matching JobModelConfig.Timeout.
+	StaleAfter string `yaml:"stale_after,omitempty"`
+
+	// PortfolioStorePath is where the job's portfolio.Portfolio event log
+	// persists (see osrs/portfolio.JSONStore). Defaults to
+	// defaultPortfolioStorePath if empty. Unrelated to StorePath above,
+	// which backs the simpler position.Store exit-signal tracker.
+	PortfolioStorePath string `yaml:"portfolio_store_path,omitempty"`
+
+	// MaxOpenGPPerItem, if set, tells Analyzer.getTopItemIDs to skip items
+	// the job's portfolio already holds at least this much open GP of (see
+	// osrs/portfolio.Portfolio.OpenGP).
+	MaxOpenGPPerItem *int `yaml:"max_open_gp_per_item,omitempty"`
+}
+
+// RetryPolicy configures exponential-backoff retries for a job's scheduled
+// execution (see scheduler.Scheduler.executeJob). Unset fields fall back to
+// the scheduler package defaults. InitialBackoff/MaxBackoff are
+// time.ParseDuration strings, matching JobModelConfig.Timeout.
+type RetryPolicy struct {
+	MaxAttempts    *int     `yaml:"max_attempts,omitempty"`
+	InitialBackoff *string  `yaml:"initial_backoff,omitempty"`
+	MaxBackoff     *string  `yaml:"max_backoff,omitempty"`
+	Multiplier     *float64 `yaml:"multiplier,omitempty"`
+	Jitter         *float64 `yaml:"jitter,omitempty"`
 }
 
 // FilterConfig holds all possible filter parameters
@@ -92,6 +208,33 @@ type FilterConfig struct {
 	InstaSellPriceMin    *int     `yaml:"insta_sell_price_min,omitempty"`
 	InstaSellPriceMax    *int     `yaml:"insta_sell_price_max,omitempty"`
 
+	// VolumeAction controls how Volume{20m,1h,24h}Min above are applied
+	// against buy-side vs sell-side volume: "combined" (default, sums both
+	// sides), "buy", "sell", "either", or "both" (see osrs.VolumeAction).
+	VolumeAction string `yaml:"volume_action,omitempty"`
+
+	// Per-side volume thresholds, independent of VolumeAction -- these
+	// catch e.g. an item with plenty of insta-sell volume but no buyers.
+	InstaBuyVolume20mMin  *float64 `yaml:"insta_buy_volume_20m_min,omitempty"`
+	InstaBuyVolume20mMax  *float64 `yaml:"insta_buy_volume_20m_max,omitempty"`
+	InstaSellVolume20mMin *float64 `yaml:"insta_sell_volume_20m_min,omitempty"`
+	InstaSellVolume20mMax *float64 `yaml:"insta_sell_volume_20m_max,omitempty"`
+	InstaBuyVolume1hMax   *float64 `yaml:"insta_buy_volume_1h_max,omitempty"`
+	InstaSellVolume1hMax  *float64 `yaml:"insta_sell_volume_1h_max,omitempty"`
+	InstaBuyVolume24hMin  *float64 `yaml:"insta_buy_volume_24h_min,omitempty"`
+	InstaBuyVolume24hMax  *float64 `yaml:"insta_buy_volume_24h_max,omitempty"`
+	InstaSellVolume24hMin *float64 `yaml:"insta_sell_volume_24h_min,omitempty"`
+	InstaSellVolume24hMax *float64 `yaml:"insta_sell_volume_24h_max,omitempty"`
+
+	// VolumeFilterMode controls how Buy/SellVolume{1h,24h}Min below
+	// combine: "total" (default, ignores them entirely), "both", "either",
+	// or "min_of_both_sides" (see osrs.VolumeFilterMode).
+	VolumeFilterMode string `yaml:"volume_filter_mode,omitempty"`
+	BuyVolume1hMin   *int   `yaml:"buy_volume_1h_min,omitempty"`
+	SellVolume1hMin  *int   `yaml:"sell_volume_1h_min,omitempty"`
+	BuyVolume24hMin  *int   `yaml:"buy_volume_24h_min,omitempty"`
+	SellVolume24hMin *int   `yaml:"sell_volume_24h_min,omitempty"`
+
 	// Buy limit filters
 	BuyLimitMin *int `yaml:"buy_limit_min,omitempty"`
 	BuyLimitMax *int `yaml:"buy_limit_max,omitempty"`
@@ -99,6 +242,30 @@ type FilterConfig struct {
 	// Freshness filters
 	MaxHoursSinceUpdate *float64 `yaml:"max_hours_since_update,omitempty"`
 
+	// Pivot-low breakout filters (see osrs.ClassifyPivotBreak)
+	RequirePivotBreak *bool    `yaml:"require_pivot_break,omitempty"`
+	PivotLength       *int     `yaml:"pivot_length,omitempty"`
+	BreakRatio        *float64 `yaml:"break_ratio,omitempty"`
+
+	// Diversity filters (see osrs.diversifyBySimilarity)
+	ExcludeSimilarTo *bool `yaml:"exclude_similar_to,omitempty"`
+
+	// Volatility/stability filters (see osrs.Analyzer.applyVolatilitySignal)
+	ATRPctMax            *float64 `yaml:"atr_pct_max,omitempty"`
+	MarginStddevMax      *float64 `yaml:"margin_stddev_max,omitempty"`
+	PriceEMADeviationMax *float64 `yaml:"price_ema_deviation_max,omitempty"`
+	MaxATRPctOfPrice     *float64 `yaml:"max_atr_pct_of_price,omitempty"`
+	MaxPriceStdDevPct    *float64 `yaml:"max_price_stddev_pct,omitempty"`
+
+	// Fisher Transform reversal screens (see osrs.VolumeMetrics.InstaBuyReversalSignal)
+	OnlyOversold   *bool `yaml:"only_oversold,omitempty"`
+	OnlyOverbought *bool `yaml:"only_overbought,omitempty"`
+
+	// Expr is an optional boolean expression checked against each item
+	// after the filters above (see osrs.FilterOptions.Expr/package
+	// osrs/expr for the supported bindings and indicator functions).
+	Expr string `yaml:"expr,omitempty"`
+
 	// Sorting and limiting
 	SortByAfterPrice  string `yaml:"sort_by_after_price,omitempty"`
 	SortByAfterVolume string `yaml:"sort_by_after_volume,omitempty"`
@@ -113,13 +280,25 @@ type OutputConfig struct {
 
 // ScheduleConfig defines when jobs should run
 type ScheduleConfig struct {
-	JobName  string `yaml:"job_name"`
-	Cron     string `yaml:"cron"`
+	JobName string `yaml:"job_name"`
+	Cron    string `yaml:"cron"`
+
+	// Timezone is an IANA zone name (e.g. "UTC", "Europe/London") the cron
+	// expression is evaluated in. Empty means the process's local time.
 	Timezone string `yaml:"timezone,omitempty"`
-	Enabled  bool   `yaml:"enabled"`
+
+	// Jitter is a time.ParseDuration string; each firing sleeps a random
+	// duration in [0, Jitter) before running the job, so schedules sharing
+	// a cron spec (e.g. several "0 */5 * * * *" jobs) don't all fire at
+	// once.
+	Jitter string `yaml:"jitter,omitempty"`
+
+	Enabled bool `yaml:"enabled"`
 }
 
-// LoadConfig loads configuration from file and environment variables
+// LoadConfig loads configuration from a YAML file at configPath, expanding
+// ${VAR}-style environment references in it along the way (see
+// interpolateEnv).
 func LoadConfig(configPath string) (*Config, error) {
 	// Start with minimal defaults (let YAML override)
 	config := &Config{
@@ -132,13 +311,17 @@ func LoadConfig(configPath string) (*Config, error) {
 		OSRS: OSRSConfig{
 			UserAgent:          "",
 			MaxConcurrentCalls: 3,
-			RateLimitDelayMs:   500,
+			RateLimitDelay:     Duration(500 * time.Millisecond),
 			VolumeDataMaxItems: 2500,
 		},
 		Logging: LoggingConfig{
 			Level:  "info",
 			Format: "json",
 		},
+		Scheduler: SchedulerConfig{
+			APIRequestsPerSecond: 1,
+			APIBurst:             1,
+		},
 	}
 
 	// Load from YAML file if it exists
@@ -150,9 +333,6 @@ func LoadConfig(configPath string) (*Config, error) {
 		}
 	}
 
-	// Override with environment variables
-	loadEnvironmentVariables(config)
-
 	// Validate required fields
 	if err := validateConfig(config); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -161,8 +341,12 @@ func LoadConfig(configPath string) (*Config, error) {
 	return config, nil
 }
 
-// loadYAMLFile loads configuration from a YAML file
-func loadYAMLFile(path string, config *Config) error {
+// loadYAMLFile reads path, expands environment variable references in its
+// raw contents via interpolateEnv, then unmarshals the result into out. out
+// need not be a *Config -- LoadStorageConfig uses this same helper to read
+// just the storage: section for callers that don't want the rest of
+// Config's (Discord/Jobs-oriented) validation.
+func loadYAMLFile(path string, out interface{}) error {
 	file, err := os.Open(path)
 	if err != nil {
 		return err
@@ -174,38 +358,35 @@ func loadYAMLFile(path string, config *Config) error {
 		return err
 	}
 
-	return yaml.Unmarshal(data, config)
+	data, err = interpolateEnv(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return yaml.Unmarshal(data, out)
 }
 
-// loadEnvironmentVariables overrides config with environment variables
-func loadEnvironmentVariables(config *Config) {
-	if token := os.Getenv("DISCORD_TOKEN"); token != "" {
-		config.Discord.Token = token
-	}
-	if channelID := os.Getenv("DISCORD_CHANNEL_ID"); channelID != "" {
-		config.Discord.ChannelID = channelID
+// LoadStorageConfig reads just the storage: section of the YAML file at
+// configPath (applying the same ${VAR} expansion as LoadConfig), for
+// callers like cmd/collector that want pkg/config's YAML file without
+// loading and validating the rest of Config's Discord/Jobs fields, which
+// don't apply to them. A missing file is not an error -- it returns a
+// zero-value StorageConfig, leaving storage.BucketRetention's hardcoded
+// defaults (and any BUCKET_RETENTION_* env override) untouched.
+func LoadStorageConfig(configPath string) (StorageConfig, error) {
+	var wrapper struct {
+		Storage StorageConfig `yaml:"storage"`
 	}
-	if guildID := os.Getenv("DISCORD_GUILD_ID"); guildID != "" {
-		config.Discord.GuildID = guildID
+	if configPath == "" {
+		return wrapper.Storage, nil
 	}
-	if baseURL := os.Getenv("LLM_BASE_URL"); baseURL != "" {
-		config.LLM.BaseURL = baseURL
-	}
-	if model := os.Getenv("LLM_MODEL"); model != "" {
-		config.LLM.Model = model
-	}
-	if timeout := os.Getenv("LLM_TIMEOUT"); timeout != "" {
-		config.LLM.Timeout = timeout
-	}
-	if level := os.Getenv("LOG_LEVEL"); level != "" {
-		config.Logging.Level = level
-	}
-	if format := os.Getenv("LOG_FORMAT"); format != "" {
-		config.Logging.Format = format
-	}
-	if userAgent := os.Getenv("OSRS_API_USER_AGENT"); userAgent != "" {
-		config.OSRS.UserAgent = userAgent
+	if err := loadYAMLFile(configPath, &wrapper); err != nil {
+		if os.IsNotExist(err) {
+			return wrapper.Storage, nil
+		}
+		return StorageConfig{}, fmt.Errorf("failed to load config file %s: %w", configPath, err)
 	}
+	return wrapper.Storage, nil
 }
 
 // validateConfig ensures required configuration is present
@@ -223,19 +404,30 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("at least one job must be configured")
 	}
 
+	for _, schedule := range config.Schedules {
+		if !schedule.Enabled {
+			continue
+		}
+		job := config.GetJobByName(schedule.JobName)
+		if job == nil {
+			return fmt.Errorf("schedule references unknown job %q", schedule.JobName)
+		}
+		if !job.Enabled {
+			return fmt.Errorf("schedule references disabled job %q", schedule.JobName)
+		}
+	}
+
 	return nil
 }
 
-// GetTimeout parses the LLM timeout string and returns a duration
+// GetTimeout returns the configured LLM timeout, defaulting to 5 minutes
+// when unset. c.Timeout is already a validated time.Duration by the time
+// LoadConfig returns, so there's no parse error to fall back on here.
 func (c *LLMConfig) GetTimeout() time.Duration {
-	if c.Timeout == "" {
+	if c.Timeout == 0 {
 		return 5 * time.Minute // default when not specified
 	}
-	duration, err := time.ParseDuration(c.Timeout)
-	if err != nil {
-		return 5 * time.Minute // default on parse error
-	}
-	return duration
+	return c.Timeout.Dur()
 }
 
 // GetMaxConcurrentCalls returns the max concurrent API calls with bounds checking
@@ -249,12 +441,12 @@ func (c *OSRSConfig) GetMaxConcurrentCalls() int {
 	return c.MaxConcurrentCalls
 }
 
-// GetRateLimitDelay returns the rate limit delay as a duration
+// GetRateLimitDelay returns the rate limit delay, with a 100ms floor
 func (c *OSRSConfig) GetRateLimitDelay() time.Duration {
-	if c.RateLimitDelayMs < 100 {
+	if c.RateLimitDelay.Dur() < 100*time.Millisecond {
 		return 100 * time.Millisecond
 	}
-	return time.Duration(c.RateLimitDelayMs) * time.Millisecond
+	return c.RateLimitDelay.Dur()
 }
 
 // GetJobModelConfig returns the effective model configuration for a job,
@@ -273,11 +465,18 @@ func (j *JobConfig) GetJobModelConfig(globalLLM *LLMConfig) llm.ModelConfig {
 		modelConfig = llm.CreateDefaultModelConfig("qwen3:14b")
 	}
 
+	if globalLLM != nil && globalLLM.Provider != "" {
+		modelConfig.Provider = globalLLM.Provider
+	}
+
 	// Apply job-specific overrides if they exist
 	if j.Model != nil {
 		if j.Model.Name != nil {
 			modelConfig.Name = *j.Model.Name
 		}
+		if j.Model.Provider != nil {
+			modelConfig.Provider = *j.Model.Provider
+		}
 		if j.Model.NumCtx != nil {
 			modelConfig.Options.NumCtx = *j.Model.NumCtx
 		}
@@ -309,9 +508,7 @@ func (j *JobConfig) GetJobModelConfig(globalLLM *LLMConfig) llm.ModelConfig {
 func (j *JobConfig) GetJobTimeout(globalLLM *LLMConfig) time.Duration {
 	// Check job-specific timeout override first
 	if j.Model != nil && j.Model.Timeout != nil {
-		if duration, err := time.ParseDuration(*j.Model.Timeout); err == nil {
-			return duration
-		}
+		return j.Model.Timeout.Dur()
 	}
 
 	// Fall back to global LLM timeout
@@ -9,7 +9,7 @@ func TestJobModelConfigOverride(t *testing.T) {
 	// Create a test configuration
 	globalLLM := &LLMConfig{
 		Model:   "qwen3:14b",
-		Timeout: "10m",
+		Timeout: Duration(10 * time.Minute),
 	}
 
 	// Test job with model overrides
@@ -19,7 +19,7 @@ func TestJobModelConfigOverride(t *testing.T) {
 			Name:        stringPtr("qwen3:4b"),
 			NumCtx:      intPtr(24000),
 			Temperature: float64Ptr(0.7),
-			Timeout:     stringPtr("5m"),
+			Timeout:     durationPtr(5 * time.Minute),
 		},
 	}
 
@@ -46,7 +46,7 @@ func TestJobModelConfigOverride(t *testing.T) {
 func TestJobWithoutModelOverride(t *testing.T) {
 	globalLLM := &LLMConfig{
 		Model:   "qwen3:14b",
-		Timeout: "10m",
+		Timeout: Duration(10 * time.Minute),
 	}
 
 	// Job without model config should use global config
@@ -90,6 +90,51 @@ func TestGetJobByName(t *testing.T) {
 	}
 }
 
+func validBaseConfig() *Config {
+	return &Config{
+		OSRS:    OSRSConfig{UserAgent: "test-agent"},
+		Discord: DiscordConfig{Token: "token", ChannelID: "channel"},
+		Jobs:    []JobConfig{{Name: "Job 1", Enabled: true}},
+	}
+}
+
+func TestValidateConfigRejectsScheduleForUnknownJob(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Schedules = []ScheduleConfig{{JobName: "Missing Job", Cron: "0 * * * * *", Enabled: true}}
+
+	if err := validateConfig(cfg); err == nil {
+		t.Error("expected an error for a schedule referencing an unknown job")
+	}
+}
+
+func TestValidateConfigRejectsScheduleForDisabledJob(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Jobs = []JobConfig{{Name: "Job 1", Enabled: false}}
+	cfg.Schedules = []ScheduleConfig{{JobName: "Job 1", Cron: "0 * * * * *", Enabled: true}}
+
+	if err := validateConfig(cfg); err == nil {
+		t.Error("expected an error for a schedule referencing a disabled job")
+	}
+}
+
+func TestValidateConfigAcceptsValidSchedule(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Schedules = []ScheduleConfig{{JobName: "Job 1", Cron: "0 * * * * *", Enabled: true}}
+
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("expected a schedule referencing an enabled job to pass validation, got: %v", err)
+	}
+}
+
+func TestValidateConfigIgnoresDisabledSchedule(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Schedules = []ScheduleConfig{{JobName: "Missing Job", Cron: "0 * * * * *", Enabled: false}}
+
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("expected a disabled schedule to be skipped, got: %v", err)
+	}
+}
+
 // Helper functions for creating pointers
 func stringPtr(s string) *string {
 	return &s
@@ -102,3 +147,8 @@ func intPtr(i int) *int {
 func float64Ptr(f float64) *float64 {
 	return &f
 }
+
+func durationPtr(d time.Duration) *Duration {
+	dur := Duration(d)
+	return &dur
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration wraps time.Duration so it can be written as a human-readable
+// string in YAML (e.g. "5m") instead of yaml.v3's default integer
+// nanoseconds, and so a malformed value fails LoadConfig immediately
+// instead of silently falling back to a default deep inside GetTimeout /
+// GetJobTimeout.
+type Duration time.Duration
+
+// Dur returns d as a time.Duration.
+func (d Duration) Dur() time.Duration {
+	return time.Duration(d)
+}
+
+// String implements fmt.Stringer so Duration prints the same way a plain
+// time.Duration would.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// UnmarshalYAML parses a time.ParseDuration-style string (e.g. "5m",
+// "30s") into d. An empty or absent value decodes to zero.
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return fmt.Errorf("duration must be a string (e.g. \"5m\"): %w", err)
+	}
+	if s == "" {
+		*d = 0
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalYAML renders d the same way UnmarshalYAML reads it, so config
+// round-trips through YAML unchanged.
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDurationUnmarshalYAML(t *testing.T) {
+	var d Duration
+	if err := yaml.Unmarshal([]byte(`"5m"`), &d); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if d.Dur() != 5*time.Minute {
+		t.Errorf("expected 5m, got %v", d.Dur())
+	}
+}
+
+func TestDurationUnmarshalYAML_Empty(t *testing.T) {
+	var d Duration
+	if err := yaml.Unmarshal([]byte(`""`), &d); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if d.Dur() != 0 {
+		t.Errorf("expected zero duration, got %v", d.Dur())
+	}
+}
+
+func TestDurationUnmarshalYAML_Invalid(t *testing.T) {
+	var d Duration
+	if err := yaml.Unmarshal([]byte(`"not-a-duration"`), &d); err == nil {
+		t.Error("expected an error for a malformed duration string")
+	}
+}
+
+func TestDurationMarshalYAML_RoundTrip(t *testing.T) {
+	d := Duration(90 * time.Second)
+
+	out, err := yaml.Marshal(d)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var roundTripped Duration
+	if err := yaml.Unmarshal(out, &roundTripped); err != nil {
+		t.Fatalf("unmarshal round-tripped value: %v", err)
+	}
+	if roundTripped != d {
+		t.Errorf("expected %v after round-trip, got %v", d, roundTripped)
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	d := Duration(5 * time.Minute)
+	if d.String() != "5m0s" {
+		t.Errorf("expected %q, got %q", "5m0s", d.String())
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 )
 
 // LoadConfigForMain loads configuration for main program (without Discord validation)
@@ -17,7 +18,7 @@ func LoadConfigForMain(configPath string) (*Config, error) {
 		OSRS: OSRSConfig{
 			UserAgent:          "",
 			MaxConcurrentCalls: 3,
-			RateLimitDelayMs:   500,
+			RateLimitDelay:     Duration(500 * time.Millisecond),
 			VolumeDataMaxItems: 50,
 		},
 		Logging: LoggingConfig{
@@ -35,9 +36,6 @@ func LoadConfigForMain(configPath string) (*Config, error) {
 		}
 	}
 
-	// Override with environment variables
-	loadEnvironmentVariables(config)
-
 	// Validate only what's needed for main program
 	if err := validateMainConfig(config); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// interpolateEnv expands docker-compose style variable references in raw
+// YAML bytes before they're unmarshaled, so any field in the file -- not
+// just the handful loadEnvironmentVariables used to special-case -- can be
+// templated from the environment:
+//
+//	${NAME}          -> os.Getenv("NAME"), or "" if unset
+//	${NAME:-default} -> os.Getenv("NAME"), or "default" if unset or empty
+//	${NAME:?message} -> os.Getenv("NAME"), or an error containing message if unset or empty
+//	$$               -> a literal "$", escaping interpolation entirely
+//
+// An error from a ${NAME:?message} reference names the 1-indexed line it
+// appears on, for file/line context in the wrapping LoadConfig error.
+func interpolateEnv(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	line := 1
+
+	for i := 0; i < len(data); {
+		switch {
+		case data[i] == '\n':
+			out.WriteByte(data[i])
+			line++
+			i++
+		case data[i] == '$' && i+1 < len(data) && data[i+1] == '$':
+			out.WriteByte('$')
+			i += 2
+		case data[i] == '$' && i+1 < len(data) && data[i+1] == '{':
+			end := bytes.IndexByte(data[i+2:], '}')
+			if end == -1 {
+				return nil, fmt.Errorf("line %d: unterminated %q reference", line, "${")
+			}
+			value, err := resolveVarRef(string(data[i+2:i+2+end]), line)
+			if err != nil {
+				return nil, err
+			}
+			out.WriteString(value)
+			i += 2 + end + 1
+		default:
+			out.WriteByte(data[i])
+			i++
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+// resolveVarRef resolves the inside of a ${...} reference, one of
+// "NAME", "NAME:-default", or "NAME:?message".
+func resolveVarRef(ref string, line int) (string, error) {
+	name, op, arg := ref, "", ""
+	if at := strings.Index(ref, ":-"); at != -1 {
+		name, op, arg = ref[:at], ":-", ref[at+2:]
+	} else if at := strings.Index(ref, ":?"); at != -1 {
+		name, op, arg = ref[:at], ":?", ref[at+2:]
+	}
+
+	if value := os.Getenv(name); value != "" {
+		return value, nil
+	}
+
+	switch op {
+	case ":-":
+		return arg, nil
+	case ":?":
+		message := arg
+		if message == "" {
+			message = "variable not set"
+		}
+		return "", fmt.Errorf("line %d: ${%s}: %s", line, ref, message)
+	default:
+		return "", nil
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig_InterpolatesEnv(t *testing.T) {
+	os.Setenv("INTERPOLATE_TEST_USER_AGENT", "my-bot/1.0")
+	defer os.Unsetenv("INTERPOLATE_TEST_USER_AGENT")
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	body := `
+osrs:
+  user_agent: ${INTERPOLATE_TEST_USER_AGENT}
+discord:
+  token: tok
+  channel_id: chan
+jobs:
+  - name: Job 1
+    enabled: true
+`
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatalf("write temp config: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.OSRS.UserAgent != "my-bot/1.0" {
+		t.Errorf("expected interpolated user agent %q, got %q", "my-bot/1.0", cfg.OSRS.UserAgent)
+	}
+}
+
+func TestInterpolateEnv_Plain(t *testing.T) {
+	os.Setenv("INTERPOLATE_TEST_VAR", "hello")
+	defer os.Unsetenv("INTERPOLATE_TEST_VAR")
+
+	got, err := interpolateEnv([]byte("value: ${INTERPOLATE_TEST_VAR}"))
+	if err != nil {
+		t.Fatalf("interpolateEnv: %v", err)
+	}
+	if string(got) != "value: hello" {
+		t.Errorf("expected %q, got %q", "value: hello", got)
+	}
+}
+
+func TestInterpolateEnv_Unset(t *testing.T) {
+	os.Unsetenv("INTERPOLATE_TEST_MISSING")
+
+	got, err := interpolateEnv([]byte("value: ${INTERPOLATE_TEST_MISSING}"))
+	if err != nil {
+		t.Fatalf("interpolateEnv: %v", err)
+	}
+	if string(got) != "value: " {
+		t.Errorf("expected %q, got %q", "value: ", got)
+	}
+}
+
+func TestInterpolateEnv_Default(t *testing.T) {
+	os.Unsetenv("INTERPOLATE_TEST_MISSING")
+
+	got, err := interpolateEnv([]byte("value: ${INTERPOLATE_TEST_MISSING:-fallback}"))
+	if err != nil {
+		t.Fatalf("interpolateEnv: %v", err)
+	}
+	if string(got) != "value: fallback" {
+		t.Errorf("expected %q, got %q", "value: fallback", got)
+	}
+}
+
+func TestInterpolateEnv_DefaultIgnoredWhenSet(t *testing.T) {
+	os.Setenv("INTERPOLATE_TEST_VAR", "set-value")
+	defer os.Unsetenv("INTERPOLATE_TEST_VAR")
+
+	got, err := interpolateEnv([]byte("value: ${INTERPOLATE_TEST_VAR:-fallback}"))
+	if err != nil {
+		t.Fatalf("interpolateEnv: %v", err)
+	}
+	if string(got) != "value: set-value" {
+		t.Errorf("expected %q, got %q", "value: set-value", got)
+	}
+}
+
+func TestInterpolateEnv_RequiredMissing(t *testing.T) {
+	os.Unsetenv("INTERPOLATE_TEST_MISSING")
+
+	_, err := interpolateEnv([]byte("line one\nvalue: ${INTERPOLATE_TEST_MISSING:?must be set}"))
+	if err == nil {
+		t.Fatal("expected an error for a missing required variable")
+	}
+	if !containsAll(err.Error(), "line 2", "must be set") {
+		t.Errorf("expected error to mention line 2 and the message, got %q", err)
+	}
+}
+
+func TestInterpolateEnv_EscapedDollar(t *testing.T) {
+	got, err := interpolateEnv([]byte("value: $${literal}"))
+	if err != nil {
+		t.Fatalf("interpolateEnv: %v", err)
+	}
+	if string(got) != "value: ${literal}" {
+		t.Errorf("expected %q, got %q", "value: ${literal}", got)
+	}
+}
+
+func TestInterpolateEnv_Unterminated(t *testing.T) {
+	if _, err := interpolateEnv([]byte("value: ${UNCLOSED")); err == nil {
+		t.Error("expected an error for an unterminated ${ reference")
+	}
+}
+
+func containsAll(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"osrs-flipping/pkg/logging"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ReloadFunc is called after a Watcher successfully reloads its config
+// file, with the config in effect before and after the swap, so a
+// consumer can react to whatever changed (e.g. reschedule a cron entry
+// whose expression moved, or resize a semaphore).
+type ReloadFunc func(old, new *Config)
+
+// Watcher holds the config currently in effect behind an atomic pointer,
+// re-reading it on SIGHUP or a file-system change and swapping it in once
+// it passes validateConfig. A failed reload is logged and discarded -- the
+// previously loaded config stays live, so a bad edit never takes the
+// process down mid-run.
+type Watcher struct {
+	path    string
+	logger  *logging.Logger
+	current atomic.Pointer[Config]
+
+	mu        sync.Mutex
+	callbacks []ReloadFunc
+}
+
+// Watch starts hot-reloading c from path: a SIGHUP, or a write/create/
+// rename event on path, triggers a re-read via LoadConfig, which re-applies
+// env overrides and validateConfig the same way startup does. The watch
+// loop runs in a background goroutine until ctx is canceled.
+func (c *Config) Watch(ctx context.Context, path string, logger *logging.Logger) (*Watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create config file watcher: %w", err)
+	}
+	if err := fsWatcher.Add(path); err != nil {
+		fsWatcher.Close()
+		return nil, fmt.Errorf("watch config file %s: %w", path, err)
+	}
+
+	w := &Watcher{path: path, logger: logger}
+	w.current.Store(c)
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	go func() {
+		defer fsWatcher.Close()
+		defer signal.Stop(sighup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sighup:
+				w.logger.WithComponent("config").Info("Received SIGHUP, reloading config")
+				w.reload()
+			case event, ok := <-fsWatcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+					w.logger.WithComponent("config").WithField("event", event.String()).Info("Config file changed, reloading")
+					w.reload()
+				}
+			case err, ok := <-fsWatcher.Errors:
+				if !ok {
+					return
+				}
+				w.logger.WithComponent("config").WithError(err).Error("Config file watcher error")
+			}
+		}
+	}()
+
+	return w, nil
+}
+
+// Current returns the config currently in effect.
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// OnReload registers fn to run after every successful reload, with the
+// config from before and after the swap. fn runs synchronously on the
+// watch goroutine, so it should return quickly (e.g. signal its own
+// channel) rather than block.
+func (w *Watcher) OnReload(fn ReloadFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callbacks = append(w.callbacks, fn)
+}
+
+// reload re-reads w.path the same way LoadConfig does and swaps it in on
+// success, running every registered callback. A parse or validation
+// failure is logged and the previously loaded config stays live.
+func (w *Watcher) reload() {
+	next, err := LoadConfig(w.path)
+	if err != nil {
+		w.logger.WithComponent("config").WithError(err).Error("Config reload failed, keeping previous config")
+		return
+	}
+
+	old := w.current.Swap(next)
+
+	w.mu.Lock()
+	callbacks := make([]ReloadFunc, len(w.callbacks))
+	copy(callbacks, w.callbacks)
+	w.mu.Unlock()
+
+	for _, cb := range callbacks {
+		cb(old, next)
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"osrs-flipping/pkg/logging"
+)
+
+const validYAMLConfig = `
+osrs:
+  user_agent: test-agent
+discord:
+  token: tok
+  channel_id: chan
+jobs:
+  - name: Job 1
+    enabled: true
+`
+
+func writeTempConfig(t *testing.T, dir, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, "config.yml")
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatalf("write temp config: %v", err)
+	}
+	return path
+}
+
+func TestWatcherReloadSwapsConfigAndRunsCallback(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTempConfig(t, dir, validYAMLConfig)
+
+	initial, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	w := &Watcher{path: path, logger: logging.NewLogger("error", "text")}
+	w.current.Store(initial)
+
+	var gotOld, gotNew *Config
+	w.OnReload(func(old, new *Config) {
+		gotOld, gotNew = old, new
+	})
+
+	// Change something observable (log level) and reload.
+	writeTempConfig(t, dir, validYAMLConfig+"logging:\n  level: debug\n")
+	w.reload()
+
+	if w.Current().Logging.Level != "debug" {
+		t.Errorf("expected reloaded config to have log level %q, got %q", "debug", w.Current().Logging.Level)
+	}
+	if gotOld != initial {
+		t.Error("expected the callback's old config to be the original config")
+	}
+	if gotNew != w.Current() {
+		t.Error("expected the callback's new config to match Current()")
+	}
+}
+
+func TestWatcherReloadKeepsOldConfigOnValidationFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTempConfig(t, dir, validYAMLConfig)
+
+	initial, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	w := &Watcher{path: path, logger: logging.NewLogger("error", "text")}
+	w.current.Store(initial)
+
+	called := false
+	w.OnReload(func(old, new *Config) { called = true })
+
+	// Drop the required user_agent so the reload fails validateConfig.
+	writeTempConfig(t, dir, `
+discord:
+  token: tok
+  channel_id: chan
+jobs:
+  - name: Job 1
+    enabled: true
+`)
+	w.reload()
+
+	if w.Current() != initial {
+		t.Error("expected a failed reload to leave the previous config in place")
+	}
+	if called {
+		t.Error("expected OnReload callbacks not to run on a failed reload")
+	}
+}
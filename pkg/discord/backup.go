@@ -0,0 +1,322 @@
+package discord
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// backupManifestName is the fixed entry name used to identify and version
+// archives produced by BackupExport, so BackupImport can reject unrelated
+// zip files before touching config or the database.
+const backupManifestName = "manifest.json"
+
+// backupManifestVersion is bumped whenever the archive layout changes.
+const backupManifestVersion = 1
+
+// backupManifest describes the contents of a backup archive.
+type backupManifest struct {
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	ConfigFile  string    `json:"config_file"`
+	ResultFiles []string  `json:"result_files"`
+	HasSnapshot bool      `json:"has_snapshot"`
+}
+
+// DBSnapshotter produces and restores a JSON snapshot of the jobs/results
+// tables. pkg/jobs/scheduler.Store satisfies this interface.
+type DBSnapshotter interface {
+	ExportSnapshot(ctx context.Context) (json.RawMessage, error)
+	ImportSnapshot(ctx context.Context, data json.RawMessage) error
+}
+
+// SetDBSnapshotter wires a database snapshot source into the bot so backup
+// export/import can include job/result rows, not just files on disk.
+func (b *Bot) SetDBSnapshotter(s DBSnapshotter) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dbSnapshotter = s
+}
+
+// BackupExport builds a zip archive of config.yml, the last N job result
+// markdowns under output/, and a JSON snapshot of the jobs/results tables
+// (if a DBSnapshotter is configured), then uploads it as a Discord
+// attachment to the configured channel.
+func (b *Bot) BackupExport(ctx context.Context, configPath, outputDir string, lastN int) error {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+
+	manifest := backupManifest{
+		Version:   backupManifestVersion,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if configPath != "" {
+		if data, err := os.ReadFile(configPath); err == nil {
+			if err := writeZipEntry(zw, "config.yml", data); err != nil {
+				return err
+			}
+			manifest.ConfigFile = "config.yml"
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read config for backup: %w", err)
+		}
+	}
+
+	resultFiles, err := recentMarkdownFiles(outputDir, lastN)
+	if err != nil {
+		return fmt.Errorf("failed to list result files for backup: %w", err)
+	}
+	for _, path := range resultFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read result file %s: %w", path, err)
+		}
+		entryName := "output/" + filepath.Base(path)
+		if err := writeZipEntry(zw, entryName, data); err != nil {
+			return err
+		}
+		manifest.ResultFiles = append(manifest.ResultFiles, entryName)
+	}
+
+	b.mu.RLock()
+	snapshotter := b.dbSnapshotter
+	b.mu.RUnlock()
+
+	if snapshotter != nil {
+		snapshot, err := snapshotter.ExportSnapshot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to export database snapshot: %w", err)
+		}
+		if err := writeZipEntry(zw, "snapshot.json", snapshot); err != nil {
+			return err
+		}
+		manifest.HasSnapshot = true
+	}
+
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal backup manifest: %w", err)
+	}
+	if err := writeZipEntry(zw, backupManifestName, manifestData); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize backup archive: %w", err)
+	}
+
+	filename := fmt.Sprintf("osrs-flips-backup-%s.zip", time.Now().UTC().Format("20060102-150405"))
+	_, err = b.session.ChannelFileSend(b.channelID, filename, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		b.logger.DiscordError("backup_export", err)
+		return fmt.Errorf("failed to upload backup archive: %w", err)
+	}
+
+	return nil
+}
+
+// BackupImport downloads the archive at attachmentURL, validates its
+// manifest, and restores config.yml plus any database snapshot in a single
+// pass. It refuses to touch disk or the database if the manifest is missing
+// or its version is unrecognized.
+func (b *Bot) BackupImport(ctx context.Context, attachmentURL, configPath string) error {
+	resp, err := http.Get(attachmentURL)
+	if err != nil {
+		return fmt.Errorf("failed to download backup archive: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download backup archive: unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read backup archive: %w", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("backup archive is not a valid zip: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	manifestFile, ok := files[backupManifestName]
+	if !ok {
+		return fmt.Errorf("backup archive is missing %s", backupManifestName)
+	}
+	var manifest backupManifest
+	if err := readZipJSON(manifestFile, &manifest); err != nil {
+		return fmt.Errorf("failed to parse backup manifest: %w", err)
+	}
+	if manifest.Version != backupManifestVersion {
+		return fmt.Errorf("unsupported backup manifest version %d (expected %d)", manifest.Version, backupManifestVersion)
+	}
+
+	if manifest.ConfigFile != "" {
+		f, ok := files[manifest.ConfigFile]
+		if !ok {
+			return fmt.Errorf("manifest references missing config file %s", manifest.ConfigFile)
+		}
+		contents, err := readZipFile(f)
+		if err != nil {
+			return fmt.Errorf("failed to read config from backup: %w", err)
+		}
+		if err := os.WriteFile(configPath, contents, 0644); err != nil {
+			return fmt.Errorf("failed to restore config: %w", err)
+		}
+	}
+
+	if manifest.HasSnapshot {
+		f, ok := files["snapshot.json"]
+		if !ok {
+			return fmt.Errorf("manifest declares a snapshot but snapshot.json is missing")
+		}
+
+		b.mu.RLock()
+		snapshotter := b.dbSnapshotter
+		b.mu.RUnlock()
+
+		if snapshotter == nil {
+			return fmt.Errorf("backup contains a database snapshot but no snapshotter is configured")
+		}
+
+		contents, err := readZipFile(f)
+		if err != nil {
+			return fmt.Errorf("failed to read database snapshot from backup: %w", err)
+		}
+		if err := snapshotter.ImportSnapshot(ctx, contents); err != nil {
+			return fmt.Errorf("failed to restore database snapshot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// recentMarkdownFiles returns up to lastN paths of the most recently
+// modified .md files in dir, newest first.
+func recentMarkdownFiles(dir string, lastN int) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	type fileInfo struct {
+		path    string
+		modTime time.Time
+	}
+	var files []fileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, fileInfo{path: filepath.Join(dir, entry.Name()), modTime: info.ModTime()})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+
+	if lastN > 0 && len(files) > lastN {
+		files = files[:lastN]
+	}
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+func writeZipEntry(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to backup archive: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to backup archive: %w", name, err)
+	}
+	return nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func readZipJSON(f *zip.File, v interface{}) error {
+	data, err := readZipFile(f)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// handleBackupCommand dispatches `!osrs backup export` / `!osrs backup
+// import <attachment-url>`.
+func (b *Bot) handleBackupCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) == 0 {
+		b.replyError(m.ChannelID, "Usage: `!osrs backup export` or `!osrs backup import <attachment-url>`")
+		return
+	}
+
+	switch args[0] {
+	case "export":
+		if err := b.BackupExport(context.Background(), "config.yml", "output", 20); err != nil {
+			b.logger.WithDiscord().WithError(err).Error("Backup export failed")
+			b.replyError(m.ChannelID, fmt.Sprintf("Backup export failed: %v", err))
+			return
+		}
+	case "import":
+		if len(args) < 2 {
+			b.replyError(m.ChannelID, "Usage: `!osrs backup import <attachment-url>`")
+			return
+		}
+		if err := b.BackupImport(context.Background(), args[1], "config.yml"); err != nil {
+			b.logger.WithDiscord().WithError(err).Error("Backup import failed")
+			b.replyError(m.ChannelID, fmt.Sprintf("Backup import failed: %v", err))
+			return
+		}
+		if _, err := s.ChannelMessageSend(m.ChannelID, "‚úÖ Backup restored successfully."); err != nil {
+			b.logger.WithDiscord().WithError(err).Error("Failed to send backup import confirmation")
+		}
+	default:
+		b.replyError(m.ChannelID, fmt.Sprintf("Unknown backup subcommand: %s", args[0]))
+	}
+}
+
+// replyError sends a simple red error embed to channelID.
+func (b *Bot) replyError(channelID, message string) {
+	embed := &discordgo.MessageEmbed{
+		Title:       "‚ùå Backup Error",
+		Description: message,
+		Color:       0xff0000,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	if _, err := b.session.ChannelMessageSendEmbed(channelID, embed); err != nil {
+		b.logger.WithDiscord().WithError(err).Error("Failed to send backup error embed")
+	}
+}
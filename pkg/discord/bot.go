@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"osrs-flipping/pkg/config"
+	"osrs-flipping/pkg/discord/ratelimiter"
 	"osrs-flipping/pkg/llm"
 	"osrs-flipping/pkg/logging"
+	"osrs-flipping/pkg/osrs"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/sirupsen/logrus"
@@ -17,14 +19,203 @@ import (
 
 // Bot represents the Discord bot instance
 type Bot struct {
-	session          *discordgo.Session
-	config           *config.DiscordConfig
-	logger           *logging.Logger
-	channelID        string
-	mu               sync.RWMutex
-	ready            bool
-	lastCommandTime  time.Time
-	commandsReceived int64
+	session           *discordgo.Session
+	gateway           *Gateway
+	config            *config.DiscordConfig
+	logger            *logging.Logger
+	channelID         string
+	mu                sync.RWMutex
+	ready             bool
+	lastCommandTime   time.Time
+	commandsReceived  int64
+	cancelRun         context.CancelFunc
+	scheduleProvider  ScheduleProvider
+	dbSnapshotter     DBSnapshotter
+	jobCanceler       JobCanceler
+	historyProvider   HistoryProvider
+	jobRerunner       JobRerunner
+	jobStatusProvider JobStatusProvider
+	cronDescriber     CronDescriber
+	progressOnce      sync.Once
+	progressTracker   *progressTracker
+}
+
+// JobCanceler cancels a running job by name, for `!osrs cancel <jobName>`.
+// jobs.JobRunner satisfies this interface.
+type JobCanceler interface {
+	CancelJob(jobName string) bool
+}
+
+// SetJobCanceler wires a JobRunner into the bot so `!osrs cancel` can
+// signal a running job's context.
+func (b *Bot) SetJobCanceler(c JobCanceler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.jobCanceler = c
+}
+
+// ScheduleProvider supplies schedule/status information backed by a
+// persistent job store, for the `!osrs schedule` / `!osrs status` commands.
+// pkg/jobs/scheduler.Store satisfies this interface.
+type ScheduleProvider interface {
+	Upcoming(ctx context.Context, limit int) ([]ScheduledJobSummary, error)
+}
+
+// ScheduledJobSummary is the subset of a persisted job's fields worth
+// surfacing in Discord.
+type ScheduledJobSummary struct {
+	JobName     string
+	Status      string
+	ScheduledAt time.Time
+}
+
+// SetScheduleProvider wires a persistent job store into the bot so
+// `!osrs schedule` reflects real upcoming/in-flight jobs instead of the
+// in-memory commandsReceived counter.
+func (b *Bot) SetScheduleProvider(p ScheduleProvider) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.scheduleProvider = p
+}
+
+// ExecutionSummary is a durable job execution record, for the
+// `!osrs history` / `!osrs logs <execution_id>` commands.
+type ExecutionSummary struct {
+	ExecutionID string
+	JobName     string
+	Trigger     string
+	StartedAt   time.Time
+	EndedAt     time.Time
+	Duration    time.Duration
+	ItemsFound  int
+	Success     bool
+	Error       string
+	Analysis    string
+}
+
+// HistoryProvider supplies durable job execution history, for the
+// `!osrs history` / `!osrs logs <execution_id>` commands.
+// pkg/jobs/scheduler.HistoryStore satisfies this interface via
+// HistoryDiscordProvider.
+type HistoryProvider interface {
+	ListRecent(ctx context.Context, limit int) ([]ExecutionSummary, error)
+	Get(ctx context.Context, executionID string) (*ExecutionSummary, error)
+}
+
+// SetHistoryProvider wires a durable execution history store into the bot
+// so `!osrs history` / `!osrs logs <execution_id>` can read past runs.
+func (b *Bot) SetHistoryProvider(p HistoryProvider) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.historyProvider = p
+}
+
+// JobRerunner re-runs a past execution's job by execution ID, for the
+// `!osrs rerun <execution_id>` command. jobs.JobRunner satisfies this
+// interface via RerunExecution.
+type JobRerunner interface {
+	RerunExecution(ctx context.Context, executionID string) (jobName string, err error)
+}
+
+// SetJobRerunner wires a JobRunner into the bot so `!osrs rerun` can
+// re-trigger a past job execution.
+func (b *Bot) SetJobRerunner(r JobRerunner) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.jobRerunner = r
+}
+
+// JobBreakerStatus reports a single job's enabled flag and circuit-breaker
+// state, for the `!osrs status <jobName>` command.
+// scheduler.Scheduler.JobStatus satisfies this via an adapter in cmd/bot,
+// since pkg/discord doesn't import pkg/scheduler directly.
+type JobBreakerStatus struct {
+	Enabled             bool
+	ConsecutiveFailures int
+	Paused              bool
+	SkipRemaining       int
+}
+
+// JobStatusProvider supplies a single job's breaker status, for the
+// `!osrs status <jobName>` command.
+type JobStatusProvider interface {
+	JobStatus(name string) (JobBreakerStatus, bool)
+}
+
+// SetJobStatusProvider wires a Scheduler into the bot so `!osrs status
+// <jobName>` can report whether a job's circuit breaker has tripped.
+func (b *Bot) SetJobStatusProvider(p JobStatusProvider) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.jobStatusProvider = p
+}
+
+// ScheduleDescription is a human-readable rendering of one job's cron
+// schedule, for the `!osrs schedules` command.
+// scheduler.Scheduler.DescribeSchedules satisfies this via an adapter in
+// cmd/bot, since pkg/discord doesn't import pkg/scheduler directly.
+type ScheduleDescription struct {
+	JobName  string
+	Cron     string
+	Human    string
+	Warnings []string
+	Next     time.Time
+}
+
+// CronDescriber supplies a human-readable description of every job's cron
+// schedule, for the `!osrs schedules` command.
+type CronDescriber interface {
+	DescribeSchedules() []ScheduleDescription
+}
+
+// SetCronDescriber wires a Scheduler into the bot so `!osrs schedules` can
+// report each job's next fire times and any degenerate-cron warnings.
+func (b *Bot) SetCronDescriber(d CronDescriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cronDescriber = d
+}
+
+// NotifyJobPaused posts a notice that jobName's circuit breaker has opened
+// after consecutiveFailures in a row. It satisfies scheduler.BreakerNotifier.
+func (b *Bot) NotifyJobPaused(jobName string, consecutiveFailures int) error {
+	embed := &discordgo.MessageEmbed{
+		Title:       "⏸️ Job Paused",
+		Description: fmt.Sprintf("`%s` has failed %d times in a row and will be skipped for a while before its next retry.", jobName, consecutiveFailures),
+		Color:       0xffaa00,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	_, err := b.SendEmbed(embed)
+	return err
+}
+
+// NotifyIncident posts a notice that an arbitrage window has opened,
+// upgraded to critical, or closed for an item. It satisfies
+// osrs.IncidentNotifier.
+func (b *Bot) NotifyIncident(incident osrs.Incident) error {
+	title := "📈 Flip Incident Opened"
+	color := 0x00aaff
+	description := fmt.Sprintf("Item %d's margin has stayed above the warn threshold, peaking at %d gp.", incident.ItemID, incident.PeakMargin)
+
+	switch {
+	case !incident.End.IsZero():
+		title = "✅ Flip Incident Closed"
+		color = 0x00cc66
+		description = fmt.Sprintf("Item %d's margin has cooled back down; it peaked at %d gp.", incident.ItemID, incident.PeakMargin)
+	case incident.Severity == osrs.IncidentCritical:
+		title = "🚨 Flip Incident Critical"
+		color = 0xff4444
+		description = fmt.Sprintf("Item %d's margin has climbed past the critical threshold, peaking at %d gp.", incident.ItemID, incident.PeakMargin)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: description,
+		Color:       color,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	_, err := b.SendEmbed(embed)
+	return err
 }
 
 // NewBot creates a new Discord bot instance
@@ -34,12 +225,26 @@ func NewBot(cfg *config.DiscordConfig, logger *logging.Logger) (*Bot, error) {
 		return nil, fmt.Errorf("failed to create Discord session: %w", err)
 	}
 
+	limiter := ratelimiter.New()
+	session.Client.Transport = newRateLimitedTransport(session.Client.Transport, limiter)
+
 	bot := &Bot{
 		session:   session,
 		config:    cfg,
 		logger:    logger,
 		channelID: cfg.ChannelID,
 	}
+	bot.gateway = NewGateway(session, logger)
+	bot.gateway.OnReady = func(*discordgo.Ready) {
+		bot.mu.Lock()
+		bot.ready = true
+		bot.mu.Unlock()
+	}
+	bot.gateway.OnDisconnect = func(error) {
+		bot.mu.Lock()
+		bot.ready = false
+		bot.mu.Unlock()
+	}
 
 	// Add event handlers
 	session.AddHandler(bot.onReady)
@@ -51,13 +256,21 @@ func NewBot(cfg *config.DiscordConfig, logger *logging.Logger) (*Bot, error) {
 	return bot, nil
 }
 
-// Start starts the Discord bot
+// Start starts the Discord bot. Unlike a bare session.Open(), this keeps a
+// durable gateway connection running for the lifetime of runCtx: a dropped
+// connection is reconnected with backoff rather than silently killing the
+// bot.
 func (b *Bot) Start(ctx context.Context) error {
 	b.logger.WithDiscord().Info("Starting Discord bot")
 
-	if err := b.session.Open(); err != nil {
-		return fmt.Errorf("failed to open Discord session: %w", err)
-	}
+	runCtx, cancel := context.WithCancel(context.Background())
+	b.cancelRun = cancel
+
+	go func() {
+		if err := b.gateway.Run(runCtx); err != nil && err != context.Canceled {
+			b.logger.WithDiscord().WithError(err).Error("Gateway run loop exited")
+		}
+	}()
 
 	// Wait for ready state or context cancellation
 	ticker := time.NewTicker(100 * time.Millisecond)
@@ -83,9 +296,12 @@ func (b *Bot) Start(ctx context.Context) error {
 	}
 }
 
-// Stop stops the Discord bot
+// Stop stops the Discord bot and its gateway run loop
 func (b *Bot) Stop() error {
 	b.logger.WithDiscord().Info("Stopping Discord bot")
+	if b.cancelRun != nil {
+		b.cancelRun()
+	}
 	return b.session.Close()
 }
 
@@ -179,11 +395,8 @@ func (b *Bot) sendLongMessage(content string) (*discordgo.Message, error) {
 		}
 
 		b.logger.DiscordMessage(b.channelID, message.ID, len(chunk))
-
-		// Add a small delay between messages to avoid rate limiting
-		if i < len(chunks)-1 {
-			time.Sleep(100 * time.Millisecond)
-		}
+		// Pacing between chunks is handled by the shared rate-limited
+		// transport rather than a fixed sleep here.
 	}
 
 	return firstMessage, nil
@@ -236,11 +449,8 @@ func (b *Bot) SendLongAnalysis(jobName, analysis string, footerText string, item
 		if _, err := b.SendEmbed(embed); err != nil {
 			return fmt.Errorf("failed to send analysis part %d: %w", i+1, err)
 		}
-
-		// Add delay between messages to avoid rate limiting
-		if i < len(chunks)-1 {
-			time.Sleep(150 * time.Millisecond)
-		}
+		// Pacing between chunks is handled by the shared rate-limited
+		// transport rather than a fixed sleep here.
 	}
 
 	return nil
@@ -321,7 +531,34 @@ func (b *Bot) handleCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
 	}).Info("Processing bot command")
 
 	switch command {
+	case "backup":
+		b.handleBackupCommand(s, m, parts[2:])
+
+	case "cancel":
+		if len(parts) < 3 {
+			b.replyError(m.ChannelID, "Usage: `!osrs cancel <jobName>`")
+			return
+		}
+		jobName := strings.Join(parts[2:], " ")
+
+		b.mu.RLock()
+		canceler := b.jobCanceler
+		b.mu.RUnlock()
+
+		if canceler == nil || !canceler.CancelJob(jobName) {
+			b.replyError(m.ChannelID, fmt.Sprintf("No running job named `%s`", jobName))
+			return
+		}
+		if _, err := s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("🛑 Canceling job `%s`...", jobName)); err != nil {
+			b.logger.WithDiscord().WithError(err).Error("Failed to send cancel confirmation")
+		}
+
 	case "status":
+		if len(parts) >= 3 {
+			b.handleJobStatusCommand(s, m, strings.Join(parts[2:], " "))
+			return
+		}
+
 		b.mu.RLock()
 		lastCommand := b.lastCommandTime
 		totalCommands := b.commandsReceived
@@ -348,11 +585,82 @@ func (b *Bot) handleCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
 			b.logger.WithDiscord().WithError(err).Error("Failed to send status command response")
 		}
 
+	case "schedule":
+		b.mu.RLock()
+		provider := b.scheduleProvider
+		b.mu.RUnlock()
+
+		if provider == nil {
+			embed := &discordgo.MessageEmbed{
+				Title:       "üìÖ Schedule",
+				Description: "No persistent job store is configured for this bot instance.",
+				Color:       0xffaa00,
+				Timestamp:   time.Now().Format(time.RFC3339),
+			}
+			if _, err := s.ChannelMessageSendEmbed(m.ChannelID, embed); err != nil {
+				b.logger.WithDiscord().WithError(err).Error("Failed to send schedule command response")
+			}
+			return
+		}
+
+		jobs, err := provider.Upcoming(context.Background(), 10)
+		if err != nil {
+			b.logger.WithDiscord().WithError(err).Error("Failed to fetch upcoming jobs")
+			return
+		}
+
+		var lines strings.Builder
+		if len(jobs) == 0 {
+			lines.WriteString("No upcoming or in-flight jobs.")
+		}
+		for _, j := range jobs {
+			lines.WriteString(fmt.Sprintf("‚Ä¢ `%s` (%s) at %s\n", j.JobName, j.Status, j.ScheduledAt.Format(time.RFC3339)))
+		}
+
+		embed := &discordgo.MessageEmbed{
+			Title:       "üìÖ Upcoming & In-Flight Jobs",
+			Description: lines.String(),
+			Color:       0x0099ff,
+			Timestamp:   time.Now().Format(time.RFC3339),
+		}
+		if _, err := s.ChannelMessageSendEmbed(m.ChannelID, embed); err != nil {
+			b.logger.WithDiscord().WithError(err).Error("Failed to send schedule command response")
+		}
+
+	case "schedules":
+		b.handleSchedulesCommand(s, m)
+
+	case "history":
+		b.handleHistoryCommand(s, m)
+
+	case "logs":
+		if len(parts) < 3 {
+			b.replyError(m.ChannelID, "Usage: `!osrs logs <execution_id>`")
+			return
+		}
+		b.handleLogsCommand(s, m, parts[2])
+
+	case "rerun":
+		if len(parts) < 3 {
+			b.replyError(m.ChannelID, "Usage: `!osrs rerun <execution_id>`")
+			return
+		}
+		b.handleRerunCommand(s, m, parts[2])
+
 	case "help":
 		embed := &discordgo.MessageEmbed{
 			Title: "üéØ osrs-flips Commands",
 			Description: "Available commands:\n" +
 				"`!osrs status` - Check bot status\n" +
+				"`!osrs status <jobName>` - Check a job's circuit-breaker status\n" +
+				"`!osrs schedule` - List upcoming and in-flight jobs\n" +
+				"`!osrs schedules` - Describe each job's cron schedule and next fire times\n" +
+				"`!osrs history` - List recent job executions\n" +
+				"`!osrs logs <execution_id>` - Show full analysis output for an execution\n" +
+				"`!osrs rerun <execution_id>` - Re-trigger the job from a past execution\n" +
+				"`!osrs backup export` - Export config and job history as a zip\n" +
+				"`!osrs backup import <url>` - Restore config and job history from a zip\n" +
+				"`!osrs cancel <jobName>` - Cancel a running job\n" +
 				"`!osrs help` - Show this help message\n" +
 				"`!osrs ping` - Test bot responsiveness\n",
 			Color:     0x0099ff,
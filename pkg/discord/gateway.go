@@ -0,0 +1,213 @@
+package discord
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"osrs-flipping/pkg/logging"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// backoff implements jpillora/backoff-style exponential backoff with jitter.
+type backoff struct {
+	base   time.Duration
+	max    time.Duration
+	factor float64
+	jitter float64
+	attempt int
+}
+
+func newBackoff() *backoff {
+	return &backoff{
+		base:   1 * time.Second,
+		max:    60 * time.Second,
+		factor: 2,
+		jitter: 0.5,
+	}
+}
+
+// next returns the delay for the current attempt and advances the attempt counter.
+func (b *backoff) next() time.Duration {
+	d := float64(b.base) * pow(b.factor, b.attempt)
+	if d > float64(b.max) {
+		d = float64(b.max)
+	}
+	b.attempt++
+
+	// Apply jitter as +/- a fraction of the delay.
+	delta := d * b.jitter
+	d = d - delta + rand.Float64()*2*delta
+	if d < float64(b.base) {
+		d = float64(b.base)
+	}
+	return time.Duration(d)
+}
+
+func (b *backoff) reset() {
+	b.attempt = 0
+}
+
+func pow(factor float64, attempt int) float64 {
+	result := 1.0
+	for i := 0; i < attempt; i++ {
+		result *= factor
+	}
+	return result
+}
+
+// Gateway wraps a discordgo session with a durable, self-healing connection
+// loop. discordgo already owns the raw websocket, but it treats Open() as a
+// one-shot call and leaves reconnection policy to the caller -- Gateway
+// supplies that policy: exponential backoff with jitter, and lifecycle
+// callbacks for the rest of the bot to hook into. Gateway does not track
+// heartbeat ACKs itself; a dropped connection is only noticed once discordgo
+// reports a disconnect.
+type Gateway struct {
+	session *discordgo.Session
+	logger  *logging.Logger
+
+	mu        sync.RWMutex
+	sessionID string
+	connected bool
+
+	OnReady      func(*discordgo.Ready)
+	OnResumed    func(*discordgo.Resumed)
+	OnDisconnect func(err error)
+}
+
+// NewGateway wraps an existing discordgo session with reconnect handling.
+func NewGateway(session *discordgo.Session, logger *logging.Logger) *Gateway {
+	g := &Gateway{
+		session: session,
+		logger:  logger,
+	}
+
+	session.AddHandler(g.onReady)
+	session.AddHandler(g.onResumed)
+	session.AddHandler(g.onDisconnect)
+
+	return g
+}
+
+// Run opens the gateway connection and blocks, reconnecting with exponential
+// backoff and jitter whenever the connection drops, until ctx is canceled.
+func (g *Gateway) Run(ctx context.Context) error {
+	bo := newBackoff()
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := g.session.Open()
+		if err != nil {
+			g.logger.WithDiscord().WithError(err).Warn("Gateway failed to open, backing off")
+			if !g.sleep(ctx, bo.next()) {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		bo.reset()
+		g.mu.Lock()
+		g.connected = true
+		g.mu.Unlock()
+
+		// Block here until the session is closed or the context is canceled.
+		<-g.waitForDisconnect(ctx)
+
+		g.mu.Lock()
+		g.connected = false
+		g.mu.Unlock()
+
+		if ctx.Err() != nil {
+			_ = g.session.Close()
+			return ctx.Err()
+		}
+
+		_ = g.session.Close()
+		g.logger.WithDiscord().Info("Gateway connection lost, reconnecting")
+		if !g.sleep(ctx, bo.next()) {
+			return ctx.Err()
+		}
+	}
+}
+
+// waitForDisconnect returns a channel that closes when discordgo reports a
+// disconnect, or when ctx is canceled, whichever happens first.
+func (g *Gateway) waitForDisconnect(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	disconnected := make(chan struct{}, 1)
+
+	g.mu.Lock()
+	prevOnDisconnect := g.OnDisconnect
+	g.mu.Unlock()
+
+	g.mu.Lock()
+	g.OnDisconnect = func(err error) {
+		if prevOnDisconnect != nil {
+			prevOnDisconnect(err)
+		}
+		select {
+		case disconnected <- struct{}{}:
+		default:
+		}
+	}
+	g.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		select {
+		case <-ctx.Done():
+		case <-disconnected:
+		}
+	}()
+
+	return done
+}
+
+func (g *Gateway) sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (g *Gateway) onReady(s *discordgo.Session, r *discordgo.Ready) {
+	g.mu.Lock()
+	g.sessionID = r.SessionID
+	g.mu.Unlock()
+
+	g.logger.WithDiscord().WithField("session_id", r.SessionID).Info("Gateway ready")
+	if g.OnReady != nil {
+		g.OnReady(r)
+	}
+}
+
+func (g *Gateway) onResumed(s *discordgo.Session, r *discordgo.Resumed) {
+	g.logger.WithDiscord().Info("Gateway resumed")
+	if g.OnResumed != nil {
+		g.OnResumed(r)
+	}
+}
+
+func (g *Gateway) onDisconnect(s *discordgo.Session, d *discordgo.Disconnect) {
+	g.logger.WithDiscord().Warn("Gateway disconnected")
+	if g.OnDisconnect != nil {
+		g.OnDisconnect(nil)
+	}
+}
+
+// IsConnected reports whether the gateway currently believes it is connected.
+func (g *Gateway) IsConnected() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.connected
+}
@@ -0,0 +1,130 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleHistoryCommand dispatches `!osrs history`, listing the most recent
+// durable job executions.
+func (b *Bot) handleHistoryCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
+	b.mu.RLock()
+	provider := b.historyProvider
+	b.mu.RUnlock()
+
+	if provider == nil {
+		b.replyError(m.ChannelID, "No execution history store is configured for this bot instance.")
+		return
+	}
+
+	execs, err := provider.ListRecent(context.Background(), 10)
+	if err != nil {
+		b.logger.WithDiscord().WithError(err).Error("Failed to fetch execution history")
+		b.replyError(m.ChannelID, fmt.Sprintf("Failed to fetch execution history: %v", err))
+		return
+	}
+
+	var lines strings.Builder
+	if len(execs) == 0 {
+		lines.WriteString("No recorded executions yet.")
+	}
+	for _, e := range execs {
+		status := "✅"
+		if !e.Success {
+			status = "❌"
+		}
+		lines.WriteString(fmt.Sprintf("%s `%s` [%s] (%s) %s in %s, %d items found\n",
+			status, e.ExecutionID, e.JobName, e.Trigger, e.StartedAt.Format(time.RFC3339), e.Duration.Truncate(time.Second), e.ItemsFound))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📜 Recent Job Executions",
+		Description: lines.String(),
+		Color:       0x0099ff,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, embed); err != nil {
+		b.logger.WithDiscord().WithError(err).Error("Failed to send history command response")
+	}
+}
+
+// handleLogsCommand dispatches `!osrs logs <execution_id>`, showing the full
+// analysis output recorded for that execution.
+func (b *Bot) handleLogsCommand(s *discordgo.Session, m *discordgo.MessageCreate, executionID string) {
+	b.mu.RLock()
+	provider := b.historyProvider
+	b.mu.RUnlock()
+
+	if provider == nil {
+		b.replyError(m.ChannelID, "No execution history store is configured for this bot instance.")
+		return
+	}
+
+	exec, err := provider.Get(context.Background(), executionID)
+	if err != nil {
+		b.logger.WithDiscord().WithError(err).Error("Failed to fetch execution logs")
+		b.replyError(m.ChannelID, fmt.Sprintf("Failed to fetch execution %s: %v", executionID, err))
+		return
+	}
+	if exec == nil {
+		b.replyError(m.ChannelID, fmt.Sprintf("No execution found with ID `%s`.", executionID))
+		return
+	}
+
+	description := exec.Analysis
+	if !exec.Success {
+		description = fmt.Sprintf("**Error:** %s\n\n%s", exec.Error, description)
+	}
+	if description == "" {
+		description = "(no analysis recorded)"
+	}
+	const embedDescLimit = 3800 // leave headroom under Discord's 4096 embed description limit
+	if len(description) > embedDescLimit {
+		description = description[:embedDescLimit] + "\n... (truncated, see original channel message for full output)"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("📄 Logs: %s [%s]", exec.ExecutionID, exec.JobName),
+		Description: fmt.Sprintf("Trigger: %s | Started: %s | Duration: %s\n\n%s",
+			exec.Trigger, exec.StartedAt.Format(time.RFC3339), exec.Duration.Truncate(time.Second), description),
+		Color:     0x0099ff,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, embed); err != nil {
+		b.logger.WithDiscord().WithError(err).Error("Failed to send logs command response")
+	}
+}
+
+// handleRerunCommand dispatches `!osrs rerun <execution_id>`, re-triggering
+// the job that a past execution ran.
+func (b *Bot) handleRerunCommand(s *discordgo.Session, m *discordgo.MessageCreate, executionID string) {
+	b.mu.RLock()
+	rerunner := b.jobRerunner
+	b.mu.RUnlock()
+
+	if rerunner == nil {
+		b.replyError(m.ChannelID, "No job rerunner is configured for this bot instance.")
+		return
+	}
+
+	jobName, err := rerunner.RerunExecution(context.Background(), executionID)
+	if err != nil {
+		b.logger.WithDiscord().WithError(err).Error("Failed to rerun execution")
+		b.replyError(m.ChannelID, fmt.Sprintf("Failed to rerun execution %s: %v", executionID, err))
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🔁 Job Re-triggered",
+		Description: fmt.Sprintf("Re-running job `%s` (from execution `%s`).", jobName, executionID),
+		Color:       0x00ff00,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, embed); err != nil {
+		b.logger.WithDiscord().WithError(err).Error("Failed to send rerun command response")
+	}
+}
@@ -0,0 +1,179 @@
+package discord
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ProgressStage identifies where in the pipeline a running job currently is.
+// Mirrors jobs.ProgressStage's values; kept as its own type here rather than
+// importing pkg/jobs, which already imports pkg/discord for Executor's
+// discordBot field -- callers such as cmd/bot/main.go convert a
+// jobs.ProgressEvent into a ProgressEvent at the call site instead (see
+// SubscribeProgress).
+type ProgressStage string
+
+const (
+	StageFetchingPrices ProgressStage = "fetching_prices"
+	StageFiltering      ProgressStage = "filtering"
+	StageLLMAnalysis    ProgressStage = "llm_analysis"
+	StageFormatting     ProgressStage = "formatting"
+)
+
+// ProgressEvent is the Discord-side counterpart of jobs.ProgressEvent,
+// describing a single update in a job's progress so SendProgress can render
+// it as an embed.
+type ProgressEvent struct {
+	JobName    string
+	Stage      ProgressStage
+	PercentPct float64 // 0-100
+	ItemsDone  int
+	ItemsTotal int
+	BytesDone  int64
+	StartedAt  time.Time
+	Emitted    time.Time
+}
+
+// ItemsPerSecond returns the observed throughput since StartedAt, or 0 if no
+// time has elapsed yet.
+func (p ProgressEvent) ItemsPerSecond() float64 {
+	elapsed := p.Emitted.Sub(p.StartedAt).Seconds()
+	if elapsed <= 0 || p.ItemsDone == 0 {
+		return 0
+	}
+	return float64(p.ItemsDone) / elapsed
+}
+
+// ETA estimates remaining time based on observed throughput so far. Returns
+// 0 if throughput or the remaining item count is unknown.
+func (p ProgressEvent) ETA() time.Duration {
+	rate := p.ItemsPerSecond()
+	if rate <= 0 || p.ItemsTotal <= p.ItemsDone {
+		return 0
+	}
+	remaining := float64(p.ItemsTotal - p.ItemsDone)
+	return time.Duration(remaining/rate) * time.Second
+}
+
+// humanizeBytes formats a byte count using the nearest binary unit (KiB,
+// MiB, ...), e.g. 1536 -> "1.5 KiB". Duplicated from jobs.HumanizeBytes
+// rather than imported, for the same reason ProgressEvent is its own type
+// here.
+func humanizeBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	units := []string{"KiB", "MiB", "GiB", "TiB"}
+	return fmt.Sprintf("%.1f %s", float64(n)/float64(div), units[exp])
+}
+
+// progressTracker holds the single in-place progress message per running
+// job name so SendProgress can edit it instead of spamming new messages.
+type progressTracker struct {
+	mu       sync.Mutex
+	messages map[string]*discordgo.Message
+}
+
+// SendProgress renders a ProgressEvent as an embed, creating the message on
+// the job's first event and editing it in place (via
+// ChannelMessageEditEmbed) on subsequent events, so a 30-minute analysis
+// shows live stage/throughput/ETA instead of going silent until the final
+// result.
+func (b *Bot) SendProgress(event ProgressEvent) error {
+	b.progressOnce.Do(func() {
+		b.progressTracker = &progressTracker{messages: make(map[string]*discordgo.Message)}
+	})
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("⏳ %s", event.JobName),
+		Description: formatProgressDescription(event),
+		Color:       0x0099ff,
+		Timestamp:   event.Emitted.Format(time.RFC3339),
+	}
+
+	b.progressTracker.mu.Lock()
+	existing, ok := b.progressTracker.messages[event.JobName]
+	b.progressTracker.mu.Unlock()
+
+	if !ok {
+		msg, err := b.session.ChannelMessageSendEmbed(b.channelID, embed)
+		if err != nil {
+			return fmt.Errorf("failed to send progress message: %w", err)
+		}
+		b.progressTracker.mu.Lock()
+		b.progressTracker.messages[event.JobName] = msg
+		b.progressTracker.mu.Unlock()
+		return nil
+	}
+
+	if _, err := b.session.ChannelMessageEditEmbed(b.channelID, existing.ID, embed); err != nil {
+		return fmt.Errorf("failed to edit progress message: %w", err)
+	}
+
+	if event.Stage == StageFormatting && event.PercentPct >= 100 {
+		b.clearProgressMessage(event.JobName)
+	}
+
+	return nil
+}
+
+// clearProgressMessage drops the tracked progress message for jobName so a
+// future run of the same job starts a fresh message rather than editing a
+// stale one.
+func (b *Bot) clearProgressMessage(jobName string) {
+	if b.progressTracker == nil {
+		return
+	}
+	b.progressTracker.mu.Lock()
+	delete(b.progressTracker.messages, jobName)
+	b.progressTracker.mu.Unlock()
+}
+
+func formatProgressDescription(event ProgressEvent) string {
+	stageLabel := map[ProgressStage]string{
+		StageFetchingPrices: "Fetching prices",
+		StageFiltering:      "Filtering",
+		StageLLMAnalysis:    "LLM analysis",
+		StageFormatting:     "Formatting",
+	}[event.Stage]
+
+	lines := fmt.Sprintf("**Stage:** %s\n**Progress:** %d/%d (%.0f%%)\n",
+		stageLabel, event.ItemsDone, event.ItemsTotal, event.PercentPct)
+
+	if rate := event.ItemsPerSecond(); rate > 0 {
+		lines += fmt.Sprintf("**Throughput:** %.2f items/sec\n", rate)
+	}
+	if eta := event.ETA(); eta > 0 {
+		lines += fmt.Sprintf("**ETA:** %s\n", eta.Truncate(time.Second))
+	}
+	if event.BytesDone > 0 {
+		lines += fmt.Sprintf("**Processed:** %s\n", humanizeBytes(event.BytesDone))
+	}
+
+	return lines
+}
+
+// SubscribeProgress reads from events until it's closed and forwards each
+// event to SendProgress, logging (but not failing) any send error so a
+// Discord hiccup doesn't take down the subscription loop. Callers convert
+// their own jobs.ProgressEvent channel into a ProgressEvent channel first
+// (see cmd/bot/main.go's convertProgress), since this package doesn't
+// import pkg/jobs.
+func (b *Bot) SubscribeProgress(events <-chan ProgressEvent) {
+	go func() {
+		for event := range events {
+			if err := b.SendProgress(event); err != nil {
+				b.logger.WithDiscord().WithError(err).Warn("Failed to send progress update")
+			}
+		}
+	}()
+}
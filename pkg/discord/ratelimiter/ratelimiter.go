@@ -0,0 +1,185 @@
+// Package ratelimiter tracks Discord's per-route and global rate-limit
+// buckets so REST calls can be paced without arbitrary sleeps.
+package ratelimiter
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// bucket tracks the token state for a single (method, major-parameter,
+// bucket-hash) rate-limit bucket, as reported by Discord's
+// X-RateLimit-* response headers.
+type bucket struct {
+	mu        sync.Mutex
+	remaining int
+	resetAt   time.Time
+}
+
+// Limiter blocks outbound Discord REST calls until the relevant bucket (and,
+// when a global limit is hit, the whole client) has capacity again.
+type Limiter struct {
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	routeHashes map[string]string
+	globalMu    sync.Mutex
+	globalUntil time.Time
+}
+
+// New creates an empty Limiter with no buckets populated yet. Buckets are
+// learned lazily from response headers as calls complete.
+func New() *Limiter {
+	return &Limiter{
+		buckets:     make(map[string]*bucket),
+		routeHashes: make(map[string]string),
+	}
+}
+
+// Key derives a bucket key from an HTTP method, a major route parameter
+// (e.g. the channel or guild ID), and Discord's bucket hash, if known. When
+// the bucket hash isn't known yet (before the first response), callers
+// should pass an empty hash and key on method+major-param alone. Once a
+// route's hash has been learned, prefer ResolveKey/LearnBucket over calling
+// Key directly so Wait and Update key into the same bucket.
+func Key(method, majorParam, bucketHash string) string {
+	if bucketHash != "" {
+		return bucketHash
+	}
+	return method + ":" + majorParam
+}
+
+// ResolveKey returns the bucket key to Wait on for a route, using the
+// bucket hash learned from a previous response for (method, majorParam) if
+// one is known, and falling back to method+major-param alone otherwise.
+// This keeps Wait keying into the same bucket that LearnBucket/Update
+// populate from response headers, instead of Wait permanently keying on
+// method+major-param while Update moves on to the real bucket hash.
+func (l *Limiter) ResolveKey(method, majorParam string) string {
+	l.mu.Lock()
+	hash := l.routeHashes[method+":"+majorParam]
+	l.mu.Unlock()
+	return Key(method, majorParam, hash)
+}
+
+// LearnBucket records the bucket hash Discord reported for (method,
+// majorParam), so later ResolveKey calls for the same route key into it.
+// A no-op if bucketHash is empty (Discord didn't report one).
+func (l *Limiter) LearnBucket(method, majorParam, bucketHash string) {
+	if bucketHash == "" {
+		return
+	}
+	l.mu.Lock()
+	l.routeHashes[method+":"+majorParam] = bucketHash
+	l.mu.Unlock()
+}
+
+// Wait blocks until it is safe to issue a request for the given bucket key,
+// honoring both the per-bucket remaining/reset state and any active global
+// pause from a previous 429 with X-RateLimit-Global.
+func (l *Limiter) Wait(key string) {
+	for {
+		l.globalMu.Lock()
+		wait := time.Until(l.globalUntil)
+		l.globalMu.Unlock()
+		if wait > 0 {
+			time.Sleep(wait)
+			continue
+		}
+		break
+	}
+
+	b := l.bucketFor(key)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.remaining <= 0 && time.Now().Before(b.resetAt) {
+		wait := time.Until(b.resetAt)
+		b.mu.Unlock()
+		time.Sleep(wait)
+		b.mu.Lock()
+	}
+	if b.remaining > 0 {
+		b.remaining--
+	}
+}
+
+// Update records the rate-limit state reported by a Discord REST response.
+// It should be called after every request, successful or not.
+func (l *Limiter) Update(key string, header http.Header) {
+	remaining, hasRemaining := parseInt(header.Get("X-RateLimit-Remaining"))
+	resetAfter, hasReset := parseFloat(header.Get("X-RateLimit-Reset-After"))
+
+	if hasRemaining || hasReset {
+		b := l.bucketFor(key)
+		b.mu.Lock()
+		if hasRemaining {
+			b.remaining = remaining
+		}
+		if hasReset {
+			b.resetAt = time.Now().Add(time.Duration(resetAfter * float64(time.Second)))
+		}
+		b.mu.Unlock()
+	}
+}
+
+// HandleTooManyRequests reacts to a 429 response: it honors Retry-After for
+// the offending bucket and, when X-RateLimit-Global is true, pauses all
+// outbound traffic until the reset.
+func (l *Limiter) HandleTooManyRequests(key string, header http.Header) {
+	retryAfter, ok := parseFloat(header.Get("Retry-After"))
+	if !ok {
+		retryAfter = 1
+	}
+	until := time.Now().Add(time.Duration(retryAfter * float64(time.Second)))
+
+	if header.Get("X-RateLimit-Global") == "true" {
+		l.globalMu.Lock()
+		if until.After(l.globalUntil) {
+			l.globalUntil = until
+		}
+		l.globalMu.Unlock()
+		return
+	}
+
+	b := l.bucketFor(key)
+	b.mu.Lock()
+	b.remaining = 0
+	b.resetAt = until
+	b.mu.Unlock()
+}
+
+func (l *Limiter) bucketFor(key string) *bucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{remaining: 1}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+func parseInt(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+func parseFloat(s string) (float64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
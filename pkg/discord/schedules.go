@@ -0,0 +1,51 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleSchedulesCommand dispatches `!osrs schedules`, describing every
+// job's cron schedule (next fire times) and flagging any degenerate
+// expression (e.g. a step value that exceeds its field's range) so
+// operators can confirm cron semantics before deploying.
+func (b *Bot) handleSchedulesCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
+	b.mu.RLock()
+	describer := b.cronDescriber
+	b.mu.RUnlock()
+
+	if describer == nil {
+		b.replyError(m.ChannelID, "No cron scheduler is configured for this bot instance.")
+		return
+	}
+
+	descs := describer.DescribeSchedules()
+	if len(descs) == 0 {
+		b.replyError(m.ChannelID, "No scheduled jobs.")
+		return
+	}
+
+	var lines strings.Builder
+	for _, d := range descs {
+		lines.WriteString(fmt.Sprintf("**%s** (`%s`)\n%s\n", d.JobName, d.Cron, d.Human))
+		if !d.Next.IsZero() {
+			lines.WriteString(fmt.Sprintf("Next run: %s\n", d.Next.Format(time.RFC3339)))
+		}
+		for _, w := range d.Warnings {
+			lines.WriteString(fmt.Sprintf("⚠️ %s\n", w))
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🗓️ Job Schedules",
+		Description: lines.String(),
+		Color:       0x0099ff,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, embed); err != nil {
+		b.logger.WithDiscord().WithError(err).Error("Failed to send schedules command response")
+	}
+}
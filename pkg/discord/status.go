@@ -0,0 +1,48 @@
+package discord
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleJobStatusCommand dispatches `!osrs status <jobName>`, reporting
+// whether jobName's circuit breaker has tripped (see
+// scheduler.Scheduler.JobStatus).
+func (b *Bot) handleJobStatusCommand(s *discordgo.Session, m *discordgo.MessageCreate, jobName string) {
+	b.mu.RLock()
+	provider := b.jobStatusProvider
+	b.mu.RUnlock()
+
+	if provider == nil {
+		b.replyError(m.ChannelID, "No job status provider is configured for this bot instance.")
+		return
+	}
+
+	status, ok := provider.JobStatus(jobName)
+	if !ok {
+		b.replyError(m.ChannelID, fmt.Sprintf("No job named `%s`.", jobName))
+		return
+	}
+
+	breakerLine := "Circuit breaker: closed (no recent failures)."
+	color := 0x00ff00
+	if status.Paused {
+		breakerLine = fmt.Sprintf("Circuit breaker: ⏸️ open — %d consecutive failures, skipping %d more tick(s).", status.ConsecutiveFailures, status.SkipRemaining)
+		color = 0xffaa00
+	} else if status.ConsecutiveFailures > 0 {
+		breakerLine = fmt.Sprintf("Circuit breaker: closed, but %d consecutive failure(s) recorded.", status.ConsecutiveFailures)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("🩺 Job Status: %s", jobName),
+		Description: fmt.Sprintf("Enabled: %t\n%s",
+			status.Enabled, breakerLine),
+		Color:     color,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, embed); err != nil {
+		b.logger.WithDiscord().WithError(err).Error("Failed to send job status command response")
+	}
+}
@@ -0,0 +1,53 @@
+package discord
+
+import (
+	"net/http"
+	"regexp"
+
+	"osrs-flipping/pkg/discord/ratelimiter"
+)
+
+// majorParamPattern extracts the major route parameter (channel/guild/webhook
+// ID) that Discord buckets rate limits by, e.g. /channels/123/messages.
+var majorParamPattern = regexp.MustCompile(`/(channels|guilds|webhooks)/(\d+)`)
+
+// rateLimitedTransport wraps an http.RoundTripper so every outbound Discord
+// REST call is paced by a shared ratelimiter.Limiter, replacing the
+// hardcoded sleeps previously used between embed/message sends.
+type rateLimitedTransport struct {
+	next    http.RoundTripper
+	limiter *ratelimiter.Limiter
+}
+
+func newRateLimitedTransport(next http.RoundTripper, limiter *ratelimiter.Limiter) *rateLimitedTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &rateLimitedTransport{next: next, limiter: limiter}
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	majorParam := ""
+	if m := majorParamPattern.FindStringSubmatch(req.URL.Path); m != nil {
+		majorParam = m[2]
+	}
+	key := t.limiter.ResolveKey(req.Method, majorParam)
+
+	t.limiter.Wait(key)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	bucketHash := resp.Header.Get("X-RateLimit-Bucket")
+	t.limiter.LearnBucket(req.Method, majorParam, bucketHash)
+	bucketKey := ratelimiter.Key(req.Method, majorParam, bucketHash)
+	t.limiter.Update(bucketKey, resp.Header)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		t.limiter.HandleTooManyRequests(bucketKey, resp.Header)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,18 @@
+package jobs
+
+import "errors"
+
+// ErrNonRetryable marks a job execution failure as permanent, so the
+// scheduler's retry subsystem (see scheduler.Scheduler.executeJob) gives up
+// immediately instead of burning through its retry budget. Wrap it with
+// fmt.Errorf("...: %w", ErrNonRetryable) from anywhere ExecuteJob can fail
+// in a way a retry can't fix (e.g. a malformed job config).
+var ErrNonRetryable = errors.New("non-retryable job error")
+
+// Retryable is implemented by errors that want to override the scheduler's
+// default "retry unless context was cancelled" classification. errors.As
+// is used to detect it, so a wrapped error works too.
+type Retryable interface {
+	error
+	Retryable() bool
+}
@@ -11,20 +11,50 @@ import (
 	"osrs-flipping/pkg/llm"
 	"osrs-flipping/pkg/logging"
 	"osrs-flipping/pkg/osrs"
+	"osrs-flipping/pkg/osrs/portfolio"
+	"osrs-flipping/pkg/osrs/position"
+	"osrs-flipping/pkg/positions"
 )
 
+// defaultPositionStorePath is where open/closed flip positions persist
+// across runs when a job's config.PositionConfig doesn't override it.
+const defaultPositionStorePath = "output/data/positions.json"
+
+// defaultPortfolioStorePath is where a job's portfolio event log persists
+// across runs when its config.PositionConfig doesn't override it.
+const defaultPortfolioStorePath = "output/data/portfolio.json"
+
 // Executor handles job execution and coordination
 type Executor struct {
 	config       *config.Config
 	logger       *logging.Logger
 	osrsAnalyzer *osrs.Analyzer
-	llmClient    *llm.Client
+	llmClient    llm.Provider
 	discordBot   *discord.Bot
 	systemPrompt string
+
+	// portfolio mirrors osrsAnalyzer's portfolio (see loadPortfolio): kept
+	// here too so generateAnalysis can fold it into the LLM prompt's
+	// "portfolio" block without needing a getter on Analyzer.
+	portfolio *portfolio.Portfolio
+
+	// reportDir, if set via SetReportDir, is passed to
+	// llm.FormatItemsForAnalysisV2 so it renders a margin/volume chart
+	// alongside the analysis JSON it already writes to output/data/. Empty
+	// disables both (see FormatItemsForAnalysisV2's reportDir doc comment).
+	reportDir string
+}
+
+// SetReportDir enables FormatItemsForAnalysisV2's chart/JSON side effect
+// for every subsequent generateAnalysis call, writing into dir. Mirrors
+// osrs.Analyzer.SetForceRefresh's pattern of a setter toggling optional
+// behavior after construction.
+func (e *Executor) SetReportDir(dir string) {
+	e.reportDir = dir
 }
 
 // NewExecutor creates a new job executor
-func NewExecutor(cfg *config.Config, logger *logging.Logger, analyzer *osrs.Analyzer, llmClient *llm.Client, discordBot *discord.Bot) (*Executor, error) {
+func NewExecutor(cfg *config.Config, logger *logging.Logger, analyzer *osrs.Analyzer, llmClient llm.Provider, discordBot *discord.Bot) (*Executor, error) {
 	executor := &Executor{
 		config:       cfg,
 		logger:       logger,
@@ -194,6 +224,9 @@ func (e *Executor) ExecuteJobWithResult(ctx context.Context, jobName string) (*J
 		}, nil
 	}
 
+	// Wire up exposure-based deprioritization before any item selection runs
+	e.loadPortfolio(jobConfig)
+
 	// Apply initial filters (price-based only) to get trading opportunities
 	items, err := e.osrsAnalyzer.ApplyPrimaryFilter(filterOpts, true)
 	if err != nil {
@@ -254,6 +287,27 @@ func (e *Executor) ExecuteJobWithResult(ctx context.Context, jobName string) (*J
 		items = items[:jobConfig.Output.MaxItems]
 	}
 
+	// Attach a trailing-stop ladder and layered-entry plan to each item,
+	// using the job's risk tuning if it set one.
+	activationRatios := osrs.DefaultActivationRatios
+	callbackRatios := osrs.DefaultCallbackRatios
+	entryLayerCount := osrs.DefaultEntryLayerCount
+	if jobConfig.Risk != nil {
+		if len(jobConfig.Risk.ActivationRatios) > 0 {
+			activationRatios = jobConfig.Risk.ActivationRatios
+		}
+		if len(jobConfig.Risk.CallbackRatios) > 0 {
+			callbackRatios = jobConfig.Risk.CallbackRatios
+		}
+		if jobConfig.Risk.EntryLayerCount != nil {
+			entryLayerCount = *jobConfig.Risk.EntryLayerCount
+		}
+	}
+	for i := range items {
+		items[i].TrailingLevels = osrs.ComputeTrailingLevels(items[i], activationRatios, callbackRatios)
+		items[i].EntryLayers = osrs.ComputeEntryLayers(items[i], entryLayerCount)
+	}
+
 	// Handle case where no items remain after filtering
 	if len(items) == 0 {
 		endTime := time.Now()
@@ -318,19 +372,22 @@ func (e *Executor) ExecuteJobWithResult(ctx context.Context, jobName string) (*J
 		}
 	}
 
+	exitSignals := e.evaluateExitSignals(jobConfig)
+
 	endTime := time.Now()
 	duration := endTime.Sub(startTime)
 
 	result := &JobResult{
-		JobName:    jobName,
-		Success:    jobSuccess,
-		StartTime:  startTime,
-		EndTime:    endTime,
-		Duration:   duration,
-		ItemsFound: len(items),
-		Analysis:   analysis,
-		RawItems:   items,
-		JobConfig:  jobConfig,
+		JobName:     jobName,
+		Success:     jobSuccess,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		Duration:    duration,
+		ItemsFound:  len(items),
+		Analysis:    analysis,
+		RawItems:    items,
+		JobConfig:   jobConfig,
+		ExitSignals: exitSignals,
 	}
 
 	e.logger.WithFields(map[string]interface{}{
@@ -370,9 +427,100 @@ func (e *Executor) convertFilters(filters config.FilterConfig) (osrs.FilterOptio
 	if filters.Volume24hMin != nil {
 		opts.Volume24hMin = filters.Volume24hMin
 	}
+	if filters.Volume20mMin != nil {
+		opts.Volume20mMin = filters.Volume20mMin
+	}
+	if filters.VolumeAction != "" {
+		opts.VolumeAction = osrs.VolumeAction(filters.VolumeAction)
+	}
+	if filters.InstaBuyVolume20mMin != nil {
+		opts.InstaBuyVolume20mMin = filters.InstaBuyVolume20mMin
+	}
+	if filters.InstaBuyVolume20mMax != nil {
+		opts.InstaBuyVolume20mMax = filters.InstaBuyVolume20mMax
+	}
+	if filters.InstaSellVolume20mMin != nil {
+		opts.InstaSellVolume20mMin = filters.InstaSellVolume20mMin
+	}
+	if filters.InstaSellVolume20mMax != nil {
+		opts.InstaSellVolume20mMax = filters.InstaSellVolume20mMax
+	}
+	if filters.InstaBuyVolume1hMin != nil {
+		opts.InstaBuyVolume1hMin = filters.InstaBuyVolume1hMin
+	}
+	if filters.InstaBuyVolume1hMax != nil {
+		opts.InstaBuyVolume1hMax = filters.InstaBuyVolume1hMax
+	}
+	if filters.InstaSellVolume1hMin != nil {
+		opts.InstaSellVolume1hMin = filters.InstaSellVolume1hMin
+	}
+	if filters.InstaSellVolume1hMax != nil {
+		opts.InstaSellVolume1hMax = filters.InstaSellVolume1hMax
+	}
+	if filters.InstaBuyVolume24hMin != nil {
+		opts.InstaBuyVolume24hMin = filters.InstaBuyVolume24hMin
+	}
+	if filters.InstaBuyVolume24hMax != nil {
+		opts.InstaBuyVolume24hMax = filters.InstaBuyVolume24hMax
+	}
+	if filters.InstaSellVolume24hMin != nil {
+		opts.InstaSellVolume24hMin = filters.InstaSellVolume24hMin
+	}
+	if filters.InstaSellVolume24hMax != nil {
+		opts.InstaSellVolume24hMax = filters.InstaSellVolume24hMax
+	}
+	if filters.VolumeFilterMode != "" {
+		opts.VolumeFilterMode = osrs.VolumeFilterMode(filters.VolumeFilterMode)
+	}
+	if filters.BuyVolume1hMin != nil {
+		opts.BuyVolume1hMin = filters.BuyVolume1hMin
+	}
+	if filters.SellVolume1hMin != nil {
+		opts.SellVolume1hMin = filters.SellVolume1hMin
+	}
+	if filters.BuyVolume24hMin != nil {
+		opts.BuyVolume24hMin = filters.BuyVolume24hMin
+	}
+	if filters.SellVolume24hMin != nil {
+		opts.SellVolume24hMin = filters.SellVolume24hMin
+	}
 	if filters.MaxHoursSinceUpdate != nil {
 		opts.MaxHoursSinceUpdate = filters.MaxHoursSinceUpdate
 	}
+	if filters.RequirePivotBreak != nil {
+		opts.RequirePivotBreak = filters.RequirePivotBreak
+	}
+	if filters.PivotLength != nil {
+		opts.PivotLength = filters.PivotLength
+	}
+	if filters.BreakRatio != nil {
+		opts.BreakRatio = filters.BreakRatio
+	}
+	if filters.ExcludeSimilarTo != nil {
+		opts.ExcludeSimilarTo = filters.ExcludeSimilarTo
+	}
+	if filters.ATRPctMax != nil {
+		opts.ATRPctMax = filters.ATRPctMax
+	}
+	if filters.MarginStddevMax != nil {
+		opts.MarginStddevMax = filters.MarginStddevMax
+	}
+	if filters.PriceEMADeviationMax != nil {
+		opts.PriceEMADeviationMax = filters.PriceEMADeviationMax
+	}
+	if filters.MaxATRPctOfPrice != nil {
+		opts.MaxATRPctOfPrice = filters.MaxATRPctOfPrice
+	}
+	if filters.MaxPriceStdDevPct != nil {
+		opts.MaxPriceStdDevPct = filters.MaxPriceStdDevPct
+	}
+	if filters.OnlyOversold != nil {
+		opts.OnlyOversold = filters.OnlyOversold
+	}
+	if filters.OnlyOverbought != nil {
+		opts.OnlyOverbought = filters.OnlyOverbought
+	}
+	opts.Expr = filters.Expr
 
 	// Set default sorting if not specified
 	if filters.SortBy != "" {
@@ -396,6 +544,123 @@ func (e *Executor) convertFilters(filters config.FilterConfig) (osrs.FilterOptio
 	return opts, nil
 }
 
+// evaluateExitSignals loads the job's persisted position store (if it
+// tracks any) and checks every open position against the job's exit
+// thresholds, falling back to position.DefaultExitConfig where jobConfig
+// doesn't override a field. A job with no Positions config returns nil.
+func (e *Executor) evaluateExitSignals(jobConfig config.JobConfig) []position.ExitSignal {
+	if jobConfig.Positions == nil {
+		return nil
+	}
+
+	storePath := jobConfig.Positions.StorePath
+	if storePath == "" {
+		storePath = defaultPositionStorePath
+	}
+
+	store := position.NewStore(storePath)
+	if err := store.Load(); err != nil {
+		e.logger.WithFields(map[string]interface{}{
+			"job_name": jobConfig.Name,
+			"error":    err.Error(),
+		}).Warn("Failed to load position store, skipping exit signals")
+		return nil
+	}
+
+	positions := store.Positions()
+	if len(positions) == 0 {
+		return nil
+	}
+
+	cfg := position.DefaultExitConfig
+	if len(jobConfig.Positions.TrailingActivationRatio) > 0 {
+		cfg.TrailingActivationRatio = jobConfig.Positions.TrailingActivationRatio
+	}
+	if len(jobConfig.Positions.TrailingCallbackRate) > 0 {
+		cfg.TrailingCallbackRate = jobConfig.Positions.TrailingCallbackRate
+	}
+	if jobConfig.Positions.StopLossPct != nil {
+		cfg.StopLossPct = *jobConfig.Positions.StopLossPct
+	}
+	if jobConfig.Positions.TakeProfitFactor != nil {
+		cfg.TakeProfitFactor = *jobConfig.Positions.TakeProfitFactor
+	}
+	if jobConfig.Positions.StaleAfter != "" {
+		if d, err := time.ParseDuration(jobConfig.Positions.StaleAfter); err == nil {
+			cfg.StaleAfter = d
+		}
+	}
+
+	return e.osrsAnalyzer.EvaluateExitSignals(positions, cfg)
+}
+
+// loadPortfolio loads the job's persisted portfolio event log (if it tracks
+// any) and wires it into the analyzer so getTopItemIDs can deprioritize
+// items already heavily held, per jobConfig.Positions.MaxOpenGPPerItem. A
+// job with no Positions config, or no MaxOpenGPPerItem set, leaves the
+// analyzer's portfolio unset.
+func (e *Executor) loadPortfolio(jobConfig config.JobConfig) {
+	if jobConfig.Positions == nil || jobConfig.Positions.MaxOpenGPPerItem == nil {
+		return
+	}
+
+	storePath := jobConfig.Positions.PortfolioStorePath
+	if storePath == "" {
+		storePath = defaultPortfolioStorePath
+	}
+
+	p := portfolio.NewPortfolio(portfolio.NewJSONStore(storePath))
+	if err := p.Load(); err != nil {
+		e.logger.WithFields(map[string]interface{}{
+			"job_name": jobConfig.Name,
+			"error":    err.Error(),
+		}).Warn("Failed to load portfolio store, skipping exposure-based deprioritization")
+		return
+	}
+
+	e.osrsAnalyzer.SetPortfolio(p, *jobConfig.Positions.MaxOpenGPPerItem)
+	e.portfolio = p
+}
+
+// buildPortfolioSummary folds e.portfolio's current exposure into the
+// positions.PortfolioSummary shape FormatItemsForAnalysisV2 expects, valuing
+// each held item against its current insta-buy price in items. Returns nil
+// if the job has no portfolio loaded (see loadPortfolio).
+//
+// Unlike pkg/positions.PositionStore.GetPortfolioSummary, this has no access
+// to a rolling buy history, so ExcludeItemIDs only covers items currently
+// held -- not items that have separately hit their 4h buy limit. That check
+// requires the Postgres-backed pkg/positions store, reachable today via
+// `osrs-flipping positions serve`, which this synchronous job path doesn't
+// depend on.
+func (e *Executor) buildPortfolioSummary(items []osrs.ItemData) *positions.PortfolioSummary {
+	if e.portfolio == nil {
+		return nil
+	}
+
+	currentPrices := make(map[int]int, len(items))
+	for _, item := range items {
+		if item.InstaBuyPrice != nil {
+			currentPrices[item.ItemID] = *item.InstaBuyPrice
+		}
+	}
+
+	summary := &positions.PortfolioSummary{
+		GPTiedUp:      make(map[int]int),
+		UnrealizedPNL: make(map[int]int),
+	}
+	for _, itemID := range e.portfolio.OpenItemIDs() {
+		summary.OpenSlots++
+		gp := e.portfolio.OpenGP(itemID)
+		summary.GPTiedUp[itemID] = gp
+		if current, ok := currentPrices[itemID]; ok {
+			summary.UnrealizedPNL[itemID] = current*e.portfolio.OpenQuantity(itemID) - gp
+		}
+		summary.ExcludeItemIDs = append(summary.ExcludeItemIDs, itemID)
+	}
+	return summary
+}
+
 // generateAnalysis generates LLM analysis for the items
 // Following the notebook pattern: configure model, generate response, clean response
 func (e *Executor) generateAnalysis(ctx context.Context, items []osrs.ItemData, jobConfig config.JobConfig) (string, error) {
@@ -411,7 +676,7 @@ func (e *Executor) generateAnalysis(ctx context.Context, items []osrs.ItemData,
 
 	// Format items for LLM input - this is our "user_prompt" equivalent
 	// Todo: Attach this file to a discord message
-	userPrompt := llm.FormatItemsForAnalysisV2(items, len(items))
+	userPrompt := llm.FormatItemsForAnalysisV2(items, len(items), e.buildPortfolioSummary(items), e.reportDir)
 
 	// Temporarily log to verify volume data is now included
 	e.logger.Debug("User prompt with volume data:")
@@ -4,8 +4,13 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"osrs-flipping/pkg/llm"
 )
 
+// discordMessageLimit is Discord's per-message character cap.
+const discordMessageLimit = 2000
+
 // OutputFormatter handles formatting job results for different outputs
 type OutputFormatter struct{}
 
@@ -61,21 +66,26 @@ func (of *OutputFormatter) FormatForMarkdown(result *JobResult) string {
 	return output.String()
 }
 
-// FormatForDiscord formats job results for Discord message
+// FormatForDiscord formats job results for a single Discord message. Analysis
+// longer than Discord's character limit is truncated -- use
+// FormatForDiscordMulti to post the full analysis across several messages
+// instead.
 func (of *OutputFormatter) FormatForDiscord(result *JobResult) string {
-	var output strings.Builder
-
-	// LLM Analysis (using smart text handling for Discord)
-	if result.Analysis != "" {
-		analysis := result.Analysis
-		const discordLimit = 2000
-
-		// Truncate analysis if it exceeds Discord's character limit
-		if len(analysis) > discordLimit {
-			analysis = analysis[:discordLimit-3] + "..."
-		}
-		output.WriteString(analysis)
+	if result.Analysis == "" {
+		return ""
 	}
+	return llm.TruncateText(result.Analysis, discordMessageLimit)
+}
 
-	return output.String()
+// FormatForDiscordMulti formats job results as an ordered list of
+// Discord-safe messages, splitting the analysis with llm.TextSplitter
+// instead of truncating it so none of the analysis is dropped. Each message
+// is numbered ("(Part i/N)") when there's more than one. Callers post the
+// slice sequentially (see Bot.sendLongMessage).
+func (of *OutputFormatter) FormatForDiscordMulti(result *JobResult) []string {
+	if result.Analysis == "" {
+		return nil
+	}
+	splitter := llm.NewTextSplitter(discordMessageLimit)
+	return splitter.SplitTextWithParts(result.Analysis)
 }
@@ -0,0 +1,177 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ExecutionTrigger records what caused a JobExecution to run.
+type ExecutionTrigger string
+
+const (
+	TriggerCron   ExecutionTrigger = "cron"
+	TriggerManual ExecutionTrigger = "manual"
+)
+
+// JobExecution is a durable record of one job run, written via a
+// HistoryStore so `!osrs history`, `!osrs rerun <execution_id>`, and
+// `!osrs logs <execution_id>` can audit past runs without scrolling
+// channel history.
+type JobExecution struct {
+	ExecutionID string
+	JobName     string
+	Trigger     ExecutionTrigger
+	StartedAt   time.Time
+	EndedAt     time.Time
+	Duration    time.Duration
+	ItemsFound  int
+	Success     bool
+	Error       string
+	Analysis    string
+}
+
+// HistoryStore persists and retrieves durable JobExecution records. It's
+// defined here, rather than alongside its Postgres implementation in
+// pkg/jobs/scheduler, because that package already imports pkg/jobs for the
+// JobExecutor interface; JobRunner satisfying the write side of a store
+// defined in pkg/jobs/scheduler would create an import cycle.
+type HistoryStore interface {
+	Record(ctx context.Context, exec JobExecution) error
+	GetExecution(ctx context.Context, executionID string) (*JobExecution, error)
+}
+
+// triggerContextKey is the context key WithTrigger/TriggerFromContext use
+// to thread a JobExecution's trigger across the JobExecutor interface
+// boundary, since JobExecutor.ExecuteJob's signature doesn't carry it.
+type triggerContextKey struct{}
+
+// WithTrigger attaches trigger to ctx so the JobRunner.RunJob call it
+// eventually reaches records the right ExecutionTrigger.
+func WithTrigger(ctx context.Context, trigger ExecutionTrigger) context.Context {
+	return context.WithValue(ctx, triggerContextKey{}, trigger)
+}
+
+// TriggerFromContext returns the trigger WithTrigger attached to ctx, or
+// TriggerManual if none was attached.
+func TriggerFromContext(ctx context.Context) ExecutionTrigger {
+	if t, ok := ctx.Value(triggerContextKey{}).(ExecutionTrigger); ok {
+		return t
+	}
+	return TriggerManual
+}
+
+// BufferedHistoryStoreConfig configures BufferedHistoryStore's flush
+// batching.
+type BufferedHistoryStoreConfig struct {
+	FlushSize  int           // Flush once this many records are buffered (default 20)
+	FlushEvery time.Duration // Flush on this interval regardless of size (default 5s)
+}
+
+// DefaultBufferedHistoryStoreConfig returns sensible defaults.
+func DefaultBufferedHistoryStoreConfig() *BufferedHistoryStoreConfig {
+	return &BufferedHistoryStoreConfig{
+		FlushSize:  20,
+		FlushEvery: 5 * time.Second,
+	}
+}
+
+// BufferedHistoryStore batches Record calls to an underlying HistoryStore,
+// flushing every FlushSize entries or FlushEvery, whichever comes first
+// (the buffered-log approach coder/coder uses for its build log pipeline).
+// This keeps a burst of job completions (e.g. ExecuteAllJobs fanning out)
+// from hitting Postgres with one INSERT per job. GetExecution passes
+// straight through, since a lookup (e.g. for `!osrs rerun`) needs
+// up-to-date data and reads are rare compared to writes.
+type BufferedHistoryStore struct {
+	inner  HistoryStore
+	config *BufferedHistoryStoreConfig
+
+	mu      sync.Mutex
+	pending []JobExecution
+
+	flushNow chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewBufferedHistoryStore wraps inner and starts its background flush
+// loop. Call Close to drain and stop it.
+func NewBufferedHistoryStore(inner HistoryStore, config *BufferedHistoryStoreConfig) *BufferedHistoryStore {
+	if config == nil {
+		config = DefaultBufferedHistoryStoreConfig()
+	}
+	if config.FlushSize < 1 {
+		config.FlushSize = 1
+	}
+
+	b := &BufferedHistoryStore{
+		inner:    inner,
+		config:   config,
+		flushNow: make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Record buffers exec for the next flush, signaling an immediate flush once
+// the buffer reaches config.FlushSize.
+func (b *BufferedHistoryStore) Record(ctx context.Context, exec JobExecution) error {
+	b.mu.Lock()
+	b.pending = append(b.pending, exec)
+	full := len(b.pending) >= b.config.FlushSize
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.flushNow <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// GetExecution passes through to the underlying store.
+func (b *BufferedHistoryStore) GetExecution(ctx context.Context, executionID string) (*JobExecution, error) {
+	return b.inner.GetExecution(ctx, executionID)
+}
+
+func (b *BufferedHistoryStore) run() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.config.FlushEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.flush()
+		case <-b.flushNow:
+			b.flush()
+		case <-b.stop:
+			b.flush()
+			return
+		}
+	}
+}
+
+// flush drains the pending buffer and writes each entry to inner. A
+// write failure is swallowed (history is an audit aid, not the source of
+// truth for job results) so one bad record can't wedge the flush loop.
+func (b *BufferedHistoryStore) flush() {
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	for _, exec := range batch {
+		_ = b.inner.Record(context.Background(), exec)
+	}
+}
+
+// Close stops the flush loop after draining any buffered executions.
+func (b *BufferedHistoryStore) Close() {
+	close(b.stop)
+	<-b.done
+}
@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProgressStage identifies where in the pipeline a running job currently is.
+type ProgressStage string
+
+const (
+	StageFetchingPrices ProgressStage = "fetching_prices"
+	StageFiltering      ProgressStage = "filtering"
+	StageLLMAnalysis    ProgressStage = "llm_analysis"
+	StageFormatting     ProgressStage = "formatting"
+)
+
+// ProgressEvent describes a single update in a job's progress, emitted over
+// a JobRunner's progress channel so subscribers (e.g. the Discord bot) can
+// render something more useful than silence until the final result.
+type ProgressEvent struct {
+	JobName    string
+	Stage      ProgressStage
+	PercentPct float64 // 0-100
+	ItemsDone  int
+	ItemsTotal int
+	BytesDone  int64
+	StartedAt  time.Time
+	Emitted    time.Time
+}
+
+// ItemsPerSecond returns the observed throughput since StartedAt, or 0 if no
+// time has elapsed yet.
+func (p ProgressEvent) ItemsPerSecond() float64 {
+	elapsed := p.Emitted.Sub(p.StartedAt).Seconds()
+	if elapsed <= 0 || p.ItemsDone == 0 {
+		return 0
+	}
+	return float64(p.ItemsDone) / elapsed
+}
+
+// ETA estimates remaining time based on observed throughput so far. Returns
+// 0 if throughput or the remaining item count is unknown.
+func (p ProgressEvent) ETA() time.Duration {
+	rate := p.ItemsPerSecond()
+	if rate <= 0 || p.ItemsTotal <= p.ItemsDone {
+		return 0
+	}
+	remaining := float64(p.ItemsTotal - p.ItemsDone)
+	return time.Duration(remaining/rate) * time.Second
+}
+
+// HumanizeBytes formats a byte count using the nearest binary unit (KiB,
+// MiB, ...), e.g. 1536 -> "1.5 KiB".
+func HumanizeBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	units := []string{"KiB", "MiB", "GiB", "TiB"}
+	return fmt.Sprintf("%.1f %s", float64(n)/float64(div), units[exp])
+}
@@ -3,12 +3,14 @@ package jobs
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"osrs-flipping/pkg/config"
 	"osrs-flipping/pkg/llm"
 	"osrs-flipping/pkg/logging"
 	"osrs-flipping/pkg/osrs"
+	"osrs-flipping/pkg/osrs/position"
 )
 
 // JobResult represents the output of a job execution
@@ -23,6 +25,11 @@ type JobResult struct {
 	Analysis   string
 	RawItems   []osrs.ItemData
 	JobConfig  config.JobConfig
+
+	// ExitSignals are recommended sells for the job's tracked open
+	// positions, if jobConfig.Positions is set (see
+	// Executor.evaluateExitSignals/osrs.Analyzer.EvaluateExitSignals).
+	ExitSignals []position.ExitSignal
 }
 
 // JobRunner handles the execution of trading analysis jobs
@@ -30,6 +37,23 @@ type JobRunner struct {
 	config   *config.Config
 	executor *Executor
 	logger   *logging.Logger
+
+	mu        sync.Mutex
+	progress  chan ProgressEvent
+	cancelers map[string]context.CancelFunc
+
+	history HistoryStore
+}
+
+// SetHistoryStore wires a durable HistoryStore into the runner so every
+// RunJob call is recorded for the `!osrs history` / `!osrs rerun
+// <execution_id>` / `!osrs logs <execution_id>` Discord commands. Pass a
+// *BufferedHistoryStore to batch the writes. Nil (the default) disables
+// history recording.
+func (jr *JobRunner) SetHistoryStore(history HistoryStore) {
+	jr.mu.Lock()
+	defer jr.mu.Unlock()
+	jr.history = history
 }
 
 // NewJobRunner creates a new job runner with the given configuration
@@ -37,27 +61,18 @@ func NewJobRunner(cfg *config.Config) (*JobRunner, error) {
 	// Create analyzer
 	analyzer := osrs.NewAnalyzer(cfg.OSRS.UserAgent)
 
-	// Parse LLM timeout
-	timeout, err := time.ParseDuration(cfg.LLM.Timeout)
+	timeout := cfg.LLM.GetTimeout()
+
+	// Create LLM provider (Ollama by default; "openai" for any
+	// OpenAI-compatible chat completions endpoint)
+	llmClient, err := llm.NewProvider(cfg.LLM.Provider, cfg.LLM.BaseURL, cfg.LLM.APIKey, timeout)
 	if err != nil {
-		timeout = 5 * time.Minute
-		// We'll log this after creating the logger
+		return nil, fmt.Errorf("failed to create LLM provider: %w", err)
 	}
 
-	// Create LLM client
-	llmClient := llm.NewClient(cfg.LLM.BaseURL, timeout)
-
 	// Create logger for the executor
 	logger := logging.NewLogger(cfg.Logging.Level, cfg.Logging.Format)
 
-	// Log timeout warning if needed
-	if err != nil {
-		logger.WithFields(map[string]interface{}{
-			"error":           err.Error(),
-			"default_timeout": timeout.String(),
-		}).Warn("Invalid LLM timeout format, using default")
-	}
-
 	// Create executor with the notebook pattern
 	executor, err := NewExecutor(cfg, logger, analyzer, llmClient, nil)
 	if err != nil {
@@ -65,12 +80,124 @@ func NewJobRunner(cfg *config.Config) (*JobRunner, error) {
 	}
 
 	return &JobRunner{
-		config:   cfg,
-		executor: executor,
-		logger:   logger,
+		config:    cfg,
+		executor:  executor,
+		logger:    logger,
+		progress:  make(chan ProgressEvent, 64),
+		cancelers: make(map[string]context.CancelFunc),
 	}, nil
 }
 
+// Analyzer exposes the runner's OSRS analyzer for tooling that needs direct
+// access beyond a normal job run, such as the backtest CLI subcommand
+// replaying historical price series.
+func (jr *JobRunner) Analyzer() *osrs.Analyzer {
+	return jr.executor.osrsAnalyzer
+}
+
+// SetReportDir enables chart rendering for every job this runner executes
+// from here on (see Executor.SetReportDir). Empty disables it again.
+func (jr *JobRunner) SetReportDir(dir string) {
+	jr.executor.SetReportDir(dir)
+}
+
+// ConvertFilters exposes the runner's filter-config translation, so external
+// tooling builds the exact same osrs.FilterOptions a live job run would use.
+func (jr *JobRunner) ConvertFilters(filters config.FilterConfig) (osrs.FilterOptions, error) {
+	return jr.executor.convertFilters(filters)
+}
+
+// RerunExecution looks up a past execution's job name via the configured
+// HistoryStore and re-runs that job asynchronously, for the `!osrs rerun
+// <execution_id>` Discord command. Returns the job name that was
+// re-triggered so the caller can report it back immediately, without
+// waiting for the (possibly long-running) rerun to finish.
+func (jr *JobRunner) RerunExecution(ctx context.Context, executionID string) (string, error) {
+	jr.mu.Lock()
+	history := jr.history
+	jr.mu.Unlock()
+
+	if history == nil {
+		return "", fmt.Errorf("no history store configured")
+	}
+
+	exec, err := history.GetExecution(ctx, executionID)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up execution %s: %w", executionID, err)
+	}
+	if exec == nil {
+		return "", fmt.Errorf("no execution found with id %s", executionID)
+	}
+
+	go func() {
+		if _, err := jr.RunJob(context.Background(), exec.JobName); err != nil {
+			jr.logger.WithFields(map[string]interface{}{
+				"job_name":     exec.JobName,
+				"execution_id": executionID,
+			}).WithError(err).Error("Rerun failed")
+		}
+	}()
+
+	return exec.JobName, nil
+}
+
+// Progress returns the channel ProgressEvents are emitted on. Subscribers
+// (e.g. discord.Bot) should read continuously; the channel is buffered but
+// a slow consumer can still cause emitProgress to drop events rather than
+// block job execution.
+func (jr *JobRunner) Progress() <-chan ProgressEvent {
+	return jr.progress
+}
+
+// emitProgress sends a ProgressEvent without blocking job execution if no
+// one is listening or the buffer is full.
+func (jr *JobRunner) emitProgress(jobName string, stage ProgressStage, startedAt time.Time, itemsDone, itemsTotal int) {
+	event := ProgressEvent{
+		JobName:    jobName,
+		Stage:      stage,
+		ItemsDone:  itemsDone,
+		ItemsTotal: itemsTotal,
+		StartedAt:  startedAt,
+		Emitted:    time.Now(),
+	}
+	if itemsTotal > 0 {
+		event.PercentPct = 100 * float64(itemsDone) / float64(itemsTotal)
+	}
+	select {
+	case jr.progress <- event:
+	default:
+	}
+}
+
+// CancelJob cancels a running job's context, if it is currently tracked,
+// for the `!osrs cancel <jobName>` command.
+func (jr *JobRunner) CancelJob(jobName string) bool {
+	jr.mu.Lock()
+	defer jr.mu.Unlock()
+
+	cancel, ok := jr.cancelers[jobName]
+	if !ok {
+		return false
+	}
+	cancel()
+	return true
+}
+
+// trackCancel registers a job's cancel func for the duration of fn.
+func (jr *JobRunner) trackCancel(jobName string, cancel context.CancelFunc, fn func()) {
+	jr.mu.Lock()
+	jr.cancelers[jobName] = cancel
+	jr.mu.Unlock()
+
+	defer func() {
+		jr.mu.Lock()
+		delete(jr.cancelers, jobName)
+		jr.mu.Unlock()
+	}()
+
+	fn()
+}
+
 // LoadData loads the base OSRS data (should be called once at startup)
 func (jr *JobRunner) LoadData(ctx context.Context) error {
 	jr.logger.Info("Loading OSRS base data")
@@ -83,10 +210,64 @@ func (jr *JobRunner) RefreshData(ctx context.Context) error {
 	return jr.executor.osrsAnalyzer.LoadData(ctx, true)
 }
 
-// RunJob executes a specific job by name and returns the result
+// RunJob executes a specific job by name and returns the result. The job is
+// cancelable via CancelJob(jobName) while it is running, and emits
+// ProgressEvents on Progress() as it moves through stages.
 func (jr *JobRunner) RunJob(ctx context.Context, jobName string) (*JobResult, error) {
-	// Delegate to the executor which has the notebook pattern
-	return jr.executor.ExecuteJobWithResult(ctx, jobName)
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var result *JobResult
+	var err error
+	startedAt := time.Now()
+
+	jr.trackCancel(jobName, cancel, func() {
+		jr.emitProgress(jobName, StageFetchingPrices, startedAt, 0, 0)
+		// Delegate to the executor which has the notebook pattern
+		result, err = jr.executor.ExecuteJobWithResult(jobCtx, jobName)
+		jr.emitProgress(jobName, StageFormatting, startedAt, result.ItemsFound, result.ItemsFound)
+	})
+
+	jr.recordExecution(ctx, jobName, startedAt, result, err)
+	return result, err
+}
+
+// recordExecution writes a JobExecution to the configured HistoryStore, if
+// one is set. It's a no-op on the common path (no history store
+// configured) and best-effort otherwise - a history write failure is only
+// logged and doesn't change the result RunJob returns.
+func (jr *JobRunner) recordExecution(ctx context.Context, jobName string, startedAt time.Time, result *JobResult, runErr error) {
+	jr.mu.Lock()
+	history := jr.history
+	jr.mu.Unlock()
+
+	if history == nil || result == nil {
+		return
+	}
+
+	exec := JobExecution{
+		ExecutionID: fmt.Sprintf("%s-%d", jobName, startedAt.UnixNano()),
+		JobName:     jobName,
+		Trigger:     TriggerFromContext(ctx),
+		StartedAt:   result.StartTime,
+		EndedAt:     result.EndTime,
+		Duration:    result.Duration,
+		ItemsFound:  result.ItemsFound,
+		Success:     result.Success && runErr == nil,
+		Analysis:    result.Analysis,
+	}
+	if result.Error != nil {
+		exec.Error = result.Error.Error()
+	} else if runErr != nil {
+		exec.Error = runErr.Error()
+	}
+
+	if err := history.Record(context.Background(), exec); err != nil {
+		jr.logger.WithFields(map[string]interface{}{
+			"job_name":     jobName,
+			"execution_id": exec.ExecutionID,
+		}).WithError(err).Warn("Failed to record job execution history")
+	}
 }
 
 // RunAllJobs executes all enabled jobs and returns their results
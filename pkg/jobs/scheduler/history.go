@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"osrs-flipping/pkg/discord"
+	"osrs-flipping/pkg/jobs"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HistoryStore persists durable jobs.JobExecution records in the
+// job_executions table (see Schema), satisfying jobs.HistoryStore so
+// JobRunner can write to it via JobRunner.SetHistoryStore.
+type HistoryStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewHistoryStore creates a new HistoryStore backed by the given pool.
+func NewHistoryStore(pool *pgxpool.Pool) *HistoryStore {
+	return &HistoryStore{pool: pool}
+}
+
+// Record upserts exec, so a rerun of the same execution ID (which shouldn't
+// normally happen, but can if a caller retries after a network error)
+// overwrites rather than duplicates.
+func (h *HistoryStore) Record(ctx context.Context, exec jobs.JobExecution) error {
+	_, err := h.pool.Exec(ctx, `
+		INSERT INTO job_executions (execution_id, job_name, trigger, started_at, ended_at, duration_seconds, items_found, success, error, analysis)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (execution_id) DO UPDATE SET
+			ended_at         = EXCLUDED.ended_at,
+			duration_seconds = EXCLUDED.duration_seconds,
+			items_found      = EXCLUDED.items_found,
+			success          = EXCLUDED.success,
+			error            = EXCLUDED.error,
+			analysis         = EXCLUDED.analysis
+	`, exec.ExecutionID, exec.JobName, string(exec.Trigger), exec.StartedAt, exec.EndedAt,
+		exec.Duration.Seconds(), exec.ItemsFound, exec.Success, exec.Error, exec.Analysis)
+	if err != nil {
+		return fmt.Errorf("failed to record execution %s: %w", exec.ExecutionID, err)
+	}
+	return nil
+}
+
+// GetExecution returns a single execution by ID, or nil if none exists.
+func (h *HistoryStore) GetExecution(ctx context.Context, executionID string) (*jobs.JobExecution, error) {
+	exec, err := scanExecution(h.pool.QueryRow(ctx, `
+		SELECT execution_id, job_name, trigger, started_at, ended_at, duration_seconds, items_found, success, error, analysis
+		FROM job_executions WHERE execution_id = $1
+	`, executionID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get execution %s: %w", executionID, err)
+	}
+	return exec, nil
+}
+
+// ListRecent returns the most recent executions across all jobs, newest
+// first, for the `!osrs history` Discord command.
+func (h *HistoryStore) ListRecent(ctx context.Context, limit int) ([]jobs.JobExecution, error) {
+	rows, err := h.pool.Query(ctx, `
+		SELECT execution_id, job_name, trigger, started_at, ended_at, duration_seconds, items_found, success, error, analysis
+		FROM job_executions
+		ORDER BY started_at DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent executions: %w", err)
+	}
+	defer rows.Close()
+
+	var execs []jobs.JobExecution
+	for rows.Next() {
+		exec, err := scanExecution(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan execution row: %w", err)
+		}
+		execs = append(execs, *exec)
+	}
+	return execs, rows.Err()
+}
+
+// rowScanner is satisfied by both pgx.Row (QueryRow) and pgx.Rows (Query),
+// letting GetExecution and ListRecent share one scan helper.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanExecution(row rowScanner) (*jobs.JobExecution, error) {
+	var exec jobs.JobExecution
+	var trigger string
+	var durationSeconds float64
+	if err := row.Scan(
+		&exec.ExecutionID, &exec.JobName, &trigger, &exec.StartedAt, &exec.EndedAt,
+		&durationSeconds, &exec.ItemsFound, &exec.Success, &exec.Error, &exec.Analysis,
+	); err != nil {
+		return nil, err
+	}
+	exec.Trigger = jobs.ExecutionTrigger(trigger)
+	exec.Duration = time.Duration(durationSeconds * float64(time.Second))
+	return &exec, nil
+}
+
+// HistoryDiscordProvider adapts a HistoryStore to discord.HistoryProvider so
+// `!osrs history` / `!osrs logs <execution_id>` can read directly from
+// Postgres via Bot.SetHistoryProvider.
+type HistoryDiscordProvider struct {
+	Store *HistoryStore
+}
+
+// ListRecent implements discord.HistoryProvider.
+func (p *HistoryDiscordProvider) ListRecent(ctx context.Context, limit int) ([]discord.ExecutionSummary, error) {
+	execs, err := p.Store.ListRecent(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]discord.ExecutionSummary, len(execs))
+	for i, e := range execs {
+		summaries[i] = toExecutionSummary(e)
+	}
+	return summaries, nil
+}
+
+// Get implements discord.HistoryProvider.
+func (p *HistoryDiscordProvider) Get(ctx context.Context, executionID string) (*discord.ExecutionSummary, error) {
+	exec, err := p.Store.GetExecution(ctx, executionID)
+	if err != nil {
+		return nil, err
+	}
+	if exec == nil {
+		return nil, nil
+	}
+	summary := toExecutionSummary(*exec)
+	return &summary, nil
+}
+
+func toExecutionSummary(e jobs.JobExecution) discord.ExecutionSummary {
+	return discord.ExecutionSummary{
+		ExecutionID: e.ExecutionID,
+		JobName:     e.JobName,
+		Trigger:     string(e.Trigger),
+		StartedAt:   e.StartedAt,
+		EndedAt:     e.EndedAt,
+		Duration:    e.Duration,
+		ItemsFound:  e.ItemsFound,
+		Success:     e.Success,
+		Error:       e.Error,
+		Analysis:    e.Analysis,
+	}
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"osrs-flipping/pkg/logging"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// scheduledJobsChannel is the Postgres NOTIFY channel Store.Enqueue signals
+// on, so a Listener-backed Worker wakes immediately instead of waiting out
+// its poll interval.
+const scheduledJobsChannel = "scheduled_jobs_new"
+
+// Listener wakes a Worker as soon as a new job is enqueued, by LISTENing on
+// scheduledJobsChannel, instead of leaving it to discover the row on its
+// next poll tick. ClaimNext's SELECT ... FOR UPDATE SKIP LOCKED is what
+// actually arbitrates which of several replicas wins a given job; Listener
+// only shortens how long an idle replica waits before trying.
+type Listener struct {
+	pool   *pgxpool.Pool
+	logger *logging.Logger
+	wake   chan struct{}
+}
+
+// NewListener creates a Listener. Call Run in its own goroutine to start
+// listening; Run blocks until ctx is canceled.
+func NewListener(pool *pgxpool.Pool, logger *logging.Logger) *Listener {
+	return &Listener{pool: pool, logger: logger, wake: make(chan struct{}, 1)}
+}
+
+// Wake returns the channel a Worker selects on alongside its poll ticker.
+// It receives a value (non-blocking; one pending wake is enough to trigger
+// a re-poll) whenever Store.Enqueue fires a NOTIFY.
+func (l *Listener) Wake() <-chan struct{} {
+	return l.wake
+}
+
+// Run holds a dedicated connection LISTENing on scheduledJobsChannel until
+// ctx is canceled, signaling Wake on every notification. A dropped
+// connection is retried after a short delay so a blip doesn't permanently
+// fall back to poll-only.
+func (l *Listener) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := l.listenOnce(ctx); err != nil && ctx.Err() == nil {
+			l.logger.WithComponent("job_listener").WithError(err).Warn("Lost Postgres LISTEN connection, retrying")
+			select {
+			case <-time.After(5 * time.Second):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (l *Listener) listenOnce(ctx context.Context) error {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection for LISTEN: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+scheduledJobsChannel); err != nil {
+		return fmt.Errorf("failed to LISTEN on %s: %w", scheduledJobsChannel, err)
+	}
+
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			return err
+		}
+		select {
+		case l.wake <- struct{}{}:
+		default:
+		}
+	}
+}
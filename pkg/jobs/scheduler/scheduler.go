@@ -0,0 +1,399 @@
+// Package scheduler provides a Postgres-backed job queue so trading
+// analyses survive process restarts instead of living only in
+// cmd/main.go's one-shot RunAllJobs pass. It expects a `scheduled_jobs`
+// table (see Schema) and a `job_triggers` table of cron-like recurring
+// schedules.
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"osrs-flipping/pkg/discord"
+	"osrs-flipping/pkg/logging"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Priority controls the order in which due jobs are claimed by workers;
+// higher values are claimed first.
+type Priority int
+
+const (
+	PriorityNormal Priority = 1
+	PriorityRescan Priority = 2
+	PriorityBackup Priority = 4
+)
+
+// Status is the lifecycle state of a persisted job.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Schema is the DDL the scheduler expects to already be applied (via the
+// repo's own migration tooling); it's documented here rather than run
+// automatically so operators control their own migrations.
+const Schema = `
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+	id           BIGSERIAL PRIMARY KEY,
+	job_name     TEXT NOT NULL,
+	priority     INT NOT NULL DEFAULT 1,
+	scheduled_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	started_at   TIMESTAMPTZ,
+	ended_at     TIMESTAMPTZ,
+	status       TEXT NOT NULL DEFAULT 'pending',
+	in_work      BOOLEAN NOT NULL DEFAULT false,
+	payload      JSONB,
+	result       JSONB,
+	attempts     INT NOT NULL DEFAULT 0,
+	last_error   TEXT
+);
+
+CREATE TABLE IF NOT EXISTS job_triggers (
+	id       BIGSERIAL PRIMARY KEY,
+	job_name TEXT NOT NULL,
+	cron     TEXT NOT NULL,
+	priority INT NOT NULL DEFAULT 1,
+	enabled  BOOLEAN NOT NULL DEFAULT true
+);
+
+CREATE TABLE IF NOT EXISTS job_executions (
+	execution_id     TEXT PRIMARY KEY,
+	job_name         TEXT NOT NULL,
+	trigger          TEXT NOT NULL,
+	started_at       TIMESTAMPTZ NOT NULL,
+	ended_at         TIMESTAMPTZ NOT NULL,
+	duration_seconds DOUBLE PRECISION NOT NULL,
+	items_found      INT NOT NULL DEFAULT 0,
+	success          BOOLEAN NOT NULL DEFAULT false,
+	error            TEXT NOT NULL DEFAULT '',
+	analysis         TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_executions_started_at ON job_executions (started_at DESC);
+`
+
+// Job is a single row of scheduled_jobs.
+type Job struct {
+	ID          int64
+	JobName     string
+	Priority    Priority
+	ScheduledAt time.Time
+	StartedAt   *time.Time
+	EndedAt     *time.Time
+	Status      Status
+	Attempts    int
+	LastError   string
+}
+
+// Trigger is a recurring cron-like schedule that enqueues jobs.
+type Trigger struct {
+	ID       int64
+	JobName  string
+	Cron     string
+	Priority Priority
+	Enabled  bool
+}
+
+// maxAttempts bounds retry-with-backoff before a job is left in StatusFailed.
+const maxAttempts = 5
+
+// Store persists and claims scheduled jobs in Postgres.
+type Store struct {
+	pool   *pgxpool.Pool
+	logger *logging.Logger
+}
+
+// NewStore creates a new scheduler Store backed by the given pool.
+func NewStore(pool *pgxpool.Pool, logger *logging.Logger) *Store {
+	return &Store{pool: pool, logger: logger}
+}
+
+// Enqueue inserts a new pending job to run at (or after) scheduledAt, then
+// notifies scheduledJobsChannel so any Listener-backed Worker wakes
+// immediately instead of waiting out its poll interval (see listen.go).
+func (s *Store) Enqueue(ctx context.Context, jobName string, priority Priority, scheduledAt time.Time) (int64, error) {
+	var id int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO scheduled_jobs (job_name, priority, scheduled_at, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`,
+		jobName, priority, scheduledAt, StatusPending,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue job %s: %w", jobName, err)
+	}
+
+	if _, err := s.pool.Exec(ctx, "SELECT pg_notify($1, $2)", scheduledJobsChannel, jobName); err != nil {
+		// A missed NOTIFY just means the worker discovers this job on its
+		// next poll tick instead of immediately, so it's logged rather than
+		// failing the enqueue outright.
+		s.logger.WithComponent("job_store").WithField("job_name", jobName).WithError(err).Warn("Failed to notify listeners of new job")
+	}
+
+	return id, nil
+}
+
+// ClaimNext atomically claims the highest-priority due job using
+// SELECT ... FOR UPDATE SKIP LOCKED so multiple workers can pull from the
+// same table without double-processing a job.
+func (s *Store) ClaimNext(ctx context.Context) (*Job, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin claim transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var job Job
+	err = tx.QueryRow(ctx, `
+		SELECT id, job_name, priority, scheduled_at, status, attempts
+		FROM scheduled_jobs
+		WHERE status = $1 AND in_work = false AND scheduled_at <= now()
+		ORDER BY priority DESC, scheduled_at ASC
+		FOR UPDATE SKIP LOCKED
+		LIMIT 1`,
+		StatusPending,
+	).Scan(&job.ID, &job.JobName, &job.Priority, &job.ScheduledAt, &job.Status, &job.Attempts)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim next job: %w", err)
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(ctx, `
+		UPDATE scheduled_jobs SET in_work = true, status = $1, started_at = $2
+		WHERE id = $3`,
+		StatusRunning, now, job.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark job %d running: %w", job.ID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit claim for job %d: %w", job.ID, err)
+	}
+
+	job.Status = StatusRunning
+	job.StartedAt = &now
+	return &job, nil
+}
+
+// Complete marks a claimed job as succeeded or failed. On failure, if
+// attempts remain, the job is rescheduled with exponential backoff instead
+// of being left terminally failed.
+func (s *Store) Complete(ctx context.Context, jobID int64, runErr error) error {
+	now := time.Now()
+
+	if runErr == nil {
+		_, err := s.pool.Exec(ctx, `
+			UPDATE scheduled_jobs SET status = $1, in_work = false, ended_at = $2
+			WHERE id = $3`,
+			StatusSucceeded, now, jobID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to mark job %d succeeded: %w", jobID, err)
+		}
+		return nil
+	}
+
+	var attempts int
+	err := s.pool.QueryRow(ctx, `SELECT attempts FROM scheduled_jobs WHERE id = $1`, jobID).Scan(&attempts)
+	if err != nil {
+		return fmt.Errorf("failed to read attempts for job %d: %w", jobID, err)
+	}
+	attempts++
+
+	if attempts >= maxAttempts {
+		_, err = s.pool.Exec(ctx, `
+			UPDATE scheduled_jobs
+			SET status = $1, in_work = false, ended_at = $2, attempts = $3, last_error = $4
+			WHERE id = $5`,
+			StatusFailed, now, attempts, runErr.Error(), jobID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to mark job %d failed: %w", jobID, err)
+		}
+		return nil
+	}
+
+	backoff := time.Duration(1<<uint(attempts)) * time.Second
+	_, err = s.pool.Exec(ctx, `
+		UPDATE scheduled_jobs
+		SET status = $1, in_work = false, scheduled_at = $2, attempts = $3, last_error = $4
+		WHERE id = $5`,
+		StatusPending, now.Add(backoff), attempts, runErr.Error(), jobID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule job %d: %w", jobID, err)
+	}
+
+	s.logger.WithComponent("job_scheduler").WithFields(map[string]interface{}{
+		"job_id":   jobID,
+		"attempts": attempts,
+		"backoff":  backoff,
+	}).Warn("Job failed, rescheduled with backoff")
+
+	return nil
+}
+
+// Upcoming returns pending/running jobs ordered by scheduled time, for the
+// `!osrs schedule` Discord command.
+func (s *Store) Upcoming(ctx context.Context, limit int) ([]Job, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, job_name, priority, scheduled_at, status, attempts
+		FROM scheduled_jobs
+		WHERE status IN ($1, $2)
+		ORDER BY scheduled_at ASC
+		LIMIT $3`,
+		StatusPending, StatusRunning, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list upcoming jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		var j Job
+		if err := rows.Scan(&j.ID, &j.JobName, &j.Priority, &j.ScheduledAt, &j.Status, &j.Attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan job row: %w", err)
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// DiscordProvider adapts a Store to discord.ScheduleProvider so `!osrs
+// schedule` can read directly from Postgres via Bot.SetScheduleProvider.
+type DiscordProvider struct {
+	Store *Store
+}
+
+// Upcoming implements discord.ScheduleProvider.
+func (p *DiscordProvider) Upcoming(ctx context.Context, limit int) ([]discord.ScheduledJobSummary, error) {
+	jobs, err := p.Store.Upcoming(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]discord.ScheduledJobSummary, len(jobs))
+	for i, j := range jobs {
+		summaries[i] = discord.ScheduledJobSummary{
+			JobName:     j.JobName,
+			Status:      string(j.Status),
+			ScheduledAt: j.ScheduledAt,
+		}
+	}
+	return summaries, nil
+}
+
+// dbSnapshot is the JSON shape written/read by ExportSnapshot/ImportSnapshot.
+type dbSnapshot struct {
+	Jobs     []Job     `json:"jobs"`
+	Triggers []Trigger `json:"triggers"`
+}
+
+// ExportSnapshot satisfies discord.DBSnapshotter, producing a JSON snapshot
+// for `!osrs backup export` of the pending/running scheduled jobs (via
+// Upcoming, at most 10000) and the enabled triggers. Succeeded or failed
+// jobs and disabled triggers are not included.
+func (s *Store) ExportSnapshot(ctx context.Context) (json.RawMessage, error) {
+	jobs, err := s.Upcoming(ctx, 10000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot jobs: %w", err)
+	}
+	triggers, err := s.Triggers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot triggers: %w", err)
+	}
+
+	data, err := json.Marshal(dbSnapshot{Jobs: jobs, Triggers: triggers})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// ImportSnapshot satisfies discord.DBSnapshotter, restoring jobs/triggers
+// from a snapshot produced by ExportSnapshot inside a single transaction.
+func (s *Store) ImportSnapshot(ctx context.Context, data json.RawMessage) error {
+	var snapshot dbSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin snapshot restore: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, j := range snapshot.Jobs {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO scheduled_jobs (id, job_name, priority, scheduled_at, status, attempts)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (id) DO UPDATE SET
+				job_name = EXCLUDED.job_name,
+				priority = EXCLUDED.priority,
+				scheduled_at = EXCLUDED.scheduled_at,
+				status = EXCLUDED.status,
+				attempts = EXCLUDED.attempts`,
+			j.ID, j.JobName, j.Priority, j.ScheduledAt, j.Status, j.Attempts,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore job %d: %w", j.ID, err)
+		}
+	}
+
+	for _, t := range snapshot.Triggers {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO job_triggers (id, job_name, cron, priority, enabled)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (id) DO UPDATE SET
+				job_name = EXCLUDED.job_name,
+				cron = EXCLUDED.cron,
+				priority = EXCLUDED.priority,
+				enabled = EXCLUDED.enabled`,
+			t.ID, t.JobName, t.Cron, t.Priority, t.Enabled,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore trigger %d: %w", t.ID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit snapshot restore: %w", err)
+	}
+	return nil
+}
+
+// Triggers returns all enabled recurring schedules.
+func (s *Store) Triggers(ctx context.Context) ([]Trigger, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, job_name, cron, priority, enabled FROM job_triggers WHERE enabled = true`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []Trigger
+	for rows.Next() {
+		var t Trigger
+		if err := rows.Scan(&t.ID, &t.JobName, &t.Cron, &t.Priority, &t.Enabled); err != nil {
+			return nil, fmt.Errorf("failed to scan trigger row: %w", err)
+		}
+		triggers = append(triggers, t)
+	}
+	return triggers, rows.Err()
+}
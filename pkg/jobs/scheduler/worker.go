@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"osrs-flipping/pkg/config"
+	"osrs-flipping/pkg/jobs"
+	"osrs-flipping/pkg/logging"
+)
+
+// Worker repeatedly claims and runs due jobs from a Store.
+type Worker struct {
+	store     *Store
+	executor  jobs.JobExecutor
+	cfg       *config.Config
+	logger    *logging.Logger
+	pollEvery time.Duration
+	listener  *Listener
+}
+
+// SetListener wires a Listener into the Worker so Run wakes immediately on
+// a NOTIFY from Store.Enqueue instead of waiting out pollEvery. Optional:
+// a Worker without one just polls on pollEvery alone.
+func (w *Worker) SetListener(l *Listener) {
+	w.listener = l
+}
+
+// NewWorker creates a Worker that polls the Store for due jobs every
+// pollEvery (a sensible default is applied when pollEvery <= 0).
+func NewWorker(store *Store, executor jobs.JobExecutor, cfg *config.Config, logger *logging.Logger, pollEvery time.Duration) *Worker {
+	if pollEvery <= 0 {
+		pollEvery = 5 * time.Second
+	}
+	return &Worker{store: store, executor: executor, cfg: cfg, logger: logger, pollEvery: pollEvery}
+}
+
+// Run polls for and executes due jobs until ctx is canceled. Multiple
+// Workers (e.g. across replicas) may call Run concurrently against the same
+// Store; ClaimNext's SKIP LOCKED semantics prevent double-processing. If
+// SetListener was called, Run also wakes immediately on a NOTIFY instead of
+// waiting out pollEvery, so a freshly enqueued job doesn't sit idle for a
+// full poll interval on an otherwise-idle replica.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollEvery)
+	defer ticker.Stop()
+
+	var wake <-chan struct{}
+	if w.listener != nil {
+		wake = w.listener.Wake()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		case <-wake:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+func (w *Worker) runOnce(ctx context.Context) {
+	for {
+		job, err := w.store.ClaimNext(ctx)
+		if err != nil {
+			w.logger.WithComponent("job_worker").WithError(err).Error("Failed to claim next job")
+			return
+		}
+		if job == nil {
+			return
+		}
+
+		jobCfg := w.cfg.GetJobByName(job.JobName)
+		if jobCfg == nil {
+			_ = w.store.Complete(ctx, job.ID, fmt.Errorf("job %s not found in configuration", job.JobName))
+			continue
+		}
+
+		w.logger.WithComponent("job_worker").WithField("job_id", job.ID).WithField("job_name", job.JobName).Info("Running claimed job")
+		runErr := w.executor.ExecuteJob(jobs.WithTrigger(ctx, jobs.TriggerCron), *jobCfg)
+		if err := w.store.Complete(ctx, job.ID, runErr); err != nil {
+			w.logger.WithComponent("job_worker").WithError(err).Error("Failed to record job completion")
+		}
+	}
+}
+
+// EnqueueDueTriggers enqueues a pending job, due now, for every trigger in
+// triggers; it does not evaluate each trigger's Cron expression. Callers
+// typically invoke this from their own cron.Cron (see pkg/scheduler) rather
+// than re-implementing cron parsing here.
+func (w *Worker) EnqueueDueTriggers(ctx context.Context, triggers []Trigger) error {
+	for _, t := range triggers {
+		if _, err := w.store.Enqueue(ctx, t.JobName, t.Priority, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
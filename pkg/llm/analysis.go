@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"osrs-flipping/pkg/osrs"
+	"osrs-flipping/pkg/positions"
+	"osrs-flipping/pkg/reporting"
 )
 
 // TradingAnalysisRequest represents a structured request for LLM trading analysis
@@ -146,6 +148,11 @@ type FilteredItemData struct {
 	InstaBuyPriceTrend1w   *string `json:"insta_buy_price_trend_1w,omitempty"`
 	InstaSellPriceTrend1m  *string `json:"insta_sell_price_trend_1m,omitempty"`
 	InstaBuyPriceTrend1m   *string `json:"insta_buy_price_trend_1m,omitempty"`
+
+	// HAStreak1h is the number of consecutive same-direction Heikin-Ashi
+	// candles behind InstaBuyPriceTrend1h, when IndicatorConfig.UseHeikinAshi
+	// is on (see osrs.ItemData.HAStreak1h).
+	HAStreak1h *int `json:"ha_streak_1h,omitempty"`
 }
 
 // FormatItemsForAnalysis creates a compressed array representation of items for LLM analysis
@@ -271,7 +278,17 @@ Data: %s`, string(schemaJSON), string(dataJSON))
 //	  "target_buy_price": "0.00",
 //	  "target_sell_price": "4673005.00"
 //	},
-func FormatItemsForAnalysisV2(items []osrs.ItemData, maxItems int) string {
+//
+// reportDir, if non-empty, saves the JSON to output/data/ (as this used to
+// only do behind a dead `if false`) and renders a reporting.
+// MarginVolumeScatter of items into reportDir (see GenerateReports). Empty
+// skips both -- chart rendering is opt-in so a caller that doesn't pass
+// reportDir (e.g. a test) doesn't touch the filesystem. Item-level price
+// overlays and the positions equity curve aren't rendered here: items
+// carries no bucket history series, and portfolio has no closed-trade
+// list, so those charts are generated where that data actually lives (see
+// `osrs-flipping positions report`).
+func FormatItemsForAnalysisV2(items []osrs.ItemData, maxItems int, portfolio *positions.PortfolioSummary, reportDir string) string {
 	if len(items) == 0 {
 		return `{"trading_opportunities": [], "message": "No items available for analysis."}`
 	}
@@ -301,8 +318,24 @@ func FormatItemsForAnalysisV2(items []osrs.ItemData, maxItems int) string {
 		// Trend indicators
 		TrendSignals map[string]*string `json:"trend_signals,omitempty"`
 
+		// Volatility indicators: ATR as a fraction of insta_sell_price
+		// (comparable across items regardless of price scale) and the
+		// Elliott-Wave Oscillator's current value/sign-flip count, a finer
+		// read than TrendSignals' increasing/decreasing/flat strings for
+		// telling a trending item from a choppy one.
+		VolatilitySignals map[string]interface{} `json:"volatility_signals,omitempty"`
+
 		// Timing info
 		LastUpdated map[string]interface{} `json:"last_updated,omitempty"`
+
+		// Staged entry/exit plan: split the buy limit across several price
+		// layers below the target buy price, then trail exits up from there.
+		EntryLayers    []osrs.EntryLayer    `json:"entry_layers,omitempty"`
+		TrailingLevels []osrs.TrailingLevel `json:"trailing_levels,omitempty"`
+
+		// Nearest neighbors in PCA feature space, in case this item isn't
+		// available and the LLM wants to suggest a substitute.
+		SimilarItems []int `json:"similar_items,omitempty"`
 	}
 
 	opportunities := make([]TradingOpportunity, len(items))
@@ -391,6 +424,23 @@ func FormatItemsForAnalysisV2(items []osrs.ItemData, maxItems int) string {
 			trendSignals["target_buy_price_trend_1month"] = item.InstaSellPriceTrend1m
 		}
 
+		volatilitySignals := make(map[string]interface{})
+		if item.ATR1hPct != nil {
+			volatilitySignals["atr_1h_pct"] = fmt.Sprintf("%.4f", *item.ATR1hPct)
+		}
+		if item.ATR24hPct != nil {
+			volatilitySignals["atr_24h_pct"] = fmt.Sprintf("%.4f", *item.ATR24hPct)
+		}
+		if item.EWO5m != nil {
+			volatilitySignals["ewo_5m"] = fmt.Sprintf("%.2f", *item.EWO5m)
+		}
+		if item.EWOSignalFlips1h != nil {
+			volatilitySignals["ewo_signal_flips_1h"] = *item.EWOSignalFlips1h
+		}
+		if item.HAStreak1h != nil {
+			volatilitySignals["ha_streak_1h"] = *item.HAStreak1h
+		}
+
 		// Last updated timestamps using OSRS field names
 		if item.LastInstaBuyTime != nil {
 			lastUpdated["last_target_sell_price_time"] = item.LastInstaBuyTime.Format(time.RFC3339)
@@ -403,16 +453,21 @@ func FormatItemsForAnalysisV2(items []osrs.ItemData, maxItems int) string {
 		afterTaxPct := item.MarginPct - 2
 
 		opportunities[i] = TradingOpportunity{
-			ItemID:        item.ItemID,
-			Name:          item.Name,
-			LastSellPrice: item.InstaBuyPrice,
-			LastBuyPrice:  item.InstaSellPrice,
-			MarginGP:      int(afterTax),
-			MarginPct:     fmt.Sprintf("%.2f", afterTaxPct),
-			VolumeMetrics: volumeMetrics,
-			PriceAverages: priceAverages,
-			TrendSignals:  trendSignals,
-			LastUpdated:   lastUpdated,
+			ItemID:            item.ItemID,
+			Name:              item.Name,
+			LastSellPrice:     item.InstaBuyPrice,
+			LastBuyPrice:      item.InstaSellPrice,
+			MarginGP:          int(afterTax),
+			MarginPct:         fmt.Sprintf("%.2f", afterTaxPct),
+			VolumeMetrics:     volumeMetrics,
+			PriceAverages:     priceAverages,
+			TrendSignals:      trendSignals,
+			VolatilitySignals: volatilitySignals,
+			LastUpdated:       lastUpdated,
+
+			EntryLayers:    item.EntryLayers,
+			TrailingLevels: item.TrailingLevels,
+			SimilarItems:   item.SimilarItems,
 		}
 	}
 
@@ -428,27 +483,38 @@ func FormatItemsForAnalysisV2(items []osrs.ItemData, maxItems int) string {
 		},
 	}
 
-	data, err := json.MarshalIndent(opportunities, "", "  ")
+	response := map[string]interface{}{
+		"trading_opportunities": opportunities,
+	}
+	if portfolio != nil {
+		response["portfolio"] = map[string]interface{}{
+			"open_slots":       portfolio.OpenSlots,
+			"gp_tied_up":       portfolio.GPTiedUp,
+			"unrealized_pnl":   portfolio.UnrealizedPNL,
+			"exclude_item_ids": portfolio.ExcludeItemIDs,
+			"note":             "exclude_item_ids are already held or at their 4h buy limit; do not recommend them again",
+		}
+	}
+
+	data, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
 		return fmt.Sprintf("Error marshaling trading opportunities: %v", err)
 	}
 
-	// TODO: make this configurable or only when run via cli
-	// And/or have the bot attach the file to it's message
-	// Save to file with timestamp in output/data/ directory
-	if false {
-		timestamp := time.Now().Format("2006-01-02T15-04-05")
-		filename := fmt.Sprintf("output/data/analysis_v2-%s.json", timestamp)
+	if reportDir != "" {
+		timestamp := time.Now()
 
-		// Create directory if it doesn't exist
+		filename := fmt.Sprintf("output/data/analysis_v2-%s.json", timestamp.Format("2006-01-02T15-04-05"))
 		if err := os.MkdirAll("output/data", 0755); err != nil {
 			fmt.Printf("Warning: Could not create directory: %v\n", err)
-		}
-
-		if err := os.WriteFile(filename, data, 0644); err != nil {
+		} else if err := os.WriteFile(filename, data, 0644); err != nil {
 			// Log error but don't fail the function
 			fmt.Printf("Warning: Could not save analysis to %s: %v\n", filename, err)
 		}
+
+		if _, err := reporting.GenerateReports(reportDir, timestamp, nil, nil, items); err != nil {
+			fmt.Printf("Warning: Could not render reports to %s: %v\n", reportDir, err)
+		}
 	}
 
 	return string(data)
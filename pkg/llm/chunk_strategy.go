@@ -0,0 +1,407 @@
+package llm
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ChunkStrategy splits content into chunks, none exceeding maxLength bytes,
+// with one exception in the built-in strategies: a chunk of a split markdown
+// table always holds the header plus at least one row, and a chunk of a
+// split code fence at least one body line, even beyond maxLength.
+// TextSplitter.SplitText delegates to one (LineBlockStrategy by default)
+// rather than hard-coding a single splitting algorithm, so a caller that
+// needs paragraph- or token-aware chunking can plug one in instead.
+type ChunkStrategy interface {
+	Split(content string, maxLength int) []string
+}
+
+// runeSafeCut returns the largest byte index <= limit in s that doesn't
+// fall inside a multi-byte UTF-8 rune, so content[:runeSafeCut(content, n)]
+// is always valid UTF-8 even when content[n] itself would split one.
+func runeSafeCut(s string, limit int) int {
+	if limit >= len(s) {
+		return len(s)
+	}
+	if limit <= 0 {
+		return 0
+	}
+	i := limit
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
+
+// splitRuneSafe slices s into chunks of at most maxLength bytes, never
+// cutting inside a multi-byte rune. Used as the last-resort splitter when a
+// single token, line, or sentence is still too long on its own.
+func splitRuneSafe(s string, maxLength int) []string {
+	var chunks []string
+	for len(s) > maxLength {
+		cut := runeSafeCut(s, maxLength)
+		if cut == 0 {
+			// maxLength is smaller than this rune's own byte width; take
+			// the whole rune rather than spin forever.
+			_, size := utf8.DecodeRuneInString(s)
+			cut = size
+		}
+		chunks = append(chunks, s[:cut])
+		s = s[cut:]
+	}
+	if len(s) > 0 {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
+
+// LineBlockStrategy is SplitText's original algorithm, wrapped as a
+// ChunkStrategy: split on newlines, keeping fenced code blocks and
+// markdown tables intact as atomic blocks (see parseBlocks), falling back
+// to a rune-safe byte cut for a block or line that's still too long on its
+// own.
+type LineBlockStrategy struct {
+	// PreserveLines enables block-aware splitting; false means "ignore line
+	// and block boundaries, just cut every maxLength bytes".
+	PreserveLines bool
+}
+
+func (s LineBlockStrategy) Split(content string, maxLength int) []string {
+	if len(content) <= maxLength {
+		return []string{content}
+	}
+
+	if !s.PreserveLines {
+		return splitRuneSafe(content, maxLength)
+	}
+
+	blocks := parseBlocks(strings.Split(content, "\n"))
+	var chunks []string
+	var currentChunk strings.Builder
+
+	flush := func() {
+		if currentChunk.Len() > 0 {
+			chunks = append(chunks, currentChunk.String())
+			currentChunk.Reset()
+		}
+	}
+	appendLine := func(line string) {
+		if currentChunk.Len() > 0 {
+			currentChunk.WriteString("\n")
+		}
+		currentChunk.WriteString(line)
+	}
+
+	for _, b := range blocks {
+		blockText := strings.Join(b.lines, "\n")
+
+		if currentChunk.Len()+len(blockText)+1 > maxLength {
+			flush()
+
+			if len(blockText) > maxLength {
+				switch b.kind {
+				case blockFence:
+					chunks = append(chunks, splitFenceBlock(b, maxLength)...)
+				case blockTable:
+					chunks = append(chunks, splitTableBlock(b, maxLength)...)
+				default:
+					lineChunks := splitRuneSafe(b.lines[0], maxLength)
+					chunks = append(chunks, lineChunks[:len(lineChunks)-1]...)
+					currentChunk.WriteString(lineChunks[len(lineChunks)-1])
+				}
+				continue
+			}
+		}
+
+		for _, line := range b.lines {
+			appendLine(line)
+		}
+	}
+
+	flush()
+	return chunks
+}
+
+// splitFenceBlock splits an oversized code fence across chunks, closing the
+// fence with ``` before each split and reopening it with the same language
+// tag on the next chunk, so no chunk ever contains an unterminated fence.
+func splitFenceBlock(b block, maxLength int) []string {
+	const closeLine = "```"
+	reopen := "```" + b.lang
+	body := b.lines[1 : len(b.lines)-1]
+
+	var chunks []string
+	var cur strings.Builder
+	cur.WriteString(b.lines[0])
+	bodyLinesInChunk := 0
+
+	for _, line := range body {
+		// Reserve room for the closing fence marker in case this line is
+		// the last one before a split.
+		if bodyLinesInChunk > 0 && cur.Len()+1+len(line)+1+len(closeLine) > maxLength {
+			cur.WriteString("\n" + closeLine)
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+			cur.WriteString(reopen)
+			bodyLinesInChunk = 0
+		}
+		cur.WriteString("\n" + line)
+		bodyLinesInChunk++
+	}
+	cur.WriteString("\n" + closeLine)
+	chunks = append(chunks, cur.String())
+
+	return chunks
+}
+
+// splitTableBlock splits an oversized markdown table between data rows,
+// repeating the header and separator row at the top of each chunk so every
+// chunk is an independently-renderable table.
+func splitTableBlock(b block, maxLength int) []string {
+	header := b.lines[0] + "\n" + b.lines[1]
+	rows := b.lines[2:]
+
+	var chunks []string
+	var cur strings.Builder
+	cur.WriteString(header)
+	rowsInChunk := 0
+
+	for _, row := range rows {
+		if rowsInChunk > 0 && cur.Len()+1+len(row) > maxLength {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+			cur.WriteString(header)
+			rowsInChunk = 0
+		}
+		cur.WriteString("\n" + row)
+		rowsInChunk++
+	}
+	chunks = append(chunks, cur.String())
+
+	return chunks
+}
+
+// ParagraphStrategy splits content at paragraph boundaries, keeping fenced
+// code blocks and markdown headings (together with the text that follows
+// them) as atomic units, so a chunk boundary never lands mid-fence or
+// separates a heading from its own content. A paragraph too long on its
+// own falls back to sentence boundaries, and a single sentence still too
+// long falls back to a rune-safe byte cut.
+type ParagraphStrategy struct{}
+
+func (ParagraphStrategy) Split(content string, maxLength int) []string {
+	if len(content) <= maxLength {
+		return []string{content}
+	}
+
+	paragraphs := splitParagraphBlocks(content)
+	var chunks []string
+	var cur strings.Builder
+
+	flush := func() {
+		if cur.Len() > 0 {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for _, p := range paragraphs {
+		text := strings.Join(p.lines, "\n")
+		sep := ""
+		if cur.Len() > 0 {
+			sep = "\n\n"
+		}
+
+		if cur.Len()+len(sep)+len(text) > maxLength {
+			flush()
+			if len(text) > maxLength {
+				switch p.kind {
+				case blockFence:
+					chunks = append(chunks, splitFenceBlock(p, maxLength)...)
+				case blockTable:
+					chunks = append(chunks, splitTableBlock(p, maxLength)...)
+				default:
+					chunks = append(chunks, splitBySentence(text, maxLength)...)
+				}
+				continue
+			}
+		} else {
+			cur.WriteString(sep)
+		}
+		cur.WriteString(text)
+	}
+	flush()
+
+	return chunks
+}
+
+// splitParagraphBlocks groups content into atomic paragraph units: a fenced
+// code block or markdown table from parseBlocks stays as its own unit so
+// splitFenceBlock/splitTableBlock can later split it properly if it's
+// still oversized, and plain text is grouped into blank-line-separated
+// runs, except that a heading line (one starting with "#") always starts a
+// new run together with the text that follows it.
+func splitParagraphBlocks(content string) []block {
+	blocks := parseBlocks(strings.Split(content, "\n"))
+
+	var paragraphs []block
+	var cur []string
+
+	flush := func() {
+		if len(cur) > 0 {
+			paragraphs = append(paragraphs, block{kind: blockText, lines: cur})
+			cur = nil
+		}
+	}
+
+	for _, b := range blocks {
+		if b.kind != blockText {
+			flush()
+			paragraphs = append(paragraphs, b)
+			continue
+		}
+
+		line := b.lines[0]
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			flush()
+		}
+		cur = append(cur, line)
+	}
+	flush()
+
+	return paragraphs
+}
+
+// sentenceBoundaryPattern matches the punctuation (and trailing
+// whitespace) that ends a sentence, e.g. ". " or "?\n".
+var sentenceBoundaryPattern = regexp.MustCompile(`[.!?]+\s+`)
+
+// splitBySentence packs p's sentences into chunks of at most maxLength
+// bytes, falling back to a rune-safe byte cut for any single sentence that
+// alone exceeds maxLength.
+func splitBySentence(p string, maxLength int) []string {
+	bounds := sentenceBoundaryPattern.FindAllStringIndex(p, -1)
+
+	var sentences []string
+	last := 0
+	for _, b := range bounds {
+		sentences = append(sentences, p[last:b[1]])
+		last = b[1]
+	}
+	if last < len(p) {
+		sentences = append(sentences, p[last:])
+	}
+	if len(sentences) == 0 {
+		sentences = []string{p}
+	}
+
+	var chunks []string
+	var cur strings.Builder
+	for _, s := range sentences {
+		if cur.Len() > 0 && cur.Len()+len(s) > maxLength {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+		}
+		if len(s) > maxLength {
+			if cur.Len() > 0 {
+				chunks = append(chunks, cur.String())
+				cur.Reset()
+			}
+			chunks = append(chunks, splitRuneSafe(s, maxLength)...)
+			continue
+		}
+		cur.WriteString(s)
+	}
+	if cur.Len() > 0 {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
+
+// Tokenizer splits s into the units TokenBudgetStrategy counts against its
+// budget. This is a deliberately different shape from tokenizer.go's
+// countTokensForModel, which only returns a whole-string estimate --
+// chunking needs to know where each token ends so it can cut a chunk
+// boundary between them. WhitespaceTokenizer is the default, cheap
+// approximation; a real BPE tokenizer (e.g. the tiktoken-go encodings
+// countTokensForModel already loads) can satisfy this interface by
+// returning each encoded token's decoded text.
+type Tokenizer interface {
+	Tokenize(s string) []string
+}
+
+// wordPunctPattern splits s into runs of whitespace, runs of letters/
+// digits/underscore, and individual punctuation/symbol characters, so
+// joining the pieces back together reconstructs s exactly.
+var wordPunctPattern = regexp.MustCompile(`[\p{L}\p{N}_]+|\s+|[^\s\p{L}\p{N}_]`)
+
+// WhitespaceTokenizer is Tokenizer's default: a cheap approximation that
+// treats each word and each punctuation character as one token.
+type WhitespaceTokenizer struct{}
+
+func (WhitespaceTokenizer) Tokenize(s string) []string {
+	return wordPunctPattern.FindAllString(s, -1)
+}
+
+// TokenBudgetStrategy splits content so no chunk's token count (per
+// Tokenizer) exceeds MaxTokens, cutting only between tokens -- and, since
+// Tokenize's pieces are themselves rune-safe, never inside a multi-byte
+// UTF-8 sequence.
+type TokenBudgetStrategy struct {
+	Tokenizer Tokenizer
+	MaxTokens int
+}
+
+// NewTokenBudgetStrategy creates a TokenBudgetStrategy using the default
+// WhitespaceTokenizer.
+func NewTokenBudgetStrategy(maxTokens int) *TokenBudgetStrategy {
+	return &TokenBudgetStrategy{Tokenizer: WhitespaceTokenizer{}, MaxTokens: maxTokens}
+}
+
+func (s TokenBudgetStrategy) Split(content string, maxLength int) []string {
+	tokenizer := s.Tokenizer
+	if tokenizer == nil {
+		tokenizer = WhitespaceTokenizer{}
+	}
+
+	if len(content) <= maxLength && len(tokenizer.Tokenize(content)) <= s.MaxTokens {
+		return []string{content}
+	}
+
+	var chunks []string
+	var cur strings.Builder
+	tokenCount := 0
+
+	flush := func() {
+		if cur.Len() > 0 {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+			tokenCount = 0
+		}
+	}
+
+	for _, tok := range tokenizer.Tokenize(content) {
+		if tokenCount > 0 && (tokenCount >= s.MaxTokens || cur.Len()+len(tok) > maxLength) {
+			flush()
+		}
+
+		if len(tok) > maxLength {
+			flush()
+			chunks = append(chunks, splitRuneSafe(tok, maxLength)...)
+			continue
+		}
+
+		cur.WriteString(tok)
+		tokenCount++
+	}
+	flush()
+
+	return chunks
+}
@@ -0,0 +1,130 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRuneSafeCut_NeverSplitsAMultiByteRune(t *testing.T) {
+	s := strings.Repeat("日", 10) // 3 bytes/rune
+	for limit := 0; limit <= len(s); limit++ {
+		cut := runeSafeCut(s, limit)
+		if !utf8.ValidString(s[:cut]) {
+			t.Fatalf("runeSafeCut(%d) = %d produced invalid UTF-8 prefix %q", limit, cut, s[:cut])
+		}
+	}
+}
+
+func TestSplitRuneSafe_ReconstructsOriginal(t *testing.T) {
+	s := strings.Repeat("日本語テスト", 20)
+	chunks := splitRuneSafe(s, 17) // not a multiple of 3
+	if strings.Join(chunks, "") != s {
+		t.Fatal("splitRuneSafe chunks don't reconstruct the original string")
+	}
+	for i, c := range chunks {
+		if !utf8.ValidString(c) {
+			t.Errorf("chunk %d is not valid UTF-8: %q", i, c)
+		}
+		if len(c) > 17 {
+			t.Errorf("chunk %d has length %d, exceeds 17", i, len(c))
+		}
+	}
+}
+
+func TestLineBlockStrategy_NoPreserveLinesIsRuneSafe(t *testing.T) {
+	s := LineBlockStrategy{PreserveLines: false}
+	chunks := s.Split(strings.Repeat("日", 30), 10)
+	for i, c := range chunks {
+		if !utf8.ValidString(c) {
+			t.Errorf("chunk %d is not valid UTF-8: %q", i, c)
+		}
+	}
+}
+
+func TestParagraphStrategy_KeepsHeadingWithFollowingText(t *testing.T) {
+	s := ParagraphStrategy{}
+	input := "# Heading\nSome text right after the heading.\n\nA separate paragraph."
+
+	chunks := s.Split(input, 50)
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(chunks))
+	}
+	if !strings.Contains(chunks[0], "# Heading") || !strings.Contains(chunks[0], "Some text right after") {
+		t.Errorf("heading and its following text should be in the same chunk, got chunks[0] = %q", chunks[0])
+	}
+}
+
+func TestParagraphStrategy_KeepsFenceAtomic(t *testing.T) {
+	s := ParagraphStrategy{}
+	input := "intro paragraph\n\n```go\nfunc f() {\n\treturn\n}\n```\n\nafter the fence"
+
+	chunks := s.Split(input, 20)
+	for i, c := range chunks {
+		if strings.Count(c, "```")%2 != 0 {
+			t.Errorf("chunk %d has an unterminated fence: %q", i, c)
+		}
+	}
+}
+
+func TestParagraphStrategy_NoChunkExceedsMaxLength(t *testing.T) {
+	s := ParagraphStrategy{}
+	input := strings.Repeat("A reasonably long sentence that takes up some space. ", 10) +
+		"\n\n" + strings.Repeat("b", 200)
+
+	chunks := s.Split(input, 40)
+	for i, c := range chunks {
+		if len(c) > 40 {
+			t.Errorf("chunk %d has length %d, exceeds 40: %q", i, len(c), c)
+		}
+	}
+}
+
+func TestWhitespaceTokenizer_ReconstructsOriginal(t *testing.T) {
+	s := "Hello, world! This is a test."
+	tokens := WhitespaceTokenizer{}.Tokenize(s)
+	if strings.Join(tokens, "") != s {
+		t.Fatalf("tokens don't reconstruct the original string: %v", tokens)
+	}
+}
+
+func TestTokenBudgetStrategy_RespectsMaxTokens(t *testing.T) {
+	strategy := TokenBudgetStrategy{Tokenizer: WhitespaceTokenizer{}, MaxTokens: 3}
+	input := "one two three four five six seven eight"
+
+	chunks := strategy.Split(input, 1000)
+	for _, c := range chunks {
+		count := 0
+		for _, tok := range (WhitespaceTokenizer{}).Tokenize(c) {
+			if strings.TrimSpace(tok) != "" {
+				count++
+			}
+		}
+		if count > 3 {
+			t.Errorf("chunk %q has %d tokens, exceeds MaxTokens 3", c, count)
+		}
+	}
+}
+
+func TestTokenBudgetStrategy_NoChunkExceedsMaxLength(t *testing.T) {
+	strategy := NewTokenBudgetStrategy(1000)
+	input := strings.Repeat("word ", 50) + strings.Repeat("日", 50)
+
+	chunks := strategy.Split(input, 30)
+	for i, c := range chunks {
+		if len(c) > 30 {
+			t.Errorf("chunk %d has length %d, exceeds 30: %q", i, len(c), c)
+		}
+		if !utf8.ValidString(c) {
+			t.Errorf("chunk %d is not valid UTF-8: %q", i, c)
+		}
+	}
+}
+
+func TestTokenBudgetStrategy_ShortContentIsSingleChunk(t *testing.T) {
+	strategy := NewTokenBudgetStrategy(1000)
+	chunks := strategy.Split("short", 1000)
+	if len(chunks) != 1 || chunks[0] != "short" {
+		t.Errorf("Split() = %v, want a single unchanged chunk", chunks)
+	}
+}
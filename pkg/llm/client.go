@@ -1,6 +1,7 @@
 package llm
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -12,8 +13,6 @@ import (
 	"regexp"
 	"strings"
 	"time"
-
-	"github.com/pkoukk/tiktoken-go"
 )
 
 // Client handles communication with Ollama API
@@ -62,6 +61,10 @@ func (c *Client) CheckConnection(ctx context.Context) error {
 
 // Generate sends a generate request to Ollama
 func (c *Client) Generate(ctx context.Context, config ModelConfig, systemPrompt, userPrompt string) (*GenerateResponse, error) {
+	provider := providerLabel(config)
+	metrics.inFlight.WithLabelValues(config.Name, provider).Inc()
+	defer metrics.inFlight.WithLabelValues(config.Name, provider).Dec()
+
 	request := GenerateRequest{
 		Model:     config.Name,
 		System:    systemPrompt,
@@ -75,8 +78,8 @@ func (c *Client) Generate(ctx context.Context, config ModelConfig, systemPrompt,
 	inputTokens := countTokensForModel(systemPrompt+userPrompt, config.Name)
 	log.Printf("Sending generate request to model %s (%d input tokens)", config.Name, inputTokens)
 
-	if inputTokens > config.Options.NumCtx {
-		log.Printf("Warning: estimated input tokens (%d) exceeds context size (%d)", inputTokens, config.Options.NumCtx)
+	if err := checkContextLimit(inputTokens, config); err != nil {
+		return nil, err
 	}
 
 	jsonData, err := json.Marshal(request)
@@ -150,6 +153,8 @@ func (c *Client) Generate(ctx context.Context, config ModelConfig, systemPrompt,
 	duration := time.Since(startTime)
 	outputTokens := countTokensForModel(response.Response, config.Name)
 	tokensPerSecond := float64(outputTokens) / duration.Seconds()
+	metrics.requestDuration.WithLabelValues(config.Name, provider).Observe(duration.Seconds())
+	metrics.tokensPerSecond.WithLabelValues(config.Name, provider).Observe(tokensPerSecond)
 
 	log.Printf("Received generate response from %s: duration=%.2fs, output_tokens=%d, tokens_per_second=%.2f",
 		config.Name, duration.Seconds(), outputTokens, tokensPerSecond)
@@ -157,6 +162,174 @@ func (c *Client) Generate(ctx context.Context, config ModelConfig, systemPrompt,
 	return &response, nil
 }
 
+// GenerateStream sends a streaming generate request to Ollama, invoking
+// onDelta with each chunk of visible response text as it arrives. Completed
+// <think>...</think> blocks are filtered out of what's passed to onDelta,
+// matching RemoveThinkingTags' behavior; a block that hasn't closed yet is
+// held back until it does (or until the stream ends). The final aggregated
+// response, with token/timing metrics computed from the terminal done
+// message, is returned once Ollama reports done.
+func (c *Client) GenerateStream(ctx context.Context, config ModelConfig, systemPrompt, userPrompt string, onDelta func(chunk string) error) (*GenerateResponse, error) {
+	provider := providerLabel(config)
+	metrics.inFlight.WithLabelValues(config.Name, provider).Inc()
+	defer metrics.inFlight.WithLabelValues(config.Name, provider).Dec()
+
+	request := GenerateRequest{
+		Model:     config.Name,
+		System:    systemPrompt,
+		Prompt:    userPrompt,
+		Options:   config.Options,
+		KeepAlive: "30m",
+		Stream:    true,
+	}
+
+	inputTokens := countTokensForModel(systemPrompt+userPrompt, config.Name)
+	log.Printf("Sending streaming generate request to model %s (%d input tokens)", config.Name, inputTokens)
+
+	if err := checkContextLimit(inputTokens, config); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/generate", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	startTime := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var (
+		fullResponse strings.Builder
+		pending      strings.Builder
+		final        GenerateResponse
+	)
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var chunk GenerateResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			continue // Skip invalid lines
+		}
+
+		fullResponse.WriteString(chunk.Response)
+		pending.WriteString(chunk.Response)
+
+		if visible := nextVisibleDelta(&pending, chunk.Done); visible != "" {
+			if err := onDelta(visible); err != nil {
+				return nil, fmt.Errorf("onDelta callback failed: %w", err)
+			}
+		}
+
+		if chunk.Done {
+			final = chunk
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read streaming response: %w", err)
+	}
+
+	final.Response = fullResponse.String()
+
+	// Calculate metrics
+	duration := time.Since(startTime)
+	outputTokens := countTokensForModel(final.Response, config.Name)
+	tokensPerSecond := float64(outputTokens) / duration.Seconds()
+	metrics.requestDuration.WithLabelValues(config.Name, provider).Observe(duration.Seconds())
+	metrics.tokensPerSecond.WithLabelValues(config.Name, provider).Observe(tokensPerSecond)
+
+	log.Printf("Received streaming generate response from %s: duration=%.2fs, output_tokens=%d, tokens_per_second=%.2f",
+		config.Name, duration.Seconds(), outputTokens, tokensPerSecond)
+
+	return &final, nil
+}
+
+// nextVisibleDelta drains pending for text that's safe to show the user:
+// anything outside a <think>...</think> block. Completed blocks are
+// dropped entirely; an unclosed block (or a tag that may still be split
+// across the next chunk) is left in pending unless flush is set, in which
+// case whatever remains is treated as visible.
+func nextVisibleDelta(pending *strings.Builder, flush bool) string {
+	buf := pending.String()
+	lower := strings.ToLower(buf)
+	var visible strings.Builder
+
+	for {
+		idx := strings.Index(lower, "<think>")
+		if idx == -1 {
+			break
+		}
+
+		if end := strings.Index(lower[idx:], "</think>"); end != -1 {
+			visible.WriteString(buf[:idx])
+			buf = buf[idx+end+len("</think>"):]
+			lower = lower[idx+end+len("</think>"):]
+			continue
+		}
+
+		// Unclosed block: show what came before it and hold the rest back.
+		visible.WriteString(buf[:idx])
+		buf = buf[idx:]
+		pending.Reset()
+		pending.WriteString(buf)
+		return visible.String()
+	}
+
+	if !flush {
+		if hold := partialTagSuffixLen(buf, "<think>"); hold > 0 {
+			visible.WriteString(buf[:len(buf)-hold])
+			buf = buf[len(buf)-hold:]
+			pending.Reset()
+			pending.WriteString(buf)
+			return visible.String()
+		}
+	}
+
+	visible.WriteString(buf)
+	pending.Reset()
+	return visible.String()
+}
+
+// partialTagSuffixLen returns the length of the longest suffix of buf that
+// is also a prefix of tag, so a tag split across two stream chunks isn't
+// emitted as visible text before it can be recognized as such.
+func partialTagSuffixLen(buf, tag string) int {
+	max := len(tag) - 1
+	if max > len(buf) {
+		max = len(buf)
+	}
+	for n := max; n > 0; n-- {
+		if strings.EqualFold(buf[len(buf)-n:], tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
 // RemoveThinkingTags removes <think>...</think> blocks from LLM responses
 func RemoveThinkingTags(content string) string {
 	if content == "" {
@@ -173,41 +346,23 @@ func RemoveThinkingTags(content string) string {
 	return cleaned
 }
 
-// countTokensForModel provides model-specific token counting
-func countTokensForModel(content string, modelName string) int {
-
-	// TODO: tiktoken has a `tiktoken.EncodingForModel`
-
-	encoding, err := tiktoken.EncodingForModel("gpt2")
-
-	// var encodingName string
-	//
-	// // Choose encoding based on model
-	// switch {
-	// case strings.Contains(strings.ToLower(modelName), "qwen"):
-	// 	// Qwen models typically use cl100k_base or similar
-	// 	encodingName = "cl100k_base"
-	// case strings.Contains(strings.ToLower(modelName), "gemma"):
-	// 	// Gemma models use cl100k_base encoding
-	// 	encodingName = "cl100k_base"
-	// case strings.Contains(strings.ToLower(modelName), "gpt-4"):
-	// 	encodingName = "cl100k_base"
-	// case strings.Contains(strings.ToLower(modelName), "gpt-3.5"):
-	// 	encodingName = "cl100k_base"
-	// default:
-	// 	// Default to cl100k_base which works for most modern models
-	// 	encodingName = "cl100k_base"
-	// }
-	//
-	// encoding, err := tiktoken.GetEncoding(encodingName)
-	if err != nil {
-		// Fallback to estimation if tiktoken fails
-		log.Printf("Warning: tiktoken encoding failed for %s, using estimation: %v", "gpt2", err)
-		return fallbackTokenCount(content)
+// checkContextLimit compares inputTokens against config.Options.NumCtx. If
+// it's exceeded and config.StrictContext is set, it returns an error
+// instead of just logging, so callers can fail a request up front rather
+// than let Ollama/the provider silently truncate it.
+func checkContextLimit(inputTokens int, config ModelConfig) error {
+	if inputTokens <= config.Options.NumCtx {
+		return nil
 	}
 
-	tokens := encoding.Encode(content, nil, nil)
-	return len(tokens)
+	if config.StrictContext {
+		metrics.contextOverflowTotal.WithLabelValues(config.Name, providerLabel(config), "error").Inc()
+		return fmt.Errorf("estimated input tokens (%d) exceed context size (%d) for model %s", inputTokens, config.Options.NumCtx, config.Name)
+	}
+
+	metrics.contextOverflowTotal.WithLabelValues(config.Name, providerLabel(config), "warning").Inc()
+	log.Printf("Warning: estimated input tokens (%d) exceeds context size (%d)", inputTokens, config.Options.NumCtx)
+	return nil
 }
 
 // fallbackTokenCount provides estimation when tiktoken is unavailable
@@ -244,6 +399,7 @@ func (c *Client) GenerateWithRetry(ctx context.Context, config ModelConfig, syst
 			case <-time.After(backoff):
 				backoff = time.Duration(math.Min(60, float64(backoff)*2))
 			}
+			metrics.retriesTotal.WithLabelValues(config.Name, providerLabel(config)).Inc()
 			log.Printf("Retrying generate request (attempt %d/%d)", attempt+1, maxRetries+1)
 		}
 
@@ -261,9 +417,9 @@ func (c *Client) GenerateWithRetry(ctx context.Context, config ModelConfig, syst
 
 // GetGenerateResponse provides a simplified interface matching the Python notebook pattern
 // Equivalent to Python's get_generate_response(model_config, system_prompt, user_prompt)
-func GetGenerateResponse(ctx context.Context, client *Client, modelConfig ModelConfig, systemPrompt, userPrompt string) (*GenerateResponse, error) {
+func GetGenerateResponse(ctx context.Context, provider Provider, modelConfig ModelConfig, systemPrompt, userPrompt string) (*GenerateResponse, error) {
 	// Use Generate directly for simple interface
-	response, err := client.Generate(ctx, modelConfig, systemPrompt, userPrompt)
+	response, err := provider.Generate(ctx, modelConfig, systemPrompt, userPrompt)
 	if err != nil {
 		return nil, fmt.Errorf("generate request failed: %w", err)
 	}
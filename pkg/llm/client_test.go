@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_GenerateStream(t *testing.T) {
+	chunks := []GenerateResponse{
+		{Response: "<think>pondering"},
+		{Response: "</think>Hello, "},
+		{Response: "world!", Done: true, EvalCount: 3},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		flusher, _ := w.(http.Flusher)
+		for _, chunk := range chunks {
+			data, err := json.Marshal(chunk)
+			if err != nil {
+				t.Fatalf("failed to marshal test chunk: %v", err)
+			}
+			w.Write(append(data, '\n'))
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, 0)
+
+	var deltas []string
+	response, err := client.GenerateStream(context.Background(), CreateDefaultModelConfig("test-model"), "system", "prompt", func(chunk string) error {
+		deltas = append(deltas, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GenerateStream returned error: %v", err)
+	}
+
+	if response.Response != "<think>pondering</think>Hello, world!" {
+		t.Errorf("expected the final response to retain the full raw text, got %q", response.Response)
+	}
+	if !response.Done {
+		t.Error("expected the final response to report done")
+	}
+
+	got := ""
+	for _, d := range deltas {
+		got += d
+	}
+	if got != "Hello, world!" {
+		t.Errorf("expected streamed deltas to filter out the think block, got %q", got)
+	}
+}
+
+func TestNextVisibleDelta_HoldsPartialTag(t *testing.T) {
+	var pending strings.Builder
+	pending.WriteString("prefix <thi")
+
+	visible := nextVisibleDelta(&pending, false)
+	if visible != "prefix " {
+		t.Errorf("expected the partial tag to be held back, got visible=%q pending=%q", visible, pending.String())
+	}
+	if pending.String() != "<thi" {
+		t.Errorf("expected the partial tag to remain buffered, got %q", pending.String())
+	}
+}
+
+func TestPartialTagSuffixLen(t *testing.T) {
+	tests := []struct {
+		buf  string
+		want int
+	}{
+		{"hello <th", 3},
+		{"hello", 0},
+		{"<think>", 0}, // a complete tag is handled by the caller, not this helper
+		{"<", 1},
+	}
+
+	for _, tt := range tests {
+		if got := partialTagSuffixLen(tt.buf, "<think>"); got != tt.want {
+			t.Errorf("partialTagSuffixLen(%q, \"<think>\") = %d, want %d", tt.buf, got, tt.want)
+		}
+	}
+}
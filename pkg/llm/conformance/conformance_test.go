@@ -0,0 +1,105 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"osrs-flipping/pkg/llm"
+)
+
+func writeVectorFile(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+		t.Fatalf("write vector file: %v", err)
+	}
+}
+
+func TestLoadVectors(t *testing.T) {
+	dir := t.TempDir()
+	writeVectorFile(t, dir, "b.yaml", "name: b-vector\nmodel: qwen3:14b\nuser_prompt: hi\n")
+	writeVectorFile(t, dir, "a.yaml", "model: qwen3:14b\nuser_prompt: hi\n") // unnamed -> derives from file name
+	writeVectorFile(t, dir, "notes.txt", "ignored")
+
+	vectors, err := LoadVectors(dir)
+	if err != nil {
+		t.Fatalf("LoadVectors: %v", err)
+	}
+	if len(vectors) != 2 {
+		t.Fatalf("expected 2 vectors, got %d", len(vectors))
+	}
+	if vectors[0].Name != "a" {
+		t.Errorf("expected first vector (sorted by file name) to derive its name from a.yaml, got %q", vectors[0].Name)
+	}
+	if vectors[1].Name != "b-vector" {
+		t.Errorf("expected second vector to keep its explicit name, got %q", vectors[1].Name)
+	}
+}
+
+func TestRun_SchemaAndRegexChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := llm.GenerateResponse{
+			Model:    "qwen3:14b",
+			Response: `{"recommended_price": 105, "note": "buy"}`,
+			Done:     true,
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	provider := llm.NewClient(server.URL, 0)
+
+	passing := Vector{
+		Name:               "price-within-range",
+		Model:              "qwen3:14b",
+		UserPrompt:         "recommend a price",
+		ExpectedJSONSchema: `{"type":"object","required":["recommended_price"],"properties":{"recommended_price":{"type":"number"}}}`,
+		ExpectedFieldsRegex: map[string]string{
+			"note": "^buy$",
+		},
+	}
+	failing := Vector{
+		Name:       "wrong-note",
+		Model:      "qwen3:14b",
+		UserPrompt: "recommend a price",
+		ExpectedFieldsRegex: map[string]string{
+			"note": "^sell$",
+		},
+	}
+
+	results := Run(context.Background(), []Vector{passing, failing}, provider)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if !results[0].Passed {
+		t.Errorf("expected %q to pass, got error: %v", results[0].Name, results[0].Err)
+	}
+	if results[1].Passed {
+		t.Errorf("expected %q to fail its regex check", results[1].Name)
+	}
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.xml")
+
+	results := []Result{
+		{Name: "ok", Passed: true},
+		{Name: "broken", Passed: false, Err: os.ErrNotExist},
+	}
+	if err := WriteJUnitReport(path, results); err != nil {
+		t.Fatalf("WriteJUnitReport: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("expected a non-empty report")
+	}
+}
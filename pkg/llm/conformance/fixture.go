@@ -0,0 +1,106 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"osrs-flipping/pkg/llm"
+)
+
+// fixturePath returns where vectorName's recorded HTTP fixture is stored
+// under dir.
+func fixturePath(dir, vectorName string) string {
+	return filepath.Join(dir, vectorName+".json")
+}
+
+// RecordFixtures replays vectors against a live provider and writes each
+// one's raw GenerateResponse to dir as a JSON fixture, for later offline
+// replay via NewOfflineProvider.
+func RecordFixtures(ctx context.Context, vectors []Vector, provider llm.Provider, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create fixture directory %s: %w", dir, err)
+	}
+
+	for _, v := range vectors {
+		config := llm.CreateDefaultModelConfig(v.Model)
+		config.Provider = v.Provider
+
+		resp, err := provider.Generate(ctx, config, v.SystemPrompt, v.UserPrompt)
+		if err != nil {
+			return fmt.Errorf("record vector %s: %w", v.Name, err)
+		}
+
+		data, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal fixture for vector %s: %w", v.Name, err)
+		}
+		if err := os.WriteFile(fixturePath(dir, v.Name), data, 0644); err != nil {
+			return fmt.Errorf("write fixture for vector %s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// OfflineProvider serves a single vector's recorded fixture over HTTP via
+// httptest, so Run exercises the real Client decode path without hitting
+// the network. Call Close when done with it.
+//
+// Fixtures are recorded as Client's GenerateResponse shape, so
+// OfflineProvider only supports replaying "ollama" vectors; an "openai"
+// vector falls back to a live provider in ProviderForVector.
+type OfflineProvider struct {
+	llm.Provider
+	server *httptest.Server
+}
+
+// NewOfflineProvider loads vectorName's fixture from dir and returns an
+// OfflineProvider backed by an httptest server that always returns the
+// recorded response.
+func NewOfflineProvider(dir, vectorName string) (*OfflineProvider, error) {
+	data, err := os.ReadFile(fixturePath(dir, vectorName))
+	if err != nil {
+		return nil, fmt.Errorf("read fixture for vector %s: %w", vectorName, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+
+	return &OfflineProvider{Provider: llm.NewClient(server.URL, 0), server: server}, nil
+}
+
+// Close shuts down the underlying httptest server.
+func (p *OfflineProvider) Close() {
+	p.server.Close()
+}
+
+// offlineEnvVar, when set to "1", makes ProviderForVector replay recorded
+// fixtures instead of calling a live backend.
+const offlineEnvVar = "LLM_CONFORMANCE_OFFLINE"
+
+// ProviderForVector returns a Provider to replay v against: a live
+// Provider built from liveBaseURL, or -- when LLM_CONFORMANCE_OFFLINE=1 --
+// an OfflineProvider serving v's recorded fixture from fixtureDir. The
+// returned close func must always be called when done (a no-op for the
+// live case).
+func ProviderForVector(v Vector, liveBaseURL, fixtureDir string) (llm.Provider, func(), error) {
+	if os.Getenv(offlineEnvVar) == "1" {
+		offline, err := NewOfflineProvider(fixtureDir, v.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return offline, offline.Close, nil
+	}
+
+	provider, err := llm.NewProvider(v.Provider, liveBaseURL, "", 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return provider, func() {}, nil
+}
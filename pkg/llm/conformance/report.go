@@ -0,0 +1,53 @@
+package conformance
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+)
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Time    float64       `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// WriteJUnitReport writes results to path in JUnit XML format, so CI can
+// surface conformance failures the same way it surfaces go test failures.
+func WriteJUnitReport(path string, results []Result) error {
+	suite := junitTestSuite{Name: "llm-conformance", Tests: len(results)}
+	for _, r := range results {
+		tc := junitTestCase{Name: r.Name, Time: r.Duration.Seconds()}
+		if !r.Passed {
+			suite.Failures++
+			msg := "conformance check failed"
+			if r.Err != nil {
+				msg = r.Err.Error()
+			}
+			tc.Failure = &junitFailure{Message: msg, Content: msg}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal junit report: %w", err)
+	}
+	if err := os.WriteFile(path, append([]byte(xml.Header), data...), 0644); err != nil {
+		return fmt.Errorf("write junit report %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+
+	"osrs-flipping/pkg/llm"
+)
+
+// Result is one vector's outcome.
+type Result struct {
+	Name     string
+	Duration time.Duration
+	Passed   bool
+	Err      error
+}
+
+// Run replays each vector against provider's Generate, parses the response
+// as JSON, and checks it against the vector's JSON Schema and field
+// regexes. A single vector's failure doesn't stop the rest of the run.
+func Run(ctx context.Context, vectors []Vector, provider llm.Provider) []Result {
+	results := make([]Result, 0, len(vectors))
+	for _, v := range vectors {
+		results = append(results, runVector(ctx, v, provider))
+	}
+	return results
+}
+
+func runVector(ctx context.Context, v Vector, provider llm.Provider) Result {
+	start := time.Now()
+	fail := func(err error) Result {
+		return Result{Name: v.Name, Duration: time.Since(start), Err: err}
+	}
+
+	config := llm.CreateDefaultModelConfig(v.Model)
+	config.Provider = v.Provider
+
+	resp, err := provider.Generate(ctx, config, v.SystemPrompt, v.UserPrompt)
+	if err != nil {
+		return fail(fmt.Errorf("generate: %w", err))
+	}
+
+	content := llm.RemoveThinkingTags(resp.Response)
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(content), &parsed); err != nil {
+		return fail(fmt.Errorf("response is not valid JSON: %w", err))
+	}
+
+	if v.ExpectedJSONSchema != "" {
+		result, err := gojsonschema.Validate(
+			gojsonschema.NewStringLoader(v.ExpectedJSONSchema),
+			gojsonschema.NewStringLoader(content),
+		)
+		if err != nil {
+			return fail(fmt.Errorf("validate schema: %w", err))
+		}
+		if !result.Valid() {
+			return fail(fmt.Errorf("schema validation failed: %v", result.Errors()))
+		}
+	}
+
+	regexes, err := v.compileFieldRegexes()
+	if err != nil {
+		return fail(err)
+	}
+
+	fields, isObject := parsed.(map[string]interface{})
+	for field, re := range regexes {
+		if !isObject {
+			return fail(fmt.Errorf("response is not a JSON object, can't check field %q", field))
+		}
+		value, present := fields[field]
+		if !present {
+			return fail(fmt.Errorf("field %q missing from response", field))
+		}
+		if !re.MatchString(fmt.Sprintf("%v", value)) {
+			return fail(fmt.Errorf("field %q value %v doesn't match %q", field, value, re.String()))
+		}
+	}
+
+	return Result{Name: v.Name, Duration: time.Since(start), Passed: true}
+}
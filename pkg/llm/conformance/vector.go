@@ -0,0 +1,84 @@
+// Package conformance replays golden-vector prompt/response pairs against
+// a live (or recorded) LLM backend and checks the output against a JSON
+// Schema and per-field regexes, so prompt-engineering changes can be
+// caught by CI instead of discovered in production.
+package conformance
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Vector is one golden-vector test case: a fixed prompt pair replayed
+// against a model, with assertions on the parsed JSON response.
+type Vector struct {
+	Name     string `yaml:"name"`
+	Model    string `yaml:"model"`
+	Provider string `yaml:"provider,omitempty"`
+
+	SystemPrompt string `yaml:"system_prompt"`
+	UserPrompt   string `yaml:"user_prompt"`
+
+	ExpectedJSONSchema  string            `yaml:"expected_json_schema,omitempty"`
+	ExpectedFieldsRegex map[string]string `yaml:"expected_fields_regex,omitempty"`
+}
+
+// LoadVectors reads every *.yaml/*.yml file in dir as a single Vector and
+// returns them sorted by file name, so a run's ordering (and its JUnit
+// report) is deterministic across machines.
+func LoadVectors(dir string) ([]Vector, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read vector directory %s: %w", dir, err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if ext := filepath.Ext(entry.Name()); ext == ".yaml" || ext == ".yml" {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+
+	vectors := make([]Vector, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read vector %s: %w", path, err)
+		}
+
+		var v Vector
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("parse vector %s: %w", path, err)
+		}
+		if v.Name == "" {
+			v.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
+
+// compileFieldRegexes compiles every pattern in v.ExpectedFieldsRegex up
+// front, so a malformed vector fails fast instead of partway through a
+// (possibly slow) live replay.
+func (v Vector) compileFieldRegexes() (map[string]*regexp.Regexp, error) {
+	compiled := make(map[string]*regexp.Regexp, len(v.ExpectedFieldsRegex))
+	for field, pattern := range v.ExpectedFieldsRegex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile regex for field %q: %w", field, err)
+		}
+		compiled[field] = re
+	}
+	return compiled, nil
+}
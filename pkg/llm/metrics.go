@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// llmMetrics is this package's self-contained Prometheus registry and
+// instrument set, shared by Client and OpenAIClient since both implement
+// Provider and are distinguished by the "provider" label rather than by
+// owning separate registries. It doesn't run its own HTTP server -- the
+// rest of the app mounts NewMetricsHandler() wherever it already serves
+// HTTP (e.g. at /metrics), the same convention pkg/collector/metrics.go and
+// pkg/scheduler/metrics.go use.
+type llmMetrics struct {
+	registry *prometheus.Registry
+
+	requestDuration      *prometheus.HistogramVec
+	tokensPerSecond      *prometheus.HistogramVec
+	retriesTotal         *prometheus.CounterVec
+	contextOverflowTotal *prometheus.CounterVec
+	inFlight             *prometheus.GaugeVec
+}
+
+func newLLMMetrics() *llmMetrics {
+	registry := prometheus.NewRegistry()
+
+	m := &llmMetrics{
+		registry: registry,
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "osrs_llm_request_duration_seconds",
+			Help:    "Duration of a completed Generate/GenerateStream call.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"model", "provider"}),
+		tokensPerSecond: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "osrs_llm_tokens_per_second",
+			Help:    "Output tokens per second for a completed Generate/GenerateStream call.",
+			Buckets: []float64{1, 5, 10, 20, 40, 80, 160, 320},
+		}, []string{"model", "provider"}),
+		retriesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "osrs_llm_retries_total",
+			Help: "Number of GenerateWithRetry attempts beyond the first.",
+		}, []string{"model", "provider"}),
+		contextOverflowTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "osrs_llm_context_overflow_total",
+			Help: "Number of requests whose estimated input tokens exceeded Options.NumCtx, by whether StrictContext turned it into an error.",
+		}, []string{"model", "provider", "outcome"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "osrs_llm_requests_in_flight",
+			Help: "Number of Generate/GenerateStream calls currently in flight.",
+		}, []string{"model", "provider"}),
+	}
+
+	registry.MustRegister(m.requestDuration, m.tokensPerSecond, m.retriesTotal,
+		m.contextOverflowTotal, m.inFlight)
+	return m
+}
+
+// metrics is package-level because Client and OpenAIClient are both thin,
+// frequently-constructed wrappers around an http.Client rather than
+// long-lived owners of their own registries -- a single shared registry
+// keyed by the "provider" label avoids double-registering the same metric
+// names every time a job builds a fresh Provider.
+var metrics = newLLMMetrics()
+
+// NewMetricsHandler returns an http.Handler serving this package's LLM call
+// metrics in the Prometheus text exposition format.
+func NewMetricsHandler() http.Handler {
+	return promhttp.HandlerFor(metrics.registry, promhttp.HandlerOpts{})
+}
+
+// providerLabel returns config's Provider, defaulting to "ollama" to match
+// NewProvider's empty-string handling.
+func providerLabel(config ModelConfig) string {
+	if config.Provider == "" {
+		return "ollama"
+	}
+	return config.Provider
+}
@@ -0,0 +1,12 @@
+package llm
+
+import "testing"
+
+func TestProviderLabel(t *testing.T) {
+	if got := providerLabel(ModelConfig{}); got != "ollama" {
+		t.Errorf("expected empty Provider to default to ollama, got %q", got)
+	}
+	if got := providerLabel(ModelConfig{Provider: "openai"}); got != "openai" {
+		t.Errorf("expected explicit Provider to pass through, got %q", got)
+	}
+}
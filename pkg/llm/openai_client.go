@@ -0,0 +1,313 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIClient talks to any OpenAI-compatible chat completions endpoint
+// (hosted OpenAI, a LiteLLM proxy, vLLM, or a local llama.cpp server) over
+// bearer auth, so NewProvider("openai", ...) works against all of them.
+type OpenAIClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewOpenAIClient creates a new OpenAI-compatible client
+func NewOpenAIClient(baseURL, apiKey string, timeout time.Duration) *OpenAIClient {
+	if baseURL == "" {
+		baseURL = "https://api.openai.com"
+	}
+
+	if timeout == 0 {
+		timeout = 5 * time.Minute
+	}
+
+	return &OpenAIClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature,omitempty"`
+	TopP        float64       `json:"top_p,omitempty"`
+	Seed        *int64        `json:"seed,omitempty"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+}
+
+type chatCompletionChoice struct {
+	Message      chatMessage `json:"message"`
+	Delta        chatMessage `json:"delta"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type chatCompletionUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type chatCompletionResponse struct {
+	Model   string                 `json:"model"`
+	Choices []chatCompletionChoice `json:"choices"`
+	Usage   chatCompletionUsage    `json:"usage"`
+}
+
+func (c *OpenAIClient) chatRequest(config ModelConfig, systemPrompt, userPrompt string, stream bool) chatCompletionRequest {
+	var messages []chatMessage
+	if systemPrompt != "" {
+		messages = append(messages, chatMessage{Role: "system", Content: systemPrompt})
+	}
+	messages = append(messages, chatMessage{Role: "user", Content: userPrompt})
+
+	request := chatCompletionRequest{
+		Model:       config.Name,
+		Messages:    messages,
+		Temperature: config.Options.Temperature,
+		TopP:        config.Options.TopP,
+		Stream:      stream,
+	}
+	if config.Options.Seed != 0 {
+		seed := config.Options.Seed
+		request.Seed = &seed
+	}
+	if config.Options.NumPredict > 0 {
+		request.MaxTokens = config.Options.NumPredict
+	}
+	return request
+}
+
+func (c *OpenAIClient) newRequest(ctx context.Context, path string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return req, nil
+}
+
+// CheckConnection verifies that the endpoint is available
+func (c *OpenAIClient) CheckConnection(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/v1/models", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("openai-compatible endpoint returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Generate sends a chat completion request to an OpenAI-compatible endpoint
+func (c *OpenAIClient) Generate(ctx context.Context, config ModelConfig, systemPrompt, userPrompt string) (*GenerateResponse, error) {
+	provider := providerLabel(config)
+	metrics.inFlight.WithLabelValues(config.Name, provider).Inc()
+	defer metrics.inFlight.WithLabelValues(config.Name, provider).Dec()
+
+	request := c.chatRequest(config, systemPrompt, userPrompt, false)
+
+	inputTokens := countTokensForModel(systemPrompt+userPrompt, config.Name)
+	log.Printf("Sending chat completion request to model %s (%d input tokens)", config.Name, inputTokens)
+
+	if err := checkContextLimit(inputTokens, config); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, "/v1/chat/completions", jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai-compatible endpoint returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var completion chatCompletionResponse
+	if err := json.Unmarshal(body, &completion); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("openai-compatible endpoint returned no choices")
+	}
+
+	response := &GenerateResponse{
+		Model:           completion.Model,
+		Response:        completion.Choices[0].Message.Content,
+		Done:            true,
+		DoneReason:      completion.Choices[0].FinishReason,
+		PromptEvalCount: completion.Usage.PromptTokens,
+		EvalCount:       completion.Usage.CompletionTokens,
+		TotalDuration:   time.Since(startTime),
+	}
+
+	duration := time.Since(startTime)
+	tokensPerSecond := float64(response.EvalCount) / duration.Seconds()
+	metrics.requestDuration.WithLabelValues(config.Name, provider).Observe(duration.Seconds())
+	metrics.tokensPerSecond.WithLabelValues(config.Name, provider).Observe(tokensPerSecond)
+	log.Printf("Received chat completion response from %s: duration=%.2fs, output_tokens=%d, tokens_per_second=%.2f",
+		config.Name, duration.Seconds(), response.EvalCount, tokensPerSecond)
+
+	return response, nil
+}
+
+// GenerateStream sends a streaming chat completion request, invoking
+// onDelta with each chunk of visible content text as it arrives. It
+// applies the same <think>...</think> filtering as Client.GenerateStream.
+func (c *OpenAIClient) GenerateStream(ctx context.Context, config ModelConfig, systemPrompt, userPrompt string, onDelta func(chunk string) error) (*GenerateResponse, error) {
+	provider := providerLabel(config)
+	metrics.inFlight.WithLabelValues(config.Name, provider).Inc()
+	defer metrics.inFlight.WithLabelValues(config.Name, provider).Dec()
+
+	request := c.chatRequest(config, systemPrompt, userPrompt, true)
+
+	inputTokens := countTokensForModel(systemPrompt+userPrompt, config.Name)
+	log.Printf("Sending streaming chat completion request to model %s (%d input tokens)", config.Name, inputTokens)
+
+	if err := checkContextLimit(inputTokens, config); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, "/v1/chat/completions", jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("openai-compatible endpoint returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var (
+		fullResponse strings.Builder
+		pending      strings.Builder
+		final        GenerateResponse
+	)
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "[DONE]" {
+			final.Done = true
+			break
+		}
+
+		var chunk chatCompletionResponse
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			continue // Skip invalid lines
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		final.Model = chunk.Model
+		delta := chunk.Choices[0].Delta.Content
+		fullResponse.WriteString(delta)
+		pending.WriteString(delta)
+
+		if visible := nextVisibleDelta(&pending, false); visible != "" {
+			if err := onDelta(visible); err != nil {
+				return nil, fmt.Errorf("onDelta callback failed: %w", err)
+			}
+		}
+
+		if chunk.Choices[0].FinishReason != "" {
+			final.DoneReason = chunk.Choices[0].FinishReason
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read streaming response: %w", err)
+	}
+
+	if visible := nextVisibleDelta(&pending, true); visible != "" {
+		if err := onDelta(visible); err != nil {
+			return nil, fmt.Errorf("onDelta callback failed: %w", err)
+		}
+	}
+
+	final.Response = fullResponse.String()
+	final.Done = true
+
+	duration := time.Since(startTime)
+	outputTokens := countTokensForModel(final.Response, config.Name)
+	final.EvalCount = outputTokens
+	final.TotalDuration = duration
+	tokensPerSecond := float64(outputTokens) / duration.Seconds()
+	metrics.requestDuration.WithLabelValues(config.Name, provider).Observe(duration.Seconds())
+	metrics.tokensPerSecond.WithLabelValues(config.Name, provider).Observe(tokensPerSecond)
+
+	log.Printf("Received streaming chat completion response from %s: duration=%.2fs, output_tokens=%d, tokens_per_second=%.2f",
+		config.Name, duration.Seconds(), outputTokens, tokensPerSecond)
+
+	return &final, nil
+}
@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenAIClient_Generate(t *testing.T) {
+	var gotAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		resp := chatCompletionResponse{
+			Model: "gpt-test",
+			Choices: []chatCompletionChoice{
+				{Message: chatMessage{Content: "hello there"}, FinishReason: "stop"},
+			},
+			Usage: chatCompletionUsage{PromptTokens: 5, CompletionTokens: 2},
+		}
+		data, _ := json.Marshal(resp)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(server.URL, "secret-key", 0)
+	response, err := client.Generate(context.Background(), CreateDefaultModelConfig("gpt-test"), "system", "prompt")
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	if response.Response != "hello there" {
+		t.Errorf("expected response content %q, got %q", "hello there", response.Response)
+	}
+	if gotAuth != "Bearer secret-key" {
+		t.Errorf("expected bearer auth header, got %q", gotAuth)
+	}
+}
+
+func TestOpenAIClient_GenerateStream(t *testing.T) {
+	chunks := []string{
+		`data: {"model":"gpt-test","choices":[{"delta":{"content":"<think>hmm"}}]}`,
+		`data: {"model":"gpt-test","choices":[{"delta":{"content":"</think>Hi "}}]}`,
+		`data: {"model":"gpt-test","choices":[{"delta":{"content":"there"},"finish_reason":"stop"}]}`,
+		`data: [DONE]`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, _ := w.(http.Flusher)
+		for _, chunk := range chunks {
+			w.Write([]byte(chunk + "\n"))
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(server.URL, "", 0)
+
+	var deltas []string
+	response, err := client.GenerateStream(context.Background(), CreateDefaultModelConfig("gpt-test"), "system", "prompt", func(chunk string) error {
+		deltas = append(deltas, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GenerateStream returned error: %v", err)
+	}
+
+	if response.Response != "<think>hmm</think>Hi there" {
+		t.Errorf("expected full raw response, got %q", response.Response)
+	}
+	if !response.Done {
+		t.Error("expected the final response to report done")
+	}
+
+	got := ""
+	for _, d := range deltas {
+		got += d
+	}
+	if got != "Hi there" {
+		t.Errorf("expected streamed deltas to filter out the think block, got %q", got)
+	}
+}
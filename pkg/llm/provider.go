@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Provider is the common interface every LLM backend implements, so the
+// rest of the codebase (GetGenerateResponse, jobs.Executor) can work
+// against whichever one NewProvider built without knowing its transport
+// details.
+type Provider interface {
+	// Generate sends a single prompt and waits for the complete response.
+	Generate(ctx context.Context, config ModelConfig, systemPrompt, userPrompt string) (*GenerateResponse, error)
+
+	// GenerateStream behaves like Generate but invokes onDelta with each
+	// chunk of visible response text as it arrives.
+	GenerateStream(ctx context.Context, config ModelConfig, systemPrompt, userPrompt string, onDelta func(chunk string) error) (*GenerateResponse, error)
+
+	// CheckConnection verifies the backend is reachable.
+	CheckConnection(ctx context.Context) error
+}
+
+var _ Provider = (*Client)(nil)
+var _ Provider = (*OpenAIClient)(nil)
+
+// NewProvider builds the Provider named by providerName ("ollama" or
+// "openai"; "" defaults to "ollama"). For "openai", apiKey is passed as a
+// bearer token; Ollama ignores it.
+func NewProvider(providerName, baseURL, apiKey string, timeout time.Duration) (Provider, error) {
+	switch providerName {
+	case "", "ollama":
+		return NewClient(baseURL, timeout), nil
+	case "openai":
+		return NewOpenAIClient(baseURL, apiKey, timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", providerName)
+	}
+}
@@ -0,0 +1,28 @@
+package llm
+
+import "testing"
+
+func TestNewProvider(t *testing.T) {
+	if _, ok := mustProvider(t, "").(*Client); !ok {
+		t.Error("expected empty provider name to default to *Client")
+	}
+	if _, ok := mustProvider(t, "ollama").(*Client); !ok {
+		t.Error("expected \"ollama\" to resolve to *Client")
+	}
+	if _, ok := mustProvider(t, "openai").(*OpenAIClient); !ok {
+		t.Error("expected \"openai\" to resolve to *OpenAIClient")
+	}
+
+	if _, err := NewProvider("anthropic", "", "", 0); err == nil {
+		t.Error("expected an error for an unknown provider name")
+	}
+}
+
+func mustProvider(t *testing.T, name string) Provider {
+	t.Helper()
+	provider, err := NewProvider(name, "", "", 0)
+	if err != nil {
+		t.Fatalf("NewProvider(%q) returned error: %v", name, err)
+	}
+	return provider
+}
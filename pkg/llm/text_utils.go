@@ -2,6 +2,8 @@ package llm
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -9,103 +11,140 @@ import (
 type TextSplitter struct {
 	MaxLength     int
 	PreserveLines bool
+
+	// Strategy overrides the splitting algorithm SplitText uses. Left nil
+	// (as NewTextSplitter leaves it), SplitText behaves exactly as before:
+	// LineBlockStrategy honoring PreserveLines. Set it to ParagraphStrategy
+	// or a TokenBudgetStrategy to chunk on a different unit instead.
+	Strategy ChunkStrategy
 }
 
-// NewTextSplitter creates a new text splitter with default settings
-func NewTextSplitter(maxLength int) *TextSplitter {
-	return &TextSplitter{
-		MaxLength:     maxLength,
-		PreserveLines: true,
-	}
+// blockKind identifies the kind of atomic unit SplitText groups lines into,
+// so a chunk boundary never lands inside a code fence or a markdown table.
+type blockKind int
+
+const (
+	blockText blockKind = iota
+	blockFence
+	blockTable
+)
+
+// block is a contiguous run of lines SplitText treats as a single unit when
+// deciding where to place a chunk boundary.
+type block struct {
+	kind  blockKind
+	lines []string
+	lang  string // fence language tag, e.g. "go" in "```go"; empty for a bare fence
 }
 
-// SplitText splits text into chunks while preserving line breaks and formatting
-func (ts *TextSplitter) SplitText(content string) []string {
-	if len(content) <= ts.MaxLength {
-		return []string{content}
-	}
+// tableSeparatorPattern matches a markdown table's header-separator row,
+// e.g. "|---|---|" or ":--- | ---:".
+var tableSeparatorPattern = regexp.MustCompile(`^[\s|:-]*-[\s|:-]*$`)
 
-	if !ts.PreserveLines {
-		// Simple character-based splitting
-		return ts.splitByCharacters(content)
-	}
+func isTableSeparator(line string) bool {
+	return tableSeparatorPattern.MatchString(line)
+}
 
-	// Split by lines first to avoid breaking in the middle of sentences
-	lines := strings.Split(content, "\n")
-	var chunks []string
-	var currentChunk strings.Builder
-
-	for _, line := range lines {
-		// If adding this line would exceed the limit, start a new chunk
-		if currentChunk.Len()+len(line)+1 > ts.MaxLength {
-			if currentChunk.Len() > 0 {
-				chunks = append(chunks, currentChunk.String())
-				currentChunk.Reset()
-			}
+func isTableRow(line string) bool {
+	return strings.Contains(line, "|")
+}
 
-			// Handle very long lines that exceed maxLength
-			if len(line) > ts.MaxLength {
-				longLineChunks := ts.splitLongLine(line)
-				chunks = append(chunks, longLineChunks[:len(longLineChunks)-1]...)
-				// Start new chunk with the last piece
-				currentChunk.WriteString(longLineChunks[len(longLineChunks)-1])
-				continue
+// parseBlocks groups lines into fence blocks (```...```), table blocks
+// (a header row, its separator row, and contiguous `|`-rows), and single-line
+// text blocks, so the caller can keep each atomic unit whole when splitting.
+func parseBlocks(lines []string) []block {
+	var blocks []block
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			lang := strings.TrimPrefix(trimmed, "```")
+			fenceLines := []string{line}
+			j := i + 1
+			for j < len(lines) {
+				fenceLines = append(fenceLines, lines[j])
+				j++
+				if strings.TrimSpace(lines[j-1]) == "```" {
+					break
+				}
 			}
+			blocks = append(blocks, block{kind: blockFence, lines: fenceLines, lang: lang})
+			i = j
+			continue
 		}
 
-		if currentChunk.Len() > 0 {
-			currentChunk.WriteString("\n")
+		if i+1 < len(lines) && isTableRow(line) && isTableSeparator(lines[i+1]) {
+			tableLines := []string{line, lines[i+1]}
+			j := i + 2
+			for j < len(lines) && isTableRow(lines[j]) {
+				tableLines = append(tableLines, lines[j])
+				j++
+			}
+			blocks = append(blocks, block{kind: blockTable, lines: tableLines})
+			i = j
+			continue
 		}
-		currentChunk.WriteString(line)
-	}
 
-	// Add the final chunk
-	if currentChunk.Len() > 0 {
-		chunks = append(chunks, currentChunk.String())
+		blocks = append(blocks, block{kind: blockText, lines: []string{line}})
+		i++
 	}
 
-	return chunks
+	return blocks
 }
 
-// splitByCharacters splits text by character count without preserving lines
-func (ts *TextSplitter) splitByCharacters(content string) []string {
-	var chunks []string
-	for len(content) > ts.MaxLength {
-		chunks = append(chunks, content[:ts.MaxLength])
-		content = content[ts.MaxLength:]
-	}
-	if len(content) > 0 {
-		chunks = append(chunks, content)
+// NewTextSplitter creates a new text splitter with default settings
+func NewTextSplitter(maxLength int) *TextSplitter {
+	return &TextSplitter{
+		MaxLength:     maxLength,
+		PreserveLines: true,
 	}
-	return chunks
 }
 
-// splitLongLine splits a single line that exceeds maxLength
-func (ts *TextSplitter) splitLongLine(line string) []string {
-	var chunks []string
-	for len(line) > ts.MaxLength {
-		chunks = append(chunks, line[:ts.MaxLength])
-		line = line[ts.MaxLength:]
-	}
-	if len(line) > 0 {
-		chunks = append(chunks, line)
+// SplitText splits content into chunks no larger than MaxLength (apart from
+// the table/fence exception described on ChunkStrategy) using Strategy, or
+// LineBlockStrategy (today's line/block-aware behavior, honoring
+// PreserveLines) if Strategy is unset.
+func (ts *TextSplitter) SplitText(content string) []string {
+	strategy := ts.Strategy
+	if strategy == nil {
+		strategy = LineBlockStrategy{PreserveLines: ts.PreserveLines}
 	}
-	return chunks
+	return strategy.Split(content, ts.MaxLength)
+}
+
+// maxPartPrefixLen upper-bounds the byte length of "(Part i/N)\n" once
+// content splits into roughly n chunks. It pads one extra digit so a
+// tighter budget that nudges the final chunk count across a power-of-ten
+// boundary (e.g. 9 -> 10 chunks) still leaves enough room.
+func maxPartPrefixLen(n int) int {
+	digits := len(strconv.Itoa(n)) + 1
+	nines := strings.Repeat("9", digits)
+	return len(fmt.Sprintf("(Part %s/%s)\n", nines, nines))
 }
 
-// SplitTextWithParts splits text and adds part indicators to each chunk
+// SplitTextWithParts splits text and adds a "(Part i/N)" indicator to every
+// chunk, including the first, so a reader sees the ordering even if
+// messages arrive out of order. The prefix itself counts against
+// MaxLength: once a first pass shows content needs more than one chunk,
+// SplitTextWithParts re-splits at a budget that reserves room for the
+// prefix, so adding it afterward can't push a chunk over MaxLength.
 func (ts *TextSplitter) SplitTextWithParts(content string) []string {
 	chunks := ts.SplitText(content)
-
 	if len(chunks) <= 1 {
 		return chunks
 	}
 
-	// Add part indicators to multi-part messages
+	reserved := *ts
+	reserved.MaxLength = ts.MaxLength - maxPartPrefixLen(len(chunks))
+	if reserved.MaxLength < 1 {
+		reserved.MaxLength = 1
+	}
+	chunks = reserved.SplitText(content)
+
 	for i := range chunks {
-		if i > 0 {
-			chunks[i] = fmt.Sprintf("(Part %d/%d)\n%s", i+1, len(chunks), chunks[i])
-		}
+		chunks[i] = fmt.Sprintf("(Part %d/%d)\n%s", i+1, len(chunks), chunks[i])
 	}
 
 	return chunks
@@ -3,6 +3,7 @@ package llm
 import (
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestTextSplitter_SplitText(t *testing.T) {
@@ -28,6 +29,11 @@ func TestTextSplitter_SplitText(t *testing.T) {
 			input:    strings.Repeat("a", 200),
 			expected: 4, // 200/50 = 4 chunks
 		},
+		{
+			name:     "very long line of multi-byte runes",
+			input:    strings.Repeat("日", 100), // 3 bytes/rune, 300 bytes total
+			expected: 7,                         // rune-safe cuts of 48 bytes: 6*48 + 12 = 7 chunks
+		},
 	}
 
 	for _, tt := range tests {
@@ -37,11 +43,14 @@ func TestTextSplitter_SplitText(t *testing.T) {
 				t.Errorf("SplitText() returned %d chunks, expected %d", len(chunks), tt.expected)
 			}
 
-			// Verify no chunk exceeds maxLength
+			// Verify no chunk exceeds maxLength and none split a multi-byte rune in half
 			for i, chunk := range chunks {
 				if len(chunk) > splitter.MaxLength {
 					t.Errorf("Chunk %d has length %d, exceeds max length %d", i, len(chunk), splitter.MaxLength)
 				}
+				if !utf8.ValidString(chunk) {
+					t.Errorf("Chunk %d is not valid UTF-8: %q", i, chunk)
+				}
 			}
 
 			// Verify rejoining chunks gives original content (for line-based splitting)
@@ -76,8 +85,9 @@ func TestTextSplitter_SplitTextWithParts(t *testing.T) {
 		t.Skip("Input too short to test part indicators")
 	}
 
-	// Check that parts 2+ have part indicators
-	for i := 1; i < len(chunks); i++ {
+	// Every chunk, including the first, should carry a part indicator so
+	// readers can order messages that may arrive out of sequence.
+	for i := range chunks {
 		expectedPrefix := "(Part "
 		if !strings.HasPrefix(chunks[i], expectedPrefix) {
 			t.Errorf("Chunk %d should have part indicator, got: %s", i+1, chunks[i][:20])
@@ -85,6 +95,76 @@ func TestTextSplitter_SplitTextWithParts(t *testing.T) {
 	}
 }
 
+func TestTextSplitter_SplitTextPreservesCodeFence(t *testing.T) {
+	splitter := NewTextSplitter(60)
+
+	var body strings.Builder
+	for i := 0; i < 10; i++ {
+		body.WriteString("some code line that takes up space\n")
+	}
+	input := "intro text\n```go\n" + body.String() + "```\nmore text after"
+
+	chunks := splitter.SplitText(input)
+	if len(chunks) < 2 {
+		t.Fatalf("expected input to split into multiple chunks, got %d", len(chunks))
+	}
+
+	for i, chunk := range chunks {
+		open := strings.Count(chunk, "```")
+		if open%2 != 0 {
+			t.Errorf("chunk %d has an unterminated fence: %q", i, chunk)
+		}
+	}
+}
+
+func TestTextSplitter_SplitTextPreservesTable(t *testing.T) {
+	splitter := NewTextSplitter(60)
+
+	var rows strings.Builder
+	for i := 0; i < 10; i++ {
+		rows.WriteString("| item | price | volume |\n")
+	}
+	input := "intro text\n| Item | Price | Volume |\n|---|---|---|\n" + rows.String() + "more text after"
+
+	chunks := splitter.SplitText(input)
+	if len(chunks) < 2 {
+		t.Fatalf("expected input to split into multiple chunks, got %d", len(chunks))
+	}
+
+	for i, chunk := range chunks {
+		if strings.Contains(chunk, "|") && !strings.Contains(chunk, "---") {
+			t.Errorf("chunk %d contains table rows but is missing the repeated header separator: %q", i, chunk)
+		}
+	}
+}
+
+func TestTextSplitter_SplitTextWithParts_PrefixCountsAgainstBudget(t *testing.T) {
+	splitter := NewTextSplitter(30)
+	input := strings.Repeat("This is a long line.\n", 20)
+
+	chunks := splitter.SplitTextWithParts(input)
+	for i, chunk := range chunks {
+		if len(chunk) > splitter.MaxLength {
+			t.Errorf("chunk %d (with part prefix) has length %d, exceeds MaxLength %d: %q", i, len(chunk), splitter.MaxLength, chunk)
+		}
+	}
+}
+
+func TestTextSplitter_SplitTextPreservesCodeFenceAtBoundary(t *testing.T) {
+	splitter := NewTextSplitter(40)
+
+	// A fence that starts right where the preceding text would otherwise
+	// push a chunk boundary.
+	input := "short intro\n```go\nfunc longEnoughBody() { return }\n```\nshort outro"
+
+	chunks := splitter.SplitText(input)
+	for i, chunk := range chunks {
+		if strings.Count(chunk, "```")%2 != 0 {
+			t.Errorf("chunk %d has an unterminated fence: %q", i, chunk)
+		}
+	}
+}
+
 func TestTruncateText(t *testing.T) {
 	tests := []struct {
 		name      string
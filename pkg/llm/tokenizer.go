@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/daulet/tokenizers"
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// tokenizerRule pairs a model-name substring pattern (matched
+// case-insensitively) with the counter to use for models that contain it.
+type tokenizerRule struct {
+	pattern string
+	counter func(content string) int
+}
+
+var (
+	tokenizerMu    sync.RWMutex
+	tokenizerRules []tokenizerRule
+)
+
+func init() {
+	// tiktoken-backed encodings. Later registrations are checked first, so a
+	// narrower pattern ("gpt-4o") is registered after the broader one it
+	// overlaps ("gpt-4").
+	registerTiktokenTokenizer("gpt-4", "cl100k_base")
+	registerTiktokenTokenizer("gpt-3.5", "cl100k_base")
+	registerTiktokenTokenizer("gpt-4o", "o200k_base")
+	registerTiktokenTokenizer("gemma", "cl100k_base")
+	registerTiktokenTokenizer("code-davinci", "p50k_base")
+	registerTiktokenTokenizer("text-davinci", "p50k_base")
+
+	// tiktoken's BPE merges don't match these model families closely enough
+	// for the NumCtx guard to be trustworthy, so count with their own
+	// Hugging Face tokenizer instead.
+	registerHFTokenizer("qwen", "Qwen/Qwen2.5-14B-Instruct")
+	registerHFTokenizer("llama", "meta-llama/Llama-3.1-8B-Instruct")
+	registerHFTokenizer("mistral", "mistralai/Mistral-7B-Instruct-v0.3")
+}
+
+// RegisterTokenizer registers counter for any model name containing pattern
+// (matched case-insensitively). Patterns are checked most-recently
+// registered first, so registering after init() lets a caller override a
+// built-in pattern (e.g. a narrower "qwen2" to take precedence over the
+// built-in "qwen") or add support for a new model family entirely.
+func RegisterTokenizer(pattern string, counter func(content string) int) {
+	tokenizerMu.Lock()
+	defer tokenizerMu.Unlock()
+	tokenizerRules = append([]tokenizerRule{{pattern: strings.ToLower(pattern), counter: counter}}, tokenizerRules...)
+}
+
+func registerTiktokenTokenizer(pattern, encodingName string) {
+	encoding, err := tiktoken.GetEncoding(encodingName)
+	if err != nil {
+		log.Printf("Warning: failed to load tiktoken encoding %s for pattern %q: %v", encodingName, pattern, err)
+		return
+	}
+	RegisterTokenizer(pattern, func(content string) int {
+		return len(encoding.Encode(content, nil, nil))
+	})
+}
+
+// hfTokenizers caches loaded Hugging Face tokenizers by model name, since
+// FromPretrained downloads and parses a tokenizer.json on first use.
+var hfTokenizers sync.Map // map[string]*tokenizers.Tokenizer
+
+func registerHFTokenizer(pattern, hfModel string) {
+	RegisterTokenizer(pattern, func(content string) int {
+		tok, err := loadHFTokenizer(hfModel)
+		if err != nil {
+			log.Printf("Warning: failed to load Hugging Face tokenizer %s, using estimation: %v", hfModel, err)
+			return fallbackTokenCount(content)
+		}
+		ids, _ := tok.Encode(content, false)
+		return len(ids)
+	})
+}
+
+func loadHFTokenizer(hfModel string) (*tokenizers.Tokenizer, error) {
+	if cached, ok := hfTokenizers.Load(hfModel); ok {
+		return cached.(*tokenizers.Tokenizer), nil
+	}
+
+	tok, err := tokenizers.FromPretrained(hfModel)
+	if err != nil {
+		return nil, err
+	}
+
+	hfTokenizers.Store(hfModel, tok)
+	return tok, nil
+}
+
+// countTokensForModel looks up the tokenizer registered for modelName and
+// falls back to a cl100k_base tiktoken count, then word/char estimation, if
+// no registered pattern matches.
+func countTokensForModel(content string, modelName string) int {
+	tokenizerMu.RLock()
+	rules := tokenizerRules
+	tokenizerMu.RUnlock()
+
+	lowerName := strings.ToLower(modelName)
+	for _, rule := range rules {
+		if strings.Contains(lowerName, rule.pattern) {
+			return rule.counter(content)
+		}
+	}
+
+	encoding, err := tiktoken.GetEncoding("cl100k_base")
+	if err != nil {
+		log.Printf("Warning: tiktoken encoding failed for %s, using estimation: %v", modelName, err)
+		return fallbackTokenCount(content)
+	}
+
+	return len(encoding.Encode(content, nil, nil))
+}
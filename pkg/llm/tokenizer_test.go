@@ -0,0 +1,48 @@
+package llm
+
+import "testing"
+
+func TestRegisterTokenizer_OverridesBuiltin(t *testing.T) {
+	var calls int
+	RegisterTokenizer("qwen3", func(content string) int {
+		calls++
+		return 7
+	})
+
+	if got := countTokensForModel("hello world", "qwen3:14b"); got != 7 {
+		t.Errorf("expected the overriding pattern to win, got %d", got)
+	}
+	if calls != 1 {
+		t.Errorf("expected the custom counter to be invoked once, got %d", calls)
+	}
+}
+
+func TestCountTokensForModel_FallsBackToCl100kBase(t *testing.T) {
+	if got := countTokensForModel("hello world", "some-unregistered-model"); got <= 0 {
+		t.Errorf("expected a positive token count for an unregistered model, got %d", got)
+	}
+}
+
+func TestCountTokensForModel_EmptyContent(t *testing.T) {
+	if got := countTokensForModel("", "gpt-4"); got != 0 {
+		t.Errorf("expected 0 tokens for empty content, got %d", got)
+	}
+}
+
+func TestCheckContextLimit(t *testing.T) {
+	config := CreateDefaultModelConfig("test-model")
+	config.Options.NumCtx = 100
+
+	if err := checkContextLimit(50, config); err != nil {
+		t.Errorf("expected no error when under the context limit, got %v", err)
+	}
+
+	if err := checkContextLimit(150, config); err != nil {
+		t.Errorf("expected only a warning (no error) over the limit without StrictContext, got %v", err)
+	}
+
+	config.StrictContext = true
+	if err := checkContextLimit(150, config); err == nil {
+		t.Error("expected an error over the limit with StrictContext set")
+	}
+}
@@ -4,10 +4,21 @@ import (
 	"time"
 )
 
-// ModelConfig represents an Ollama model configuration
+// ModelConfig represents a model configuration for a given backend
 type ModelConfig struct {
 	Name    string  `json:"name"`
 	Options Options `json:"options"`
+
+	// Provider names the backend for this config: "ollama" (the default,
+	// also when empty) or "openai". It labels the LLM metrics; it does not
+	// choose the Provider implementation that Generate/GenerateStream run
+	// on, which is whichever one the caller built (see NewProvider).
+	Provider string `json:"provider,omitempty"`
+
+	// StrictContext makes Generate/GenerateStream return an error instead of
+	// just logging a warning when the estimated input token count exceeds
+	// Options.NumCtx.
+	StrictContext bool `json:"strict_context,omitempty"`
 }
 
 // Options represents Ollama generation options
@@ -50,7 +61,8 @@ type GenerateResponse struct {
 // CreateDefaultModelConfig creates a ModelConfig with sensible defaults
 func CreateDefaultModelConfig(name string) ModelConfig {
 	return ModelConfig{
-		Name: name,
+		Name:     name,
+		Provider: "ollama",
 		Options: Options{
 			NumCtx:      4096,
 			Temperature: 0.8,
@@ -66,7 +78,8 @@ func CreateDefaultModelConfig(name string) ModelConfig {
 // CreateQwen3ModelConfig creates a ModelConfig for qwen3:14b with expanded context
 func CreateQwen3ModelConfig() ModelConfig {
 	return ModelConfig{
-		Name: "qwen3:14b",
+		Name:     "qwen3:14b",
+		Provider: "ollama",
 		Options: Options{
 			NumCtx:      8000, // 20k token context to match notebook
 			Temperature: 0.8,
@@ -17,22 +17,6 @@ type Logger struct {
 func NewLogger(level, format string) *Logger {
 	logger := logrus.New()
 
-	// Set log level
-	switch strings.ToLower(level) {
-	case "debug":
-		logger.SetLevel(logrus.DebugLevel)
-	case "info":
-		logger.SetLevel(logrus.InfoLevel)
-	case "warn", "warning":
-		logger.SetLevel(logrus.WarnLevel)
-	case "error":
-		logger.SetLevel(logrus.ErrorLevel)
-	case "fatal":
-		logger.SetLevel(logrus.FatalLevel)
-	default:
-		logger.SetLevel(logrus.InfoLevel)
-	}
-
 	// Set output format
 	switch strings.ToLower(format) {
 	case "json":
@@ -64,7 +48,30 @@ func NewLogger(level, format string) *Logger {
 	// Set output (stdout for containers)
 	logger.SetOutput(os.Stdout)
 
-	return &Logger{Logger: logger}
+	l := &Logger{Logger: logger}
+	l.SetLevel(level)
+	return l
+}
+
+// SetLevel changes the logger's level at runtime (e.g. after a config
+// reload), using the same string values as NewLogger. An unrecognized
+// level is treated as "info". logrus.Logger's level is safe to change
+// concurrently with in-flight logging calls.
+func (l *Logger) SetLevel(level string) {
+	switch strings.ToLower(level) {
+	case "debug":
+		l.Logger.SetLevel(logrus.DebugLevel)
+	case "info":
+		l.Logger.SetLevel(logrus.InfoLevel)
+	case "warn", "warning":
+		l.Logger.SetLevel(logrus.WarnLevel)
+	case "error":
+		l.Logger.SetLevel(logrus.ErrorLevel)
+	case "fatal":
+		l.Logger.SetLevel(logrus.FatalLevel)
+	default:
+		l.Logger.SetLevel(logrus.InfoLevel)
+	}
 }
 
 // WithComponent adds a component field to all log entries
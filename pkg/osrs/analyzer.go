@@ -3,26 +3,194 @@ package osrs
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"osrs-flipping/pkg/osrs/expr"
+	"osrs-flipping/pkg/osrs/factors"
+	"osrs-flipping/pkg/osrs/indicator"
+	"osrs-flipping/pkg/osrs/pca"
+	"osrs-flipping/pkg/osrs/portfolio"
+	"osrs-flipping/pkg/osrs/store"
 )
 
+// defaultStorePath is where the rolling timeseries cache persists across
+// runs so a cold start doesn't lose price history.
+const defaultStorePath = "output/data/market_store.gob"
+
+// defaultSimilarItemCount is how many nearest neighbors are recorded on each
+// item's SimilarItems field.
+const defaultSimilarItemCount = 5
+
 // Analyzer is the main class equivalent to OSRSItemFilter in Python
 type Analyzer struct {
-	client *Client
-	items  []ItemData
+	client          *Client
+	items           []ItemData
+	store           *store.Store
+	pcaEmbedding    *pca.Embedding
+	indicatorConfig IndicatorConfig
+
+	// portfolio and maxOpenGPPerItem, when both set, let getTopItemIDs
+	// deprioritize items the user is already heavily exposed to (see
+	// SetPortfolio).
+	portfolio        *portfolio.Portfolio
+	maxOpenGPPerItem int
+
+	// flipModel is the fitted regression model from TrainFlipModel, used by
+	// ScoreItems and getTopItemIDs (sortByModelScore) to rank candidates by
+	// predicted return. Nil until trained or loaded.
+	flipModel *factors.Model
+
+	// forceRefresh makes calculateVolumeMetrics bypass the store's cached
+	// timeseries (see SetForceRefresh) and always hit the API.
+	forceRefresh bool
+
+	// forecastConfig tunes the Holt-Winters model ForecastPrice fits to each
+	// item's stored timeseries (see SetForecastConfig).
+	forecastConfig ForecastConfig
+
+	// dataQualityConfig tunes the volume-reset/outlier/gap scan
+	// calculate5mMetrics and calculate24hMetrics run over each item's raw
+	// timeseries (see SetDataQualityConfig).
+	dataQualityConfig DataQualityConfig
+
+	// scrapeRecorder observes each API fetch LoadData and
+	// calculateVolumeMetrics make (see SetScrapeRecorder). Nil by default, so
+	// Analyzer behaves identically whether or not a caller wires one in.
+	scrapeRecorder ScrapeRecorder
+
+	// incidentConfig tunes DetectIncidents' warn/critical/cooldown margin
+	// state machine (see SetIncidentConfig).
+	incidentConfig IncidentConfig
+
+	// incidents is DetectIncidents' per-item open/closed incident state
+	// store (see RecentIncidents).
+	incidents *incidentStore
+
+	// incidentNotifier, if set, is posted a notice each time DetectIncidents
+	// opens, upgrades, or closes an incident (see SetIncidentNotifier).
+	incidentNotifier IncidentNotifier
+
+	// spreadConfig tunes applySpreadSignal's market-making quote suggestion
+	// (see SetSpreadConfig).
+	spreadConfig SpreadConfig
+}
+
+// ScrapeRecorder observes the outcome of a single upstream API fetch, keyed
+// by a short endpoint name ("latest", "mapping", "5m", "24h"). It exists so
+// a caller like pkg/osrs/metrics can publish per-endpoint scrape-health
+// telemetry without Analyzer importing a metrics package itself -- the same
+// dependency-injection shape as SetPortfolio and scheduler.BreakerNotifier.
+type ScrapeRecorder interface {
+	RecordScrape(endpoint string, start time.Time, err error)
+}
+
+// SetScrapeRecorder wires r into the analyzer so LoadData and
+// calculateVolumeMetrics report each fetch's outcome and duration through
+// it, e.g. to pkg/osrs/metrics.Metrics.
+func (a *Analyzer) SetScrapeRecorder(r ScrapeRecorder) {
+	a.scrapeRecorder = r
+}
+
+// recordScrape reports a single fetch's outcome through scrapeRecorder, if
+// one is wired in (SetScrapeRecorder) -- a no-op otherwise.
+func (a *Analyzer) recordScrape(endpoint string, start time.Time, err error) {
+	if a.scrapeRecorder != nil {
+		a.scrapeRecorder.RecordScrape(endpoint, start, err)
+	}
 }
 
-// NewAnalyzer creates a new OSRS analyzer instance
+// NewAnalyzer creates a new OSRS analyzer instance. Its timeseries store is
+// loaded from defaultStorePath if present; use SetStore to point it
+// elsewhere (e.g. in tests).
 func NewAnalyzer(userAgent string) *Analyzer {
+	s := store.New(defaultStorePath, 0, 0)
+	if err := s.Load(); err != nil {
+		fmt.Printf("⚠️  failed to load market data store: %v\n", err)
+	}
+
 	return &Analyzer{
-		client: NewClient(userAgent),
-		items:  make([]ItemData, 0),
+		client:            NewClient(userAgent),
+		items:             make([]ItemData, 0),
+		store:             s,
+		indicatorConfig:   DefaultIndicatorConfig(),
+		forecastConfig:    DefaultForecastConfig(),
+		dataQualityConfig: DefaultDataQualityConfig(),
+		incidentConfig:    DefaultIncidentConfig(),
+		incidents:         newIncidentStore(),
+		spreadConfig:      DefaultSpreadConfig(),
 	}
 }
 
+// SetStore overrides the analyzer's timeseries store, e.g. to point it at a
+// test-local path or an in-memory (path-less) store.
+func (a *Analyzer) SetStore(s *store.Store) {
+	a.store = s
+}
+
+// SetIndicatorConfig overrides the EMA crossover tuning calculateTrend uses
+// to classify price trends, e.g. to tighten the threshold for a volatile
+// market or try different EMA spans in tests.
+func (a *Analyzer) SetIndicatorConfig(cfg IndicatorConfig) {
+	a.indicatorConfig = cfg
+}
+
+// SetForecastConfig overrides the smoothing factors and season length
+// ForecastPrice uses, e.g. to fit shorter seasons in tests where only a
+// handful of stored buckets are available.
+func (a *Analyzer) SetForecastConfig(cfg ForecastConfig) {
+	a.forecastConfig = cfg
+}
+
+// SetDataQualityConfig overrides the volume-reset/outlier/gap thresholds
+// (and whether offending buckets get sanitized) that calculate5mMetrics and
+// calculate24hMetrics use, e.g. to enable Sanitize or loosen MADThreshold
+// for a known-volatile item.
+func (a *Analyzer) SetDataQualityConfig(cfg DataQualityConfig) {
+	a.dataQualityConfig = cfg
+}
+
+// SetPortfolio wires p into the analyzer so getTopItemIDs can skip items
+// the user is already holding more than maxOpenGPPerItem GP of, per
+// p.OpenGP. maxOpenGPPerItem <= 0 disables the check even if a portfolio is
+// set.
+func (a *Analyzer) SetPortfolio(p *portfolio.Portfolio, maxOpenGPPerItem int) {
+	a.portfolio = p
+	a.maxOpenGPPerItem = maxOpenGPPerItem
+}
+
+// SetForceRefresh controls whether calculateVolumeMetrics trusts the
+// store's cached timeseries (the default) or always refetches from the
+// API -- the --force-refresh CLI flag's effect.
+func (a *Analyzer) SetForceRefresh(force bool) {
+	a.forceRefresh = force
+}
+
+// GetSeries exposes the rolling timeseries cache for a single item/interval
+// to indicator code (ATR, trend classification, etc.) without requiring
+// callers to re-fetch or re-parse raw API responses.
+func (a *Analyzer) GetSeries(itemID int, interval store.Interval, since time.Time) []store.DataPoint {
+	return a.store.GetSeries(itemID, interval, since)
+}
+
+// LastFetched exposes when a single item/interval was last fetched from the
+// API (as opposed to served from the store's own cache), so callers like
+// pkg/osrs/metrics can flag a series as stale without reaching into the
+// store directly.
+func (a *Analyzer) LastFetched(itemID int, interval store.Interval) (time.Time, bool) {
+	return a.store.LastFetched(itemID, interval)
+}
+
+// Client exposes the analyzer's underlying API client for callers that need
+// direct access to endpoints Analyzer doesn't itself wrap, such as
+// backtest.TimeseriesClient's per-item /timeseries replay.
+func (a *Analyzer) Client() *Client {
+	return a.client
+}
+
 // LoadData fetches and merges item mappings with latest prices
 // Equivalent to load_data method in Python
 func (a *Analyzer) LoadData(ctx context.Context, forceReload bool) error {
@@ -34,13 +202,17 @@ func (a *Analyzer) LoadData(ctx context.Context, forceReload bool) error {
 	fmt.Println("Loading data for filtering...")
 
 	// Get item mappings
-	mappings, err := a.client.GetItemMapping(ctx)
+	mappingStart := time.Now()
+	mappings, _, _, _, err := a.client.GetItemMapping(ctx, "", "")
+	a.recordScrape("mapping", mappingStart, err)
 	if err != nil {
 		return fmt.Errorf("getting item mappings: %w", err)
 	}
 
 	// Get latest prices
+	latestStart := time.Now()
 	prices, err := a.client.GetLatestPrices(ctx, nil)
+	a.recordScrape("latest", latestStart, err)
 	if err != nil {
 		return fmt.Errorf("getting latest prices: %w", err)
 	}
@@ -168,14 +340,32 @@ func (a *Analyzer) ApplyFilter(opts FilterOptions, verbose bool) ([]ItemData, er
 		fmt.Printf("Starting with %d items with price data\n", len(a.items))
 	}
 
+	// Compile opts.Expr once for the whole call rather than per item -- see
+	// package osrs/expr.
+	var compiledExpr *expr.Expr
+	if opts.Expr != "" {
+		compiled, err := expr.Compile(opts.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("compiling filter expression: %w", err)
+		}
+		compiledExpr = compiled
+	}
+
 	// Apply filters
 	for _, item := range a.items {
 		if item.ItemID == 13190 { // old school bond, requires additional tax
 			continue
 		}
-		if a.passesFilter(item, opts) {
-			filtered = append(filtered, item)
+		if !a.passesFilter(item, opts) {
+			continue
 		}
+		if compiledExpr != nil {
+			ok, err := compiledExpr.Eval(itemExprEnv{item: item, store: a.store})
+			if err != nil || !ok {
+				continue
+			}
+		}
+		filtered = append(filtered, item)
 	}
 
 	if verbose {
@@ -245,6 +435,20 @@ func (a *Analyzer) ApplySecondaryFilter(items []ItemData, opts FilterOptions, ve
 		fmt.Printf("Applying secondary filters (volume-based) to %d items...\n", len(items))
 	}
 
+	pivotLength := DefaultPivotLength
+	if opts.PivotLength != nil {
+		pivotLength = *opts.PivotLength
+	}
+	breakRatio := DefaultBreakRatio
+	if opts.BreakRatio != nil {
+		breakRatio = *opts.BreakRatio
+	}
+	for i := range items {
+		a.applyPivotSignal(&items[i], pivotLength, breakRatio)
+		a.applyVolatilitySignal(&items[i])
+		a.applySpreadSignal(&items[i])
+	}
+
 	// fmt.Printf("Volume Filter Options: %v\n", *opts.Volume20mMin)
 	for _, item := range items {
 		if a.passesVolumeFilters(item, opts) {
@@ -264,6 +468,21 @@ func (a *Analyzer) ApplySecondaryFilter(items []ItemData, opts FilterOptions, ve
 		}
 	}
 
+	// Enrich with PCA-based substitute suggestions, and optionally drop
+	// items too similar to one already kept so the LLM doesn't see ten
+	// near-identical items.
+	if embedding := a.EnsurePCAEmbedding(); embedding != nil {
+		for i := range filtered {
+			a.applySimilarItems(&filtered[i], embedding)
+		}
+		if opts.ExcludeSimilarTo != nil && *opts.ExcludeSimilarTo {
+			filtered = diversifyBySimilarity(filtered, embedding, defaultSimilarItemCount)
+			if verbose {
+				fmt.Printf("After similarity diversification: %d items remain\n", len(filtered))
+			}
+		}
+	}
+
 	// Apply limit
 	if opts.Limit > 0 && len(filtered) > opts.Limit {
 		filtered = filtered[:opts.Limit]
@@ -277,47 +496,410 @@ func (a *Analyzer) ApplySecondaryFilter(items []ItemData, opts FilterOptions, ve
 
 // passesVolumeFilters checks if an item passes volume-based filter criteria
 func (a *Analyzer) passesVolumeFilters(item ItemData, opts FilterOptions) bool {
-	// Volume filters - both buy and sell volumes must individually meet the threshold
-	// Volume filters - both buy and sell volumes must individually meet the threshold
-	if opts.Volume20mMin != nil {
-		// Both buy and sell volumes must be present and >= threshold
-		if item.InstaBuyVolume20m == nil || item.InstaSellVolume20m == nil {
+	if opts.Volume20mMin != nil && !passesVolumeAction(opts.VolumeAction, item.InstaBuyVolume20m, item.InstaSellVolume20m, float64(*opts.Volume20mMin)) {
+		return false
+	}
+	if opts.Volume1hMin != nil && !passesVolumeAction(opts.VolumeAction, item.InstaBuyVolume1h, item.InstaSellVolume1h, float64(*opts.Volume1hMin)) {
+		return false
+	}
+	if opts.Volume24hMin != nil && !passesVolumeAction(opts.VolumeAction, item.InstaBuyVolume24h, item.InstaSellVolume24h, float64(*opts.Volume24hMin)) {
+		return false
+	}
+
+	if !passesVolumeFilterMode(opts.VolumeFilterMode, opts.BuyVolume1hMin, opts.SellVolume1hMin, item.InstaBuyVolume1h, item.InstaSellVolume1h) {
+		return false
+	}
+	if !passesVolumeFilterMode(opts.VolumeFilterMode, opts.BuyVolume24hMin, opts.SellVolume24hMin, item.InstaBuyVolume24h, item.InstaSellVolume24h) {
+		return false
+	}
+
+	if !passesVolumeBound(item.InstaBuyVolume20m, opts.InstaBuyVolume20mMin, opts.InstaBuyVolume20mMax) {
+		return false
+	}
+	if !passesVolumeBound(item.InstaSellVolume20m, opts.InstaSellVolume20mMin, opts.InstaSellVolume20mMax) {
+		return false
+	}
+	if !passesVolumeBound(item.InstaBuyVolume1h, opts.InstaBuyVolume1hMin, opts.InstaBuyVolume1hMax) {
+		return false
+	}
+	if !passesVolumeBound(item.InstaSellVolume1h, opts.InstaSellVolume1hMin, opts.InstaSellVolume1hMax) {
+		return false
+	}
+	if !passesVolumeBound(item.InstaBuyVolume24h, opts.InstaBuyVolume24hMin, opts.InstaBuyVolume24hMax) {
+		return false
+	}
+	if !passesVolumeBound(item.InstaSellVolume24h, opts.InstaSellVolume24hMin, opts.InstaSellVolume24hMax) {
+		return false
+	}
+
+	if opts.RequirePivotBreak != nil && *opts.RequirePivotBreak && !item.PivotBreakBuy {
+		return false
+	}
+
+	if opts.ATRPctMax != nil {
+		if item.ATR1h == nil || item.InstaSellPrice == nil || *item.InstaSellPrice <= 0 {
+			return false
+		}
+		if *item.ATR1h/float64(*item.InstaSellPrice) > *opts.ATRPctMax {
 			return false
 		}
+	}
 
-		thresholdFloat := float64(*opts.Volume20mMin)
-		if *item.InstaBuyVolume20m+*item.InstaSellVolume20m < thresholdFloat {
+	if opts.MaxATRPctOfPrice != nil {
+		if item.AvgTrueRange1h == nil || item.InstaBuyPrice == nil || *item.InstaBuyPrice <= 0 {
+			return false
+		}
+		if *item.AvgTrueRange1h/float64(*item.InstaBuyPrice) > *opts.MaxATRPctOfPrice {
 			return false
 		}
 	}
 
-	if opts.Volume1hMin != nil {
-		// Both buy and sell volumes must be present and >= threshold
-		if item.InstaBuyVolume1h == nil || item.InstaSellVolume1h == nil {
+	if opts.MarginStddevMax != nil {
+		if item.MarginStddev24h == nil || *item.MarginStddev24h > *opts.MarginStddevMax {
 			return false
 		}
+	}
 
-		thresholdFloat := float64(*opts.Volume1hMin)
-		if *item.InstaBuyVolume1h+*item.InstaSellVolume1h < thresholdFloat {
+	if opts.MaxPriceStdDevPct != nil {
+		if item.InstaBuyPriceStats1h == nil || item.InstaBuyPriceStats1h.Mean <= 0 {
+			return false
+		}
+		if item.InstaBuyPriceStats1h.StdDev/item.InstaBuyPriceStats1h.Mean > *opts.MaxPriceStdDevPct {
 			return false
 		}
 	}
 
-	if opts.Volume24hMin != nil {
-		// Both buy and sell volumes must be present and >= threshold
-		if item.InstaBuyVolume24h == nil || item.InstaSellVolume24h == nil {
+	if opts.OnlyOversold != nil && *opts.OnlyOversold {
+		if item.InstaBuyReversalSignal == nil || *item.InstaBuyReversalSignal != "oversold" {
+			return false
+		}
+	}
+
+	if opts.OnlyOverbought != nil && *opts.OnlyOverbought {
+		if item.InstaBuyReversalSignal == nil || *item.InstaBuyReversalSignal != "overbought" {
+			return false
+		}
+	}
+
+	if opts.PriceEMADeviationMax != nil {
+		if item.PriceEMA24h == nil || item.InstaSellPrice == nil || *item.PriceEMA24h <= 0 {
+			return false
+		}
+		deviation := math.Abs(float64(*item.InstaSellPrice)-*item.PriceEMA24h) / *item.PriceEMA24h
+		if deviation > *opts.PriceEMADeviationMax {
 			return false
 		}
+	}
+
+	return true
+}
 
-		thresholdFloat := float64(*opts.Volume24hMin)
-		if *item.InstaBuyVolume24h+*item.InstaSellVolume24h < thresholdFloat {
+// passesVolumeAction evaluates a legacy combined-volume threshold against
+// buyVol/sellVol according to action, defaulting to VolumeActionCombined
+// (buy+sell summed) when action is empty.
+func passesVolumeAction(action VolumeAction, buyVol, sellVol *float64, threshold float64) bool {
+	switch action {
+	case VolumeActionBuy:
+		return buyVol != nil && *buyVol >= threshold
+	case VolumeActionSell:
+		return sellVol != nil && *sellVol >= threshold
+	case VolumeActionEither:
+		return (buyVol != nil && *buyVol >= threshold) || (sellVol != nil && *sellVol >= threshold)
+	case VolumeActionBoth:
+		return buyVol != nil && sellVol != nil && *buyVol >= threshold && *sellVol >= threshold
+	case VolumeActionCombined, "":
+		fallthrough
+	default:
+		if buyVol == nil || sellVol == nil {
 			return false
 		}
+		return *buyVol+*sellVol >= threshold
+	}
+}
+
+// passesVolumeFilterMode evaluates buyMin/sellMin against buyVol/sellVol
+// according to mode (see VolumeFilterMode). VolumeFilterTotal (the default,
+// including an empty mode) passes unconditionally -- these per-side fields
+// aren't in effect at all until a caller opts into a mode.
+func passesVolumeFilterMode(mode VolumeFilterMode, buyMin, sellMin *int, buyVol, sellVol *float64) bool {
+	if mode == "" || mode == VolumeFilterTotal {
+		return true
+	}
+	if buyMin == nil && sellMin == nil {
+		return true
+	}
+
+	buyOK := buyMin == nil || (buyVol != nil && *buyVol >= float64(*buyMin))
+	sellOK := sellMin == nil || (sellVol != nil && *sellVol >= float64(*sellMin))
+
+	switch mode {
+	case VolumeFilterEither:
+		return buyOK || sellOK
+	case VolumeFilterMinOfBothSides:
+		buyThreshold, sellThreshold := 0.0, 0.0
+		if buyMin != nil {
+			buyThreshold = float64(*buyMin)
+		}
+		if sellMin != nil {
+			sellThreshold = float64(*sellMin)
+		}
+		minThreshold := buyThreshold
+		if buyMin != nil && sellMin != nil {
+			minThreshold = math.Min(buyThreshold, sellThreshold)
+		} else if sellMin != nil {
+			minThreshold = sellThreshold
+		}
+
+		buyV, sellV := 0.0, 0.0
+		if buyVol != nil {
+			buyV = *buyVol
+		}
+		if sellVol != nil {
+			sellV = *sellVol
+		}
+		return math.Min(buyV, sellV) >= minThreshold
+	default: // VolumeFilterBoth
+		return buyOK && sellOK
 	}
+}
 
+// passesVolumeBound checks an optional per-side Min/Max pair against vol,
+// treating a missing vol as failing whichever bound is set (consistent with
+// the rest of passesVolumeFilters, which requires volume data to be loaded
+// before any volume threshold can pass).
+func passesVolumeBound(vol *float64, min, max *float64) bool {
+	if min == nil && max == nil {
+		return true
+	}
+	if vol == nil {
+		return false
+	}
+	if min != nil && *vol < *min {
+		return false
+	}
+	if max != nil && *vol > *max {
+		return false
+	}
 	return true
 }
 
+// applyPivotSignal computes pivot-breakout signals for item from its stored
+// 24h insta-sell price history and sets PivotBreakBuy/PivotBounceSell. Items
+// with no insta-sell price or not enough history get neither signal.
+func (a *Analyzer) applyPivotSignal(item *ItemData, pivotLength int, breakRatio float64) {
+	if item.InstaSellPrice == nil {
+		return
+	}
+
+	points := a.store.GetSeries(item.ItemID, store.Interval24h, time.Time{})
+	if len(points) == 0 {
+		return
+	}
+
+	prices := make([]float64, len(points))
+	for i, p := range points {
+		prices[i] = float64(p.InstaSellPrice)
+	}
+
+	item.PivotBreakBuy, item.PivotBounceSell = ClassifyPivotBreak(prices, float64(*item.InstaSellPrice), pivotLength, breakRatio)
+}
+
+// ewoFastWindow/ewoSlowWindow are the Elliott-Wave Oscillator's fast/slow
+// SMA windows over 5m mid-price buckets; ewoFlipLookback is how many of the
+// most recent 5m buckets EWOSignalFlips1h scans for sign changes (12 * 5m =
+// 1h).
+const (
+	ewoFastWindow   = 3
+	ewoSlowWindow   = 19
+	ewoFlipLookback = 12
+)
+
+// applyVolatilitySignal computes item's streaming-indicator volatility
+// columns (ATR1h, PriceEMA24h, MarginStddev24h, EWO5m) from stored price
+// history, replaying each tick through a fresh indicator.ATR/EMA/SMA/EWO
+// rather than recomputing over a flat slice. Items with no stored history
+// get no signal; ATR1h and MarginStddev24h need at least two ticks to
+// produce a true range, so a single-tick history leaves them unset too.
+func (a *Analyzer) applyVolatilitySignal(item *ItemData) {
+	hourly := a.store.GetSeries(item.ItemID, store.Interval1h, time.Time{})
+	if len(hourly) >= 2 {
+		atr := indicator.NewATR(14)
+		var last float64
+		for _, p := range hourly {
+			last = atr.UpdateHLC(float64(p.InstaBuyPrice), float64(p.InstaSellPrice), float64(p.InstaSellPrice))
+		}
+		if last > 0 {
+			item.ATR1h = &last
+		}
+	}
+
+	if item.ATR1h != nil && item.InstaSellPrice != nil && *item.InstaSellPrice > 0 {
+		pct := *item.ATR1h / float64(*item.InstaSellPrice)
+		item.ATR1hPct = &pct
+	}
+	if item.ATR24h != nil && item.InstaSellPrice != nil && *item.InstaSellPrice > 0 {
+		pct := *item.ATR24h / float64(*item.InstaSellPrice)
+		item.ATR24hPct = &pct
+	}
+
+	fiveMin := a.store.GetSeries(item.ItemID, store.Interval5m, time.Time{})
+	if len(fiveMin) > 0 {
+		ewo := indicator.NewEWO(ewoFastWindow, ewoSlowWindow)
+		var last float64
+		for _, p := range fiveMin {
+			mid := (float64(p.InstaBuyPrice) + float64(p.InstaSellPrice)) / 2
+			last = ewo.Update(mid)
+		}
+		item.EWO5m = &last
+		flips := ewo.SignFlips(ewoFlipLookback)
+		item.EWOSignalFlips1h = &flips
+	}
+
+	daily := a.store.GetSeries(item.ItemID, store.Interval24h, time.Time{})
+	if len(daily) == 0 {
+		return
+	}
+
+	ema := indicator.NewEMA(24)
+	var margins []float64
+	var lastEMA float64
+	for _, p := range daily {
+		lastEMA = ema.Update(float64(p.InstaSellPrice))
+		margins = append(margins, float64(p.InstaBuyPrice-p.InstaSellPrice))
+	}
+	item.PriceEMA24h = &lastEMA
+
+	if len(margins) >= 2 {
+		stddev := stddev(margins)
+		item.MarginStddev24h = &stddev
+	}
+
+	// Refine ATR24h (originally set from ClassifyTrendATR during volume
+	// loading) with the streaming ATR's read over the same window, if we
+	// have enough history to trust it over the coarser flat-array pass.
+	if len(daily) >= 2 {
+		atr := indicator.NewATR(14)
+		var last float64
+		for _, p := range daily {
+			last = atr.UpdateHLC(float64(p.InstaBuyPrice), float64(p.InstaSellPrice), float64(p.InstaSellPrice))
+		}
+		if last > 0 {
+			item.ATR24h = &last
+		}
+	}
+}
+
+// stddev returns the population standard deviation of values.
+func stddev(values []float64) float64 {
+	mean := average(values)
+	var sumSquaredDev float64
+	for _, v := range values {
+		diff := v - mean
+		sumSquaredDev += diff * diff
+	}
+	return math.Sqrt(sumSquaredDev / float64(len(values)))
+}
+
+// PassesFilter reports whether item would pass opts under ApplyFilter's
+// per-item criteria (prices, margins, volume minimums, members, staleness
+// and name); opts.Expr is not evaluated. Exposed so packages that replay historical data
+// (like osrs/backtest) can reuse the exact same filter decisions without
+// needing a live Analyzer.
+func PassesFilter(item ItemData, opts FilterOptions) bool {
+	return (&Analyzer{}).passesFilter(item, opts)
+}
+
+// EnsurePCAEmbedding returns the analyzer's cached PCA embedding over its
+// loaded items, recomputing it if missing or older than pca.DefaultMaxAge.
+func (a *Analyzer) EnsurePCAEmbedding() *pca.Embedding {
+	if a.pcaEmbedding.Stale(pca.DefaultMaxAge) {
+		itemIDs := make([]int, len(a.items))
+		vectors := make([][]float64, len(a.items))
+		for i, item := range a.items {
+			itemIDs[i] = item.ItemID
+			vectors[i] = featureVector(item)
+		}
+		a.pcaEmbedding = pca.Compute(itemIDs, vectors, pca.DefaultDimensions)
+	}
+	return a.pcaEmbedding
+}
+
+// featureVector builds item's raw feature row, in the fixed column order
+// standardize/pca.Compute assume: margin_pct, total 1h volume, total 24h
+// volume, 1h price trend (-1 down, 0 flat/unknown, 1 up), buy limit. Items
+// lack a stored high-alch value at this layer, so that column from the
+// original proposal is omitted rather than faked. Lives here rather than in
+// pca so that package stays unaware of osrs.ItemData (see EnsurePCAEmbedding).
+func featureVector(item ItemData) []float64 {
+	var volume1h, volume24h float64
+	if item.InstaBuyVolume1h != nil {
+		volume1h += *item.InstaBuyVolume1h
+	}
+	if item.InstaSellVolume1h != nil {
+		volume1h += *item.InstaSellVolume1h
+	}
+	if item.InstaBuyVolume24h != nil {
+		volume24h += *item.InstaBuyVolume24h
+	}
+	if item.InstaSellVolume24h != nil {
+		volume24h += *item.InstaSellVolume24h
+	}
+
+	return []float64{
+		item.MarginPct,
+		volume1h,
+		volume24h,
+		encodeTrend(item.InstaBuyPriceTrend1h),
+		float64(item.BuyLimit),
+	}
+}
+
+func encodeTrend(trend *string) float64 {
+	if trend == nil {
+		return 0
+	}
+	switch *trend {
+	case "up":
+		return 1
+	case "down":
+		return -1
+	default:
+		return 0
+	}
+}
+
+// applySimilarItems sets item.SimilarItems to its nearest neighbors in the
+// analyzer's PCA embedding, for jobs that want to surface substitutes.
+func (a *Analyzer) applySimilarItems(item *ItemData, embedding *pca.Embedding) {
+	if embedding == nil {
+		return
+	}
+	item.SimilarItems = embedding.FindSimilar(item.ItemID, defaultSimilarItemCount)
+}
+
+// diversifyBySimilarity greedily drops items that are among a previously
+// kept item's nearest PCA neighbors, so the final list isn't dominated by
+// near-identical items (e.g. ten different runes). items is assumed to
+// already be sorted by the caller's preferred order; earlier items win.
+func diversifyBySimilarity(items []ItemData, embedding *pca.Embedding, similarCount int) []ItemData {
+	if embedding == nil {
+		return items
+	}
+
+	excluded := make(map[int]bool)
+	kept := make([]ItemData, 0, len(items))
+	for _, item := range items {
+		if excluded[item.ItemID] {
+			continue
+		}
+		kept = append(kept, item)
+		for _, similarID := range embedding.FindSimilar(item.ItemID, similarCount) {
+			excluded[similarID] = true
+		}
+	}
+	return kept
+}
+
 // passesFilter checks if an item passes all filter criteria
 func (a *Analyzer) passesFilter(item ItemData, opts FilterOptions) bool {
 	// Buy limit filters
@@ -484,6 +1066,12 @@ func (a *Analyzer) sortItems(items []ItemData, sortBy string, desc bool) {
 		case "avg_margin_gp_24h":
 			less = a.compareFloat64Ptr(items[i].AvgMarginGP24h, items[j].AvgMarginGP24h)
 
+		// Volatility/stability metrics (see applyVolatilitySignal)
+		case "atr_1h":
+			less = a.compareFloat64Ptr(items[i].ATR1h, items[j].ATR1h)
+		case "margin_stddev_24h":
+			less = a.compareFloat64Ptr(items[i].MarginStddev24h, items[j].MarginStddev24h)
+
 		default:
 			// Default to sorting by margin_gp
 			less = items[i].MarginGP < items[j].MarginGP
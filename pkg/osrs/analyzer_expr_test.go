@@ -0,0 +1,47 @@
+package osrs
+
+import "testing"
+
+func TestApplyFilterWithExpr(t *testing.T) {
+	analyzer := &Analyzer{
+		items: []ItemData{
+			{ItemID: 1, Name: "Cheap Item", InstaBuyPrice: intPtr(105), InstaSellPrice: intPtr(100)},
+			{ItemID: 2, Name: "Pricey Item", InstaBuyPrice: intPtr(2005), InstaSellPrice: intPtr(2000)},
+		},
+	}
+
+	items, err := analyzer.ApplyFilter(FilterOptions{Expr: "price > 1000"}, false)
+	if err != nil {
+		t.Fatalf("ApplyFilter returned error: %v", err)
+	}
+	if len(items) != 1 || items[0].ItemID != 2 {
+		t.Fatalf("expected only the item priced over 1000, got %+v", items)
+	}
+}
+
+func TestApplyFilterWithExprCombinesWithScalarFilters(t *testing.T) {
+	analyzer := &Analyzer{
+		items: []ItemData{
+			{ItemID: 1, Name: "A", InstaBuyPrice: intPtr(105), InstaSellPrice: intPtr(100)},
+			{ItemID: 2, Name: "B", InstaBuyPrice: intPtr(205), InstaSellPrice: intPtr(200)},
+		},
+	}
+
+	opts := FilterOptions{InstaSellPriceMin: intPtr(150), Expr: "margin >= 5"}
+	items, err := analyzer.ApplyFilter(opts, false)
+	if err != nil {
+		t.Fatalf("ApplyFilter returned error: %v", err)
+	}
+	if len(items) != 1 || items[0].ItemID != 2 {
+		t.Fatalf("expected item 2 to survive both the price-min and expr filters, got %+v", items)
+	}
+}
+
+func TestApplyFilterRejectsInvalidExpr(t *testing.T) {
+	analyzer := &Analyzer{
+		items: []ItemData{{ItemID: 1, Name: "A", InstaSellPrice: intPtr(100)}},
+	}
+	if _, err := analyzer.ApplyFilter(FilterOptions{Expr: "price >> 100"}, false); err == nil {
+		t.Error("expected an error for an invalid filter expression")
+	}
+}
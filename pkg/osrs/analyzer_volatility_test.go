@@ -0,0 +1,170 @@
+package osrs
+
+import (
+	"testing"
+	"time"
+
+	"osrs-flipping/pkg/osrs/store"
+)
+
+func TestPassesVolumeFilters_ATRPctMax(t *testing.T) {
+	analyzer := &Analyzer{}
+
+	item := ItemData{
+		ItemID:         1,
+		Name:           "Test Item",
+		InstaSellPrice: intPtr(1000),
+		ATR1h:          float64Ptr(50.0), // 5% of insta sell price
+	}
+
+	t.Run("rejects an item whose 1h ATR exceeds the percentage cap", func(t *testing.T) {
+		opts := FilterOptions{ATRPctMax: float64Ptr(0.02)}
+		if analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected failure: ATR is 5% of price, above the 2% cap")
+		}
+	})
+
+	t.Run("accepts an item whose 1h ATR is within the percentage cap", func(t *testing.T) {
+		opts := FilterOptions{ATRPctMax: float64Ptr(0.1)}
+		if !analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected success: ATR is 5% of price, within the 10% cap")
+		}
+	})
+
+	t.Run("rejects an item with no ATR1h computed yet", func(t *testing.T) {
+		opts := FilterOptions{ATRPctMax: float64Ptr(0.1)}
+		noATR := ItemData{ItemID: 1, Name: "Test Item", InstaSellPrice: intPtr(1000)}
+		if analyzer.passesVolumeFilters(noATR, opts) {
+			t.Error("expected failure: ATR1h is nil")
+		}
+	})
+}
+
+func TestPassesVolumeFilters_MaxATRPctOfPrice(t *testing.T) {
+	analyzer := &Analyzer{}
+
+	item := ItemData{
+		ItemID:         1,
+		Name:           "Test Item",
+		InstaBuyPrice:  intPtr(1000),
+		AvgTrueRange1h: float64Ptr(50.0), // 5% of insta buy price
+	}
+
+	t.Run("rejects an item whose bucketed 1h ATR exceeds the percentage cap", func(t *testing.T) {
+		opts := FilterOptions{MaxATRPctOfPrice: float64Ptr(0.02)}
+		if analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected failure: ATR is 5% of price, above the 2% cap")
+		}
+	})
+
+	t.Run("accepts an item whose bucketed 1h ATR is within the percentage cap", func(t *testing.T) {
+		opts := FilterOptions{MaxATRPctOfPrice: float64Ptr(0.1)}
+		if !analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected success: ATR is 5% of price, within the 10% cap")
+		}
+	})
+
+	t.Run("rejects an item with no AvgTrueRange1h computed yet", func(t *testing.T) {
+		opts := FilterOptions{MaxATRPctOfPrice: float64Ptr(0.1)}
+		noATR := ItemData{ItemID: 1, Name: "Test Item", InstaBuyPrice: intPtr(1000)}
+		if analyzer.passesVolumeFilters(noATR, opts) {
+			t.Error("expected failure: AvgTrueRange1h is nil")
+		}
+	})
+}
+
+func TestPassesVolumeFilters_MarginStddevMax(t *testing.T) {
+	analyzer := &Analyzer{}
+
+	item := ItemData{
+		ItemID:          1,
+		Name:            "Test Item",
+		MarginStddev24h: float64Ptr(25.0),
+	}
+
+	t.Run("rejects an item with a noisier margin than the cap", func(t *testing.T) {
+		opts := FilterOptions{MarginStddevMax: float64Ptr(10.0)}
+		if analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected failure: margin stddev exceeds the cap")
+		}
+	})
+
+	t.Run("accepts an item with a stabler margin than the cap", func(t *testing.T) {
+		opts := FilterOptions{MarginStddevMax: float64Ptr(50.0)}
+		if !analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected success: margin stddev is within the cap")
+		}
+	})
+}
+
+func TestPassesVolumeFilters_PriceEMADeviationMax(t *testing.T) {
+	analyzer := &Analyzer{}
+
+	item := ItemData{
+		ItemID:         1,
+		Name:           "Test Item",
+		InstaSellPrice: intPtr(1100),
+		PriceEMA24h:    float64Ptr(1000.0), // 10% above the 24h EMA
+	}
+
+	t.Run("rejects an item trading further from its EMA than the cap", func(t *testing.T) {
+		opts := FilterOptions{PriceEMADeviationMax: float64Ptr(0.05)}
+		if analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected failure: price is 10% off its EMA, above the 5% cap")
+		}
+	})
+
+	t.Run("accepts an item trading within the cap of its EMA", func(t *testing.T) {
+		opts := FilterOptions{PriceEMADeviationMax: float64Ptr(0.2)}
+		if !analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected success: price is 10% off its EMA, within the 20% cap")
+		}
+	})
+}
+
+func TestApplyVolatilitySignal(t *testing.T) {
+	s := store.New("", 0, 0)
+	analyzer := &Analyzer{}
+	analyzer.SetStore(s)
+
+	itemID := 1
+	base := time.Now().Add(-time.Hour)
+	for i, price := range []int{100, 102, 98, 105, 101} {
+		ts := base.Add(time.Duration(i) * time.Minute)
+		dp := store.DataPoint{Timestamp: ts, InstaBuyPrice: price + 2, InstaSellPrice: price}
+		s.Append(itemID, store.Interval1h, dp)
+		s.Append(itemID, store.Interval24h, dp)
+	}
+
+	for i, price := range []int{100, 102, 98, 105, 101} {
+		ts := base.Add(time.Duration(i) * 5 * time.Minute)
+		s.Append(itemID, store.Interval5m, store.DataPoint{Timestamp: ts, InstaBuyPrice: price + 2, InstaSellPrice: price})
+	}
+
+	item := ItemData{ItemID: itemID, Name: "Test Item", InstaSellPrice: intPtr(101)}
+	analyzer.applyVolatilitySignal(&item)
+
+	if item.ATR1h == nil {
+		t.Error("expected ATR1h to be computed once 1h series ticks exist")
+	}
+	if item.PriceEMA24h == nil {
+		t.Error("expected PriceEMA24h to be computed once 24h series ticks exist")
+	}
+	if item.MarginStddev24h == nil {
+		t.Error("expected MarginStddev24h to be computed once 24h series ticks exist")
+	}
+	if item.ATR24h == nil {
+		t.Error("expected ATR24h to be refined from the streaming ATR once >=2 24h ticks exist")
+	}
+	if item.ATR1hPct == nil {
+		t.Error("expected ATR1hPct to be derived from ATR1h and InstaSellPrice")
+	} else if want := *item.ATR1h / 101.0; *item.ATR1hPct != want {
+		t.Errorf("ATR1hPct = %v, want %v", *item.ATR1hPct, want)
+	}
+	if item.EWO5m == nil {
+		t.Error("expected EWO5m to be computed once 5m series ticks exist")
+	}
+	if item.EWOSignalFlips1h == nil {
+		t.Error("expected EWOSignalFlips1h to be computed once 5m series ticks exist")
+	}
+}
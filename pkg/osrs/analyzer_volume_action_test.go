@@ -0,0 +1,87 @@
+package osrs
+
+import "testing"
+
+func TestPassesVolumeFilters_VolumeAction(t *testing.T) {
+	analyzer := &Analyzer{}
+
+	item := ItemData{
+		ItemID:            1,
+		Name:              "Test Item",
+		InstaBuyVolume1h:  float64Ptr(10.0),
+		InstaSellVolume1h: float64Ptr(1.0),
+	}
+
+	tests := []struct {
+		name           string
+		action         VolumeAction
+		expectedResult bool
+	}{
+		{"empty defaults to combined sum", "", true},
+		{"combined sums both sides", VolumeActionCombined, true},
+		{"buy-only passes on buy side alone", VolumeActionBuy, true},
+		{"sell-only fails since sell side is below threshold", VolumeActionSell, false},
+		{"either passes since buy side meets threshold", VolumeActionEither, true},
+		{"both fails since sell side doesn't meet threshold", VolumeActionBoth, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := FilterOptions{Volume1hMin: intPtr(5), VolumeAction: tt.action}
+			if result := analyzer.passesVolumeFilters(item, opts); result != tt.expectedResult {
+				t.Errorf("passesVolumeFilters() with action %q = %v, want %v", tt.action, result, tt.expectedResult)
+			}
+		})
+	}
+}
+
+func TestPassesVolumeFilters_PerSideBounds(t *testing.T) {
+	analyzer := &Analyzer{}
+
+	item := ItemData{
+		ItemID:             1,
+		Name:               "Test Item",
+		InstaBuyVolume1h:   float64Ptr(10.0),
+		InstaSellVolume1h:  float64Ptr(2.0),
+		InstaBuyVolume24h:  float64Ptr(100.0),
+		InstaSellVolume24h: float64Ptr(1.0),
+	}
+
+	t.Run("InstaSellVolume1hMin rejects an item with too little sell-side volume", func(t *testing.T) {
+		opts := FilterOptions{InstaSellVolume1hMin: float64Ptr(5.0)}
+		if analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected failure: sell-side 1h volume is below the minimum")
+		}
+	})
+
+	t.Run("InstaBuyVolume1hMin accepts an item with enough buy-side volume", func(t *testing.T) {
+		opts := FilterOptions{InstaBuyVolume1hMin: float64Ptr(5.0)}
+		if !analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected success: buy-side 1h volume meets the minimum")
+		}
+	})
+
+	t.Run("InstaBuyVolume24hMax rejects an item over the cap", func(t *testing.T) {
+		opts := FilterOptions{InstaBuyVolume24hMax: float64Ptr(50.0)}
+		if analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected failure: buy-side 24h volume exceeds the max")
+		}
+	})
+
+	t.Run("missing volume data fails a set bound", func(t *testing.T) {
+		opts := FilterOptions{InstaSellVolume20mMin: float64Ptr(1.0)}
+		if analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected failure: item has no InstaSellVolume20m data")
+		}
+	})
+
+	t.Run("per-side bounds and combined thresholds both apply", func(t *testing.T) {
+		opts := FilterOptions{
+			Volume1hMin:          intPtr(5),
+			InstaSellVolume1hMin: float64Ptr(5.0),
+		}
+		if analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected failure: combined sum passes but the explicit sell-side minimum doesn't")
+		}
+	})
+}
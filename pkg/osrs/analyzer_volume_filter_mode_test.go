@@ -0,0 +1,77 @@
+package osrs
+
+import "testing"
+
+func TestPassesVolumeFilters_VolumeFilterMode(t *testing.T) {
+	analyzer := &Analyzer{}
+
+	item := ItemData{
+		ItemID:            1,
+		Name:              "Test Item",
+		InstaBuyVolume1h:  float64Ptr(10.0),
+		InstaSellVolume1h: float64Ptr(1.0),
+	}
+
+	tests := []struct {
+		name           string
+		mode           VolumeFilterMode
+		expectedResult bool
+	}{
+		{"empty defaults to total, ignoring the per-side thresholds", "", true},
+		{"total ignores the per-side thresholds", VolumeFilterTotal, true},
+		{"both fails since sell side doesn't meet its own threshold", VolumeFilterBoth, false},
+		{"either passes since buy side meets its own threshold", VolumeFilterEither, true},
+		{"min_of_both_sides fails since the weaker side misses the weaker bar", VolumeFilterMinOfBothSides, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := FilterOptions{
+				VolumeFilterMode: tt.mode,
+				BuyVolume1hMin:   intPtr(5),
+				SellVolume1hMin:  intPtr(5),
+			}
+			if result := analyzer.passesVolumeFilters(item, opts); result != tt.expectedResult {
+				t.Errorf("passesVolumeFilters() with mode %q = %v, want %v", tt.mode, result, tt.expectedResult)
+			}
+		})
+	}
+
+	t.Run("min_of_both_sides passes when the weaker side still clears the weaker threshold", func(t *testing.T) {
+		opts := FilterOptions{
+			VolumeFilterMode: VolumeFilterMinOfBothSides,
+			BuyVolume1hMin:   intPtr(20), // buy side (10.0) misses this
+			SellVolume1hMin:  intPtr(1),  // but sell side (1.0) clears the lower bar
+		}
+		if !analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected success: min(buyVol, sellVol) clears min(buyMin, sellMin)")
+		}
+	})
+
+	t.Run("24h thresholds are evaluated independently of 1h", func(t *testing.T) {
+		item24h := ItemData{
+			ItemID:             1,
+			Name:               "Test Item",
+			InstaBuyVolume24h:  float64Ptr(100.0),
+			InstaSellVolume24h: float64Ptr(2.0),
+		}
+		opts := FilterOptions{
+			VolumeFilterMode: VolumeFilterBoth,
+			BuyVolume24hMin:  intPtr(50),
+			SellVolume24hMin: intPtr(10),
+		}
+		if analyzer.passesVolumeFilters(item24h, opts) {
+			t.Error("expected failure: 24h sell-side volume is below its own threshold")
+		}
+	})
+
+	t.Run("a threshold set on only one side is satisfied by that side alone", func(t *testing.T) {
+		opts := FilterOptions{
+			VolumeFilterMode: VolumeFilterBoth,
+			BuyVolume1hMin:   intPtr(5),
+		}
+		if !analyzer.passesVolumeFilters(item, opts) {
+			t.Error("expected success: SellVolume1hMin is unset, so only BuyVolume1hMin must be cleared")
+		}
+	})
+}
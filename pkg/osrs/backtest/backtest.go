@@ -0,0 +1,235 @@
+// Package backtest replays an item's stored price history against a job's
+// filter configuration to estimate how that job would have performed had it
+// run repeatedly over a past window, instead of users tuning filters (margin
+// thresholds, volume minimums) by guesswork.
+//
+// It reuses the same rolling history the Analyzer already collects via
+// pkg/osrs/store, so a backtest only covers whatever window that store has
+// retained -- it is not a full historical database query.
+package backtest
+
+import (
+	"time"
+
+	"osrs-flipping/pkg/osrs"
+	"osrs-flipping/pkg/osrs/store"
+)
+
+// ItemResult is the outcome of one simulated flip: buy at the insta-sell
+// price observed when the item passed the job's filters, then watch forward
+// for the insta-buy price to reach that same tick's target within Horizon.
+type ItemResult struct {
+	ItemID          int
+	Name            string
+	EntryTime       time.Time
+	EntryPrice      int
+	TargetSellPrice int
+
+	Filled            bool
+	FillTime          time.Time
+	TimeToFill        time.Duration
+	TheoreticalProfit int // GP per unit, EntryPrice to TargetSellPrice, only set when Filled
+	WorstCaseLoss     int // GP per unit lost if forced to sell at the lowest price seen before Horizon elapsed, only set when !Filled
+}
+
+// Report summarizes a backtest run across every sampled tick that passed the
+// job's filters.
+type Report struct {
+	JobName               string
+	From, To              time.Time
+	SampleIntervalMinutes int
+	Horizon               time.Duration
+
+	Samples []ItemResult
+
+	TotalTheoreticalProfit int
+	HitRate                float64 // fraction of Samples that were Filled
+	AvgTimeToFill          time.Duration
+	WorstCaseLoss          int // largest single WorstCaseLoss across unfilled samples
+}
+
+// Config controls one backtest run.
+type Config struct {
+	JobName  string
+	Filters  osrs.FilterOptions
+	MaxItems int
+
+	From, To              time.Time
+	SampleIntervalMinutes int           // how often the job would have run; defaults to 60
+	Horizon               time.Duration // how long a fill has to happen; defaults to 6h
+}
+
+const (
+	defaultSampleIntervalMinutes = 60
+	defaultHorizon               = 6 * time.Hour
+)
+
+// seriesSource is the slice of *osrs.Analyzer / *store.Store that Run needs
+// to replay history. Accepting the interface rather than a concrete store
+// lets callers pass either a bare Store (as in tests) or a live Analyzer.
+type seriesSource interface {
+	GetSeries(itemID int, interval store.Interval, since time.Time) []store.DataPoint
+}
+
+// Run replays items' stored 5m and 1h price history between cfg.From and
+// cfg.To at cfg.SampleIntervalMinutes cadence, checking cfg.Filters against
+// the price snapshot at each sample. Only the price and margin fields are
+// rebuilt from the past tick; every other criterion, the volume minimums
+// included, is checked against the live item's current values.
+func Run(items []osrs.ItemData, s seriesSource, cfg Config) *Report {
+	interval := cfg.SampleIntervalMinutes
+	if interval <= 0 {
+		interval = defaultSampleIntervalMinutes
+	}
+	horizon := cfg.Horizon
+	if horizon <= 0 {
+		horizon = defaultHorizon
+	}
+
+	report := &Report{
+		JobName:               cfg.JobName,
+		From:                  cfg.From,
+		To:                    cfg.To,
+		SampleIntervalMinutes: interval,
+		Horizon:               horizon,
+	}
+
+	for _, item := range items {
+		series := mergedSeries(s, item.ItemID, cfg.From, cfg.To.Add(horizon))
+		if len(series) == 0 {
+			continue
+		}
+
+		for t := cfg.From; !t.After(cfg.To); t = t.Add(time.Duration(interval) * time.Minute) {
+			snapshot, ok := snapshotAt(item, series, t)
+			if !ok {
+				continue
+			}
+			if !osrs.PassesFilter(snapshot, cfg.Filters) {
+				continue
+			}
+
+			result := simulateFlip(snapshot, series, t, horizon)
+			report.Samples = append(report.Samples, result)
+		}
+
+		if cfg.MaxItems > 0 && len(report.Samples) >= cfg.MaxItems {
+			break
+		}
+	}
+
+	summarize(report)
+	return report
+}
+
+// mergedSeries returns an item's 5m and 1h ticks between from and to,
+// sorted oldest first, so replay has the finest granularity available at
+// every point in the window.
+func mergedSeries(s seriesSource, itemID int, from, to time.Time) []store.DataPoint {
+	var points []store.DataPoint
+	for _, interval := range []store.Interval{store.Interval5m, store.Interval1h} {
+		for _, p := range s.GetSeries(itemID, interval, time.Time{}) {
+			if p.Timestamp.Before(from) || p.Timestamp.After(to) {
+				continue
+			}
+			points = append(points, p)
+		}
+	}
+
+	for i := 1; i < len(points); i++ {
+		for j := i; j > 0 && points[j].Timestamp.Before(points[j-1].Timestamp); j-- {
+			points[j], points[j-1] = points[j-1], points[j]
+		}
+	}
+	return points
+}
+
+// snapshotAt builds the ItemData as it would have looked at time t, using
+// the most recent tick at-or-before t, with the item's static fields (name,
+// buy limit, members flag) carried over from the live item.
+func snapshotAt(item osrs.ItemData, series []store.DataPoint, t time.Time) (osrs.ItemData, bool) {
+	var latest *store.DataPoint
+	for i := range series {
+		if series[i].Timestamp.After(t) {
+			break
+		}
+		latest = &series[i]
+	}
+	if latest == nil {
+		return osrs.ItemData{}, false
+	}
+
+	snapshot := item
+	instaBuy := latest.InstaBuyPrice
+	instaSell := latest.InstaSellPrice
+	snapshot.InstaBuyPrice = &instaBuy
+	snapshot.InstaSellPrice = &instaSell
+	snapshot.MarginGP = instaBuy - instaSell
+	if instaSell > 0 {
+		snapshot.MarginPct = (float64(snapshot.MarginGP) / float64(instaSell)) * 100
+	}
+	return snapshot, true
+}
+
+// simulateFlip estimates the fill outcome for buying at entryTime's
+// insta-sell price and trying to sell at that tick's insta-buy price within
+// horizon.
+func simulateFlip(snapshot osrs.ItemData, series []store.DataPoint, entryTime time.Time, horizon time.Duration) ItemResult {
+	result := ItemResult{
+		ItemID:          snapshot.ItemID,
+		Name:            snapshot.Name,
+		EntryTime:       entryTime,
+		EntryPrice:      *snapshot.InstaSellPrice,
+		TargetSellPrice: *snapshot.InstaBuyPrice,
+	}
+
+	deadline := entryTime.Add(horizon)
+	worstSeen := result.EntryPrice
+
+	for _, p := range series {
+		if !p.Timestamp.After(entryTime) || p.Timestamp.After(deadline) {
+			continue
+		}
+		if p.InstaSellPrice < worstSeen {
+			worstSeen = p.InstaSellPrice
+		}
+		if p.InstaBuyPrice >= result.TargetSellPrice {
+			result.Filled = true
+			result.FillTime = p.Timestamp
+			result.TimeToFill = p.Timestamp.Sub(entryTime)
+			result.TheoreticalProfit = result.TargetSellPrice - result.EntryPrice
+			return result
+		}
+	}
+
+	result.WorstCaseLoss = result.EntryPrice - worstSeen
+	return result
+}
+
+// summarize fills in report's aggregate statistics from its Samples.
+func summarize(report *Report) {
+	if len(report.Samples) == 0 {
+		return
+	}
+
+	var filledCount int
+	var totalTimeToFill time.Duration
+
+	for _, s := range report.Samples {
+		if s.Filled {
+			filledCount++
+			report.TotalTheoreticalProfit += s.TheoreticalProfit
+			totalTimeToFill += s.TimeToFill
+		} else {
+			report.TotalTheoreticalProfit -= s.WorstCaseLoss
+			if s.WorstCaseLoss > report.WorstCaseLoss {
+				report.WorstCaseLoss = s.WorstCaseLoss
+			}
+		}
+	}
+
+	report.HitRate = float64(filledCount) / float64(len(report.Samples))
+	if filledCount > 0 {
+		report.AvgTimeToFill = totalTimeToFill / time.Duration(filledCount)
+	}
+}
@@ -0,0 +1,129 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"osrs-flipping/pkg/osrs"
+	"osrs-flipping/pkg/osrs/store"
+)
+
+func intPtr(v int) *int { return &v }
+
+func buildStoreWithSeries(itemID int, points []store.DataPoint) *store.Store {
+	s := store.New("", 0, 0)
+	for _, p := range points {
+		s.Append(itemID, store.Interval5m, p)
+	}
+	return s
+}
+
+func TestRunFillsWithinHorizon(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := []store.DataPoint{
+		{Timestamp: base, InstaBuyPrice: 110, InstaSellPrice: 100},
+		{Timestamp: base.Add(1 * time.Hour), InstaBuyPrice: 115, InstaSellPrice: 105},
+	}
+	s := buildStoreWithSeries(1, points)
+
+	items := []osrs.ItemData{
+		{ItemID: 1, Name: "Test Item", BuyLimit: 100, InstaBuyPrice: intPtr(110), InstaSellPrice: intPtr(100)},
+	}
+
+	cfg := Config{
+		JobName:               "test-job",
+		From:                  base,
+		To:                    base,
+		SampleIntervalMinutes: 60,
+		Horizon:               2 * time.Hour,
+	}
+
+	report := Run(items, s, cfg)
+	if len(report.Samples) != 1 {
+		t.Fatalf("expected 1 sample, got %d", len(report.Samples))
+	}
+	sample := report.Samples[0]
+	if !sample.Filled {
+		t.Fatalf("expected sample to fill, got unfilled with worst-case loss %d", sample.WorstCaseLoss)
+	}
+	if sample.TheoreticalProfit != 10 {
+		t.Errorf("expected profit of 10, got %d", sample.TheoreticalProfit)
+	}
+	if report.HitRate != 1.0 {
+		t.Errorf("expected hit rate 1.0, got %f", report.HitRate)
+	}
+	if report.TotalTheoreticalProfit != 10 {
+		t.Errorf("expected total profit 10, got %d", report.TotalTheoreticalProfit)
+	}
+}
+
+func TestRunRecordsWorstCaseLossWhenUnfilled(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := []store.DataPoint{
+		{Timestamp: base, InstaBuyPrice: 110, InstaSellPrice: 100},
+		{Timestamp: base.Add(1 * time.Hour), InstaBuyPrice: 105, InstaSellPrice: 90},
+	}
+	s := buildStoreWithSeries(1, points)
+
+	items := []osrs.ItemData{
+		{ItemID: 1, Name: "Test Item", BuyLimit: 100, InstaBuyPrice: intPtr(110), InstaSellPrice: intPtr(100)},
+	}
+
+	cfg := Config{
+		JobName:               "test-job",
+		From:                  base,
+		To:                    base,
+		SampleIntervalMinutes: 60,
+		Horizon:               2 * time.Hour,
+	}
+
+	report := Run(items, s, cfg)
+	if len(report.Samples) != 1 {
+		t.Fatalf("expected 1 sample, got %d", len(report.Samples))
+	}
+	sample := report.Samples[0]
+	if sample.Filled {
+		t.Fatalf("expected sample to not fill since insta-buy price never reached target")
+	}
+	if sample.WorstCaseLoss != 10 {
+		t.Errorf("expected worst-case loss of 10 (100 -> 90), got %d", sample.WorstCaseLoss)
+	}
+}
+
+func TestRunSkipsItemsFailingFilters(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := []store.DataPoint{
+		{Timestamp: base, InstaBuyPrice: 110, InstaSellPrice: 100},
+	}
+	s := buildStoreWithSeries(1, points)
+
+	items := []osrs.ItemData{
+		{ItemID: 1, Name: "Test Item", BuyLimit: 100, InstaBuyPrice: intPtr(110), InstaSellPrice: intPtr(100)},
+	}
+
+	cfg := Config{
+		JobName:               "test-job",
+		Filters:               osrs.FilterOptions{MarginMin: intPtr(1000)},
+		From:                  base,
+		To:                    base,
+		SampleIntervalMinutes: 60,
+		Horizon:               2 * time.Hour,
+	}
+
+	report := Run(items, s, cfg)
+	if len(report.Samples) != 0 {
+		t.Fatalf("expected 0 samples for an item that never meets MarginMin, got %d", len(report.Samples))
+	}
+}
+
+func TestRunNoHistoryProducesNoSamples(t *testing.T) {
+	s := store.New("", 0, 0)
+	items := []osrs.ItemData{
+		{ItemID: 1, Name: "Test Item", BuyLimit: 100, InstaBuyPrice: intPtr(110), InstaSellPrice: intPtr(100)},
+	}
+
+	report := Run(items, s, Config{From: time.Now(), To: time.Now()})
+	if len(report.Samples) != 0 {
+		t.Errorf("expected no samples with an empty store, got %d", len(report.Samples))
+	}
+}
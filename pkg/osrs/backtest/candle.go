@@ -0,0 +1,557 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"osrs-flipping/pkg/osrs"
+)
+
+// geTaxRate is the Grand Exchange's tax on sales, introduced alongside the
+// 2024 tax update. geTaxCapGP is the per-sale cap on that tax regardless of
+// sale price.
+const (
+	geTaxRate  = 0.01
+	geTaxCapGP = 5_000_000
+
+	// buyLimitResetEvery matches the GE's rolling 4-hour buy limit window.
+	buyLimitResetEvery = 4 * time.Hour
+)
+
+// geTax returns the GP withheld when selling quantity units at unitPrice.
+func geTax(unitPrice, quantity int) int {
+	if unitPrice <= 0 || quantity <= 0 {
+		return 0
+	}
+	tax := int(math.Floor(float64(unitPrice*quantity) * geTaxRate))
+	if tax > geTaxCapGP {
+		tax = geTaxCapGP
+	}
+	return tax
+}
+
+// FillModel controls what price a simulated trade actually fills at.
+type FillModel string
+
+const (
+	// FillModelInstant fills buys at a candle's InstaSellPrice and sells at
+	// its InstaBuyPrice, as if every order landed at the top of the book
+	// immediately. This is the default, and the only behavior before
+	// FillModel existed.
+	FillModelInstant FillModel = "instant"
+	// FillModelMidpointSlippage fills around the candle's
+	// (InstaBuyPrice+InstaSellPrice)/2 midpoint, offset by CandleConfig's
+	// SlippagePct against the trader -- buys pay the midpoint plus
+	// slippage, sells receive the midpoint minus slippage -- a more
+	// conservative estimate for illiquid items where instant fills at the
+	// quoted price aren't realistic.
+	FillModelMidpointSlippage FillModel = "midpoint_slippage"
+)
+
+// buyFillPrice and sellFillPrice return what a trade would actually pay or
+// receive at candle c under model, applying slippagePct (e.g. 0.01 for 1%)
+// for FillModelMidpointSlippage.
+func buyFillPrice(c Candle, model FillModel, slippagePct float64) int {
+	if model != FillModelMidpointSlippage {
+		return c.InstaSellPrice
+	}
+	mid := float64(c.InstaBuyPrice+c.InstaSellPrice) / 2
+	return int(mid + mid*slippagePct)
+}
+
+func sellFillPrice(c Candle, model FillModel, slippagePct float64) int {
+	if model != FillModelMidpointSlippage {
+		return c.InstaBuyPrice
+	}
+	mid := float64(c.InstaBuyPrice+c.InstaSellPrice) / 2
+	return int(mid - mid*slippagePct)
+}
+
+// markPrice is what an open position could realistically be unwound for
+// right now -- unlike sellFillPrice, which assumes a limit sell eventually
+// gets filled at InstaBuyPrice, an immediate exit only clears at
+// InstaSellPrice under FillModelInstant.
+func markPrice(c Candle, model FillModel, slippagePct float64) int {
+	if model != FillModelMidpointSlippage {
+		return c.InstaSellPrice
+	}
+	mid := float64(c.InstaBuyPrice+c.InstaSellPrice) / 2
+	return int(mid - mid*slippagePct)
+}
+
+// Candle is one historical tick from the wiki API's /timeseries endpoint.
+type Candle struct {
+	Timestamp       time.Time
+	InstaBuyPrice   int
+	InstaSellPrice  int
+	InstaBuyVolume  int
+	InstaSellVolume int
+}
+
+// CandleSource fetches an item's historical candles at a given resolution
+// ("1h", "6h", or "24h"), letting RunCandleReplay accept either a live
+// *osrs.Client (via TimeseriesClient) or a fixed series in tests.
+type CandleSource interface {
+	GetCandles(ctx context.Context, itemID int, timestep string) ([]Candle, error)
+}
+
+// TimeseriesClient adapts osrs.Client's GetTimeseries to CandleSource.
+type TimeseriesClient struct {
+	Client *osrs.Client
+}
+
+// NewTimeseriesClient wraps client for use as a candle replay's CandleSource.
+func NewTimeseriesClient(client *osrs.Client) *TimeseriesClient {
+	return &TimeseriesClient{Client: client}
+}
+
+// GetCandles fetches and parses itemID's /timeseries data at timestep,
+// oldest first. Ticks with no recorded price on either side are skipped --
+// the wiki API reports them as zero/null rather than omitting the row.
+func (t *TimeseriesClient) GetCandles(ctx context.Context, itemID int, timestep string) ([]Candle, error) {
+	raw, err := t.Client.GetTimeseries(ctx, itemID, timestep)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s candles for item %d: %w", timestep, itemID, err)
+	}
+
+	dataSlice, ok := raw["data"].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	candles := make([]Candle, 0, len(dataSlice))
+	for _, entry := range dataSlice {
+		point, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		ts, _ := point["timestamp"].(float64)
+		var avgHigh, avgLow, highVol, lowVol float64
+		if v, ok := point["avgHighPrice"]; ok && v != nil {
+			avgHigh, _ = v.(float64)
+		}
+		if v, ok := point["avgLowPrice"]; ok && v != nil {
+			avgLow, _ = v.(float64)
+		}
+		if v, ok := point["highPriceVolume"]; ok && v != nil {
+			highVol, _ = v.(float64)
+		}
+		if v, ok := point["lowPriceVolume"]; ok && v != nil {
+			lowVol, _ = v.(float64)
+		}
+		if avgHigh == 0 && avgLow == 0 {
+			continue
+		}
+
+		candles = append(candles, Candle{
+			Timestamp:       time.Unix(int64(ts), 0).UTC(),
+			InstaBuyPrice:   int(avgHigh),
+			InstaSellPrice:  int(avgLow),
+			InstaBuyVolume:  int(highVol),
+			InstaSellVolume: int(lowVol),
+		})
+	}
+
+	sort.Slice(candles, func(i, j int) bool { return candles[i].Timestamp.Before(candles[j].Timestamp) })
+	return candles, nil
+}
+
+// FileCandleSource loads candles from local JSON files instead of the live
+// wiki API, for replaying a fixed, reproducible historical window (e.g. an
+// archived dataset) without depending on network access or the wiki's
+// retention window. Each item's candles live at
+// filepath.Join(Dir, fmt.Sprintf("%d_%s.json", itemID, timestep)), holding
+// the same {"data": [...]} shape the wiki API's /timeseries endpoint
+// returns.
+type FileCandleSource struct {
+	Dir string
+}
+
+// NewFileCandleSource returns a FileCandleSource reading per-item timeseries
+// JSON files out of dir.
+func NewFileCandleSource(dir string) *FileCandleSource {
+	return &FileCandleSource{Dir: dir}
+}
+
+// GetCandles reads and parses itemID's archived timeseries file at
+// timestep, oldest first, mirroring TimeseriesClient.GetCandles' parsing.
+func (f *FileCandleSource) GetCandles(ctx context.Context, itemID int, timestep string) ([]Candle, error) {
+	path := filepath.Join(f.Dir, fmt.Sprintf("%d_%s.json", itemID, timestep))
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading archived candles for item %d: %w", itemID, err)
+	}
+
+	var parsed struct {
+		Data []struct {
+			Timestamp       int64    `json:"timestamp"`
+			AvgHighPrice    *float64 `json:"avgHighPrice"`
+			AvgLowPrice     *float64 `json:"avgLowPrice"`
+			HighPriceVolume *float64 `json:"highPriceVolume"`
+			LowPriceVolume  *float64 `json:"lowPriceVolume"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("parsing archived candles for item %d: %w", itemID, err)
+	}
+
+	candles := make([]Candle, 0, len(parsed.Data))
+	for _, point := range parsed.Data {
+		var avgHigh, avgLow, highVol, lowVol float64
+		if point.AvgHighPrice != nil {
+			avgHigh = *point.AvgHighPrice
+		}
+		if point.AvgLowPrice != nil {
+			avgLow = *point.AvgLowPrice
+		}
+		if point.HighPriceVolume != nil {
+			highVol = *point.HighPriceVolume
+		}
+		if point.LowPriceVolume != nil {
+			lowVol = *point.LowPriceVolume
+		}
+		if avgHigh == 0 && avgLow == 0 {
+			continue
+		}
+
+		candles = append(candles, Candle{
+			Timestamp:       time.Unix(point.Timestamp, 0).UTC(),
+			InstaBuyPrice:   int(avgHigh),
+			InstaSellPrice:  int(avgLow),
+			InstaBuyVolume:  int(highVol),
+			InstaSellVolume: int(lowVol),
+		})
+	}
+
+	sort.Slice(candles, func(i, j int) bool { return candles[i].Timestamp.Before(candles[j].Timestamp) })
+	return candles, nil
+}
+
+// Trade is one simulated buy-limit-constrained flip: buy quantity units at
+// BuyPrice, then either sell them at SellPrice (Open == false) or, if the
+// replay window ended first, mark them open at LastPrice for unrealized PnL.
+type Trade struct {
+	ItemID   int
+	Name     string
+	Quantity int
+
+	BuyTime  time.Time
+	BuyPrice int
+
+	Open      bool
+	SellTime  time.Time
+	SellPrice int
+	TaxPaid   int
+
+	ProfitGP int // realized profit net of tax; unrealized mark-to-market when Open
+}
+
+// EquityPoint is one sample of cumulative realized PnL, ordered by the time
+// each contributing trade closed.
+type EquityPoint struct {
+	Timestamp time.Time
+	EquityGP  int
+}
+
+// ItemPNL breaks BacktestResult's aggregate PnL down per item, for spotting
+// which items in a job's filter actually drove its returns.
+type ItemPNL struct {
+	ItemID             int
+	Name               string
+	ClosedTrades       int
+	RealizedProfitGP   int
+	UnrealizedProfitGP int
+	WinRate            float64 // fraction of this item's closed trades with ProfitGP > 0
+}
+
+// BacktestResult is the outcome of a CandleConfig replay across every item
+// passed to RunCandleReplay.
+type BacktestResult struct {
+	JobName  string
+	Timestep string
+	From, To time.Time
+
+	Trades []Trade
+
+	RealizedProfitGP   int
+	UnrealizedProfitGP int
+	WinRate            float64 // fraction of closed trades with ProfitGP > 0
+	MaxDrawdownGP      int     // largest peak-to-trough drop in cumulative realized PnL
+	SharpeRatio        float64 // mean / stddev of per-trade realized PnL, unannualized
+	GPPerHour          float64 // RealizedProfitGP / hours between From and To
+
+	// PerItem breaks RealizedProfitGP/UnrealizedProfitGP/WinRate down by
+	// item, ordered by descending RealizedProfitGP.
+	PerItem []ItemPNL
+
+	// Equity is cumulative realized PnL ordered by trade close time. It
+	// only reflects closed trades -- open positions at the end of the
+	// window are summarized in UnrealizedProfitGP instead, since they
+	// don't have a close time to place them on the curve.
+	Equity []EquityPoint
+}
+
+// CandleConfig controls one RunCandleReplay call.
+type CandleConfig struct {
+	JobName  string
+	Filters  osrs.FilterOptions
+	Timestep string // "1h", "6h", or "24h"; defaults to "1h"
+	From, To time.Time
+
+	// MaxQuantityPerTrade caps how many units a single trade buys, on top
+	// of the item's own buy limit. Zero means the buy limit is the only
+	// cap.
+	MaxQuantityPerTrade int
+
+	// FillModel selects how a trade's buy/sell prices are derived from a
+	// candle; empty defaults to FillModelInstant. SlippagePct only applies
+	// to FillModelMidpointSlippage.
+	FillModel   FillModel
+	SlippagePct float64
+}
+
+const defaultCandleTimestep = "1h"
+
+// RunCandleReplay simulates buy-limit-constrained flips for each item
+// across its historical candles between cfg.From and cfg.To, checking
+// osrs.PassesFilter against the candle-derived snapshot at every tick. A
+// passing tick with no open position opens one at that candle's
+// insta-sell price, sized to the item's remaining buy limit for the
+// current 4-hour window; the position closes once a later candle's
+// insta-buy price reaches the entry margin's target, net of the GE's 1%
+// sale tax. Items are replayed independently of one another.
+func RunCandleReplay(ctx context.Context, source CandleSource, items []osrs.ItemData, cfg CandleConfig) (*BacktestResult, error) {
+	timestep := cfg.Timestep
+	if timestep == "" {
+		timestep = defaultCandleTimestep
+	}
+
+	result := &BacktestResult{
+		JobName:  cfg.JobName,
+		Timestep: timestep,
+		From:     cfg.From,
+		To:       cfg.To,
+	}
+
+	for _, item := range items {
+		candles, err := source.GetCandles(ctx, item.ItemID, timestep)
+		if err != nil {
+			return nil, fmt.Errorf("replaying item %d: %w", item.ItemID, err)
+		}
+		result.Trades = append(result.Trades, replayItem(item, candles, cfg)...)
+	}
+
+	summarizeCandleResult(result)
+	return result, nil
+}
+
+// replayItem runs one item's buy-limit-constrained flip simulation across
+// its candles, restricted to [cfg.From, cfg.To].
+func replayItem(item osrs.ItemData, candles []Candle, cfg CandleConfig) []Trade {
+	maxQty := item.BuyLimit
+	if cfg.MaxQuantityPerTrade > 0 && (maxQty == 0 || cfg.MaxQuantityPerTrade < maxQty) {
+		maxQty = cfg.MaxQuantityPerTrade
+	}
+	fillModel := cfg.FillModel
+	if fillModel == "" {
+		fillModel = FillModelInstant
+	}
+
+	var trades []Trade
+	var open *Trade
+	var targetSellPrice int
+
+	var resetAt time.Time
+	boughtSinceReset := 0
+
+	for _, c := range candles {
+		if c.Timestamp.Before(cfg.From) || c.Timestamp.After(cfg.To) {
+			continue
+		}
+
+		if resetAt.IsZero() || !c.Timestamp.Before(resetAt) {
+			resetAt = c.Timestamp.Add(buyLimitResetEvery)
+			boughtSinceReset = 0
+		}
+
+		if open != nil && c.InstaBuyPrice >= targetSellPrice {
+			sellPrice := sellFillPrice(c, fillModel, cfg.SlippagePct)
+			tax := geTax(sellPrice, open.Quantity)
+			open.SellTime = c.Timestamp
+			open.SellPrice = sellPrice
+			open.TaxPaid = tax
+			open.ProfitGP = (open.SellPrice-open.BuyPrice)*open.Quantity - tax
+			trades = append(trades, *open)
+			open = nil
+			continue
+		}
+
+		if open != nil {
+			continue
+		}
+
+		snapshot := snapshotFromCandle(item, c)
+		if !osrs.PassesFilter(snapshot, cfg.Filters) {
+			continue
+		}
+
+		remaining := maxQty - boughtSinceReset
+		if item.BuyLimit > 0 && remaining <= 0 {
+			continue
+		}
+		qty := remaining
+		if qty <= 0 {
+			qty = maxQty
+		}
+		if qty <= 0 {
+			qty = 1
+		}
+
+		boughtSinceReset += qty
+		open = &Trade{
+			ItemID:   item.ItemID,
+			Name:     item.Name,
+			Quantity: qty,
+			BuyTime:  c.Timestamp,
+			BuyPrice: buyFillPrice(c, fillModel, cfg.SlippagePct),
+		}
+		targetSellPrice = c.InstaBuyPrice
+	}
+
+	if open != nil {
+		// Replay window ended with the position still open -- mark it to
+		// the last candle's mark price (what it could realistically be
+		// unwound for right now) for an unrealized PnL estimate.
+		last := candles[len(candles)-1]
+		open.Open = true
+		open.SellTime = last.Timestamp
+		open.SellPrice = markPrice(last, fillModel, cfg.SlippagePct)
+		open.ProfitGP = (open.SellPrice - open.BuyPrice) * open.Quantity
+		trades = append(trades, *open)
+	}
+
+	return trades
+}
+
+// snapshotFromCandle builds the ItemData osrs.PassesFilter would have seen
+// at candle c, carrying over item's static fields (name, buy limit, members
+// flag).
+func snapshotFromCandle(item osrs.ItemData, c Candle) osrs.ItemData {
+	snapshot := item
+	instaBuy := c.InstaBuyPrice
+	instaSell := c.InstaSellPrice
+	snapshot.InstaBuyPrice = &instaBuy
+	snapshot.InstaSellPrice = &instaSell
+	snapshot.MarginGP = instaBuy - instaSell
+	if instaSell > 0 {
+		snapshot.MarginPct = (float64(snapshot.MarginGP) / float64(instaSell)) * 100
+	}
+	return snapshot
+}
+
+// summarizeCandleResult fills in result's aggregate statistics from its
+// Trades.
+func summarizeCandleResult(result *BacktestResult) {
+	if len(result.Trades) == 0 {
+		return
+	}
+
+	closed := make([]Trade, 0, len(result.Trades))
+	for _, t := range result.Trades {
+		if t.Open {
+			result.UnrealizedProfitGP += t.ProfitGP
+			continue
+		}
+		closed = append(closed, t)
+	}
+	sort.Slice(closed, func(i, j int) bool { return closed[i].SellTime.Before(closed[j].SellTime) })
+
+	if len(closed) == 0 {
+		return
+	}
+
+	var wins int
+	var sumProfit, sumSquaredDev float64
+	cumulative := 0
+	peak := 0
+	for _, t := range closed {
+		result.RealizedProfitGP += t.ProfitGP
+		if t.ProfitGP > 0 {
+			wins++
+		}
+		sumProfit += float64(t.ProfitGP)
+
+		cumulative += t.ProfitGP
+		result.Equity = append(result.Equity, EquityPoint{Timestamp: t.SellTime, EquityGP: cumulative})
+		if cumulative > peak {
+			peak = cumulative
+		}
+		if drawdown := peak - cumulative; drawdown > result.MaxDrawdownGP {
+			result.MaxDrawdownGP = drawdown
+		}
+	}
+	result.WinRate = float64(wins) / float64(len(closed))
+
+	mean := sumProfit / float64(len(closed))
+	for _, t := range closed {
+		diff := float64(t.ProfitGP) - mean
+		sumSquaredDev += diff * diff
+	}
+	stddev := math.Sqrt(sumSquaredDev / float64(len(closed)))
+	if stddev > 0 {
+		result.SharpeRatio = mean / stddev
+	}
+
+	if hours := result.To.Sub(result.From).Hours(); hours > 0 {
+		result.GPPerHour = float64(result.RealizedProfitGP) / hours
+	}
+
+	result.PerItem = perItemPNL(result.Trades)
+}
+
+// perItemPNL groups trades by item, computing each item's realized and
+// unrealized PnL and win rate the same way summarizeCandleResult does for
+// the aggregate totals, ordered by descending RealizedProfitGP.
+func perItemPNL(trades []Trade) []ItemPNL {
+	byItem := make(map[int]*ItemPNL)
+	wins := make(map[int]int)
+	var order []int
+
+	for _, t := range trades {
+		pnl, ok := byItem[t.ItemID]
+		if !ok {
+			pnl = &ItemPNL{ItemID: t.ItemID, Name: t.Name}
+			byItem[t.ItemID] = pnl
+			order = append(order, t.ItemID)
+		}
+
+		if t.Open {
+			pnl.UnrealizedProfitGP += t.ProfitGP
+			continue
+		}
+		pnl.ClosedTrades++
+		pnl.RealizedProfitGP += t.ProfitGP
+		if t.ProfitGP > 0 {
+			wins[t.ItemID]++
+		}
+	}
+
+	perItem := make([]ItemPNL, 0, len(order))
+	for _, id := range order {
+		pnl := byItem[id]
+		if pnl.ClosedTrades > 0 {
+			pnl.WinRate = float64(wins[id]) / float64(pnl.ClosedTrades)
+		}
+		perItem = append(perItem, *pnl)
+	}
+
+	sort.Slice(perItem, func(i, j int) bool { return perItem[i].RealizedProfitGP > perItem[j].RealizedProfitGP })
+	return perItem
+}
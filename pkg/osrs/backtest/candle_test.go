@@ -0,0 +1,219 @@
+package backtest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"osrs-flipping/pkg/osrs"
+)
+
+// fakeCandleSource serves a fixed series per item for tests, standing in
+// for a live TimeseriesClient.
+type fakeCandleSource map[int][]Candle
+
+func (f fakeCandleSource) GetCandles(ctx context.Context, itemID int, timestep string) ([]Candle, error) {
+	return f[itemID], nil
+}
+
+func TestRunCandleReplayFillsAndRealizesProfitNetOfTax(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := fakeCandleSource{
+		1: {
+			{Timestamp: base, InstaBuyPrice: 110, InstaSellPrice: 100},
+			{Timestamp: base.Add(time.Hour), InstaBuyPrice: 115, InstaSellPrice: 105},
+		},
+	}
+	items := []osrs.ItemData{{ItemID: 1, Name: "Test Item", BuyLimit: 10}}
+
+	result, err := RunCandleReplay(context.Background(), source, items, CandleConfig{
+		JobName: "test", From: base, To: base.Add(time.Hour), Timestep: "1h",
+	})
+	if err != nil {
+		t.Fatalf("RunCandleReplay failed: %v", err)
+	}
+	if len(result.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(result.Trades))
+	}
+
+	trade := result.Trades[0]
+	if trade.Open {
+		t.Fatal("expected the trade to have closed within the window")
+	}
+	if trade.Quantity != 10 {
+		t.Errorf("expected quantity capped at buy limit 10, got %d", trade.Quantity)
+	}
+	wantTax := geTax(115, 10)
+	wantProfit := (115-100)*10 - wantTax
+	if trade.ProfitGP != wantProfit {
+		t.Errorf("ProfitGP = %d, want %d (tax %d)", trade.ProfitGP, wantProfit, wantTax)
+	}
+	if result.RealizedProfitGP != wantProfit {
+		t.Errorf("RealizedProfitGP = %d, want %d", result.RealizedProfitGP, wantProfit)
+	}
+	if result.WinRate != 1.0 {
+		t.Errorf("expected win rate 1.0, got %f", result.WinRate)
+	}
+}
+
+func TestRunCandleReplayMarksUnfilledPositionsOpen(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := fakeCandleSource{
+		1: {
+			{Timestamp: base, InstaBuyPrice: 110, InstaSellPrice: 100},
+			{Timestamp: base.Add(time.Hour), InstaBuyPrice: 108, InstaSellPrice: 95},
+		},
+	}
+	items := []osrs.ItemData{{ItemID: 1, Name: "Test Item", BuyLimit: 5}}
+
+	result, err := RunCandleReplay(context.Background(), source, items, CandleConfig{
+		JobName: "test", From: base, To: base.Add(time.Hour), Timestep: "1h",
+	})
+	if err != nil {
+		t.Fatalf("RunCandleReplay failed: %v", err)
+	}
+	if len(result.Trades) != 1 || !result.Trades[0].Open {
+		t.Fatalf("expected 1 open trade, got %+v", result.Trades)
+	}
+	if result.RealizedProfitGP != 0 {
+		t.Errorf("expected no realized profit from an open trade, got %d", result.RealizedProfitGP)
+	}
+	if result.UnrealizedProfitGP != (95-100)*5 {
+		t.Errorf("UnrealizedProfitGP = %d, want %d", result.UnrealizedProfitGP, (95-100)*5)
+	}
+}
+
+func TestRunCandleReplaySkipsItemsFailingFilters(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := fakeCandleSource{
+		1: {{Timestamp: base, InstaBuyPrice: 110, InstaSellPrice: 100}},
+	}
+	items := []osrs.ItemData{{ItemID: 1, Name: "Test Item", BuyLimit: 10}}
+
+	marginMin := 1000
+	result, err := RunCandleReplay(context.Background(), source, items, CandleConfig{
+		JobName: "test",
+		Filters: osrs.FilterOptions{MarginMin: &marginMin},
+		From:    base, To: base, Timestep: "1h",
+	})
+	if err != nil {
+		t.Fatalf("RunCandleReplay failed: %v", err)
+	}
+	if len(result.Trades) != 0 {
+		t.Fatalf("expected 0 trades for an item that never meets MarginMin, got %d", len(result.Trades))
+	}
+}
+
+func TestRunCandleReplayMidpointSlippageFillModel(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := fakeCandleSource{
+		1: {
+			{Timestamp: base, InstaBuyPrice: 110, InstaSellPrice: 100},
+			{Timestamp: base.Add(time.Hour), InstaBuyPrice: 115, InstaSellPrice: 105},
+		},
+	}
+	items := []osrs.ItemData{{ItemID: 1, Name: "Test Item", BuyLimit: 10}}
+
+	result, err := RunCandleReplay(context.Background(), source, items, CandleConfig{
+		JobName: "test", From: base, To: base.Add(time.Hour), Timestep: "1h",
+		FillModel: FillModelMidpointSlippage, SlippagePct: 0.1,
+	})
+	if err != nil {
+		t.Fatalf("RunCandleReplay failed: %v", err)
+	}
+	if len(result.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(result.Trades))
+	}
+
+	trade := result.Trades[0]
+	buyMid, sellMid := 105.0, 110.0
+	wantBuy := int(buyMid + buyMid*0.1)    // midpoint of 100/110, plus 10% slippage
+	wantSell := int(sellMid - sellMid*0.1) // midpoint of 105/115, minus 10% slippage
+	if trade.BuyPrice != wantBuy {
+		t.Errorf("BuyPrice = %d, want %d", trade.BuyPrice, wantBuy)
+	}
+	if trade.SellPrice != wantSell {
+		t.Errorf("SellPrice = %d, want %d", trade.SellPrice, wantSell)
+	}
+}
+
+func TestRunCandleReplayPerItemPNL(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := fakeCandleSource{
+		1: {
+			{Timestamp: base, InstaBuyPrice: 110, InstaSellPrice: 100},
+			{Timestamp: base.Add(time.Hour), InstaBuyPrice: 115, InstaSellPrice: 105},
+		},
+		2: {
+			{Timestamp: base, InstaBuyPrice: 210, InstaSellPrice: 200},
+			{Timestamp: base.Add(time.Hour), InstaBuyPrice: 208, InstaSellPrice: 195},
+		},
+	}
+	items := []osrs.ItemData{
+		{ItemID: 1, Name: "Winner", BuyLimit: 10},
+		{ItemID: 2, Name: "Loser", BuyLimit: 5},
+	}
+
+	result, err := RunCandleReplay(context.Background(), source, items, CandleConfig{
+		JobName: "test", From: base, To: base.Add(time.Hour), Timestep: "1h",
+	})
+	if err != nil {
+		t.Fatalf("RunCandleReplay failed: %v", err)
+	}
+	if len(result.PerItem) != 2 {
+		t.Fatalf("expected 2 items in PerItem, got %d", len(result.PerItem))
+	}
+
+	// Item 1 closes profitably, item 2 never reaches its target and ends
+	// the window open at an unrealized loss -- PerItem should be sorted by
+	// descending RealizedProfitGP, so the winner comes first.
+	if result.PerItem[0].ItemID != 1 || result.PerItem[0].RealizedProfitGP <= 0 {
+		t.Errorf("expected item 1 first with positive realized profit, got %+v", result.PerItem[0])
+	}
+	if result.PerItem[1].ItemID != 2 || result.PerItem[1].UnrealizedProfitGP >= 0 {
+		t.Errorf("expected item 2 second with a negative unrealized mark, got %+v", result.PerItem[1])
+	}
+
+	if result.GPPerHour != float64(result.RealizedProfitGP) {
+		t.Errorf("GPPerHour = %f, want %f over a 1-hour window", result.GPPerHour, float64(result.RealizedProfitGP))
+	}
+}
+
+func TestFileCandleSourceReadsArchivedTimeseries(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "1_1h.json")
+	data := `{"data":[{"timestamp":1700000000,"avgHighPrice":110,"avgLowPrice":100,"highPriceVolume":5,"lowPriceVolume":7}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	source := NewFileCandleSource(dir)
+	candles, err := source.GetCandles(context.Background(), 1, "1h")
+	if err != nil {
+		t.Fatalf("GetCandles failed: %v", err)
+	}
+	if len(candles) != 1 {
+		t.Fatalf("expected 1 candle, got %d", len(candles))
+	}
+	if candles[0].InstaBuyPrice != 110 || candles[0].InstaSellPrice != 100 {
+		t.Errorf("unexpected candle prices: %+v", candles[0])
+	}
+}
+
+func TestFileCandleSourceMissingFileErrors(t *testing.T) {
+	source := NewFileCandleSource(t.TempDir())
+	if _, err := source.GetCandles(context.Background(), 999, "1h"); err == nil {
+		t.Error("expected an error reading a missing archive file, got nil")
+	}
+}
+
+func TestGeTaxCapsAtFiveMillion(t *testing.T) {
+	if got := geTax(1_000_000_000, 1); got != geTaxCapGP {
+		t.Errorf("geTax(1e9, 1) = %d, want cap %d", got, geTaxCapGP)
+	}
+	if got := geTax(100, 10); got != 10 {
+		t.Errorf("geTax(100, 10) = %d, want 10 (1%% of 1000)", got)
+	}
+}
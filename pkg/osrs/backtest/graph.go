@@ -0,0 +1,81 @@
+package backtest
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// RenderGraphs writes a per-trade PnL scatter and a cumulative-PnL line
+// chart for result to dir, named pnl.png and cumulative_pnl.png. This
+// mirrors the graphPNLPath/generateGraph option bbgo's drift strategy
+// offers for eyeballing a strategy's equity curve before risking it live.
+func RenderGraphs(result *BacktestResult, dir string) error {
+	if len(result.Equity) == 0 {
+		return fmt.Errorf("no closed trades to graph")
+	}
+
+	if err := renderPNLScatter(result, filepath.Join(dir, "pnl.png")); err != nil {
+		return err
+	}
+	if err := renderCumulativePNL(result, filepath.Join(dir, "cumulative_pnl.png")); err != nil {
+		return err
+	}
+	return nil
+}
+
+// renderPNLScatter plots each closed trade's realized profit against its
+// position in the equity curve.
+func renderPNLScatter(result *BacktestResult, path string) error {
+	pts := make(plotter.XYs, len(result.Equity))
+	prev := 0
+	for i, e := range result.Equity {
+		pts[i].X = float64(i)
+		pts[i].Y = float64(e.EquityGP - prev)
+		prev = e.EquityGP
+	}
+
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("%s: per-trade PnL (GP)", result.JobName)
+	p.X.Label.Text = "trade #"
+	p.Y.Label.Text = "realized GP"
+
+	scatter, err := plotter.NewScatter(pts)
+	if err != nil {
+		return fmt.Errorf("building pnl scatter: %w", err)
+	}
+	p.Add(scatter)
+
+	if err := p.Save(8*vg.Inch, 4*vg.Inch, path); err != nil {
+		return fmt.Errorf("saving pnl graph: %w", err)
+	}
+	return nil
+}
+
+// renderCumulativePNL plots the running total of realized profit over time.
+func renderCumulativePNL(result *BacktestResult, path string) error {
+	pts := make(plotter.XYs, len(result.Equity))
+	for i, e := range result.Equity {
+		pts[i].X = float64(e.Timestamp.Unix())
+		pts[i].Y = float64(e.EquityGP)
+	}
+
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("%s: cumulative PnL (GP)", result.JobName)
+	p.X.Label.Text = "time (unix seconds)"
+	p.Y.Label.Text = "cumulative GP"
+
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		return fmt.Errorf("building cumulative pnl line: %w", err)
+	}
+	p.Add(line)
+
+	if err := p.Save(8*vg.Inch, 4*vg.Inch, path); err != nil {
+		return fmt.Errorf("saving cumulative pnl graph: %w", err)
+	}
+	return nil
+}
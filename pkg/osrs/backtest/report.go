@@ -0,0 +1,98 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// summaryJSON is the JSON-serializable form of a Report's aggregate stats,
+// written alongside the per-sample CSV.
+type summaryJSON struct {
+	JobName               string  `json:"job_name"`
+	From                  string  `json:"from"`
+	To                    string  `json:"to"`
+	SampleIntervalMinutes int     `json:"sample_interval_minutes"`
+	HorizonMinutes        float64 `json:"horizon_minutes"`
+	SampleCount           int     `json:"sample_count"`
+
+	TotalTheoreticalProfit int     `json:"total_theoretical_profit_gp"`
+	HitRate                float64 `json:"hit_rate"`
+	AvgTimeToFillMinutes   float64 `json:"avg_time_to_fill_minutes"`
+	WorstCaseLoss          int     `json:"worst_case_loss_gp"`
+}
+
+// WriteSummaryJSON writes the report's aggregate statistics to path as JSON.
+func (r *Report) WriteSummaryJSON(path string) error {
+	summary := summaryJSON{
+		JobName:                r.JobName,
+		From:                   r.From.Format(time.RFC3339),
+		To:                     r.To.Format(time.RFC3339),
+		SampleIntervalMinutes:  r.SampleIntervalMinutes,
+		HorizonMinutes:         r.Horizon.Minutes(),
+		SampleCount:            len(r.Samples),
+		TotalTheoreticalProfit: r.TotalTheoreticalProfit,
+		HitRate:                r.HitRate,
+		AvgTimeToFillMinutes:   r.AvgTimeToFill.Minutes(),
+		WorstCaseLoss:          r.WorstCaseLoss,
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling backtest summary: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing backtest summary: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes one row per sample to path, for users who want to inspect
+// or chart individual simulated flips.
+func (r *Report) WriteCSV(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating backtest csv: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	header := []string{
+		"item_id", "name", "entry_time", "entry_price", "target_sell_price",
+		"filled", "fill_time", "time_to_fill_minutes", "theoretical_profit_gp", "worst_case_loss_gp",
+	}
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("writing backtest csv header: %w", err)
+	}
+
+	for _, s := range r.Samples {
+		fillTime := ""
+		timeToFill := ""
+		if s.Filled {
+			fillTime = s.FillTime.Format(time.RFC3339)
+			timeToFill = fmt.Sprintf("%.1f", s.TimeToFill.Minutes())
+		}
+
+		row := []string{
+			fmt.Sprintf("%d", s.ItemID),
+			s.Name,
+			s.EntryTime.Format(time.RFC3339),
+			fmt.Sprintf("%d", s.EntryPrice),
+			fmt.Sprintf("%d", s.TargetSellPrice),
+			fmt.Sprintf("%t", s.Filled),
+			fillTime,
+			timeToFill,
+			fmt.Sprintf("%d", s.TheoreticalProfit),
+			fmt.Sprintf("%d", s.WorstCaseLoss),
+		}
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("writing backtest csv row: %w", err)
+		}
+	}
+
+	return nil
+}
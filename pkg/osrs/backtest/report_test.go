@@ -0,0 +1,59 @@
+package backtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteCSVAndSummaryJSON(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		JobName:               "test-job",
+		From:                  base,
+		To:                    base.Add(24 * time.Hour),
+		SampleIntervalMinutes: 60,
+		Horizon:               6 * time.Hour,
+		Samples: []ItemResult{
+			{
+				ItemID: 1, Name: "Test Item", EntryTime: base, EntryPrice: 100, TargetSellPrice: 110,
+				Filled: true, FillTime: base.Add(time.Hour), TimeToFill: time.Hour, TheoreticalProfit: 10,
+			},
+			{
+				ItemID: 2, Name: "Other Item", EntryTime: base, EntryPrice: 200, TargetSellPrice: 220,
+				Filled: false, WorstCaseLoss: 15,
+			},
+		},
+		TotalTheoreticalProfit: -5,
+		HitRate:                0.5,
+		AvgTimeToFill:          time.Hour,
+		WorstCaseLoss:          15,
+	}
+
+	csvPath := filepath.Join(dir, "report.csv")
+	if err := report.WriteCSV(csvPath); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("failed to read csv: %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("expected non-empty csv output")
+	}
+
+	summaryPath := filepath.Join(dir, "summary.json")
+	if err := report.WriteSummaryJSON(summaryPath); err != nil {
+		t.Fatalf("WriteSummaryJSON failed: %v", err)
+	}
+	summaryData, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("failed to read summary json: %v", err)
+	}
+	if len(summaryData) == 0 {
+		t.Fatal("expected non-empty summary json output")
+	}
+}
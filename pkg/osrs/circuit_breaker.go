@@ -0,0 +1,143 @@
+package osrs
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// CircuitState is a CircuitBreaker's current stage, surfaced via
+// Client.Stats() so callers can tell "healthy" apart from "tripped,
+// cooling down" and "cautiously probing again after a trip."
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// ErrCircuitOpen is returned by Client's request methods instead of making
+// a network call while the circuit breaker is open, letting a caller like
+// VolumePoller or GapFiller treat "the API is currently unhealthy" as
+// distinct from "this one request failed."
+var ErrCircuitOpen = errors.New("circuit breaker open: too many recent API failures")
+
+// circuitBreakerFailureThreshold is how many consecutive Retryable
+// failures (429/5xx, see ClassifyError) CircuitBreaker tolerates before it
+// opens, stopping further requests rather than hammering an API that's
+// already struggling.
+const circuitBreakerFailureThreshold = 5
+
+// circuitBreakerBaseCooldown/circuitBreakerMaxCooldown bound how long an
+// open CircuitBreaker waits before allowing a half-open probe: it starts
+// at the base and doubles on every failed probe, capped at the max -- the
+// same exponential-backoff shape RetryConfig uses for individual retries.
+const (
+	circuitBreakerBaseCooldown = 5 * time.Second
+	circuitBreakerMaxCooldown  = 5 * time.Minute
+)
+
+// CircuitBreaker is a process-wide trip switch for the Wiki API: Client
+// calls Allow before every request and RecordResult after, so repeated
+// 429/5xx responses open the circuit and reject requests for a cooldown
+// instead of every caller (VolumePoller, GapFiller, UI handlers) retrying
+// into a struggling API independently. Modeled on the leaky/token-bucket
+// style circuit breakers used by rate limiting proxies like gubernator: a
+// closed circuit passes everything, an open one rejects everything until
+// its cooldown elapses, and a half-open one allows exactly one probe
+// request to decide whether to close again or reopen with a longer
+// cooldown.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	state            CircuitState
+	consecutiveFails int
+	cooldown         time.Duration
+	openedAt         time.Time
+	probeInFlight    bool
+	lastErr          error
+}
+
+// NewCircuitBreaker creates a CircuitBreaker in the closed state.
+func NewCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{state: CircuitClosed, cooldown: circuitBreakerBaseCooldown}
+}
+
+// Allow reports whether a request may proceed: always true while closed,
+// false while open until its cooldown elapses, and true for exactly one
+// caller (the probe) once the cooldown elapses or the circuit is already
+// half-open with no probe outstanding.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if time.Since(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		cb.state = CircuitHalfOpen
+		cb.probeInFlight = true
+		return true
+	case CircuitHalfOpen:
+		if cb.probeInFlight {
+			return false
+		}
+		cb.probeInFlight = true
+		return true
+	default: // CircuitClosed
+		return true
+	}
+}
+
+// RecordResult reports the outcome of a request Allow just permitted.
+// retryable should be ClassifyError(err).Retryable() -- only 429/5xx-class
+// failures count toward tripping the breaker, the same failures
+// RetryConfig retries; a permanent client error (4xx) means the caller's
+// request was bad, not that the API is struggling, so it doesn't trip.
+func (cb *CircuitBreaker) RecordResult(err error, retryable bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.lastErr = err
+	cb.probeInFlight = false
+
+	if err == nil || !retryable {
+		cb.consecutiveFails = 0
+		cb.state = CircuitClosed
+		cb.cooldown = circuitBreakerBaseCooldown
+		return
+	}
+
+	if cb.state == CircuitHalfOpen {
+		// The probe failed -- reopen with a longer cooldown.
+		cb.state = CircuitOpen
+		cb.openedAt = time.Now()
+		cb.cooldown *= 2
+		if cb.cooldown > circuitBreakerMaxCooldown {
+			cb.cooldown = circuitBreakerMaxCooldown
+		}
+		return
+	}
+
+	cb.consecutiveFails++
+	if cb.consecutiveFails >= circuitBreakerFailureThreshold {
+		cb.state = CircuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// State returns the breaker's current stage.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// LastErr returns the most recently recorded result's error, nil if the
+// last recorded result was a success or nothing has been recorded yet.
+func (cb *CircuitBreaker) LastErr() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.lastErr
+}
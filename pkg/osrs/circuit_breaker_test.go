@@ -0,0 +1,129 @@
+package osrs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerStartsClosed(t *testing.T) {
+	cb := NewCircuitBreaker()
+
+	if !cb.Allow() {
+		t.Error("a new breaker should allow requests")
+	}
+	if cb.State() != CircuitClosed {
+		t.Errorf("State() = %v, want %v", cb.State(), CircuitClosed)
+	}
+}
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	cb := NewCircuitBreaker()
+
+	for i := 0; i < circuitBreakerFailureThreshold; i++ {
+		if !cb.Allow() {
+			t.Fatalf("Allow() should still pass before the breaker trips (i=%d)", i)
+		}
+		cb.RecordResult(errors.New("boom"), true)
+	}
+
+	if cb.State() != CircuitOpen {
+		t.Errorf("State() after %d failures = %v, want %v", circuitBreakerFailureThreshold, cb.State(), CircuitOpen)
+	}
+	if cb.Allow() {
+		t.Error("Allow() should reject requests while open and within cooldown")
+	}
+}
+
+func TestCircuitBreakerIgnoresNonRetryableFailures(t *testing.T) {
+	cb := NewCircuitBreaker()
+
+	for i := 0; i < circuitBreakerFailureThreshold*2; i++ {
+		cb.RecordResult(errors.New("not found"), false)
+	}
+
+	if cb.State() != CircuitClosed {
+		t.Errorf("State() after non-retryable failures = %v, want %v", cb.State(), CircuitClosed)
+	}
+}
+
+func TestCircuitBreakerHalfOpensAfterCooldown(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.cooldown = time.Millisecond
+
+	for i := 0; i < circuitBreakerFailureThreshold; i++ {
+		cb.Allow()
+		cb.RecordResult(errors.New("boom"), true)
+	}
+	if cb.State() != CircuitOpen {
+		t.Fatalf("State() = %v, want %v", cb.State(), CircuitOpen)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if !cb.Allow() {
+		t.Fatal("Allow() should permit exactly one probe once the cooldown elapses")
+	}
+	if cb.State() != CircuitHalfOpen {
+		t.Errorf("State() after cooldown = %v, want %v", cb.State(), CircuitHalfOpen)
+	}
+	if cb.Allow() {
+		t.Error("Allow() should reject a second concurrent probe while one is in flight")
+	}
+}
+
+func TestCircuitBreakerClosesOnSuccessfulProbe(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.cooldown = time.Millisecond
+
+	for i := 0; i < circuitBreakerFailureThreshold; i++ {
+		cb.Allow()
+		cb.RecordResult(errors.New("boom"), true)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	cb.Allow() // consumes the probe slot, transitions to half-open
+	cb.RecordResult(nil, false)
+
+	if cb.State() != CircuitClosed {
+		t.Errorf("State() after a successful probe = %v, want %v", cb.State(), CircuitClosed)
+	}
+	if !cb.Allow() {
+		t.Error("Allow() should pass again once closed")
+	}
+}
+
+func TestCircuitBreakerReopensWithLongerCooldownOnFailedProbe(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.cooldown = time.Millisecond
+
+	for i := 0; i < circuitBreakerFailureThreshold; i++ {
+		cb.Allow()
+		cb.RecordResult(errors.New("boom"), true)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	cb.Allow()
+	cb.RecordResult(errors.New("still failing"), true)
+
+	if cb.State() != CircuitOpen {
+		t.Fatalf("State() after a failed probe = %v, want %v", cb.State(), CircuitOpen)
+	}
+	if cb.cooldown <= time.Millisecond {
+		t.Errorf("cooldown after a failed probe = %v, want > %v", cb.cooldown, time.Millisecond)
+	}
+}
+
+func TestCircuitBreakerLastErr(t *testing.T) {
+	cb := NewCircuitBreaker()
+
+	if cb.LastErr() != nil {
+		t.Error("LastErr() on a fresh breaker should be nil")
+	}
+
+	want := errors.New("boom")
+	cb.RecordResult(want, true)
+	if !errors.Is(cb.LastErr(), want) {
+		t.Errorf("LastErr() = %v, want %v", cb.LastErr(), want)
+	}
+}
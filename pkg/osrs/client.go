@@ -5,37 +5,286 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
+// defaultRequestsPerSecond is each endpoint's starting rate in a new
+// Client's RateLimiterGroup, before any AIMD adjustment from Observe.
+const defaultRequestsPerSecond = 2.0
+
+// defaultRequestBudgetLimit/defaultRequestBudgetWindow cap a new Client to
+// 100 requests/minute across every endpoint combined, independent of the
+// per-endpoint RateLimiterGroup, so a full catalogue refresh and an
+// interactive query sharing one Client can't together exceed what the Wiki
+// API tolerates.
+const (
+	defaultRequestBudgetLimit  = 100
+	defaultRequestBudgetWindow = time.Minute
+)
+
+// RetryConfig controls makeConditionalAPIRequest's retry loop for failures
+// ClassifyError deems Retryable (rate limits, 5xx, network errors).
+type RetryConfig struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryConfig returns the retry policy a new Client starts with: up
+// to 3 retries, backing off exponentially from 500ms and capped at 10s.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{MaxRetries: 3, BaseDelay: 500 * time.Millisecond, MaxDelay: 10 * time.Second}
+}
+
 // Client handles API communication with RuneScape Wiki API
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	userAgent  string
+	baseURL      string
+	httpClient   *http.Client
+	userAgent    string
+	rateLimiters *RateLimiterGroup
+	budget       *RequestBudget
+	retryConfig  RetryConfig
+	breaker      *CircuitBreaker
+	cache        ResponseCache
+
+	retriedSuccessfully atomic.Int64
+	failedAfterRetries  atomic.Int64
+	requestCount        atomic.Int64
+	throttledCount      atomic.Int64
+	cacheHits           atomic.Int64
+	cacheMisses         atomic.Int64
+	cacheRevalidated    atomic.Int64
+}
+
+// ClientStats is a point-in-time snapshot of c's cumulative request
+// outcomes, for a caller (e.g. APIDataSource.loadVolumeMetricsFromAPI, or
+// VolumePoller/GapFiller's own Stats) that wants to report how much of a
+// batch's failures were transient, whether the shared circuit breaker is
+// currently protecting the API from further load, and how much bandwidth
+// the response cache is saving.
+type ClientStats struct {
+	RetriedSuccessfully int64
+	FailedAfterRetries  int64
+	RequestCount        int64
+	ThrottledCount      int64
+	CircuitState        CircuitState
+	LastError           string
+	CacheHits           int64
+	CacheMisses         int64
+	CacheRevalidated    int64
+
+	// EffectiveRPS is each endpoint's current requests/second, keyed the
+	// same way as rateLimitKeyFor, reflecting any AIMD backoff a 429/503
+	// has applied since the last recovery. Every caller -- Poller,
+	// Backfiller, GapFiller, VolumePoller -- shares this one Client, so
+	// this is the coordinated rate across all of them, not a per-caller
+	// estimate.
+	EffectiveRPS map[string]float64
+}
+
+// Stats returns a snapshot of c's cumulative retry/circuit-breaker/cache
+// outcomes since creation.
+func (c *Client) Stats() ClientStats {
+	stats := ClientStats{
+		RetriedSuccessfully: c.retriedSuccessfully.Load(),
+		FailedAfterRetries:  c.failedAfterRetries.Load(),
+		RequestCount:        c.requestCount.Load(),
+		ThrottledCount:      c.throttledCount.Load(),
+		CircuitState:        c.breaker.State(),
+		CacheHits:           c.cacheHits.Load(),
+		CacheMisses:         c.cacheMisses.Load(),
+		CacheRevalidated:    c.cacheRevalidated.Load(),
+		EffectiveRPS:        c.rateLimiters.EffectiveRPS(),
+	}
+	if err := c.breaker.LastErr(); err != nil {
+		stats.LastError = err.Error()
+	}
+	return stats
 }
 
 // NewClient creates a new OSRS API client
 // userAgent is required by the RuneScape Wiki API
 func NewClient(userAgent string) *Client {
 	return &Client{
-		baseURL:    "https://prices.runescape.wiki/api/v1/osrs",
-		userAgent:  userAgent,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:      "https://prices.runescape.wiki/api/v1/osrs",
+		userAgent:    userAgent,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		rateLimiters: NewRateLimiterGroup(defaultRequestsPerSecond),
+		budget:       NewRequestBudget(defaultRequestBudgetLimit, defaultRequestBudgetWindow),
+		retryConfig:  DefaultRetryConfig(),
+		breaker:      NewCircuitBreaker(),
+		cache:        NewMemoryResponseCache(),
+	}
+}
+
+// SetRequestBudget replaces c's request budget, e.g. to raise or lower the
+// shared cross-endpoint ceiling from its default of 100 requests/minute.
+func (c *Client) SetRequestBudget(b *RequestBudget) {
+	c.budget = b
+}
+
+// SetRetryConfig replaces c's retry policy for Retryable failures.
+func (c *Client) SetRetryConfig(cfg RetryConfig) {
+	c.retryConfig = cfg
+}
+
+// SetResponseCache replaces c's response cache, e.g. with an adapter that
+// persists validators across restarts instead of MemoryResponseCache's
+// process-lifetime map.
+func (c *Client) SetResponseCache(cache ResponseCache) {
+	c.cache = cache
+}
+
+// retryBackoff returns how long to wait before retry attempt (1-indexed),
+// as an exponential backoff off cfg.BaseDelay with full jitter, capped at
+// cfg.MaxDelay -- jitter so a burst of requests that all failed together
+// (e.g. after a shared rate limit) don't all retry in lockstep.
+func retryBackoff(cfg RetryConfig, attempt int) time.Duration {
+	d := cfg.BaseDelay * time.Duration(1<<uint(attempt-1))
+	if d > cfg.MaxDelay || d <= 0 {
+		d = cfg.MaxDelay
 	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// rateLimitKeyFor derives the RateLimiterGroup bucket key for a request.
+// The Wiki API exposes "/5m", "/1h" and "/24h" as a timestep parameter on
+// the single "/timeseries" endpoint rather than as distinct paths, but
+// they're independent in practice (an item's 5m history is fetched far
+// more often than its 24h history), so GetTimeseries calls get their own
+// bucket per timestep instead of sharing "/timeseries"'s.
+func rateLimitKeyFor(endpoint string, params map[string]string) string {
+	if endpoint == "/timeseries" {
+		if timestep, ok := params["timestep"]; ok {
+			return endpoint + "/" + timestep
+		}
+	}
+	return endpoint
 }
 
 // makeAPIRequest is the core HTTP request method (equivalent to make_api_request in Python)
 func (c *Client) makeAPIRequest(ctx context.Context, endpoint string, params map[string]string) ([]byte, error) {
+	return c.makeCachedAPIRequest(ctx, endpoint, params)
+}
+
+// makeCachedAPIRequest wraps makeConditionalAPIRequest with c.cache
+// consulted automatically: any validators cached under endpoint+params are
+// sent as If-None-Match/If-Modified-Since, and a 304 response returns the
+// cached body instead of nil, so callers like GetLatestPrices don't need
+// to manage ETags themselves the way GetItemMapping's callers do.
+func (c *Client) makeCachedAPIRequest(ctx context.Context, endpoint string, params map[string]string) ([]byte, error) {
+	key := cacheKeyFor(endpoint, params)
+	cached, hasCached, _ := c.cache.Get(ctx, key)
+
+	headers := make(map[string]string, 2)
+	if hasCached {
+		c.cacheHits.Add(1)
+		if cached.ETag != "" {
+			headers["If-None-Match"] = cached.ETag
+		}
+		if cached.LastModified != "" {
+			headers["If-Modified-Since"] = cached.LastModified
+		}
+	} else {
+		c.cacheMisses.Add(1)
+	}
+
+	body, respHeaders, notModified, err := c.makeConditionalAPIRequest(ctx, endpoint, params, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if notModified {
+		c.cacheRevalidated.Add(1)
+		if !hasCached {
+			return nil, fmt.Errorf("%s: server returned 304 but no cached response is available", endpoint)
+		}
+		return cached.Body, nil
+	}
+
+	if etag, lastMod := respHeaders.Get("ETag"), respHeaders.Get("Last-Modified"); etag != "" || lastMod != "" {
+		c.cache.Set(ctx, key, CachedResponse{Body: body, ETag: etag, LastModified: lastMod})
+	}
+
+	return body, nil
+}
+
+// makeConditionalAPIRequest is makeAPIRequest extended with optional
+// request headers (for conditional GETs, e.g. If-None-Match) and the
+// response headers, so a caller like GetItemMapping can read back
+// ETag/Last-Modified and treat 304 Not Modified as a cache hit instead of
+// an error. Failures ClassifyError deems Retryable (rate limits, 5xx,
+// network errors) are retried with backoff per c.retryConfig; anything else
+// (a permanent 4xx, a canceled context) returns immediately.
+func (c *Client) makeConditionalAPIRequest(ctx context.Context, endpoint string, params, headers map[string]string) ([]byte, http.Header, bool, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(retryBackoff(c.retryConfig, attempt)):
+			case <-ctx.Done():
+				return nil, nil, false, ctx.Err()
+			}
+		}
+
+		body, respHeaders, notModified, err := c.attemptConditionalAPIRequest(ctx, endpoint, params, headers)
+		if err == nil {
+			if attempt > 0 {
+				c.retriedSuccessfully.Add(1)
+			}
+			return body, respHeaders, notModified, nil
+		}
+		lastErr = err
+		if !ClassifyError(err).Retryable() {
+			return nil, nil, false, err
+		}
+	}
+	c.failedAfterRetries.Add(1)
+	return nil, nil, false, lastErr
+}
+
+// attemptConditionalAPIRequest makes a single request attempt: checking the
+// shared circuit breaker, reserving a slot in c's cross-endpoint budget,
+// waiting on the endpoint's rate limiter, then performing the HTTP round
+// trip and feeding its outcome back to the breaker.
+func (c *Client) attemptConditionalAPIRequest(ctx context.Context, endpoint string, params, headers map[string]string) ([]byte, http.Header, bool, error) {
+	if !c.breaker.Allow() {
+		c.throttledCount.Add(1)
+		return nil, nil, false, ErrCircuitOpen
+	}
+
+	if err := c.budget.Reserve(ctx); err != nil {
+		return nil, nil, false, err
+	}
+
+	rateLimitKey := rateLimitKeyFor(endpoint, params)
+	if err := c.rateLimiters.Wait(ctx, rateLimitKey); err != nil {
+		return nil, nil, false, err
+	}
+
+	c.requestCount.Add(1)
+	body, respHeaders, notModified, err := c.doHTTPRequest(ctx, endpoint, rateLimitKey, params, headers)
+	c.breaker.RecordResult(err, ClassifyError(err).Retryable())
+	return body, respHeaders, notModified, err
+}
+
+// doHTTPRequest performs the actual round trip for attemptConditionalAPIRequest,
+// once the budget/rate limiter/circuit breaker have all cleared it.
+func (c *Client) doHTTPRequest(ctx context.Context, endpoint, rateLimitKey string, params, headers map[string]string) ([]byte, http.Header, bool, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return nil, nil, false, fmt.Errorf("creating request: %w", err)
 	}
 
 	// Critical: User-Agent required by RuneScape Wiki API
 	req.Header.Set("User-Agent", c.userAgent)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 
 	// Add query parameters
 	if params != nil {
@@ -48,20 +297,28 @@ func (c *Client) makeAPIRequest(ctx context.Context, endpoint string, params map
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("making request: %w", err)
+		// The request never reached (or returned from) the server, so
+		// there's no status/Retry-After to learn from.
+		c.rateLimiters.Observe(rateLimitKey, 0, 0)
+		return nil, nil, false, fmt.Errorf("making request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	c.rateLimiters.Observe(rateLimitKey, resp.StatusCode, parseRetryAfter(resp.Header))
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, resp.Header, true, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, nil, false, &APIError{Endpoint: endpoint, StatusCode: resp.StatusCode}
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
+		return nil, nil, false, fmt.Errorf("reading response: %w", err)
 	}
 
-	return body, nil
+	return body, resp.Header, false, nil
 }
 
 // GetLatestPrices fetches current prices for all items or a specific item
@@ -87,20 +344,74 @@ func (c *Client) GetLatestPrices(ctx context.Context, itemID *int) (*LatestPrice
 	return &response, nil
 }
 
-// GetItemMapping fetches item metadata (names, buy limits, etc.)
-// Equivalent to get_item_mapping method in Python
-func (c *Client) GetItemMapping(ctx context.Context) ([]ItemMapping, error) {
-	data, err := c.makeAPIRequest(ctx, "/mapping", nil)
+// GetItemMapping fetches item metadata (names, buy limits, etc.).
+// ifNoneMatch/ifModifiedSince are the ETag/Last-Modified values learned
+// from a previous call (either may be empty to fetch unconditionally); the
+// Wiki API rarely changes its mapping, so sending them lets the server
+// reply 304 Not Modified instead of resending the whole catalog. When it
+// does, notModified is true and mappings is nil -- the caller should keep
+// using whatever it already has.
+//
+// Callers that track their own validators across restarts (e.g. ItemSyncer,
+// which persists them via ItemStore) should keep passing them in as before.
+// Callers that don't (analyzer.go, datasource.go, backfiller.go all call
+// this with empty strings) get the same 304 benefit for free: when both
+// arguments are empty, GetItemMapping consults c.cache itself and, on a
+// 304, unmarshals the cached body instead of returning nil.
+func (c *Client) GetItemMapping(ctx context.Context, ifNoneMatch, ifModifiedSince string) (mappings []ItemMapping, etag, lastModified string, notModified bool, err error) {
+	cacheKey := cacheKeyFor("/mapping", nil)
+	autoCache := ifNoneMatch == "" && ifModifiedSince == ""
+
+	var cached CachedResponse
+	var hasCached bool
+	if autoCache {
+		cached, hasCached, _ = c.cache.Get(ctx, cacheKey)
+		if hasCached {
+			c.cacheHits.Add(1)
+			ifNoneMatch = cached.ETag
+			ifModifiedSince = cached.LastModified
+		} else {
+			c.cacheMisses.Add(1)
+		}
+	}
+
+	headers := make(map[string]string, 2)
+	if ifNoneMatch != "" {
+		headers["If-None-Match"] = ifNoneMatch
+	}
+	if ifModifiedSince != "" {
+		headers["If-Modified-Since"] = ifModifiedSince
+	}
+
+	data, respHeaders, notModified, err := c.makeConditionalAPIRequest(ctx, "/mapping", nil, headers)
 	if err != nil {
-		return nil, fmt.Errorf("fetching item mapping: %w", err)
+		return nil, "", "", false, fmt.Errorf("fetching item mapping: %w", err)
+	}
+	if notModified {
+		if autoCache {
+			c.cacheRevalidated.Add(1)
+			if !hasCached {
+				return nil, "", "", false, fmt.Errorf("fetching item mapping: server returned 304 but no cached response is available")
+			}
+			if err := json.Unmarshal(cached.Body, &mappings); err != nil {
+				return nil, "", "", false, fmt.Errorf("parsing cached item mapping: %w", err)
+			}
+			return mappings, cached.ETag, cached.LastModified, true, nil
+		}
+		return nil, respHeaders.Get("ETag"), respHeaders.Get("Last-Modified"), true, nil
 	}
 
-	var mappings []ItemMapping
 	if err := json.Unmarshal(data, &mappings); err != nil {
-		return nil, fmt.Errorf("parsing item mapping response: %w", err)
+		return nil, "", "", false, fmt.Errorf("parsing item mapping response: %w", err)
+	}
+
+	etag = respHeaders.Get("ETag")
+	lastModified = respHeaders.Get("Last-Modified")
+	if etag != "" || lastModified != "" {
+		c.cache.Set(ctx, cacheKey, CachedResponse{Body: data, ETag: etag, LastModified: lastModified})
 	}
 
-	return mappings, nil
+	return mappings, etag, lastModified, false, nil
 }
 
 // GetTimeseries fetches historical price/volume data for a specific item
@@ -0,0 +1,115 @@
+package osrs
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// countingRoundTripper returns statuses in sequence (repeating the last one
+// once exhausted) and counts how many requests it saw, so tests can assert
+// on makeConditionalAPIRequest's retry behavior without a real server.
+type countingRoundTripper struct {
+	statuses []int
+	calls    int
+}
+
+func (rt *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	idx := rt.calls
+	if idx >= len(rt.statuses) {
+		idx = len(rt.statuses) - 1
+	}
+	status := rt.statuses[idx]
+	rt.calls++
+	return &http.Response{
+		StatusCode: status,
+		Body:       http.NoBody,
+		Header:     make(http.Header),
+	}, nil
+}
+
+func newTestClient(statuses []int) (*Client, *countingRoundTripper) {
+	rt := &countingRoundTripper{statuses: statuses}
+	c := NewClient("test-agent")
+	c.httpClient = &http.Client{Transport: rt}
+	c.retryConfig = RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	return c, rt
+}
+
+func TestMakeConditionalAPIRequestRetriesOnServerError(t *testing.T) {
+	c, rt := newTestClient([]int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK})
+
+	_, _, _, err := c.makeConditionalAPIRequest(context.Background(), "/latest", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rt.calls != 3 {
+		t.Errorf("calls = %d, want 3 (two failures then a success)", rt.calls)
+	}
+}
+
+func TestMakeConditionalAPIRequestDoesNotRetryClientError(t *testing.T) {
+	c, rt := newTestClient([]int{http.StatusNotFound, http.StatusOK})
+
+	_, _, _, err := c.makeConditionalAPIRequest(context.Background(), "/latest", nil, nil)
+	if err == nil {
+		t.Fatal("expected an error for a 404")
+	}
+	if rt.calls != 1 {
+		t.Errorf("calls = %d, want 1 (a 404 should not be retried)", rt.calls)
+	}
+}
+
+func TestMakeConditionalAPIRequestGivesUpAfterMaxRetries(t *testing.T) {
+	c, rt := newTestClient([]int{
+		http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+		http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+	})
+
+	_, _, _, err := c.makeConditionalAPIRequest(context.Background(), "/latest", nil, nil)
+	if err == nil {
+		t.Fatal("expected an error once retries are exhausted")
+	}
+	if rt.calls != c.retryConfig.MaxRetries+1 {
+		t.Errorf("calls = %d, want %d (1 initial + MaxRetries)", rt.calls, c.retryConfig.MaxRetries+1)
+	}
+}
+
+func TestRetryBackoffWithinBounds(t *testing.T) {
+	cfg := RetryConfig{MaxRetries: 5, BaseDelay: 10 * time.Millisecond, MaxDelay: 100 * time.Millisecond}
+	for attempt := 1; attempt <= cfg.MaxRetries; attempt++ {
+		d := retryBackoff(cfg, attempt)
+		if d < 0 || d > cfg.MaxDelay {
+			t.Errorf("retryBackoff(attempt=%d) = %v, want within [0, %v]", attempt, d, cfg.MaxDelay)
+		}
+	}
+}
+
+func TestClientTripsCircuitBreakerOnRepeatedServerErrors(t *testing.T) {
+	statuses := make([]int, circuitBreakerFailureThreshold)
+	for i := range statuses {
+		statuses[i] = http.StatusServiceUnavailable
+	}
+	c, _ := newTestClient(statuses)
+	c.retryConfig = RetryConfig{MaxRetries: 0, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	for i := 0; i < circuitBreakerFailureThreshold; i++ {
+		if _, _, _, err := c.makeConditionalAPIRequest(context.Background(), "/latest", nil, nil); err == nil {
+			t.Fatalf("call %d: expected a server error", i)
+		}
+	}
+
+	stats := c.Stats()
+	if stats.CircuitState != CircuitOpen {
+		t.Errorf("CircuitState = %v, want %v", stats.CircuitState, CircuitOpen)
+	}
+
+	_, _, _, err := c.makeConditionalAPIRequest(context.Background(), "/latest", nil, nil)
+	if err != ErrCircuitOpen {
+		t.Errorf("err = %v, want ErrCircuitOpen", err)
+	}
+	if c.Stats().ThrottledCount != 1 {
+		t.Errorf("ThrottledCount = %d, want 1", c.Stats().ThrottledCount)
+	}
+}
@@ -29,12 +29,14 @@ type DataSource interface {
 // APIDataSource fetches data directly from the OSRS Wiki API.
 type APIDataSource struct {
 	client *Client
+	events *DataSourceEventFeed
 }
 
 // NewAPIDataSource creates a data source that uses the OSRS Wiki API.
 func NewAPIDataSource(userAgent string) *APIDataSource {
 	return &APIDataSource{
 		client: NewClient(userAgent),
+		events: newDataSourceEventFeed(),
 	}
 }
 
@@ -42,9 +44,17 @@ func NewAPIDataSource(userAgent string) *APIDataSource {
 func NewAPIDataSourceWithClient(client *Client) *APIDataSource {
 	return &APIDataSource{
 		client: client,
+		events: newDataSourceEventFeed(),
 	}
 }
 
+// Events exposes volume-fetch summary events (see loadVolumeMetricsFromAPI),
+// published through the same DataSourceEventFeed type MultiProviderDataSource
+// uses for its health-state changes.
+func (s *APIDataSource) Events() (<-chan DataSourceEvent, func()) {
+	return s.events.Subscribe()
+}
+
 func (s *APIDataSource) Name() string {
 	return "OSRS Wiki API"
 }
@@ -56,7 +66,7 @@ func (s *APIDataSource) IsFresh(ctx context.Context) (bool, error) {
 
 func (s *APIDataSource) LoadPrices(ctx context.Context) ([]ItemData, error) {
 	// Get item mappings
-	mappings, err := s.client.GetItemMapping(ctx)
+	mappings, _, _, _, err := s.client.GetItemMapping(ctx, "", "")
 	if err != nil {
 		return nil, fmt.Errorf("getting item mappings: %w", err)
 	}
@@ -100,21 +110,20 @@ func (s *APIDataSource) LoadVolumeData(ctx context.Context, items []ItemData, ma
 }
 
 // loadVolumeMetricsFromAPI fetches volume data for items from the API.
-// This is a simplified version that doesn't use the Analyzer's rate limiter.
+// Requests are paced per-endpoint by s.client's own rate limiter (see
+// Client.rateLimiters), so this doesn't need one of its own.
 func (s *APIDataSource) loadVolumeMetricsFromAPI(ctx context.Context, itemIDs []int) (map[int]VolumeMetrics, error) {
 	fmt.Printf("📈 Fetching volume data for %d items from API...\n", len(itemIDs))
 
-	rateLimiter := NewRateLimiter(2.0)
 	volumeData := make(map[int]VolumeMetrics)
+	statsBefore := s.client.Stats()
+	errCount := 0
 
 	for i, itemID := range itemIDs {
-		if err := rateLimiter.Wait(ctx); err != nil {
-			return volumeData, fmt.Errorf("rate limit wait: %w", err)
-		}
-
 		metrics, err := s.calculateVolumeMetrics(ctx, itemID)
 		if err != nil {
 			fmt.Printf("  ❌ Error fetching data for item %d: %v\n", itemID, err)
+			errCount++
 			continue
 		}
 
@@ -126,6 +135,18 @@ func (s *APIDataSource) loadVolumeMetricsFromAPI(ctx context.Context, itemIDs []
 	}
 
 	fmt.Printf("✅ Successfully enriched %d/%d items with volume data\n", len(volumeData), len(itemIDs))
+
+	if errCount > 0 {
+		statsAfter := s.client.Stats()
+		retriedOK := statsAfter.RetriedSuccessfully - statsBefore.RetriedSuccessfully
+		s.events.publish(DataSourceEvent{
+			Name:          s.Name(),
+			Healthy:       errCount == 0,
+			LastCheckedAt: time.Now(),
+			Message:       fmt.Sprintf("%d items failed volume fetch, %d retried successfully", errCount, retriedOK),
+		})
+	}
+
 	return volumeData, nil
 }
 
@@ -184,7 +205,7 @@ func (s *DBDataSource) LoadPrices(ctx context.Context) ([]ItemData, error) {
 	// Get item mappings (still from API for now - item metadata isn't in DB yet)
 	// TODO: Once item_metadata table exists, query from DB
 	if s.mappingCache == nil {
-		mappings, err := s.mappingClient.GetItemMapping(ctx)
+		mappings, _, _, _, err := s.mappingClient.GetItemMapping(ctx, "", "")
 		if err != nil {
 			return nil, fmt.Errorf("getting item mappings: %w", err)
 		}
@@ -333,6 +354,11 @@ func (s *DBDataSource) LoadVolumeData(ctx context.Context, items []ItemData, max
 type HybridDataSource struct {
 	dbSource  *DBDataSource
 	apiSource *APIDataSource
+
+	// snapshotSource, if set via SetSnapshotSource, is tried between dbSource
+	// and apiSource: a fresh-enough captured/imported snapshot file avoids
+	// hitting the Wiki API at all.
+	snapshotSource *FileDataSource
 }
 
 // NewHybridDataSource creates a data source that tries DB first, then falls back to API.
@@ -343,6 +369,14 @@ func NewHybridDataSource(dbSource *DBDataSource, apiSource *APIDataSource) *Hybr
 	}
 }
 
+// SetSnapshotSource adds a third tier between the DB and the API: a
+// snapshot file LoadPrices/LoadVolumeData try whenever the DB is stale, so
+// long as the snapshot is itself within its own freshness TTL (see
+// FileDataSource.IsFresh).
+func (s *HybridDataSource) SetSnapshotSource(snapshotSource *FileDataSource) {
+	s.snapshotSource = snapshotSource
+}
+
 func (s *HybridDataSource) Name() string {
 	return "Hybrid (DB + API fallback)"
 }
@@ -378,6 +412,17 @@ func (s *HybridDataSource) LoadPrices(ctx context.Context) ([]ItemData, error) {
 		return items, nil
 	}
 
+	if s.snapshotSource != nil {
+		if snapFresh, err := s.snapshotSource.IsFresh(ctx); err == nil && snapFresh {
+			items, err := s.snapshotSource.LoadPrices(ctx)
+			if err == nil {
+				fmt.Printf("✅ Loaded %d items from snapshot file\n", len(items))
+				return items, nil
+			}
+			fmt.Printf("Warning: snapshot load failed: %v, falling back to API\n", err)
+		}
+	}
+
 	fmt.Println("Local data is stale, fetching from API...")
 	return s.apiSource.LoadPrices(ctx)
 }
@@ -399,6 +444,14 @@ func (s *HybridDataSource) LoadVolumeData(ctx context.Context, items []ItemData,
 		return nil
 	}
 
+	if s.snapshotSource != nil {
+		if snapFresh, err := s.snapshotSource.IsFresh(ctx); err == nil && snapFresh {
+			if err := s.snapshotSource.LoadVolumeData(ctx, items, maxItems); err == nil {
+				return nil
+			}
+		}
+	}
+
 	return s.apiSource.LoadVolumeData(ctx, items, maxItems)
 }
 
@@ -501,6 +554,19 @@ func applyVolumeMetrics(items []ItemData, metrics map[int]VolumeMetrics) {
 		item.InstaBuyPriceTrend1w = &m.InstaBuyPriceTrend1w
 		item.InstaSellPriceTrend1m = &m.InstaSellPriceTrend1m
 		item.InstaBuyPriceTrend1m = &m.InstaBuyPriceTrend1m
+		item.TrendStrength = &m.TrendStrength
+
+		if m.WaveTrend24h != "" {
+			item.ATR24h = &m.ATR24h
+			item.WaveTrend24h = &m.WaveTrend24h
+		}
+
+		item.AvgTrueRange1h = &m.AvgTrueRange1h
+		item.AvgTrueRange24h = &m.AvgTrueRange24h
+		if m.AvgTrueRange1h > 0 {
+			riskAdjusted := float64(item.MarginGP) / m.AvgTrueRange1h
+			item.RiskAdjustedEfficiency = &riskAdjusted
+		}
 	}
 }
 
@@ -537,6 +603,10 @@ func process5mData(dataSlice []interface{}, metrics VolumeMetrics) VolumeMetrics
 		instaBuyVol24h, instaSellVol24h float64
 		timestamps1h, instaBuyPrices1h, instaSellPrices1h   []float64
 		timestamps24h, instaBuyPrices24h, instaSellPrices24h []float64
+
+		// lowPrices1h/24h shadow instaBuyPrices1h/24h index-for-index (see
+		// calculate5mMetrics' identical arrays in volume.go).
+		lowPrices1h, lowPrices24h []float64
 	)
 
 	for _, item := range dataSlice {
@@ -573,6 +643,11 @@ func process5mData(dataSlice []interface{}, metrics VolumeMetrics) VolumeMetrics
 					instaBuy1h = append(instaBuy1h, avgHigh)
 					timestamps1h = append(timestamps1h, float64(timestamp))
 					instaBuyPrices1h = append(instaBuyPrices1h, avgHigh)
+					low := avgLow
+					if low <= 0 {
+						low = avgHigh
+					}
+					lowPrices1h = append(lowPrices1h, low)
 				}
 				if avgLow > 0 {
 					instaSell1h = append(instaSell1h, avgLow)
@@ -587,6 +662,11 @@ func process5mData(dataSlice []interface{}, metrics VolumeMetrics) VolumeMetrics
 					instaBuy24h = append(instaBuy24h, avgHigh)
 					timestamps24h = append(timestamps24h, float64(timestamp))
 					instaBuyPrices24h = append(instaBuyPrices24h, avgHigh)
+					low := avgLow
+					if low <= 0 {
+						low = avgHigh
+					}
+					lowPrices24h = append(lowPrices24h, low)
 				}
 				if avgLow > 0 {
 					instaSell24h = append(instaSell24h, avgLow)
@@ -629,28 +709,37 @@ func process5mData(dataSlice []interface{}, metrics VolumeMetrics) VolumeMetrics
 	metrics.InstaSellVolume24h = instaSellVol24h
 	metrics.AvgMarginGP24h = metrics.AvgInstaBuyPrice24h - metrics.AvgInstaSellPrice24h
 
-	// Calculate trends
+	// Calculate trends using the EMA crossover (see calculateTrend).
+	// InstaBuyPriceTrend1h is the primary signal surfaced downstream, so
+	// its crossover strength is also recorded on TrendStrength.
+	indicatorConfig := DefaultIndicatorConfig()
 	if len(instaBuyPrices1h) >= 3 {
-		metrics.InstaBuyPriceTrend1h = calculateTrend(timestamps1h, instaBuyPrices1h)
+		metrics.InstaBuyPriceTrend1h, metrics.TrendStrength = calculateTrend(timestamps1h, instaBuyPrices1h, indicatorConfig)
 	} else {
 		metrics.InstaBuyPriceTrend1h = "flat"
 	}
 	if len(instaSellPrices1h) >= 3 {
-		metrics.InstaSellPriceTrend1h = calculateTrend(timestamps1h, instaSellPrices1h)
+		metrics.InstaSellPriceTrend1h, _ = calculateTrend(timestamps1h, instaSellPrices1h, indicatorConfig)
 	} else {
 		metrics.InstaSellPriceTrend1h = "flat"
 	}
 	if len(instaBuyPrices24h) >= 3 {
-		metrics.InstaBuyPriceTrend24h = calculateTrend(timestamps24h, instaBuyPrices24h)
+		metrics.InstaBuyPriceTrend24h, _ = calculateTrend(timestamps24h, instaBuyPrices24h, indicatorConfig)
 	} else {
 		metrics.InstaBuyPriceTrend24h = "flat"
 	}
 	if len(instaSellPrices24h) >= 3 {
-		metrics.InstaSellPriceTrend24h = calculateTrend(timestamps24h, instaSellPrices24h)
+		metrics.InstaSellPriceTrend24h, _ = calculateTrend(timestamps24h, instaSellPrices24h, indicatorConfig)
 	} else {
 		metrics.InstaSellPriceTrend24h = "flat"
 	}
 
+	metrics = applyATRTrend(metrics, instaBuyPrices24h, instaSellPrices24h)
+
+	// Bucketed ATR (see VolumeMetrics.AvgTrueRange1h).
+	metrics.AvgTrueRange1h = ATR(instaBuyPrices1h, lowPrices1h, instaBuyPrices1h, indicatorConfig.ATRWindow)
+	metrics.AvgTrueRange24h = ATR(instaBuyPrices24h, lowPrices24h, instaBuyPrices24h, indicatorConfig.ATRWindow)
+
 	return metrics
 }
 
@@ -698,24 +787,25 @@ func process24hData(dataSlice []interface{}, metrics VolumeMetrics) VolumeMetric
 		}
 	}
 
-	// Calculate trends
+	// Calculate trends using the EMA crossover (see calculateTrend).
+	indicatorConfig := DefaultIndicatorConfig()
 	if len(instaBuyPrices1w) >= 3 {
-		metrics.InstaBuyPriceTrend1w = calculateTrend(timestamps1w, instaBuyPrices1w)
+		metrics.InstaBuyPriceTrend1w, _ = calculateTrend(timestamps1w, instaBuyPrices1w, indicatorConfig)
 	} else {
 		metrics.InstaBuyPriceTrend1w = "flat"
 	}
 	if len(instaSellPrices1w) >= 3 {
-		metrics.InstaSellPriceTrend1w = calculateTrend(timestamps1w, instaSellPrices1w)
+		metrics.InstaSellPriceTrend1w, _ = calculateTrend(timestamps1w, instaSellPrices1w, indicatorConfig)
 	} else {
 		metrics.InstaSellPriceTrend1w = "flat"
 	}
 	if len(instaBuyPrices1m) >= 3 {
-		metrics.InstaBuyPriceTrend1m = calculateTrend(timestamps1m, instaBuyPrices1m)
+		metrics.InstaBuyPriceTrend1m, _ = calculateTrend(timestamps1m, instaBuyPrices1m, indicatorConfig)
 	} else {
 		metrics.InstaBuyPriceTrend1m = "flat"
 	}
 	if len(instaSellPrices1m) >= 3 {
-		metrics.InstaSellPriceTrend1m = calculateTrend(timestamps1m, instaSellPrices1m)
+		metrics.InstaSellPriceTrend1m, _ = calculateTrend(timestamps1m, instaSellPrices1m, indicatorConfig)
 	} else {
 		metrics.InstaSellPriceTrend1m = "flat"
 	}
@@ -0,0 +1,270 @@
+package osrs
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// NoteKind identifies the kind of data-quality issue a Note records.
+type NoteKind string
+
+const (
+	// NoteVolumeReset flags a bucket whose combined volume drops to zero
+	// mid-series, between two traded buckets -- the Wiki API's signature of
+	// a post-update wipe or a thin-liquidity tick.
+	NoteVolumeReset NoteKind = "volume_reset"
+	// NoteOutlier flags an avgHigh/avgLow price more than
+	// DataQualityConfig.MADThreshold median-absolute-deviations from the
+	// median of the whole series (not a rolling window).
+	NoteOutlier NoteKind = "price_outlier"
+	// NoteGap flags a step between consecutive timestamps more than
+	// DataQualityConfig.GapMultiple times the expected bucket interval.
+	NoteGap NoteKind = "timestamp_gap"
+)
+
+// Note records a single data-quality observation surfaced while parsing a
+// timeseries, so callers can see *why* a metric was adjusted (or left
+// alone) instead of bad ticks being silently absorbed into VWAPs and
+// averages. See VolumeMetrics.Notes.
+type Note struct {
+	Kind      NoteKind
+	Timestamp time.Time
+	Message   string
+}
+
+// DataQualityConfig tunes detectDataQualityNotes and the optional
+// sanitizeSeriesPoints pass calculate5mMetrics/calculate24hMetrics apply
+// before computing VWAPs and averages.
+type DataQualityConfig struct {
+	// MADThreshold is how many median-absolute-deviations a price bucket
+	// may sit from the median of the whole series (not a rolling window)
+	// before it's flagged as an outlier. Zero disables outlier detection entirely.
+	MADThreshold float64
+	// GapMultiple is how many multiples of the expected bucket step a gap
+	// between consecutive timestamps must exceed before it's flagged. Zero
+	// disables gap detection entirely.
+	GapMultiple float64
+	// Sanitize, when true, makes calculate5mMetrics/calculate24hMetrics
+	// winsorize outlier prices to the series median and zero out
+	// volume-reset buckets before accumulating VWAPs and averages. When
+	// false (the default), buckets are left untouched and Notes are purely
+	// informational.
+	Sanitize bool
+}
+
+// DefaultDataQualityConfig returns the default diagnostics tuning: outliers
+// flagged past 5 median-absolute-deviations, gaps flagged past 2x the
+// expected step, and no automatic sanitization.
+func DefaultDataQualityConfig() DataQualityConfig {
+	return DataQualityConfig{
+		MADThreshold: 5.0,
+		GapMultiple:  2.0,
+		Sanitize:     false,
+	}
+}
+
+// seriesPoint is the minimal per-bucket shape parseSeriesPoints,
+// detectDataQualityNotes, and sanitizeSeriesPoints work over.
+type seriesPoint struct {
+	Timestamp int64
+	AvgHigh   float64
+	AvgLow    float64
+	HighVol   float64
+	LowVol    float64
+}
+
+// parseSeriesPoints extracts the timestamp/price/volume fields
+// calculate5mMetrics/calculate24hMetrics need out of a raw Wiki API "data"
+// slice, skipping any entry that isn't the expected map shape.
+func parseSeriesPoints(dataSlice []interface{}) []seriesPoint {
+	points := make([]seriesPoint, 0, len(dataSlice))
+	for _, item := range dataSlice {
+		raw, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		p := seriesPoint{Timestamp: int64(raw["timestamp"].(float64))}
+		if val, exists := raw["avgHighPrice"]; exists && val != nil {
+			p.AvgHigh = val.(float64)
+		}
+		if val, exists := raw["avgLowPrice"]; exists && val != nil {
+			p.AvgLow = val.(float64)
+		}
+		if val, exists := raw["highPriceVolume"]; exists && val != nil {
+			p.HighVol = val.(float64)
+		}
+		if val, exists := raw["lowPriceVolume"]; exists && val != nil {
+			p.LowVol = val.(float64)
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// detectDataQualityNotes scans points (assumed timestamp-ascending) for
+// volume resets, price outliers, and oversized gaps -- see NoteVolumeReset,
+// NoteOutlier, and NoteGap. Returns notes timestamp-ascending.
+func detectDataQualityNotes(points []seriesPoint, expectedStep time.Duration, cfg DataQualityConfig) []Note {
+	if len(points) == 0 {
+		return nil
+	}
+
+	var notes []Note
+	for i, p := range points {
+		if i > 0 && i < len(points)-1 {
+			combinedVol := p.HighVol + p.LowVol
+			prevVol := points[i-1].HighVol + points[i-1].LowVol
+			nextVol := points[i+1].HighVol + points[i+1].LowVol
+			if combinedVol == 0 && prevVol > 0 && nextVol > 0 {
+				notes = append(notes, Note{
+					Kind:      NoteVolumeReset,
+					Timestamp: time.Unix(p.Timestamp, 0).UTC(),
+					Message:   "volume dropped to zero mid-series between two traded buckets",
+				})
+			}
+		}
+
+		if i > 0 && cfg.GapMultiple > 0 && expectedStep > 0 {
+			step := time.Duration(p.Timestamp-points[i-1].Timestamp) * time.Second
+			if step > time.Duration(cfg.GapMultiple*float64(expectedStep)) {
+				notes = append(notes, Note{
+					Kind:      NoteGap,
+					Timestamp: time.Unix(p.Timestamp, 0).UTC(),
+					Message:   fmt.Sprintf("gap of %s since the previous bucket, expected ~%s", step, expectedStep),
+				})
+			}
+		}
+	}
+
+	notes = append(notes, detectPriceOutliers(points, cfg.MADThreshold)...)
+
+	sort.Slice(notes, func(i, j int) bool { return notes[i].Timestamp.Before(notes[j].Timestamp) })
+	return notes
+}
+
+// detectPriceOutliers flags avgHigh/avgLow values more than madThreshold
+// median-absolute-deviations from their own side's series-wide median,
+// computed separately for avgHigh and avgLow since buy and sell prices can
+// differ systematically. Zero prices (a quiet bucket, not an outlier) are
+// excluded from both the median/MAD computation and the scan.
+func detectPriceOutliers(points []seriesPoint, madThreshold float64) []Note {
+	if madThreshold <= 0 {
+		return nil
+	}
+
+	highs := make([]float64, 0, len(points))
+	lows := make([]float64, 0, len(points))
+	for _, p := range points {
+		if p.AvgHigh > 0 {
+			highs = append(highs, p.AvgHigh)
+		}
+		if p.AvgLow > 0 {
+			lows = append(lows, p.AvgLow)
+		}
+	}
+	medHigh, madHigh := median(highs), medianAbsoluteDeviation(highs)
+	medLow, madLow := median(lows), medianAbsoluteDeviation(lows)
+
+	var notes []Note
+	for _, p := range points {
+		if madHigh > 0 && p.AvgHigh > 0 {
+			if deviation := math.Abs(p.AvgHigh-medHigh) / madHigh; deviation > madThreshold {
+				notes = append(notes, Note{
+					Kind:      NoteOutlier,
+					Timestamp: time.Unix(p.Timestamp, 0).UTC(),
+					Message:   fmt.Sprintf("avgHighPrice %.0f is %.1f MADs from the series median %.0f", p.AvgHigh, deviation, medHigh),
+				})
+			}
+		}
+		if madLow > 0 && p.AvgLow > 0 {
+			if deviation := math.Abs(p.AvgLow-medLow) / madLow; deviation > madThreshold {
+				notes = append(notes, Note{
+					Kind:      NoteOutlier,
+					Timestamp: time.Unix(p.Timestamp, 0).UTC(),
+					Message:   fmt.Sprintf("avgLowPrice %.0f is %.1f MADs from the series median %.0f", p.AvgLow, deviation, medLow),
+				})
+			}
+		}
+	}
+	return notes
+}
+
+// median returns the middle value of a sorted copy of values, averaging the
+// two middle values for an even-length slice. Returns 0 for an empty slice.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// medianAbsoluteDeviation returns the median of |v - median(values)| across
+// values, the scale estimate detectPriceOutliers divides each deviation by.
+func medianAbsoluteDeviation(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	med := median(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - med)
+	}
+	return median(deviations)
+}
+
+// sanitizeSeriesPoints returns a copy of points with volume-reset buckets
+// zeroed and outlier prices winsorized to their side's series median, per
+// cfg's thresholds (the same checks detectDataQualityNotes runs). Gap notes
+// don't change any bucket's values, since a gap isn't a bad value -- it's a
+// missing one.
+func sanitizeSeriesPoints(points []seriesPoint, cfg DataQualityConfig) []seriesPoint {
+	sanitized := make([]seriesPoint, len(points))
+	copy(sanitized, points)
+
+	for i := range sanitized {
+		if i > 0 && i < len(sanitized)-1 {
+			combinedVol := sanitized[i].HighVol + sanitized[i].LowVol
+			prevVol := sanitized[i-1].HighVol + sanitized[i-1].LowVol
+			nextVol := sanitized[i+1].HighVol + sanitized[i+1].LowVol
+			if combinedVol == 0 && prevVol > 0 && nextVol > 0 {
+				sanitized[i].HighVol = 0
+				sanitized[i].LowVol = 0
+			}
+		}
+	}
+
+	if cfg.MADThreshold > 0 {
+		highs := make([]float64, 0, len(sanitized))
+		lows := make([]float64, 0, len(sanitized))
+		for _, p := range sanitized {
+			if p.AvgHigh > 0 {
+				highs = append(highs, p.AvgHigh)
+			}
+			if p.AvgLow > 0 {
+				lows = append(lows, p.AvgLow)
+			}
+		}
+		medHigh, madHigh := median(highs), medianAbsoluteDeviation(highs)
+		medLow, madLow := median(lows), medianAbsoluteDeviation(lows)
+
+		for i, p := range sanitized {
+			if madHigh > 0 && p.AvgHigh > 0 && math.Abs(p.AvgHigh-medHigh)/madHigh > cfg.MADThreshold {
+				sanitized[i].AvgHigh = medHigh
+			}
+			if madLow > 0 && p.AvgLow > 0 && math.Abs(p.AvgLow-medLow)/madLow > cfg.MADThreshold {
+				sanitized[i].AvgLow = medLow
+			}
+		}
+	}
+
+	return sanitized
+}
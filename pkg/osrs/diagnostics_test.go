@@ -0,0 +1,165 @@
+package osrs
+
+import (
+	"testing"
+	"time"
+)
+
+func makeSeriesPoints(baseTimestamp int64, stepSeconds int64, highs, lows, highVols, lowVols []float64) []seriesPoint {
+	points := make([]seriesPoint, len(highs))
+	for i := range highs {
+		points[i] = seriesPoint{
+			Timestamp: baseTimestamp + int64(i)*stepSeconds,
+			AvgHigh:   highs[i],
+			AvgLow:    lows[i],
+			HighVol:   highVols[i],
+			LowVol:    lowVols[i],
+		}
+	}
+	return points
+}
+
+func TestDetectDataQualityNotesVolumeReset(t *testing.T) {
+	cfg := DefaultDataQualityConfig()
+	points := makeSeriesPoints(1000, 300,
+		[]float64{100, 100, 100, 100},
+		[]float64{90, 90, 90, 90},
+		[]float64{50, 0, 60, 55},
+		[]float64{40, 0, 45, 42},
+	)
+
+	notes := detectDataQualityNotes(points, 5*time.Minute, cfg)
+
+	var resets int
+	for _, n := range notes {
+		if n.Kind == NoteVolumeReset {
+			resets++
+			if !n.Timestamp.Equal(time.Unix(1300, 0).UTC()) {
+				t.Errorf("reset note timestamp = %v, want the mid-series bucket", n.Timestamp)
+			}
+		}
+	}
+	if resets != 1 {
+		t.Errorf("expected exactly 1 volume reset note, got %d", resets)
+	}
+}
+
+func TestDetectDataQualityNotesVolumeResetIgnoresLeadingTrailingZero(t *testing.T) {
+	cfg := DefaultDataQualityConfig()
+	// A zero-volume bucket at either end isn't a "reset" -- there's no
+	// traded bucket on both sides of it.
+	points := makeSeriesPoints(1000, 300,
+		[]float64{100, 100, 100},
+		[]float64{90, 90, 90},
+		[]float64{0, 50, 60},
+		[]float64{0, 40, 45},
+	)
+
+	notes := detectDataQualityNotes(points, 5*time.Minute, cfg)
+	for _, n := range notes {
+		if n.Kind == NoteVolumeReset {
+			t.Errorf("did not expect a volume reset note for a leading zero-volume bucket, got %+v", n)
+		}
+	}
+}
+
+func TestDetectDataQualityNotesPriceOutlier(t *testing.T) {
+	cfg := DefaultDataQualityConfig()
+	highs := []float64{1000, 1005, 995, 1010, 990, 1000, 5000} // last bucket spikes to 5000
+	lows := []float64{900, 905, 895, 910, 890, 900, 900}
+	vols := make([]float64, len(highs))
+	for i := range vols {
+		vols[i] = 10
+	}
+
+	points := makeSeriesPoints(1000, 300, highs, lows, vols, vols)
+	notes := detectDataQualityNotes(points, 5*time.Minute, cfg)
+
+	var found bool
+	spikeTimestamp := time.Unix(1000+6*300, 0).UTC()
+	for _, n := range notes {
+		if n.Kind == NoteOutlier && n.Timestamp.Equal(spikeTimestamp) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a price outlier note for the injected spike, got %+v", notes)
+	}
+}
+
+func TestDetectDataQualityNotesGap(t *testing.T) {
+	cfg := DefaultDataQualityConfig()
+	points := []seriesPoint{
+		{Timestamp: 1000, AvgHigh: 100, AvgLow: 90, HighVol: 10, LowVol: 10},
+		{Timestamp: 1300, AvgHigh: 100, AvgLow: 90, HighVol: 10, LowVol: 10},
+		{Timestamp: 4000, AvgHigh: 100, AvgLow: 90, HighVol: 10, LowVol: 10}, // ~45 minute gap, vs a 5m step
+	}
+
+	notes := detectDataQualityNotes(points, 5*time.Minute, cfg)
+
+	var found bool
+	for _, n := range notes {
+		if n.Kind == NoteGap && n.Timestamp.Equal(time.Unix(4000, 0).UTC()) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a gap note for the injected 45-minute gap, got %+v", notes)
+	}
+}
+
+func TestDetectDataQualityNotesDisabledThresholds(t *testing.T) {
+	cfg := DataQualityConfig{} // zero value: outlier/gap detection both off
+	highs := []float64{1000, 1000, 1000, 5000}
+	lows := []float64{900, 900, 900, 900}
+	vols := []float64{10, 10, 10, 10}
+
+	points := makeSeriesPoints(1000, 300, highs, lows, vols, vols)
+	notes := detectDataQualityNotes(points, 5*time.Minute, cfg)
+
+	for _, n := range notes {
+		if n.Kind == NoteOutlier || n.Kind == NoteGap {
+			t.Errorf("expected outlier/gap detection disabled at zero thresholds, got %+v", n)
+		}
+	}
+}
+
+func TestSanitizeSeriesPointsWinsorizesAndZeroes(t *testing.T) {
+	cfg := DefaultDataQualityConfig()
+	cfg.Sanitize = true
+
+	points := makeSeriesPoints(1000, 300,
+		[]float64{1000, 1000, 1000, 5000},
+		[]float64{900, 900, 900, 900},
+		[]float64{50, 0, 60, 55},
+		[]float64{40, 0, 45, 42},
+	)
+
+	sanitized := sanitizeSeriesPoints(points, cfg)
+
+	if sanitized[1].HighVol != 0 || sanitized[1].LowVol != 0 {
+		t.Errorf("expected the reset bucket's volume to stay zeroed, got %+v", sanitized[1])
+	}
+	if sanitized[3].AvgHigh == 5000 {
+		t.Error("expected the outlier price to be winsorized to the series median")
+	}
+	if sanitized[0].AvgHigh != 1000 {
+		t.Errorf("expected non-outlier buckets to be left untouched, got %v", sanitized[0].AvgHigh)
+	}
+}
+
+func TestMedianAndMAD(t *testing.T) {
+	if got := median([]float64{1, 2, 3}); got != 2 {
+		t.Errorf("median of odd-length slice = %v, want 2", got)
+	}
+	if got := median([]float64{1, 2, 3, 4}); got != 2.5 {
+		t.Errorf("median of even-length slice = %v, want 2.5", got)
+	}
+	if got := median(nil); got != 0 {
+		t.Errorf("median of empty slice = %v, want 0", got)
+	}
+
+	if got := medianAbsoluteDeviation([]float64{1, 1, 1, 1}); got != 0 {
+		t.Errorf("MAD of a constant series = %v, want 0", got)
+	}
+}
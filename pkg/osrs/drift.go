@@ -0,0 +1,105 @@
+package osrs
+
+import (
+	"math"
+
+	"osrs-flipping/pkg/osrs/indicator"
+)
+
+// driftEpsilon keeps driftMA's Fisher transform input inside (-1, 1),
+// mirroring fisherTransform's clamp but phrased as a one-sided epsilon per
+// the chunk9-1 request.
+const driftEpsilon = 0.001
+
+// logReturns converts a price series into r_i = log(p_i/p_{i-1}). Points
+// where either price is non-positive contribute a 0 return rather than a
+// NaN/Inf, since the API occasionally reports a zero price for a quiet
+// bucket.
+func logReturns(prices []float64) []float64 {
+	if len(prices) < 2 {
+		return nil
+	}
+	returns := make([]float64, 0, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		if prices[i-1] <= 0 || prices[i] <= 0 {
+			returns = append(returns, 0)
+			continue
+		}
+		returns = append(returns, math.Log(prices[i]/prices[i-1]))
+	}
+	return returns
+}
+
+// wmaSeries runs values through a streaming indicator.WMA of the given
+// period and returns the resulting series, one output per input.
+func wmaSeries(values []float64, period int) []float64 {
+	w := indicator.NewWMA(period)
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = w.Update(v)
+	}
+	return out
+}
+
+// driftMA computes a sortable momentum score for prices: a WMA-smoothed
+// log-return series, rescaled via a rolling min/max over fisherWindow
+// points into (-1, 1) and Fisher-transformed for a sharper, more Gaussian
+// signal, then WMA-smoothed a second time. This replaces calculateTrend's
+// coarse "increasing"/"decreasing"/"flat" buckets with a continuous value;
+// see DriftLabel for a derived bucket-string view when one is still wanted.
+// Returns 0 if prices has fewer than fisherWindow+1 points.
+func driftMA(prices []float64, fisherWindow int) float64 {
+	if fisherWindow <= 0 {
+		return 0
+	}
+
+	returns := logReturns(prices)
+	if len(returns) < fisherWindow {
+		return 0
+	}
+
+	smoothed := wmaSeries(returns, fisherWindow)
+
+	fisherSeries := make([]float64, len(smoothed))
+	for i := range smoothed {
+		lo := i - fisherWindow + 1
+		if lo < 0 {
+			lo = 0
+		}
+		window := smoothed[lo : i+1]
+
+		min, max := window[0], window[0]
+		for _, v := range window {
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+
+		var x float64
+		if max > min {
+			x = 2*((smoothed[i]-min)/(max-min)) - 1
+		}
+		x = math.Max(-1+driftEpsilon, math.Min(1-driftEpsilon, x))
+		fisherSeries[i] = 0.5 * math.Log((1+x)/(1-x))
+	}
+
+	drift := wmaSeries(fisherSeries, fisherWindow)
+	return drift[len(drift)-1]
+}
+
+// DriftLabel thresholds a VolumeMetrics/ItemData Drift* value into the same
+// "increasing"/"decreasing"/"flat" buckets calculateTrend used to produce
+// directly, for callers that still want a label rather than the raw score.
+func DriftLabel(drift, threshold float64) string {
+	switch {
+	case drift > threshold:
+		return "increasing"
+	case drift < -threshold:
+		return "decreasing"
+	default:
+		return "flat"
+	}
+}
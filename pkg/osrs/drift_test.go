@@ -0,0 +1,61 @@
+package osrs
+
+import "testing"
+
+func TestDriftMA(t *testing.T) {
+	t.Run("too short for window returns 0", func(t *testing.T) {
+		if got := driftMA([]float64{100, 101, 102}, 5); got != 0 {
+			t.Errorf("driftMA = %v, want 0", got)
+		}
+	})
+
+	t.Run("flat series returns 0", func(t *testing.T) {
+		if got := driftMA(repeat(100, 20), 5); got != 0 {
+			t.Errorf("driftMA = %v, want 0 for a constant series", got)
+		}
+	})
+
+	t.Run("accelerating rise drifts positive", func(t *testing.T) {
+		prices := make([]float64, 30)
+		prices[0] = 100
+		for i := 1; i < len(prices); i++ {
+			prices[i] = prices[i-1] * (1 + 0.001*float64(i))
+		}
+		if got := driftMA(prices, 5); got <= 0 {
+			t.Errorf("driftMA = %v, want > 0 for a rise whose per-step return keeps growing", got)
+		}
+	})
+
+	t.Run("accelerating decline drifts negative", func(t *testing.T) {
+		prices := make([]float64, 30)
+		prices[0] = 100
+		for i := 1; i < len(prices); i++ {
+			prices[i] = prices[i-1] * (1 - 0.001*float64(i))
+		}
+		if got := driftMA(prices, 5); got >= 0 {
+			t.Errorf("driftMA = %v, want < 0 for a decline whose per-step return keeps falling", got)
+		}
+	})
+}
+
+func TestDriftLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		drift     float64
+		threshold float64
+		expected  string
+	}{
+		{"above threshold", 0.1, 0.05, "increasing"},
+		{"below negative threshold", -0.1, 0.05, "decreasing"},
+		{"within threshold", 0.01, 0.05, "flat"},
+		{"exactly at threshold is flat", 0.05, 0.05, "flat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DriftLabel(tt.drift, tt.threshold); got != tt.expected {
+				t.Errorf("DriftLabel(%v, %v) = %q, want %q", tt.drift, tt.threshold, got, tt.expected)
+			}
+		})
+	}
+}
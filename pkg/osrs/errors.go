@@ -0,0 +1,81 @@
+package osrs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+)
+
+// ErrorClass categorizes an API failure so callers (e.g. BackgroundSync) can
+// decide whether to retry, back off, or give up, based on the status code
+// APIError carries rather than matching error strings.
+type ErrorClass string
+
+const (
+	ErrorClassRateLimit   ErrorClass = "rate_limit"
+	ErrorClassServerError ErrorClass = "server_error"
+	ErrorClassNetwork     ErrorClass = "network"
+	ErrorClassClientError ErrorClass = "client_error"
+	ErrorClassUnknown     ErrorClass = "unknown"
+)
+
+// APIError is returned by Client's request methods (via makeAPIRequest) when
+// the wiki API responds with a non-200 status, carrying enough detail for
+// ClassifyError to categorize the failure per endpoint.
+type APIError struct {
+	Endpoint   string
+	StatusCode int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API returned status %d for %s", e.StatusCode, e.Endpoint)
+}
+
+// ClassifyError categorizes err for retry/backoff decisions:
+//   - ErrorClassRateLimit: a 429 from the API
+//   - ErrorClassServerError: a 5xx from the API, usually transient
+//   - ErrorClassClientError: any other 4xx, a permanent failure not worth retrying
+//   - ErrorClassNetwork: a transport-level failure (timeout, connection refused, DNS)
+//   - ErrorClassUnknown: anything else
+//
+// A nil err classifies as "".
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == 429:
+			return ErrorClassRateLimit
+		case apiErr.StatusCode >= 500:
+			return ErrorClassServerError
+		case apiErr.StatusCode >= 400:
+			return ErrorClassClientError
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrorClassNetwork
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return ErrorClassNetwork
+	}
+
+	return ErrorClassUnknown
+}
+
+// Retryable reports whether a failure of this class is worth retrying with
+// backoff. Permanent client errors and unclassified failures are not.
+func (c ErrorClass) Retryable() bool {
+	switch c {
+	case ErrorClassRateLimit, ErrorClassServerError, ErrorClassNetwork:
+		return true
+	default:
+		return false
+	}
+}
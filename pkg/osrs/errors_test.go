@@ -0,0 +1,46 @@
+package osrs
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{"nil", nil, ""},
+		{"rate_limit", &APIError{Endpoint: "/latest", StatusCode: 429}, ErrorClassRateLimit},
+		{"server_error", &APIError{Endpoint: "/latest", StatusCode: 503}, ErrorClassServerError},
+		{"client_error", &APIError{Endpoint: "/latest", StatusCode: 404}, ErrorClassClientError},
+		{"network", &net.DNSError{IsTimeout: true}, ErrorClassNetwork},
+		{"unknown", errors.New("boom"), ErrorClassUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.want {
+				t.Errorf("ClassifyError(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorClassRetryable(t *testing.T) {
+	retryable := []ErrorClass{ErrorClassRateLimit, ErrorClassServerError, ErrorClassNetwork}
+	for _, c := range retryable {
+		if !c.Retryable() {
+			t.Errorf("%q should be retryable", c)
+		}
+	}
+
+	permanent := []ErrorClass{ErrorClassClientError, ErrorClassUnknown}
+	for _, c := range permanent {
+		if c.Retryable() {
+			t.Errorf("%q should not be retryable", c)
+		}
+	}
+}
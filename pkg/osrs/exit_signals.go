@@ -0,0 +1,123 @@
+package osrs
+
+import (
+	"time"
+
+	"osrs-flipping/pkg/osrs/indicator"
+	"osrs-flipping/pkg/osrs/position"
+	"osrs-flipping/pkg/osrs/store"
+)
+
+// EvaluateExitSignals checks each open position's current insta-buy price
+// (what selling it would fetch) against cfg's trailing-stop ladder, stop
+// loss, take-profit, and staleness thresholds, returning one ExitSignal per
+// position that has tripped a threshold. Positions for items with no
+// current price data are skipped -- a cold analyzer run with no loaded
+// items yields no signals rather than false positives.
+func (a *Analyzer) EvaluateExitSignals(positions []position.Position, cfg position.ExitConfig) []position.ExitSignal {
+	itemsByID := make(map[int]ItemData, len(a.items))
+	for _, item := range a.GetData() {
+		itemsByID[item.ItemID] = item
+	}
+
+	var signals []position.ExitSignal
+	for _, p := range positions {
+		item, ok := itemsByID[p.ItemID]
+		if !ok || item.InstaBuyPrice == nil {
+			continue
+		}
+
+		if reason, suggested, triggered := a.checkExitSignal(p, item, cfg); triggered {
+			signals = append(signals, position.ExitSignal{
+				ItemID:             p.ItemID,
+				Name:               p.Name,
+				Quantity:           p.Quantity,
+				Reason:             reason,
+				SuggestedSellPrice: suggested,
+			})
+		}
+	}
+	return signals
+}
+
+// checkExitSignal evaluates a single position against cfg, in priority
+// order: stop loss (a hard risk floor) first, then the trailing ladder,
+// then take profit, then staleness. Only the first threshold tripped is
+// reported -- a position can only be sold once.
+func (a *Analyzer) checkExitSignal(p position.Position, item ItemData, cfg position.ExitConfig) (position.Reason, int, bool) {
+	current := float64(*item.InstaBuyPrice)
+	buyPrice := float64(p.BuyPrice)
+	gain := (current - buyPrice) / buyPrice
+
+	if cfg.StopLossPct > 0 && gain <= -cfg.StopLossPct {
+		return position.ReasonStopLoss, int(current), true
+	}
+
+	if peak := a.peakPriceSince(p.ItemID, p.BuyTime, current, buyPrice); trailingTriggered(buyPrice, peak, current, cfg.TrailingActivationRatio, cfg.TrailingCallbackRate) {
+		return position.ReasonTrailing, int(current), true
+	}
+
+	if cfg.TakeProfitFactor > 0 {
+		if marginATR := a.marginATR(p.ItemID); marginATR > 0 && current-buyPrice >= cfg.TakeProfitFactor*marginATR {
+			return position.ReasonTakeProfit, int(current), true
+		}
+	}
+
+	if cfg.StaleAfter > 0 && time.Since(p.BuyTime) >= cfg.StaleAfter {
+		return position.ReasonStale, int(current), true
+	}
+
+	return "", 0, false
+}
+
+// peakPriceSince returns the highest insta-buy price observed for itemID
+// since since, from the analyzer's rolling 1h store plus the current
+// price, falling back to buyPrice if there's no history yet.
+func (a *Analyzer) peakPriceSince(itemID int, since time.Time, current, buyPrice float64) float64 {
+	peak := buyPrice
+	if current > peak {
+		peak = current
+	}
+	for _, dp := range a.store.GetSeries(itemID, store.Interval1h, since) {
+		if float64(dp.InstaBuyPrice) > peak {
+			peak = float64(dp.InstaBuyPrice)
+		}
+	}
+	return peak
+}
+
+// trailingTriggered reports whether price has pulled back from peak by the
+// callback rate of the highest activation tier peak has reached above
+// buyPrice. Tiers are checked from the tightest (highest activation)
+// downward so a price that blew through every tier still uses its
+// tightest, most appropriate callback.
+func trailingTriggered(buyPrice, peak, current float64, activation, callback []float64) bool {
+	if len(activation) == 0 || len(activation) != len(callback) {
+		return false
+	}
+	for i := len(activation) - 1; i >= 0; i-- {
+		if peak >= buyPrice*(1+activation[i]) {
+			return current <= peak*(1-callback[i])
+		}
+	}
+	return false
+}
+
+// marginATR computes the item's margin volatility (insta-buy minus
+// insta-sell, true-range smoothed) over its full 24h store history, for
+// comparison against TakeProfitFactor. Fewer than two ticks of history
+// yields zero, which disables the take-profit check for that item.
+func (a *Analyzer) marginATR(itemID int) float64 {
+	series := a.store.GetSeries(itemID, store.Interval24h, time.Time{})
+	if len(series) < 2 {
+		return 0
+	}
+
+	atr := indicator.NewATR(14)
+	var last float64
+	for _, dp := range series {
+		margin := float64(dp.InstaBuyPrice - dp.InstaSellPrice)
+		last = atr.UpdateHLC(margin, margin, margin)
+	}
+	return last
+}
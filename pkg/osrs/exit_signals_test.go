@@ -0,0 +1,82 @@
+package osrs
+
+import (
+	"testing"
+	"time"
+
+	"osrs-flipping/pkg/osrs/position"
+	"osrs-flipping/pkg/osrs/store"
+)
+
+func TestEvaluateExitSignals_StopLoss(t *testing.T) {
+	analyzer := &Analyzer{
+		items: []ItemData{{ItemID: 1, Name: "Test Item", InstaBuyPrice: intPtr(90)}},
+		store: store.New("", 0, 0),
+	}
+
+	positions := []position.Position{
+		{ItemID: 1, Name: "Test Item", Quantity: 10, BuyPrice: 100, BuyTime: time.Now()},
+	}
+
+	signals := analyzer.EvaluateExitSignals(positions, position.ExitConfig{StopLossPct: 0.05})
+	if len(signals) != 1 || signals[0].Reason != position.ReasonStopLoss {
+		t.Fatalf("expected a stop-loss signal (price down 10%%, threshold 5%%), got %+v", signals)
+	}
+}
+
+func TestEvaluateExitSignals_Trailing(t *testing.T) {
+	itemID := 1
+	s := store.New("", 0, 0)
+	buyTime := time.Now().Add(-time.Hour)
+	// Price ran up to 120 (20% above the 100 entry) then pulled back to 115.
+	s.Append(itemID, store.Interval1h, store.DataPoint{Timestamp: buyTime.Add(10 * time.Minute), InstaBuyPrice: 120, InstaSellPrice: 118})
+
+	analyzer := &Analyzer{
+		items: []ItemData{{ItemID: itemID, Name: "Test Item", InstaBuyPrice: intPtr(115)}},
+		store: s,
+	}
+
+	positions := []position.Position{
+		{ItemID: itemID, Name: "Test Item", Quantity: 1, BuyPrice: 100, BuyTime: buyTime},
+	}
+
+	cfg := position.ExitConfig{
+		TrailingActivationRatio: []float64{0.01, 0.1},
+		TrailingCallbackRate:    []float64{0.01, 0.02},
+	}
+	signals := analyzer.EvaluateExitSignals(positions, cfg)
+	if len(signals) != 1 || signals[0].Reason != position.ReasonTrailing {
+		t.Fatalf("expected a trailing-stop signal (peak 120 pulled back to 115, past the 2%% callback), got %+v", signals)
+	}
+}
+
+func TestEvaluateExitSignals_Stale(t *testing.T) {
+	analyzer := &Analyzer{
+		items: []ItemData{{ItemID: 1, Name: "Test Item", InstaBuyPrice: intPtr(101)}},
+		store: store.New("", 0, 0),
+	}
+
+	positions := []position.Position{
+		{ItemID: 1, Name: "Test Item", Quantity: 1, BuyPrice: 100, BuyTime: time.Now().Add(-48 * time.Hour)},
+	}
+
+	signals := analyzer.EvaluateExitSignals(positions, position.ExitConfig{StaleAfter: 24 * time.Hour})
+	if len(signals) != 1 || signals[0].Reason != position.ReasonStale {
+		t.Fatalf("expected a stale signal (open 48h, threshold 24h), got %+v", signals)
+	}
+}
+
+func TestEvaluateExitSignals_SkipsPositionsWithNoCurrentPrice(t *testing.T) {
+	analyzer := &Analyzer{
+		items: []ItemData{{ItemID: 1, Name: "Test Item"}}, // no InstaBuyPrice
+		store: store.New("", 0, 0),
+	}
+
+	positions := []position.Position{
+		{ItemID: 1, Name: "Test Item", Quantity: 1, BuyPrice: 100, BuyTime: time.Now()},
+	}
+
+	if signals := analyzer.EvaluateExitSignals(positions, position.DefaultExitConfig); len(signals) != 0 {
+		t.Errorf("expected no signals for an item with no current price, got %+v", signals)
+	}
+}
@@ -0,0 +1,203 @@
+package expr
+
+import "fmt"
+
+// node is one AST node. isBool is static (known at parse time from the
+// grammar position a node was parsed in), so Compile can reject a
+// numeric-only expression without evaluating it.
+type node interface {
+	eval(env Env) (interface{}, error)
+	isBool() bool
+	describe() string
+}
+
+type numberNode struct{ value float64 }
+
+func (n numberNode) eval(Env) (interface{}, error) { return n.value, nil }
+func (n numberNode) isBool() bool                  { return false }
+func (n numberNode) describe() string              { return "number" }
+
+type identNode struct{ name string }
+
+func (n identNode) eval(env Env) (interface{}, error) {
+	v, ok := env.Scalar(n.name)
+	if !ok {
+		return nil, fmt.Errorf("no value available for %q", n.name)
+	}
+	return v, nil
+}
+func (n identNode) isBool() bool     { return false }
+func (n identNode) describe() string { return "identifier" }
+
+// callArg is one argument to an indicator function call: either a bare
+// series identifier (sma(price, 10)'s "price") or a number (atr(14)'s
+// "14").
+type callArg struct {
+	isSeries bool
+	name     string
+	value    float64
+}
+
+type callNode struct {
+	fn   string
+	args []callArg
+}
+
+func (n callNode) eval(env Env) (interface{}, error) {
+	var seriesName string
+	window := 0
+	for _, a := range n.args {
+		if a.isSeries {
+			seriesName = a.name
+		} else {
+			window = int(a.value)
+		}
+	}
+	v, ok := env.Indicator(n.fn, seriesName, window)
+	if !ok {
+		return nil, fmt.Errorf("not enough history to compute %s(...)", n.fn)
+	}
+	return v, nil
+}
+func (n callNode) isBool() bool     { return false }
+func (n callNode) describe() string { return "function call" }
+
+type unaryMinusNode struct{ operand node }
+
+func (n unaryMinusNode) eval(env Env) (interface{}, error) {
+	v, err := evalNumber(n.operand, env)
+	if err != nil {
+		return nil, err
+	}
+	return -v, nil
+}
+func (n unaryMinusNode) isBool() bool     { return false }
+func (n unaryMinusNode) describe() string { return "number" }
+
+type notNode struct{ operand node }
+
+func (n notNode) eval(env Env) (interface{}, error) {
+	v, err := evalBool(n.operand, env)
+	if err != nil {
+		return nil, err
+	}
+	return !v, nil
+}
+func (n notNode) isBool() bool     { return true }
+func (n notNode) describe() string { return "boolean" }
+
+type arithNode struct {
+	op          byte // '+', '-', '*', '/'
+	left, right node
+}
+
+func (n arithNode) eval(env Env) (interface{}, error) {
+	l, err := evalNumber(n.left, env)
+	if err != nil {
+		return nil, err
+	}
+	r, err := evalNumber(n.right, env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	case '/':
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	default:
+		return nil, fmt.Errorf("unknown arithmetic operator %q", n.op)
+	}
+}
+func (n arithNode) isBool() bool     { return false }
+func (n arithNode) describe() string { return "number" }
+
+type compareNode struct {
+	op          string // ">", ">=", "<", "<=", "==", "!="
+	left, right node
+}
+
+func (n compareNode) eval(env Env) (interface{}, error) {
+	l, err := evalNumber(n.left, env)
+	if err != nil {
+		return nil, err
+	}
+	r, err := evalNumber(n.right, env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case "==":
+		return l == r, nil
+	case "!=":
+		return l != r, nil
+	default:
+		return nil, fmt.Errorf("unknown comparison operator %q", n.op)
+	}
+}
+func (n compareNode) isBool() bool     { return true }
+func (n compareNode) describe() string { return "boolean" }
+
+type logicalNode struct {
+	op          string // "&&", "||"
+	left, right node
+}
+
+func (n logicalNode) eval(env Env) (interface{}, error) {
+	l, err := evalBool(n.left, env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" && !l {
+		return false, nil
+	}
+	if n.op == "||" && l {
+		return true, nil
+	}
+	r, err := evalBool(n.right, env)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+func (n logicalNode) isBool() bool     { return true }
+func (n logicalNode) describe() string { return "boolean" }
+
+func evalNumber(n node, env Env) (float64, error) {
+	v, err := n.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("expected a number, got a %s", n.describe())
+	}
+	return f, nil
+}
+
+func evalBool(n node, env Env) (bool, error) {
+	v, err := n.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a boolean, got a %s", n.describe())
+	}
+	return b, nil
+}
@@ -0,0 +1,67 @@
+// Package expr implements a small boolean expression language for
+// FilterOptions.Expr: comparisons and logical combinators over a handful of
+// named bindings (price, margin, volume_buy, volume_sell) and indicator
+// functions (sma, ema, stddev, atr, slope, pct_change) computed from an
+// item's price history. It exists so FilterOptions can grow one new
+// comparison idea without growing a dozen new option fields to match.
+package expr
+
+import (
+	"fmt"
+)
+
+// Env resolves the bindings and indicator functions an Expr references.
+// The osrs package supplies the concrete implementation, backed by an
+// item's current values and its rolling price-history store.
+type Env interface {
+	// Scalar resolves a bare identifier (price, margin, volume_buy,
+	// volume_sell) to its current value. ok is false if the item has no
+	// value for that binding yet (e.g. no volume data loaded).
+	Scalar(name string) (value float64, ok bool)
+
+	// Indicator resolves an indicator function call to its latest value.
+	// seriesName is the bare identifier passed as the series argument
+	// (e.g. "price" in sma(price, 10)); it's empty for atr, which only
+	// takes a window. ok is false if there isn't enough history yet.
+	Indicator(fn string, seriesName string, window int) (value float64, ok bool)
+}
+
+// Expr is a compiled expression, ready to evaluate against any Env.
+type Expr struct {
+	root node
+}
+
+// Compile parses source into an Expr. The expression must evaluate to a
+// boolean at its root -- "price > 100" and "sma(price,10) > ema(price,50)
+// && margin > 2" are valid; "price" alone is not.
+func Compile(source string) (*Expr, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.atEnd() {
+		return nil, fmt.Errorf("unexpected token %q after expression", p.peek().text)
+	}
+	if !root.isBool() {
+		return nil, fmt.Errorf("expression must evaluate to a boolean, got %s", root.describe())
+	}
+	return &Expr{root: root}, nil
+}
+
+// Eval evaluates the compiled expression against env.
+func (e *Expr) Eval(env Env) (bool, error) {
+	v, err := e.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluated to a non-boolean value %v", v)
+	}
+	return b, nil
+}
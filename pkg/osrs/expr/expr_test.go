@@ -0,0 +1,99 @@
+package expr
+
+import "testing"
+
+// testEnv is a fixed-binding Env for exercising Compile/Eval without
+// needing a real item or store.
+type testEnv struct {
+	scalars    map[string]float64
+	indicators map[string]float64 // keyed by "fn:series"
+}
+
+func (e testEnv) Scalar(name string) (float64, bool) {
+	v, ok := e.scalars[name]
+	return v, ok
+}
+
+func (e testEnv) Indicator(fn, seriesName string, window int) (float64, bool) {
+	v, ok := e.indicators[fn+":"+seriesName]
+	return v, ok
+}
+
+func mustCompile(t *testing.T, source string) *Expr {
+	t.Helper()
+	e, err := Compile(source)
+	if err != nil {
+		t.Fatalf("Compile(%q) returned error: %v", source, err)
+	}
+	return e
+}
+
+func TestEvalSimpleComparison(t *testing.T) {
+	e := mustCompile(t, "price > 100")
+	env := testEnv{scalars: map[string]float64{"price": 150}}
+	got, err := e.Eval(env)
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if !got {
+		t.Error("expected price(150) > 100 to be true")
+	}
+}
+
+func TestEvalArithmeticAndLogical(t *testing.T) {
+	e := mustCompile(t, "margin * 2 > 100 && volume_buy >= 5")
+	env := testEnv{scalars: map[string]float64{"margin": 60, "volume_buy": 10}}
+	got, err := e.Eval(env)
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if !got {
+		t.Error("expected (60*2 > 100) && (10 >= 5) to be true")
+	}
+}
+
+func TestEvalIndicatorFunctions(t *testing.T) {
+	e := mustCompile(t, "sma(price, 10) > ema(price, 50) || atr(14) < 1")
+	env := testEnv{
+		indicators: map[string]float64{"sma:price": 120, "ema:price": 100, "atr:": 5},
+	}
+	got, err := e.Eval(env)
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if !got {
+		t.Error("expected sma(price,10)=120 > ema(price,50)=100 to be true")
+	}
+}
+
+func TestEvalNot(t *testing.T) {
+	e := mustCompile(t, "!(price > 100)")
+	env := testEnv{scalars: map[string]float64{"price": 50}}
+	got, err := e.Eval(env)
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if !got {
+		t.Error("expected !(50 > 100) to be true")
+	}
+}
+
+func TestCompileRejectsNonBooleanRoot(t *testing.T) {
+	if _, err := Compile("price + 1"); err == nil {
+		t.Error("expected an error compiling a purely numeric expression")
+	}
+}
+
+func TestCompileRejectsGarbageSyntax(t *testing.T) {
+	if _, err := Compile("price >> 100"); err == nil {
+		t.Error("expected an error compiling invalid syntax")
+	}
+}
+
+func TestEvalMissingBindingIsAnError(t *testing.T) {
+	e := mustCompile(t, "price > 100")
+	_, err := e.Eval(testEnv{})
+	if err == nil {
+		t.Error("expected an error evaluating against an env with no price binding")
+	}
+}
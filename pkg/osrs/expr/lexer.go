@@ -0,0 +1,142 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokAnd
+	tokOr
+	tokNot
+	tokGT
+	tokGE
+	tokLT
+	tokLE
+	tokEQ
+	tokNE
+	tokPlus
+	tokMinus
+	tokStar
+	tokSlash
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+// token is one lexeme, carrying its kind, raw source text (for error
+// messages and identifier/function names), and parsed value for numbers.
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	i := 0
+
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")"})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ","})
+			i++
+		case r == '+':
+			tokens = append(tokens, token{kind: tokPlus, text: "+"})
+			i++
+		case r == '-':
+			tokens = append(tokens, token{kind: tokMinus, text: "-"})
+			i++
+		case r == '*':
+			tokens = append(tokens, token{kind: tokStar, text: "*"})
+			i++
+		case r == '/':
+			tokens = append(tokens, token{kind: tokSlash, text: "/"})
+			i++
+		case r == '>':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokGE, text: ">="})
+				i += 2
+			} else {
+				tokens = append(tokens, token{kind: tokGT, text: ">"})
+				i++
+			}
+		case r == '<':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokLE, text: "<="})
+				i += 2
+			} else {
+				tokens = append(tokens, token{kind: tokLT, text: "<"})
+				i++
+			}
+		case r == '=':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokEQ, text: "=="})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected character %q (did you mean ==?)", r)
+			}
+		case r == '!':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokNE, text: "!="})
+				i += 2
+			} else {
+				tokens = append(tokens, token{kind: tokNot, text: "!"})
+				i++
+			}
+		case r == '&':
+			if i+1 < len(runes) && runes[i+1] == '&' {
+				tokens = append(tokens, token{kind: tokAnd, text: "&&"})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected character %q (did you mean &&?)", r)
+			}
+		case r == '|':
+			if i+1 < len(runes) && runes[i+1] == '|' {
+				tokens = append(tokens, token{kind: tokOr, text: "||"})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected character %q (did you mean ||?)", r)
+			}
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			raw := string(runes[start:i])
+			num, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q: %w", raw, err)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: raw, num: num})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			word := string(runes[start:i])
+			tokens = append(tokens, token{kind: tokIdent, text: word})
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, text: ""})
+	return tokens, nil
+}
@@ -0,0 +1,217 @@
+package expr
+
+import "fmt"
+
+// parser is a straightforward recursive-descent parser over the
+// precedence chain: || < && < ! < comparison < +- < */ < unary < atom.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token     { return p.tokens[p.pos] }
+func (p *parser) atEnd() bool     { return p.peek().kind == tokEOF }
+func (p *parser) advance() token  { t := p.tokens[p.pos]; p.pos++; return t }
+func (p *parser) check(k tokenKind) bool {
+	return p.peek().kind == k
+}
+func (p *parser) match(k tokenKind) bool {
+	if p.check(k) {
+		p.advance()
+		return true
+	}
+	return false
+}
+func (p *parser) expect(k tokenKind, what string) (token, error) {
+	if !p.check(k) {
+		return token{}, fmt.Errorf("expected %s, got %q", what, p.peek().text)
+	}
+	return p.advance(), nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.match(tokOr) {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.match(tokAnd) {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.match(tokNot) {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseArith()
+	if err != nil {
+		return nil, err
+	}
+
+	var op string
+	switch {
+	case p.match(tokGT):
+		op = ">"
+	case p.match(tokGE):
+		op = ">="
+	case p.match(tokLT):
+		op = "<"
+	case p.match(tokLE):
+		op = "<="
+	case p.match(tokEQ):
+		op = "=="
+	case p.match(tokNE):
+		op = "!="
+	default:
+		return left, nil
+	}
+
+	right, err := p.parseArith()
+	if err != nil {
+		return nil, err
+	}
+	return compareNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseArith() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.check(tokPlus) || p.check(tokMinus) {
+		op := byte('+')
+		if p.check(tokMinus) {
+			op = '-'
+		}
+		p.advance()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = arithNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.check(tokStar) || p.check(tokSlash) {
+		op := byte('*')
+		if p.check(tokSlash) {
+			op = '/'
+		}
+		p.advance()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = arithNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.match(tokMinus) {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryMinusNode{operand: operand}, nil
+	}
+	return p.parseAtom()
+}
+
+func (p *parser) parseAtom() (node, error) {
+	switch {
+	case p.check(tokNumber):
+		t := p.advance()
+		return numberNode{value: t.num}, nil
+
+	case p.check(tokLParen):
+		p.advance()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+
+	case p.check(tokIdent):
+		t := p.advance()
+		if p.check(tokLParen) {
+			return p.parseCall(t.text)
+		}
+		return identNode{name: t.text}, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected token %q", p.peek().text)
+	}
+}
+
+// parseCall parses the argument list of a function call already past its
+// name. Each argument is either a bare identifier (a series name, e.g.
+// "price" in sma(price, 10)) or a numeric literal (a window, e.g. the 10);
+// anything else is a parse error since indicator functions don't take
+// arbitrary sub-expressions.
+func (p *parser) parseCall(fn string) (node, error) {
+	if _, err := p.expect(tokLParen, "'('"); err != nil {
+		return nil, err
+	}
+
+	var args []callArg
+	if !p.check(tokRParen) {
+		for {
+			switch {
+			case p.check(tokIdent):
+				t := p.advance()
+				args = append(args, callArg{isSeries: true, name: t.text})
+			case p.check(tokNumber):
+				t := p.advance()
+				args = append(args, callArg{value: t.num})
+			default:
+				return nil, fmt.Errorf("%s(...) arguments must be a series name or a number, got %q", fn, p.peek().text)
+			}
+			if !p.match(tokComma) {
+				break
+			}
+		}
+	}
+
+	if _, err := p.expect(tokRParen, "')'"); err != nil {
+		return nil, err
+	}
+	return callNode{fn: fn, args: args}, nil
+}
@@ -0,0 +1,125 @@
+// Package factors implements a multi-feature regression scoring model for
+// flip candidates: a handful of cheap per-item signals (spread, volume
+// z-score, drift, ...) combined via an OLS-fit linear model into a single
+// predicted return, so Analyzer can rank candidates by something trained on
+// historical outcomes instead of the hand-tuned FlipEfficiency formula.
+//
+// FlipFeatures is a flat struct rather than osrs.ItemData/VolumeMetrics
+// directly: Analyzer (in package osrs) needs to import factors for Model, so
+// factors importing osrs back would form an import cycle. Callers in
+// package osrs build a FlipFeatures from an ItemData/VolumeMetrics pair
+// before calling into this package.
+package factors
+
+// FlipFeatures holds the raw inputs the built-in factors below read from.
+// Zero-value fields (e.g. a volume stat that hasn't loaded yet) are treated
+// as 0, the same "no signal" convention VolumeMetrics itself uses.
+type FlipFeatures struct {
+	InstaBuyPrice  float64
+	InstaSellPrice float64
+	BuyLimit       float64
+	MarginGP       float64
+
+	InstaBuyVolume1h  float64
+	InstaSellVolume1h float64
+
+	InstaBuyVolumeMean1h    float64
+	InstaBuyVolumeStdDev1h  float64
+	InstaBuyVolumeMean24h   float64
+	InstaBuyVolumeStdDev24h float64
+
+	Drift1h float64
+
+	// InstaBuyPriceStdDev1h is the stddev of insta-buy prices over the 1h
+	// window (see osrs.DistributionStats.StdDev), used directly as the
+	// volatility factor.
+	InstaBuyPriceStdDev1h float64
+
+	// TaxGP is the GE tax (see osrs/portfolio.geTax for the formula this
+	// mirrors) that would be paid selling one unit at InstaSellPrice.
+	TaxGP float64
+}
+
+// Factor is one scored signal folded into a Model's linear combination.
+type Factor interface {
+	// Name identifies the factor, used as its FactorNames entry in a
+	// persisted Model so a loaded model can be matched back to its factors.
+	Name() string
+	// Compute derives this factor's value from f.
+	Compute(f FlipFeatures) float64
+}
+
+type spreadPctFactor struct{}
+
+func (spreadPctFactor) Name() string { return "spread_pct" }
+func (spreadPctFactor) Compute(f FlipFeatures) float64 {
+	if f.InstaBuyPrice <= 0 {
+		return 0
+	}
+	return (f.InstaBuyPrice - f.InstaSellPrice) / f.InstaBuyPrice
+}
+
+type buyLimitUtilizationFactor struct{}
+
+func (buyLimitUtilizationFactor) Name() string { return "buy_limit_utilization" }
+func (buyLimitUtilizationFactor) Compute(f FlipFeatures) float64 {
+	if f.BuyLimit <= 0 {
+		return 0
+	}
+	return f.InstaBuyVolume1h / f.BuyLimit
+}
+
+type volumeZScore1hFactor struct{}
+
+func (volumeZScore1hFactor) Name() string { return "volume_zscore_1h" }
+func (volumeZScore1hFactor) Compute(f FlipFeatures) float64 {
+	return zScore(f.InstaBuyVolume1h, f.InstaBuyVolumeMean1h, f.InstaBuyVolumeStdDev1h)
+}
+
+type volumeZScore24hFactor struct{}
+
+func (volumeZScore24hFactor) Name() string { return "volume_zscore_24h" }
+func (volumeZScore24hFactor) Compute(f FlipFeatures) float64 {
+	return zScore(f.InstaBuyVolume1h, f.InstaBuyVolumeMean24h, f.InstaBuyVolumeStdDev24h)
+}
+
+type driftFactor struct{}
+
+func (driftFactor) Name() string                   { return "drift" }
+func (driftFactor) Compute(f FlipFeatures) float64 { return f.Drift1h }
+
+type marginTaxRatioFactor struct{}
+
+func (marginTaxRatioFactor) Name() string { return "margin_tax_ratio" }
+func (marginTaxRatioFactor) Compute(f FlipFeatures) float64 {
+	if f.TaxGP <= 0 {
+		return 0
+	}
+	return f.MarginGP / f.TaxGP
+}
+
+type volatilityFactor struct{}
+
+func (volatilityFactor) Name() string                   { return "volatility" }
+func (volatilityFactor) Compute(f FlipFeatures) float64 { return f.InstaBuyPriceStdDev1h }
+
+func zScore(value, mean, stdDev float64) float64 {
+	if stdDev <= 0 {
+		return 0
+	}
+	return (value - mean) / stdDev
+}
+
+// DefaultFactors returns the built-in factor set, in the fixed order Fit and
+// Predict read/write Model.Coefficients.
+func DefaultFactors() []Factor {
+	return []Factor{
+		spreadPctFactor{},
+		buyLimitUtilizationFactor{},
+		volumeZScore1hFactor{},
+		volumeZScore24hFactor{},
+		driftFactor{},
+		marginTaxRatioFactor{},
+		volatilityFactor{},
+	}
+}
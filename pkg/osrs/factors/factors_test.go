@@ -0,0 +1,87 @@
+package factors
+
+import "testing"
+
+func TestSpreadPctFactor(t *testing.T) {
+	f := spreadPctFactor{}
+
+	got := f.Compute(FlipFeatures{InstaBuyPrice: 200, InstaSellPrice: 150})
+	want := 0.25
+	if got != want {
+		t.Errorf("Compute = %v, want %v", got, want)
+	}
+
+	if got := f.Compute(FlipFeatures{InstaBuyPrice: 0}); got != 0 {
+		t.Errorf("Compute with zero InstaBuyPrice = %v, want 0", got)
+	}
+}
+
+func TestBuyLimitUtilizationFactor(t *testing.T) {
+	f := buyLimitUtilizationFactor{}
+
+	got := f.Compute(FlipFeatures{InstaBuyVolume1h: 50, BuyLimit: 100})
+	if got != 0.5 {
+		t.Errorf("Compute = %v, want 0.5", got)
+	}
+
+	if got := f.Compute(FlipFeatures{BuyLimit: 0}); got != 0 {
+		t.Errorf("Compute with zero BuyLimit = %v, want 0", got)
+	}
+}
+
+func TestVolumeZScoreFactors(t *testing.T) {
+	f1h := volumeZScore1hFactor{}
+	got := f1h.Compute(FlipFeatures{InstaBuyVolume1h: 20, InstaBuyVolumeMean1h: 10, InstaBuyVolumeStdDev1h: 5})
+	if got != 2 {
+		t.Errorf("volumeZScore1hFactor.Compute = %v, want 2", got)
+	}
+
+	f24h := volumeZScore24hFactor{}
+	got = f24h.Compute(FlipFeatures{InstaBuyVolume1h: 20, InstaBuyVolumeMean24h: 10, InstaBuyVolumeStdDev24h: 0})
+	if got != 0 {
+		t.Errorf("volumeZScore24hFactor.Compute with zero stddev = %v, want 0", got)
+	}
+}
+
+func TestDriftFactor(t *testing.T) {
+	f := driftFactor{}
+	if got := f.Compute(FlipFeatures{Drift1h: 0.3}); got != 0.3 {
+		t.Errorf("Compute = %v, want 0.3", got)
+	}
+}
+
+func TestMarginTaxRatioFactor(t *testing.T) {
+	f := marginTaxRatioFactor{}
+
+	got := f.Compute(FlipFeatures{MarginGP: 100, TaxGP: 50})
+	if got != 2 {
+		t.Errorf("Compute = %v, want 2", got)
+	}
+
+	if got := f.Compute(FlipFeatures{MarginGP: 100, TaxGP: 0}); got != 0 {
+		t.Errorf("Compute with zero TaxGP = %v, want 0", got)
+	}
+}
+
+func TestVolatilityFactor(t *testing.T) {
+	f := volatilityFactor{}
+	if got := f.Compute(FlipFeatures{InstaBuyPriceStdDev1h: 12.5}); got != 12.5 {
+		t.Errorf("Compute = %v, want 12.5", got)
+	}
+}
+
+func TestDefaultFactors(t *testing.T) {
+	fs := DefaultFactors()
+	if len(fs) != 7 {
+		t.Fatalf("DefaultFactors returned %d factors, want 7", len(fs))
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range fs {
+		name := f.Name()
+		if seen[name] {
+			t.Errorf("duplicate factor name %q", name)
+		}
+		seen[name] = true
+	}
+}
@@ -0,0 +1,132 @@
+package factors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Observation is one (features, realized outcome) training example: a
+// factor vector captured at some point in time, paired with the return the
+// item actually went on to realize over the prediction horizon.
+type Observation struct {
+	Features []float64
+	Target   float64
+}
+
+// Model is an OLS-fit linear combination of a fixed factor set: predicted
+// return = Intercept + sum(Coefficients[i] * factors[i].Compute(f)).
+// FactorNames records which factors Coefficients correspond to (in order),
+// so a loaded Model can be checked against the caller's current factor set
+// before Predict is trusted.
+type Model struct {
+	FactorNames  []string
+	Coefficients []float64
+	Intercept    float64
+	TrainedAt    time.Time
+	Observations int
+}
+
+// Fit trains a Model by ordinary least squares: builds the design matrix
+// (one row per observation, one column per factor plus an intercept
+// column of 1s) and solves the normal equations via gonum's QR solver.
+// Returns an error if there are fewer observations than factors+1 --
+// OLS is underdetermined otherwise.
+func Fit(fs []Factor, observations []Observation) (*Model, error) {
+	if len(observations) <= len(fs) {
+		return nil, fmt.Errorf("factors: need more than %d observations to fit %d factors, got %d", len(fs), len(fs), len(observations))
+	}
+
+	rows := len(observations)
+	cols := len(fs) + 1 // +1 for the intercept column
+
+	x := mat.NewDense(rows, cols, nil)
+	y := mat.NewDense(rows, 1, nil)
+	for i, obs := range observations {
+		if len(obs.Features) != len(fs) {
+			return nil, fmt.Errorf("factors: observation %d has %d features, want %d", i, len(obs.Features), len(fs))
+		}
+		x.Set(i, 0, 1)
+		for j, v := range obs.Features {
+			x.Set(i, j+1, v)
+		}
+		y.Set(i, 0, obs.Target)
+	}
+
+	var qr mat.QR
+	qr.Factorize(x)
+
+	var beta mat.Dense
+	if err := qr.SolveTo(&beta, false, y); err != nil {
+		return nil, fmt.Errorf("factors: OLS solve failed: %w", err)
+	}
+
+	names := make([]string, len(fs))
+	for i, f := range fs {
+		names[i] = f.Name()
+	}
+
+	coefficients := make([]float64, len(fs))
+	for i := range coefficients {
+		coefficients[i] = beta.At(i+1, 0)
+	}
+
+	return &Model{
+		FactorNames:  names,
+		Coefficients: coefficients,
+		Intercept:    beta.At(0, 0),
+		TrainedAt:    time.Now(),
+		Observations: rows,
+	}, nil
+}
+
+// Predict scores f through fs (which must be in the same order the Model
+// was Fit with -- see FactorNames) and returns the predicted return.
+func (m *Model) Predict(fs []Factor, f FlipFeatures) float64 {
+	if m == nil {
+		return 0
+	}
+
+	score := m.Intercept
+	for i, factor := range fs {
+		if i >= len(m.Coefficients) {
+			break
+		}
+		score += m.Coefficients[i] * factor.Compute(f)
+	}
+	return score
+}
+
+// Save persists m as indented JSON to path.
+func (m *Model) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("factors: marshal model: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("factors: write model: %w", err)
+	}
+	return nil
+}
+
+// LoadModel reads a Model previously written by Save. A missing file is not
+// an error -- it returns (nil, nil), the same "nothing trained yet"
+// convention as a cold-start portfolio store.
+func LoadModel(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("factors: read model: %w", err)
+	}
+
+	var m Model
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("factors: unmarshal model: %w", err)
+	}
+	return &m, nil
+}
@@ -0,0 +1,96 @@
+package factors
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// identityFactor reads FlipFeatures.Drift1h verbatim, giving Fit/Predict a
+// single factor whose relationship to the target is easy to hand-compute.
+type identityFactor struct{}
+
+func (identityFactor) Name() string                   { return "identity" }
+func (identityFactor) Compute(f FlipFeatures) float64 { return f.Drift1h }
+
+func TestFitPredictRoundTrip(t *testing.T) {
+	fs := []Factor{identityFactor{}}
+
+	// target = 2*x + 1, noise-free so the fitted coefficients should land
+	// on (intercept=1, coefficient=2) to within float tolerance.
+	var observations []Observation
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		observations = append(observations, Observation{
+			Features: []float64{x},
+			Target:   2*x + 1,
+		})
+	}
+
+	model, err := Fit(fs, observations)
+	if err != nil {
+		t.Fatalf("Fit returned error: %v", err)
+	}
+
+	if math.Abs(model.Intercept-1) > 1e-6 {
+		t.Errorf("Intercept = %v, want ~1", model.Intercept)
+	}
+	if len(model.Coefficients) != 1 || math.Abs(model.Coefficients[0]-2) > 1e-6 {
+		t.Errorf("Coefficients = %v, want [~2]", model.Coefficients)
+	}
+
+	got := model.Predict(fs, FlipFeatures{Drift1h: 5})
+	want := 2*5.0 + 1
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("Predict = %v, want %v", got, want)
+	}
+}
+
+func TestFitNotEnoughObservations(t *testing.T) {
+	fs := []Factor{identityFactor{}}
+	_, err := Fit(fs, []Observation{{Features: []float64{1}, Target: 1}})
+	if err == nil {
+		t.Fatal("Fit with too few observations: want error, got nil")
+	}
+}
+
+func TestPredictNilModel(t *testing.T) {
+	var m *Model
+	if got := m.Predict(DefaultFactors(), FlipFeatures{}); got != 0 {
+		t.Errorf("Predict on nil model = %v, want 0", got)
+	}
+}
+
+func TestSaveLoadModelRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "model.json")
+
+	model := &Model{
+		FactorNames:  []string{"identity"},
+		Coefficients: []float64{2},
+		Intercept:    1,
+		Observations: 10,
+	}
+
+	if err := model.Save(path); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	loaded, err := LoadModel(path)
+	if err != nil {
+		t.Fatalf("LoadModel returned error: %v", err)
+	}
+	if loaded.Intercept != model.Intercept || loaded.Coefficients[0] != model.Coefficients[0] {
+		t.Errorf("loaded model = %+v, want %+v", loaded, model)
+	}
+}
+
+func TestLoadModelMissingFile(t *testing.T) {
+	model, err := LoadModel(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	if err != nil {
+		t.Fatalf("LoadModel returned error: %v", err)
+	}
+	if model != nil {
+		t.Errorf("LoadModel for a missing file = %+v, want nil", model)
+	}
+}
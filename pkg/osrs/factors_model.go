@@ -0,0 +1,199 @@
+package osrs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"osrs-flipping/pkg/osrs/factors"
+	"osrs-flipping/pkg/osrs/portfolio"
+	"osrs-flipping/pkg/osrs/store"
+)
+
+// defaultFlipModelPath is where Analyzer.TrainFlipModel persists its fitted
+// coefficients when a caller doesn't override the path.
+const defaultFlipModelPath = "output/data/flip_model.json"
+
+// flipFeatureWindow is how many 5m ticks back a training/scoring window
+// looks for its 1h stats (12 * 5m = 1h); flipFeatureWindow24h is the same
+// for the 24h stats.
+const (
+	flipFeatureWindow    = 12
+	flipFeatureWindow24h = 288
+)
+
+// flipFeaturesFromPoints builds a factors.FlipFeatures from a 5m DataPoint
+// series, reading the window ending at idx -- the path TrainFlipModel uses
+// while walking historical ticks. ScoreItems does not call it; it scores
+// loaded items through flipFeaturesFromItem.
+func (a *Analyzer) flipFeaturesFromPoints(points []store.DataPoint, idx int, buyLimit int) factors.FlipFeatures {
+	current := points[idx]
+
+	start1h := idx - flipFeatureWindow + 1
+	if start1h < 0 {
+		start1h = 0
+	}
+	start24h := idx - flipFeatureWindow24h + 1
+	if start24h < 0 {
+		start24h = 0
+	}
+	win1h := points[start1h : idx+1]
+	win24h := points[start24h : idx+1]
+
+	prices1h := make([]float64, len(win1h))
+	vol1h := make([]float64, len(win1h))
+	for i, p := range win1h {
+		prices1h[i] = float64(p.InstaBuyPrice)
+		vol1h[i] = float64(p.InstaBuyVolume)
+	}
+	vol24h := make([]float64, len(win24h))
+	for i, p := range win24h {
+		vol24h[i] = float64(p.InstaBuyVolume)
+	}
+
+	priceStats1h := computeStats(prices1h)
+	volStats1h := computeStats(vol1h)
+	volStats24h := computeStats(vol24h)
+
+	return factors.FlipFeatures{
+		InstaBuyPrice:           float64(current.InstaBuyPrice),
+		InstaSellPrice:          float64(current.InstaSellPrice),
+		BuyLimit:                float64(buyLimit),
+		MarginGP:                float64(current.InstaBuyPrice - current.InstaSellPrice),
+		InstaBuyVolume1h:        float64(current.InstaBuyVolume),
+		InstaBuyVolumeMean1h:    volStats1h.Mean,
+		InstaBuyVolumeStdDev1h:  volStats1h.StdDev,
+		InstaBuyVolumeMean24h:   volStats24h.Mean,
+		InstaBuyVolumeStdDev24h: volStats24h.StdDev,
+		Drift1h:                 driftMA(prices1h, a.indicatorConfig.DriftWindow),
+		InstaBuyPriceStdDev1h:   priceStats1h.StdDev,
+		TaxGP:                   float64(portfolio.GeTax(current.InstaSellPrice, 1)),
+	}
+}
+
+// flipFeaturesFromItem builds a factors.FlipFeatures from an already-loaded
+// ItemData's volume-data pointer fields (see LoadVolumeData), the path used
+// by ScoreItems/getTopItemIDs to score candidates without re-querying the
+// store. Fields whose pointer hasn't loaded yet (volume data not run) are
+// treated as 0, the same "no signal" convention as elsewhere.
+func (a *Analyzer) flipFeaturesFromItem(item ItemData) factors.FlipFeatures {
+	f := factors.FlipFeatures{
+		BuyLimit: float64(item.BuyLimit),
+		MarginGP: float64(item.MarginGP),
+	}
+	if item.InstaSellPrice != nil {
+		f.InstaSellPrice = float64(*item.InstaSellPrice)
+		f.TaxGP = float64(portfolio.GeTax(*item.InstaSellPrice, 1))
+	}
+	if item.InstaBuyPrice != nil {
+		f.InstaBuyPrice = float64(*item.InstaBuyPrice)
+	}
+	if item.InstaBuyVolume1h != nil {
+		f.InstaBuyVolume1h = *item.InstaBuyVolume1h
+	}
+	if item.InstaBuyVolumeStats1h != nil {
+		f.InstaBuyVolumeMean1h = item.InstaBuyVolumeStats1h.Mean
+		f.InstaBuyVolumeStdDev1h = item.InstaBuyVolumeStats1h.StdDev
+	}
+	if item.InstaBuyVolumeStats24h != nil {
+		f.InstaBuyVolumeMean24h = item.InstaBuyVolumeStats24h.Mean
+		f.InstaBuyVolumeStdDev24h = item.InstaBuyVolumeStats24h.StdDev
+	}
+	if item.Drift1h != nil {
+		f.Drift1h = *item.Drift1h
+	}
+	if item.InstaBuyPriceStats1h != nil {
+		f.InstaBuyPriceStdDev1h = item.InstaBuyPriceStats1h.StdDev
+	}
+	return f
+}
+
+// TrainFlipModel walks each loaded item's stored 5m history over the last
+// lookbackHours, builds (features -> realized 1h return) observations at
+// every tick with a full hour of future data to grade against, fits an OLS
+// regression over factors.DefaultFactors, and persists the result to
+// defaultFlipModelPath for ScoreItems/getTopItemIDs to use. Returns an error
+// if there isn't enough history yet to fit a model.
+func (a *Analyzer) TrainFlipModel(ctx context.Context, lookbackHours int) error {
+	if lookbackHours <= 0 {
+		lookbackHours = 24
+	}
+	since := time.Now().Add(-time.Duration(lookbackHours) * time.Hour)
+
+	fs := factors.DefaultFactors()
+	var observations []factors.Observation
+
+	for _, item := range a.items {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		points := a.store.GetSeries(item.ItemID, store.Interval5m, since)
+		// flipFeatureWindow ticks of context before idx, flipFeatureWindow
+		// ticks of future price after idx to grade the realized 1h return.
+		for idx := flipFeatureWindow; idx < len(points)-flipFeatureWindow; idx++ {
+			if points[idx].InstaBuyPrice <= 0 {
+				continue
+			}
+
+			f := a.flipFeaturesFromPoints(points, idx, item.BuyLimit)
+
+			featureValues := make([]float64, len(fs))
+			for i, factor := range fs {
+				featureValues[i] = factor.Compute(f)
+			}
+
+			futurePrice := points[idx+flipFeatureWindow].InstaBuyPrice
+			realizedReturn := float64(futurePrice-points[idx].InstaBuyPrice) / float64(points[idx].InstaBuyPrice)
+
+			observations = append(observations, factors.Observation{
+				Features: featureValues,
+				Target:   realizedReturn,
+			})
+		}
+	}
+
+	model, err := factors.Fit(fs, observations)
+	if err != nil {
+		return fmt.Errorf("training flip model: %w", err)
+	}
+
+	if err := model.Save(defaultFlipModelPath); err != nil {
+		return fmt.Errorf("persisting flip model: %w", err)
+	}
+
+	a.flipModel = model
+	return nil
+}
+
+// ScoreItems returns a.items ranked by predicted 1h return from the
+// analyzer's trained flip model (see TrainFlipModel), highest first. Returns
+// items unranked (original order) if no model has been trained or persisted
+// yet.
+func (a *Analyzer) ScoreItems() []ItemData {
+	if a.flipModel == nil {
+		if model, err := factors.LoadModel(defaultFlipModelPath); err == nil {
+			a.flipModel = model
+		}
+	}
+	if a.flipModel == nil {
+		return a.items
+	}
+
+	fs := factors.DefaultFactors()
+	scored := make([]ItemData, len(a.items))
+	copy(scored, a.items)
+
+	scores := make(map[int]float64, len(scored))
+	for _, item := range scored {
+		scores[item.ItemID] = a.flipModel.Predict(fs, a.flipFeaturesFromItem(item))
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		return scores[scored[i].ItemID] > scores[scored[j].ItemID]
+	})
+	return scored
+}
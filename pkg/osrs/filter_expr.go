@@ -0,0 +1,135 @@
+package osrs
+
+import (
+	"time"
+
+	"osrs-flipping/pkg/osrs/expr"
+	"osrs-flipping/pkg/osrs/indicator"
+	"osrs-flipping/pkg/osrs/store"
+)
+
+// itemExprEnv implements expr.Env for FilterOptions.Expr: price, margin,
+// volume_buy, and volume_sell resolve to item's current values, and
+// sma/ema/stddev/atr/slope/pct_change are computed from its rolling 1h
+// price-history store.
+type itemExprEnv struct {
+	item  ItemData
+	store *store.Store
+}
+
+func (e itemExprEnv) Scalar(name string) (float64, bool) {
+	switch name {
+	case "price":
+		if e.item.InstaSellPrice == nil {
+			return 0, false
+		}
+		return float64(*e.item.InstaSellPrice), true
+	case "margin":
+		if e.item.InstaBuyPrice == nil || e.item.InstaSellPrice == nil {
+			return 0, false
+		}
+		return float64(*e.item.InstaBuyPrice - *e.item.InstaSellPrice), true
+	case "volume_buy":
+		if e.item.InstaBuyVolume1h == nil {
+			return 0, false
+		}
+		return *e.item.InstaBuyVolume1h, true
+	case "volume_sell":
+		if e.item.InstaSellVolume1h == nil {
+			return 0, false
+		}
+		return *e.item.InstaSellVolume1h, true
+	default:
+		return 0, false
+	}
+}
+
+func (e itemExprEnv) Indicator(fn string, seriesName string, window int) (float64, bool) {
+	points := e.store.GetSeries(e.item.ItemID, store.Interval1h, time.Time{})
+
+	if fn == "atr" {
+		if len(points) < 2 {
+			return 0, false
+		}
+		atr := indicator.NewATR(window)
+		var last float64
+		for _, p := range points {
+			last = atr.UpdateHLC(float64(p.InstaBuyPrice), float64(p.InstaSellPrice), float64(p.InstaSellPrice))
+		}
+		return last, true
+	}
+
+	values := seriesValues(points, seriesName)
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	switch fn {
+	case "sma":
+		sma := indicator.NewSMA(window)
+		var last float64
+		for _, v := range values {
+			last = sma.Update(v)
+		}
+		return last, true
+	case "ema":
+		ema := indicator.NewEMA(window)
+		var last float64
+		for _, v := range values {
+			last = ema.Update(v)
+		}
+		return last, true
+	case "stddev":
+		tail := windowTail(values, window)
+		if len(tail) < 2 {
+			return 0, false
+		}
+		return stddev(tail), true
+	case "slope":
+		tail := windowTail(values, window)
+		if len(tail) < 2 {
+			return 0, false
+		}
+		return (tail[len(tail)-1] - tail[0]) / float64(len(tail)-1), true
+	case "pct_change":
+		tail := windowTail(values, window)
+		if len(tail) < 2 || tail[0] == 0 {
+			return 0, false
+		}
+		return (tail[len(tail)-1] - tail[0]) / tail[0], true
+	default:
+		return 0, false
+	}
+}
+
+// seriesValues projects the item's rolling 1h store history down to a
+// single named value per tick, matching itemExprEnv.Scalar's bindings.
+func seriesValues(points []store.DataPoint, name string) []float64 {
+	values := make([]float64, len(points))
+	for i, p := range points {
+		switch name {
+		case "price":
+			values[i] = float64(p.InstaSellPrice)
+		case "margin":
+			values[i] = float64(p.InstaBuyPrice - p.InstaSellPrice)
+		case "volume_buy":
+			values[i] = float64(p.InstaBuyVolume)
+		case "volume_sell":
+			values[i] = float64(p.InstaSellVolume)
+		default:
+			return nil
+		}
+	}
+	return values
+}
+
+// windowTail returns the last n values of values, or all of them if n is
+// non-positive or larger than the series.
+func windowTail(values []float64, n int) []float64 {
+	if n <= 0 || n > len(values) {
+		n = len(values)
+	}
+	return values[len(values)-n:]
+}
+
+var _ expr.Env = itemExprEnv{}
@@ -0,0 +1,67 @@
+package osrs
+
+import "math"
+
+// FisherOverboughtThreshold/FisherOversoldThreshold classify
+// fisherTransform's last value into InstaBuyReversalSignal -- values beyond
+// these are rare enough (the transform is roughly Gaussian) to flag a price
+// at a short-term extreme, a common signal to flip against the crowd.
+const (
+	FisherOverboughtThreshold = 2.0
+	FisherOversoldThreshold   = -2.0
+)
+
+// fisherTransform computes John Ehlers' Fisher Transform over prices using a
+// rolling window, converting price into a Gaussian-ish oscillator that makes
+// turning points sharper and more clearly defined than the raw price series.
+// Returns nil if prices has fewer than window points.
+func fisherTransform(prices []float64, window int) []float64 {
+	if window <= 0 || len(prices) < window {
+		return nil
+	}
+
+	fish := make([]float64, len(prices))
+	for i := window - 1; i < len(prices); i++ {
+		w := prices[i-window+1 : i+1]
+		min, max := w[0], w[0]
+		for _, v := range w {
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+
+		var x float64
+		if max > min {
+			x = 2*((prices[i]-min)/(max-min)) - 1
+		}
+		// Clamp to avoid the ln singularity at +/-1.
+		x = math.Max(-0.999, math.Min(0.999, x))
+
+		// fish[i-1] is still its zero value the first time through (i ==
+		// window-1), which is exactly the fish[0]=0 seed the recurrence
+		// wants.
+		fish[i] = 0.5*math.Log((1+x)/(1-x)) + 0.5*fish[i-1]
+	}
+
+	return fish[window-1:]
+}
+
+// classifyFisherReversal classifies the last value of a fisherTransform
+// series into "overbought", "oversold", or "" (neither extreme).
+func classifyFisherReversal(fish []float64) string {
+	if len(fish) == 0 {
+		return ""
+	}
+	last := fish[len(fish)-1]
+	switch {
+	case last > FisherOverboughtThreshold:
+		return "overbought"
+	case last < FisherOversoldThreshold:
+		return "oversold"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,71 @@
+package osrs
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFisherTransform(t *testing.T) {
+	t.Run("too short for window returns nil", func(t *testing.T) {
+		fish := fisherTransform([]float64{100, 101, 102}, 5)
+		if fish != nil {
+			t.Errorf("expected nil, got %v", fish)
+		}
+	})
+
+	t.Run("flat window is the min==max edge case and returns 0", func(t *testing.T) {
+		fish := fisherTransform(repeat(100, 10), 5)
+		if len(fish) == 0 {
+			t.Fatalf("expected a non-empty result")
+		}
+		if last := fish[len(fish)-1]; last != 0 {
+			t.Errorf("last = %v, want 0", last)
+		}
+	})
+
+	t.Run("rising series trends toward a positive fisher value", func(t *testing.T) {
+		fish := fisherTransform(makeRange(1, 20), 5)
+		if len(fish) == 0 {
+			t.Fatalf("expected a non-empty result")
+		}
+		if last := fish[len(fish)-1]; last <= 0 {
+			t.Errorf("last = %v, want > 0 for a steadily rising series", last)
+		}
+	})
+}
+
+func TestClassifyFisherReversal(t *testing.T) {
+	tests := []struct {
+		name     string
+		fish     []float64
+		expected string
+	}{
+		{"empty", nil, ""},
+		{"neutral", []float64{0.1}, ""},
+		{"overbought", []float64{2.5}, "overbought"},
+		{"oversold", []float64{-2.5}, "oversold"},
+		{"boundary is not extreme", []float64{2.0}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyFisherReversal(tt.fish); got != tt.expected {
+				t.Errorf("classifyFisherReversal(%v) = %q, want %q", tt.fish, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFisherTransformClampsNearSingularity(t *testing.T) {
+	// The last value in the window equal to the window max drives x toward
+	// +1 before clamping; without the clamp, math.Log((1+x)/(1-x)) would be
+	// +Inf.
+	prices := append(repeat(1, 4), 100)
+	fish := fisherTransform(prices, 5)
+	if len(fish) == 0 {
+		t.Fatalf("expected a non-empty result")
+	}
+	if last := fish[len(fish)-1]; math.IsInf(last, 0) || math.IsNaN(last) {
+		t.Errorf("last = %v, want a finite clamped value", last)
+	}
+}
@@ -0,0 +1,190 @@
+package osrs
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"osrs-flipping/pkg/osrs/store"
+)
+
+// ForecastConfig tunes the triple-exponential-smoothing (additive
+// Holt-Winters) model ForecastPrice fits to each item's stored timeseries.
+type ForecastConfig struct {
+	// Alpha, Beta, Gamma are the level/trend/seasonal smoothing factors,
+	// each in [0,1]. Higher values weight recent observations more heavily.
+	Alpha, Beta, Gamma float64
+	// SeasonLength is the seasonal period m, in buckets -- 24 for
+	// hourly-of-day seasonality on the 1h series ForecastPrice uses.
+	SeasonLength int
+	// MinPoints is the fewest stored 1h points ForecastPrice requires (at
+	// least two full seasons, to seed both the level and the trend) before
+	// it will fit a model.
+	MinPoints int
+}
+
+// DefaultForecastConfig returns the default Holt-Winters tuning: a 0.3
+// level, 0.1 trend, and 0.3 seasonal smoothing factor, a 24-bucket
+// (hourly-of-day) season, and a 48-point minimum history.
+func DefaultForecastConfig() ForecastConfig {
+	return ForecastConfig{
+		Alpha:        0.3,
+		Beta:         0.1,
+		Gamma:        0.3,
+		SeasonLength: 24,
+		MinPoints:    48,
+	}
+}
+
+// ForecastPoint is a single predicted bucket returned by ForecastPrice.
+type ForecastPoint struct {
+	// StepsAhead is h, the number of 1h buckets past the last stored point
+	// this forecast covers (1 is the next bucket).
+	StepsAhead     int
+	InstaBuyPrice  float64
+	InstaSellPrice float64
+	Volume         float64
+	// ConfidenceLow/ConfidenceHigh bound the predicted margin
+	// (InstaBuyPrice - InstaSellPrice) at roughly a 95% band: +-1.96
+	// residual standard deviations, widened by sqrt(h) for the growing
+	// uncertainty of a multi-step forecast.
+	ConfidenceLow  float64
+	ConfidenceHigh float64
+}
+
+// holtWintersModel is a fitted additive Holt-Winters smoother for one
+// series, plus the residual spread needed to build a confidence band.
+type holtWintersModel struct {
+	level, trend float64
+	// seasonal holds the most recent SeasonLength seasonal components,
+	// oldest first, so seasonal[(h-1)%season] is S_{t-m+((h-1) mod m)+1}.
+	seasonal       []float64
+	season         int
+	residualStdDev float64
+}
+
+// fitHoltWinters fits an additive Holt-Winters model to y using cfg's
+// smoothing factors and season length m:
+//
+//	L_t = α(y_t − S_{t−m}) + (1−α)(L_{t−1} + T_{t−1})
+//	T_t = β(L_t − L_{t−1}) + (1−β)T_{t−1}
+//	S_t = γ(y_t − L_t) + (1−γ)S_{t−m}
+//
+// L_0 is seeded as the mean of the first season, T_0 as the average
+// per-step slope between the first two seasons, and S_i (i in the first
+// season) as y_i − L_0. It returns an error if y has fewer than two full
+// seasons, since that leaves T_0 unseedable.
+func fitHoltWinters(y []float64, cfg ForecastConfig) (*holtWintersModel, error) {
+	m := cfg.SeasonLength
+	if m <= 0 || len(y) < 2*m {
+		return nil, fmt.Errorf("need at least %d points for a %d-bucket season, got %d", 2*m, m, len(y))
+	}
+
+	season1, season2 := y[:m], y[m:2*m]
+	level := average(season1)
+
+	var trend float64
+	for i := 0; i < m; i++ {
+		trend += (season2[i] - season1[i]) / float64(m)
+	}
+	trend /= float64(m)
+
+	seasonal := make([]float64, len(y))
+	for i, v := range season1 {
+		seasonal[i] = v - level
+	}
+
+	var residuals []float64
+	for t := m; t < len(y); t++ {
+		residuals = append(residuals, y[t]-(level+trend+seasonal[t-m]))
+
+		prevLevel := level
+		level = cfg.Alpha*(y[t]-seasonal[t-m]) + (1-cfg.Alpha)*(level+trend)
+		trend = cfg.Beta*(level-prevLevel) + (1-cfg.Beta)*trend
+		seasonal[t] = cfg.Gamma*(y[t]-level) + (1-cfg.Gamma)*seasonal[t-m]
+	}
+
+	return &holtWintersModel{
+		level:          level,
+		trend:          trend,
+		seasonal:       seasonal[len(seasonal)-m:],
+		season:         m,
+		residualStdDev: computeStats(residuals).StdDev,
+	}, nil
+}
+
+// forecast returns the h-step-ahead point forecast (h >= 1).
+func (hw *holtWintersModel) forecast(h int) float64 {
+	return hw.level + float64(h)*hw.trend + hw.seasonal[(h-1)%hw.season]
+}
+
+// ForecastPrice fits an additive Holt-Winters model independently to an
+// item's stored InstaBuyPrice, InstaSellPrice, and combined-volume 1h
+// series (see fitHoltWinters) and returns point forecasts for the next
+// horizon buckets, each with a residual-based confidence band around the
+// predicted margin. It requires at least a.forecastConfig.MinPoints of
+// stored 1h history.
+func (a *Analyzer) ForecastPrice(itemID int, horizon int) ([]ForecastPoint, error) {
+	points := a.store.GetSeries(itemID, store.Interval1h, time.Time{})
+	if len(points) < a.forecastConfig.MinPoints {
+		return nil, fmt.Errorf("forecast: item %d has %d stored 1h points, need at least %d", itemID, len(points), a.forecastConfig.MinPoints)
+	}
+
+	buyPrices := make([]float64, len(points))
+	sellPrices := make([]float64, len(points))
+	volumes := make([]float64, len(points))
+	for i, p := range points {
+		buyPrices[i] = float64(p.InstaBuyPrice)
+		sellPrices[i] = float64(p.InstaSellPrice)
+		volumes[i] = float64(p.InstaBuyVolume + p.InstaSellVolume)
+	}
+
+	buyModel, err := fitHoltWinters(buyPrices, a.forecastConfig)
+	if err != nil {
+		return nil, fmt.Errorf("forecast: insta-buy price: %w", err)
+	}
+	sellModel, err := fitHoltWinters(sellPrices, a.forecastConfig)
+	if err != nil {
+		return nil, fmt.Errorf("forecast: insta-sell price: %w", err)
+	}
+	volumeModel, err := fitHoltWinters(volumes, a.forecastConfig)
+	if err != nil {
+		return nil, fmt.Errorf("forecast: volume: %w", err)
+	}
+
+	marginResidualStdDev := math.Sqrt(
+		buyModel.residualStdDev*buyModel.residualStdDev + sellModel.residualStdDev*sellModel.residualStdDev,
+	)
+
+	forecasts := make([]ForecastPoint, horizon)
+	for h := 1; h <= horizon; h++ {
+		buy := buyModel.forecast(h)
+		sell := sellModel.forecast(h)
+		margin := buy - sell
+		band := 1.96 * marginResidualStdDev * math.Sqrt(float64(h))
+
+		forecasts[h-1] = ForecastPoint{
+			StepsAhead:     h,
+			InstaBuyPrice:  buy,
+			InstaSellPrice: sell,
+			Volume:         math.Max(0, volumeModel.forecast(h)),
+			ConfidenceLow:  margin - band,
+			ConfidenceHigh: margin + band,
+		}
+	}
+	return forecasts, nil
+}
+
+// ExpectedFlipMargin reports the forecast margin (InstaBuyPrice -
+// InstaSellPrice) hours buckets out from ForecastPrice, and whether the
+// price is "collapsing" -- the confidence band around that margin has
+// dropped to include zero or below, meaning the flip may no longer be
+// profitable by then.
+func (a *Analyzer) ExpectedFlipMargin(itemID int, hours int) (margin float64, collapsing bool, err error) {
+	forecasts, err := a.ForecastPrice(itemID, hours)
+	if err != nil {
+		return 0, false, err
+	}
+	last := forecasts[len(forecasts)-1]
+	return last.InstaBuyPrice - last.InstaSellPrice, last.ConfidenceLow <= 0, nil
+}
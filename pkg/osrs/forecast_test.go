@@ -0,0 +1,118 @@
+package osrs
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"osrs-flipping/pkg/osrs/store"
+)
+
+func TestFitHoltWintersTooShortReturnsError(t *testing.T) {
+	cfg := DefaultForecastConfig()
+	cfg.SeasonLength = 4
+	_, err := fitHoltWinters([]float64{1, 2, 3, 4, 5}, cfg)
+	if err == nil {
+		t.Fatal("expected an error for fewer than two seasons of data")
+	}
+}
+
+func TestFitHoltWintersFlatSeriesForecastsFlat(t *testing.T) {
+	cfg := DefaultForecastConfig()
+	cfg.SeasonLength = 4
+
+	y := repeat(100, 16)
+	model, err := fitHoltWinters(y, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for h := 1; h <= 4; h++ {
+		if got := model.forecast(h); math.Abs(got-100) > 0.01 {
+			t.Errorf("forecast(%d) = %v, want ~100 for a flat series", h, got)
+		}
+	}
+}
+
+func TestFitHoltWintersTrackedRisingTrend(t *testing.T) {
+	cfg := DefaultForecastConfig()
+	cfg.SeasonLength = 4
+	cfg.Alpha, cfg.Beta, cfg.Gamma = 0.5, 0.5, 0.5
+
+	// A steady rise of 10/step across 6 seasons; Holt-Winters should pick up
+	// the trend and keep extrapolating it forward.
+	y := make([]float64, 24)
+	for i := range y {
+		y[i] = 100 + float64(i)*10
+	}
+
+	model, err := fitHoltWinters(y, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model.trend <= 0 {
+		t.Errorf("trend = %v, want > 0 for a steadily rising series", model.trend)
+	}
+
+	next := model.forecast(1)
+	further := model.forecast(4)
+	if further <= next {
+		t.Errorf("forecast(4) = %v should exceed forecast(1) = %v for a rising trend", further, next)
+	}
+}
+
+func TestAnalyzerForecastPriceRequiresMinPoints(t *testing.T) {
+	analyzer := &Analyzer{
+		store:          store.New("", 0, 0),
+		forecastConfig: DefaultForecastConfig(),
+	}
+	if _, err := analyzer.ForecastPrice(1, 4); err == nil {
+		t.Fatal("expected an error when no timeseries is stored for the item")
+	}
+}
+
+func TestAnalyzerForecastPriceAndExpectedFlipMargin(t *testing.T) {
+	s := store.New("", 0, 0)
+	cfg := DefaultForecastConfig()
+	cfg.SeasonLength = 4
+
+	base := time.Now().Add(-time.Duration(2*cfg.MinPoints) * time.Hour)
+	for i := 0; i < 2*cfg.MinPoints; i++ {
+		s.Append(1, store.Interval1h, store.DataPoint{
+			Timestamp:       base.Add(time.Duration(i) * time.Hour),
+			InstaBuyPrice:   1000,
+			InstaSellPrice:  900,
+			InstaBuyVolume:  50,
+			InstaSellVolume: 50,
+		})
+	}
+
+	analyzer := &Analyzer{store: s, forecastConfig: cfg}
+
+	forecasts, err := analyzer.ForecastPrice(1, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(forecasts) != 3 {
+		t.Fatalf("expected 3 forecast points, got %d", len(forecasts))
+	}
+	for _, f := range forecasts {
+		if math.Abs(f.InstaBuyPrice-1000) > 1 {
+			t.Errorf("InstaBuyPrice = %v, want ~1000 for a flat series", f.InstaBuyPrice)
+		}
+		if math.Abs(f.InstaSellPrice-900) > 1 {
+			t.Errorf("InstaSellPrice = %v, want ~900 for a flat series", f.InstaSellPrice)
+		}
+	}
+
+	margin, collapsing, err := analyzer.ExpectedFlipMargin(1, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(margin-100) > 2 {
+		t.Errorf("margin = %v, want ~100", margin)
+	}
+	if collapsing {
+		t.Error("a flat, stable 100gp margin should not be flagged as collapsing")
+	}
+}
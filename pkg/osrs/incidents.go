@@ -0,0 +1,242 @@
+package osrs
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"osrs-flipping/pkg/osrs/store"
+)
+
+// IncidentSeverity classifies how far a sustained margin window has
+// climbed above the configured thresholds.
+type IncidentSeverity string
+
+const (
+	IncidentWarning  IncidentSeverity = "warning"
+	IncidentCritical IncidentSeverity = "critical"
+)
+
+// Incident is a sustained arbitrage window: one item's flip margin stayed
+// at or above IncidentConfig.WarnMargin for at least WarnBuckets
+// consecutive 5m buckets (and, if it climbed further, at or above
+// CriticalMargin for CriticalBuckets), mirroring the warn/critical
+// threshold state machine cloud-monitoring alert rules use for sustained
+// breaches rather than single-sample spikes. End is the zero Time while the
+// incident is still open.
+type Incident struct {
+	ItemID     int
+	Start      time.Time
+	End        time.Time
+	Severity   IncidentSeverity
+	PeakMargin int
+}
+
+// IncidentConfig tunes DetectIncidents' warn/critical/cooldown state
+// machine.
+type IncidentConfig struct {
+	// WarnMargin and CriticalMargin are flip-margin thresholds, in gp.
+	WarnMargin     int
+	CriticalMargin int
+
+	// WarnBuckets and CriticalBuckets are how many consecutive 5m buckets
+	// the margin must stay at or above the matching threshold before an
+	// incident opens (or upgrades to critical).
+	WarnBuckets     int
+	CriticalBuckets int
+
+	// CooldownBuckets is how many consecutive buckets the margin must stay
+	// below WarnMargin before an open incident closes, so a single dip
+	// doesn't flap an incident open/closed every 5 minutes.
+	CooldownBuckets int
+}
+
+// DefaultIncidentConfig returns conservative thresholds suitable as a
+// starting point; override per deployment via SetIncidentConfig once you
+// know an item's typical margin volatility.
+func DefaultIncidentConfig() IncidentConfig {
+	return IncidentConfig{
+		WarnMargin:      50,
+		CriticalMargin:  150,
+		WarnBuckets:     3,
+		CriticalBuckets: 6,
+		CooldownBuckets: 3,
+	}
+}
+
+// IncidentNotifier is notified whenever DetectIncidents opens, upgrades, or
+// closes an incident, so users learn about arbitrage windows opening and
+// closing instead of polling RecentIncidents -- an optional dependency,
+// the same shape as scheduler.BreakerNotifier. discord.Bot can satisfy this
+// directly, or a small webhook wrapper can.
+type IncidentNotifier interface {
+	NotifyIncident(incident Incident) error
+}
+
+// itemIncidentState is the mutable per-item bookkeeping behind the
+// warn/critical/cooldown state machine, guarded by incidentStore.mu.
+type itemIncidentState struct {
+	open           *Incident
+	warnStreak     int
+	critStreak     int
+	cooldownStreak int
+	lastProcessed  time.Time
+}
+
+// maxClosedIncidents caps how many closed incidents incidentStore retains
+// for RecentIncidents, evicting the oldest once exceeded.
+const maxClosedIncidents = 1000
+
+// incidentStore is Analyzer's small state store of open and recently
+// closed incidents, keyed by item ID.
+type incidentStore struct {
+	mu     sync.Mutex
+	states map[int]*itemIncidentState
+	closed []Incident
+}
+
+func newIncidentStore() *incidentStore {
+	return &incidentStore{states: make(map[int]*itemIncidentState)}
+}
+
+// SetIncidentConfig overrides the warn/critical margin thresholds and
+// bucket counts DetectIncidents uses, e.g. to loosen them for a
+// known-volatile item.
+func (a *Analyzer) SetIncidentConfig(cfg IncidentConfig) {
+	a.incidentConfig = cfg
+}
+
+// SetIncidentNotifier wires n into the analyzer so DetectIncidents posts a
+// notice each time an incident opens, upgrades, or closes. Optional:
+// without one, incidents are only visible via RecentIncidents.
+func (a *Analyzer) SetIncidentNotifier(n IncidentNotifier) {
+	a.incidentNotifier = n
+}
+
+// DetectIncidents scans itemID's stored 5m series for sustained margin
+// windows since the last call, advances the warn/critical/cooldown state
+// machine, and returns any incidents that opened, upgraded, or closed
+// during this scan. Call it after LoadVolumeData populates the 5m store for
+// itemID, e.g. once per scheduler tick.
+func (a *Analyzer) DetectIncidents(itemID int) []Incident {
+	points := a.store.GetSeries(itemID, store.Interval5m, time.Time{})
+	events := a.incidents.scan(itemID, points, a.incidentConfig)
+
+	for _, incident := range events {
+		if a.incidentNotifier == nil {
+			continue
+		}
+		if err := a.incidentNotifier.NotifyIncident(incident); err != nil {
+			fmt.Printf("⚠️  failed to notify incident for item %d: %v\n", itemID, err)
+		}
+	}
+
+	return events
+}
+
+// RecentIncidents returns every incident (open or closed) whose Start is at
+// or after since, across all items.
+func (a *Analyzer) RecentIncidents(since time.Time) []Incident {
+	return a.incidents.recent(since)
+}
+
+// scan walks points after state.lastProcessed in timestamp order, advancing
+// the warn/critical/cooldown counters and returning a copy of state.open
+// each time it opens, upgrades severity, or closes.
+func (s *incidentStore) scan(itemID int, points []store.DataPoint, cfg IncidentConfig) []Incident {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[itemID]
+	if !ok {
+		state = &itemIncidentState{}
+		s.states[itemID] = state
+	}
+
+	var events []Incident
+	for _, p := range points {
+		if !p.Timestamp.After(state.lastProcessed) {
+			continue
+		}
+		state.lastProcessed = p.Timestamp
+
+		margin := p.InstaBuyPrice - p.InstaSellPrice
+
+		if margin < cfg.WarnMargin {
+			state.warnStreak = 0
+			state.critStreak = 0
+
+			if state.open == nil {
+				continue
+			}
+			state.cooldownStreak++
+			if state.cooldownStreak < cfg.CooldownBuckets {
+				continue
+			}
+			state.open.End = p.Timestamp
+			events = append(events, *state.open)
+			s.closeIncident(*state.open)
+			state.open = nil
+			state.cooldownStreak = 0
+			continue
+		}
+
+		state.cooldownStreak = 0
+		state.warnStreak++
+		if margin >= cfg.CriticalMargin {
+			state.critStreak++
+		} else {
+			state.critStreak = 0
+		}
+
+		switch {
+		case state.open == nil && state.warnStreak >= cfg.WarnBuckets:
+			state.open = &Incident{
+				ItemID:     itemID,
+				Start:      p.Timestamp,
+				Severity:   IncidentWarning,
+				PeakMargin: margin,
+			}
+			events = append(events, *state.open)
+		case state.open != nil:
+			if margin > state.open.PeakMargin {
+				state.open.PeakMargin = margin
+			}
+			if state.open.Severity == IncidentWarning && state.critStreak >= cfg.CriticalBuckets {
+				state.open.Severity = IncidentCritical
+				events = append(events, *state.open)
+			}
+		}
+	}
+
+	return events
+}
+
+// closeIncident records incident in the closed history, evicting the
+// oldest entry once maxClosedIncidents is exceeded.
+func (s *incidentStore) closeIncident(incident Incident) {
+	s.closed = append(s.closed, incident)
+	if len(s.closed) > maxClosedIncidents {
+		s.closed = s.closed[len(s.closed)-maxClosedIncidents:]
+	}
+}
+
+// recent returns every closed incident (and any still-open one) whose
+// Start is at or after since.
+func (s *incidentStore) recent(since time.Time) []Incident {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Incident
+	for _, incident := range s.closed {
+		if !incident.Start.Before(since) {
+			out = append(out, incident)
+		}
+	}
+	for _, state := range s.states {
+		if state.open != nil && !state.open.Start.Before(since) {
+			out = append(out, *state.open)
+		}
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+package osrs
+
+import (
+	"testing"
+	"time"
+
+	"osrs-flipping/pkg/osrs/store"
+)
+
+// seedMargins appends one 5m DataPoint per margin value, five minutes
+// apart, so DetectIncidents sees a clean consecutive-bucket series.
+func seedMargins(s *store.Store, itemID int, base time.Time, margins []int) {
+	for i, margin := range margins {
+		s.Append(itemID, store.Interval5m, store.DataPoint{
+			Timestamp:      base.Add(time.Duration(i) * 5 * time.Minute),
+			InstaBuyPrice:  1000 + margin,
+			InstaSellPrice: 1000,
+		})
+	}
+}
+
+func newTestIncidentAnalyzer() (*Analyzer, *store.Store) {
+	s := store.New("", 0, 0)
+	return &Analyzer{
+		store:          s,
+		incidentConfig: DefaultIncidentConfig(),
+		incidents:      newIncidentStore(),
+	}, s
+}
+
+func TestDetectIncidentsNoneBelowWarnThreshold(t *testing.T) {
+	analyzer, s := newTestIncidentAnalyzer()
+	base := time.Now().Add(-time.Hour)
+	seedMargins(s, 1, base, []int{10, 10, 10, 10, 10})
+
+	events := analyzer.DetectIncidents(1)
+	if len(events) != 0 {
+		t.Errorf("expected no incidents for a margin that never reaches WarnMargin, got %+v", events)
+	}
+	if recent := analyzer.RecentIncidents(base); len(recent) != 0 {
+		t.Errorf("expected no recent incidents, got %+v", recent)
+	}
+}
+
+func TestDetectIncidentsOpensAndClosesWarning(t *testing.T) {
+	analyzer, s := newTestIncidentAnalyzer()
+	base := time.Now().Add(-time.Hour)
+	// WarnBuckets=3 at WarnMargin=50, then CooldownBuckets=3 below it.
+	seedMargins(s, 1, base, []int{60, 60, 60, 60, 10, 10, 10})
+
+	events := analyzer.DetectIncidents(1)
+
+	var opened, closed bool
+	for _, e := range events {
+		if e.End.IsZero() {
+			opened = true
+			if e.Severity != IncidentWarning {
+				t.Errorf("expected the opening event to be IncidentWarning, got %v", e.Severity)
+			}
+		} else {
+			closed = true
+		}
+	}
+	if !opened {
+		t.Errorf("expected a warning incident to open, got %+v", events)
+	}
+	if !closed {
+		t.Errorf("expected the incident to close after the cooldown buckets, got %+v", events)
+	}
+
+	if recent := analyzer.RecentIncidents(base); len(recent) != 1 {
+		t.Errorf("expected exactly 1 recent incident after closing, got %d: %+v", len(recent), recent)
+	}
+}
+
+func TestDetectIncidentsUpgradesToCritical(t *testing.T) {
+	analyzer, s := newTestIncidentAnalyzer()
+	base := time.Now().Add(-time.Hour)
+	// 3 buckets over WarnMargin opens the incident, then 6 more over
+	// CriticalMargin upgrades it; never dips back below WarnMargin.
+	margins := []int{60, 60, 60}
+	for i := 0; i < 6; i++ {
+		margins = append(margins, 200)
+	}
+	seedMargins(s, 1, base, margins)
+
+	events := analyzer.DetectIncidents(1)
+
+	var sawWarningOpen, sawCriticalUpgrade bool
+	var peak int
+	for _, e := range events {
+		if e.Severity == IncidentWarning {
+			sawWarningOpen = true
+		}
+		if e.Severity == IncidentCritical {
+			sawCriticalUpgrade = true
+		}
+		peak = e.PeakMargin
+	}
+	if !sawWarningOpen {
+		t.Errorf("expected the incident to first open as IncidentWarning, got %+v", events)
+	}
+	if !sawCriticalUpgrade {
+		t.Errorf("expected the incident to upgrade to IncidentCritical, got %+v", events)
+	}
+	if peak != 200 {
+		t.Errorf("expected PeakMargin to track the highest observed margin (200), got %d", peak)
+	}
+
+	// Still open: not yet in RecentIncidents' closed history, but should
+	// surface via the open state.
+	recent := analyzer.RecentIncidents(base)
+	if len(recent) != 1 || recent[0].Severity != IncidentCritical {
+		t.Errorf("expected 1 still-open critical incident, got %+v", recent)
+	}
+}
+
+func TestDetectIncidentsIsIncrementalAcrossCalls(t *testing.T) {
+	analyzer, s := newTestIncidentAnalyzer()
+	base := time.Now().Add(-time.Hour)
+	seedMargins(s, 1, base, []int{60, 60, 60})
+
+	first := analyzer.DetectIncidents(1)
+	if len(first) != 1 {
+		t.Fatalf("expected the first scan to open 1 incident, got %+v", first)
+	}
+
+	// Calling again with no new points should not re-emit the same event.
+	second := analyzer.DetectIncidents(1)
+	if len(second) != 0 {
+		t.Errorf("expected no duplicate events on a re-scan with no new points, got %+v", second)
+	}
+}
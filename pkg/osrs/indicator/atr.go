@@ -0,0 +1,55 @@
+package indicator
+
+import "math"
+
+// ATR is a streaming Average True Range using Wilder's smoothing, fed one
+// high/low/close tick at a time instead of trend.go's flat-array ATR, which
+// recomputes over a whole slice each call.
+type ATR struct {
+	values
+	Window int
+
+	prevClose float64
+	seeded    bool
+	value     float64
+}
+
+// NewATR creates an ATR smoothed over the last window true ranges.
+func NewATR(window int) *ATR {
+	if window <= 0 {
+		window = 1
+	}
+	return &ATR{Window: window}
+}
+
+// UpdateHLC feeds the next tick's high/low/close and returns the updated
+// ATR. The first tick has no previous close to measure a true range
+// against, so it seeds the ATR with high-low alone.
+func (a *ATR) UpdateHLC(high, low, close float64) float64 {
+	var tr float64
+	if !a.seeded {
+		tr = high - low
+		a.seeded = true
+	} else {
+		hl := high - low
+		hc := math.Abs(high - a.prevClose)
+		lc := math.Abs(low - a.prevClose)
+		tr = math.Max(hl, math.Max(hc, lc))
+	}
+	a.prevClose = close
+
+	if a.Length() == 0 {
+		a.value = tr
+	} else {
+		a.value = (a.value*float64(a.Window-1) + tr) / float64(a.Window)
+	}
+	return a.push(a.value)
+}
+
+// Update feeds a single price value (no separate high/low) by treating it
+// as a degenerate tick where high == low == close, satisfying the
+// Indicator interface for callers that only have one price per tick.
+// Prefer UpdateHLC when a high/low spread is available.
+func (a *ATR) Update(value float64) float64 {
+	return a.UpdateHLC(value, value, value)
+}
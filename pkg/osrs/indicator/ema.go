@@ -0,0 +1,31 @@
+package indicator
+
+// EMA is a streaming exponential moving average with smoothing factor
+// 2/(Window+1), the standard EMA weighting.
+type EMA struct {
+	values
+	Window int
+	alpha  float64
+	prev   float64
+	seeded bool
+}
+
+// NewEMA creates an EMA with the standard 2/(window+1) smoothing factor.
+func NewEMA(window int) *EMA {
+	if window <= 0 {
+		window = 1
+	}
+	return &EMA{Window: window, alpha: 2.0 / float64(window+1)}
+}
+
+// Update feeds the next value in and returns the current EMA, seeding with
+// the first value fed rather than a full SMA warm-up period.
+func (e *EMA) Update(value float64) float64 {
+	if !e.seeded {
+		e.prev = value
+		e.seeded = true
+		return e.push(value)
+	}
+	e.prev = e.alpha*value + (1-e.alpha)*e.prev
+	return e.push(e.prev)
+}
@@ -0,0 +1,59 @@
+package indicator
+
+// EWO is a streaming Elliott-Wave Oscillator: the difference between a fast
+// and a slow SMA of the same price series. A positive, growing value signals
+// accelerating upward momentum; a sign change signals the trend may be
+// turning, which SignFlips below counts over a trailing window.
+type EWO struct {
+	values
+	fast *SMA
+	slow *SMA
+}
+
+// NewEWO creates an EWO from a fast/slow SMA window pair, e.g. 3 and 19.
+func NewEWO(fastWindow, slowWindow int) *EWO {
+	return &EWO{fast: NewSMA(fastWindow), slow: NewSMA(slowWindow)}
+}
+
+// Update feeds the next price and returns the updated fast-SMA minus
+// slow-SMA oscillator value.
+func (e *EWO) Update(value float64) float64 {
+	fast := e.fast.Update(value)
+	slow := e.slow.Update(value)
+	return e.push(fast - slow)
+}
+
+// SignFlips counts how many times the oscillator's sign changed across the
+// last lookback computed values (fewer than lookback available still counts
+// over whatever history exists). A zero value never counts as either sign,
+// so a flip either side of an exact-zero reading still registers once the
+// oscillator clears it.
+func (e *EWO) SignFlips(lookback int) int {
+	n := e.Length()
+	if n > lookback {
+		n = lookback
+	}
+	if n < 2 {
+		return 0
+	}
+
+	flips := 0
+	prevSign := 0
+	for i := n - 1; i >= 0; i-- {
+		v := e.Last(i)
+		sign := 0
+		switch {
+		case v > 0:
+			sign = 1
+		case v < 0:
+			sign = -1
+		}
+		if sign != 0 && prevSign != 0 && sign != prevSign {
+			flips++
+		}
+		if sign != 0 {
+			prevSign = sign
+		}
+	}
+	return flips
+}
@@ -0,0 +1,44 @@
+// Package indicator provides streaming technical indicators over historical
+// price data, following the bbgo-style Update/Last interface: feed values in
+// one at a time as history is replayed, then read back the current or a
+// past value without re-scanning the whole series. This complements
+// pkg/osrs's existing flat-array ATR (see trend.go), which recomputes over
+// a fixed slice each call -- these types are for callers (like Analyzer's
+// derived columns) that want to fold a long rolling series incrementally.
+package indicator
+
+// Indicator is the shared interface every streaming indicator in this
+// package implements.
+type Indicator interface {
+	// Update feeds the next raw value (e.g. a closing price) into the
+	// indicator and returns the newly computed value.
+	Update(value float64) float64
+	// Last returns the i-th most recent computed value (0 = most recent).
+	// It returns 0 if fewer than i+1 values have been computed yet.
+	Last(i int) float64
+	// Length reports how many values have been computed so far.
+	Length() int
+}
+
+// values is embedded by every indicator below to store its computed
+// history and implement Last/Length identically.
+type values struct {
+	history []float64
+}
+
+func (v *values) push(val float64) float64 {
+	v.history = append(v.history, val)
+	return val
+}
+
+func (v *values) Last(i int) float64 {
+	idx := len(v.history) - 1 - i
+	if idx < 0 || idx >= len(v.history) {
+		return 0
+	}
+	return v.history[idx]
+}
+
+func (v *values) Length() int {
+	return len(v.history)
+}
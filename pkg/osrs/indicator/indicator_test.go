@@ -0,0 +1,118 @@
+package indicator
+
+import "testing"
+
+func TestSMA(t *testing.T) {
+	sma := NewSMA(3)
+	sma.Update(1)
+	sma.Update(2)
+	got := sma.Update(3)
+	if got != 2 {
+		t.Errorf("SMA(3).Update sequence = %v, want 2", got)
+	}
+	got = sma.Update(6)
+	if got != (2.0+3.0+6.0)/3.0 {
+		t.Errorf("SMA should drop the oldest value once past window, got %v", got)
+	}
+	if sma.Last(0) != got {
+		t.Errorf("Last(0) = %v, want the most recent update %v", sma.Last(0), got)
+	}
+}
+
+func TestEMA(t *testing.T) {
+	ema := NewEMA(2) // alpha = 2/3
+	first := ema.Update(10)
+	if first != 10 {
+		t.Errorf("first EMA update should seed with the raw value, got %v", first)
+	}
+	second := ema.Update(16)
+	want := (2.0/3.0)*16 + (1.0/3.0)*10
+	if second != want {
+		t.Errorf("EMA.Update = %v, want %v", second, want)
+	}
+}
+
+func TestWMA(t *testing.T) {
+	wma := NewWMA(3)
+	wma.Update(1)
+	wma.Update(2)
+	got := wma.Update(3)
+	want := (1.0*1 + 2.0*2 + 3.0*3) / (1.0 + 2.0 + 3.0)
+	if got != want {
+		t.Errorf("WMA(3).Update sequence = %v, want %v", got, want)
+	}
+
+	got = wma.Update(6)
+	want = (2.0*1 + 3.0*2 + 6.0*3) / (1.0 + 2.0 + 3.0)
+	if got != want {
+		t.Errorf("WMA should drop the oldest value once past window, got %v, want %v", got, want)
+	}
+	if wma.Last(0) != got {
+		t.Errorf("Last(0) = %v, want the most recent update %v", wma.Last(0), got)
+	}
+}
+
+func TestATR_UpdateHLC(t *testing.T) {
+	atr := NewATR(3)
+	first := atr.UpdateHLC(110, 100, 105) // seed: high-low = 10
+	if first != 10 {
+		t.Errorf("first ATR tick should seed with high-low, got %v", first)
+	}
+
+	second := atr.UpdateHLC(120, 108, 115)
+	// true range = max(12, |120-105|=15, |108-105|=3) = 15
+	// smoothed = (10*2 + 15) / 3
+	want := (10.0*2 + 15.0) / 3.0
+	if second != want {
+		t.Errorf("ATR.UpdateHLC = %v, want %v", second, want)
+	}
+	if atr.Last(0) != second {
+		t.Errorf("Last(0) = %v, want %v", atr.Last(0), second)
+	}
+}
+
+func TestATR_UpdateDegenerateSinglePrice(t *testing.T) {
+	atr := NewATR(2)
+	if got := atr.Update(100); got != 0 {
+		t.Errorf("a single-price tick has zero high-low spread, expected ATR seed of 0, got %v", got)
+	}
+}
+
+func TestEWO_Update(t *testing.T) {
+	ewo := NewEWO(1, 2)
+	first := ewo.Update(10) // fast SMA(1)=10, slow SMA(2)=10
+	if first != 0 {
+		t.Errorf("first EWO.Update = %v, want 0 (fast and slow seed equal)", first)
+	}
+
+	second := ewo.Update(20) // fast=20, slow=(10+20)/2=15
+	if second != 5 {
+		t.Errorf("second EWO.Update = %v, want 5", second)
+	}
+	if ewo.Last(0) != second {
+		t.Errorf("Last(0) = %v, want %v", ewo.Last(0), second)
+	}
+}
+
+func TestEWO_SignFlipsCountsAlternation(t *testing.T) {
+	// fast=1 tracks each raw value instantly; slow=20 lags behind as a
+	// cumulative average, so every update but the very first (where both
+	// are seeded from the same lone sample) alternates sign with the input.
+	ewo := NewEWO(1, 20)
+	for _, v := range []float64{10, -10, 10, -10, 10} {
+		ewo.Update(v)
+	}
+	if got := ewo.SignFlips(10); got != 3 {
+		t.Errorf("SignFlips = %d, want 3", got)
+	}
+}
+
+func TestEWO_SignFlipsRespectsLookback(t *testing.T) {
+	ewo := NewEWO(1, 20)
+	for _, v := range []float64{10, -10, 10, -10, 10} {
+		ewo.Update(v)
+	}
+	if got := ewo.SignFlips(2); got != 1 {
+		t.Errorf("SignFlips(2) = %d, want 1 (only the last two values considered)", got)
+	}
+}
@@ -0,0 +1,31 @@
+package indicator
+
+// SMA is a streaming simple moving average over the last Window values fed
+// to Update.
+type SMA struct {
+	values
+	Window int
+	window []float64
+}
+
+// NewSMA creates an SMA averaging over the last window values.
+func NewSMA(window int) *SMA {
+	if window <= 0 {
+		window = 1
+	}
+	return &SMA{Window: window}
+}
+
+// Update feeds the next value in and returns the current average.
+func (s *SMA) Update(value float64) float64 {
+	s.window = append(s.window, value)
+	if len(s.window) > s.Window {
+		s.window = s.window[len(s.window)-s.Window:]
+	}
+
+	var sum float64
+	for _, v := range s.window {
+		sum += v
+	}
+	return s.push(sum / float64(len(s.window)))
+}
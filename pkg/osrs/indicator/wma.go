@@ -0,0 +1,35 @@
+package indicator
+
+// WMA is a streaming weighted moving average over the last Window values fed
+// to Update, weighting more recent values more heavily (weight i+1 for the
+// i-th oldest value in the window) -- unlike SMA, which weights every value
+// in the window equally.
+type WMA struct {
+	values
+	Window int
+	window []float64
+}
+
+// NewWMA creates a WMA averaging over the last window values.
+func NewWMA(window int) *WMA {
+	if window <= 0 {
+		window = 1
+	}
+	return &WMA{Window: window}
+}
+
+// Update feeds the next value in and returns the current weighted average.
+func (w *WMA) Update(value float64) float64 {
+	w.window = append(w.window, value)
+	if len(w.window) > w.Window {
+		w.window = w.window[len(w.window)-w.Window:]
+	}
+
+	var weightedSum, weightTotal float64
+	for i, v := range w.window {
+		weight := float64(i + 1)
+		weightedSum += v * weight
+		weightTotal += weight
+	}
+	return w.push(weightedSum / weightTotal)
+}
@@ -0,0 +1,181 @@
+// Package metrics exposes an osrs.Analyzer's loaded items and scrape health
+// as Prometheus metrics, so a long-running process can mount Handler()
+// wherever it already serves HTTP (e.g. at /metrics) and point
+// Grafana/alerting at the result, instead of only reading the one-shot CLI
+// output.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"osrs-flipping/pkg/osrs"
+	"osrs-flipping/pkg/osrs/store"
+)
+
+// staleThresholds is how long a series can go unfetched before itemCollector
+// counts it as stale -- twice the cache TTL calculateVolumeMetrics itself
+// trusts for that interval (cacheTTL5m/cacheTTL24h in pkg/osrs/volume.go),
+// giving a normal polling cadence room to catch up after one missed cycle
+// before anyone pages on it.
+var staleThresholds = map[store.Interval]time.Duration{
+	store.Interval5m:  2 * 6 * time.Minute,
+	store.Interval24h: 2 * 25 * time.Hour,
+}
+
+// Metrics is the analyzer's self-contained Prometheus registry and
+// instrument set. It doesn't run its own HTTP server -- the rest of the app
+// mounts Handler() wherever it already serves HTTP (e.g. at /metrics).
+type Metrics struct {
+	registry *prometheus.Registry
+
+	scrapeErrors   *prometheus.CounterVec
+	scrapeDuration *prometheus.HistogramVec
+	lastScrapeTime *prometheus.GaugeVec
+	scrapeHealth   *prometheus.GaugeVec
+}
+
+// New builds a Metrics instance that publishes a live snapshot of a's
+// loaded items plus scrape-health telemetry reported through RecordScrape.
+// Call a.SetScrapeRecorder(m) on the result to wire the two together --
+// New itself only reads a, it doesn't register as its recorder.
+func New(a *osrs.Analyzer) *Metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &Metrics{
+		registry: registry,
+		scrapeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "osrs_scrape_errors_total",
+			Help: "Count of failed API fetches, by endpoint (mapping, latest, 5m, 24h).",
+		}, []string{"endpoint"}),
+		scrapeDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "osrs_scrape_duration_seconds",
+			Help:    "Duration of each API fetch, by endpoint.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		lastScrapeTime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "osrs_last_scrape_time_seconds",
+			Help: "Unix time of the most recent fetch attempt, by endpoint.",
+		}, []string{"endpoint"}),
+		scrapeHealth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "osrs_scrape_health",
+			Help: "1 if the most recent fetch for this endpoint succeeded, 0 if it failed.",
+		}, []string{"endpoint"}),
+	}
+
+	registry.MustRegister(
+		m.scrapeErrors,
+		m.scrapeDuration,
+		m.lastScrapeTime,
+		m.scrapeHealth,
+		newItemCollector(a),
+	)
+	return m
+}
+
+// RecordScrape implements osrs.ScrapeRecorder, publishing one endpoint's
+// fetch outcome and duration.
+func (m *Metrics) RecordScrape(endpoint string, start time.Time, err error) {
+	m.scrapeDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+	m.lastScrapeTime.WithLabelValues(endpoint).Set(float64(time.Now().Unix()))
+
+	health := 1.0
+	if err != nil {
+		m.scrapeErrors.WithLabelValues(endpoint).Inc()
+		health = 0
+	}
+	m.scrapeHealth.WithLabelValues(endpoint).Set(health)
+}
+
+// Handler returns an http.Handler serving this registry's metrics in the
+// standard Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// itemCollector computes the per-item price/margin/volume/VWAP gauges and
+// the stale-series count from the analyzer's live state at scrape time,
+// rather than via a periodically ticked goroutine -- the same shape as
+// scheduler.schedulerCollector and collector.rateBreakerCollector.
+type itemCollector struct {
+	a *osrs.Analyzer
+
+	instaBuyPrice  *prometheus.Desc
+	instaSellPrice *prometheus.Desc
+	marginGP       *prometheus.Desc
+	volume24h      *prometheus.Desc
+	vwap24h        *prometheus.Desc
+	staleSeries    *prometheus.Desc
+}
+
+func newItemCollector(a *osrs.Analyzer) *itemCollector {
+	return &itemCollector{
+		a: a,
+		instaBuyPrice: prometheus.NewDesc("osrs_insta_buy_price",
+			"Most recent insta-buy price.", []string{"item_id", "item_name"}, nil),
+		instaSellPrice: prometheus.NewDesc("osrs_insta_sell_price",
+			"Most recent insta-sell price.", []string{"item_id", "item_name"}, nil),
+		marginGP: prometheus.NewDesc("osrs_margin_gp",
+			"Computed insta-buy minus insta-sell margin, in gp.", []string{"item_id", "item_name"}, nil),
+		volume24h: prometheus.NewDesc("osrs_volume_24h",
+			"24h traded volume, by side.", []string{"item_id", "item_name", "side"}, nil),
+		vwap24h: prometheus.NewDesc("osrs_vwap_24h",
+			"24h volume-weighted average price, by side.", []string{"item_id", "item_name", "side"}, nil),
+		staleSeries: prometheus.NewDesc("osrs_stale_series_total",
+			"Count of loaded items whose series hasn't been refetched within twice its cache TTL.", []string{"interval"}, nil),
+	}
+}
+
+func (c *itemCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.instaBuyPrice
+	ch <- c.instaSellPrice
+	ch <- c.marginGP
+	ch <- c.volume24h
+	ch <- c.vwap24h
+	ch <- c.staleSeries
+}
+
+func (c *itemCollector) Collect(ch chan<- prometheus.Metric) {
+	items := c.a.GetData()
+	stale := make(map[store.Interval]int, len(staleThresholds))
+
+	for _, item := range items {
+		id := strconv.Itoa(item.ItemID)
+
+		if item.InstaBuyPrice != nil {
+			ch <- prometheus.MustNewConstMetric(c.instaBuyPrice, prometheus.GaugeValue, float64(*item.InstaBuyPrice), id, item.Name)
+		}
+		if item.InstaSellPrice != nil {
+			ch <- prometheus.MustNewConstMetric(c.instaSellPrice, prometheus.GaugeValue, float64(*item.InstaSellPrice), id, item.Name)
+		}
+		ch <- prometheus.MustNewConstMetric(c.marginGP, prometheus.GaugeValue, float64(item.MarginGP), id, item.Name)
+
+		if item.InstaBuyVolume24h != nil {
+			ch <- prometheus.MustNewConstMetric(c.volume24h, prometheus.GaugeValue, *item.InstaBuyVolume24h, id, item.Name, "buy")
+		}
+		if item.InstaSellVolume24h != nil {
+			ch <- prometheus.MustNewConstMetric(c.volume24h, prometheus.GaugeValue, *item.InstaSellVolume24h, id, item.Name, "sell")
+		}
+		if item.VWAPInstaBuy24h != nil {
+			ch <- prometheus.MustNewConstMetric(c.vwap24h, prometheus.GaugeValue, *item.VWAPInstaBuy24h, id, item.Name, "buy")
+		}
+		if item.VWAPInstaSell24h != nil {
+			ch <- prometheus.MustNewConstMetric(c.vwap24h, prometheus.GaugeValue, *item.VWAPInstaSell24h, id, item.Name, "sell")
+		}
+
+		for interval, ttl := range staleThresholds {
+			lastFetched, ok := c.a.LastFetched(item.ItemID, interval)
+			if !ok || time.Since(lastFetched) > ttl {
+				stale[interval]++
+			}
+		}
+	}
+
+	for interval := range staleThresholds {
+		ch <- prometheus.MustNewConstMetric(c.staleSeries, prometheus.GaugeValue, float64(stale[interval]), string(interval))
+	}
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"osrs-flipping/pkg/osrs"
+	"osrs-flipping/pkg/osrs/store"
+)
+
+func newTestAnalyzer() *osrs.Analyzer {
+	a := osrs.NewAnalyzer("test-agent")
+	a.SetStore(store.New("", 0, 0))
+	return a
+}
+
+func TestHandlerServesRegistry(t *testing.T) {
+	m := New(newTestAnalyzer())
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 from the metrics handler, got %d", rec.Code)
+	}
+}
+
+func TestRecordScrapeReportsHealthAndErrors(t *testing.T) {
+	m := New(newTestAnalyzer())
+
+	m.RecordScrape("latest", time.Now(), nil)
+	m.RecordScrape("5m", time.Now(), errors.New("boom"))
+
+	families, err := m.registry.Gather()
+	if err != nil {
+		t.Fatalf("gathering registry: %v", err)
+	}
+
+	var sawScrapeHealth, sawScrapeErrors bool
+	for _, family := range families {
+		switch family.GetName() {
+		case "osrs_scrape_health":
+			sawScrapeHealth = true
+		case "osrs_scrape_errors_total":
+			sawScrapeErrors = true
+		}
+	}
+	if !sawScrapeHealth {
+		t.Error("expected osrs_scrape_health to be reported after RecordScrape")
+	}
+	if !sawScrapeErrors {
+		t.Error("expected osrs_scrape_errors_total to be reported after a failed RecordScrape")
+	}
+}
@@ -0,0 +1,423 @@
+package osrs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rollingWindowSize caps how many recent call outcomes a providerBreaker
+// keeps to evaluate BreakerConfig.ErrorPercentThreshold against.
+const rollingWindowSize = 20
+
+// BreakerConfig tunes a single provider's circuit breaker within
+// MultiProviderDataSource.
+type BreakerConfig struct {
+	// Timeout bounds how long a single LoadPrices/LoadVolumeData call
+	// against this provider is allowed to run before its context is
+	// canceled and the call counts as a failure.
+	Timeout time.Duration
+
+	// SleepWindow is how long the breaker stays open after tripping before
+	// a single half-open probe call is let through.
+	SleepWindow time.Duration
+
+	// ErrorPercentThreshold trips the breaker once the error rate over the
+	// last rollingWindowSize calls (once at least RequestVolumeThreshold of
+	// them have happened) reaches this percentage.
+	ErrorPercentThreshold int
+
+	// RequestVolumeThreshold is the minimum number of calls in the rolling
+	// window before ErrorPercentThreshold is evaluated at all, so a single
+	// early failure doesn't trip the breaker.
+	RequestVolumeThreshold int
+
+	// MaxConcurrentRequests caps how many calls against this provider can
+	// be in flight at once.
+	MaxConcurrentRequests int
+}
+
+// DefaultBreakerConfig returns conservative breaker tuning suitable as a
+// starting point for a freshly added provider.
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		Timeout:                10 * time.Second,
+		SleepWindow:            30 * time.Second,
+		ErrorPercentThreshold:  50,
+		RequestVolumeThreshold: 5,
+		MaxConcurrentRequests:  10,
+	}
+}
+
+// breakerState is a provider breaker's circuit state.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// providerBreaker is the circuit breaker guarding a single provider inside
+// MultiProviderDataSource -- tripped by a sustained error rate rather than
+// scheduler.breakerState's simpler consecutive-failure count, since a
+// flaky upstream API can fail intermittently without ever stringing
+// together enough consecutive errors to matter.
+type providerBreaker struct {
+	mu       sync.Mutex
+	cfg      BreakerConfig
+	state    breakerState
+	openedAt time.Time
+	inFlight int
+	results  []bool
+}
+
+func newProviderBreaker(cfg BreakerConfig) *providerBreaker {
+	return &providerBreaker{cfg: cfg}
+}
+
+// allow reports whether a call should be let through right now. A true
+// result reserves a concurrency slot that the caller must release via
+// release() once the call completes. While open it lets nothing through
+// until SleepWindow elapses, then allows exactly one half-open probe.
+func (b *providerBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cfg.SleepWindow {
+			return false
+		}
+		b.state = breakerHalfOpen
+		fallthrough
+	case breakerHalfOpen:
+		if b.inFlight > 0 {
+			return false
+		}
+	}
+
+	if b.cfg.MaxConcurrentRequests > 0 && b.inFlight >= b.cfg.MaxConcurrentRequests {
+		return false
+	}
+	b.inFlight++
+	return true
+}
+
+// release frees the concurrency slot a prior successful allow() reserved.
+func (b *providerBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.inFlight > 0 {
+		b.inFlight--
+	}
+}
+
+// recordResult feeds a completed call's outcome into the breaker, tripping
+// it open on a sustained error rate and resolving a half-open probe
+// immediately (success closes it, failure re-opens it).
+func (b *providerBreaker) recordResult(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.results = nil
+		if ok {
+			b.state = breakerClosed
+		} else {
+			b.state = breakerOpen
+			b.openedAt = time.Now()
+		}
+		return
+	}
+
+	b.results = append(b.results, ok)
+	if len(b.results) > rollingWindowSize {
+		b.results = b.results[len(b.results)-rollingWindowSize:]
+	}
+	if len(b.results) < b.cfg.RequestVolumeThreshold {
+		return
+	}
+
+	errCount := 0
+	for _, r := range b.results {
+		if !r {
+			errCount++
+		}
+	}
+	if errCount*100/len(b.results) >= b.cfg.ErrorPercentThreshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// healthy reports whether the breaker is fully closed -- a half-open
+// probe in flight doesn't count, since it hasn't proven the provider
+// recovered yet.
+func (b *providerBreaker) healthy() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == breakerClosed
+}
+
+// DataSourceEvent reports a health-state change for one provider behind
+// MultiProviderDataSource, so a caller can display e.g. "API down, using DB
+// (stale 12m)" without inspecting logs.
+type DataSourceEvent struct {
+	Name          string
+	Healthy       bool
+	LastCheckedAt time.Time
+	Err           error
+	// Message optionally carries a human-readable summary for events that
+	// aren't a simple health flip, e.g. APIDataSource reporting how many
+	// items failed a volume fetch and how many recovered on retry.
+	Message string
+}
+
+// DataSourceEventFeed is a minimal channel-based pub/sub for
+// DataSourceEvent, so more than one subscriber (CLI, TUI, HTTP endpoint)
+// can observe provider health changes without polling
+// MultiProviderDataSource directly.
+type DataSourceEventFeed struct {
+	mu   sync.Mutex
+	subs map[int]chan DataSourceEvent
+	next int
+}
+
+func newDataSourceEventFeed() *DataSourceEventFeed {
+	return &DataSourceEventFeed{subs: make(map[int]chan DataSourceEvent)}
+}
+
+// Subscribe returns a channel that receives every future event, and an
+// unsubscribe function to stop and close it. The channel is buffered so a
+// slow subscriber doesn't block LoadPrices/LoadVolumeData; once full,
+// further events are dropped for that subscriber rather than blocking.
+func (f *DataSourceEventFeed) Subscribe() (<-chan DataSourceEvent, func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := f.next
+	f.next++
+	ch := make(chan DataSourceEvent, 16)
+	f.subs[id] = ch
+
+	return ch, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if sub, ok := f.subs[id]; ok {
+			delete(f.subs, id)
+			close(sub)
+		}
+	}
+}
+
+func (f *DataSourceEventFeed) publish(event DataSourceEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ch := range f.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// providerEntry pairs a DataSource with its own breaker and last-known
+// health, tracked in priority order inside MultiProviderDataSource.
+type providerEntry struct {
+	source        DataSource
+	breaker       *providerBreaker
+	lastErr       error
+	lastCheckedAt time.Time
+}
+
+// MultiProviderDataSource chains an ordered list of DataSource
+// implementations behind a per-provider circuit breaker, similar to how a
+// wallet market-data manager wraps a prioritized list of price providers.
+// LoadPrices and LoadVolumeData try each provider in priority order,
+// skipping any whose breaker is open, and publish a DataSourceEvent each
+// time a provider's health changes -- replacing HybridDataSource's ad-hoc
+// fmt.Printf fallback warnings with structured health tracking, and
+// letting more than two sources be chained (e.g. DB -> Wiki API -> cached
+// CSV snapshot).
+type MultiProviderDataSource struct {
+	providers []*providerEntry
+	events    *DataSourceEventFeed
+}
+
+// NewMultiProviderDataSource wraps sources, in priority order, each behind
+// its own breaker built from cfg. Use SetBreakerConfig to tune an
+// individual provider's thresholds afterward.
+func NewMultiProviderDataSource(sources []DataSource, cfg BreakerConfig) *MultiProviderDataSource {
+	m := &MultiProviderDataSource{events: newDataSourceEventFeed()}
+	for _, s := range sources {
+		m.providers = append(m.providers, &providerEntry{
+			source:  s,
+			breaker: newProviderBreaker(cfg),
+		})
+	}
+	return m
+}
+
+// SetBreakerConfig overrides the breaker tuning for the provider named
+// name (matching DataSource.Name()), e.g. to give a known-flaky source a
+// shorter SleepWindow. A no-op if no provider with that name is wrapped.
+func (m *MultiProviderDataSource) SetBreakerConfig(name string, cfg BreakerConfig) {
+	for _, p := range m.providers {
+		if p.source.Name() == name {
+			p.breaker.mu.Lock()
+			p.breaker.cfg = cfg
+			p.breaker.mu.Unlock()
+			return
+		}
+	}
+}
+
+// Events exposes the feed of DataSourceEvent health-state changes, so the
+// CLI/TUI or an HTTP endpoint can display "API down, using DB (stale 12m)"
+// without inspecting logs.
+func (m *MultiProviderDataSource) Events() *DataSourceEventFeed {
+	return m.events
+}
+
+// Status returns a human-readable one-line summary of each provider's
+// health, e.g. "Wiki API: down (stale 12m) | Local Database: healthy", for
+// a CLI/TUI status line or HTTP endpoint.
+func (m *MultiProviderDataSource) Status() string {
+	parts := make([]string, len(m.providers))
+	for i, p := range m.providers {
+		if p.breaker.healthy() {
+			parts[i] = fmt.Sprintf("%s: healthy", p.source.Name())
+			continue
+		}
+		age := "unknown"
+		if !p.lastCheckedAt.IsZero() {
+			age = time.Since(p.lastCheckedAt).Round(time.Minute).String()
+		}
+		parts[i] = fmt.Sprintf("%s: down (stale %s)", p.source.Name(), age)
+	}
+	return strings.Join(parts, " | ")
+}
+
+func (m *MultiProviderDataSource) Name() string {
+	names := make([]string, len(m.providers))
+	for i, p := range m.providers {
+		names[i] = p.source.Name()
+	}
+	return "Multi-Provider (" + strings.Join(names, " -> ") + ")"
+}
+
+func (m *MultiProviderDataSource) IsFresh(ctx context.Context) (bool, error) {
+	for _, p := range m.providers {
+		if !p.breaker.healthy() {
+			continue
+		}
+		if fresh, err := p.source.IsFresh(ctx); err == nil {
+			return fresh, nil
+		}
+	}
+	return false, fmt.Errorf("no healthy provider available to check freshness")
+}
+
+func (m *MultiProviderDataSource) LoadPrices(ctx context.Context) ([]ItemData, error) {
+	var lastErr error
+	attempted := false
+
+	for _, p := range m.providers {
+		if !p.breaker.allow() {
+			continue
+		}
+		attempted = true
+
+		items, err := m.loadPricesFromProvider(ctx, p)
+		p.breaker.release()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return items, nil
+	}
+
+	if !attempted {
+		return nil, fmt.Errorf("all providers' breakers are open")
+	}
+	return nil, fmt.Errorf("all providers failed, last error: %w", lastErr)
+}
+
+func (m *MultiProviderDataSource) LoadVolumeData(ctx context.Context, items []ItemData, maxItems int) error {
+	var lastErr error
+	attempted := false
+
+	for _, p := range m.providers {
+		if !p.breaker.allow() {
+			continue
+		}
+		attempted = true
+
+		err := m.loadVolumeFromProvider(ctx, p, items, maxItems)
+		p.breaker.release()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+
+	if !attempted {
+		return fmt.Errorf("all providers' breakers are open")
+	}
+	return fmt.Errorf("all providers failed, last error: %w", lastErr)
+}
+
+// loadPricesFromProvider calls p's LoadPrices, enforcing p's breaker
+// Timeout if set, and records the outcome against p's breaker (publishing
+// a DataSourceEvent if that flips its health).
+func (m *MultiProviderDataSource) loadPricesFromProvider(ctx context.Context, p *providerEntry) ([]ItemData, error) {
+	callCtx := ctx
+	if p.breaker.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, p.breaker.cfg.Timeout)
+		defer cancel()
+	}
+
+	items, err := p.source.LoadPrices(callCtx)
+	m.recordOutcome(p, err)
+	return items, err
+}
+
+// loadVolumeFromProvider is loadPricesFromProvider's LoadVolumeData
+// counterpart.
+func (m *MultiProviderDataSource) loadVolumeFromProvider(ctx context.Context, p *providerEntry, items []ItemData, maxItems int) error {
+	callCtx := ctx
+	if p.breaker.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, p.breaker.cfg.Timeout)
+		defer cancel()
+	}
+
+	err := p.source.LoadVolumeData(callCtx, items, maxItems)
+	m.recordOutcome(p, err)
+	return err
+}
+
+// recordOutcome feeds a call's result into p's breaker and, if that
+// flipped its healthy/unhealthy state, publishes a DataSourceEvent.
+func (m *MultiProviderDataSource) recordOutcome(p *providerEntry, err error) {
+	wasHealthy := p.breaker.healthy()
+	p.breaker.recordResult(err == nil)
+	p.lastErr = err
+	p.lastCheckedAt = time.Now()
+
+	healthy := p.breaker.healthy()
+	if healthy == wasHealthy {
+		return
+	}
+	m.events.publish(DataSourceEvent{
+		Name:          p.source.Name(),
+		Healthy:       healthy,
+		LastCheckedAt: p.lastCheckedAt,
+		Err:           err,
+	})
+}
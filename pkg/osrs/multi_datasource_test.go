@@ -0,0 +1,140 @@
+package osrs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeDataSource is a minimal DataSource stub for exercising
+// MultiProviderDataSource's fallback/breaker/event behavior without
+// touching the real API or DB sources.
+type fakeDataSource struct {
+	name    string
+	fail    bool
+	loadErr error
+}
+
+func (f *fakeDataSource) Name() string { return f.name }
+
+func (f *fakeDataSource) IsFresh(ctx context.Context) (bool, error) {
+	return !f.fail, nil
+}
+
+func (f *fakeDataSource) LoadPrices(ctx context.Context) ([]ItemData, error) {
+	if f.fail {
+		return nil, f.loadErr
+	}
+	return []ItemData{{ItemID: 1, Name: f.name}}, nil
+}
+
+func (f *fakeDataSource) LoadVolumeData(ctx context.Context, items []ItemData, maxItems int) error {
+	if f.fail {
+		return f.loadErr
+	}
+	return nil
+}
+
+func testBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		Timeout:                time.Second,
+		SleepWindow:            20 * time.Millisecond,
+		ErrorPercentThreshold:  50,
+		RequestVolumeThreshold: 2,
+		MaxConcurrentRequests:  5,
+	}
+}
+
+func TestMultiProviderDataSourceFallsBackOnError(t *testing.T) {
+	primary := &fakeDataSource{name: "primary", fail: true, loadErr: errors.New("boom")}
+	secondary := &fakeDataSource{name: "secondary"}
+
+	m := NewMultiProviderDataSource([]DataSource{primary, secondary}, testBreakerConfig())
+
+	items, err := m.LoadPrices(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Name != "secondary" {
+		t.Errorf("expected fallback to secondary, got %+v", items)
+	}
+}
+
+func TestMultiProviderDataSourceTripsBreakerAndPublishesEvent(t *testing.T) {
+	primary := &fakeDataSource{name: "primary", fail: true, loadErr: errors.New("boom")}
+	secondary := &fakeDataSource{name: "secondary"}
+
+	m := NewMultiProviderDataSource([]DataSource{primary, secondary}, testBreakerConfig())
+
+	events, unsubscribe := m.Events().Subscribe()
+	defer unsubscribe()
+
+	// RequestVolumeThreshold=2, ErrorPercentThreshold=50%: two failures trips it.
+	for i := 0; i < 2; i++ {
+		if _, err := m.LoadPrices(context.Background()); err != nil {
+			t.Fatalf("unexpected error on call %d: %v", i, err)
+		}
+	}
+
+	select {
+	case e := <-events:
+		if e.Name != "primary" || e.Healthy {
+			t.Errorf("expected an unhealthy event for primary, got %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a DataSourceEvent after the breaker tripped")
+	}
+
+	// Further calls should skip primary entirely and go straight to secondary.
+	items, err := m.LoadPrices(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items[0].Name != "secondary" {
+		t.Errorf("expected the open breaker to skip primary, got %+v", items)
+	}
+}
+
+func TestMultiProviderDataSourceHalfOpenProbeRecovers(t *testing.T) {
+	primary := &fakeDataSource{name: "primary", fail: true, loadErr: errors.New("boom")}
+	secondary := &fakeDataSource{name: "secondary"}
+
+	cfg := testBreakerConfig()
+	m := NewMultiProviderDataSource([]DataSource{primary, secondary}, cfg)
+
+	for i := 0; i < 2; i++ {
+		if _, err := m.LoadPrices(context.Background()); err != nil {
+			t.Fatalf("unexpected error on call %d: %v", i, err)
+		}
+	}
+
+	primary.fail = false // simulate the upstream recovering
+	time.Sleep(cfg.SleepWindow + 5*time.Millisecond)
+
+	items, err := m.LoadPrices(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items[0].Name != "primary" {
+		t.Errorf("expected the half-open probe to recover primary, got %+v", items)
+	}
+}
+
+func TestMultiProviderDataSourceAllProvidersFail(t *testing.T) {
+	primary := &fakeDataSource{name: "primary", fail: true, loadErr: errors.New("boom")}
+	m := NewMultiProviderDataSource([]DataSource{primary}, testBreakerConfig())
+
+	if _, err := m.LoadPrices(context.Background()); err == nil {
+		t.Fatal("expected an error when the only provider fails")
+	}
+}
+
+func TestMultiProviderDataSourceStatus(t *testing.T) {
+	healthy := &fakeDataSource{name: "healthy-source"}
+	m := NewMultiProviderDataSource([]DataSource{healthy}, testBreakerConfig())
+
+	if status := m.Status(); status != "healthy-source: healthy" {
+		t.Errorf("expected a healthy status line, got %q", status)
+	}
+}
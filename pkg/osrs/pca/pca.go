@@ -0,0 +1,186 @@
+// Package pca computes a low-dimensional PCA embedding over caller-supplied
+// feature vectors (e.g. an item's margin, volume, trend, buy limit) and
+// finds nearest neighbors in that embedding, so jobs can diversify their
+// output list or suggest substitutes instead of surfacing ten
+// near-identical items. This package knows nothing about osrs.ItemData --
+// building feature vectors from it is pkg/osrs's job (see
+// Analyzer.EnsurePCAEmbedding), keeping pca leaf-level and reusable for any
+// caller-defined feature set.
+package pca
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// DefaultDimensions is how many principal components Compute projects into
+// absent an override.
+const DefaultDimensions = 4
+
+// DefaultMaxAge is how long a cached Embedding is considered fresh before a
+// caller should recompute it (see Embedding.Stale).
+const DefaultMaxAge = 24 * time.Hour
+
+// Embedding is a PCA projection of a set of items into a k-dimensional
+// space.
+type Embedding struct {
+	ItemIDs    []int
+	Points     [][]float64 // Points[i] is the k-dim projection of ItemIDs[i]
+	ComputedAt time.Time
+}
+
+// Stale reports whether e is older than maxAge (or e is nil), so callers
+// know to recompute rather than serve an out-of-date embedding.
+func (e *Embedding) Stale(maxAge time.Duration) bool {
+	if e == nil {
+		return true
+	}
+	return time.Since(e.ComputedAt) > maxAge
+}
+
+// FindSimilar returns up to n item IDs closest to itemID in PCA space,
+// nearest first. Returns nil if itemID isn't in the embedding.
+func (e *Embedding) FindSimilar(itemID int, n int) []int {
+	if e == nil || n <= 0 {
+		return nil
+	}
+
+	idx := e.indexOf(itemID)
+	if idx < 0 {
+		return nil
+	}
+
+	type neighbor struct {
+		itemID int
+		dist   float64
+	}
+	neighbors := make([]neighbor, 0, len(e.ItemIDs)-1)
+	for i, id := range e.ItemIDs {
+		if i == idx {
+			continue
+		}
+		neighbors = append(neighbors, neighbor{id, euclidean(e.Points[idx], e.Points[i])})
+	}
+
+	sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].dist < neighbors[j].dist })
+
+	if n > len(neighbors) {
+		n = len(neighbors)
+	}
+	result := make([]int, n)
+	for i := 0; i < n; i++ {
+		result[i] = neighbors[i].itemID
+	}
+	return result
+}
+
+func (e *Embedding) indexOf(itemID int) int {
+	for i, id := range e.ItemIDs {
+		if id == itemID {
+			return i
+		}
+	}
+	return -1
+}
+
+func euclidean(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// standardize rescales each column of m to zero mean and unit variance in
+// place, so a single large-magnitude column (like volume) doesn't dominate
+// the SVD. A zero-variance column is left at zero rather than divided by
+// zero.
+func standardize(m *mat.Dense) {
+	rows, cols := m.Dims()
+	for j := 0; j < cols; j++ {
+		var mean float64
+		for i := 0; i < rows; i++ {
+			mean += m.At(i, j)
+		}
+		mean /= float64(rows)
+
+		var variance float64
+		for i := 0; i < rows; i++ {
+			d := m.At(i, j) - mean
+			variance += d * d
+		}
+		variance /= float64(rows)
+		stddev := math.Sqrt(variance)
+
+		for i := 0; i < rows; i++ {
+			if stddev > 0 {
+				m.Set(i, j, (m.At(i, j)-mean)/stddev)
+			} else {
+				m.Set(i, j, 0)
+			}
+		}
+	}
+}
+
+// Compute builds a k-dimensional PCA embedding over vectors via SVD, one
+// row per itemIDs[i]. Callers build vectors themselves (see
+// Analyzer.EnsurePCAEmbedding for the osrs.ItemData -> feature-row
+// conversion) since this package has no notion of what a "feature" is.
+// Returns nil if there isn't enough data to embed (fewer than 2 items) or
+// itemIDs/vectors don't line up.
+func Compute(itemIDs []int, vectors [][]float64, k int) *Embedding {
+	if k <= 0 {
+		k = DefaultDimensions
+	}
+	if len(itemIDs) < 2 || len(vectors) != len(itemIDs) {
+		return nil
+	}
+
+	rows := len(vectors)
+	cols := len(vectors[0])
+
+	features := mat.NewDense(rows, cols, nil)
+	for i, v := range vectors {
+		for j, x := range v {
+			features.Set(i, j, x)
+		}
+	}
+	standardize(features)
+
+	var svd mat.SVD
+	if !svd.Factorize(features, mat.SVDThin) {
+		return nil
+	}
+	var v mat.Dense
+	svd.VTo(&v)
+
+	if k > cols {
+		k = cols
+	}
+
+	points := make([][]float64, rows)
+	for i := 0; i < rows; i++ {
+		point := make([]float64, k)
+		for d := 0; d < k; d++ {
+			var sum float64
+			for j := 0; j < cols; j++ {
+				sum += features.At(i, j) * v.At(j, d)
+			}
+			point[d] = sum
+		}
+		points[i] = point
+	}
+
+	ids := make([]int, rows)
+	copy(ids, itemIDs)
+
+	return &Embedding{
+		ItemIDs:    ids,
+		Points:     points,
+		ComputedAt: time.Now(),
+	}
+}
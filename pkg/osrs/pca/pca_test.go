@@ -0,0 +1,87 @@
+package pca
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEmbeddingStale(t *testing.T) {
+	var nilEmbedding *Embedding
+	if !nilEmbedding.Stale(DefaultMaxAge) {
+		t.Error("expected a nil embedding to be stale")
+	}
+
+	fresh := &Embedding{ComputedAt: time.Now()}
+	if fresh.Stale(DefaultMaxAge) {
+		t.Error("expected a just-computed embedding to not be stale")
+	}
+
+	old := &Embedding{ComputedAt: time.Now().Add(-48 * time.Hour)}
+	if !old.Stale(DefaultMaxAge) {
+		t.Error("expected a 48h-old embedding to be stale with the default 24h max age")
+	}
+}
+
+func TestFindSimilar(t *testing.T) {
+	embedding := &Embedding{
+		ItemIDs: []int{1, 2, 3, 4},
+		Points: [][]float64{
+			{0, 0},
+			{1, 0},
+			{10, 10},
+			{1.1, 0},
+		},
+		ComputedAt: time.Now(),
+	}
+
+	similar := embedding.FindSimilar(1, 2)
+	if len(similar) != 2 {
+		t.Fatalf("expected 2 similar items, got %d", len(similar))
+	}
+	if similar[0] != 2 && similar[0] != 4 {
+		t.Errorf("expected the nearest neighbor to be item 2 or 4, got %d", similar[0])
+	}
+	for _, id := range similar {
+		if id == 3 {
+			t.Errorf("expected item 3 (far away) to not be in the nearest neighbors, got %v", similar)
+		}
+	}
+}
+
+func TestFindSimilarUnknownItem(t *testing.T) {
+	embedding := &Embedding{ItemIDs: []int{1, 2}, Points: [][]float64{{0, 0}, {1, 1}}}
+	if similar := embedding.FindSimilar(99, 1); similar != nil {
+		t.Errorf("expected nil for an item not in the embedding, got %v", similar)
+	}
+}
+
+func TestComputeRequiresAtLeastTwoItems(t *testing.T) {
+	if e := Compute(nil, nil, DefaultDimensions); e != nil {
+		t.Errorf("expected nil embedding for no items, got %v", e)
+	}
+	if e := Compute([]int{1}, [][]float64{{5, 100}}, DefaultDimensions); e != nil {
+		t.Errorf("expected nil embedding for a single item, got %v", e)
+	}
+}
+
+func TestComputeProducesOnePointPerItem(t *testing.T) {
+	itemIDs := []int{1, 2, 3}
+	vectors := [][]float64{
+		{5, 100, 10, 10},
+		{50, 5, 1000, 1000},
+		{2, 2000, 1, 1},
+	}
+
+	embedding := Compute(itemIDs, vectors, 2)
+	if embedding == nil {
+		t.Fatal("expected a non-nil embedding for 3 items")
+	}
+	if len(embedding.ItemIDs) != 3 || len(embedding.Points) != 3 {
+		t.Fatalf("expected 3 item IDs and 3 points, got %d ids and %d points", len(embedding.ItemIDs), len(embedding.Points))
+	}
+	for _, point := range embedding.Points {
+		if len(point) != 2 {
+			t.Errorf("expected 2-dimensional points, got %d", len(point))
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package osrs
+
+// DefaultPivotLength and DefaultBreakRatio are used when a job doesn't
+// override pivot-breakout detection via FilterOptions.
+const (
+	DefaultPivotLength = 10
+	DefaultBreakRatio  = 0.001 // 0.1%
+)
+
+// findPivotLow scans prices for the most recent confirmed pivot low: a point
+// surrounded by pivotLength/2 higher (or equal) points on each side. Returns
+// ok=false if prices is too short or no such point can be confirmed.
+func findPivotLow(prices []float64, pivotLength int) (value float64, idx int, ok bool) {
+	side := pivotLength / 2
+	if side < 1 || len(prices) < 2*side+1 {
+		return 0, 0, false
+	}
+
+	for i := side; i < len(prices)-side; i++ {
+		isPivot := true
+		for j := i - side; j <= i+side; j++ {
+			if j != i && prices[j] < prices[i] {
+				isPivot = false
+				break
+			}
+		}
+		if isPivot {
+			value, idx, ok = prices[i], i, true
+		}
+	}
+	return value, idx, ok
+}
+
+// ClassifyPivotBreak compares currentPrice against the most recent confirmed
+// pivot low in prices. breakBuy fires when currentPrice has broken below the
+// pivot by more than breakRatio -- a fresh breakdown through support, which
+// for flipping tends to be an overreaction worth buying into. bounceSell
+// fires when currentPrice is approaching the pivot from below but still
+// within breakRatio of it -- support is holding, so it's time to take
+// profit before it breaks. A prices series too short to confirm a pivot
+// yields neither signal.
+func ClassifyPivotBreak(prices []float64, currentPrice float64, pivotLength int, breakRatio float64) (breakBuy, bounceSell bool) {
+	pivot, _, ok := findPivotLow(prices, pivotLength)
+	if !ok || pivot <= 0 {
+		return false, false
+	}
+
+	threshold := pivot * breakRatio
+	switch {
+	case currentPrice < pivot-threshold:
+		breakBuy = true
+	case currentPrice < pivot:
+		bounceSell = true
+	}
+	return breakBuy, bounceSell
+}
@@ -0,0 +1,78 @@
+package osrs
+
+import "testing"
+
+func TestFindPivotLow(t *testing.T) {
+	tests := []struct {
+		name        string
+		prices      []float64
+		pivotLength int
+		wantOK      bool
+		wantValue   float64
+	}{
+		{
+			name:        "too short for pivot length",
+			prices:      []float64{100, 90, 95},
+			pivotLength: 10,
+			wantOK:      false,
+		},
+		{
+			name:        "zero pivot length",
+			prices:      []float64{100, 90, 95, 100, 105},
+			pivotLength: 0,
+			wantOK:      false,
+		},
+		{
+			name:        "clear pivot low at center",
+			prices:      []float64{110, 105, 100, 105, 110},
+			pivotLength: 2,
+			wantOK:      true,
+			wantValue:   100,
+		},
+		{
+			name:        "monotonic series has no interior pivot",
+			prices:      []float64{100, 101, 102, 103, 104},
+			pivotLength: 2,
+			wantOK:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, _, ok := findPivotLow(tt.prices, tt.pivotLength)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && value != tt.wantValue {
+				t.Errorf("value = %v, want %v", value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestClassifyPivotBreak(t *testing.T) {
+	// Pivot low of 100 confirmed at the center of this series.
+	prices := []float64{110, 105, 100, 105, 110}
+
+	breakBuy, bounceSell := ClassifyPivotBreak(prices, 98, 2, 0.01)
+	if !breakBuy || bounceSell {
+		t.Errorf("price well below pivot: got breakBuy=%v bounceSell=%v, want breakBuy=true", breakBuy, bounceSell)
+	}
+
+	breakBuy, bounceSell = ClassifyPivotBreak(prices, 99.5, 2, 0.01)
+	if breakBuy || !bounceSell {
+		t.Errorf("price just below pivot within ratio: got breakBuy=%v bounceSell=%v, want bounceSell=true", breakBuy, bounceSell)
+	}
+
+	breakBuy, bounceSell = ClassifyPivotBreak(prices, 105, 2, 0.01)
+	if breakBuy || bounceSell {
+		t.Errorf("price above pivot: got breakBuy=%v bounceSell=%v, want neither", breakBuy, bounceSell)
+	}
+}
+
+func TestClassifyPivotBreakNoConfirmedPivot(t *testing.T) {
+	breakBuy, bounceSell := ClassifyPivotBreak([]float64{100, 101, 102}, 90, 2, 0.01)
+	if breakBuy || bounceSell {
+		t.Errorf("expected no signal when no pivot can be confirmed, got breakBuy=%v bounceSell=%v", breakBuy, bounceSell)
+	}
+}
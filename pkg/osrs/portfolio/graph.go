@@ -0,0 +1,41 @@
+package portfolio
+
+import (
+	"fmt"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// GenerateGraph writes a cumulative realized-PnL line chart to path,
+// mirroring osrs/backtest.RenderGraphs' cumulative_pnl.png for eyeballing a
+// live portfolio's equity curve instead of a backtest's.
+func (p *Portfolio) GenerateGraph(path string) error {
+	points := p.equityCurve()
+	if len(points) == 0 {
+		return fmt.Errorf("no closed trades to graph")
+	}
+
+	pts := make(plotter.XYs, len(points))
+	for i, e := range points {
+		pts[i].X = float64(e.Timestamp.Unix())
+		pts[i].Y = float64(e.EquityGP)
+	}
+
+	plt := plot.New()
+	plt.Title.Text = "Portfolio: cumulative PnL (GP)"
+	plt.X.Label.Text = "time (unix seconds)"
+	plt.Y.Label.Text = "cumulative GP"
+
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		return fmt.Errorf("building cumulative pnl line: %w", err)
+	}
+	plt.Add(line)
+
+	if err := plt.Save(8*vg.Inch, 4*vg.Inch, path); err != nil {
+		return fmt.Errorf("saving cumulative pnl graph: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,389 @@
+// Package portfolio records actual flip lifecycle events (offer placed,
+// filled buy, filled sell, cancelled) and derives running exposure and P&L
+// from them, mirroring the Position/ProfitStats model bbgo strategies use
+// to track a live account. This is deliberately separate from package
+// position: position tracks a single open-flip's entry price for exit-signal
+// evaluation, while Portfolio is an event log an external trading client (or
+// a human via the CLI) appends to as offers actually move through the GE,
+// letting GP-per-hour, tax paid, and hit rate be derived after the fact
+// rather than recomputed from a snapshot.
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EventType identifies one step in a flip's lifecycle.
+type EventType string
+
+const (
+	// EventOfferPlaced records a buy or sell offer submitted to the GE,
+	// before it's known to have filled.
+	EventOfferPlaced EventType = "offer_placed"
+	// EventFilledBuy records units actually bought at Price.
+	EventFilledBuy EventType = "filled_buy"
+	// EventFilledSell records units actually sold at Price, closing out
+	// that quantity of the item's open exposure.
+	EventFilledSell EventType = "filled_sell"
+	// EventCancelled records an offer pulled before it filled.
+	EventCancelled EventType = "cancelled"
+)
+
+// Event is one lifecycle step for an item's flip, as actually observed
+// (not predicted) -- the unit of Portfolio's durable event log.
+type Event struct {
+	ItemID    int       `json:"item_id"`
+	Name      string    `json:"name"`
+	Type      EventType `json:"type"`
+	Quantity  int       `json:"quantity"`
+	Price     int       `json:"price"` // per-unit GP; zero for EventOfferPlaced/EventCancelled
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ProfitStats summarizes a Portfolio's realized performance across every
+// EventFilledSell recorded so far, mirroring bbgo's ProfitStats.
+type ProfitStats struct {
+	ClosedTrades     int     `json:"closed_trades"`
+	WinningTrades    int     `json:"winning_trades"`
+	RealizedProfitGP int     `json:"realized_profit_gp"` // net of TaxPaidGP
+	TaxPaidGP        int     `json:"tax_paid_gp"`
+	WinRate          float64 `json:"win_rate"`
+}
+
+// Snapshot is the durable state a Store persists: the full event log plus
+// the daily history ResetDaily has already rolled off.
+type Snapshot struct {
+	Events      []Event      `json:"events"`
+	DailyStats  []DailyStats `json:"daily_stats"`
+	LastResetAt time.Time    `json:"last_reset_at"`
+}
+
+// DailyStats is one day's ProfitStats, snapshotted by ResetDaily.
+type DailyStats struct {
+	Date  string      `json:"date"` // YYYY-MM-DD, UTC
+	Stats ProfitStats `json:"stats"`
+}
+
+// Store persists a Portfolio's Snapshot. Implementations: JSONStore (the
+// default, a local file) and RedisStore (shared state across collector
+// replicas).
+type Store interface {
+	Load() (Snapshot, error)
+	Save(Snapshot) error
+}
+
+// openLot is one not-yet-fully-sold buy fill, tracked FIFO per item so
+// EventFilledSell can compute realized profit against the price it was
+// actually bought at.
+type openLot struct {
+	quantity int
+	price    int
+}
+
+// Portfolio tracks flip lifecycle events in memory, persisting through
+// store after every mutation so a restarted collector picks up where it
+// left off.
+type Portfolio struct {
+	mu    sync.RWMutex
+	store Store
+
+	events      []Event
+	openLots    map[int][]openLot // FIFO queue of unsold buy fills, by item ID
+	dailyStats  []DailyStats
+	lastResetAt time.Time
+}
+
+// NewPortfolio returns a Portfolio backed by store. Call Load to restore any
+// previously-persisted state before recording new events.
+func NewPortfolio(store Store) *Portfolio {
+	return &Portfolio{
+		store:    store,
+		openLots: make(map[int][]openLot),
+	}
+}
+
+// Load restores a previously-persisted Snapshot from the portfolio's Store,
+// replaying every event to rebuild openLots. A Store with no prior state is
+// not an error -- it's just a cold start.
+func (p *Portfolio) Load() error {
+	snap, err := p.store.Load()
+	if err != nil {
+		return fmt.Errorf("loading portfolio: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = nil
+	p.openLots = make(map[int][]openLot)
+	p.dailyStats = snap.DailyStats
+	p.lastResetAt = snap.LastResetAt
+	for _, e := range snap.Events {
+		p.applyLocked(e)
+	}
+	return nil
+}
+
+// Record appends e to the event log, updates in-memory exposure/P&L state,
+// and persists the result.
+func (p *Portfolio) Record(e Event) error {
+	p.mu.Lock()
+	p.applyLocked(e)
+	snap := p.snapshotLocked()
+	p.mu.Unlock()
+
+	if err := p.store.Save(snap); err != nil {
+		return fmt.Errorf("saving portfolio after recording event: %w", err)
+	}
+	return nil
+}
+
+// applyLocked updates openLots for e. Only EventFilledBuy and
+// EventFilledSell affect exposure; EventOfferPlaced/EventCancelled are kept
+// in the log for audit purposes only. Callers must hold p.mu.
+func (p *Portfolio) applyLocked(e Event) {
+	p.events = append(p.events, e)
+
+	switch e.Type {
+	case EventFilledBuy:
+		p.openLots[e.ItemID] = append(p.openLots[e.ItemID], openLot{quantity: e.Quantity, price: e.Price})
+	case EventFilledSell:
+		p.closeLotsLocked(e.ItemID, e.Quantity)
+	}
+}
+
+// closeLotsLocked removes up to quantity units from itemID's oldest open
+// lots first (FIFO), for exposure accounting. Selling more than is
+// currently open just drains the queue -- Portfolio trusts the caller's
+// event log over its own bookkeeping.
+func (p *Portfolio) closeLotsLocked(itemID, quantity int) {
+	lots := p.openLots[itemID]
+	for quantity > 0 && len(lots) > 0 {
+		lot := &lots[0]
+		if lot.quantity <= quantity {
+			quantity -= lot.quantity
+			lots = lots[1:]
+			continue
+		}
+		lot.quantity -= quantity
+		quantity = 0
+	}
+	if len(lots) == 0 {
+		delete(p.openLots, itemID)
+	} else {
+		p.openLots[itemID] = lots
+	}
+}
+
+// OpenGP returns itemID's current open exposure: quantity still held times
+// the price it was bought at, summed across every unsold lot.
+func (p *Portfolio) OpenGP(itemID int) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	total := 0
+	for _, lot := range p.openLots[itemID] {
+		total += lot.quantity * lot.price
+	}
+	return total
+}
+
+// OpenQuantity returns the number of units of itemID still held across
+// every unsold lot.
+func (p *Portfolio) OpenQuantity(itemID int) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	total := 0
+	for _, lot := range p.openLots[itemID] {
+		total += lot.quantity
+	}
+	return total
+}
+
+// OpenItemIDs returns the item IDs with at least one open lot, for callers
+// (e.g. FormatItemsForAnalysisV2's portfolio block) that need to summarize
+// exposure across every held item rather than check one at a time.
+func (p *Portfolio) OpenItemIDs() []int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	ids := make([]int, 0, len(p.openLots))
+	for itemID := range p.openLots {
+		ids = append(ids, itemID)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Stats computes ProfitStats across every EventFilledSell recorded since
+// the last ResetDaily, net of GE tax (see geTax).
+func (p *Portfolio) Stats() ProfitStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.statsSinceLocked(p.lastResetAt)
+}
+
+// statsSinceLocked computes ProfitStats from sell fills after since,
+// matching each sell against the average buy price of the fills that
+// preceded it for that item. Callers must hold at least a read lock.
+func (p *Portfolio) statsSinceLocked(since time.Time) ProfitStats {
+	avgBuyPrice := make(map[int]float64)
+	buyCount := make(map[int]int)
+
+	var stats ProfitStats
+	for _, e := range p.events {
+		switch e.Type {
+		case EventFilledBuy:
+			n := buyCount[e.ItemID]
+			avgBuyPrice[e.ItemID] = (avgBuyPrice[e.ItemID]*float64(n) + float64(e.Price)*float64(e.Quantity)) / float64(n+e.Quantity)
+			buyCount[e.ItemID] = n + e.Quantity
+		case EventFilledSell:
+			if e.Timestamp.Before(since) {
+				continue
+			}
+			tax := geTax(e.Price, e.Quantity)
+			profit := int((float64(e.Price)-avgBuyPrice[e.ItemID])*float64(e.Quantity)) - tax
+			stats.ClosedTrades++
+			stats.RealizedProfitGP += profit
+			stats.TaxPaidGP += tax
+			if profit > 0 {
+				stats.WinningTrades++
+			}
+		}
+	}
+	if stats.ClosedTrades > 0 {
+		stats.WinRate = float64(stats.WinningTrades) / float64(stats.ClosedTrades)
+	}
+	return stats
+}
+
+// Throughput counts EventFilledSell events in the trailing window ending
+// now, for a rolling GP/hour or trades/day readout.
+func (p *Portfolio) Throughput(window time.Duration) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	cutoff := time.Now().Add(-window)
+	count := 0
+	for _, e := range p.events {
+		if e.Type == EventFilledSell && e.Timestamp.After(cutoff) {
+			count++
+		}
+	}
+	return count
+}
+
+// ResetDaily snapshots the current day's ProfitStats into DailyStats and
+// rolls the window ResetDaily/Stats measures forward, once per UTC day at
+// hourUTC -- mirroring the "24 hour accumulated fees" reset pattern other
+// exchange trackers use so a long-running collector's stats don't silently
+// accumulate forever. Safe to call on every tick; it's a no-op between
+// reset hours.
+func (p *Portfolio) ResetDaily(hourUTC int) error {
+	now := time.Now().UTC()
+	if now.Hour() != hourUTC {
+		return nil
+	}
+
+	p.mu.Lock()
+	if p.lastResetAt.UTC().Format("2006-01-02") == now.Format("2006-01-02") {
+		p.mu.Unlock()
+		return nil
+	}
+
+	stats := p.statsSinceLocked(p.lastResetAt)
+	p.dailyStats = append(p.dailyStats, DailyStats{Date: now.Format("2006-01-02"), Stats: stats})
+	p.lastResetAt = now
+	snap := p.snapshotLocked()
+	p.mu.Unlock()
+
+	if err := p.store.Save(snap); err != nil {
+		return fmt.Errorf("saving portfolio after daily reset: %w", err)
+	}
+	return nil
+}
+
+// snapshotLocked builds the Snapshot store.Save persists. Callers must hold
+// at least a read lock.
+func (p *Portfolio) snapshotLocked() Snapshot {
+	events := make([]Event, len(p.events))
+	copy(events, p.events)
+	daily := make([]DailyStats, len(p.dailyStats))
+	copy(daily, p.dailyStats)
+	return Snapshot{Events: events, DailyStats: daily, LastResetAt: p.lastResetAt}
+}
+
+// EquityPoint is one sample of cumulative realized PnL, ordered by the time
+// each contributing sell fill landed. See GenerateGraph.
+type EquityPoint struct {
+	Timestamp time.Time
+	EquityGP  int
+}
+
+// equityCurve returns cumulative realized profit ordered by sell time, for
+// GenerateGraph.
+func (p *Portfolio) equityCurve() []EquityPoint {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	sells := make([]Event, 0, len(p.events))
+	for _, e := range p.events {
+		if e.Type == EventFilledSell {
+			sells = append(sells, e)
+		}
+	}
+	sort.Slice(sells, func(i, j int) bool { return sells[i].Timestamp.Before(sells[j].Timestamp) })
+
+	avgBuyPrice := make(map[int]float64)
+	buyCount := make(map[int]int)
+	for _, e := range p.events {
+		if e.Type == EventFilledBuy {
+			n := buyCount[e.ItemID]
+			avgBuyPrice[e.ItemID] = (avgBuyPrice[e.ItemID]*float64(n) + float64(e.Price)*float64(e.Quantity)) / float64(n+e.Quantity)
+			buyCount[e.ItemID] = n + e.Quantity
+		}
+	}
+
+	points := make([]EquityPoint, len(sells))
+	cumulative := 0
+	for i, e := range sells {
+		tax := geTax(e.Price, e.Quantity)
+		profit := int((float64(e.Price)-avgBuyPrice[e.ItemID])*float64(e.Quantity)) - tax
+		cumulative += profit
+		points[i].Timestamp = e.Timestamp
+		points[i].EquityGP = cumulative
+	}
+	return points
+}
+
+// geTaxRate and geTaxCapGP match the Grand Exchange's 2024 sale tax update
+// (see osrs/backtest.geTax) -- duplicated here rather than imported, since
+// package osrs/backtest imports osrs and osrs.Analyzer wires in this
+// package, which would otherwise cycle.
+const (
+	geTaxRate  = 0.01
+	geTaxCapGP = 5_000_000
+)
+
+// geTax returns the GP withheld when selling quantity units at unitPrice.
+func geTax(unitPrice, quantity int) int {
+	if unitPrice <= 0 || quantity <= 0 {
+		return 0
+	}
+	tax := int(float64(unitPrice*quantity) * geTaxRate)
+	if tax > geTaxCapGP {
+		tax = geTaxCapGP
+	}
+	return tax
+}
+
+// GeTax is the exported form of geTax, for callers outside this package
+// (e.g. package osrs filling FlipFeatures.TaxGP for the factors package's
+// margin-tax-ratio factor) that want the same GE tax
+// calculation without duplicating the rate/cap constants a third time.
+func GeTax(unitPrice, quantity int) int {
+	return geTax(unitPrice, quantity)
+}
@@ -0,0 +1,116 @@
+package portfolio
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPortfolioRecordBuyThenSellComputesStats(t *testing.T) {
+	p := NewPortfolio(NewJSONStore(""))
+
+	base := time.Now()
+	if err := p.Record(Event{ItemID: 1, Name: "Test Item", Type: EventFilledBuy, Quantity: 10, Price: 100, Timestamp: base}); err != nil {
+		t.Fatalf("Record (buy) failed: %v", err)
+	}
+	if got := p.OpenGP(1); got != 1000 {
+		t.Errorf("OpenGP after buy = %d, want 1000", got)
+	}
+
+	if err := p.Record(Event{ItemID: 1, Name: "Test Item", Type: EventFilledSell, Quantity: 10, Price: 120, Timestamp: base.Add(time.Hour)}); err != nil {
+		t.Fatalf("Record (sell) failed: %v", err)
+	}
+	if got := p.OpenGP(1); got != 0 {
+		t.Errorf("OpenGP after full sell = %d, want 0", got)
+	}
+
+	wantTax := geTax(120, 10)
+	wantProfit := (120-100)*10 - wantTax
+	stats := p.Stats()
+	if stats.ClosedTrades != 1 || stats.RealizedProfitGP != wantProfit || stats.TaxPaidGP != wantTax || stats.WinRate != 1.0 {
+		t.Errorf("unexpected stats: %+v, want profit %d tax %d", stats, wantProfit, wantTax)
+	}
+}
+
+func TestPortfolioPartialSellLeavesRemainingExposure(t *testing.T) {
+	p := NewPortfolio(NewJSONStore(""))
+	base := time.Now()
+
+	if err := p.Record(Event{ItemID: 1, Type: EventFilledBuy, Quantity: 10, Price: 100, Timestamp: base}); err != nil {
+		t.Fatalf("Record (buy) failed: %v", err)
+	}
+	if err := p.Record(Event{ItemID: 1, Type: EventFilledSell, Quantity: 4, Price: 120, Timestamp: base.Add(time.Hour)}); err != nil {
+		t.Fatalf("Record (partial sell) failed: %v", err)
+	}
+
+	if got := p.OpenGP(1); got != 600 { // 6 remaining units * 100 GP buy price
+		t.Errorf("OpenGP after partial sell = %d, want 600", got)
+	}
+}
+
+func TestPortfolioOfferPlacedAndCancelledDoNotAffectExposure(t *testing.T) {
+	p := NewPortfolio(NewJSONStore(""))
+	base := time.Now()
+
+	if err := p.Record(Event{ItemID: 1, Type: EventOfferPlaced, Quantity: 10, Timestamp: base}); err != nil {
+		t.Fatalf("Record (offer placed) failed: %v", err)
+	}
+	if err := p.Record(Event{ItemID: 1, Type: EventCancelled, Quantity: 10, Timestamp: base.Add(time.Minute)}); err != nil {
+		t.Fatalf("Record (cancelled) failed: %v", err)
+	}
+
+	if got := p.OpenGP(1); got != 0 {
+		t.Errorf("OpenGP after an offer that never filled = %d, want 0", got)
+	}
+	if stats := p.Stats(); stats.ClosedTrades != 0 {
+		t.Errorf("expected no closed trades from an unfilled offer, got %+v", stats)
+	}
+}
+
+func TestPortfolioJSONStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "portfolio.json")
+	base := time.Now()
+
+	p := NewPortfolio(NewJSONStore(path))
+	if err := p.Record(Event{ItemID: 1, Name: "Test Item", Type: EventFilledBuy, Quantity: 5, Price: 50, Timestamp: base}); err != nil {
+		t.Fatalf("Record (buy) failed: %v", err)
+	}
+	if err := p.Record(Event{ItemID: 1, Name: "Test Item", Type: EventFilledSell, Quantity: 5, Price: 60, Timestamp: base.Add(time.Hour)}); err != nil {
+		t.Fatalf("Record (sell) failed: %v", err)
+	}
+
+	loaded := NewPortfolio(NewJSONStore(path))
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stats := loaded.Stats(); stats.ClosedTrades != 1 {
+		t.Errorf("expected the closed trade to round-trip, got stats %+v", stats)
+	}
+}
+
+func TestPortfolioResetDailyIsIdempotentWithinTheSameDay(t *testing.T) {
+	p := NewPortfolio(NewJSONStore(""))
+	now := time.Now().UTC()
+
+	if err := p.Record(Event{ItemID: 1, Type: EventFilledBuy, Quantity: 1, Price: 100, Timestamp: now}); err != nil {
+		t.Fatalf("Record (buy) failed: %v", err)
+	}
+	if err := p.Record(Event{ItemID: 1, Type: EventFilledSell, Quantity: 1, Price: 110, Timestamp: now}); err != nil {
+		t.Fatalf("Record (sell) failed: %v", err)
+	}
+
+	if err := p.ResetDaily(now.Hour()); err != nil {
+		t.Fatalf("ResetDaily failed: %v", err)
+	}
+	firstRolls := len(p.dailyStats)
+	if firstRolls != 1 {
+		t.Fatalf("expected 1 daily snapshot after the first reset, got %d", firstRolls)
+	}
+
+	if err := p.ResetDaily(now.Hour()); err != nil {
+		t.Fatalf("second ResetDaily failed: %v", err)
+	}
+	if len(p.dailyStats) != firstRolls {
+		t.Errorf("expected ResetDaily to be a no-op within the same UTC day, got %d snapshots", len(p.dailyStats))
+	}
+}
@@ -0,0 +1,55 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore persists a Portfolio's Snapshot as a single JSON value under
+// Key in Redis, so multiple collector replicas (or a collector that gets
+// rescheduled onto a new host) share one portfolio instead of each keeping
+// its own JSONStore file. Use this over JSONStore whenever more than one
+// process can be recording events for the same portfolio.
+type RedisStore struct {
+	Client *redis.Client
+	Key    string
+}
+
+// NewRedisStore returns a RedisStore backed by client, persisting under key.
+func NewRedisStore(client *redis.Client, key string) *RedisStore {
+	return &RedisStore{Client: client, Key: key}
+}
+
+// Load restores a previously-saved Snapshot from Redis. A missing key is
+// not an error -- it just means this is a cold start.
+func (s *RedisStore) Load() (Snapshot, error) {
+	data, err := s.Client.Get(context.Background(), s.Key).Bytes()
+	if err == redis.Nil {
+		return Snapshot{}, nil
+	}
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("reading portfolio store key %s: %w", s.Key, err)
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Snapshot{}, fmt.Errorf("decoding portfolio store key %s: %w", s.Key, err)
+	}
+	return snap, nil
+}
+
+// Save writes snap to Redis as JSON, with no expiration -- a portfolio is
+// meant to persist indefinitely, the same as JSONStore's file.
+func (s *RedisStore) Save(snap Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("encoding portfolio store: %w", err)
+	}
+	if err := s.Client.Set(context.Background(), s.Key, data, 0).Err(); err != nil {
+		return fmt.Errorf("writing portfolio store key %s: %w", s.Key, err)
+	}
+	return nil
+}
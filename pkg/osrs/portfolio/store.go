@@ -0,0 +1,50 @@
+package portfolio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// JSONStore persists a Portfolio's Snapshot to a local JSON file, the
+// default Store -- no external services required, matching package
+// position's Store.
+type JSONStore struct {
+	path string
+}
+
+// NewJSONStore returns a JSONStore backed by path.
+func NewJSONStore(path string) *JSONStore {
+	return &JSONStore{path: path}
+}
+
+// Load restores a previously-saved Snapshot from disk. A missing file is
+// not an error -- it just means this is a cold start.
+func (s *JSONStore) Load() (Snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Snapshot{}, nil
+	}
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("reading portfolio store %s: %w", s.path, err)
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Snapshot{}, fmt.Errorf("decoding portfolio store %s: %w", s.path, err)
+	}
+	return snap, nil
+}
+
+// Save writes snap to disk as indented JSON.
+func (s *JSONStore) Save(snap Snapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding portfolio store: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("writing portfolio store %s: %w", s.path, err)
+	}
+	return nil
+}
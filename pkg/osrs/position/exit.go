@@ -0,0 +1,38 @@
+package position
+
+import "time"
+
+// ExitConfig tunes Analyzer.EvaluateExitSignals' tiered trailing-stop
+// ladder, stop loss, take-profit, and staleness thresholds. It mirrors the
+// bbgo drift trailing-stop config: TrailingActivationRatio and
+// TrailingCallbackRate are paired, ordered-ascending tiers -- once a
+// position's peak price since entry rises above
+// buyPrice*(1+activation[i]), a trailing stop activates at that tier and
+// fires once price falls back by callback[i] from the peak.
+type ExitConfig struct {
+	TrailingActivationRatio []float64
+
+	TrailingCallbackRate []float64
+
+	// StopLossPct is a hard floor: fires once the current price falls this
+	// fraction below the buy price, regardless of the trailing ladder.
+	StopLossPct float64
+
+	// TakeProfitFactor fires once per-unit profit reaches this multiple of
+	// the item's observed margin ATR (see Analyzer.EvaluateExitSignals).
+	TakeProfitFactor float64
+
+	// StaleAfter fires for positions still open this long after BuyTime,
+	// regardless of price. Zero disables the staleness check.
+	StaleAfter time.Duration
+}
+
+// DefaultExitConfig is used by Analyzer.EvaluateExitSignals when a caller
+// doesn't supply its own tuning.
+var DefaultExitConfig = ExitConfig{
+	TrailingActivationRatio: []float64{0.01, 0.05, 0.1},
+	TrailingCallbackRate:    []float64{0.01, 0.02, 0.03},
+	StopLossPct:             0.05,
+	TakeProfitFactor:        3.0,
+	StaleAfter:              72 * time.Hour,
+}
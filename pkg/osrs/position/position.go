@@ -0,0 +1,197 @@
+// Package position tracks persistent, user-held flip positions (item,
+// quantity, buy price, entry time) across analyzer runs, and the realized
+// P&L stats accumulated as they're closed out. Evaluating exit signals
+// against current market prices is Analyzer.EvaluateExitSignals' job, since
+// that needs the analyzer's loaded price/volume data and store history;
+// this package only owns the positions themselves and their persistence.
+package position
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Position is one open flip: a quantity of an item bought at BuyPrice,
+// still awaiting a sell.
+type Position struct {
+	ItemID   int       `json:"item_id"`
+	Name     string    `json:"name"`
+	Quantity int       `json:"quantity"`
+	BuyPrice int       `json:"buy_price"`
+	BuyTime  time.Time `json:"buy_time"`
+}
+
+// Reason identifies why EvaluateExitSignals recommended closing a position.
+type Reason string
+
+const (
+	ReasonTrailing   Reason = "trailing"
+	ReasonStopLoss   Reason = "stop_loss"
+	ReasonTakeProfit Reason = "take_profit"
+	ReasonStale      Reason = "stale"
+)
+
+// ExitSignal is a recommended sell action for one open Position, produced
+// by Analyzer.EvaluateExitSignals so the CLI can render it alongside the
+// position it refers to.
+type ExitSignal struct {
+	ItemID             int    `json:"item_id"`
+	Name               string `json:"name"`
+	Quantity           int    `json:"quantity"`
+	Reason             Reason `json:"reason"`
+	SuggestedSellPrice int    `json:"suggested_sell_price"`
+}
+
+// ClosedPosition is a Position that has since been sold, kept around for
+// Store.Stats' realized P&L and win-rate numbers.
+type ClosedPosition struct {
+	Position
+	SellPrice int       `json:"sell_price"`
+	SellTime  time.Time `json:"sell_time"`
+	ProfitGP  int       `json:"profit_gp"`
+	Reason    Reason    `json:"reason"`
+}
+
+// Stats summarizes realized performance across every closed position in a
+// Store. Unrealized PnL isn't included here since it depends on current
+// market prices, which Store doesn't have access to; see
+// Analyzer.EvaluateExitSignals for the live view.
+type Stats struct {
+	ClosedCount      int     `json:"closed_count"`
+	RealizedProfitGP int     `json:"realized_profit_gp"`
+	WinRate          float64 `json:"win_rate"`
+}
+
+// snapshot is the on-disk JSON form of a Store.
+type snapshot struct {
+	Open   []Position       `json:"open"`
+	Closed []ClosedPosition `json:"closed"`
+}
+
+// Store persists open and closed positions to a JSON file on disk, so
+// flips survive process restarts. A Store created with an empty path is
+// in-memory only; Save is then a no-op.
+type Store struct {
+	mu     sync.RWMutex
+	path   string
+	open   []Position
+	closed []ClosedPosition
+}
+
+// NewStore creates an empty Store backed by path (used by Save/Load).
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load restores a previously-saved store from its configured path. A
+// missing file is not an error -- it just means this is a cold start.
+func (s *Store) Load() error {
+	if s == nil || s.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading position store: %w", err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("decoding position store: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.open = snap.Open
+	s.closed = snap.Closed
+	return nil
+}
+
+// Save persists the store to its configured path as JSON. A Store created
+// with an empty path is in-memory only and Save is a no-op.
+func (s *Store) Save() error {
+	if s == nil || s.path == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	snap := snapshot{Open: s.open, Closed: s.closed}
+	s.mu.RUnlock()
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding position store: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("writing position store: %w", err)
+	}
+	return nil
+}
+
+// Open records a newly-bought position.
+func (s *Store) Open(p Position) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.open = append(s.open, p)
+}
+
+// Close removes the open position for itemID, records it as a
+// ClosedPosition priced at sellPrice, and returns it. An unknown itemID is
+// an error -- callers should only close positions Positions() reported.
+func (s *Store) Close(itemID int, sellPrice int, sellTime time.Time, reason Reason) (ClosedPosition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, p := range s.open {
+		if p.ItemID != itemID {
+			continue
+		}
+		closed := ClosedPosition{
+			Position:  p,
+			SellPrice: sellPrice,
+			SellTime:  sellTime,
+			ProfitGP:  (sellPrice - p.BuyPrice) * p.Quantity,
+			Reason:    reason,
+		}
+		s.open = append(s.open[:i], s.open[i+1:]...)
+		s.closed = append(s.closed, closed)
+		return closed, nil
+	}
+	return ClosedPosition{}, fmt.Errorf("no open position for item %d", itemID)
+}
+
+// Positions returns a copy of every currently-open position.
+func (s *Store) Positions() []Position {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Position, len(s.open))
+	copy(out, s.open)
+	return out
+}
+
+// Stats returns the realized P&L and win rate across every closed
+// position.
+func (s *Store) Stats() Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := Stats{ClosedCount: len(s.closed)}
+	wins := 0
+	for _, c := range s.closed {
+		stats.RealizedProfitGP += c.ProfitGP
+		if c.ProfitGP > 0 {
+			wins++
+		}
+	}
+	if stats.ClosedCount > 0 {
+		stats.WinRate = float64(wins) / float64(stats.ClosedCount)
+	}
+	return stats
+}
@@ -0,0 +1,73 @@
+package position
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreOpenAndClose(t *testing.T) {
+	s := NewStore("")
+	s.Open(Position{ItemID: 1, Name: "Test Item", Quantity: 10, BuyPrice: 100, BuyTime: time.Now()})
+
+	if got := s.Positions(); len(got) != 1 {
+		t.Fatalf("expected 1 open position, got %d", len(got))
+	}
+
+	closed, err := s.Close(1, 120, time.Now(), ReasonTakeProfit)
+	if err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if closed.ProfitGP != 200 {
+		t.Errorf("expected profit of (120-100)*10=200, got %d", closed.ProfitGP)
+	}
+	if len(s.Positions()) != 0 {
+		t.Errorf("expected no open positions after close, got %d", len(s.Positions()))
+	}
+
+	stats := s.Stats()
+	if stats.ClosedCount != 1 || stats.RealizedProfitGP != 200 || stats.WinRate != 1.0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestStoreCloseUnknownItem(t *testing.T) {
+	s := NewStore("")
+	if _, err := s.Close(99, 100, time.Now(), ReasonStale); err == nil {
+		t.Error("expected an error closing a position that was never opened")
+	}
+}
+
+func TestStoreSaveAndLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "positions.json")
+
+	s := NewStore(path)
+	s.Open(Position{ItemID: 1, Name: "Test Item", Quantity: 5, BuyPrice: 50, BuyTime: time.Now()})
+	if _, err := s.Close(1, 60, time.Now(), ReasonTrailing); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	s.Open(Position{ItemID: 2, Name: "Another Item", Quantity: 1, BuyPrice: 1000, BuyTime: time.Now()})
+
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	loaded := NewStore(path)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if got := loaded.Positions(); len(got) != 1 || got[0].ItemID != 2 {
+		t.Errorf("expected the still-open item 2 position to round-trip, got %+v", got)
+	}
+	if stats := loaded.Stats(); stats.ClosedCount != 1 {
+		t.Errorf("expected the closed position to round-trip, got stats %+v", stats)
+	}
+}
+
+func TestStoreLoadMissingFileIsNotAnError(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	if err := s.Load(); err != nil {
+		t.Errorf("Load of a missing file should be a no-op cold start, got error: %v", err)
+	}
+}
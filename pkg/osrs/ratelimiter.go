@@ -0,0 +1,313 @@
+package osrs
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// rateLimiterAIMDRecoverySteps is how many successful Observe calls it
+	// takes to linearly walk a throttled RateLimiter's refill interval back
+	// to its configured max after a 429/503.
+	rateLimiterAIMDRecoverySteps = 10
+
+	// rateLimiterMaxBackoff caps how far AIMD can stretch a RateLimiter's
+	// refill interval (as a multiple of its configured max), so a run of
+	// 429s can't stall a bucket indefinitely.
+	rateLimiterMaxBackoff = 8
+)
+
+// RateLimiter is a single endpoint's adaptive token bucket. Wait blocks on
+// a channel refilled by a background ticker rather than the previous
+// mutex+time.After scheme, which recomputed "tokens available" from
+// elapsed time under a lock and could clobber a concurrent waiter's token
+// consumption (it always reset tokens to maxTokens-1 after a wait,
+// regardless of how many goroutines were actually waiting). Observe feeds
+// back the server's own signal (429/503 + Retry-After) to halve the
+// refill rate and linearly recover it (AIMD), instead of trusting a fixed
+// req/s forever.
+type RateLimiter struct {
+	mu          sync.Mutex
+	tokens      chan struct{}
+	ticker      *time.Ticker
+	maxInterval time.Duration
+	curInterval time.Duration
+	recoverStep time.Duration
+	recoverLeft int
+}
+
+// NewRateLimiter creates a rate limiter with specified requests per second.
+// Its bucket starts full (maxTokens = ceil(requestsPerSecond)) so an idle
+// limiter's first burst isn't delayed by the refill ticker.
+func NewRateLimiter(requestsPerSecond float64) *RateLimiter {
+	maxTokens := int(math.Ceil(requestsPerSecond))
+	if maxTokens < 1 {
+		maxTokens = 1
+	}
+	interval := time.Duration(float64(time.Second) / requestsPerSecond)
+
+	rl := &RateLimiter{
+		tokens:      make(chan struct{}, maxTokens),
+		ticker:      time.NewTicker(interval),
+		maxInterval: interval,
+		curInterval: interval,
+	}
+	for i := 0; i < maxTokens; i++ {
+		rl.tokens <- struct{}{}
+	}
+
+	go rl.refill()
+	return rl
+}
+
+func (rl *RateLimiter) refill() {
+	for range rl.ticker.C {
+		select {
+		case rl.tokens <- struct{}{}:
+		default:
+			// Bucket already full.
+		}
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-rl.tokens:
+		return nil
+	}
+}
+
+// Stop releases the background refill goroutine. Callers that create
+// short-lived RateLimiters (e.g. tests) should call this to avoid leaking
+// it; a RateLimiter living for the process lifetime (the usual case) can
+// skip it.
+func (rl *RateLimiter) Stop() {
+	rl.ticker.Stop()
+}
+
+// Observe adjusts rl's refill rate based on the outcome of the request
+// that just consumed a Wait token (AIMD): a 429 or 503 doubles the refill
+// interval (or sets it to retryAfter if the server asked for longer),
+// capped at rateLimiterMaxBackoff times the configured max, and starts a
+// linear recovery back to that max over rateLimiterAIMDRecoverySteps
+// subsequent Observe calls. Any other status counts as one recovery step.
+func (rl *RateLimiter) Observe(status int, retryAfter time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+		newInterval := rl.curInterval * 2
+		if retryAfter > newInterval {
+			newInterval = retryAfter
+		}
+		if maxBackoff := rl.maxInterval * rateLimiterMaxBackoff; newInterval > maxBackoff {
+			newInterval = maxBackoff
+		}
+
+		rl.curInterval = newInterval
+		rl.recoverStep = (rl.curInterval - rl.maxInterval) / rateLimiterAIMDRecoverySteps
+		rl.recoverLeft = rateLimiterAIMDRecoverySteps
+		rl.ticker.Reset(rl.curInterval)
+		return
+	}
+
+	if rl.recoverLeft <= 0 {
+		return
+	}
+
+	rl.curInterval -= rl.recoverStep
+	rl.recoverLeft--
+	if rl.recoverLeft == 0 || rl.curInterval < rl.maxInterval {
+		rl.curInterval = rl.maxInterval
+	}
+	rl.ticker.Reset(rl.curInterval)
+}
+
+// EffectiveRPS returns rl's current steady-state requests/second, reflecting
+// any AIMD backoff Observe has applied.
+func (rl *RateLimiter) EffectiveRPS() float64 {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rl.curInterval <= 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(rl.curInterval)
+}
+
+// RateLimiterGroup holds one adaptive RateLimiter per API endpoint (e.g.
+// "/latest", "/timeseries"), keyed by whatever string the caller passes to
+// Wait/Observe. This way a 429 that throttles one endpoint's bucket (see
+// RateLimiter.Observe) doesn't slow down calls to an endpoint the server
+// isn't complaining about.
+type RateLimiterGroup struct {
+	mu             sync.Mutex
+	limiters       map[string]*RateLimiter
+	requestsPerSec float64
+}
+
+// NewRateLimiterGroup creates a group whose limiters are each created (on
+// first use, see Limiter) at requestsPerSecond.
+func NewRateLimiterGroup(requestsPerSecond float64) *RateLimiterGroup {
+	return &RateLimiterGroup{
+		limiters:       make(map[string]*RateLimiter),
+		requestsPerSec: requestsPerSecond,
+	}
+}
+
+// Limiter returns endpoint's RateLimiter, creating one at the group's
+// configured rate on first use.
+func (g *RateLimiterGroup) Limiter(endpoint string) *RateLimiter {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	rl, ok := g.limiters[endpoint]
+	if !ok {
+		rl = NewRateLimiter(g.requestsPerSec)
+		g.limiters[endpoint] = rl
+	}
+	return rl
+}
+
+// Wait blocks until endpoint's bucket has a token available or ctx is
+// done.
+func (g *RateLimiterGroup) Wait(ctx context.Context, endpoint string) error {
+	return g.Limiter(endpoint).Wait(ctx)
+}
+
+// Observe feeds endpoint's bucket the outcome of the request that just
+// consumed its token; see RateLimiter.Observe.
+func (g *RateLimiterGroup) Observe(endpoint string, status int, retryAfter time.Duration) {
+	g.Limiter(endpoint).Observe(status, retryAfter)
+}
+
+// EffectiveRPS returns the current requests/second for every endpoint that
+// has been used at least once, reflecting any AIMD backoff Observe has
+// applied to that endpoint's bucket.
+func (g *RateLimiterGroup) EffectiveRPS() map[string]float64 {
+	g.mu.Lock()
+	limiters := make(map[string]*RateLimiter, len(g.limiters))
+	for endpoint, rl := range g.limiters {
+		limiters[endpoint] = rl
+	}
+	g.mu.Unlock()
+
+	rps := make(map[string]float64, len(limiters))
+	for endpoint, rl := range limiters {
+		rps[endpoint] = rl.EffectiveRPS()
+	}
+	return rps
+}
+
+// parseRetryAfter reads the Retry-After header as a whole number of
+// seconds, the format throttling responses use in practice; the
+// HTTP-date form, a missing header, and an invalid value all return 0.
+func parseRetryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(v)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// RequestBudget caps how many requests may be made across any rolling
+// window, independent of any single endpoint's RateLimiter bucket -- so a
+// scheduled full-catalogue refresh and a concurrent interactive query draw
+// from one shared ceiling instead of each pacing itself and together still
+// blowing past what the Wiki API actually tolerates per minute.
+type RequestBudget struct {
+	mu         sync.Mutex
+	limit      int
+	window     time.Duration
+	timestamps []time.Time
+}
+
+// NewRequestBudget creates a budget permitting up to limit requests within
+// any rolling window-sized span.
+func NewRequestBudget(limit int, window time.Duration) *RequestBudget {
+	return &RequestBudget{limit: limit, window: window}
+}
+
+// Reserve blocks until a slot is free within the rolling window, then
+// records this request's timestamp. Unlike RateLimiter.Wait, which paces a
+// steady req/s, Reserve only blocks once limit requests have already
+// landed within the last window.
+func (b *RequestBudget) Reserve(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		b.evict()
+		if len(b.timestamps) < b.limit {
+			b.timestamps = append(b.timestamps, time.Now())
+			b.mu.Unlock()
+			return nil
+		}
+		wait := b.window - time.Since(b.timestamps[0])
+		b.mu.Unlock()
+		if wait < 0 {
+			wait = 0
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// evict drops timestamps older than window. Callers must hold b.mu.
+func (b *RequestBudget) evict() {
+	cutoff := time.Now().Add(-b.window)
+	i := 0
+	for i < len(b.timestamps) && b.timestamps[i].Before(cutoff) {
+		i++
+	}
+	b.timestamps = b.timestamps[i:]
+}
+
+// Used returns how many requests fall within the current rolling window.
+func (b *RequestBudget) Used() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.evict()
+	return len(b.timestamps)
+}
+
+// Semaphore is a global concurrency limit, independent of any RateLimiter's
+// req/s bucket: it bounds how many requests are in flight at once so a
+// caller (e.g. LoadVolumeData) can raise its worker pool size -- more
+// goroutines queued up waiting on a slow bucket -- without also raising
+// how many requests actually hit the network at the same instant.
+type Semaphore struct {
+	tokens chan struct{}
+}
+
+// NewSemaphore creates a Semaphore permitting up to n concurrent holders.
+func NewSemaphore(n int) *Semaphore {
+	return &Semaphore{tokens: make(chan struct{}, n)}
+}
+
+// Acquire blocks until a slot is free or ctx is done.
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	select {
+	case s.tokens <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees the slot acquired by a prior successful Acquire.
+func (s *Semaphore) Release() {
+	<-s.tokens
+}
@@ -0,0 +1,212 @@
+package osrs
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBasic(t *testing.T) {
+	rl := NewRateLimiter(10.0)
+	defer rl.Stop()
+
+	ctx := context.Background()
+
+	// First request should pass immediately -- the bucket starts full.
+	start := time.Now()
+	if err := rl.Wait(ctx); err != nil {
+		t.Errorf("first Wait failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("first Wait took too long: %v", elapsed)
+	}
+}
+
+func TestRateLimiterContextCancellation(t *testing.T) {
+	rl := NewRateLimiter(0.5) // Very slow: 1 request per 2 seconds
+	defer rl.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// First request consumes the initial token.
+	if err := rl.Wait(ctx); err != nil {
+		t.Errorf("first Wait failed: %v", err)
+	}
+
+	// Second request should time out waiting for a refill.
+	if err := rl.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("second Wait = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRateLimiterObserveBacksOffOn429(t *testing.T) {
+	rl := NewRateLimiter(100.0) // fast, so the test doesn't need to wait long
+	defer rl.Stop()
+
+	before := rl.curInterval
+	rl.Observe(http.StatusTooManyRequests, 0)
+	if rl.curInterval <= before {
+		t.Errorf("curInterval after a 429 = %v, want > %v", rl.curInterval, before)
+	}
+}
+
+func TestRateLimiterEffectiveRPS(t *testing.T) {
+	rl := NewRateLimiter(10.0)
+	defer rl.Stop()
+
+	if got := rl.EffectiveRPS(); got < 9.9 || got > 10.1 {
+		t.Errorf("EffectiveRPS() = %v, want ~10.0 before any backoff", got)
+	}
+
+	rl.Observe(http.StatusTooManyRequests, 0)
+	if got := rl.EffectiveRPS(); got >= 10.0 {
+		t.Errorf("EffectiveRPS() after a 429 = %v, want < 10.0", got)
+	}
+}
+
+func TestRateLimiterGroupEffectiveRPS(t *testing.T) {
+	g := NewRateLimiterGroup(5.0)
+
+	if rps := g.EffectiveRPS(); len(rps) != 0 {
+		t.Errorf("EffectiveRPS() before any use = %v, want empty", rps)
+	}
+
+	g.Wait(context.Background(), "/latest")
+	rps := g.EffectiveRPS()
+	if got, ok := rps["/latest"]; !ok || got < 4.9 || got > 5.1 {
+		t.Errorf("EffectiveRPS()[\"/latest\"] = %v, want ~5.0", rps)
+	}
+}
+
+func TestRateLimiterObserveHonorsRetryAfter(t *testing.T) {
+	rl := NewRateLimiter(100.0)
+	defer rl.Stop()
+
+	rl.Observe(http.StatusTooManyRequests, 5*time.Second)
+	if rl.curInterval < 5*time.Second {
+		t.Errorf("curInterval after Retry-After=5s = %v, want >= 5s", rl.curInterval)
+	}
+}
+
+func TestRateLimiterObserveRecoversLinearly(t *testing.T) {
+	rl := NewRateLimiter(100.0)
+	defer rl.Stop()
+
+	rl.Observe(http.StatusTooManyRequests, 0)
+	throttled := rl.curInterval
+
+	for i := 0; i < rateLimiterAIMDRecoverySteps; i++ {
+		rl.Observe(http.StatusOK, 0)
+	}
+
+	if rl.curInterval != rl.maxInterval {
+		t.Errorf("curInterval after full recovery = %v, want maxInterval %v", rl.curInterval, rl.maxInterval)
+	}
+	if rl.curInterval >= throttled {
+		t.Errorf("curInterval didn't shrink during recovery: before=%v after=%v", throttled, rl.curInterval)
+	}
+}
+
+func TestRateLimiterGroupSeparatesBuckets(t *testing.T) {
+	g := NewRateLimiterGroup(100.0)
+
+	g.Observe("/5m", http.StatusTooManyRequests, 0)
+
+	throttled := g.Limiter("/5m").curInterval
+	untouched := g.Limiter("/1h").curInterval
+
+	if throttled == untouched {
+		t.Errorf("expected /5m and /1h to have independent buckets, both at %v", throttled)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+	}{
+		{"missing", "", 0},
+		{"seconds", "30", 30 * time.Second},
+		{"invalid", "not-a-number", 0},
+		{"negative", "-5", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			if tt.header != "" {
+				h.Set("Retry-After", tt.header)
+			}
+			if got := parseRetryAfter(h); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestBudgetAllowsUpToLimit(t *testing.T) {
+	b := NewRequestBudget(2, time.Minute)
+	ctx := context.Background()
+
+	if err := b.Reserve(ctx); err != nil {
+		t.Fatalf("first Reserve failed: %v", err)
+	}
+	if err := b.Reserve(ctx); err != nil {
+		t.Fatalf("second Reserve failed: %v", err)
+	}
+	if got := b.Used(); got != 2 {
+		t.Errorf("Used() = %d, want 2", got)
+	}
+}
+
+func TestRequestBudgetBlocksOverLimit(t *testing.T) {
+	b := NewRequestBudget(1, 50*time.Millisecond)
+	ctx := context.Background()
+
+	if err := b.Reserve(ctx); err != nil {
+		t.Fatalf("first Reserve failed: %v", err)
+	}
+
+	blockedCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := b.Reserve(blockedCtx); err != context.DeadlineExceeded {
+		t.Errorf("Reserve over limit = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRequestBudgetEvictsExpiredTimestamps(t *testing.T) {
+	b := NewRequestBudget(1, 10*time.Millisecond)
+	ctx := context.Background()
+
+	if err := b.Reserve(ctx); err != nil {
+		t.Fatalf("first Reserve failed: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if err := b.Reserve(ctx); err != nil {
+		t.Errorf("Reserve after window elapsed should not block: %v", err)
+	}
+}
+
+func TestSemaphore(t *testing.T) {
+	sem := NewSemaphore(1)
+	ctx := context.Background()
+
+	if err := sem.Acquire(ctx); err != nil {
+		t.Fatalf("first Acquire failed: %v", err)
+	}
+
+	acquireCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := sem.Acquire(acquireCtx); err != context.DeadlineExceeded {
+		t.Errorf("second Acquire while held = %v, want context.DeadlineExceeded", err)
+	}
+
+	sem.Release()
+	if err := sem.Acquire(ctx); err != nil {
+		t.Errorf("Acquire after Release failed: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package osrs
+
+import (
+	"context"
+	"sync"
+)
+
+// CachedResponse is one endpoint+params key's last successful response:
+// the raw body (so GetItemMapping/GetLatestPrices can re-unmarshal it on a
+// 304 without Client having to know their result types) plus the
+// validators the server returned alongside it.
+type CachedResponse struct {
+	Body         []byte
+	ETag         string
+	LastModified string
+}
+
+// ResponseCache is where Client stashes CachedResponse per cache key (see
+// cacheKeyFor) so a later call can send If-None-Match/If-Modified-Since
+// and, on a 304, hand the caller back the same parsed data without
+// re-downloading it. MemoryResponseCache is the default; a caller wanting
+// the cache to survive process restarts (e.g. so a redeployed GapFiller
+// doesn't lose its mapping ETag) can set Client.cache to an adapter over
+// this repo's existing Postgres-backed ItemStore.GetSyncMetadata/
+// SetSyncMetadata (see pkg/collector/item_syncer.go's syncMetadataETagKey
+// pattern) instead of introducing a new storage dependency.
+type ResponseCache interface {
+	Get(ctx context.Context, key string) (CachedResponse, bool, error)
+	Set(ctx context.Context, key string, resp CachedResponse) error
+}
+
+// MemoryResponseCache is a process-lifetime ResponseCache: a plain map
+// guarded by a mutex, matching RateLimiterGroup's per-key-map shape. It
+// never evicts -- the handful of endpoints Client caches (mapping, latest)
+// is small and bounded, so there's no growth concern worth a TTL or LRU.
+type MemoryResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]CachedResponse
+}
+
+// NewMemoryResponseCache creates an empty MemoryResponseCache.
+func NewMemoryResponseCache() *MemoryResponseCache {
+	return &MemoryResponseCache{entries: make(map[string]CachedResponse)}
+}
+
+// Get returns key's cached response, if any. Never errors -- satisfies
+// ResponseCache's signature for adapters (e.g. a DB-backed one) that can.
+func (m *MemoryResponseCache) Get(ctx context.Context, key string) (CachedResponse, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	resp, ok := m.entries[key]
+	return resp, ok, nil
+}
+
+// Set stores resp under key, replacing whatever was cached before.
+func (m *MemoryResponseCache) Set(ctx context.Context, key string, resp CachedResponse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = resp
+	return nil
+}
+
+// cacheKeyFor derives a ResponseCache key for endpoint+params, reusing
+// rateLimitKeyFor's id/timestep-aware shape so an item-scoped /latest
+// request and the all-items /latest request don't collide, and so
+// /timeseries's three timesteps cache independently.
+func cacheKeyFor(endpoint string, params map[string]string) string {
+	key := rateLimitKeyFor(endpoint, params)
+	if id, ok := params["id"]; ok {
+		key += "?id=" + id
+	}
+	return key
+}
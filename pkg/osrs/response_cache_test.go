@@ -0,0 +1,70 @@
+package osrs
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryResponseCacheGetMiss(t *testing.T) {
+	c := NewMemoryResponseCache()
+
+	_, ok, err := c.Get(context.Background(), "/mapping")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if ok {
+		t.Error("Get on empty cache should miss")
+	}
+}
+
+func TestMemoryResponseCacheSetThenGet(t *testing.T) {
+	c := NewMemoryResponseCache()
+	ctx := context.Background()
+
+	want := CachedResponse{Body: []byte(`{"a":1}`), ETag: `"abc"`, LastModified: "Mon, 01 Jan 2024 00:00:00 GMT"}
+	if err := c.Set(ctx, "/mapping", want); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	got, ok, err := c.Get(ctx, "/mapping")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if !ok {
+		t.Fatal("Get should hit after Set")
+	}
+	if string(got.Body) != string(want.Body) || got.ETag != want.ETag || got.LastModified != want.LastModified {
+		t.Errorf("Get = %+v, want %+v", got, want)
+	}
+}
+
+func TestMemoryResponseCacheSetOverwrites(t *testing.T) {
+	c := NewMemoryResponseCache()
+	ctx := context.Background()
+
+	c.Set(ctx, "/mapping", CachedResponse{Body: []byte("old"), ETag: `"old"`})
+	c.Set(ctx, "/mapping", CachedResponse{Body: []byte("new"), ETag: `"new"`})
+
+	got, _, _ := c.Get(ctx, "/mapping")
+	if string(got.Body) != "new" || got.ETag != `"new"` {
+		t.Errorf("Get = %+v, want the overwritten entry", got)
+	}
+}
+
+func TestCacheKeyForDistinctItems(t *testing.T) {
+	latest := cacheKeyFor("/latest", nil)
+	itemA := cacheKeyFor("/latest", map[string]string{"id": "4151"})
+	itemB := cacheKeyFor("/latest", map[string]string{"id": "2"})
+
+	if latest == itemA || latest == itemB || itemA == itemB {
+		t.Errorf("cacheKeyFor should give distinct keys per item: %q, %q, %q", latest, itemA, itemB)
+	}
+}
+
+func TestCacheKeyForStableAcrossCalls(t *testing.T) {
+	a := cacheKeyFor("/mapping", nil)
+	b := cacheKeyFor("/mapping", nil)
+	if a != b {
+		t.Errorf("cacheKeyFor should be stable for identical inputs: %q != %q", a, b)
+	}
+}
@@ -0,0 +1,60 @@
+package osrs
+
+// DefaultActivationRatios and DefaultCallbackRatios mirror a typical
+// trailing-stop ladder: tighten the stop progressively as price runs further
+// above entry. Used when a job doesn't override them via config.Risk.
+var (
+	DefaultActivationRatios = []float64{0.001, 0.01, 0.05} // 0.1%, 1%, 5%
+	DefaultCallbackRatios   = []float64{0.002, 0.01, 0.02} // 0.2%, 1%, 2%
+)
+
+// DefaultEntryLayerCount is the number of rungs in a layered-entry plan when
+// a job doesn't override it via config.Risk.
+const DefaultEntryLayerCount = 3
+
+// ComputeTrailingLevels builds a trailing-stop ladder for an item's current
+// insta-sell price (the entry price for a buy order): at each activation
+// ratio above entry, a trailing stop activates and follows price down by
+// the paired callback ratio. activationRatios and callbackRatios must be
+// the same length and ordered ascending; mismatched or empty input yields
+// no levels.
+func ComputeTrailingLevels(item ItemData, activationRatios, callbackRatios []float64) []TrailingLevel {
+	if item.InstaSellPrice == nil || len(activationRatios) == 0 || len(activationRatios) != len(callbackRatios) {
+		return nil
+	}
+
+	entry := float64(*item.InstaSellPrice)
+	levels := make([]TrailingLevel, len(activationRatios))
+	for i, activation := range activationRatios {
+		levels[i] = TrailingLevel{
+			ActivationRatio: activation,
+			CallbackRatio:   callbackRatios[i],
+			SuggestedPrice:  int(entry * (1 + activation)),
+		}
+	}
+	return levels
+}
+
+// ComputeEntryLayers splits an item's buy limit across layerCount price
+// levels below its current insta-sell price, each layer further below the
+// last and sized so the layers sum to 100% of the buy limit. A nil
+// insta-sell price, zero buy limit, or non-positive layerCount yields no
+// layers.
+func ComputeEntryLayers(item ItemData, layerCount int) []EntryLayer {
+	if item.InstaSellPrice == nil || item.BuyLimit <= 0 || layerCount <= 0 {
+		return nil
+	}
+
+	entry := float64(*item.InstaSellPrice)
+	layers := make([]EntryLayer, layerCount)
+	quantityPct := 1.0 / float64(layerCount)
+	for i := 0; i < layerCount; i++ {
+		// Step down 0.5% of entry per layer, so deeper layers buy cheaper.
+		offsetPct := 0.005 * float64(i)
+		layers[i] = EntryLayer{
+			PriceOffset: int(entry * offsetPct),
+			QuantityPct: quantityPct,
+		}
+	}
+	return layers
+}
@@ -0,0 +1,64 @@
+package osrs
+
+import "testing"
+
+func TestComputeTrailingLevels(t *testing.T) {
+	item := ItemData{InstaSellPrice: intPtr(1000)}
+
+	levels := ComputeTrailingLevels(item, []float64{0.001, 0.01, 0.05}, []float64{0.002, 0.01, 0.02})
+	if len(levels) != 3 {
+		t.Fatalf("expected 3 levels, got %d", len(levels))
+	}
+	if levels[0].SuggestedPrice != 1001 {
+		t.Errorf("expected first activation price 1001, got %d", levels[0].SuggestedPrice)
+	}
+	if levels[2].SuggestedPrice != 1050 {
+		t.Errorf("expected third activation price 1050, got %d", levels[2].SuggestedPrice)
+	}
+	if levels[1].CallbackRatio != 0.01 {
+		t.Errorf("expected second callback ratio 0.01, got %f", levels[1].CallbackRatio)
+	}
+}
+
+func TestComputeTrailingLevelsNoEntryPrice(t *testing.T) {
+	item := ItemData{}
+	if levels := ComputeTrailingLevels(item, DefaultActivationRatios, DefaultCallbackRatios); levels != nil {
+		t.Errorf("expected nil levels for item with no insta-sell price, got %v", levels)
+	}
+}
+
+func TestComputeTrailingLevelsMismatchedRatios(t *testing.T) {
+	item := ItemData{InstaSellPrice: intPtr(1000)}
+	if levels := ComputeTrailingLevels(item, []float64{0.01}, nil); levels != nil {
+		t.Errorf("expected nil levels for mismatched ratio lengths, got %v", levels)
+	}
+}
+
+func TestComputeEntryLayers(t *testing.T) {
+	item := ItemData{InstaSellPrice: intPtr(1000), BuyLimit: 100}
+
+	layers := ComputeEntryLayers(item, 3)
+	if len(layers) != 3 {
+		t.Fatalf("expected 3 layers, got %d", len(layers))
+	}
+	if layers[0].PriceOffset != 0 {
+		t.Errorf("expected first layer at entry price (offset 0), got %d", layers[0].PriceOffset)
+	}
+	if layers[1].PriceOffset <= layers[0].PriceOffset {
+		t.Errorf("expected layers to step down further below entry, got offsets %d then %d", layers[0].PriceOffset, layers[1].PriceOffset)
+	}
+	total := 0.0
+	for _, l := range layers {
+		total += l.QuantityPct
+	}
+	if total < 0.999 || total > 1.001 {
+		t.Errorf("expected layer quantities to sum to 1.0, got %f", total)
+	}
+}
+
+func TestComputeEntryLayersNoBuyLimit(t *testing.T) {
+	item := ItemData{InstaSellPrice: intPtr(1000), BuyLimit: 0}
+	if layers := ComputeEntryLayers(item, 3); layers != nil {
+		t.Errorf("expected nil layers for item with no buy limit, got %v", layers)
+	}
+}
@@ -0,0 +1,257 @@
+package osrs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"time"
+
+	"osrs-flipping/pkg/osrs/store"
+)
+
+// snapshotMagic/snapshotVersion identify the on-disk snapshot format (see
+// FileDataSource.ExportSnapshot), so ImportSnapshot can reject a file from
+// an incompatible future version instead of decoding it into garbage.
+const (
+	snapshotMagic   = "OSRSSNAP"
+	snapshotVersion = 1
+)
+
+// SnapshotSource is implemented by a DataSource that can serialize its full
+// state to a portable, content-addressed archive and restore from one --
+// analogous to a Filecoin/Lotus CAR file for offline deal analysis. Kept as
+// its own interface rather than folded into DataSource, so APIDataSource and
+// DBDataSource (which have no meaningful "import" of their own) don't need a
+// pair of no-op methods; the same shape as ScrapeRecorder/IncidentNotifier
+// being separate from Analyzer's core surface.
+type SnapshotSource interface {
+	// ExportSnapshot writes the full snapshot to w: a versioned header,
+	// length-prefixed gob records (item mappings, latest prices, per-item
+	// timeseries), and a trailing SHA-256 of everything written before it.
+	ExportSnapshot(ctx context.Context, w io.Writer) error
+
+	// ImportSnapshot reads a snapshot previously written by ExportSnapshot,
+	// verifying its trailing checksum before replacing any in-memory state.
+	ImportSnapshot(ctx context.Context, r io.Reader) error
+}
+
+// snapshotSeries is one (item, interval) series within a snapshot's
+// timeseries record.
+type snapshotSeries struct {
+	ItemID   int
+	Interval store.Interval
+	Points   []store.DataPoint
+}
+
+// FileDataSource serves ItemData from an in-memory snapshot captured via
+// Capture or loaded via ImportSnapshot, rather than fetching it live. It
+// gives HybridDataSource a third, offline tier (DB -> snapshot file, if
+// within TTL -> API) and lets CI or a bug report run the analyzer against a
+// frozen dataset instead of the live Wiki API.
+type FileDataSource struct {
+	freshThreshold time.Duration
+
+	items      []ItemData
+	mappings   []ItemMapping
+	series     map[int]map[store.Interval][]store.DataPoint
+	capturedAt time.Time
+}
+
+// NewFileDataSource creates an empty FileDataSource. Call Capture or
+// ImportSnapshot to populate it before LoadPrices can return anything.
+// freshThreshold bounds how old a capture can be before IsFresh reports
+// false, e.g. to stop HybridDataSource from trusting a stale snapshot file.
+func NewFileDataSource(freshThreshold time.Duration) *FileDataSource {
+	return &FileDataSource{freshThreshold: freshThreshold}
+}
+
+func (f *FileDataSource) Name() string {
+	return "Snapshot File"
+}
+
+func (f *FileDataSource) IsFresh(ctx context.Context) (bool, error) {
+	if f.capturedAt.IsZero() {
+		return false, nil
+	}
+	return time.Since(f.capturedAt) <= f.freshThreshold, nil
+}
+
+func (f *FileDataSource) LoadPrices(ctx context.Context) ([]ItemData, error) {
+	if f.capturedAt.IsZero() {
+		return nil, fmt.Errorf("no snapshot loaded")
+	}
+	return append([]ItemData(nil), f.items...), nil
+}
+
+// LoadVolumeData is a no-op: a captured/imported snapshot's items already
+// carry whatever volume metrics were present when it was taken.
+func (f *FileDataSource) LoadVolumeData(ctx context.Context, items []ItemData, maxItems int) error {
+	return nil
+}
+
+// Capture snapshots items (with volume metrics already merged in, as
+// returned by LoadPrices+LoadVolumeData) and s's full timeseries history, so
+// a later ExportSnapshot call has something to serialize.
+func (f *FileDataSource) Capture(items []ItemData, s *store.Store) {
+	f.items = append([]ItemData(nil), items...)
+	f.mappings = make([]ItemMapping, len(items))
+	for i, item := range items {
+		f.mappings[i] = ItemMapping{
+			ID:       item.ItemID,
+			Name:     item.Name,
+			Members:  item.Members,
+			BuyLimit: item.BuyLimit,
+		}
+	}
+	f.series = s.AllSeries()
+	f.capturedAt = time.Now()
+}
+
+// RestoreSeriesInto replays this snapshot's captured timeseries into s via
+// Append, e.g. so a test or CI run can seed a fresh store.Store from an
+// imported snapshot instead of hitting the Wiki API for history.
+func (f *FileDataSource) RestoreSeriesInto(s *store.Store) {
+	for itemID, byInterval := range f.series {
+		for interval, points := range byInterval {
+			for _, p := range points {
+				s.Append(itemID, interval, p)
+			}
+		}
+	}
+}
+
+// ExportSnapshot writes f's captured mappings, latest prices, and
+// timeseries as a versioned, checksummed archive. Returns an error if
+// nothing has been captured yet.
+func (f *FileDataSource) ExportSnapshot(ctx context.Context, w io.Writer) error {
+	if f.capturedAt.IsZero() {
+		return fmt.Errorf("no snapshot captured to export")
+	}
+
+	hasher := sha256.New()
+	out := io.MultiWriter(w, hasher)
+
+	header := make([]byte, len(snapshotMagic)+1)
+	copy(header, snapshotMagic)
+	header[len(snapshotMagic)] = snapshotVersion
+	if _, err := out.Write(header); err != nil {
+		return fmt.Errorf("writing snapshot header: %w", err)
+	}
+
+	seriesList := make([]snapshotSeries, 0, len(f.series))
+	for itemID, byInterval := range f.series {
+		for interval, points := range byInterval {
+			seriesList = append(seriesList, snapshotSeries{ItemID: itemID, Interval: interval, Points: points})
+		}
+	}
+
+	records := []interface{}{f.mappings, f.items, seriesList}
+	for _, record := range records {
+		if err := writeSnapshotRecord(out, record); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.Write(hasher.Sum(nil)); err != nil {
+		return fmt.Errorf("writing snapshot checksum: %w", err)
+	}
+	return nil
+}
+
+// ImportSnapshot reads a snapshot written by ExportSnapshot, verifying the
+// header and trailing SHA-256 before replacing f's in-memory state. r must
+// support re-reading its full contents for the checksum check, so the
+// caller should pass something seekable or buffer it first (e.g.
+// bytes.NewReader).
+func (f *FileDataSource) ImportSnapshot(ctx context.Context, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+	if len(data) < sha256.Size {
+		return fmt.Errorf("snapshot too short to contain a checksum")
+	}
+
+	payload, trailer := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	sum := sha256.Sum256(payload)
+	if !bytes.Equal(sum[:], trailer) {
+		return fmt.Errorf("snapshot checksum mismatch: file may be corrupt")
+	}
+
+	buf := bytes.NewReader(payload)
+	header := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(buf, header); err != nil {
+		return fmt.Errorf("reading snapshot header: %w", err)
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return fmt.Errorf("not an osrs-flipping snapshot file")
+	}
+	if header[len(snapshotMagic)] != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", header[len(snapshotMagic)])
+	}
+
+	var mappings []ItemMapping
+	var items []ItemData
+	var seriesList []snapshotSeries
+	if err := readSnapshotRecord(buf, &mappings); err != nil {
+		return fmt.Errorf("reading mappings record: %w", err)
+	}
+	if err := readSnapshotRecord(buf, &items); err != nil {
+		return fmt.Errorf("reading prices record: %w", err)
+	}
+	if err := readSnapshotRecord(buf, &seriesList); err != nil {
+		return fmt.Errorf("reading timeseries record: %w", err)
+	}
+
+	series := make(map[int]map[store.Interval][]store.DataPoint)
+	for _, s := range seriesList {
+		byInterval, ok := series[s.ItemID]
+		if !ok {
+			byInterval = make(map[store.Interval][]store.DataPoint)
+			series[s.ItemID] = byInterval
+		}
+		byInterval[s.Interval] = s.Points
+	}
+
+	f.mappings = mappings
+	f.items = items
+	f.series = series
+	f.capturedAt = time.Now()
+	return nil
+}
+
+// writeSnapshotRecord gob-encodes record and writes it to w as a
+// length-prefixed (uint32 big-endian) block.
+func writeSnapshotRecord(w io.Writer, record interface{}) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(record); err != nil {
+		return fmt.Errorf("encoding snapshot record: %w", err)
+	}
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(buf.Len()))
+	if _, err := w.Write(length[:]); err != nil {
+		return fmt.Errorf("writing snapshot record length: %w", err)
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing snapshot record: %w", err)
+	}
+	return nil
+}
+
+// readSnapshotRecord reads one length-prefixed block written by
+// writeSnapshotRecord and gob-decodes it into dest.
+func readSnapshotRecord(r io.Reader, dest interface{}) error {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return fmt.Errorf("reading record length: %w", err)
+	}
+	buf := make([]byte, binary.BigEndian.Uint32(length[:]))
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return fmt.Errorf("reading record body: %w", err)
+	}
+	return gob.NewDecoder(bytes.NewReader(buf)).Decode(dest)
+}
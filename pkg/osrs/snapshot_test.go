@@ -0,0 +1,80 @@
+package osrs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"osrs-flipping/pkg/osrs/store"
+)
+
+func TestFileDataSourceExportImportRoundTrip(t *testing.T) {
+	s := store.New("", 0, 0)
+	base := time.Now().Add(-time.Hour)
+	s.Append(1, store.Interval5m, store.DataPoint{Timestamp: base, InstaBuyPrice: 100, InstaSellPrice: 95})
+	s.Append(1, store.Interval5m, store.DataPoint{Timestamp: base.Add(5 * time.Minute), InstaBuyPrice: 102, InstaSellPrice: 97})
+
+	items := []ItemData{
+		{ItemID: 1, Name: "Item A", InstaBuyPrice: intPtr(102), InstaSellPrice: intPtr(97), BuyLimit: 100},
+	}
+
+	src := NewFileDataSource(time.Hour)
+	src.Capture(items, s)
+
+	var buf bytes.Buffer
+	if err := src.ExportSnapshot(context.Background(), &buf); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	restored := NewFileDataSource(time.Hour)
+	if err := restored.ImportSnapshot(context.Background(), bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("unexpected import error: %v", err)
+	}
+
+	fresh, err := restored.IsFresh(context.Background())
+	if err != nil || !fresh {
+		t.Errorf("expected the imported snapshot to be fresh, got fresh=%v err=%v", fresh, err)
+	}
+
+	got, err := restored.LoadPrices(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected LoadPrices error: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "Item A" || *got[0].InstaBuyPrice != 102 {
+		t.Errorf("unexpected restored items: %+v", got)
+	}
+
+	restoredStore := store.New("", 0, 0)
+	restored.RestoreSeriesInto(restoredStore)
+	series := restoredStore.GetSeries(1, store.Interval5m, time.Time{})
+	if len(series) != 2 {
+		t.Errorf("expected 2 restored series points, got %d", len(series))
+	}
+}
+
+func TestFileDataSourceImportRejectsCorruptChecksum(t *testing.T) {
+	s := store.New("", 0, 0)
+	src := NewFileDataSource(time.Hour)
+	src.Capture([]ItemData{{ItemID: 1, Name: "Item A"}}, s)
+
+	var buf bytes.Buffer
+	if err := src.ExportSnapshot(context.Background(), &buf); err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	corrupt := buf.Bytes()
+	corrupt[0] ^= 0xFF
+
+	restored := NewFileDataSource(time.Hour)
+	if err := restored.ImportSnapshot(context.Background(), bytes.NewReader(corrupt)); err == nil {
+		t.Error("expected a checksum mismatch error on corrupted snapshot bytes")
+	}
+}
+
+func TestFileDataSourceLoadPricesErrorsWithoutCapture(t *testing.T) {
+	src := NewFileDataSource(time.Hour)
+	if _, err := src.LoadPrices(context.Background()); err == nil {
+		t.Error("expected an error from LoadPrices before any snapshot is captured")
+	}
+}
@@ -0,0 +1,85 @@
+package osrs
+
+import (
+	"time"
+
+	"osrs-flipping/pkg/osrs/store"
+)
+
+// SpreadConfig tunes applySpreadSignal's market-making quote suggestion,
+// analogous to the bbgo gap strategy's spread-shrink and quote-sizing
+// knobs.
+type SpreadConfig struct {
+	// SpreadShrinkFactor (k) pulls the suggested bid/ask in from the full
+	// observed 1h spread toward its midpoint, so a maker quotes inside the
+	// market rather than at its edges: bid = mid - k*spread/2, ask = mid +
+	// k*spread/2. 1.0 would quote at the observed high/low; lower values
+	// quote tighter (and thus fill faster, at a smaller margin).
+	SpreadShrinkFactor float64
+
+	// VolumeFraction caps RecommendedFlipSize at this fraction of the
+	// thinner side's 1h volume, so a single flip doesn't try to move more
+	// volume than that side has actually been trading.
+	VolumeFraction float64
+}
+
+// DefaultSpreadConfig returns the default market-making tuning: quote 80%
+// of the way into the observed spread, sized at 50% of the thinner side's
+// hourly volume.
+func DefaultSpreadConfig() SpreadConfig {
+	return SpreadConfig{
+		SpreadShrinkFactor: 0.8,
+		VolumeFraction:     0.5,
+	}
+}
+
+// SetSpreadConfig overrides the market-making quote tuning applySpreadSignal
+// uses, e.g. to quote tighter in a test or for a known-stable item.
+func (a *Analyzer) SetSpreadConfig(cfg SpreadConfig) {
+	a.spreadConfig = cfg
+}
+
+// applySpreadSignal computes item's suggested market-making quotes
+// (SuggestedBidGP/SuggestedAskGP), SpreadStability, and RecommendedFlipSize
+// from its 1h average prices/volume (set by updateItemsWithVolumeData) and
+// stored 1h price history. Items with no 1h volume data, or fewer than two
+// stored 1h ticks, are left unset.
+func (a *Analyzer) applySpreadSignal(item *ItemData) {
+	if item.AvgInstaBuyPrice1h == nil || item.AvgInstaSellPrice1h == nil {
+		return
+	}
+
+	mid := (*item.AvgInstaBuyPrice1h + *item.AvgInstaSellPrice1h) / 2
+	spread := *item.AvgInstaBuyPrice1h - *item.AvgInstaSellPrice1h
+
+	k := a.spreadConfig.SpreadShrinkFactor
+	bid := mid - k*spread/2
+	ask := mid + k*spread/2
+	item.SuggestedBidGP = &bid
+	item.SuggestedAskGP = &ask
+
+	hourly := a.store.GetSeries(item.ItemID, store.Interval1h, time.Time{})
+	if len(hourly) >= 2 {
+		var spreads []float64
+		for _, p := range hourly {
+			spreads = append(spreads, float64(p.InstaBuyPrice-p.InstaSellPrice))
+		}
+		meanSpread := average(spreads)
+		if meanSpread != 0 {
+			stability := 1 - stddev(spreads)/meanSpread
+			item.SpreadStability = &stability
+		}
+	}
+
+	if item.InstaBuyVolume1h != nil && item.InstaSellVolume1h != nil {
+		minVolumeSide := *item.InstaBuyVolume1h
+		if *item.InstaSellVolume1h < minVolumeSide {
+			minVolumeSide = *item.InstaSellVolume1h
+		}
+		size := int(minVolumeSide * a.spreadConfig.VolumeFraction)
+		if item.BuyLimit > 0 && size > item.BuyLimit {
+			size = item.BuyLimit
+		}
+		item.RecommendedFlipSize = &size
+	}
+}
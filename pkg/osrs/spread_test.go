@@ -0,0 +1,97 @@
+package osrs
+
+import (
+	"testing"
+	"time"
+
+	"osrs-flipping/pkg/osrs/store"
+)
+
+func TestApplySpreadSignal(t *testing.T) {
+	s := store.New("", 0, 0)
+	analyzer := &Analyzer{spreadConfig: DefaultSpreadConfig()}
+	analyzer.SetStore(s)
+
+	itemID := 1
+	base := time.Now().Add(-time.Hour)
+	for i, margin := range []int{40, 42, 38, 41} {
+		ts := base.Add(time.Duration(i) * time.Minute)
+		dp := store.DataPoint{Timestamp: ts, InstaBuyPrice: 1000 + margin, InstaSellPrice: 1000}
+		s.Append(itemID, store.Interval1h, dp)
+	}
+
+	avgBuy := 1040.0
+	avgSell := 1000.0
+	buyVol := 100.0
+	sellVol := 60.0
+
+	item := ItemData{
+		ItemID:              itemID,
+		Name:                "Test Item",
+		BuyLimit:            1000,
+		AvgInstaBuyPrice1h:  &avgBuy,
+		AvgInstaSellPrice1h: &avgSell,
+		InstaBuyVolume1h:    &buyVol,
+		InstaSellVolume1h:   &sellVol,
+	}
+	analyzer.applySpreadSignal(&item)
+
+	wantMid := (avgBuy + avgSell) / 2
+	wantSpread := avgBuy - avgSell
+	wantBid := wantMid - 0.8*wantSpread/2
+	wantAsk := wantMid + 0.8*wantSpread/2
+
+	if item.SuggestedBidGP == nil || *item.SuggestedBidGP != wantBid {
+		t.Errorf("expected SuggestedBidGP %v, got %v", wantBid, item.SuggestedBidGP)
+	}
+	if item.SuggestedAskGP == nil || *item.SuggestedAskGP != wantAsk {
+		t.Errorf("expected SuggestedAskGP %v, got %v", wantAsk, item.SuggestedAskGP)
+	}
+	if item.SpreadStability == nil {
+		t.Error("expected SpreadStability to be computed once 1h series ticks exist")
+	}
+	if item.RecommendedFlipSize == nil {
+		t.Fatal("expected RecommendedFlipSize to be computed once 1h volume exists")
+	}
+	// min(buyVol, sellVol)=60, * VolumeFraction(0.5) = 30, below BuyLimit.
+	if *item.RecommendedFlipSize != 30 {
+		t.Errorf("expected RecommendedFlipSize 30, got %d", *item.RecommendedFlipSize)
+	}
+}
+
+func TestApplySpreadSignalNoVolumeDataYet(t *testing.T) {
+	analyzer := &Analyzer{spreadConfig: DefaultSpreadConfig()}
+	analyzer.SetStore(store.New("", 0, 0))
+
+	item := ItemData{ItemID: 1, Name: "Test Item"}
+	analyzer.applySpreadSignal(&item)
+
+	if item.SuggestedBidGP != nil || item.SuggestedAskGP != nil {
+		t.Error("expected no suggested quotes without 1h volume data")
+	}
+}
+
+func TestApplySpreadSignalCapsFlipSizeAtBuyLimit(t *testing.T) {
+	analyzer := &Analyzer{spreadConfig: DefaultSpreadConfig()}
+	analyzer.SetStore(store.New("", 0, 0))
+
+	avgBuy := 1040.0
+	avgSell := 1000.0
+	buyVol := 1000.0
+	sellVol := 1000.0
+
+	item := ItemData{
+		ItemID:              1,
+		Name:                "Test Item",
+		BuyLimit:            10,
+		AvgInstaBuyPrice1h:  &avgBuy,
+		AvgInstaSellPrice1h: &avgSell,
+		InstaBuyVolume1h:    &buyVol,
+		InstaSellVolume1h:   &sellVol,
+	}
+	analyzer.applySpreadSignal(&item)
+
+	if item.RecommendedFlipSize == nil || *item.RecommendedFlipSize != 10 {
+		t.Errorf("expected RecommendedFlipSize capped at BuyLimit (10), got %v", item.RecommendedFlipSize)
+	}
+}
@@ -0,0 +1,373 @@
+// Package store provides a rolling, on-disk timeseries cache for raw price
+// and volume ticks, modeled on a serial market-data store: one ring buffer
+// per (item, interval) pair, capped by count and age, so the Analyzer can
+// fetch incremental deltas instead of re-downloading full history on every
+// run.
+package store
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Interval identifies which OSRS wiki bucket granularity a DataPoint was
+// sourced from.
+type Interval string
+
+const (
+	Interval5m  Interval = "5m"
+	Interval1h  Interval = "1h"
+	Interval24h Interval = "24h"
+)
+
+// DataPoint is a single raw price/volume tick for one item at one interval.
+type DataPoint struct {
+	Timestamp       time.Time
+	InstaBuyPrice   int
+	InstaSellPrice  int
+	InstaBuyVolume  int
+	InstaSellVolume int
+}
+
+const (
+	defaultMaxPoints = 500
+	defaultMaxAge    = 30 * 24 * time.Hour
+)
+
+// ring is a fixed-capacity, timestamp-ordered buffer of DataPoints for a
+// single (item, interval) pair.
+type ring struct {
+	points []DataPoint
+	maxLen int
+	maxAge time.Duration
+}
+
+func newRing(maxLen int, maxAge time.Duration) *ring {
+	return &ring{maxLen: maxLen, maxAge: maxAge}
+}
+
+// append adds dp if it is newer than the newest stored point, then evicts
+// anything past the count or age cap.
+func (r *ring) append(dp DataPoint) {
+	if len(r.points) > 0 && !dp.Timestamp.After(r.points[len(r.points)-1].Timestamp) {
+		return
+	}
+	r.points = append(r.points, dp)
+	r.evict()
+}
+
+func (r *ring) evict() {
+	if r.maxLen > 0 && len(r.points) > r.maxLen {
+		r.points = r.points[len(r.points)-r.maxLen:]
+	}
+	if r.maxAge > 0 {
+		cutoff := time.Now().Add(-r.maxAge)
+		i := 0
+		for i < len(r.points) && r.points[i].Timestamp.Before(cutoff) {
+			i++
+		}
+		r.points = r.points[i:]
+	}
+}
+
+func (r *ring) since(t time.Time) []DataPoint {
+	var out []DataPoint
+	for _, p := range r.points {
+		if p.Timestamp.After(t) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (r *ring) last() (time.Time, bool) {
+	if len(r.points) == 0 {
+		return time.Time{}, false
+	}
+	return r.points[len(r.points)-1].Timestamp, true
+}
+
+// Store is the rolling per-item timeseries cache. It is safe for concurrent
+// use; callers typically share one Store across the worker pool that feeds
+// LoadVolumeData.
+type Store struct {
+	mu     sync.RWMutex
+	rings  map[int]map[Interval]*ring
+	// fetched is the wall-clock time of the last successful API fetch for
+	// an item/interval (distinct from a DataPoint's own Timestamp), so a
+	// caller can decide a cache hit is fresh enough to skip refetching
+	// entirely rather than just deduping overlap on merge.
+	fetched map[int]map[Interval]time.Time
+	maxLen  int
+	maxAge  time.Duration
+	path    string
+}
+
+// New creates an empty Store backed by path (used by Save/Load). maxLen and
+// maxAge cap each ring; pass 0 for either to use the package defaults.
+func New(path string, maxLen int, maxAge time.Duration) *Store {
+	if maxLen <= 0 {
+		maxLen = defaultMaxPoints
+	}
+	if maxAge <= 0 {
+		maxAge = defaultMaxAge
+	}
+	return &Store{
+		rings:   make(map[int]map[Interval]*ring),
+		fetched: make(map[int]map[Interval]time.Time),
+		maxLen:  maxLen,
+		maxAge:  maxAge,
+		path:    path,
+	}
+}
+
+// Append merges a freshly-fetched DataPoint into the item/interval ring. A
+// nil Store is a no-op, so callers that don't wire one up (zero-value
+// Analyzer in tests, for instance) don't need to nil-check it themselves.
+func (s *Store) Append(itemID int, interval Interval, dp DataPoint) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byInterval, ok := s.rings[itemID]
+	if !ok {
+		byInterval = make(map[Interval]*ring)
+		s.rings[itemID] = byInterval
+	}
+	r, ok := byInterval[interval]
+	if !ok {
+		r = newRing(s.maxLen, s.maxAge)
+		byInterval[interval] = r
+	}
+	r.append(dp)
+}
+
+// LastTimestamp returns the newest stored timestamp for itemID/interval, so
+// callers can request only the delta since the last run.
+func (s *Store) LastTimestamp(itemID int, interval Interval) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byInterval, ok := s.rings[itemID]
+	if !ok {
+		return time.Time{}, false
+	}
+	r, ok := byInterval[interval]
+	if !ok {
+		return time.Time{}, false
+	}
+	return r.last()
+}
+
+// GetSeries returns the stored DataPoints for itemID/interval strictly after
+// since, oldest first, for use by indicator code (ATR, trend classification,
+// etc.).
+func (s *Store) GetSeries(itemID int, interval Interval, since time.Time) []DataPoint {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byInterval, ok := s.rings[itemID]
+	if !ok {
+		return nil
+	}
+	r, ok := byInterval[interval]
+	if !ok {
+		return nil
+	}
+	return r.since(since)
+}
+
+// AllSeries returns every stored DataPoint for every (item, interval) pair,
+// oldest first within each, for callers that need to serialize the whole
+// store rather than one series at a time (see osrs.FileDataSource's
+// snapshot export).
+func (s *Store) AllSeries() map[int]map[Interval][]DataPoint {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := make(map[int]map[Interval][]DataPoint, len(s.rings))
+	for itemID, byInterval := range s.rings {
+		all[itemID] = make(map[Interval][]DataPoint, len(byInterval))
+		for interval, r := range byInterval {
+			all[itemID][interval] = append([]DataPoint(nil), r.points...)
+		}
+	}
+	return all
+}
+
+// MarkFetched records the wall-clock time of a successful API fetch for
+// itemID/interval, independent of the DataPoints that fetch returned -- an
+// empty response (market closed, item untraded) is still "freshly fetched".
+func (s *Store) MarkFetched(itemID int, interval Interval, at time.Time) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byInterval, ok := s.fetched[itemID]
+	if !ok {
+		byInterval = make(map[Interval]time.Time)
+		s.fetched[itemID] = byInterval
+	}
+	byInterval[interval] = at
+}
+
+// LastFetched returns the wall-clock time of the last MarkFetched call for
+// itemID/interval, so a caller can decide a cache entry is fresh enough to
+// skip refetching entirely.
+func (s *Store) LastFetched(itemID int, interval Interval) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byInterval, ok := s.fetched[itemID]
+	if !ok {
+		return time.Time{}, false
+	}
+	at, ok := byInterval[interval]
+	return at, ok
+}
+
+// Stats summarizes a Store's current cache state, for logging/diagnostics.
+type Stats struct {
+	Items       int
+	TotalPoints int
+	OldestPoint time.Time
+	NewestPoint time.Time
+}
+
+// Stats aggregates point counts and the oldest/newest stored timestamps
+// across every item and interval in the store.
+func (s *Store) Stats() Stats {
+	var stats Stats
+	if s == nil {
+		return stats
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats.Items = len(s.rings)
+	for _, byInterval := range s.rings {
+		for _, r := range byInterval {
+			stats.TotalPoints += len(r.points)
+			if len(r.points) == 0 {
+				continue
+			}
+			oldest := r.points[0].Timestamp
+			newest := r.points[len(r.points)-1].Timestamp
+			if stats.OldestPoint.IsZero() || oldest.Before(stats.OldestPoint) {
+				stats.OldestPoint = oldest
+			}
+			if newest.After(stats.NewestPoint) {
+				stats.NewestPoint = newest
+			}
+		}
+	}
+	return stats
+}
+
+// snapshot is the gob-serializable form of a Store, used by Save/Load.
+type snapshot struct {
+	Points  map[int]map[Interval][]DataPoint
+	Fetched map[int]map[Interval]time.Time
+}
+
+// Save persists the store to its configured path as a gob file, so a cold
+// start picks up where the last run left off instead of losing history. A
+// Store created with an empty path is in-memory only and Save is a no-op.
+func (s *Store) Save() error {
+	if s == nil || s.path == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	snap := snapshot{
+		Points:  make(map[int]map[Interval][]DataPoint, len(s.rings)),
+		Fetched: make(map[int]map[Interval]time.Time, len(s.fetched)),
+	}
+	for itemID, byInterval := range s.rings {
+		snap.Points[itemID] = make(map[Interval][]DataPoint, len(byInterval))
+		for interval, r := range byInterval {
+			snap.Points[itemID][interval] = append([]DataPoint(nil), r.points...)
+		}
+	}
+	for itemID, byInterval := range s.fetched {
+		snap.Fetched[itemID] = make(map[Interval]time.Time, len(byInterval))
+		for interval, at := range byInterval {
+			snap.Fetched[itemID][interval] = at
+		}
+	}
+	s.mu.RUnlock()
+
+	f, err := os.Create(s.path)
+	if err != nil {
+		return fmt.Errorf("creating store file: %w", err)
+	}
+	defer f.Close()
+
+	if err := gob.NewEncoder(f).Encode(snap); err != nil {
+		return fmt.Errorf("encoding store: %w", err)
+	}
+	return nil
+}
+
+// Load restores a previously-saved store from its configured path. A
+// missing file is not an error -- it just means this is a cold start.
+func (s *Store) Load() error {
+	if s == nil || s.path == "" {
+		return nil
+	}
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("opening store file: %w", err)
+	}
+	defer f.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return fmt.Errorf("decoding store: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rings = make(map[int]map[Interval]*ring, len(snap.Points))
+	for itemID, byInterval := range snap.Points {
+		s.rings[itemID] = make(map[Interval]*ring, len(byInterval))
+		for interval, points := range byInterval {
+			r := newRing(s.maxLen, s.maxAge)
+			r.points = points
+			r.evict()
+			s.rings[itemID][interval] = r
+		}
+	}
+	s.fetched = make(map[int]map[Interval]time.Time, len(snap.Fetched))
+	for itemID, byInterval := range snap.Fetched {
+		s.fetched[itemID] = make(map[Interval]time.Time, len(byInterval))
+		for interval, at := range byInterval {
+			s.fetched[itemID][interval] = at
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreAppendAndGetSeries(t *testing.T) {
+	s := New("", 10, 0)
+
+	base := time.Now().Add(-time.Hour)
+	s.Append(1, Interval5m, DataPoint{Timestamp: base, InstaBuyPrice: 100})
+	s.Append(1, Interval5m, DataPoint{Timestamp: base.Add(5 * time.Minute), InstaBuyPrice: 105})
+	s.Append(1, Interval5m, DataPoint{Timestamp: base.Add(10 * time.Minute), InstaBuyPrice: 110})
+
+	series := s.GetSeries(1, Interval5m, base)
+	if len(series) != 2 {
+		t.Fatalf("expected 2 points after base, got %d", len(series))
+	}
+	if series[0].InstaBuyPrice != 105 || series[1].InstaBuyPrice != 110 {
+		t.Errorf("unexpected series contents: %+v", series)
+	}
+}
+
+func TestStoreAppendDropsStaleTicks(t *testing.T) {
+	s := New("", 10, 0)
+
+	now := time.Now()
+	s.Append(1, Interval5m, DataPoint{Timestamp: now, InstaBuyPrice: 100})
+	s.Append(1, Interval5m, DataPoint{Timestamp: now.Add(-time.Minute), InstaBuyPrice: 200})
+
+	last, ok := s.LastTimestamp(1, Interval5m)
+	if !ok {
+		t.Fatal("expected a stored timestamp")
+	}
+	if !last.Equal(now) {
+		t.Errorf("expected last timestamp to stay at %v, got %v", now, last)
+	}
+}
+
+func TestStoreEvictsOverCountCap(t *testing.T) {
+	s := New("", 3, 0)
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 5; i++ {
+		s.Append(1, Interval5m, DataPoint{Timestamp: base.Add(time.Duration(i) * time.Minute), InstaBuyPrice: 100 + i})
+	}
+
+	series := s.GetSeries(1, Interval5m, time.Time{})
+	if len(series) != 3 {
+		t.Fatalf("expected ring capped at 3 points, got %d", len(series))
+	}
+	if series[0].InstaBuyPrice != 102 {
+		t.Errorf("expected oldest surviving point to be 102, got %d", series[0].InstaBuyPrice)
+	}
+}
+
+func TestStoreSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "market_store.gob")
+
+	s := New(path, 10, 0)
+	ts := time.Now().Truncate(time.Second)
+	s.Append(42, Interval24h, DataPoint{Timestamp: ts, InstaBuyPrice: 321, InstaSellPrice: 300})
+
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded := New(path, 10, 0)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	series := loaded.GetSeries(42, Interval24h, ts.Add(-time.Second))
+	if len(series) != 1 || series[0].InstaBuyPrice != 321 {
+		t.Errorf("expected round-tripped point, got %+v", series)
+	}
+}
+
+func TestStoreLoadMissingFileIsNotError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "does-not-exist.gob")
+	s := New(path, 10, 0)
+	if err := s.Load(); err != nil {
+		t.Errorf("Load() on missing file should be a no-op, got error: %v", err)
+	}
+}
+
+func TestStoreMarkAndLastFetched(t *testing.T) {
+	s := New("", 10, 0)
+
+	if _, ok := s.LastFetched(1, Interval5m); ok {
+		t.Fatal("expected no fetch recorded yet")
+	}
+
+	at := time.Now()
+	s.MarkFetched(1, Interval5m, at)
+
+	got, ok := s.LastFetched(1, Interval5m)
+	if !ok {
+		t.Fatal("expected a fetch time after MarkFetched")
+	}
+	if !got.Equal(at) {
+		t.Errorf("LastFetched = %v, want %v", got, at)
+	}
+
+	if _, ok := s.LastFetched(1, Interval24h); ok {
+		t.Error("expected a separate interval to have no fetch recorded")
+	}
+}
+
+func TestStoreFetchedSurvivesSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "market_store.gob")
+
+	s := New(path, 10, 0)
+	at := time.Now().Truncate(time.Second)
+	s.MarkFetched(7, Interval5m, at)
+
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded := New(path, 10, 0)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	got, ok := loaded.LastFetched(7, Interval5m)
+	if !ok {
+		t.Fatal("expected round-tripped fetch time")
+	}
+	if !got.Equal(at) {
+		t.Errorf("LastFetched after round trip = %v, want %v", got, at)
+	}
+}
+
+func TestStoreStats(t *testing.T) {
+	s := New("", 10, 0)
+
+	if stats := s.Stats(); stats.Items != 0 || stats.TotalPoints != 0 {
+		t.Fatalf("expected empty stats on a new store, got %+v", stats)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	s.Append(1, Interval5m, DataPoint{Timestamp: base, InstaBuyPrice: 100})
+	s.Append(1, Interval5m, DataPoint{Timestamp: base.Add(5 * time.Minute), InstaBuyPrice: 105})
+	s.Append(2, Interval24h, DataPoint{Timestamp: base.Add(10 * time.Minute), InstaBuyPrice: 200})
+
+	stats := s.Stats()
+	if stats.Items != 2 {
+		t.Errorf("stats.Items = %d, want 2", stats.Items)
+	}
+	if stats.TotalPoints != 3 {
+		t.Errorf("stats.TotalPoints = %d, want 3", stats.TotalPoints)
+	}
+	if !stats.OldestPoint.Equal(base) {
+		t.Errorf("stats.OldestPoint = %v, want %v", stats.OldestPoint, base)
+	}
+	if !stats.NewestPoint.Equal(base.Add(10 * time.Minute)) {
+		t.Errorf("stats.NewestPoint = %v, want %v", stats.NewestPoint, base.Add(10*time.Minute))
+	}
+}
@@ -0,0 +1,217 @@
+package osrs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ItemUpdate reports a single item's price (and, when available, volume)
+// change between two consecutive PriceSubscriber polls.
+type ItemUpdate struct {
+	ItemID      int
+	OldPrice    int
+	NewPrice    int
+	VolumeDelta int
+	Timestamp   time.Time
+}
+
+// SubscribeOptions filters which ItemUpdates a subscriber receives, so a
+// TUI/web client watching a handful of items doesn't have to wade through
+// the full ~4k item firehose on every poll.
+type SubscribeOptions struct {
+	// ItemIDs restricts updates to these items. Empty means all items.
+	ItemIDs []int
+
+	// MinMargin drops updates for items whose current MarginGP is below
+	// this threshold. Zero means no filter.
+	MinMargin int
+
+	// MinVolume drops updates for items whose InstaBuyVolume20m is below
+	// this threshold. Zero means no filter.
+	MinVolume int
+}
+
+func (o SubscribeOptions) matches(item ItemData, update ItemUpdate) bool {
+	if len(o.ItemIDs) > 0 {
+		found := false
+		for _, id := range o.ItemIDs {
+			if id == item.ItemID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if o.MinMargin > 0 && item.MarginGP < o.MinMargin {
+		return false
+	}
+	if o.MinVolume > 0 {
+		if item.InstaBuyVolume20m == nil || *item.InstaBuyVolume20m < float64(o.MinVolume) {
+			return false
+		}
+	}
+	return true
+}
+
+// subscriber is one Subscribe caller's filter and delivery channel.
+type subscriber struct {
+	opts SubscribeOptions
+	ch   chan ItemUpdate
+}
+
+// PriceSubscriber turns a DataSource's poll-based LoadPrices into a push
+// feed of ItemUpdate events, fanning a single shared poll loop out to every
+// subscriber rather than having each Subscribe call poll independently.
+// APIDataSource and HybridDataSource have no native push mechanism, so this
+// is the general-purpose path; a DataSource backed by Postgres LISTEN/NOTIFY
+// can still use it, trading a little latency for not needing a dedicated
+// notifier.
+type PriceSubscriber struct {
+	source   DataSource
+	interval time.Duration
+
+	mu      sync.Mutex
+	last    map[int]ItemData
+	subs    map[int]*subscriber
+	nextID  int
+	running bool
+}
+
+// NewPriceSubscriber wraps source, polling it every interval once the first
+// Subscribe call arrives. The poll loop stops again once the last
+// subscriber's context is canceled.
+func NewPriceSubscriber(source DataSource, interval time.Duration) *PriceSubscriber {
+	return &PriceSubscriber{
+		source:   source,
+		interval: interval,
+		last:     make(map[int]ItemData),
+		subs:     make(map[int]*subscriber),
+	}
+}
+
+// Subscribe returns a channel of ItemUpdates matching opts. The channel is
+// closed when ctx is canceled. The returned channel is buffered; a slow
+// subscriber misses updates rather than blocking the shared poll loop.
+func (p *PriceSubscriber) Subscribe(ctx context.Context, opts SubscribeOptions) (<-chan ItemUpdate, error) {
+	p.mu.Lock()
+	id := p.nextID
+	p.nextID++
+	sub := &subscriber{opts: opts, ch: make(chan ItemUpdate, 64)}
+	p.subs[id] = sub
+	startLoop := !p.running
+	p.running = true
+	p.mu.Unlock()
+
+	if startLoop {
+		go p.run(ctx)
+	}
+
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		delete(p.subs, id)
+		empty := len(p.subs) == 0
+		if empty {
+			p.running = false
+		}
+		p.mu.Unlock()
+		close(sub.ch)
+	}()
+
+	return sub.ch, nil
+}
+
+// run holds the single shared poll loop until ctx is canceled or the last
+// subscriber unsubscribes.
+func (p *PriceSubscriber) run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.poll(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			p.mu.Lock()
+			stillRunning := p.running
+			p.mu.Unlock()
+			if !stillRunning {
+				return
+			}
+			p.poll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll loads the current snapshot, diffs it against the last one, and
+// publishes an ItemUpdate to every matching subscriber for each item whose
+// price or volume actually changed.
+func (p *PriceSubscriber) poll(ctx context.Context) {
+	items, err := p.source.LoadPrices(ctx)
+	if err != nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, item := range items {
+		prev, seen := p.last[item.ItemID]
+		p.last[item.ItemID] = item
+		if !seen {
+			continue
+		}
+
+		update, changed := diffItem(prev, item)
+		if !changed {
+			continue
+		}
+		for _, sub := range p.subs {
+			if !sub.opts.matches(item, update) {
+				continue
+			}
+			select {
+			case sub.ch <- update:
+			default:
+			}
+		}
+	}
+}
+
+// diffItem reports the ItemUpdate between prev and current, and whether
+// anything actually changed. Missing (*int) prices are treated as zero.
+func diffItem(prev, current ItemData) (ItemUpdate, bool) {
+	oldPrice := intOrZero(prev.InstaBuyPrice)
+	newPrice := intOrZero(current.InstaBuyPrice)
+	volumeDelta := int(float64OrZero(current.InstaBuyVolume20m) - float64OrZero(prev.InstaBuyVolume20m))
+
+	if oldPrice == newPrice && volumeDelta == 0 {
+		return ItemUpdate{}, false
+	}
+
+	return ItemUpdate{
+		ItemID:      current.ItemID,
+		OldPrice:    oldPrice,
+		NewPrice:    newPrice,
+		VolumeDelta: volumeDelta,
+		Timestamp:   time.Now(),
+	}, true
+}
+
+func intOrZero(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func float64OrZero(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
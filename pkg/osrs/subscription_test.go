@@ -0,0 +1,113 @@
+package osrs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sequenceDataSource returns its next()ed snapshot on each LoadPrices call,
+// repeating the last one once exhausted, so tests can drive PriceSubscriber
+// through a scripted sequence of price changes.
+type sequenceDataSource struct {
+	mu        sync.Mutex
+	snapshots [][]ItemData
+	next      int
+}
+
+func (s *sequenceDataSource) Name() string { return "sequence" }
+func (s *sequenceDataSource) IsFresh(ctx context.Context) (bool, error) { return true, nil }
+func (s *sequenceDataSource) LoadVolumeData(ctx context.Context, items []ItemData, maxItems int) error {
+	return nil
+}
+
+func (s *sequenceDataSource) LoadPrices(ctx context.Context) ([]ItemData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx := s.next
+	if idx >= len(s.snapshots) {
+		idx = len(s.snapshots) - 1
+	} else {
+		s.next++
+	}
+	return s.snapshots[idx], nil
+}
+
+func TestPriceSubscriberEmitsUpdateOnPriceChange(t *testing.T) {
+	src := &sequenceDataSource{snapshots: [][]ItemData{
+		{{ItemID: 1, Name: "Item", InstaBuyPrice: intPtr(100), MarginGP: 10}},
+		{{ItemID: 1, Name: "Item", InstaBuyPrice: intPtr(120), MarginGP: 10}},
+	}}
+	sub := NewPriceSubscriber(src, 5*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := sub.Subscribe(ctx, SubscribeOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case u := <-updates:
+		if u.OldPrice != 100 || u.NewPrice != 120 {
+			t.Errorf("expected 100 -> 120, got %+v", u)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an ItemUpdate after the price changed")
+	}
+}
+
+func TestPriceSubscriberFiltersByMinMargin(t *testing.T) {
+	src := &sequenceDataSource{snapshots: [][]ItemData{
+		{{ItemID: 1, Name: "Item", InstaBuyPrice: intPtr(100), MarginGP: 5}},
+		{{ItemID: 1, Name: "Item", InstaBuyPrice: intPtr(120), MarginGP: 5}},
+	}}
+	sub := NewPriceSubscriber(src, 5*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := sub.Subscribe(ctx, SubscribeOptions{MinMargin: 50})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case u := <-updates:
+		t.Fatalf("expected the low-margin update to be filtered out, got %+v", u)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPriceSubscriberFiltersByItemID(t *testing.T) {
+	src := &sequenceDataSource{snapshots: [][]ItemData{
+		{
+			{ItemID: 1, Name: "A", InstaBuyPrice: intPtr(100)},
+			{ItemID: 2, Name: "B", InstaBuyPrice: intPtr(200)},
+		},
+		{
+			{ItemID: 1, Name: "A", InstaBuyPrice: intPtr(110)},
+			{ItemID: 2, Name: "B", InstaBuyPrice: intPtr(250)},
+		},
+	}}
+	sub := NewPriceSubscriber(src, 5*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := sub.Subscribe(ctx, SubscribeOptions{ItemIDs: []int{2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case u := <-updates:
+		if u.ItemID != 2 {
+			t.Errorf("expected only item 2's update, got %+v", u)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an ItemUpdate for item 2")
+	}
+}
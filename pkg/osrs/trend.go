@@ -0,0 +1,220 @@
+package osrs
+
+import "math"
+
+// ATR computes the Average True Range over a series of high/low/close
+// prices using Wilder's smoothing, a more volatility-aware alternative to
+// the flat %-change threshold used by calculateTrend.
+func ATR(high, low, close []float64, period int) float64 {
+	if len(high) < 2 || len(high) != len(low) || len(high) != len(close) || period <= 0 {
+		return 0
+	}
+
+	var trueRanges []float64
+	for i := 1; i < len(high); i++ {
+		hl := high[i] - low[i]
+		hc := math.Abs(high[i] - close[i-1])
+		lc := math.Abs(low[i] - close[i-1])
+		tr := math.Max(hl, math.Max(hc, lc))
+		trueRanges = append(trueRanges, tr)
+	}
+
+	if len(trueRanges) == 0 {
+		return 0
+	}
+	if len(trueRanges) < period {
+		period = len(trueRanges)
+	}
+
+	// Seed with a simple average of the first `period` true ranges, then
+	// apply Wilder's smoothing for the rest.
+	atr := average(trueRanges[:period])
+	for i := period; i < len(trueRanges); i++ {
+		atr = (atr*float64(period-1) + trueRanges[i]) / float64(period)
+	}
+	return atr
+}
+
+// swingPoint is a local high or low in a price series, the building block
+// for a simplified Elliott Wave impulse/correction count.
+type swingPoint struct {
+	index  int
+	value  float64
+	isHigh bool
+}
+
+// findSwingPoints returns local extrema in prices using a symmetric window
+// of `lookaround` points on each side.
+func findSwingPoints(prices []float64, lookaround int) []swingPoint {
+	var swings []swingPoint
+	for i := lookaround; i < len(prices)-lookaround; i++ {
+		isHigh, isLow := true, true
+		for j := i - lookaround; j <= i+lookaround; j++ {
+			if j == i {
+				continue
+			}
+			if prices[j] >= prices[i] {
+				isHigh = false
+			}
+			if prices[j] <= prices[i] {
+				isLow = false
+			}
+		}
+		if isHigh {
+			swings = append(swings, swingPoint{index: i, value: prices[i], isHigh: true})
+		} else if isLow {
+			swings = append(swings, swingPoint{index: i, value: prices[i], isHigh: false})
+		}
+	}
+	return swings
+}
+
+// WaveTrend is a simplified Elliott Wave-style classification: it counts
+// alternating swing highs/lows to approximate an "impulse" (5-wave, trend
+// continuation likely) vs "correction" (3-wave, reversal/consolidation
+// likely) structure. This is a heuristic, not a full Elliott Wave engine --
+// it's meant to augment, not replace, the ATR-based volatility read.
+type WaveTrend string
+
+const (
+	WaveImpulseUp     WaveTrend = "impulse_up"
+	WaveImpulseDown   WaveTrend = "impulse_down"
+	WaveCorrection    WaveTrend = "correction"
+	WaveIndeterminate WaveTrend = "indeterminate"
+)
+
+// ClassifyWave approximates an Elliott Wave structure from a price series.
+// A run of 5+ alternating swings trending in one direction is treated as an
+// impulse; fewer or non-monotonic swings are treated as a correction.
+func ClassifyWave(prices []float64) WaveTrend {
+	if len(prices) < 9 {
+		return WaveIndeterminate
+	}
+
+	swings := findSwingPoints(prices, 2)
+	if len(swings) < 3 {
+		return WaveIndeterminate
+	}
+
+	up, down := 0, 0
+	for i := 1; i < len(swings); i++ {
+		if swings[i].value > swings[i-1].value {
+			up++
+		} else if swings[i].value < swings[i-1].value {
+			down++
+		}
+	}
+
+	switch {
+	case len(swings) >= 5 && up >= len(swings)-2:
+		return WaveImpulseUp
+	case len(swings) >= 5 && down >= len(swings)-2:
+		return WaveImpulseDown
+	default:
+		return WaveCorrection
+	}
+}
+
+// HeikinAshiCandle is one smoothed Heikin-Ashi candle derived from a raw
+// open/high/low/close series, averaging out the single-print noise that
+// makes calculateTrend's raw EMA crossover flip label on one volatile tick
+// (see IndicatorConfig.UseHeikinAshi).
+type HeikinAshiCandle struct {
+	Open, High, Low, Close float64
+}
+
+// HeikinAshi transforms raw open/high/low/close series into Heikin-Ashi
+// candles: HAClose = (O+H+L+C)/4, HAOpen = (prevHAOpen+prevHAClose)/2
+// (seeded with the first raw open), and HAHigh/HALow extend the raw
+// high/low to also cover both HA open and close.
+func HeikinAshi(open, high, low, close []float64) []HeikinAshiCandle {
+	n := len(open)
+	if n == 0 || len(high) != n || len(low) != n || len(close) != n {
+		return nil
+	}
+
+	candles := make([]HeikinAshiCandle, n)
+	prevOpen, prevClose := open[0], open[0]
+	for i := 0; i < n; i++ {
+		haClose := (open[i] + high[i] + low[i] + close[i]) / 4
+		haOpen := open[0]
+		if i > 0 {
+			haOpen = (prevOpen + prevClose) / 2
+		}
+		haHigh := math.Max(high[i], math.Max(haOpen, haClose))
+		haLow := math.Min(low[i], math.Min(haOpen, haClose))
+
+		candles[i] = HeikinAshiCandle{Open: haOpen, High: haHigh, Low: haLow, Close: haClose}
+		prevOpen, prevClose = haOpen, haClose
+	}
+	return candles
+}
+
+// HAStreak counts the consecutive same-direction Heikin-Ashi candles
+// (HAClose above or below HAOpen) ending the series, a confidence read on
+// how long the current HA-smoothed trend has held.
+func HAStreak(candles []HeikinAshiCandle) int {
+	if len(candles) == 0 {
+		return 0
+	}
+
+	sign := func(c HeikinAshiCandle) int {
+		switch {
+		case c.Close > c.Open:
+			return 1
+		case c.Close < c.Open:
+			return -1
+		default:
+			return 0
+		}
+	}
+
+	last := sign(candles[len(candles)-1])
+	if last == 0 {
+		return 0
+	}
+	streak := 0
+	for i := len(candles) - 1; i >= 0 && sign(candles[i]) == last; i-- {
+		streak++
+	}
+	return streak
+}
+
+// TrendSignal combines ATR-based volatility with a simplified Elliott Wave
+// read for a richer alternative to calculateTrend's flat %-threshold.
+type TrendSignal struct {
+	ATR   float64
+	Wave  WaveTrend
+	Label string
+}
+
+// ClassifyTrendATR produces a TrendSignal from high/low/close price series.
+// It labels moves as "volatile breakout" when ATR is large relative to the
+// recent average price and the wave read is an impulse, "trending" for a
+// clear impulse with moderate volatility, and "choppy" otherwise.
+func ClassifyTrendATR(high, low, close []float64) TrendSignal {
+	atr := ATR(high, low, close, 14)
+	wave := ClassifyWave(close)
+
+	avgClose := average(close)
+	volatilityRatio := 0.0
+	if avgClose > 0 {
+		volatilityRatio = atr / avgClose
+	}
+
+	var label string
+	switch {
+	case wave == WaveIndeterminate:
+		label = "flat"
+	case volatilityRatio >= 0.05 && (wave == WaveImpulseUp || wave == WaveImpulseDown):
+		label = "volatile breakout"
+	case wave == WaveImpulseUp:
+		label = "trending up"
+	case wave == WaveImpulseDown:
+		label = "trending down"
+	default:
+		label = "choppy"
+	}
+
+	return TrendSignal{ATR: atr, Wave: wave, Label: label}
+}
@@ -0,0 +1,167 @@
+package osrs
+
+import "testing"
+
+func TestATR(t *testing.T) {
+	tests := []struct {
+		name   string
+		high   []float64
+		low    []float64
+		close  []float64
+		period int
+		want   float64 // approximate, checked with tolerance
+	}{
+		{
+			name:   "empty data",
+			high:   []float64{},
+			low:    []float64{},
+			close:  []float64{},
+			period: 14,
+			want:   0,
+		},
+		{
+			name:   "mismatched lengths",
+			high:   []float64{1, 2, 3},
+			low:    []float64{1, 2},
+			close:  []float64{1, 2, 3},
+			period: 14,
+			want:   0,
+		},
+		{
+			name:   "zero period",
+			high:   []float64{1, 2, 3},
+			low:    []float64{1, 2, 3},
+			close:  []float64{1, 2, 3},
+			period: 0,
+			want:   0,
+		},
+		{
+			name:   "constant range",
+			high:   []float64{110, 110, 110, 110, 110},
+			low:    []float64{100, 100, 100, 100, 100},
+			close:  []float64{105, 105, 105, 105, 105},
+			period: 3,
+			want:   10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ATR(tt.high, tt.low, tt.close, tt.period)
+			if diff := got - tt.want; diff > 0.01 || diff < -0.01 {
+				t.Errorf("ATR(%v, %v, %v, %d) = %v, want ~%v", tt.high, tt.low, tt.close, tt.period, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindSwingPoints(t *testing.T) {
+	// A clean zigzag with a trough at index 2 and a peak at index 4,
+	// spaced far enough from the slice edges for lookaround=1 to evaluate.
+	prices := []float64{100, 90, 80, 95, 110, 95, 80}
+
+	swings := findSwingPoints(prices, 1)
+
+	if len(swings) != 2 {
+		t.Fatalf("expected 2 swings, got %d: %+v", len(swings), swings)
+	}
+	if swings[0].index != 2 || !(!swings[0].isHigh) {
+		t.Errorf("expected swing low at index 2, got %+v", swings[0])
+	}
+	if swings[1].index != 4 || !swings[1].isHigh {
+		t.Errorf("expected swing high at index 4, got %+v", swings[1])
+	}
+}
+
+func TestClassifyWave(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []float64
+		want   WaveTrend
+	}{
+		{
+			name:   "too few points",
+			prices: []float64{1, 2, 3},
+			want:   WaveIndeterminate,
+		},
+		{
+			name:   "monotonic series has no pivots",
+			prices: []float64{100, 101, 102, 103, 104, 105, 106, 107, 108, 109},
+			want:   WaveIndeterminate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyWave(tt.prices)
+			if got != tt.want {
+				t.Errorf("ClassifyWave(%v) = %v, want %v", tt.prices, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyTrendATR(t *testing.T) {
+	high := []float64{100, 101, 99, 103, 102, 106, 105, 110, 108, 115}
+	low := []float64{95, 96, 94, 98, 97, 101, 100, 105, 103, 110}
+	close := high
+
+	signal := ClassifyTrendATR(high, low, close)
+	if signal.Label == "" {
+		t.Error("expected a non-empty trend label")
+	}
+	if signal.ATR <= 0 {
+		t.Errorf("expected positive ATR for varying high/low, got %v", signal.ATR)
+	}
+}
+
+func TestHeikinAshi_SeedsFirstCandleFromRawOpen(t *testing.T) {
+	open := []float64{100}
+	high := []float64{110}
+	low := []float64{90}
+	close := []float64{105}
+
+	candles := HeikinAshi(open, high, low, close)
+	if len(candles) != 1 {
+		t.Fatalf("expected 1 candle, got %d", len(candles))
+	}
+	if candles[0].Open != 100 {
+		t.Errorf("first candle's HAOpen = %v, want the raw open 100", candles[0].Open)
+	}
+	wantClose := (100.0 + 110.0 + 90.0 + 105.0) / 4
+	if candles[0].Close != wantClose {
+		t.Errorf("HAClose = %v, want %v", candles[0].Close, wantClose)
+	}
+}
+
+func TestHeikinAshi_SmoothsOutASingleVolatileSpike(t *testing.T) {
+	// A steady uptrend with one huge spike on the last bar: a raw close
+	// would jump wildly, but HAOpen averages in the prior candle so the
+	// spike doesn't single-handedly flip the candle's direction sign.
+	open := []float64{100, 102, 104, 106}
+	high := []float64{101, 103, 105, 200}
+	low := []float64{99, 101, 103, 106}
+	close := []float64{100.5, 102.5, 104.5, 199}
+
+	candles := HeikinAshi(open, high, low, close)
+	last := candles[len(candles)-1]
+	if last.Open <= 0 || last.Open >= last.Close {
+		t.Errorf("expected the spike candle to still close above its smoothed HAOpen, got open=%v close=%v", last.Open, last.Close)
+	}
+}
+
+func TestHAStreak(t *testing.T) {
+	up := HeikinAshiCandle{Open: 100, Close: 110}
+	down := HeikinAshiCandle{Open: 100, Close: 90}
+	flat := HeikinAshiCandle{Open: 100, Close: 100}
+
+	if got := HAStreak([]HeikinAshiCandle{down, up, up, up}); got != 3 {
+		t.Errorf("HAStreak = %d, want 3 consecutive up candles", got)
+	}
+	if got := HAStreak([]HeikinAshiCandle{up, down}); got != 1 {
+		t.Errorf("HAStreak = %d, want 1 (the single down candle)", got)
+	}
+	if got := HAStreak([]HeikinAshiCandle{up, flat}); got != 0 {
+		t.Errorf("HAStreak = %d, want 0 for a flat final candle", got)
+	}
+}
@@ -79,6 +79,24 @@ type ItemData struct {
 	AvgInstaSellPrice24h *float64 `json:"avg_insta_sell_price_24h,omitempty"`
 	AvgMarginGP24h       *float64 `json:"avg_margin_gp_24h,omitempty"`
 
+	// VWAP fields mirroring VolumeMetrics.VWAPInstaBuy*/VWAPInstaSell*/
+	// VWAPSpread24h. PriceVsVWAPDeviation24h is the live MarginGP's
+	// fractional deviation from VWAPSpread24h, (MarginGP - VWAPSpread24h) /
+	// VWAPSpread24h. VWAPFlipSignal flags items whose live margin exceeds
+	// VWAPSpread24h by more than IndicatorConfig.VWAPSignalSigma standard
+	// deviations of the 24h price distributions (see
+	// Analyzer.updateItemsWithVolumeData) -- a sign the live spread is an
+	// outlier rather than the item's normal fair-value spread.
+	VWAPInstaBuy1h          *float64 `json:"vwap_insta_buy_1h,omitempty"`
+	VWAPInstaSell1h         *float64 `json:"vwap_insta_sell_1h,omitempty"`
+	VWAPInstaBuy4h          *float64 `json:"vwap_insta_buy_4h,omitempty"`
+	VWAPInstaSell4h         *float64 `json:"vwap_insta_sell_4h,omitempty"`
+	VWAPInstaBuy24h         *float64 `json:"vwap_insta_buy_24h,omitempty"`
+	VWAPInstaSell24h        *float64 `json:"vwap_insta_sell_24h,omitempty"`
+	VWAPSpread24h           *float64 `json:"vwap_spread_24h,omitempty"`
+	PriceVsVWAPDeviation24h *float64 `json:"price_vs_vwap_deviation_24h,omitempty"`
+	VWAPFlipSignal          bool     `json:"vwap_flip_signal"`
+
 	// Trend analysis
 	InstaSellPriceTrend1h  *string `json:"insta_sell_price_trend_1h,omitempty"`
 	InstaBuyPriceTrend1h   *string `json:"insta_buy_price_trend_1h,omitempty"`
@@ -88,6 +106,136 @@ type ItemData struct {
 	InstaBuyPriceTrend1w   *string `json:"insta_buy_price_trend_1w,omitempty"`
 	InstaSellPriceTrend1m  *string `json:"insta_sell_price_trend_1m,omitempty"`
 	InstaBuyPriceTrend1m   *string `json:"insta_buy_price_trend_1m,omitempty"`
+
+	// TrendStrength is the EMA crossover strength behind InstaBuyPriceTrend1h
+	// (see VolumeMetrics.TrendStrength), for sorting/filtering by trend
+	// magnitude rather than just its "increasing"/"decreasing"/"flat" label.
+	TrendStrength *float64 `json:"trend_strength,omitempty"`
+
+	// HAStreak1h is the number of consecutive same-direction Heikin-Ashi
+	// candles behind InstaBuyPriceTrend1h when IndicatorConfig.UseHeikinAshi
+	// is set (see VolumeMetrics.HAStreak1h), nil otherwise.
+	HAStreak1h *int `json:"ha_streak_1h,omitempty"`
+
+	// ATR/Elliott-Wave trend signal over the 24h window (see ClassifyTrendATR).
+	ATR24h       *float64 `json:"atr_24h,omitempty"`
+	WaveTrend24h *string  `json:"wave_trend_24h,omitempty"`
+
+	// Streaming-indicator volatility/stability columns (see
+	// pkg/osrs/indicator and Analyzer.applyVolatilitySignal), computed from
+	// the rolling price store rather than re-parsed per-request like
+	// ATR24h/WaveTrend24h above. ATR1h has no 1h-window counterpart among
+	// the original trend fields, so it's new rather than a replacement.
+	ATR1h           *float64 `json:"atr_1h,omitempty"`
+	PriceEMA24h     *float64 `json:"price_ema_24h,omitempty"`
+	MarginStddev24h *float64 `json:"margin_stddev_24h,omitempty"`
+
+	// ATR1hPct/ATR24hPct express ATR1h/ATR24h as a fraction of InstaSellPrice
+	// instead of raw GP, so volatility is comparable across items of very
+	// different price scales (see Analyzer.applyVolatilitySignal).
+	ATR1hPct  *float64 `json:"atr_1h_pct,omitempty"`
+	ATR24hPct *float64 `json:"atr_24h_pct,omitempty"`
+
+	// EWO5m is the current Elliott-Wave Oscillator (fast-SMA minus slow-SMA
+	// of mid price) over the 5m bucket history, and EWOSignalFlips1h counts
+	// how many times it changed sign in the last hour's worth of 5m buckets
+	// (see indicator.EWO). A positive, growing EWO5m alongside a low
+	// ATR1hPct is a stronger "trending, not choppy" read than the flat
+	// increasing/decreasing/flat trend strings alone can express.
+	EWO5m            *float64 `json:"ewo_5m,omitempty"`
+	EWOSignalFlips1h *int     `json:"ewo_signal_flips_1h,omitempty"`
+
+	// AvgTrueRange1h/24h mirror VolumeMetrics.AvgTrueRange1h/24h.
+	// RiskAdjustedEfficiency is FlipEfficiency's companion sized for risk:
+	// MarginGP / AvgTrueRange1h, so two items with identical margins but
+	// different volatility rank differently. Nil until volume data (and
+	// thus AvgTrueRange1h) has loaded.
+	AvgTrueRange1h         *float64 `json:"avg_true_range_1h,omitempty"`
+	AvgTrueRange24h        *float64 `json:"avg_true_range_24h,omitempty"`
+	RiskAdjustedEfficiency *float64 `json:"risk_adjusted_efficiency,omitempty"`
+
+	// Market-making quote suggestion (see Analyzer.applySpreadSignal and
+	// SpreadConfig). SuggestedBidGP/SuggestedAskGP sit inside the observed
+	// 1h insta-buy/insta-sell spread rather than at its edges, SpreadStability
+	// scores how steady that spread has been over the 1h window, and
+	// RecommendedFlipSize caps flip quantity by whichever side of the 1h
+	// volume is thinner, so illiquid sides don't get overallocated. Nil
+	// until both volume data and 1h price history (store.Interval1h) are
+	// available.
+	SuggestedBidGP      *float64 `json:"suggested_bid_gp,omitempty"`
+	SuggestedAskGP      *float64 `json:"suggested_ask_gp,omitempty"`
+	SpreadStability     *float64 `json:"spread_stability,omitempty"`
+	RecommendedFlipSize *int     `json:"recommended_flip_size,omitempty"`
+
+	// Distribution stats mirroring VolumeMetrics.InstaBuyPriceStats1h et al.
+	// Nil until volume data has loaded.
+	InstaBuyPriceStats1h   *DistributionStats `json:"insta_buy_price_stats_1h,omitempty"`
+	InstaSellPriceStats1h  *DistributionStats `json:"insta_sell_price_stats_1h,omitempty"`
+	InstaBuyVolumeStats1h  *DistributionStats `json:"insta_buy_volume_stats_1h,omitempty"`
+	InstaSellVolumeStats1h *DistributionStats `json:"insta_sell_volume_stats_1h,omitempty"`
+
+	InstaBuyPriceStats24h   *DistributionStats `json:"insta_buy_price_stats_24h,omitempty"`
+	InstaSellPriceStats24h  *DistributionStats `json:"insta_sell_price_stats_24h,omitempty"`
+	InstaBuyVolumeStats24h  *DistributionStats `json:"insta_buy_volume_stats_24h,omitempty"`
+	InstaSellVolumeStats24h *DistributionStats `json:"insta_sell_volume_stats_24h,omitempty"`
+
+	// Fisher Transform reversal signal mirroring
+	// VolumeMetrics.FisherTransform1h/InstaBuyReversalSignal.
+	FisherTransform1h      *float64 `json:"fisher_transform_1h,omitempty"`
+	InstaBuyReversalSignal *string  `json:"insta_buy_reversal_signal,omitempty"`
+
+	// Drift momentum scores mirroring VolumeMetrics.Drift20m/1h/24h/1w/1m.
+	Drift20m *float64 `json:"drift_20m,omitempty"`
+	Drift1h  *float64 `json:"drift_1h,omitempty"`
+	Drift24h *float64 `json:"drift_24h,omitempty"`
+	Drift1w  *float64 `json:"drift_1w,omitempty"`
+	Drift1m  *float64 `json:"drift_1m,omitempty"`
+
+	// Staged entry/exit plan (see ComputeTrailingLevels/ComputeEntryLayers).
+	TrailingLevels []TrailingLevel `json:"trailing_levels,omitempty"`
+	EntryLayers    []EntryLayer    `json:"entry_layers,omitempty"`
+
+	// Pivot-low breakout signals over the 24h insta-sell price history (see
+	// ClassifyPivotBreak).
+	PivotBreakBuy   bool `json:"pivot_break_buy"`
+	PivotBounceSell bool `json:"pivot_bounce_sell"`
+
+	// Nearest neighbors in PCA feature space, for suggesting substitutes
+	// (see pca.Embedding.FindSimilar).
+	SimilarItems []int `json:"similar_items,omitempty"`
+}
+
+// TrailingLevel is one rung of a trailing-stop ladder: once price moves
+// ActivationRatio above entry, a trailing stop activates and follows price
+// down by CallbackRatio. SuggestedPrice is the activation price in GP for
+// the item's current insta-sell (entry) price.
+type TrailingLevel struct {
+	ActivationRatio float64 `json:"activation_ratio"`
+	CallbackRatio   float64 `json:"callback_ratio"`
+	SuggestedPrice  int     `json:"suggested_price"`
+}
+
+// EntryLayer is one rung of a layered-entry plan: a limit buy PriceOffset
+// GP below the item's insta-sell price, sized at QuantityPct of the buy
+// limit.
+type EntryLayer struct {
+	PriceOffset int     `json:"price_offset"`
+	QuantityPct float64 `json:"quantity_pct"`
+}
+
+// DistributionStats summarizes the shape of a price or volume series over a
+// window, rather than collapsing it to a single average -- a burst of one
+// huge trade and steady flow both average out the same, but look very
+// different here. See computeStats.
+type DistributionStats struct {
+	Mean   float64
+	StdDev float64
+	Min    float64
+	Max    float64
+	P25    float64
+	P50    float64
+	P75    float64
+	P95    float64
 }
 
 // VolumeMetrics holds calculated volume and trend data for an item
@@ -110,6 +258,31 @@ type VolumeMetrics struct {
 	AvgInstaSellPrice24h float64
 	AvgMarginGP24h       float64
 
+	// VWAPInstaBuy/VWAPInstaSell are volume-weighted average prices over
+	// each window -- sum(price_i * volume_i) / sum(volume_i), skipping
+	// zero-volume buckets -- a more honest "fair value" than
+	// AvgInstaBuyPrice*/AvgInstaSellPrice* above, which weight every bucket
+	// equally regardless of how much actually traded there. Zero until a
+	// window has at least one non-zero-volume bucket. See
+	// Analyzer.calculate5mMetrics.
+	VWAPInstaBuy1h   float64
+	VWAPInstaSell1h  float64
+	VWAPInstaBuy4h   float64
+	VWAPInstaSell4h  float64
+	VWAPInstaBuy24h  float64
+	VWAPInstaSell24h float64
+
+	// VWAPSpread24h is the volume-weighted "fair" margin, VWAPInstaBuy24h -
+	// VWAPInstaSell24h, for comparing against an item's live MarginGP.
+	VWAPSpread24h float64
+
+	// Notes records data-quality issues detectDataQualityNotes found while
+	// parsing this item's 5m/24h timeseries -- volume resets, price
+	// outliers, and oversized gaps -- so callers can see why a bucket was
+	// adjusted (or left alone; see DataQualityConfig.Sanitize) before it
+	// fed into the averages and VWAPs above.
+	Notes []Note
+
 	// Trend analysis
 	InstaSellPriceTrend1h  string
 	InstaBuyPriceTrend1h   string
@@ -119,6 +292,64 @@ type VolumeMetrics struct {
 	InstaBuyPriceTrend1w   string
 	InstaSellPriceTrend1m  string
 	InstaBuyPriceTrend1m   string
+
+	// TrendStrength is the EMA crossover strength behind InstaBuyPriceTrend1h,
+	// (fastEMA-slowEMA)/slowEMA -- see calculateTrend. Unlike the trend
+	// labels above, it's a continuous signal, useful for sorting/filtering
+	// items by how strong a trend is rather than just its direction.
+	TrendStrength float64
+
+	// HAStreak1h is the number of consecutive same-direction Heikin-Ashi
+	// candles behind InstaBuyPriceTrend1h, set only when
+	// IndicatorConfig.UseHeikinAshi is on -- see calculateTrendHA.
+	HAStreak1h int
+
+	// ATR/Elliott-Wave trend signal, computed over the 24h window where we
+	// have enough samples for a meaningful volatility read. See
+	// ClassifyTrendATR.
+	ATR24h       float64
+	WaveTrend24h string
+
+	// AvgTrueRange1h/24h are Wilder's ATR (see the package-level ATR
+	// function) over the bucketed avgHighPrice/avgLowPrice series for each
+	// window, using each bucket's own avgHighPrice as the "previous close"
+	// since these bucketed feeds have no true close price. Unlike ATR24h
+	// above (which feeds into a Label/Wave classification), these are the
+	// raw ATR values, for ranking flips by volatility directly.
+	AvgTrueRange1h  float64
+	AvgTrueRange24h float64
+
+	// Distribution stats (mean, stddev, min/max, percentiles) over the
+	// bucketed 1h/24h insta-buy/insta-sell price and volume series -- see
+	// computeStats and DistributionStats.
+	InstaBuyPriceStats1h   DistributionStats
+	InstaSellPriceStats1h  DistributionStats
+	InstaBuyVolumeStats1h  DistributionStats
+	InstaSellVolumeStats1h DistributionStats
+
+	InstaBuyPriceStats24h   DistributionStats
+	InstaSellPriceStats24h  DistributionStats
+	InstaBuyVolumeStats24h  DistributionStats
+	InstaSellVolumeStats24h DistributionStats
+
+	// FisherTransform1h is the last value of the Fisher Transform (see the
+	// package-level fisherTransform function) over the 1h insta-buy price
+	// series -- a sharper, more Gaussian reversal oscillator than the raw
+	// price. InstaBuyReversalSignal classifies it into "overbought"/
+	// "oversold"/"" (neither extreme).
+	FisherTransform1h      float64
+	InstaBuyReversalSignal string
+
+	// Drift20m/1h/24h/1w/1m are sortable momentum scores over the insta-buy
+	// price series for each window (see the package-level driftMA
+	// function) -- a continuous alternative to the
+	// InstaBuyPriceTrend*/InstaSellPriceTrend* bucket strings above. Use
+	// DriftLabel(drift, threshold) to get a comparable bucket string back.
+	Drift20m float64
+	Drift1h  float64
+	Drift24h float64
+	Drift1w  float64
+	Drift1m  float64
 }
 
 // BulkPriceDataPoint represents a single item's data from a bulk price endpoint (/5m, /1h, /24h).
@@ -152,6 +383,44 @@ type FilterOptions struct {
 	Volume20mMin        *int
 	Volume1hMin         *int
 	Volume24hMin        *int
+
+	// VolumeAction controls how Volume{20m,1h,24h}Min above are evaluated
+	// against an item's buy-side and sell-side volumes. Empty defaults to
+	// VolumeActionCombined, the original buy+sell-summed behavior.
+	VolumeAction VolumeAction
+
+	// Per-side volume thresholds, always evaluated independently of
+	// VolumeAction -- an item can have plenty of insta-sell volume with no
+	// one buying, which a single combined threshold can't detect.
+	InstaBuyVolume20mMin  *float64
+	InstaBuyVolume20mMax  *float64
+	InstaSellVolume20mMin *float64
+	InstaSellVolume20mMax *float64
+	InstaBuyVolume1hMin   *float64
+	InstaBuyVolume1hMax   *float64
+	InstaSellVolume1hMin  *float64
+	InstaSellVolume1hMax  *float64
+	InstaBuyVolume24hMin  *float64
+	InstaBuyVolume24hMax  *float64
+	InstaSellVolume24hMin *float64
+	InstaSellVolume24hMax *float64
+
+	// VolumeFilterMode selects how BuyVolume{1h,24h}Min/
+	// SellVolume{1h,24h}Min below combine. Empty defaults to
+	// VolumeFilterTotal, under which they're ignored entirely and
+	// Volume{1h,24h}Min/VolumeAction above remain the only volume floor in
+	// effect -- the original, pre-chunk8-4 behavior.
+	VolumeFilterMode VolumeFilterMode
+
+	// Per-side volume floors combined per VolumeFilterMode. Unlike
+	// InstaBuyVolume1hMin/InstaSellVolume1hMin above, which are always
+	// ANDed together, these let a caller ask for Either or
+	// MinOfBothSides instead of always requiring Both.
+	BuyVolume1hMin   *int
+	SellVolume1hMin  *int
+	BuyVolume24hMin  *int
+	SellVolume24hMin *int
+
 	MembersOnly         *bool
 	MaxHoursSinceUpdate *float64
 	NameContains        *string
@@ -160,4 +429,100 @@ type FilterOptions struct {
 	SortByAfterVolume   string
 	SortDesc            bool
 	Limit               int
+
+	// Pivot-low breakout filters (see ClassifyPivotBreak)
+	RequirePivotBreak *bool
+	PivotLength       *int
+	BreakRatio        *float64
+
+	// ExcludeSimilarTo, when true, drops items that are near-duplicates (by
+	// PCA feature distance) of an item already kept, so the output list
+	// isn't dominated by e.g. ten different runes. See diversifyBySimilarity.
+	ExcludeSimilarTo *bool
+
+	// Volatility/stability filters (see Analyzer.applyVolatilitySignal).
+	// ATRPctMax excludes items whose 1h ATR, as a fraction of current
+	// price, exceeds the given ratio. MarginStddevMax excludes items whose
+	// margin swings wildly over the last 24h. PriceEMADeviationMax
+	// excludes items whose current insta-sell price has strayed more than
+	// the given fraction from its 24h EMA, a likely sign of an ongoing
+	// price manipulation rather than a stable flipping opportunity.
+	ATRPctMax            *float64
+	MarginStddevMax      *float64
+	PriceEMADeviationMax *float64
+
+	// MaxATRPctOfPrice excludes items whose bucketed 1h ATR (see
+	// VolumeMetrics.AvgTrueRange1h), as a fraction of InstaBuyPrice,
+	// exceeds the given ratio. This is the same idea as ATRPctMax above
+	// but computed from the bucketed avgHighPrice/avgLowPrice timeseries
+	// rather than ATRPctMax's streaming indicator.ATR fed from the rolling
+	// price store -- keep both, since they can disagree when the streaming
+	// store hasn't accumulated enough ticks yet but bucketed history is
+	// already available.
+	MaxATRPctOfPrice *float64
+
+	// MaxPriceStdDevPct excludes items whose 1h insta-buy price stddev,
+	// as a fraction of its mean (see VolumeMetrics.InstaBuyPriceStats1h),
+	// exceeds the given ratio -- a distribution-shape complement to
+	// ATRPctMax/MaxATRPctOfPrice above, which only look at bar-to-bar range.
+	MaxPriceStdDevPct *float64
+
+	// OnlyOversold/OnlyOverbought screen for Fisher Transform reversal
+	// candidates (see VolumeMetrics.InstaBuyReversalSignal) -- at most one
+	// should be set, since an item can't be both at once.
+	OnlyOversold   *bool
+	OnlyOverbought *bool
+
+	// Expr is an optional boolean expression (see package osrs/expr)
+	// checked against each item after the scalar filters above, for
+	// one-off comparisons ("24h volume trending up", "margin > 2x its
+	// 7-day median") that don't warrant their own FilterOptions field.
+	// Bindings: price, margin, volume_buy, volume_sell. Functions:
+	// sma(series,n), ema(series,n), stddev(series,n), atr(n),
+	// slope(series,n), pct_change(series,n). Compiled once per ApplyFilter
+	// call, not per item.
+	Expr string
 }
+
+// VolumeAction selects how an item's buy-side and sell-side volumes are
+// combined when evaluating Volume{20m,1h,24h}Min.
+type VolumeAction string
+
+const (
+	// VolumeActionCombined sums buy-side and sell-side volume and compares
+	// the total against the threshold. This is the default (empty
+	// VolumeAction) and matches the package's original behavior.
+	VolumeActionCombined VolumeAction = "combined"
+	// VolumeActionBuy requires only buy-side volume to meet the threshold.
+	VolumeActionBuy VolumeAction = "buy"
+	// VolumeActionSell requires only sell-side volume to meet the threshold.
+	VolumeActionSell VolumeAction = "sell"
+	// VolumeActionEither requires at least one side to meet the threshold.
+	VolumeActionEither VolumeAction = "either"
+	// VolumeActionBoth requires both sides to independently meet the
+	// threshold (the pre-chunk5-2 passesFilter semantics).
+	VolumeActionBoth VolumeAction = "both"
+)
+
+// VolumeFilterMode selects how FilterOptions' BuyVolume{1h,24h}Min and
+// SellVolume{1h,24h}Min combine when passesVolumeFilters evaluates them.
+type VolumeFilterMode string
+
+const (
+	// VolumeFilterTotal ignores BuyVolume{1h,24h}Min/SellVolume{1h,24h}Min
+	// entirely, leaving Volume{1h,24h}Min/VolumeAction as the only volume
+	// floor in effect. This is the default (empty VolumeFilterMode).
+	VolumeFilterTotal VolumeFilterMode = "total"
+	// VolumeFilterBoth requires both sides to independently clear their
+	// own threshold.
+	VolumeFilterBoth VolumeFilterMode = "both"
+	// VolumeFilterEither requires at least one side to clear its own
+	// threshold.
+	VolumeFilterEither VolumeFilterMode = "either"
+	// VolumeFilterMinOfBothSides requires the smaller of the two sides'
+	// volumes to clear the smaller of the two thresholds -- looser than
+	// VolumeFilterBoth when the thresholds differ, since it lets a
+	// structurally thin side (e.g. an item rarely insta-sold) slide as
+	// long as the weaker of the two bars is still met.
+	VolumeFilterMinOfBothSides VolumeFilterMode = "min_of_both_sides"
+)
@@ -121,7 +121,7 @@ func TestCalculate5mMetricsEdgeCases(t *testing.T) {
 		dataSlice := []interface{}{}
 		metrics := VolumeMetrics{}
 
-		result := analyzer.calculate5mMetrics(dataSlice, metrics)
+		result := analyzer.calculate5mMetrics(1, dataSlice, metrics)
 
 		if result.InstaBuyVolume20m != 0 {
 			t.Errorf("Expected InstaBuyVolume20m to be 0, got %f", result.InstaBuyVolume20m)
@@ -148,7 +148,7 @@ func TestCalculate5mMetricsEdgeCases(t *testing.T) {
 		}
 		metrics := VolumeMetrics{}
 
-		result := analyzer.calculate5mMetrics(dataSlice, metrics)
+		result := analyzer.calculate5mMetrics(1, dataSlice, metrics)
 
 		if result.InstaBuyVolume20m != 100.0 {
 			t.Errorf("Expected InstaBuyVolume20m to be 100.0, got %f", result.InstaBuyVolume20m)
@@ -175,7 +175,7 @@ func TestCalculate5mMetricsEdgeCases(t *testing.T) {
 		}
 		metrics := VolumeMetrics{}
 
-		result := analyzer.calculate5mMetrics(dataSlice, metrics)
+		result := analyzer.calculate5mMetrics(1, dataSlice, metrics)
 
 		// Zero prices should not be included in price arrays
 		if result.AvgInstaBuyPrice20m != 0 {
@@ -205,7 +205,7 @@ func TestCalculate24hMetricsEdgeCases(t *testing.T) {
 		}
 		metrics := VolumeMetrics{}
 
-		result := analyzer.calculate24hMetrics(dataSlice, metrics)
+		result := analyzer.calculate24hMetrics(1, dataSlice, metrics)
 
 		if result.InstaBuyPriceTrend24h != "flat" {
 			t.Errorf("Expected InstaBuyPriceTrend24h to be 'flat', got %s", result.InstaBuyPriceTrend24h)
@@ -228,7 +228,7 @@ func TestCalculate24hMetricsEdgeCases(t *testing.T) {
 		}
 		metrics := VolumeMetrics{}
 
-		result := analyzer.calculate24hMetrics(dataSlice, metrics)
+		result := analyzer.calculate24hMetrics(1, dataSlice, metrics)
 
 		// Should handle gracefully and return empty metrics
 		if result.InstaBuyVolume24h != 0 {
@@ -252,7 +252,7 @@ func TestProcessTimeseriesDataEdgeCases(t *testing.T) {
 			"data": 12345,
 		}
 
-		metrics := analyzer.processTimeseriesData(data5m, data24h)
+		metrics := analyzer.processTimeseriesData(1, data5m, data24h)
 
 		// Should handle gracefully
 		if metrics.InstaBuyVolume20m != 0 {
@@ -266,7 +266,7 @@ func TestProcessTimeseriesDataEdgeCases(t *testing.T) {
 		}
 		data24h := map[string]interface{}{}
 
-		metrics := analyzer.processTimeseriesData(data5m, data24h)
+		metrics := analyzer.processTimeseriesData(1, data5m, data24h)
 
 		// Should handle gracefully
 		if metrics.InstaBuyVolume20m != 0 {
@@ -328,17 +328,12 @@ func TestCalculateTrendRealWorldScenarios(t *testing.T) {
 		},
 	}
 
+	cfg := DefaultIndicatorConfig()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := calculateTrend(tt.x, tt.y)
+			result, strength := calculateTrend(tt.x, tt.y, cfg)
 			if result != tt.expected {
-				t.Errorf("%s: calculateTrend() = %v, want %v", tt.description, result, tt.expected)
-
-				// Print additional debug info for failed tests
-				if len(tt.y) > 1 && tt.y[0] != 0 {
-					pctChange := (tt.y[len(tt.y)-1] - tt.y[0]) / tt.y[0] * 100
-					t.Logf("Percentage change: %.2f%%", pctChange)
-				}
+				t.Errorf("%s: calculateTrend() = %v (strength %.4f), want %v", tt.description, result, strength, tt.expected)
 			}
 		})
 	}
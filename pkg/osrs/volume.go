@@ -5,65 +5,14 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
-)
-
-// RateLimiter implements a token bucket rate limiter for API calls
-type RateLimiter struct {
-	tokens     int
-	maxTokens  int
-	refillRate time.Duration
-	lastRefill time.Time
-	mutex      sync.Mutex
-}
-
-// NewRateLimiter creates a rate limiter with specified requests per second
-func NewRateLimiter(requestsPerSecond float64) *RateLimiter {
-	maxTokens := int(math.Ceil(requestsPerSecond))
-	refillRate := time.Duration(float64(time.Second) / requestsPerSecond)
-
-	return &RateLimiter{
-		tokens:     maxTokens,
-		maxTokens:  maxTokens,
-		refillRate: refillRate,
-		lastRefill: time.Now(),
-	}
-}
-
-// Wait blocks until a token is available, respecting the rate limit
-func (rl *RateLimiter) Wait(ctx context.Context) error {
-	rl.mutex.Lock()
-	defer rl.mutex.Unlock()
-
-	// Refill tokens based on time passed
-	now := time.Now()
-	elapsed := now.Sub(rl.lastRefill)
-	tokensToAdd := int(elapsed / rl.refillRate)
 
-	if tokensToAdd > 0 {
-		rl.tokens = min(rl.maxTokens, rl.tokens+tokensToAdd)
-		rl.lastRefill = now
-	}
-
-	// If we have tokens, consume one
-	if rl.tokens > 0 {
-		rl.tokens--
-		return nil
-	}
-
-	// Wait until next token is available
-	waitTime := rl.refillRate - (elapsed % rl.refillRate)
-
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(waitTime):
-		rl.tokens = rl.maxTokens - 1 // Consume the token we just got
-		rl.lastRefill = time.Now()
-		return nil
-	}
-}
+	"osrs-flipping/pkg/osrs/factors"
+	"osrs-flipping/pkg/osrs/indicator"
+	"osrs-flipping/pkg/osrs/store"
+)
 
 func min(a, b int) int {
 	if a < b {
@@ -86,7 +35,20 @@ type TimeseriesResponse struct {
 	Data []VolumeDataPoint `json:"data"`
 }
 
-// LoadVolumeData fetches volume data for specified items with rate limiting
+// numVolumeWorkers is LoadVolumeData's worker pool size. Actual API
+// concurrency is bounded separately by volumeConcurrencyLimit (see
+// Semaphore) and by each endpoint's own adaptive rate limiter (see
+// Client.rateLimiters), so this can be raised well past the req/s budget
+// without flooding the network.
+const numVolumeWorkers = 8
+
+// volumeConcurrencyLimit caps how many LoadVolumeData requests are
+// in-flight against the API at once, independent of numVolumeWorkers.
+const volumeConcurrencyLimit = 4
+
+// LoadVolumeData fetches volume data for specified items. Requests are
+// paced per-endpoint by the analyzer's Client (see Client.rateLimiters);
+// this just bounds how much of that pacing happens concurrently.
 // Equivalent to load_volume_data method in Python
 func (a *Analyzer) LoadVolumeData(ctx context.Context, itemIDs []int, maxItems int) error {
 	if !a.HasData() {
@@ -95,20 +57,16 @@ func (a *Analyzer) LoadVolumeData(ctx context.Context, itemIDs []int, maxItems i
 
 	// Use top profitable items if none specified
 	if itemIDs == nil {
-		itemIDs = a.getTopItemIDs(maxItems)
+		itemIDs = a.getTopItemIDs(maxItems, "")
 	}
 
 	if len(itemIDs) > maxItems {
 		itemIDs = itemIDs[:maxItems]
 	}
 
-	fmt.Printf("📈 Fetching volume data for %d items (rate limited to 2 req/sec)...\n", len(itemIDs))
-
-	// Create rate limiter: 2 requests per second with some buffer
-	rateLimiter := NewRateLimiter(2.0) // Slightly under 2 req/sec for safety
+	fmt.Printf("📈 Fetching volume data for %d items...\n", len(itemIDs))
 
-	// Create worker pool with limited concurrency
-	const numWorkers = 2 // Keep concurrency low due to rate limit
+	sem := NewSemaphore(volumeConcurrencyLimit)
 
 	type volumeJob struct {
 		itemID int
@@ -127,13 +85,12 @@ func (a *Analyzer) LoadVolumeData(ctx context.Context, itemIDs []int, maxItems i
 
 	// Start workers
 	var wg sync.WaitGroup
-	for i := 0; i < numWorkers; i++ {
+	for i := 0; i < numVolumeWorkers; i++ {
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
 			for job := range jobs {
-				// Wait for rate limiter
-				if err := rateLimiter.Wait(ctx); err != nil {
+				if err := sem.Acquire(ctx); err != nil {
 					results <- volumeResult{itemID: job.itemID, index: job.index, err: err}
 					continue
 				}
@@ -143,6 +100,7 @@ func (a *Analyzer) LoadVolumeData(ctx context.Context, itemIDs []int, maxItems i
 				time.Sleep(time.Duration(jitterMs) * time.Millisecond)
 
 				metrics, err := a.calculateVolumeMetrics(ctx, job.itemID)
+				sem.Release()
 				results <- volumeResult{
 					itemID:  job.itemID,
 					index:   job.index,
@@ -193,53 +151,111 @@ func (a *Analyzer) LoadVolumeData(ctx context.Context, itemIDs []int, maxItems i
 	// Update analyzer data with volume metrics
 	a.updateItemsWithVolumeData(volumeData)
 
+	if err := a.store.Save(); err != nil {
+		fmt.Printf("⚠️  failed to persist market data store: %v\n", err)
+	}
+
 	fmt.Printf("✅ Successfully enriched %d/%d items with volume data\n", successCount, len(itemIDs))
 	return nil
 }
 
+// cacheTTL5m and cacheTTL24h are how long a store entry is trusted before
+// fetchTimeseriesCached will hit the API again for it -- a little past each
+// bucket's own granularity so a normal polling cadence never pays for a
+// redundant fetch.
+const (
+	cacheTTL5m  = 6 * time.Minute
+	cacheTTL24h = 25 * time.Hour
+)
+
 // calculateVolumeMetrics processes timeseries data for a single item
 func (a *Analyzer) calculateVolumeMetrics(ctx context.Context, itemID int) (VolumeMetrics, error) {
 	// Get 5-minute data for recent metrics (20m, 1h calculations)
-	data5m, err := a.client.GetTimeseries(ctx, itemID, "5m")
+	data5m, err := a.fetchTimeseriesCached(ctx, itemID, store.Interval5m, "5m", cacheTTL5m)
 	if err != nil {
 		return VolumeMetrics{}, fmt.Errorf("fetching 5m data: %w", err)
 	}
 
 	// Get 24h data for longer trend analysis
-	data24h, err := a.client.GetTimeseries(ctx, itemID, "24h")
+	data24h, err := a.fetchTimeseriesCached(ctx, itemID, store.Interval24h, "24h", cacheTTL24h)
 	if err != nil {
 		return VolumeMetrics{}, fmt.Errorf("fetching 24h data: %w", err)
 	}
 
-	// Process the timeseries data
-	metrics := a.processTimeseriesData(data5m, data24h)
+	// Process the timeseries data, merging fetched ticks into the rolling
+	// store as we go so future runs can read them back via GetSeries
+	// instead of re-fetching.
+	metrics := a.processTimeseriesData(itemID, data5m, data24h)
 	return metrics, nil
 }
 
+// fetchTimeseriesCached returns timeseries data shaped exactly like
+// Client.GetTimeseries's response, but skips the API call entirely when the
+// store already has a fetch for itemID/interval younger than ttl -- unless
+// a.forceRefresh is set. On a cache hit it synthesizes the response from the
+// store's own DataPoints (see timeseriesFromStore) so the existing
+// calculate5mMetrics/calculate24hMetrics parsing doesn't need to know the
+// difference.
+func (a *Analyzer) fetchTimeseriesCached(ctx context.Context, itemID int, interval store.Interval, timestep string, ttl time.Duration) (map[string]interface{}, error) {
+	if !a.forceRefresh {
+		if lastFetched, ok := a.store.LastFetched(itemID, interval); ok && time.Since(lastFetched) < ttl {
+			return timeseriesFromStore(a.store.GetSeries(itemID, interval, time.Time{})), nil
+		}
+	}
+
+	start := time.Now()
+	data, err := a.client.GetTimeseries(ctx, itemID, timestep)
+	a.recordScrape(timestep, start, err)
+	if err != nil {
+		return nil, err
+	}
+	a.store.MarkFetched(itemID, interval, time.Now())
+	return data, nil
+}
+
+// timeseriesFromStore rebuilds the "data" shape Client.GetTimeseries returns
+// from cached DataPoints, reversing the avgHigh/avgLow/highVol/lowVol ->
+// DataPoint mapping calculate5mMetrics/calculate24hMetrics use when they
+// first parse and store a tick.
+func timeseriesFromStore(points []store.DataPoint) map[string]interface{} {
+	data := make([]interface{}, len(points))
+	for i, p := range points {
+		data[i] = map[string]interface{}{
+			"timestamp":       float64(p.Timestamp.Unix()),
+			"avgHighPrice":    float64(p.InstaBuyPrice),
+			"avgLowPrice":     float64(p.InstaSellPrice),
+			"highPriceVolume": float64(p.InstaBuyVolume),
+			"lowPriceVolume":  float64(p.InstaSellVolume),
+		}
+	}
+	return map[string]interface{}{"data": data}
+}
+
 // processTimeseriesData converts raw API response to our metrics
-func (a *Analyzer) processTimeseriesData(data5m, data24h map[string]interface{}) VolumeMetrics {
+func (a *Analyzer) processTimeseriesData(itemID int, data5m, data24h map[string]interface{}) VolumeMetrics {
 	var metrics VolumeMetrics
 
 	// Parse 5-minute data for recent metrics
 	if dataSlice, ok := data5m["data"].([]interface{}); ok {
-		metrics = a.calculate5mMetrics(dataSlice, metrics)
+		metrics = a.calculate5mMetrics(itemID, dataSlice, metrics)
 	}
 
 	// Parse 24h data for trend analysis
 	if dataSlice, ok := data24h["data"].([]interface{}); ok {
-		metrics = a.calculate24hMetrics(dataSlice, metrics)
+		metrics = a.calculate24hMetrics(itemID, dataSlice, metrics)
 	}
 
 	return metrics
 }
 
 // calculate5mMetrics processes 5-minute data for 20m, 1h, and 24h windows
-func (a *Analyzer) calculate5mMetrics(dataSlice []interface{}, metrics VolumeMetrics) VolumeMetrics {
+func (a *Analyzer) calculate5mMetrics(itemID int, dataSlice []interface{}, metrics VolumeMetrics) VolumeMetrics {
 	now := time.Now().Unix()
 
 	// Time windows
 	window20m := now - (20 * 60)      // 20 minutes ago
 	window1h := now - (60 * 60)       // 1 hour ago
+	window4h := now - (4 * 60 * 60)   // 4 hours ago
 	window24h := now - (24 * 60 * 60) // 25 hour ago
 
 	var (
@@ -255,71 +271,133 @@ func (a *Analyzer) calculate5mMetrics(dataSlice []interface{}, metrics VolumeMet
 		instaBuy24h, instaSell24h       []float64
 		instaBuyVol24h, instaSellVol24h float64
 
+		// VWAP numerator (sum price_i*volume_i) and denominator (sum
+		// volume_i) per window and side -- see VolumeMetrics.VWAPInstaBuy*.
+		vwapBuyNum1h, vwapBuyDen1h     float64
+		vwapSellNum1h, vwapSellDen1h   float64
+		vwapBuyNum4h, vwapBuyDen4h     float64
+		vwapSellNum4h, vwapSellDen4h   float64
+		vwapBuyNum24h, vwapBuyDen24h   float64
+		vwapSellNum24h, vwapSellDen24h float64
+
 		// For 1h trend analysis - collect timestamps and prices
 		timestamps1h, instaBuyPrices1h, instaSellPrices1h []float64
 
 		// For 24h trend analysis - collect timestamps and prices
 		timestamps24h, instaBuyPrices24h, instaSellPrices24h []float64
+
+		// Per-bucket volume series for 1h/24h distribution stats (see
+		// computeStats), alongside the running instaBuyVol1h/24h sums above.
+		instaBuyVolSeries1h, instaSellVolSeries1h   []float64
+		instaBuyVolSeries24h, instaSellVolSeries24h []float64
+
+		// lowPrices1h/24h shadow instaBuyPrices1h/24h index-for-index (the
+		// ATR function needs matched high/low/close slices), falling back
+		// to the high price itself when a bucket has no avgLowPrice.
+		lowPrices1h, lowPrices24h []float64
 	)
 
-	for _, item := range dataSlice {
-		if dataPoint, ok := item.(map[string]interface{}); ok {
-			timestamp := int64(dataPoint["timestamp"].(float64))
+	points := parseSeriesPoints(dataSlice)
+	metrics.Notes = append(metrics.Notes, detectDataQualityNotes(points, 5*time.Minute, a.dataQualityConfig)...)
+	if a.dataQualityConfig.Sanitize {
+		points = sanitizeSeriesPoints(points, a.dataQualityConfig)
+	}
 
-			// Extract prices and volumes
-			var avgHigh, avgLow, highVol, lowVol float64
-			if val, exists := dataPoint["avgHighPrice"]; exists && val != nil {
-				avgHigh = val.(float64)
-			}
-			if val, exists := dataPoint["avgLowPrice"]; exists && val != nil {
-				avgLow = val.(float64)
-			}
-			if val, exists := dataPoint["highPriceVolume"]; exists && val != nil {
-				highVol = val.(float64)
+	for _, p := range points {
+		timestamp := p.Timestamp
+		avgHigh, avgLow, highVol, lowVol := p.AvgHigh, p.AvgLow, p.HighVol, p.LowVol
+
+		a.store.Append(itemID, store.Interval5m, store.DataPoint{
+			Timestamp:       time.Unix(timestamp, 0).UTC(),
+			InstaBuyPrice:   int(avgHigh),
+			InstaSellPrice:  int(avgLow),
+			InstaBuyVolume:  int(highVol),
+			InstaSellVolume: int(lowVol),
+		})
+
+		// 20-minute window
+		if timestamp >= window20m {
+			if avgHigh > 0 {
+				instaBuy20m = append(instaBuy20m, avgHigh)
 			}
-			if val, exists := dataPoint["lowPriceVolume"]; exists && val != nil {
-				lowVol = val.(float64)
+			if avgLow > 0 {
+				instaSell20m = append(instaSell20m, avgLow)
 			}
+			instaBuyVol20m += highVol
+			instaSellVol20m += lowVol
+		}
 
-			// 20-minute window
-			if timestamp >= window20m {
-				if avgHigh > 0 {
-					instaBuy20m = append(instaBuy20m, avgHigh)
-				}
-				if avgLow > 0 {
-					instaSell20m = append(instaSell20m, avgLow)
+		// 1-hour window
+		if timestamp >= window1h {
+			if avgHigh > 0 {
+				instaBuy1h = append(instaBuy1h, avgHigh)
+				timestamps1h = append(timestamps1h, float64(timestamp))
+				instaBuyPrices1h = append(instaBuyPrices1h, avgHigh)
+				low := avgLow
+				if low <= 0 {
+					low = avgHigh
 				}
-				instaBuyVol20m += highVol
-				instaSellVol20m += lowVol
+				lowPrices1h = append(lowPrices1h, low)
+			}
+			if avgLow > 0 {
+				instaSell1h = append(instaSell1h, avgLow)
+				instaSellPrices1h = append(instaSellPrices1h, avgLow)
+			}
+			instaBuyVol1h += highVol
+			instaSellVol1h += lowVol
+			instaBuyVolSeries1h = append(instaBuyVolSeries1h, highVol)
+			instaSellVolSeries1h = append(instaSellVolSeries1h, lowVol)
+
+			if highVol > 0 {
+				vwapBuyNum1h += avgHigh * highVol
+				vwapBuyDen1h += highVol
 			}
+			if lowVol > 0 {
+				vwapSellNum1h += avgLow * lowVol
+				vwapSellDen1h += lowVol
+			}
+		}
 
-			// 1-hour window
-			if timestamp >= window1h {
-				if avgHigh > 0 {
-					instaBuy1h = append(instaBuy1h, avgHigh)
-					timestamps1h = append(timestamps1h, float64(timestamp))
-					instaBuyPrices1h = append(instaBuyPrices1h, avgHigh)
-				}
-				if avgLow > 0 {
-					instaSell1h = append(instaSell1h, avgLow)
-					instaSellPrices1h = append(instaSellPrices1h, avgLow)
-				}
-				instaBuyVol1h += highVol
-				instaSellVol1h += lowVol
+		// 4-hour window (VWAP only -- no trend/distribution analysis
+		// uses this window today).
+		if timestamp >= window4h {
+			if highVol > 0 {
+				vwapBuyNum4h += avgHigh * highVol
+				vwapBuyDen4h += highVol
 			}
+			if lowVol > 0 {
+				vwapSellNum4h += avgLow * lowVol
+				vwapSellDen4h += lowVol
+			}
+		}
 
-			if timestamp >= window24h {
-				if avgHigh > 0 {
-					instaBuy24h = append(instaBuy24h, avgHigh)
-					timestamps24h = append(timestamps24h, float64(timestamp))
-					instaBuyPrices24h = append(instaBuyPrices24h, avgHigh)
-				}
-				if avgLow > 0 {
-					instaSell24h = append(instaSell24h, avgLow)
-					instaSellPrices24h = append(instaSellPrices24h, avgLow)
+		if timestamp >= window24h {
+			if avgHigh > 0 {
+				instaBuy24h = append(instaBuy24h, avgHigh)
+				timestamps24h = append(timestamps24h, float64(timestamp))
+				instaBuyPrices24h = append(instaBuyPrices24h, avgHigh)
+				low := avgLow
+				if low <= 0 {
+					low = avgHigh
 				}
-				instaBuyVol24h += highVol
-				instaSellVol24h += lowVol
+				lowPrices24h = append(lowPrices24h, low)
+			}
+			if avgLow > 0 {
+				instaSell24h = append(instaSell24h, avgLow)
+				instaSellPrices24h = append(instaSellPrices24h, avgLow)
+			}
+			instaBuyVol24h += highVol
+			instaSellVol24h += lowVol
+			instaBuyVolSeries24h = append(instaBuyVolSeries24h, highVol)
+			instaSellVolSeries24h = append(instaSellVolSeries24h, lowVol)
+
+			if highVol > 0 {
+				vwapBuyNum24h += avgHigh * highVol
+				vwapBuyDen24h += highVol
+			}
+			if lowVol > 0 {
+				vwapSellNum24h += avgLow * lowVol
+				vwapSellDen24h += lowVol
 			}
 		}
 	}
@@ -355,37 +433,95 @@ func (a *Analyzer) calculate5mMetrics(dataSlice []interface{}, metrics VolumeMet
 	metrics.InstaSellVolume24h = instaSellVol24h
 	metrics.AvgMarginGP24h = metrics.AvgInstaBuyPrice24h - metrics.AvgInstaSellPrice24h
 
-	// Calculate 1h trends using linear regression
+	// Volume-weighted averages (see VolumeMetrics.VWAPInstaBuy*). Windows
+	// with no non-zero-volume buckets are left at zero rather than
+	// producing a NaN.
+	if vwapBuyDen1h > 0 {
+		metrics.VWAPInstaBuy1h = vwapBuyNum1h / vwapBuyDen1h
+	}
+	if vwapSellDen1h > 0 {
+		metrics.VWAPInstaSell1h = vwapSellNum1h / vwapSellDen1h
+	}
+	if vwapBuyDen4h > 0 {
+		metrics.VWAPInstaBuy4h = vwapBuyNum4h / vwapBuyDen4h
+	}
+	if vwapSellDen4h > 0 {
+		metrics.VWAPInstaSell4h = vwapSellNum4h / vwapSellDen4h
+	}
+	if vwapBuyDen24h > 0 {
+		metrics.VWAPInstaBuy24h = vwapBuyNum24h / vwapBuyDen24h
+	}
+	if vwapSellDen24h > 0 {
+		metrics.VWAPInstaSell24h = vwapSellNum24h / vwapSellDen24h
+	}
+	if metrics.VWAPInstaBuy24h > 0 && metrics.VWAPInstaSell24h > 0 {
+		metrics.VWAPSpread24h = metrics.VWAPInstaBuy24h - metrics.VWAPInstaSell24h
+	}
+
+	metrics.InstaBuyPriceStats1h = computeStats(instaBuy1h)
+	metrics.InstaSellPriceStats1h = computeStats(instaSell1h)
+	metrics.InstaBuyVolumeStats1h = computeStats(instaBuyVolSeries1h)
+	metrics.InstaSellVolumeStats1h = computeStats(instaSellVolSeries1h)
+
+	metrics.InstaBuyPriceStats24h = computeStats(instaBuy24h)
+	metrics.InstaSellPriceStats24h = computeStats(instaSell24h)
+	metrics.InstaBuyVolumeStats24h = computeStats(instaBuyVolSeries24h)
+	metrics.InstaSellVolumeStats24h = computeStats(instaSellVolSeries24h)
+
+	if fish := fisherTransform(instaBuyPrices1h, a.indicatorConfig.FisherWindow); len(fish) > 0 {
+		metrics.FisherTransform1h = fish[len(fish)-1]
+		metrics.InstaBuyReversalSignal = classifyFisherReversal(fish)
+	}
+
+	metrics.Drift20m = driftMA(instaBuy20m, a.indicatorConfig.DriftWindow)
+	metrics.Drift1h = driftMA(instaBuyPrices1h, a.indicatorConfig.DriftWindow)
+	metrics.Drift24h = driftMA(instaBuyPrices24h, a.indicatorConfig.DriftWindow)
+
+	// Calculate 1h trends using the EMA crossover (or, if configured,
+	// Heikin-Ashi candles -- see IndicatorConfig.UseHeikinAshi).
+	// InstaBuyPriceTrend1h is the primary signal surfaced downstream, so its
+	// crossover strength (and, in HA mode, streak length) is also recorded.
 	if len(instaBuyPrices1h) >= 3 {
-		metrics.InstaBuyPriceTrend1h = calculateTrend(timestamps1h, instaBuyPrices1h)
+		if a.indicatorConfig.UseHeikinAshi {
+			metrics.InstaBuyPriceTrend1h, metrics.TrendStrength, metrics.HAStreak1h = calculateTrendHA(instaBuyPrices1h, lowPrices1h, a.indicatorConfig)
+		} else {
+			metrics.InstaBuyPriceTrend1h, metrics.TrendStrength = calculateTrend(timestamps1h, instaBuyPrices1h, a.indicatorConfig)
+		}
 	} else {
 		metrics.InstaBuyPriceTrend1h = "flat"
 	}
 
 	if len(instaSellPrices1h) >= 3 {
-		metrics.InstaSellPriceTrend1h = calculateTrend(timestamps1h, instaSellPrices1h)
+		metrics.InstaSellPriceTrend1h, _ = calculateTrend(timestamps1h, instaSellPrices1h, a.indicatorConfig)
 	} else {
 		metrics.InstaSellPriceTrend1h = "flat"
 	}
 
-	// Calculate 24h trends using linear regression
+	// Calculate 24h trends using the EMA crossover.
 	if len(instaBuyPrices24h) >= 3 {
-		metrics.InstaBuyPriceTrend24h = calculateTrend(timestamps24h, instaBuyPrices24h)
+		metrics.InstaBuyPriceTrend24h, _ = calculateTrend(timestamps24h, instaBuyPrices24h, a.indicatorConfig)
 	} else {
 		metrics.InstaBuyPriceTrend24h = "flat"
 	}
 
 	if len(instaSellPrices24h) >= 3 {
-		metrics.InstaSellPriceTrend24h = calculateTrend(timestamps24h, instaSellPrices24h)
+		metrics.InstaSellPriceTrend24h, _ = calculateTrend(timestamps24h, instaSellPrices24h, a.indicatorConfig)
 	} else {
 		metrics.InstaSellPriceTrend24h = "flat"
 	}
 
+	metrics = applyATRTrend(metrics, instaBuyPrices24h, instaSellPrices24h)
+
+	// Bucketed ATR (see VolumeMetrics.AvgTrueRange1h), using each bucket's
+	// own avgHighPrice as the "previous close" (see ATR's doc comment).
+	metrics.AvgTrueRange1h = ATR(instaBuyPrices1h, lowPrices1h, instaBuyPrices1h, a.indicatorConfig.ATRWindow)
+	metrics.AvgTrueRange24h = ATR(instaBuyPrices24h, lowPrices24h, instaBuyPrices24h, a.indicatorConfig.ATRWindow)
+
 	return metrics
 }
 
 // calculate24hMetrics processes 24h data for long-term week and month trend analysis
-func (a *Analyzer) calculate24hMetrics(dataSlice []interface{}, metrics VolumeMetrics) VolumeMetrics {
+func (a *Analyzer) calculate24hMetrics(itemID int, dataSlice []interface{}, metrics VolumeMetrics) VolumeMetrics {
 	now := time.Now().Unix()
 	window1w := now - (7 * 24 * 60 * 60)  // 1 week ago
 	window1m := now - (30 * 24 * 60 * 60) // 1 month ago
@@ -396,86 +532,136 @@ func (a *Analyzer) calculate24hMetrics(dataSlice []interface{}, metrics VolumeMe
 		timestamps1m, instaBuyPrices1m, instaSellPrices1m []float64
 	)
 
-	for _, item := range dataSlice {
-		if dataPoint, ok := item.(map[string]interface{}); ok {
-			timestamp := int64(dataPoint["timestamp"].(float64))
+	points := parseSeriesPoints(dataSlice)
+	metrics.Notes = append(metrics.Notes, detectDataQualityNotes(points, 24*time.Hour, a.dataQualityConfig)...)
+	if a.dataQualityConfig.Sanitize {
+		points = sanitizeSeriesPoints(points, a.dataQualityConfig)
+	}
+
+	for _, p := range points {
+		timestamp := p.Timestamp
+		avgHigh, avgLow := p.AvgHigh, p.AvgLow
+
+		a.store.Append(itemID, store.Interval24h, store.DataPoint{
+			Timestamp:      time.Unix(timestamp, 0).UTC(),
+			InstaBuyPrice:  int(avgHigh),
+			InstaSellPrice: int(avgLow),
+		})
 
-			var avgHigh, avgLow float64
-			if val, exists := dataPoint["avgHighPrice"]; exists && val != nil {
-				avgHigh = val.(float64)
+		// 1-week window for weekly trend analysis
+		if timestamp >= window1w {
+			if avgHigh > 0 {
+				timestamps1w = append(timestamps1w, float64(timestamp))
+				instaBuyPrices1w = append(instaBuyPrices1w, avgHigh)
 			}
-			if val, exists := dataPoint["avgLowPrice"]; exists && val != nil {
-				avgLow = val.(float64)
+			if avgLow > 0 {
+				instaSellPrices1w = append(instaSellPrices1w, avgLow)
 			}
+		}
 
-			// 1-week window for weekly trend analysis
-			if timestamp >= window1w {
-				if avgHigh > 0 {
-					timestamps1w = append(timestamps1w, float64(timestamp))
-					instaBuyPrices1w = append(instaBuyPrices1w, avgHigh)
-				}
-				if avgLow > 0 {
-					instaSellPrices1w = append(instaSellPrices1w, avgLow)
-				}
+		// 1-month window for monthly trend analysis
+		if timestamp >= window1m {
+			if avgHigh > 0 {
+				timestamps1m = append(timestamps1m, float64(timestamp))
+				instaBuyPrices1m = append(instaBuyPrices1m, avgHigh)
 			}
-
-			// 1-month window for monthly trend analysis
-			if timestamp >= window1m {
-				if avgHigh > 0 {
-					timestamps1m = append(timestamps1m, float64(timestamp))
-					instaBuyPrices1m = append(instaBuyPrices1m, avgHigh)
-				}
-				if avgLow > 0 {
-					instaSellPrices1m = append(instaSellPrices1m, avgLow)
-				}
+			if avgLow > 0 {
+				instaSellPrices1m = append(instaSellPrices1m, avgLow)
 			}
 		}
 	}
 
-	// Calculate 1w trends using linear regression
+	// Calculate 1w trends using the EMA crossover.
 	if len(instaBuyPrices1w) >= 3 {
-		metrics.InstaBuyPriceTrend1w = calculateTrend(timestamps1w, instaBuyPrices1w)
+		metrics.InstaBuyPriceTrend1w, _ = calculateTrend(timestamps1w, instaBuyPrices1w, a.indicatorConfig)
 	} else {
 		metrics.InstaBuyPriceTrend1w = "flat"
 	}
 
 	if len(instaSellPrices1w) >= 3 {
-		metrics.InstaSellPriceTrend1w = calculateTrend(timestamps1w, instaSellPrices1w)
+		metrics.InstaSellPriceTrend1w, _ = calculateTrend(timestamps1w, instaSellPrices1w, a.indicatorConfig)
 	} else {
 		metrics.InstaSellPriceTrend1w = "flat"
 	}
 
-	// Calculate 1m trends using linear regression
+	// Calculate 1m trends using the EMA crossover.
 	if len(instaBuyPrices1m) >= 3 {
-		metrics.InstaBuyPriceTrend1m = calculateTrend(timestamps1m, instaBuyPrices1m)
+		metrics.InstaBuyPriceTrend1m, _ = calculateTrend(timestamps1m, instaBuyPrices1m, a.indicatorConfig)
 	} else {
 		metrics.InstaBuyPriceTrend1m = "flat"
 	}
 
 	if len(instaSellPrices1m) >= 3 {
-		metrics.InstaSellPriceTrend1m = calculateTrend(timestamps1m, instaSellPrices1m)
+		metrics.InstaSellPriceTrend1m, _ = calculateTrend(timestamps1m, instaSellPrices1m, a.indicatorConfig)
 	} else {
 		metrics.InstaSellPriceTrend1m = "flat"
 	}
 
+	metrics.Drift1w = driftMA(instaBuyPrices1w, a.indicatorConfig.DriftWindow)
+	metrics.Drift1m = driftMA(instaBuyPrices1m, a.indicatorConfig.DriftWindow)
+
+	return metrics
+}
+
+// applyATRTrend augments metrics with an ATR/Elliott-Wave trend signal over
+// the 24h insta-buy/insta-sell series, a volatility-aware complement to the
+// flat-threshold trends calculated above. Needs at least the ATR period's
+// worth of samples to produce a meaningful read.
+func applyATRTrend(metrics VolumeMetrics, instaBuyPrices24h, instaSellPrices24h []float64) VolumeMetrics {
+	if len(instaBuyPrices24h) < 14 || len(instaBuyPrices24h) != len(instaSellPrices24h) {
+		return metrics
+	}
+
+	signal := ClassifyTrendATR(instaBuyPrices24h, instaSellPrices24h, instaBuyPrices24h)
+	metrics.ATR24h = signal.ATR
+	metrics.WaveTrend24h = signal.Label
 	return metrics
 }
 
-// getTopItemIDs returns item IDs sorted by flip efficiency for volume analysis
-func (a *Analyzer) getTopItemIDs(maxItems int) []int {
+// sortByRiskAdjustedEfficiency selects RiskAdjustedEfficiency as
+// getTopItemIDs' sort key instead of the default FlipEfficiency.
+const sortByRiskAdjustedEfficiency = "risk_adjusted_efficiency"
+
+// sortByModelScore selects the trained flip model's predicted return (see
+// Analyzer.TrainFlipModel/ScoreItems) as getTopItemIDs' sort key.
+const sortByModelScore = "model_score"
+
+// getTopItemIDs returns item IDs sorted by flip efficiency for volume
+// analysis. sortBy selects the ranking key: "" (or any other value)
+// defaults to FlipEfficiency; sortByRiskAdjustedEfficiency ranks by
+// RiskAdjustedEfficiency instead, falling back to FlipEfficiency for items
+// that haven't had volume data (and thus AvgTrueRange1h) loaded yet;
+// sortByModelScore ranks by the trained flip model's predicted return,
+// falling back to FlipEfficiency if no model has been trained or loaded.
+// Items the analyzer's portfolio already holds more than maxOpenGPPerItem
+// GP of are skipped entirely (see SetPortfolio).
+func (a *Analyzer) getTopItemIDs(maxItems int, sortBy string) []int {
 	// Filter items with meaningful data
 	var candidates []ItemData
 	for _, item := range a.items {
 		if item.InstaBuyPrice != nil && item.InstaSellPrice != nil &&
 			item.MarginGP > 100 && item.BuyLimit > 0 {
+			if a.portfolio != nil && a.maxOpenGPPerItem > 0 && a.portfolio.OpenGP(item.ItemID) >= a.maxOpenGPPerItem {
+				continue
+			}
 			candidates = append(candidates, item)
 		}
 	}
 
-	// Sort by flip efficiency
+	rankOf := func(item ItemData) float64 {
+		if sortBy == sortByModelScore && a.flipModel != nil {
+			return a.flipModel.Predict(factors.DefaultFactors(), a.flipFeaturesFromItem(item))
+		}
+		if sortBy == sortByRiskAdjustedEfficiency && item.RiskAdjustedEfficiency != nil {
+			return *item.RiskAdjustedEfficiency
+		}
+		return item.FlipEfficiency
+	}
+
+	// Sort by the selected ranking key
 	for i := 0; i < len(candidates)-1; i++ {
 		for j := i + 1; j < len(candidates); j++ {
-			if candidates[i].FlipEfficiency < candidates[j].FlipEfficiency {
+			if rankOf(candidates[i]) < rankOf(candidates[j]) {
 				candidates[i], candidates[j] = candidates[j], candidates[i]
 			}
 		}
@@ -518,9 +704,41 @@ func (a *Analyzer) updateItemsWithVolumeData(volumeData map[int]VolumeMetrics) {
 			item.AvgInstaSellPrice24h = &metrics.AvgInstaSellPrice24h
 			item.AvgMarginGP24h = &metrics.AvgMarginGP24h
 
+			// Update VWAP metrics and derive the flip signal: a live margin
+			// that sits more than VWAPSignalSigma standard deviations above
+			// the volume-weighted 24h spread is an outlier worth flagging,
+			// treating the buy-side and sell-side price stddevs as
+			// independent (margin variance = sum of the two).
+			item.VWAPInstaBuy1h = &metrics.VWAPInstaBuy1h
+			item.VWAPInstaSell1h = &metrics.VWAPInstaSell1h
+			item.VWAPInstaBuy4h = &metrics.VWAPInstaBuy4h
+			item.VWAPInstaSell4h = &metrics.VWAPInstaSell4h
+			item.VWAPInstaBuy24h = &metrics.VWAPInstaBuy24h
+			item.VWAPInstaSell24h = &metrics.VWAPInstaSell24h
+			if metrics.VWAPSpread24h != 0 {
+				item.VWAPSpread24h = &metrics.VWAPSpread24h
+
+				deviation := (float64(item.MarginGP) - metrics.VWAPSpread24h) / metrics.VWAPSpread24h
+				item.PriceVsVWAPDeviation24h = &deviation
+
+				marginStdDev := math.Sqrt(
+					metrics.InstaBuyPriceStats24h.StdDev*metrics.InstaBuyPriceStats24h.StdDev +
+						metrics.InstaSellPriceStats24h.StdDev*metrics.InstaSellPriceStats24h.StdDev,
+				)
+				if marginStdDev > 0 {
+					liveMargin := float64(item.MarginGP)
+					threshold := a.indicatorConfig.VWAPSignalSigma * marginStdDev
+					item.VWAPFlipSignal = liveMargin-metrics.VWAPSpread24h > threshold
+				}
+			}
+
 			// Update trends (all time periods)
 			if metrics.InstaBuyPriceTrend1h != "" {
 				item.InstaBuyPriceTrend1h = &metrics.InstaBuyPriceTrend1h
+				item.TrendStrength = &metrics.TrendStrength
+				if a.indicatorConfig.UseHeikinAshi {
+					item.HAStreak1h = &metrics.HAStreak1h
+				}
 			}
 			if metrics.InstaSellPriceTrend1h != "" {
 				item.InstaSellPriceTrend1h = &metrics.InstaSellPriceTrend1h
@@ -543,6 +761,38 @@ func (a *Analyzer) updateItemsWithVolumeData(volumeData map[int]VolumeMetrics) {
 			if metrics.InstaSellPriceTrend1m != "" {
 				item.InstaSellPriceTrend1m = &metrics.InstaSellPriceTrend1m
 			}
+
+			if metrics.WaveTrend24h != "" {
+				item.ATR24h = &metrics.ATR24h
+				item.WaveTrend24h = &metrics.WaveTrend24h
+			}
+
+			item.AvgTrueRange1h = &metrics.AvgTrueRange1h
+			item.AvgTrueRange24h = &metrics.AvgTrueRange24h
+			if metrics.AvgTrueRange1h > 0 {
+				riskAdjusted := float64(item.MarginGP) / metrics.AvgTrueRange1h
+				item.RiskAdjustedEfficiency = &riskAdjusted
+			}
+
+			item.InstaBuyPriceStats1h = &metrics.InstaBuyPriceStats1h
+			item.InstaSellPriceStats1h = &metrics.InstaSellPriceStats1h
+			item.InstaBuyVolumeStats1h = &metrics.InstaBuyVolumeStats1h
+			item.InstaSellVolumeStats1h = &metrics.InstaSellVolumeStats1h
+			item.InstaBuyPriceStats24h = &metrics.InstaBuyPriceStats24h
+			item.InstaSellPriceStats24h = &metrics.InstaSellPriceStats24h
+			item.InstaBuyVolumeStats24h = &metrics.InstaBuyVolumeStats24h
+			item.InstaSellVolumeStats24h = &metrics.InstaSellVolumeStats24h
+
+			item.FisherTransform1h = &metrics.FisherTransform1h
+			if metrics.InstaBuyReversalSignal != "" {
+				item.InstaBuyReversalSignal = &metrics.InstaBuyReversalSignal
+			}
+
+			item.Drift20m = &metrics.Drift20m
+			item.Drift1h = &metrics.Drift1h
+			item.Drift24h = &metrics.Drift24h
+			item.Drift1w = &metrics.Drift1w
+			item.Drift1m = &metrics.Drift1m
 		}
 	}
 }
@@ -560,59 +810,180 @@ func average(values []float64) float64 {
 	return sum / float64(len(values))
 }
 
-// calculateTrend performs linear regression to determine price trend
-// This matches the Python implementation logic
-func calculateTrend(x, y []float64) string {
-	// Need at least 3 points for a meaningful trend (matching Python)
-	if len(x) < 3 || len(x) != len(y) {
-		return "flat"
+// computeStats summarizes values into DistributionStats: Mean/StdDev via
+// Welford's online algorithm (one pass, numerically stable) and percentiles
+// via nearest-rank on a sorted copy. An empty slice returns the zero value.
+func computeStats(values []float64) DistributionStats {
+	if len(values) == 0 {
+		return DistributionStats{}
+	}
+
+	var mean, m2 float64
+	min, max := values[0], values[0]
+	for i, v := range values {
+		n := float64(i + 1)
+		delta := v - mean
+		mean += delta / n
+		m2 += delta * (v - mean)
+
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	var stdDev float64
+	if len(values) > 1 {
+		stdDev = math.Sqrt(m2 / float64(len(values)))
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	return DistributionStats{
+		Mean:   mean,
+		StdDev: stdDev,
+		Min:    min,
+		Max:    max,
+		P25:    percentile(sorted, 0.25),
+		P50:    percentile(sorted, 0.50),
+		P75:    percentile(sorted, 0.75),
+		P95:    percentile(sorted, 0.95),
+	}
+}
+
+// percentile returns the nearest-rank pth percentile of sorted, which must
+// already be sorted ascending and non-empty.
+func percentile(sorted []float64, p float64) float64 {
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx > len(sorted)-1 {
+		idx = len(sorted) - 1
 	}
+	return sorted[idx]
+}
+
+// IndicatorConfig tunes calculateTrend's EMA crossover. A fast EMA reacting
+// quickly to recent prices is compared against a slow EMA tracking the
+// broader trend -- the fast one pulling away from the slow one signals
+// acceleration, which a flat %-change-over-the-period threshold (the
+// previous approach) can't distinguish from noise.
+type IndicatorConfig struct {
+	// FastSpan and SlowSpan are the EMA spans (in samples) for the fast and
+	// slow moving averages; each uses the standard alpha = 2/(span+1).
+	FastSpan int
+	SlowSpan int
+	// TrendThresholdPct is how far the fast EMA must sit above or below the
+	// slow EMA, as a fraction of the slow EMA (e.g. 0.005 for 0.5%), before
+	// the crossover counts as "increasing"/"decreasing" rather than "flat".
+	TrendThresholdPct float64
+	// ATRWindow is the Wilder smoothing period (see the package-level ATR
+	// function) used for VolumeMetrics.AvgTrueRange1h/24h.
+	ATRWindow int
+	// FisherWindow is the rolling lookback (see the package-level
+	// fisherTransform function) used for VolumeMetrics.FisherTransform1h.
+	FisherWindow int
+	// DriftWindow is the WMA/Fisher-rescale lookback (see the package-level
+	// driftMA function) used for VolumeMetrics.Drift20m/1h/24h/1w/1m.
+	DriftWindow int
+	// VWAPSignalSigma is how many standard deviations of the 24h price
+	// distributions a live margin must exceed VWAPSpread24h by before
+	// ItemData.VWAPFlipSignal flags it (see Analyzer.updateItemsWithVolumeData).
+	VWAPSignalSigma float64
+	// UseHeikinAshi switches InstaBuyPriceTrend1h from calculateTrend's raw
+	// EMA crossover to calculateTrendHA's Heikin-Ashi-smoothed candles (see
+	// HeikinAshi), which don't flip label on one volatile print the way a
+	// raw close can. Off by default so existing trend strings are
+	// unaffected; when on, VolumeMetrics.HAStreak1h is also populated.
+	UseHeikinAshi bool
+}
 
-	// Check for empty values
-	if len(y) == 0 {
-		return "flat"
+// DefaultIndicatorConfig returns the default EMA crossover tuning: a
+// 5-sample fast EMA, a 20-sample slow EMA, a 0.5% crossover threshold, a
+// 14-period ATR window, a 10-sample Fisher Transform window, a 10-sample
+// drift window, and a 2-sigma VWAP flip-signal threshold.
+func DefaultIndicatorConfig() IndicatorConfig {
+	return IndicatorConfig{
+		FastSpan:          5,
+		SlowSpan:          20,
+		TrendThresholdPct: 0.005,
+		ATRWindow:         14,
+		FisherWindow:      10,
+		DriftWindow:       10,
+		VWAPSignalSigma:   2.0,
+	}
+}
+
+// calculateTrend classifies a price series' direction via a fast/slow EMA
+// crossover (see IndicatorConfig and indicator.EMA), seeding each EMA with
+// y[0] and folding the rest of the series in. It also returns the
+// crossover's strength, (fastEMA-slowEMA)/slowEMA, so callers can sort or
+// filter on a continuous signal instead of just the three-way label.
+func calculateTrend(x, y []float64, cfg IndicatorConfig) (string, float64) {
+	// Need at least 3 points for a meaningful trend.
+	if len(x) < 3 || len(x) != len(y) {
+		return "flat", 0
 	}
 
-	n := float64(len(x))
-	sumX, sumY, sumXY, sumX2 := 0.0, 0.0, 0.0, 0.0
+	fast := indicator.NewEMA(cfg.FastSpan)
+	slow := indicator.NewEMA(cfg.SlowSpan)
 
-	for i := 0; i < len(x); i++ {
-		sumX += x[i]
-		sumY += y[i]
-		sumXY += x[i] * y[i]
-		sumX2 += x[i] * x[i]
+	var fastEMA, slowEMA float64
+	for _, v := range y {
+		fastEMA = fast.Update(v)
+		slowEMA = slow.Update(v)
 	}
 
-	// Calculate slope using linear regression (m = (n*ΣXY - ΣX*ΣY) / (n*ΣX² - (ΣX)²))
-	numerator := n*sumXY - sumX*sumY
-	denominator := n*sumX2 - sumX*sumX
+	if slowEMA == 0 {
+		return "flat", 0
+	}
 
-	if denominator == 0 {
-		return "flat"
+	strength := (fastEMA - slowEMA) / slowEMA
+	switch {
+	case strength > cfg.TrendThresholdPct:
+		return "increasing", strength
+	case strength < -cfg.TrendThresholdPct:
+		return "decreasing", strength
+	default:
+		return "flat", strength
 	}
+}
 
-	slope := numerator / denominator
+// calculateTrendHA classifies direction from the sign and streak length of
+// the most recent Heikin-Ashi candle's close-open delta (see HeikinAshi),
+// the IndicatorConfig.UseHeikinAshi alternative to calculateTrend's raw EMA
+// crossover. This package's 1h series only has two price sides per point
+// (insta_buy high, insta_sell low), not a separately-sampled open/close, so
+// high doubles as each candle's raw close and low as its raw open --
+// mirroring how ATR already treats these two series as a high/low/close
+// triple (see calculateVolumeMetrics' AvgTrueRange1h).
+func calculateTrendHA(high, low []float64, cfg IndicatorConfig) (label string, strength float64, streak int) {
+	if len(high) < 3 || len(high) != len(low) {
+		return "flat", 0, 0
+	}
 
-	// Calculate percentage change over the period (matching Python logic)
-	var pctChange float64
-	if len(y) > 1 && y[0] != 0 {
-		pctChange = (y[len(y)-1] - y[0]) / y[0] * 100
+	candles := HeikinAshi(low, high, low, high)
+	if len(candles) == 0 {
+		return "flat", 0, 0
 	}
 
-	// Determine trend based on slope and percent change
-	// Less than 1% change is considered flat (matching Python threshold)
-	if math.Abs(pctChange) < 1.0 {
-		return "flat"
-	} else if math.Abs(pctChange) >= 10.0 {
-		// Sharp moves: 10% or more
-		if slope > 0 {
-			return "sharp increase"
-		} else {
-			return "sharp decrease"
-		}
-	} else if slope > 0 {
-		return "increasing"
-	} else {
-		return "decreasing"
+	last := candles[len(candles)-1]
+	if last.Open != 0 {
+		strength = (last.Close - last.Open) / math.Abs(last.Open)
+	}
+	streak = HAStreak(candles)
+
+	switch {
+	case strength > cfg.TrendThresholdPct:
+		label = "increasing"
+	case strength < -cfg.TrendThresholdPct:
+		label = "decreasing"
+	default:
+		label = "flat"
 	}
+	return label, strength, streak
 }
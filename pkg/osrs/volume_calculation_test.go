@@ -60,7 +60,7 @@ func TestVolumeCalculationWith24hData(t *testing.T) {
 	}
 
 	// Process the test data - pass as 5m data since that's where 24h volumes are calculated
-	metrics := analyzer.processTimeseriesData(testData, map[string]interface{}{})
+	metrics := analyzer.processTimeseriesData(1, testData, map[string]interface{}{})
 
 	t.Logf("Volume calculation results:")
 	t.Logf("  InstaBuyVolume24h: %.2f", metrics.InstaBuyVolume24h)
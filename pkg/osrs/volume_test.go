@@ -1,103 +1,152 @@
 package osrs
 
 import (
-	"context"
 	"math"
 	"testing"
 	"time"
+
+	"osrs-flipping/pkg/osrs/store"
 )
 
 func TestCalculateTrend(t *testing.T) {
+	defaultCfg := DefaultIndicatorConfig()
+
 	tests := []struct {
 		name     string
 		x        []float64
 		y        []float64
+		cfg      IndicatorConfig
 		expected string
 	}{
 		{
 			name:     "empty data",
 			x:        []float64{},
 			y:        []float64{},
+			cfg:      defaultCfg,
 			expected: "flat",
 		},
 		{
 			name:     "insufficient data points",
 			x:        []float64{1, 2},
 			y:        []float64{100, 102},
+			cfg:      defaultCfg,
 			expected: "flat",
 		},
 		{
 			name:     "mismatched lengths",
 			x:        []float64{1, 2, 3},
 			y:        []float64{100, 102},
+			cfg:      defaultCfg,
 			expected: "flat",
 		},
 		{
-			name:     "clearly increasing trend",
-			x:        []float64{1, 2, 3, 4, 5},
-			y:        []float64{100, 105, 110, 115, 120}, // 20% increase
-			expected: "increasing",
-		},
-		{
-			name:     "clearly decreasing trend",
-			x:        []float64{1, 2, 3, 4, 5},
-			y:        []float64{120, 115, 110, 105, 100}, // 16.7% decrease
-			expected: "decreasing",
-		},
-		{
-			name:     "flat trend - small changes",
-			x:        []float64{1, 2, 3, 4, 5},
-			y:        []float64{1000, 1005, 1000, 1002, 1001}, // 0.1% change
-			expected: "flat",
-		},
-		{
-			name:     "exactly 1% threshold should be flat",
+			name:     "all same values",
 			x:        []float64{1, 2, 3, 4, 5},
-			y:        []float64{1000, 1002, 1004, 1006, 1008}, // 0.8% change
+			y:        []float64{1000, 1000, 1000, 1000, 1000},
+			cfg:      defaultCfg,
 			expected: "flat",
 		},
 		{
-			name:     "slightly increasing above 1% threshold",
-			x:        []float64{1, 2, 3, 4, 5},
-			y:        []float64{1000, 1005, 1008, 1009, 1011}, // 1.1% change
+			name: "clearly increasing trend, default spans",
+			x:    []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			y:    []float64{1000, 1005, 1010, 1016, 1021, 1025, 1030, 1034, 1040, 1045},
+			cfg:  defaultCfg,
+			// fast EMA (span 5) pulls ahead of slow EMA (span 20) by >0.5%
 			expected: "increasing",
 		},
 		{
-			name:     "slightly decreasing above 1% threshold",
-			x:        []float64{1, 2, 3, 4, 5},
-			y:        []float64{1000, 995, 992, 991, 989}, // 1.1% decrease
+			name: "clearly decreasing trend, default spans",
+			x:    []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			y:    []float64{1045, 1040, 1034, 1030, 1025, 1021, 1016, 1010, 1005, 1000},
+			cfg:  defaultCfg,
 			expected: "decreasing",
 		},
 		{
-			name:     "volatile but overall flat",
-			x:        []float64{1, 2, 3, 4, 5, 6, 7},
-			y:        []float64{1000, 1020, 980, 1030, 970, 1010, 1005}, // 0.5% overall change
+			name:     "volatile but overall flat, default spans",
+			x:        []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			y:        []float64{1000, 1002, 999, 1003, 998, 1004, 997, 1005, 996, 1001},
+			cfg:      defaultCfg,
 			expected: "flat",
 		},
 		{
-			name:     "zero starting value",
-			x:        []float64{1, 2, 3, 4, 5},
-			y:        []float64{0, 1, 2, 3, 4},
-			expected: "flat", // Should handle division by zero
+			// FastSpan=1 gives alpha=1, so the fast EMA collapses to the raw
+			// last value with no smoothing at all -- the alpha edge case.
+			name: "fast span of 1 tracks the raw series exactly",
+			x:    []float64{1, 2, 3, 4, 5},
+			y:    []float64{100, 100, 100, 100, 200},
+			cfg:  IndicatorConfig{FastSpan: 1, SlowSpan: 5, TrendThresholdPct: 0.005},
+			// fast EMA = 200 (the raw last tick), slow EMA ~= 133.3 -> >0.5% above
+			expected: "increasing",
 		},
 		{
-			name:     "all same values",
-			x:        []float64{1, 2, 3, 4, 5},
-			y:        []float64{1000, 1000, 1000, 1000, 1000},
+			// A run of flat prices lets the slow EMA converge near the base
+			// price, so a fast (span 1) final tick isolates exactly how far
+			// past the threshold a single tick needs to move.
+			name:     "just above the 0.5% crossover threshold",
+			x:        sequence(51),
+			y:        append(repeat(100, 50), 100.6),
+			cfg:      IndicatorConfig{FastSpan: 1, SlowSpan: 20, TrendThresholdPct: 0.005},
+			expected: "increasing",
+		},
+		{
+			name:     "just below the 0.5% crossover threshold",
+			x:        sequence(51),
+			y:        append(repeat(100, 50), 100.3),
+			cfg:      IndicatorConfig{FastSpan: 1, SlowSpan: 20, TrendThresholdPct: 0.005},
 			expected: "flat",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := calculateTrend(tt.x, tt.y)
+			result, _ := calculateTrend(tt.x, tt.y, tt.cfg)
 			if result != tt.expected {
-				t.Errorf("calculateTrend(%v, %v) = %v, want %v", tt.x, tt.y, result, tt.expected)
+				t.Errorf("calculateTrend(%v, %v, %+v) = %v, want %v", tt.x, tt.y, tt.cfg, result, tt.expected)
 			}
 		})
 	}
 }
 
+// sequence and repeat build test fixtures for calculateTrend's EMA-crossover
+// boundary cases, where y needs enough leading samples for the slow EMA to
+// converge before a single final tick is checked against the threshold.
+func sequence(n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = float64(i + 1)
+	}
+	return s
+}
+
+func repeat(value float64, n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = value
+	}
+	return s
+}
+
+func TestCalculateTrendHA(t *testing.T) {
+	cfg := DefaultIndicatorConfig()
+
+	if label, strength, streak := calculateTrendHA([]float64{100, 102}, []float64{98, 100}, cfg); label != "flat" || strength != 0 || streak != 0 {
+		t.Errorf("insufficient data: got (%q, %v, %d), want (\"flat\", 0, 0)", label, strength, streak)
+	}
+
+	high := []float64{100, 102, 104, 106, 110}
+	low := []float64{98, 100, 102, 104, 108}
+	label, strength, streak := calculateTrendHA(high, low, cfg)
+	if label != "increasing" {
+		t.Errorf("label = %q, want \"increasing\" for a steadily rising high/low series", label)
+	}
+	if strength <= cfg.TrendThresholdPct {
+		t.Errorf("strength = %v, want it above the %v threshold", strength, cfg.TrendThresholdPct)
+	}
+	if streak != 5 {
+		t.Errorf("streak = %d, want 5 (every HA candle in a steady rise closes above its open)", streak)
+	}
+}
+
 func TestAverage(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -141,6 +190,71 @@ func TestAverage(t *testing.T) {
 	}
 }
 
+func TestComputeStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		expected DistributionStats
+	}{
+		{
+			name:     "empty slice",
+			values:   []float64{},
+			expected: DistributionStats{},
+		},
+		{
+			name:   "single value",
+			values: []float64{42.5},
+			expected: DistributionStats{
+				Mean: 42.5, StdDev: 0, Min: 42.5, Max: 42.5,
+				P25: 42.5, P50: 42.5, P75: 42.5, P95: 42.5,
+			},
+		},
+		{
+			name:   "constant series has zero stddev",
+			values: repeat(10, 20),
+			expected: DistributionStats{
+				Mean: 10, StdDev: 0, Min: 10, Max: 10,
+				P25: 10, P50: 10, P75: 10, P95: 10,
+			},
+		},
+		{
+			name:   "percentile boundary cases over 1..100",
+			values: makeRange(1, 100),
+			expected: DistributionStats{
+				Mean: 50.5, StdDev: math.Sqrt(833.25), Min: 1, Max: 100,
+				P25: 25, P50: 50, P75: 75, P95: 95,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := computeStats(tt.values)
+			if math.Abs(result.Mean-tt.expected.Mean) > 1e-9 {
+				t.Errorf("Mean = %v, want %v", result.Mean, tt.expected.Mean)
+			}
+			if math.Abs(result.StdDev-tt.expected.StdDev) > 1e-6 {
+				t.Errorf("StdDev = %v, want %v", result.StdDev, tt.expected.StdDev)
+			}
+			if result.Min != tt.expected.Min || result.Max != tt.expected.Max {
+				t.Errorf("Min/Max = %v/%v, want %v/%v", result.Min, result.Max, tt.expected.Min, tt.expected.Max)
+			}
+			if result.P25 != tt.expected.P25 || result.P50 != tt.expected.P50 || result.P75 != tt.expected.P75 || result.P95 != tt.expected.P95 {
+				t.Errorf("percentiles = %+v, want P25=%v P50=%v P75=%v P95=%v", result, tt.expected.P25, tt.expected.P50, tt.expected.P75, tt.expected.P95)
+			}
+		})
+	}
+}
+
+// makeRange returns [start, start+1, ..., end] as a float64 slice.
+func makeRange(start, end int) []float64 {
+	s := make([]float64, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		s = append(s, float64(i))
+	}
+	return s
+}
+
 func TestMin(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -164,52 +278,6 @@ func TestMin(t *testing.T) {
 	}
 }
 
-func TestRateLimiter(t *testing.T) {
-	t.Run("basic rate limiting", func(t *testing.T) {
-		// Create a rate limiter that allows 10 requests per second
-		rl := NewRateLimiter(10.0)
-
-		// Should have initial tokens
-		if rl.tokens != rl.maxTokens {
-			t.Errorf("Initial tokens = %d, want %d", rl.tokens, rl.maxTokens)
-		}
-
-		ctx := context.Background()
-
-		// First request should pass immediately
-		start := time.Now()
-		err := rl.Wait(ctx)
-		elapsed := time.Since(start)
-
-		if err != nil {
-			t.Errorf("First request failed: %v", err)
-		}
-
-		if elapsed > 10*time.Millisecond {
-			t.Errorf("First request took too long: %v", elapsed)
-		}
-	})
-
-	t.Run("rate limiting with context cancellation", func(t *testing.T) {
-		rl := NewRateLimiter(0.5) // Very slow: 1 request per 2 seconds
-
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
-		defer cancel()
-
-		// First request consumes the initial token
-		err := rl.Wait(ctx)
-		if err != nil {
-			t.Errorf("First request failed: %v", err)
-		}
-
-		// Second request should timeout due to context cancellation
-		err = rl.Wait(ctx)
-		if err != context.DeadlineExceeded {
-			t.Errorf("Expected context deadline exceeded, got: %v", err)
-		}
-	})
-}
-
 func TestProcessTimeseriesData(t *testing.T) {
 	analyzer := &Analyzer{}
 
@@ -217,7 +285,7 @@ func TestProcessTimeseriesData(t *testing.T) {
 		data5m := map[string]interface{}{}
 		data24h := map[string]interface{}{}
 
-		metrics := analyzer.processTimeseriesData(data5m, data24h)
+		metrics := analyzer.processTimeseriesData(1, data5m, data24h)
 
 		// Should return zero values
 		if metrics.InstaBuyVolume20m != 0 {
@@ -263,7 +331,7 @@ func TestProcessTimeseriesData(t *testing.T) {
 			},
 		}
 
-		metrics := analyzer.processTimeseriesData(data5m, data24h)
+		metrics := analyzer.processTimeseriesData(1, data5m, data24h)
 
 		// Should have processed the data
 		if metrics.InstaBuyVolume20m <= 0 {
@@ -272,6 +340,10 @@ func TestProcessTimeseriesData(t *testing.T) {
 		if metrics.AvgInstaBuyPrice20m <= 0 {
 			t.Errorf("Expected positive AvgInstaBuyPrice20m, got %f", metrics.AvgInstaBuyPrice20m)
 		}
+
+		for _, note := range metrics.Notes {
+			t.Logf("data quality note: %s at %s: %s", note.Kind, note.Timestamp, note.Message)
+		}
 	})
 }
 
@@ -323,7 +395,7 @@ func TestGetTopItemIDs(t *testing.T) {
 	}
 
 	t.Run("get top items", func(t *testing.T) {
-		itemIDs := analyzer.getTopItemIDs(10)
+		itemIDs := analyzer.getTopItemIDs(10, "")
 
 		// Should return items sorted by flip efficiency, filtering out invalid ones
 		expectedIDs := []int{2, 1} // Item 2 has higher efficiency than Item 1
@@ -339,7 +411,7 @@ func TestGetTopItemIDs(t *testing.T) {
 	})
 
 	t.Run("limit results", func(t *testing.T) {
-		itemIDs := analyzer.getTopItemIDs(1)
+		itemIDs := analyzer.getTopItemIDs(1, "")
 
 		// Should return only 1 item
 		if len(itemIDs) != 1 {
@@ -354,12 +426,34 @@ func TestGetTopItemIDs(t *testing.T) {
 
 	t.Run("empty analyzer", func(t *testing.T) {
 		emptyAnalyzer := &Analyzer{items: []ItemData{}}
-		itemIDs := emptyAnalyzer.getTopItemIDs(10)
+		itemIDs := emptyAnalyzer.getTopItemIDs(10, "")
 
 		if len(itemIDs) != 0 {
 			t.Errorf("Expected 0 items, got %d", len(itemIDs))
 		}
 	})
+
+	t.Run("sort by risk adjusted efficiency", func(t *testing.T) {
+		// Item 1 has lower FlipEfficiency than Item 2 but a higher
+		// RiskAdjustedEfficiency, so the two rankings should disagree.
+		analyzer.items[0].RiskAdjustedEfficiency = float64Ptr(30.0)
+		analyzer.items[1].RiskAdjustedEfficiency = float64Ptr(5.0)
+
+		itemIDs := analyzer.getTopItemIDs(10, sortByRiskAdjustedEfficiency)
+
+		expectedIDs := []int{1, 2}
+		if len(itemIDs) != len(expectedIDs) {
+			t.Fatalf("Expected %d items, got %d", len(expectedIDs), len(itemIDs))
+		}
+		for i, expectedID := range expectedIDs {
+			if itemIDs[i] != expectedID {
+				t.Errorf("Expected item ID %d at position %d, got %d", expectedID, i, itemIDs[i])
+			}
+		}
+
+		analyzer.items[0].RiskAdjustedEfficiency = nil
+		analyzer.items[1].RiskAdjustedEfficiency = nil
+	})
 }
 
 func TestUpdateItemsWithVolumeData(t *testing.T) {
@@ -408,6 +502,8 @@ func TestUpdateItemsWithVolumeData(t *testing.T) {
 			InstaSellPriceTrend1w:  "decreasing",
 			InstaBuyPriceTrend1m:   "increasing",
 			InstaSellPriceTrend1m:  "flat",
+			AvgTrueRange1h:         25.0,
+			AvgTrueRange24h:        30.0,
 		},
 	}
 
@@ -433,6 +529,15 @@ func TestUpdateItemsWithVolumeData(t *testing.T) {
 		if item.InstaSellPriceTrend24h == nil || *item.InstaSellPriceTrend24h != "increasing" {
 			t.Errorf("Expected InstaSellPriceTrend24h to be 'increasing', got %v", item.InstaSellPriceTrend24h)
 		}
+
+		// MarginGP is 50, AvgTrueRange1h is 25.0, so RiskAdjustedEfficiency should be 2.0.
+		if item.RiskAdjustedEfficiency == nil || *item.RiskAdjustedEfficiency != 2.0 {
+			t.Errorf("Expected RiskAdjustedEfficiency to be 2.0, got %v", item.RiskAdjustedEfficiency)
+		}
+
+		if item.AvgTrueRange24h == nil || *item.AvgTrueRange24h != 30.0 {
+			t.Errorf("Expected AvgTrueRange24h to be 30.0, got %v", item.AvgTrueRange24h)
+		}
 	})
 
 	t.Run("item without volume data unchanged", func(t *testing.T) {
@@ -449,6 +554,111 @@ func TestUpdateItemsWithVolumeData(t *testing.T) {
 	})
 }
 
+func TestCalculate5mMetricsVWAP(t *testing.T) {
+	analyzer := &Analyzer{indicatorConfig: DefaultIndicatorConfig()}
+	now := time.Now().Unix()
+
+	// Two buckets: a low-volume tick at a far-off price, and a high-volume
+	// tick near 1000/950. An unweighted mean would split the difference;
+	// VWAP should land close to the high-volume bucket instead.
+	dataSlice := []interface{}{
+		map[string]interface{}{
+			"timestamp":       float64(now - 300),
+			"avgHighPrice":    2000.0,
+			"avgLowPrice":     1900.0,
+			"highPriceVolume": 1.0,
+			"lowPriceVolume":  1.0,
+		},
+		map[string]interface{}{
+			"timestamp":       float64(now - 600),
+			"avgHighPrice":    1000.0,
+			"avgLowPrice":     950.0,
+			"highPriceVolume": 999.0,
+			"lowPriceVolume":  999.0,
+		},
+	}
+
+	metrics := analyzer.calculate5mMetrics(1, dataSlice, VolumeMetrics{})
+
+	wantBuy := (2000.0*1 + 1000.0*999) / 1000.0
+	if math.Abs(metrics.VWAPInstaBuy1h-wantBuy) > 0.01 {
+		t.Errorf("VWAPInstaBuy1h = %v, want %v", metrics.VWAPInstaBuy1h, wantBuy)
+	}
+	if metrics.VWAPInstaBuy1h == average([]float64{2000.0, 1000.0}) {
+		t.Error("VWAPInstaBuy1h should differ from the unweighted mean")
+	}
+
+	// Both buckets also fall within the 24h window, so VWAPSpread24h should
+	// be the same weighted buy/sell difference rather than zero.
+	wantSpread := metrics.VWAPInstaBuy24h - metrics.VWAPInstaSell24h
+	if metrics.VWAPSpread24h != wantSpread {
+		t.Errorf("VWAPSpread24h = %v, want %v", metrics.VWAPSpread24h, wantSpread)
+	}
+}
+
+func TestUpdateItemsWithVolumeDataVWAPFlipSignal(t *testing.T) {
+	buyPrice, sellPrice := 1000, 700 // a 300gp live margin
+	analyzer := &Analyzer{
+		indicatorConfig: DefaultIndicatorConfig(),
+		items: []ItemData{
+			{ItemID: 1, InstaBuyPrice: &buyPrice, InstaSellPrice: &sellPrice, MarginGP: 300},
+		},
+	}
+
+	volumeData := map[int]VolumeMetrics{
+		1: {
+			VWAPInstaBuy24h:        950.0,
+			VWAPInstaSell24h:       900.0,
+			VWAPSpread24h:          50.0, // live margin (300) is way above the historical spread (50)
+			InstaBuyPriceStats24h:  DistributionStats{StdDev: 5.0},
+			InstaSellPriceStats24h: DistributionStats{StdDev: 5.0},
+		},
+	}
+
+	analyzer.updateItemsWithVolumeData(volumeData)
+
+	item := &analyzer.items[0]
+	if item.VWAPSpread24h == nil || *item.VWAPSpread24h != 50.0 {
+		t.Fatalf("expected VWAPSpread24h to be 50.0, got %v", item.VWAPSpread24h)
+	}
+	if item.PriceVsVWAPDeviation24h == nil {
+		t.Fatal("expected PriceVsVWAPDeviation24h to be set")
+	}
+	if wantDeviation := (300.0 - 50.0) / 50.0; math.Abs(*item.PriceVsVWAPDeviation24h-wantDeviation) > 0.001 {
+		t.Errorf("PriceVsVWAPDeviation24h = %v, want %v", *item.PriceVsVWAPDeviation24h, wantDeviation)
+	}
+	if !item.VWAPFlipSignal {
+		t.Error("expected VWAPFlipSignal to fire for a margin far above the historical spread")
+	}
+}
+
+func TestTimeseriesFromStore(t *testing.T) {
+	ts := time.Now().Truncate(time.Second)
+	points := []store.DataPoint{
+		{Timestamp: ts, InstaBuyPrice: 100, InstaSellPrice: 95, InstaBuyVolume: 10, InstaSellVolume: 5},
+	}
+
+	got := timeseriesFromStore(points)
+	dataSlice, ok := got["data"].([]interface{})
+	if !ok || len(dataSlice) != 1 {
+		t.Fatalf("expected a single-element data slice, got %#v", got)
+	}
+
+	point, ok := dataSlice[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a map data point, got %#v", dataSlice[0])
+	}
+	if point["timestamp"].(float64) != float64(ts.Unix()) {
+		t.Errorf("timestamp = %v, want %v", point["timestamp"], ts.Unix())
+	}
+	if point["avgHighPrice"].(float64) != 100 || point["avgLowPrice"].(float64) != 95 {
+		t.Errorf("unexpected prices: %+v", point)
+	}
+	if point["highPriceVolume"].(float64) != 10 || point["lowPriceVolume"].(float64) != 5 {
+		t.Errorf("unexpected volumes: %+v", point)
+	}
+}
+
 // Benchmark tests
 func BenchmarkCalculateTrend(b *testing.B) {
 	x := make([]float64, 100)
@@ -459,9 +669,10 @@ func BenchmarkCalculateTrend(b *testing.B) {
 		y[i] = float64(i*2 + 1000) // Linear increasing trend
 	}
 
+	cfg := DefaultIndicatorConfig()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		calculateTrend(x, y)
+		calculateTrend(x, y, cfg)
 	}
 }
 
@@ -0,0 +1,64 @@
+package positions
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// fillRequest is the JSON body POST /positions/fill accepts.
+type fillRequest struct {
+	ItemID    int       `json:"item_id"`
+	Name      string    `json:"name"`
+	Side      string    `json:"side"`
+	Quantity  int       `json:"qty"`
+	Price     int       `json:"price"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewFillHandler returns an http.Handler for POST /positions/fill that
+// records a fill reported by an external order tracker (e.g. a user's
+// manual flip, or a future automated trader) into store.
+func NewFillHandler(store *PositionStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req fillRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		side := Side(req.Side)
+		if side != Buy && side != Sell {
+			http.Error(w, "side must be \"buy\" or \"sell\"", http.StatusBadRequest)
+			return
+		}
+		if req.ItemID == 0 || req.Quantity == 0 || req.Price == 0 {
+			http.Error(w, "item_id, qty, and price are required", http.StatusBadRequest)
+			return
+		}
+		timestamp := req.Timestamp
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+
+		fill := Fill{
+			ItemID:    req.ItemID,
+			Name:      req.Name,
+			Side:      side,
+			Quantity:  req.Quantity,
+			Price:     req.Price,
+			Timestamp: timestamp,
+		}
+		if err := store.RecordFill(r.Context(), fill); err != nil {
+			http.Error(w, "recording fill: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	})
+}
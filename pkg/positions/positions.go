@@ -0,0 +1,383 @@
+// Package positions persists the GE's open buy/sell slots and the
+// resulting round-trip trade history in Postgres, so the job executor can
+// tell the LLM what's already held instead of re-recommending it.
+//
+// This is deliberately separate from package position (a JSON-file CLI
+// tracker for manually-logged flips, see cmd/positions.go) and package
+// portfolio (the job executor's lightweight JSON/Redis exposure tracker,
+// see pkg/osrs/portfolio). Neither of those models the GE's distinct open
+// buy/open sell/filled-inventory slots or keeps a durable trades table, and
+// both predate this package's HTTP-fill-recording use case -- they stay as
+// they are for the CLI and exposure-deprioritization jobs they already
+// serve.
+package positions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"osrs-flipping/pkg/osrs/portfolio"
+)
+
+// Side is which side of the GE an order sits on.
+type Side string
+
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+// buyLimitResetEvery mirrors backtest.buyLimitResetEvery and
+// osrs/backtest/candle.go's buy-limit window: the GE resets how many units
+// of an item you may buy every 4 hours.
+const buyLimitResetEvery = 4 * time.Hour
+
+// Fill is one order fill reported to RecordFill, e.g. from a
+// POST /positions/fill request.
+type Fill struct {
+	ItemID    int
+	Name      string
+	Side      Side
+	Quantity  int
+	Price     int
+	Timestamp time.Time
+}
+
+// ClosedTrade is one completed round-trip, the same shape RecordFill writes
+// to the trades table when a sell fill closes out open buy lots.
+type ClosedTrade struct {
+	ItemID    int
+	Name      string
+	Quantity  int
+	BuyPrice  int
+	SellPrice int
+	BuyTime   time.Time
+	SellTime  time.Time
+	TaxGP     int
+	ProfitGP  int
+}
+
+// TradeStats summarizes the trades table, in the same shape
+// backtest.TradeStats emits so the two can be compared side by side --
+// except AvgHoldingTime in place of PNLPerHour, since positions has no
+// fixed replay window to divide by.
+type TradeStats struct {
+	ClosedTrades   int
+	WinRate        float64
+	AvgProfitGP    float64
+	AvgHoldingTime time.Duration
+	MaxDrawdownGP  int
+	PNLByItem      map[int]int
+}
+
+// PortfolioSummary is what FormatItemsForAnalysisV2 folds into its
+// top-level "portfolio" block: what's tied up right now, how it's doing,
+// and which items the LLM shouldn't bother recommending.
+type PortfolioSummary struct {
+	OpenSlots      int
+	GPTiedUp       map[int]int // itemID -> GP committed to open buy lots
+	UnrealizedPNL  map[int]int // itemID -> (currentPrice-avgCost)*openQty
+	ExcludeItemIDs []int       // already held, or at the 4h buy limit
+}
+
+// PositionStore is a Postgres-backed store for open GE slots and completed
+// trades, mirroring storage.QueryRepository's and collector.Repository's
+// single-pool constructor convention.
+type PositionStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPositionStore creates a new PositionStore.
+func NewPositionStore(pool *pgxpool.Pool) *PositionStore {
+	return &PositionStore{pool: pool}
+}
+
+// openLot is one still-open buy position row, the unit closeLots matches
+// sells against oldest-first -- the same FIFO shape as
+// backtest.openLot/portfolio.openLot, just sourced from a DB row instead of
+// an in-memory slice.
+type openLot struct {
+	id        int64
+	remaining int
+	price     int
+	placedAt  time.Time
+}
+
+// closeLots consumes qty units from lots oldest-first, returning the
+// portion of each lot it closed and how much qty remains unmatched (open
+// buy liquidity ran out before the sell did). It mutates nothing -- callers
+// apply the returned closures to the DB themselves.
+func closeLots(lots []openLot, qty int) (closed []openLot, remainingQty int) {
+	for _, lot := range lots {
+		if qty <= 0 {
+			break
+		}
+		take := lot.remaining
+		if take > qty {
+			take = qty
+		}
+		closed = append(closed, openLot{id: lot.id, remaining: take, price: lot.price, placedAt: lot.placedAt})
+		qty -= take
+	}
+	return closed, qty
+}
+
+// RecordFill applies fill to the open positions and, for a sell that closes
+// out one or more open buy lots, appends the resulting ClosedTrade rows to
+// trades.
+func (s *PositionStore) RecordFill(ctx context.Context, fill Fill) error {
+	switch fill.Side {
+	case Buy:
+		return s.recordBuyFill(ctx, fill)
+	case Sell:
+		return s.recordSellFill(ctx, fill)
+	default:
+		return fmt.Errorf("record fill: unknown side %q", fill.Side)
+	}
+}
+
+func (s *PositionStore) recordBuyFill(ctx context.Context, fill Fill) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO positions (item_id, name, side, quantity, filled_quantity, price, status, placed_at)
+		VALUES ($1, $2, 'buy', $3, 0, $4, 'open', $5)
+	`, fill.ItemID, fill.Name, fill.Quantity, fill.Price, fill.Timestamp)
+	if err != nil {
+		return fmt.Errorf("record buy fill: %w", err)
+	}
+	return nil
+}
+
+func (s *PositionStore) recordSellFill(ctx context.Context, fill Fill) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("record sell fill: begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, quantity - filled_quantity, price, placed_at
+		FROM positions
+		WHERE item_id = $1 AND side = 'buy' AND status = 'open'
+		ORDER BY placed_at ASC
+		FOR UPDATE
+	`, fill.ItemID)
+	if err != nil {
+		return fmt.Errorf("record sell fill: query open lots: %w", err)
+	}
+
+	var lots []openLot
+	for rows.Next() {
+		var lot openLot
+		if err := rows.Scan(&lot.id, &lot.remaining, &lot.price, &lot.placedAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("record sell fill: scan open lot: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("record sell fill: %w", err)
+	}
+
+	closed, _ := closeLots(lots, fill.Quantity)
+	for _, lot := range closed {
+		tax := portfolio.GeTax(fill.Price, lot.remaining)
+		profit := (fill.Price-lot.price)*lot.remaining - tax
+
+		if _, err := tx.Exec(ctx, `
+			UPDATE positions
+			SET filled_quantity = filled_quantity + $1,
+			    status = CASE WHEN filled_quantity + $1 >= quantity THEN 'closed' ELSE 'open' END,
+			    updated_at = $2
+			WHERE id = $3
+		`, lot.remaining, fill.Timestamp, lot.id); err != nil {
+			return fmt.Errorf("record sell fill: close lot %d: %w", lot.id, err)
+		}
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO trades (item_id, name, quantity, buy_price, sell_price, buy_time, sell_time, tax_gp, profit_gp)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, fill.ItemID, fill.Name, lot.remaining, lot.price, fill.Price, lot.placedAt, fill.Timestamp, tax, profit); err != nil {
+			return fmt.Errorf("record sell fill: insert trade: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("record sell fill: commit: %w", err)
+	}
+	return nil
+}
+
+// TradeStats computes TradeStats over the full trades table, the same way
+// backtest.summarize folds backtest.ClosedTrade rows into backtest.TradeStats.
+func (s *PositionStore) TradeStats(ctx context.Context) (TradeStats, error) {
+	trades, err := s.ListClosedTrades(ctx)
+	if err != nil {
+		return TradeStats{}, err
+	}
+	return summarizeTrades(trades), nil
+}
+
+// ListClosedTrades returns every row of the trades table, oldest sell_time
+// first -- the raw material TradeStats summarizes, and what
+// reporting.EquityCurve charts directly.
+func (s *PositionStore) ListClosedTrades(ctx context.Context) ([]ClosedTrade, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT item_id, quantity, buy_price, sell_price, buy_time, sell_time, tax_gp, profit_gp
+		FROM trades
+		ORDER BY sell_time ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list closed trades: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []ClosedTrade
+	for rows.Next() {
+		var t ClosedTrade
+		if err := rows.Scan(&t.ItemID, &t.Quantity, &t.BuyPrice, &t.SellPrice, &t.BuyTime, &t.SellTime, &t.TaxGP, &t.ProfitGP); err != nil {
+			return nil, fmt.Errorf("list closed trades: scan trade: %w", err)
+		}
+		trades = append(trades, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list closed trades: %w", err)
+	}
+
+	return trades, nil
+}
+
+// summarizeTrades is TradeStats' pure core, split out so it can be unit
+// tested without a database.
+func summarizeTrades(trades []ClosedTrade) TradeStats {
+	stats := TradeStats{PNLByItem: make(map[int]int)}
+
+	var totalProfit, wins, cumulative, peak int
+	var totalHoldingTime time.Duration
+	for _, t := range trades {
+		stats.ClosedTrades++
+		totalProfit += t.ProfitGP
+		totalHoldingTime += t.SellTime.Sub(t.BuyTime)
+		if t.ProfitGP > 0 {
+			wins++
+		}
+		stats.PNLByItem[t.ItemID] += t.ProfitGP
+
+		cumulative += t.ProfitGP
+		if cumulative > peak {
+			peak = cumulative
+		}
+		if drawdown := peak - cumulative; drawdown > stats.MaxDrawdownGP {
+			stats.MaxDrawdownGP = drawdown
+		}
+	}
+
+	if stats.ClosedTrades > 0 {
+		stats.WinRate = float64(wins) / float64(stats.ClosedTrades)
+		stats.AvgProfitGP = float64(totalProfit) / float64(stats.ClosedTrades)
+		stats.AvgHoldingTime = totalHoldingTime / time.Duration(stats.ClosedTrades)
+	}
+	return stats
+}
+
+// GetPortfolioSummary reports what's currently tied up in open buy lots,
+// valued against currentPrices (keyed by item ID, typically each item's
+// current insta-buy price from storage.GetLatestPrices), and which item IDs
+// to steer the LLM away from recommending: anything already held, or any
+// item whose buys in the last buyLimitResetEvery window already hit its
+// buyLimits entry.
+func (s *PositionStore) GetPortfolioSummary(ctx context.Context, now time.Time, currentPrices map[int]int, buyLimits map[int]int) (*PortfolioSummary, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT item_id, name, quantity - filled_quantity, price, placed_at
+		FROM positions
+		WHERE side = 'buy' AND status = 'open'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("portfolio summary: query open lots: %w", err)
+	}
+
+	type lot struct {
+		itemID    int
+		remaining int
+		price     int
+		placedAt  time.Time
+	}
+	var lots []lot
+	for rows.Next() {
+		var l lot
+		var name string
+		if err := rows.Scan(&l.itemID, &name, &l.remaining, &l.price, &l.placedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("portfolio summary: scan open lot: %w", err)
+		}
+		lots = append(lots, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("portfolio summary: %w", err)
+	}
+
+	summary := &PortfolioSummary{
+		GPTiedUp:      make(map[int]int),
+		UnrealizedPNL: make(map[int]int),
+	}
+	held := make(map[int]bool)
+	for _, l := range lots {
+		summary.OpenSlots++
+		summary.GPTiedUp[l.itemID] += l.remaining * l.price
+		if current, ok := currentPrices[l.itemID]; ok {
+			summary.UnrealizedPNL[l.itemID] += (current - l.price) * l.remaining
+		}
+		held[l.itemID] = true
+	}
+
+	boughtSinceReset, err := s.boughtSinceReset(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[int]bool)
+	for itemID := range held {
+		excluded[itemID] = true
+	}
+	for itemID, bought := range boughtSinceReset {
+		if limit, ok := buyLimits[itemID]; ok && limit > 0 && bought >= limit {
+			excluded[itemID] = true
+		}
+	}
+	for itemID := range excluded {
+		summary.ExcludeItemIDs = append(summary.ExcludeItemIDs, itemID)
+	}
+	sort.Ints(summary.ExcludeItemIDs)
+
+	return summary, nil
+}
+
+// boughtSinceReset returns how many units of each item were bought within
+// the current buyLimitResetEvery window ending at now.
+func (s *PositionStore) boughtSinceReset(ctx context.Context, now time.Time) (map[int]int, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT item_id, COALESCE(SUM(quantity), 0)
+		FROM positions
+		WHERE side = 'buy' AND placed_at >= $1
+		GROUP BY item_id
+	`, now.Add(-buyLimitResetEvery))
+	if err != nil {
+		return nil, fmt.Errorf("bought since reset: %w", err)
+	}
+	defer rows.Close()
+
+	bought := make(map[int]int)
+	for rows.Next() {
+		var itemID, qty int
+		if err := rows.Scan(&itemID, &qty); err != nil {
+			return nil, fmt.Errorf("bought since reset: scan: %w", err)
+		}
+		bought[itemID] = qty
+	}
+	return bought, rows.Err()
+}
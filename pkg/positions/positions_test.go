@@ -0,0 +1,83 @@
+package positions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCloseLots_FIFOAcrossMultipleLots(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	lots := []openLot{
+		{id: 1, remaining: 10, price: 100, placedAt: base},
+		{id: 2, remaining: 10, price: 110, placedAt: base.Add(time.Hour)},
+	}
+
+	closed, remaining := closeLots(lots, 15)
+	if remaining != 0 {
+		t.Fatalf("remaining = %d, want 0", remaining)
+	}
+	if len(closed) != 2 || closed[0].id != 1 || closed[0].remaining != 10 || closed[1].id != 2 || closed[1].remaining != 5 {
+		t.Errorf("closed = %+v, want lot 1 fully closed then 5 units of lot 2", closed)
+	}
+}
+
+func TestCloseLots_ReturnsUnmatchedQtyWhenLiquidityRunsOut(t *testing.T) {
+	lots := []openLot{{id: 1, remaining: 5, price: 100}}
+
+	closed, remaining := closeLots(lots, 8)
+	if remaining != 3 {
+		t.Errorf("remaining = %d, want 3", remaining)
+	}
+	if len(closed) != 1 || closed[0].remaining != 5 {
+		t.Errorf("closed = %+v, want all 5 units of the only lot", closed)
+	}
+}
+
+func TestSummarizeTrades(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []ClosedTrade{
+		{ItemID: 1, Quantity: 10, ProfitGP: 100, BuyTime: base, SellTime: base.Add(time.Hour)},
+		{ItemID: 1, Quantity: 10, ProfitGP: -40, BuyTime: base, SellTime: base.Add(3 * time.Hour)},
+	}
+
+	stats := summarizeTrades(trades)
+	if stats.ClosedTrades != 2 {
+		t.Errorf("ClosedTrades = %d, want 2", stats.ClosedTrades)
+	}
+	if stats.WinRate != 0.5 {
+		t.Errorf("WinRate = %v, want 0.5", stats.WinRate)
+	}
+	if stats.AvgProfitGP != 30 {
+		t.Errorf("AvgProfitGP = %v, want 30", stats.AvgProfitGP)
+	}
+	if stats.AvgHoldingTime != 2*time.Hour {
+		t.Errorf("AvgHoldingTime = %v, want 2h", stats.AvgHoldingTime)
+	}
+	if stats.MaxDrawdownGP != 40 {
+		t.Errorf("MaxDrawdownGP = %d, want 40 (peak 100, trough 60)", stats.MaxDrawdownGP)
+	}
+	if stats.PNLByItem[1] != 60 {
+		t.Errorf("PNLByItem[1] = %d, want 60", stats.PNLByItem[1])
+	}
+}
+
+func TestFillHandler_RejectsInvalidSideAndMethod(t *testing.T) {
+	handler := NewFillHandler(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/positions/fill", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status = %d, want 405", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/positions/fill", strings.NewReader(`{"item_id":1,"side":"hold","qty":1,"price":1}`))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid side: status = %d, want 400", w.Code)
+	}
+}
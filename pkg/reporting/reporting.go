@@ -0,0 +1,213 @@
+// Package reporting renders PNG charts for humans reviewing trading
+// recommendations, using the same gonum.org/v1/plot library and
+// p.Save(8*vg.Inch, 4*vg.Inch, path) convention as osrs/backtest.RenderGraphs,
+// backtest.RenderEquityGraph, and portfolio.Portfolio.GenerateGraph. Those
+// three already render their own
+// equity curves against their own result types; this package covers chart
+// kinds that don't have an existing subsystem to live in: a convenience
+// equity curve for positions.PositionStore (which, unlike backtest.Result
+// and portfolio.Portfolio, has no GenerateGraph of its own), per-item
+// price/margin overlays sourced from bucket history, and a margin-vs-volume
+// scatter of the current top opportunities.
+package reporting
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+
+	"osrs-flipping/pkg/osrs"
+	"osrs-flipping/pkg/positions"
+)
+
+// EquityCurve writes trades' realized-PnL-over-time line chart to path, in
+// the same shape as backtest.RenderEquityGraph and
+// portfolio.Portfolio.GenerateGraph. trades must already be ordered oldest
+// sell_time first (see positions.PositionStore.ListClosedTrades).
+func EquityCurve(trades []positions.ClosedTrade, path string) error {
+	if len(trades) == 0 {
+		return fmt.Errorf("no closed trades to graph")
+	}
+
+	pts := make(plotter.XYs, len(trades))
+	cumulative := 0
+	for i, t := range trades {
+		cumulative += t.ProfitGP
+		pts[i].X = float64(t.SellTime.Unix())
+		pts[i].Y = float64(cumulative)
+	}
+
+	p := plot.New()
+	p.Title.Text = "positions: cumulative PnL (GP)"
+	p.X.Label.Text = "time (unix seconds)"
+	p.Y.Label.Text = "cumulative GP"
+
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		return fmt.Errorf("building equity line: %w", err)
+	}
+	p.Add(line)
+
+	if err := p.Save(8*vg.Inch, 4*vg.Inch, path); err != nil {
+		return fmt.Errorf("saving equity graph: %w", err)
+	}
+	return nil
+}
+
+// PricePoint is one bucket's insta-buy/insta-sell prices -- the minimal
+// shape ItemPriceOverlay needs. Callers derive it from whatever bucket
+// history they already have (e.g. storage.Bucket's AvgHighPrice/
+// AvgLowPrice) rather than this package importing a specific history
+// source directly.
+type PricePoint struct {
+	Time           time.Time
+	InstaBuyPrice  float64 // "high": what you can sell into instantly
+	InstaSellPrice float64 // "low": what you can buy into instantly
+}
+
+// ItemHistory pairs one item's price history with its identity, for
+// GenerateReports to label each per-item chart it writes.
+type ItemHistory struct {
+	ItemID int
+	Name   string
+	Points []PricePoint
+}
+
+// ItemPriceOverlay writes a chart overlaying name's insta-buy/insta-sell
+// price history with the margin between them (InstaBuyPrice -
+// InstaSellPrice) to path.
+func ItemPriceOverlay(name string, points []PricePoint, path string) error {
+	if len(points) == 0 {
+		return fmt.Errorf("%s: no price history to graph", name)
+	}
+
+	buyLine := make(plotter.XYs, len(points))
+	sellLine := make(plotter.XYs, len(points))
+	marginLine := make(plotter.XYs, len(points))
+	for i, pt := range points {
+		x := float64(pt.Time.Unix())
+		buyLine[i].X, buyLine[i].Y = x, pt.InstaBuyPrice
+		sellLine[i].X, sellLine[i].Y = x, pt.InstaSellPrice
+		marginLine[i].X, marginLine[i].Y = x, pt.InstaBuyPrice-pt.InstaSellPrice
+	}
+
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("%s: price and margin", name)
+	p.X.Label.Text = "time (unix seconds)"
+	p.Y.Label.Text = "GP"
+
+	buy, err := plotter.NewLine(buyLine)
+	if err != nil {
+		return fmt.Errorf("building insta-buy price line: %w", err)
+	}
+	buy.Color = color.RGBA{R: 200, A: 255}
+	p.Add(buy)
+	p.Legend.Add("insta-buy price", buy)
+
+	sell, err := plotter.NewLine(sellLine)
+	if err != nil {
+		return fmt.Errorf("building insta-sell price line: %w", err)
+	}
+	sell.Color = color.RGBA{B: 200, A: 255}
+	p.Add(sell)
+	p.Legend.Add("insta-sell price", sell)
+
+	margin, err := plotter.NewLine(marginLine)
+	if err != nil {
+		return fmt.Errorf("building margin line: %w", err)
+	}
+	margin.Color = color.RGBA{G: 150, A: 255}
+	p.Add(margin)
+	p.Legend.Add("margin", margin)
+
+	if err := p.Save(8*vg.Inch, 4*vg.Inch, path); err != nil {
+		return fmt.Errorf("saving %s price overlay: %w", name, err)
+	}
+	return nil
+}
+
+// MarginVolumeScatter writes a scatter of items' margin (GP) against their
+// 1h insta-buy+insta-sell transaction volume to path, one point per item
+// that has 1h volume data. items with nil InstaBuyVolume1h/
+// InstaSellVolume1h are skipped.
+func MarginVolumeScatter(items []osrs.ItemData, path string) error {
+	var pts plotter.XYs
+	for _, item := range items {
+		if item.InstaBuyVolume1h == nil || item.InstaSellVolume1h == nil {
+			continue
+		}
+		pts = append(pts, plotter.XY{
+			X: *item.InstaBuyVolume1h + *item.InstaSellVolume1h,
+			Y: float64(item.MarginGP),
+		})
+	}
+	if len(pts) == 0 {
+		return fmt.Errorf("no items with 1h volume data to graph")
+	}
+
+	p := plot.New()
+	p.Title.Text = "current opportunities: margin vs 1h volume"
+	p.X.Label.Text = "1h volume (insta-buy + insta-sell txns)"
+	p.Y.Label.Text = "margin (GP)"
+
+	scatter, err := plotter.NewScatter(pts)
+	if err != nil {
+		return fmt.Errorf("building margin/volume scatter: %w", err)
+	}
+	p.Add(scatter)
+
+	if err := p.Save(8*vg.Inch, 4*vg.Inch, path); err != nil {
+		return fmt.Errorf("saving margin/volume scatter: %w", err)
+	}
+	return nil
+}
+
+// GenerateReports renders every chart this package supports into a fresh
+// baseDir/<ts> directory and returns the paths of whatever it wrote. trades
+// and history are each optional (nil/empty skips that chart) -- a caller
+// that only has items on hand (e.g. the offline analysis CLI, which has no
+// DB-backed positions store or bucket history) still gets the
+// margin/volume scatter.
+func GenerateReports(baseDir string, ts time.Time, trades []positions.ClosedTrade, history []ItemHistory, items []osrs.ItemData) ([]string, error) {
+	dir := filepath.Join(baseDir, ts.Format("2006-01-02T15-04-05"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating report directory: %w", err)
+	}
+
+	var paths []string
+
+	if len(trades) > 0 {
+		path := filepath.Join(dir, "equity_curve.png")
+		if err := EquityCurve(trades, path); err != nil {
+			return paths, fmt.Errorf("rendering equity curve: %w", err)
+		}
+		paths = append(paths, path)
+	}
+
+	for _, h := range history {
+		if len(h.Points) == 0 {
+			continue
+		}
+		path := filepath.Join(dir, fmt.Sprintf("item_%d.png", h.ItemID))
+		if err := ItemPriceOverlay(h.Name, h.Points, path); err != nil {
+			return paths, fmt.Errorf("rendering %s price overlay: %w", h.Name, err)
+		}
+		paths = append(paths, path)
+	}
+
+	if len(items) > 0 {
+		path := filepath.Join(dir, "margin_vs_volume.png")
+		if err := MarginVolumeScatter(items, path); err != nil {
+			return paths, fmt.Errorf("rendering margin/volume scatter: %w", err)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
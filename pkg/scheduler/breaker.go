@@ -0,0 +1,121 @@
+package scheduler
+
+// Breaker tuning: K consecutive failures opens the breaker; each further
+// failure while it's open doubles the number of cron ticks skipped, capped
+// at DefaultBreakerMaxSkip, similar to the pause-on-delivery-errors pattern
+// some federation relays use to stop hammering a dead endpoint.
+const (
+	DefaultBreakerFailureThreshold = 3
+	DefaultBreakerInitialSkip      = 1
+	DefaultBreakerMaxSkip          = 8
+)
+
+// BreakerStatus reports a job's circuit-breaker state, for JobStatus and
+// GetJobStatus.
+type BreakerStatus struct {
+	ConsecutiveFailures int
+	Paused              bool
+	SkipRemaining       int
+}
+
+// breakerState is the mutable per-job bookkeeping behind BreakerStatus,
+// guarded by Scheduler.mu like the rest of Scheduler's per-job state.
+type breakerState struct {
+	consecutiveFailures int
+	skipRemaining       int
+	nextSkip            int
+}
+
+// BreakerNotifier posts a one-time notice when a job's circuit breaker
+// opens, so operators learn about a stuck job once instead of once per
+// cron tick. discord.Bot satisfies this directly.
+type BreakerNotifier interface {
+	NotifyJobPaused(jobName string, consecutiveFailures int) error
+}
+
+// SetBreakerNotifier wires a BreakerNotifier into the Scheduler so opening a
+// job's breaker posts a notice. Optional: without one, a paused job is only
+// visible via JobStatus/GetJobStatus or the scheduler log.
+func (s *Scheduler) SetBreakerNotifier(n BreakerNotifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.breakerNotifier = n
+}
+
+// shouldSkipTick reports whether jobName's breaker is open for this cron
+// tick, consuming one tick of the skip budget if so. Only cron-triggered
+// runs call this; a manual trigger always runs regardless of breaker state.
+func (s *Scheduler) shouldSkipTick(jobName string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.breakers[jobName]
+	if !ok || b.skipRemaining <= 0 {
+		return false
+	}
+	b.skipRemaining--
+	return true
+}
+
+// recordBreakerSuccess resets jobName's breaker after a successful
+// execution (cron or manual), so a job that was flapping doesn't stay
+// paused once it starts working again.
+func (s *Scheduler) recordBreakerSuccess(jobName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.breakers, jobName)
+}
+
+// recordBreakerFailure bumps jobName's consecutive-failure count after an
+// execution exhausts its retries. Once the count reaches
+// DefaultBreakerFailureThreshold the breaker opens (skipping cron ticks)
+// and, the first time it opens, posts a notice via BreakerNotifier; each
+// subsequent failure while still open doubles the skip budget up to
+// DefaultBreakerMaxSkip.
+func (s *Scheduler) recordBreakerFailure(jobName string) {
+	s.mu.Lock()
+	b, ok := s.breakers[jobName]
+	if !ok {
+		b = &breakerState{}
+		s.breakers[jobName] = b
+	}
+	b.consecutiveFailures++
+
+	var justOpened bool
+	if b.consecutiveFailures >= DefaultBreakerFailureThreshold {
+		if b.nextSkip == 0 {
+			b.nextSkip = DefaultBreakerInitialSkip
+			justOpened = true
+		} else {
+			b.nextSkip *= 2
+			if b.nextSkip > DefaultBreakerMaxSkip {
+				b.nextSkip = DefaultBreakerMaxSkip
+			}
+		}
+		b.skipRemaining = b.nextSkip
+	}
+	failures := b.consecutiveFailures
+	notifier := s.breakerNotifier
+	s.mu.Unlock()
+
+	if justOpened && notifier != nil {
+		if err := notifier.NotifyJobPaused(jobName, failures); err != nil {
+			s.logger.WithComponent("scheduler").WithField("job_name", jobName).WithError(err).Warn("Failed to send job-paused notice")
+		}
+	}
+}
+
+// breakerStatus builds a BreakerStatus snapshot for jobName, or nil if its
+// breaker has never recorded a failure. Callers must hold s.mu (read or
+// write).
+func (s *Scheduler) breakerStatus(jobName string) *BreakerStatus {
+	b, ok := s.breakers[jobName]
+	if !ok {
+		return nil
+	}
+	return &BreakerStatus{
+		ConsecutiveFailures: b.consecutiveFailures,
+		Paused:              b.skipRemaining > 0,
+		SkipRemaining:       b.skipRemaining,
+	}
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// cronFieldRanges describes the six whitespace-separated fields LoadJobs'
+// parser expects (seconds-first, via cron.WithSeconds): the maximum legal
+// value for each, used by ValidateCron to catch a "*/N" step whose N
+// exceeds the field's range. robfig/cron/v3 parses such an expression
+// without error but silently reinterprets it -- see
+// TestInvalidNinetyMinuteCron, where "0 */90 * * * *" behaves like hourly
+// ("every 90th minute" only exists at minute 0) rather than every 90
+// minutes.
+var cronFieldRanges = []struct {
+	name string
+	max  int
+}{
+	{"second", 59},
+	{"minute", 59},
+	{"hour", 23},
+	{"day of month", 31},
+	{"month", 12},
+	{"day of week", 7},
+}
+
+// cronPreviewCount is how many upcoming fire times ValidateCron computes
+// for its human-readable description.
+const cronPreviewCount = 5
+
+// ValidateCron parses expr (the same six-field, seconds-first layout
+// addSchedule uses) and returns a natural-language description of its next
+// few fire times, plus warnings for any field whose "*/N" step exceeds that
+// field's range. A parse failure is returned as err; a degenerate-but-valid
+// expression parses successfully and is reported via warnings instead,
+// since addSchedule still needs a schedule to load even for a job whose
+// cron the caller decides to keep despite the warning.
+func ValidateCron(expr string) (human string, warnings []string, err error) {
+	fields := strings.Fields(expr)
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "CRON_TZ=") {
+		// CRON_TZ=<zone> (see addSchedule) isn't part of the six-field
+		// grammar cron.Parser validates; drop it before range-checking
+		// fields so the indices below line up.
+		fields = fields[1:]
+	}
+
+	for i, field := range fields {
+		if i >= len(cronFieldRanges) {
+			break
+		}
+		step, ok := parseStep(field)
+		if !ok || step <= cronFieldRanges[i].max {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"%s field %q uses step %d, which exceeds the field's range of 0-%d and will silently behave like a smaller step instead of what was intended",
+			cronFieldRanges[i].name, field, step, cronFieldRanges[i].max))
+	}
+
+	parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	schedule, err := parser.Parse(expr)
+	if err != nil {
+		return "", warnings, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+
+	times := make([]string, 0, cronPreviewCount)
+	next := time.Now()
+	for i := 0; i < cronPreviewCount; i++ {
+		next = schedule.Next(next)
+		times = append(times, next.Format(time.RFC3339))
+	}
+	human = fmt.Sprintf("Next %d fire times: %s", cronPreviewCount, strings.Join(times, ", "))
+
+	return human, warnings, nil
+}
+
+// parseStep extracts N from a "*/N" or "<range>/N" field, reporting false
+// if the field has no step at all (e.g. a bare "*" or a literal value).
+func parseStep(field string) (int, bool) {
+	_, stepStr, ok := strings.Cut(field, "/")
+	if !ok {
+		return 0, false
+	}
+	step, err := strconv.Atoi(stepStr)
+	if err != nil {
+		return 0, false
+	}
+	return step, true
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCronDegenerateStep(t *testing.T) {
+	tests := []struct {
+		name         string
+		cronExpr     string
+		expectErr    bool
+		expectWarn   bool
+		warnContains string
+	}{
+		{
+			name:         "ninety_minute_step_warns",
+			cronExpr:     "0 */90 * * * *",
+			expectWarn:   true,
+			warnContains: "minute",
+		},
+		{
+			name:         "twenty_five_hour_step_warns",
+			cronExpr:     "0 0 */25 * * *",
+			expectWarn:   true,
+			warnContains: "hour",
+		},
+		{
+			name:       "valid_hourly_no_warning",
+			cronExpr:   "0 0 */1 * * *",
+			expectWarn: false,
+		},
+		{
+			name:      "malformed_expression_errors",
+			cronExpr:  "not a cron",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			human, warnings, err := ValidateCron(tt.cronExpr)
+
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error for %q, got none", tt.cronExpr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %v", tt.cronExpr, err)
+			}
+			if human == "" {
+				t.Fatalf("expected a human-readable description for %q", tt.cronExpr)
+			}
+
+			if tt.expectWarn {
+				if len(warnings) == 0 {
+					t.Fatalf("expected a warning for %q, got none", tt.cronExpr)
+				}
+				if !strings.Contains(warnings[0], tt.warnContains) {
+					t.Fatalf("expected warning to mention %q, got: %s", tt.warnContains, warnings[0])
+				}
+			} else if len(warnings) != 0 {
+				t.Fatalf("expected no warnings for %q, got: %v", tt.cronExpr, warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"osrs-flipping/pkg/config"
+)
+
+// DockerProvider discovers jobs and schedules from container labels.
+// pkg/scheduler/docker.Provider satisfies this.
+type DockerProvider interface {
+	Discover(ctx context.Context) ([]config.JobConfig, []config.ScheduleConfig, error)
+}
+
+// SetDockerProvider wires a DockerProvider into the Scheduler so
+// StartDockerReconciliation (or a manually-driven ReconcileDockerJobs) can
+// merge label-discovered jobs with the static config.Config.
+func (s *Scheduler) SetDockerProvider(p DockerProvider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dockerProvider = p
+}
+
+// StartDockerReconciliation runs ReconcileDockerJobs on a timer until ctx is
+// canceled, so relabeling a container (or starting/stopping one) picks up
+// job additions and removals without an operator restarting osrs-flips.
+func (s *Scheduler) StartDockerReconciliation(ctx context.Context, interval time.Duration) {
+	if err := s.ReconcileDockerJobs(ctx); err != nil {
+		s.logger.WithComponent("scheduler").WithError(err).Error("Initial Docker job reconciliation failed")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.ReconcileDockerJobs(ctx); err != nil {
+				s.logger.WithComponent("scheduler").WithError(err).Error("Docker job reconciliation failed")
+			}
+		}
+	}
+}
+
+// ReconcileDockerJobs discovers jobs via the configured DockerProvider and
+// merges them into the Scheduler: new or relabeled containers add/update
+// cron entries, and jobs previously discovered via Docker that no longer
+// appear (container stopped or relabeled away) have their schedule
+// removed. Jobs loaded from config.yml are never touched here, even if
+// their name collides with a label-discovered one, since YAML is still the
+// source of truth for anything it defines.
+func (s *Scheduler) ReconcileDockerJobs(ctx context.Context) error {
+	s.mu.RLock()
+	provider := s.dockerProvider
+	s.mu.RUnlock()
+	if provider == nil {
+		return nil
+	}
+
+	discoveredJobs, discoveredSchedules, err := provider.Discover(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]bool, len(discoveredJobs))
+	for _, job := range discoveredJobs {
+		seen[job.Name] = true
+
+		// A statically configured job of the same name wins; Docker labels
+		// only fill in jobs config.yml doesn't already define.
+		if _, isStatic := s.jobs[job.Name]; isStatic && !s.dockerJobs[job.Name] {
+			continue
+		}
+
+		s.jobs[job.Name] = job
+		s.dockerJobs[job.Name] = true
+		if _, ok := s.semaphores[job.Name]; !ok {
+			s.semaphores[job.Name] = make(chan struct{}, DefaultMaxConcurrent)
+		}
+	}
+
+	for _, schedule := range discoveredSchedules {
+		if !s.dockerJobs[schedule.JobName] {
+			continue
+		}
+		// Re-registering a schedule without first removing the old cron
+		// entry would leave both running, so drop any prior entry for this
+		// job before adding the (possibly updated) one.
+		s.removeScheduleEntryOnly(schedule.JobName)
+		if err := s.addSchedule(schedule); err != nil {
+			s.logger.WithComponent("scheduler").WithField("job_name", schedule.JobName).WithError(err).Warn("Failed to add Docker-discovered schedule")
+		}
+	}
+
+	for name := range s.dockerJobs {
+		if !seen[name] {
+			s.removeSchedule(name)
+			delete(s.dockerJobs, name)
+		}
+	}
+
+	return nil
+}
+
+// removeScheduleEntryOnly stops jobName's cron entry without forgetting the
+// job itself, for the re-register-on-reconcile path above. Callers must
+// hold s.mu.
+func (s *Scheduler) removeScheduleEntryOnly(jobName string) {
+	entryID, exists := s.entryIDs[jobName]
+	if !exists {
+		return
+	}
+	s.cron.Remove(entryID)
+	delete(s.entryIDs, jobName)
+}
@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// ExecRunner runs commands inside already-running containers on behalf of
+// "exec"-kind config.JobConfig jobs, following dockron's exec-schedule
+// feature: a schedule that doesn't produce a flip analysis, just runs a
+// maintenance command (DB vacuum, cache warmup, price-refresh script)
+// inside a named container.
+type ExecRunner struct {
+	cli *client.Client
+}
+
+// NewExecRunner creates an ExecRunner using the Docker client's standard
+// environment-based configuration, matching Provider's.
+func NewExecRunner() (*ExecRunner, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	return &ExecRunner{cli: cli}, nil
+}
+
+// Run executes command inside containerName and waits for it to finish,
+// returning its captured stdout/stderr. A non-zero exit code is reported as
+// an error with the combined output attached, so callers (BotExecutor) can
+// surface it the same way a failed analysis job's error is surfaced.
+func (r *ExecRunner) Run(ctx context.Context, containerName string, command []string) (stdout, stderr string, err error) {
+	execID, err := r.cli.ContainerExecCreate(ctx, containerName, container.ExecOptions{
+		Cmd:          command,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create exec in container %s: %w", containerName, err)
+	}
+
+	attach, err := r.cli.ContainerExecAttach(ctx, execID.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to attach to exec in container %s: %w", containerName, err)
+	}
+	defer attach.Close()
+
+	var outBuf, errBuf bytes.Buffer
+	if _, err := stdcopy.StdCopy(&outBuf, &errBuf, attach.Reader); err != nil {
+		return "", "", fmt.Errorf("failed to read exec output from container %s: %w", containerName, err)
+	}
+	stdout, stderr = outBuf.String(), errBuf.String()
+
+	inspect, err := r.cli.ContainerExecInspect(ctx, execID.ID)
+	if err != nil {
+		return stdout, stderr, fmt.Errorf("failed to inspect exec in container %s: %w", containerName, err)
+	}
+	if inspect.ExitCode != 0 {
+		return stdout, stderr, fmt.Errorf("command in container %s exited %d: %s", containerName, inspect.ExitCode, stderr)
+	}
+
+	return stdout, stderr, nil
+}
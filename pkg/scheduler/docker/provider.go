@@ -0,0 +1,165 @@
+// Package docker discovers scheduler jobs from container labels, following
+// the approach in dockron: instead of editing config.yml and restarting,
+// operators deploying via compose add/remove flip analysis jobs by
+// attaching labels like
+//
+//	osrs-flips.job.<name>.schedule=0 0 */1 * * *
+//	osrs-flips.job.<name>.model=llama3
+//	osrs-flips.job.<name>.filter=margin_gp_min=100,margin_pct_min=0.05
+//
+// to a container and relabeling it. Provider.Discover reads these labels
+// from the running containers on each call; scheduler.Scheduler's
+// reconciliation loop (see scheduler.ReconcileDockerJobs) merges the result
+// with the static config.Config on a timer.
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"osrs-flipping/pkg/config"
+	"osrs-flipping/pkg/logging"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// labelPrefix namespaces every label Provider looks at, so containers
+// unrelated to osrs-flips are ignored.
+const labelPrefix = "osrs-flips.job."
+
+// Provider discovers jobs and their schedules from Docker container labels.
+type Provider struct {
+	cli    *client.Client
+	logger *logging.Logger
+}
+
+// NewProvider creates a Provider using the Docker client's standard
+// environment-based configuration (DOCKER_HOST, etc.), matching how the
+// `docker` CLI itself connects.
+func NewProvider(logger *logging.Logger) (*Provider, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	return &Provider{cli: cli, logger: logger}, nil
+}
+
+// Discover lists running containers and returns the jobs and schedules
+// described by their osrs-flips.job.* labels. Containers with no such
+// labels are ignored; a container with a malformed label (e.g. an
+// unparseable filter value) has that one field skipped, logged, and
+// otherwise still contributes its job.
+func (p *Provider) Discover(ctx context.Context) ([]config.JobConfig, []config.ScheduleConfig, error) {
+	containers, err := p.cli.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	labelsByJob := make(map[string]map[string]string)
+	for _, c := range containers {
+		for key, value := range c.Labels {
+			if !strings.HasPrefix(key, labelPrefix) {
+				continue
+			}
+			rest := strings.TrimPrefix(key, labelPrefix)
+			name, field, ok := strings.Cut(rest, ".")
+			if !ok {
+				continue
+			}
+			if labelsByJob[name] == nil {
+				labelsByJob[name] = make(map[string]string)
+			}
+			labelsByJob[name][field] = value
+		}
+	}
+
+	names := make([]string, 0, len(labelsByJob))
+	for name := range labelsByJob {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var jobs []config.JobConfig
+	var schedules []config.ScheduleConfig
+	for _, name := range names {
+		fields := labelsByJob[name]
+
+		job := config.JobConfig{
+			Name:    name,
+			Enabled: true,
+		}
+		if model := fields["model"]; model != "" {
+			job.Model = &config.JobModelConfig{Name: &model}
+		}
+		if filter, ok := fields["filter"]; ok {
+			parsed, err := parseFilter(filter)
+			if err != nil {
+				p.logger.WithComponent("docker_provider").WithField("job_name", name).WithError(err).Warn("Failed to parse filter label, job will have no filters")
+			} else {
+				job.Filters = parsed
+			}
+		}
+		jobs = append(jobs, job)
+
+		if cronSpec := fields["schedule"]; cronSpec != "" {
+			schedules = append(schedules, config.ScheduleConfig{
+				JobName: name,
+				Cron:    cronSpec,
+				Enabled: true,
+			})
+		}
+	}
+
+	return jobs, schedules, nil
+}
+
+// parseFilter parses a "key=value,key=value" label into a FilterConfig,
+// supporting the subset of FilterConfig's numeric fields most useful to set
+// per-container: margin_gp_min, margin_pct_min, volume_1h_min, limit.
+func parseFilter(raw string) (config.FilterConfig, error) {
+	var filter config.FilterConfig
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return filter, fmt.Errorf("malformed filter entry %q, expected key=value", pair)
+		}
+
+		switch key {
+		case "margin_gp_min":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return filter, fmt.Errorf("margin_gp_min: %w", err)
+			}
+			filter.MarginMin = &n
+		case "margin_pct_min":
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return filter, fmt.Errorf("margin_pct_min: %w", err)
+			}
+			filter.MarginPctMin = &f
+		case "volume_1h_min":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return filter, fmt.Errorf("volume_1h_min: %w", err)
+			}
+			filter.Volume1hMin = &n
+		case "limit":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return filter, fmt.Errorf("limit: %w", err)
+			}
+			filter.Limit = &n
+		default:
+			return filter, fmt.Errorf("unsupported filter key %q", key)
+		}
+	}
+	return filter, nil
+}
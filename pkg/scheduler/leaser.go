@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// JobLeaser arbitrates which scheduler replica may run a given job, so
+// running multiple Scheduler instances (e.g. during a rolling deploy)
+// doesn't double-execute a job against the OSRS wiki API. Pass one to
+// NewScheduler via WithLeaser; the default noopLeaser always grants the
+// lease, preserving single-node behavior.
+type JobLeaser interface {
+	// Acquire attempts to take (or renew, if this caller already holds it)
+	// the lease for jobName, valid for ttl from now. Returns false, nil if
+	// another holder currently owns an unexpired lease.
+	Acquire(ctx context.Context, jobName string, ttl time.Duration) (bool, error)
+
+	// Release gives up a held lease immediately, so another replica doesn't
+	// have to wait out the TTL before picking the job up.
+	Release(ctx context.Context, jobName string) error
+}
+
+// noopLeaser is the default JobLeaser: it always grants the lease. Correct
+// for single-node deployments, where there's no other replica to coordinate
+// with.
+type noopLeaser struct{}
+
+func (noopLeaser) Acquire(ctx context.Context, jobName string, ttl time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (noopLeaser) Release(ctx context.Context, jobName string) error {
+	return nil
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// jobRunState is the last-known run facts for a single job, used to compute
+// the last-success-age gauge.
+type jobRunState struct {
+	lastSuccess time.Time
+}
+
+// metrics is the scheduler's self-contained Prometheus registry and
+// instrument set. It doesn't run its own HTTP server -- the rest of the app
+// mounts Scheduler.MetricsHandler() wherever it already serves HTTP (e.g.
+// at /metrics).
+type metrics struct {
+	registry *prometheus.Registry
+
+	executionSeconds *prometheus.HistogramVec
+	creationLag      *prometheus.GaugeVec
+	queueWaitSeconds *prometheus.HistogramVec
+}
+
+func newMetrics(s *Scheduler) *metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &metrics{
+		registry: registry,
+		executionSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "osrs_job_execution_duration_seconds",
+			Help:    "Duration of executor.ExecuteJob calls, one observation per attempt.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"job", "outcome"}),
+		creationLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "osrs_job_creation_lag_seconds",
+			Help: "Wall-time between a job's scheduled fire time and executeJob actually starting, catching scheduler starvation.",
+		}, []string{"job"}),
+		queueWaitSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "osrs_job_queue_wait_seconds",
+			Help:    "Time a job spent waiting on the shared API rate limiter before its executor call started.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"job"}),
+	}
+
+	registry.MustRegister(m.executionSeconds, m.creationLag, m.queueWaitSeconds, newSchedulerCollector(s))
+	return m
+}
+
+// schedulerCollector computes the last-success-age and overdue gauges from
+// the scheduler's live state at scrape time, rather than via a periodically
+// ticked goroutine.
+type schedulerCollector struct {
+	s *Scheduler
+
+	lastSuccessAge *prometheus.Desc
+	overdueSeconds *prometheus.Desc
+}
+
+func newSchedulerCollector(s *Scheduler) *schedulerCollector {
+	return &schedulerCollector{
+		s: s,
+		lastSuccessAge: prometheus.NewDesc(
+			"osrs_job_last_success_age_seconds",
+			"Seconds since the job last completed successfully.",
+			[]string{"job"}, nil,
+		),
+		overdueSeconds: prometheus.NewDesc(
+			"osrs_job_overdue_seconds",
+			"How many seconds past its expected next run time a job is, 0 if on schedule.",
+			[]string{"job"}, nil,
+		),
+	}
+}
+
+func (c *schedulerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.lastSuccessAge
+	ch <- c.overdueSeconds
+}
+
+func (c *schedulerCollector) Collect(ch chan<- prometheus.Metric) {
+	c.s.mu.RLock()
+	defer c.s.mu.RUnlock()
+
+	now := time.Now()
+	for name := range c.s.jobs {
+		if state, ok := c.s.runState[name]; ok && !state.lastSuccess.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.lastSuccessAge, prometheus.GaugeValue, now.Sub(state.lastSuccess).Seconds(), name)
+		}
+
+		id, ok := c.s.entryIDs[name]
+		if !ok {
+			continue
+		}
+		next := c.s.cron.Entry(id).Next
+		if next.IsZero() {
+			continue
+		}
+		overdue := now.Sub(next).Seconds()
+		if overdue < 0 {
+			overdue = 0
+		}
+		ch <- prometheus.MustNewConstMetric(c.overdueSeconds, prometheus.GaugeValue, overdue, name)
+	}
+}
+
+// MetricsHandler returns an http.Handler serving this scheduler's metrics in
+// the Prometheus text exposition format.
+func (s *Scheduler) MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{})
+}
+
+// recordSuccess updates the job's last-success timestamp for the
+// last-success-age gauge.
+func (s *Scheduler) recordSuccess(jobName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runState[jobName] = &jobRunState{lastSuccess: time.Now()}
+}
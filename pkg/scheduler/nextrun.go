@@ -0,0 +1,29 @@
+package scheduler
+
+import "time"
+
+// NextRun returns jobName's next scheduled fire time and whether it
+// currently has an active cron entry at all (false if its schedule or the
+// job itself is disabled, or it was never scheduled).
+func (s *Scheduler) NextRun(jobName string) (time.Time, bool) {
+	s.mu.RLock()
+	entryID, exists := s.entryIDs[jobName]
+	s.mu.RUnlock()
+	if !exists {
+		return time.Time{}, false
+	}
+	return s.cron.Entry(entryID).Next, true
+}
+
+// NextRuns returns every scheduled job's next fire time, so an operator can
+// see the whole schedule at a glance instead of querying job-by-job.
+func (s *Scheduler) NextRuns() map[string]time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	next := make(map[string]time.Time, len(s.entryIDs))
+	for jobName, entryID := range s.entryIDs {
+		next[jobName] = s.cron.Entry(entryID).Next
+	}
+	return next
+}
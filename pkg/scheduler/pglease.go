@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresLeaser is a JobLeaser backed by the job_leases table (see
+// migrations/000001_create_job_leases.up.sql), letting multiple Scheduler
+// replicas coordinate over who runs each job instead of every replica
+// firing on its own cron tick.
+type PostgresLeaser struct {
+	pool        *pgxpool.Pool
+	leaseHolder string
+}
+
+// NewPostgresLeaser creates a PostgresLeaser. leaseHolder identifies this
+// process in the job_leases table (e.g. hostname+pid) so an operator can
+// tell which replica currently holds a lease.
+func NewPostgresLeaser(pool *pgxpool.Pool, leaseHolder string) *PostgresLeaser {
+	return &PostgresLeaser{pool: pool, leaseHolder: leaseHolder}
+}
+
+// Acquire atomically inserts the job_leases row for jobName, or steals it
+// if the existing row is either unexpired-but-ours (a heartbeat renewal) or
+// expired. The WHERE clause is what makes this safe for two replicas
+// racing the same INSERT ... ON CONFLICT: only one of them sees
+// RowsAffected > 0.
+func (l *PostgresLeaser) Acquire(ctx context.Context, jobName string, ttl time.Duration) (bool, error) {
+	tag, err := l.pool.Exec(ctx, `
+		INSERT INTO job_leases (job_name, lease_holder, acquired_at, expires_at)
+		VALUES ($1, $2, now(), now() + make_interval(secs => $3))
+		ON CONFLICT (job_name) DO UPDATE
+			SET lease_holder = EXCLUDED.lease_holder,
+				acquired_at  = EXCLUDED.acquired_at,
+				expires_at   = EXCLUDED.expires_at
+			WHERE job_leases.expires_at < now()
+				OR job_leases.lease_holder = EXCLUDED.lease_holder
+	`, jobName, l.leaseHolder, ttl.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("acquire lease for %s: %w", jobName, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Release deletes this holder's lease row, if it still owns it.
+func (l *PostgresLeaser) Release(ctx context.Context, jobName string) error {
+	_, err := l.pool.Exec(ctx, `
+		DELETE FROM job_leases WHERE job_name = $1 AND lease_holder = $2
+	`, jobName, l.leaseHolder)
+	if err != nil {
+		return fmt.Errorf("release lease for %s: %w", jobName, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TestPostgresLeaser_Acquire requires a reachable Postgres instance with the
+// job_leases table from migrations/000001_create_job_leases.up.sql, given
+// via TEST_DATABASE_URL. It exists specifically to round-trip Acquire's
+// make_interval(secs => $3) usage against a real server, since ttl
+// previously went in as a bare time.Duration, which pgx has no codec for
+// against the interval type Postgres infers there.
+func TestPostgresLeaser_Acquire(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping Postgres-backed test")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+	defer pool.Close()
+
+	jobName := "pglease_test_job"
+	if _, err := pool.Exec(ctx, "DELETE FROM job_leases WHERE job_name = $1", jobName); err != nil {
+		t.Fatalf("clean up existing lease row: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), "DELETE FROM job_leases WHERE job_name = $1", jobName)
+	})
+
+	first := NewPostgresLeaser(pool, "holder-a")
+	second := NewPostgresLeaser(pool, "holder-b")
+
+	acquired, err := first.Acquire(ctx, jobName, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+	if !acquired {
+		t.Fatal("expected first Acquire on an unheld lease to succeed")
+	}
+
+	acquired, err = second.Acquire(ctx, jobName, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("second Acquire: %v", err)
+	}
+	if acquired {
+		t.Fatal("expected Acquire by a different holder to fail while the lease is unexpired")
+	}
+
+	acquired, err = first.Acquire(ctx, jobName, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("renewal Acquire: %v", err)
+	}
+	if !acquired {
+		t.Fatal("expected Acquire by the current holder to succeed as a renewal")
+	}
+
+	time.Sleep(250 * time.Millisecond)
+
+	acquired, err = second.Acquire(ctx, jobName, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("post-expiry Acquire: %v", err)
+	}
+	if !acquired {
+		t.Fatal("expected Acquire by a different holder to succeed once the lease expired")
+	}
+}
@@ -2,7 +2,10 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -11,19 +14,118 @@ import (
 	"osrs-flipping/pkg/logging"
 
 	"github.com/robfig/cron/v3"
+	"golang.org/x/time/rate"
 )
 
+// Default retry policy, used for any job that doesn't set its own
+// config.RetryPolicy.
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 10 * time.Second
+	DefaultMaxBackoff     = 5 * time.Minute
+	DefaultMultiplier     = 2.0
+	DefaultJitter         = 0.2
+)
+
+// DefaultLeaseTTL is how long a job lease is valid for before it's
+// considered expired absent a renewal. The heartbeat goroutine renews at
+// DefaultLeaseTTL/3, comfortably covering long-running LLM jobs.
+const DefaultLeaseTTL = 30 * time.Minute
+
+// Fallback rate limit applied when config.SchedulerConfig doesn't set one,
+// matching the previous hard-coded 5s inter-job sleep this replaced.
+const (
+	DefaultAPIRequestsPerSecond = 0.2
+	DefaultAPIBurst             = 1
+)
+
+// DefaultMaxConcurrent is how many executions of a single job may run at
+// once when config.JobConfig.MaxConcurrent is unset.
+const DefaultMaxConcurrent = 1
+
+// leaseHandle tracks an in-flight job's held lease so it's released exactly
+// once, whether executeJob finishes normally or Stop() is called
+// concurrently during shutdown.
+type leaseHandle struct {
+	stopHeartbeat chan struct{}
+	once          sync.Once
+}
+
+func (h *leaseHandle) release(s *Scheduler, jobName string) {
+	h.once.Do(func() {
+		close(h.stopHeartbeat)
+		if err := s.leaser.Release(context.Background(), jobName); err != nil {
+			s.logger.WithComponent("scheduler").WithField("job_name", jobName).WithError(err).Warn("Failed to release job lease")
+		}
+		s.mu.Lock()
+		delete(s.heldLeases, jobName)
+		s.mu.Unlock()
+	})
+}
+
+// JobRetryState tracks in-progress retry bookkeeping for a scheduled job.
+// NextRetryAt is the zero time while an attempt is in flight or once the
+// job has stopped retrying.
+type JobRetryState struct {
+	Attempt     int
+	NextRetryAt time.Time
+}
+
+// JobStatus reports a job's enabled flag plus any in-progress retry state
+// and circuit-breaker state.
+type JobStatus struct {
+	Enabled bool
+	Retry   *JobRetryState
+	Breaker *BreakerStatus
+}
+
 // Scheduler manages job scheduling and execution
 type Scheduler struct {
-	cron     *cron.Cron
-	executor jobs.JobExecutor
-	logger   *logging.Logger
-	jobs     map[string]config.JobConfig
-	mu       sync.RWMutex
+	cron           *cron.Cron
+	executor       jobs.JobExecutor
+	logger         *logging.Logger
+	jobs           map[string]config.JobConfig
+	retryState     map[string]*JobRetryState
+	runState       map[string]*jobRunState
+	entryIDs       map[string]cron.EntryID
+	metrics        *metrics
+	leaser         JobLeaser
+	leaseTTL       time.Duration
+	heldLeases     map[string]*leaseHandle
+	limiter        *rate.Limiter
+	semaphores      map[string]chan struct{}
+	dockerProvider  DockerProvider
+	dockerJobs      map[string]bool
+	breakers        map[string]*breakerState
+	breakerNotifier BreakerNotifier
+	cronSpecs       map[string]string
+	mu              sync.RWMutex
+}
+
+// SchedulerOption configures optional Scheduler behavior at construction
+// time.
+type SchedulerOption func(*Scheduler)
+
+// WithLeaser installs a JobLeaser so multiple Scheduler instances (e.g.
+// during a rolling deploy) coordinate over who runs each job instead of
+// every replica firing on its own cron tick. Single-node deployments can
+// omit this and keep the default no-op (always-acquire) behavior.
+func WithLeaser(leaser JobLeaser) SchedulerOption {
+	return func(s *Scheduler) {
+		s.leaser = leaser
+	}
+}
+
+// WithLeaseTTL overrides the default lease TTL (and heartbeat interval of
+// TTL/3). Only meaningful alongside WithLeaser.
+func WithLeaseTTL(ttl time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		s.leaseTTL = ttl
+	}
 }
 
 // NewScheduler creates a new job scheduler
-func NewScheduler(logger *logging.Logger, executor jobs.JobExecutor) *Scheduler {
+func NewScheduler(logger *logging.Logger, executor jobs.JobExecutor, opts ...SchedulerOption) *Scheduler {
 	// Create cron with second precision and logging
 	cronLogger := cron.VerbosePrintfLogger(logger.WithComponent("scheduler").Logger)
 	c := cron.New(
@@ -32,12 +134,28 @@ func NewScheduler(logger *logging.Logger, executor jobs.JobExecutor) *Scheduler
 		cron.WithChain(cron.Recover(cronLogger)),
 	)
 
-	return &Scheduler{
-		cron:     c,
-		executor: executor,
-		logger:   logger,
-		jobs:     make(map[string]config.JobConfig),
+	s := &Scheduler{
+		cron:       c,
+		executor:   executor,
+		logger:     logger,
+		jobs:       make(map[string]config.JobConfig),
+		retryState: make(map[string]*JobRetryState),
+		runState:   make(map[string]*jobRunState),
+		entryIDs:   make(map[string]cron.EntryID),
+		leaser:     noopLeaser{},
+		leaseTTL:   DefaultLeaseTTL,
+		heldLeases: make(map[string]*leaseHandle),
+		limiter:    rate.NewLimiter(rate.Limit(DefaultAPIRequestsPerSecond), DefaultAPIBurst),
+		semaphores: make(map[string]chan struct{}),
+		dockerJobs: make(map[string]bool),
+		breakers:   make(map[string]*breakerState),
+		cronSpecs:  make(map[string]string),
+	}
+	s.metrics = newMetrics(s)
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // LoadJobs loads job configurations and sets up schedules
@@ -45,46 +163,124 @@ func (s *Scheduler) LoadJobs(cfg *config.Config) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if cfg.Scheduler.APIRequestsPerSecond > 0 {
+		burst := cfg.Scheduler.APIBurst
+		if burst < 1 {
+			burst = 1
+		}
+		s.limiter = rate.NewLimiter(rate.Limit(cfg.Scheduler.APIRequestsPerSecond), burst)
+	}
+
 	// Store job configurations
 	for _, job := range cfg.Jobs {
 		s.jobs[job.Name] = job
+
+		maxConcurrent := job.MaxConcurrent
+		if maxConcurrent < 1 {
+			maxConcurrent = DefaultMaxConcurrent
+		}
+		s.semaphores[job.Name] = make(chan struct{}, maxConcurrent)
 	}
 
 	// Set up scheduled jobs
 	for _, schedule := range cfg.Schedules {
-		if !schedule.Enabled {
-			s.logger.WithComponent("scheduler").WithField("job_name", schedule.JobName).Debug("Schedule disabled, skipping")
-			continue
+		if err := s.addSchedule(schedule); err != nil {
+			return err
 		}
+	}
 
-		job, exists := s.jobs[schedule.JobName]
-		if !exists {
-			s.logger.WithComponent("scheduler").WithField("job_name", schedule.JobName).Error("Job not found for schedule")
-			continue
-		}
+	return nil
+}
 
-		if !job.Enabled {
-			s.logger.WithComponent("scheduler").WithField("job_name", schedule.JobName).Debug("Job disabled, skipping schedule")
-			continue
+// addSchedule registers a single cron entry for schedule against the job of
+// the same name already stored in s.jobs. Callers must hold s.mu. Shared by
+// LoadJobs and ReconcileDockerJobs so both sources of schedules go through
+// the same validation and cron wiring.
+func (s *Scheduler) addSchedule(schedule config.ScheduleConfig) error {
+	if !schedule.Enabled {
+		s.logger.WithComponent("scheduler").WithField("job_name", schedule.JobName).Debug("Schedule disabled, skipping")
+		return nil
+	}
+
+	job, exists := s.jobs[schedule.JobName]
+	if !exists {
+		s.logger.WithComponent("scheduler").WithField("job_name", schedule.JobName).Error("Job not found for schedule")
+		return nil
+	}
+
+	if !job.Enabled {
+		s.logger.WithComponent("scheduler").WithField("job_name", schedule.JobName).Debug("Job disabled, skipping schedule")
+		return nil
+	}
+
+	cronSpec := schedule.Cron
+	if schedule.Timezone != "" {
+		if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q for schedule %s: %w", schedule.Timezone, schedule.JobName, err)
 		}
+		// robfig/cron/v3 reads a leading CRON_TZ=<zone> prefix and
+		// evaluates the rest of the spec in that location.
+		cronSpec = fmt.Sprintf("CRON_TZ=%s %s", schedule.Timezone, schedule.Cron)
+	}
 
-		// Add cron job
-		_, err := s.cron.AddFunc(schedule.Cron, func() {
-			s.executeJob(job)
-		})
+	_, warnings, err := ValidateCron(cronSpec)
+	if err != nil {
+		return fmt.Errorf("invalid cron expression for schedule %s: %w", schedule.JobName, err)
+	}
+	for _, w := range warnings {
+		s.logger.WithComponent("scheduler").WithField("job_name", schedule.JobName).WithField("cron", cronSpec).Warn(w)
+	}
+
+	var jitter time.Duration
+	if schedule.Jitter != "" {
+		d, err := time.ParseDuration(schedule.Jitter)
 		if err != nil {
-			return fmt.Errorf("failed to add cron job for %s: %w", schedule.JobName, err)
+			return fmt.Errorf("invalid jitter %q for schedule %s: %w", schedule.Jitter, schedule.JobName, err)
 		}
+		jitter = d
+	}
 
-		s.logger.WithComponent("scheduler").WithFields(map[string]interface{}{
-			"job_name": schedule.JobName,
-			"cron":     schedule.Cron,
-		}).Info("Scheduled job added")
+	// Add cron job
+	var entryID cron.EntryID
+	entryID, err := s.cron.AddFunc(cronSpec, func() {
+		if jitter > 0 {
+			time.Sleep(time.Duration(rand.Int63n(int64(jitter))))
+		}
+		// entry.Prev is the fire time that triggered this invocation
+		// (cron advances it just before running the job), so it doubles
+		// as the "scheduled for" timestamp for the creation-lag gauge.
+		scheduledFor := s.cron.Entry(entryID).Prev
+		s.executeJob(job, scheduledFor)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add cron job for %s: %w", schedule.JobName, err)
 	}
+	s.entryIDs[schedule.JobName] = entryID
+	s.cronSpecs[schedule.JobName] = cronSpec
 
+	s.logger.WithComponent("scheduler").WithFields(map[string]interface{}{
+		"job_name": schedule.JobName,
+		"cron":     cronSpec,
+		"jitter":   jitter.String(),
+	}).Info("Scheduled job added")
 	return nil
 }
 
+// removeSchedule stops and removes jobName's cron entry, if it has one.
+// Callers must hold s.mu.
+func (s *Scheduler) removeSchedule(jobName string) {
+	entryID, exists := s.entryIDs[jobName]
+	if !exists {
+		return
+	}
+	s.cron.Remove(entryID)
+	delete(s.entryIDs, jobName)
+	delete(s.cronSpecs, jobName)
+	delete(s.jobs, jobName)
+	delete(s.semaphores, jobName)
+	s.logger.WithComponent("scheduler").WithField("job_name", jobName).Info("Scheduled job removed")
+}
+
 // Start starts the scheduler
 func (s *Scheduler) Start() {
 	s.logger.WithComponent("scheduler").Info("Starting job scheduler")
@@ -96,6 +292,54 @@ func (s *Scheduler) Stop() {
 	s.logger.WithComponent("scheduler").Info("Stopping job scheduler")
 	ctx := s.cron.Stop()
 	<-ctx.Done()
+
+	s.mu.RLock()
+	handles := make(map[string]*leaseHandle, len(s.heldLeases))
+	for name, handle := range s.heldLeases {
+		handles[name] = handle
+	}
+	s.mu.RUnlock()
+
+	for name, handle := range handles {
+		handle.release(s, name)
+	}
+}
+
+// jobSemaphore returns jobName's concurrency-limiting semaphore, lazily
+// creating one sized DefaultMaxConcurrent if LoadJobs was never called for
+// it (e.g. a test driving executeJob directly).
+func (s *Scheduler) jobSemaphore(jobName string) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sem, ok := s.semaphores[jobName]
+	if !ok {
+		sem = make(chan struct{}, DefaultMaxConcurrent)
+		s.semaphores[jobName] = sem
+	}
+	return sem
+}
+
+// startLeaseHeartbeat renews jobName's lease on a ticker at leaseTTL/3, so a
+// long-running job (the 25-minute LLM jobs this scheduler runs) doesn't lose
+// its lease mid-execution. The returned channel stops the heartbeat when
+// closed.
+func (s *Scheduler) startLeaseHeartbeat(jobName string) chan struct{} {
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(s.leaseTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.leaser.Acquire(context.Background(), jobName, s.leaseTTL); err != nil {
+					s.logger.WithComponent("scheduler").WithField("job_name", jobName).WithError(err).Warn("Failed to renew job lease")
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return stop
 }
 
 // ExecuteJob executes a job immediately (manual trigger)
@@ -108,7 +352,7 @@ func (s *Scheduler) ExecuteJob(jobName string) error {
 		return fmt.Errorf("job %s not found", jobName)
 	}
 
-	go s.executeJob(job)
+	go s.executeJob(job, time.Time{})
 	return nil
 }
 
@@ -125,35 +369,212 @@ func (s *Scheduler) ExecuteAllJobs() {
 
 	s.logger.WithComponent("scheduler").WithField("job_count", len(jobs)).Info("Executing all enabled jobs")
 
-	// Execute jobs sequentially to avoid API rate limiting
+	// Fan jobs out to run concurrently; the shared rate limiter and each
+	// job's semaphore (see executeJob) provide the pacing that the old
+	// fixed inter-job sleep used to.
+	var wg sync.WaitGroup
 	for _, job := range jobs {
-		s.executeJob(job)
-		// Add delay between jobs to prevent API overload
-		time.Sleep(5 * time.Second)
+		wg.Add(1)
+		go func(job config.JobConfig) {
+			defer wg.Done()
+			s.executeJob(job, time.Time{})
+		}(job)
 	}
+	wg.Wait()
 }
 
-// executeJob executes a single job with error handling
-func (s *Scheduler) executeJob(job config.JobConfig) {
+// executeJob executes a single job, retrying transient failures with
+// exponential backoff per job.Retry (or the package defaults). scheduledFor
+// is the cron fire time that triggered this run, used for the
+// creation-lag gauge; pass the zero time for manually-triggered runs, which
+// have no schedule to lag behind.
+func (s *Scheduler) executeJob(job config.JobConfig, scheduledFor time.Time) {
+	trigger := jobs.TriggerManual
+	if !scheduledFor.IsZero() {
+		trigger = jobs.TriggerCron
+	}
+
+	// Only cron ticks are ever skipped for an open breaker; a manual
+	// trigger (Discord `!osrs run`, or ExecuteJob/ExecuteAllJobs) always
+	// runs, and resets the breaker on success.
+	if trigger == jobs.TriggerCron && s.shouldSkipTick(job.Name) {
+		s.logger.WithComponent("scheduler").WithField("job_name", job.Name).Info("Circuit breaker open, skipping scheduled run")
+		return
+	}
+
+	acquired, err := s.leaser.Acquire(context.Background(), job.Name, s.leaseTTL)
+	if err != nil {
+		s.logger.WithComponent("scheduler").WithField("job_name", job.Name).WithError(err).Error("Failed to acquire job lease")
+		return
+	}
+	if !acquired {
+		s.logger.WithComponent("scheduler").WithField("job_name", job.Name).Debug("Job lease held by another scheduler replica, skipping run")
+		return
+	}
+
+	handle := &leaseHandle{stopHeartbeat: s.startLeaseHeartbeat(job.Name)}
+	s.mu.Lock()
+	s.heldLeases[job.Name] = handle
+	s.mu.Unlock()
+	defer handle.release(s, job.Name)
+
+	waitStart := time.Now()
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		s.logger.WithComponent("scheduler").WithField("job_name", job.Name).WithError(err).Error("Failed waiting on API rate limiter")
+		return
+	}
+
+	sem := s.jobSemaphore(job.Name)
+	sem <- struct{}{}
+	defer func() { <-sem }()
+	s.metrics.queueWaitSeconds.WithLabelValues(job.Name).Observe(time.Since(waitStart).Seconds())
+
+	if !scheduledFor.IsZero() {
+		s.metrics.creationLag.WithLabelValues(job.Name).Set(time.Since(scheduledFor).Seconds())
+	}
+
 	timeout := 25 * time.Minute
 	if job.Model != nil && job.Model.Timeout != nil {
-		if jobTimeout, err := time.ParseDuration(*job.Model.Timeout); err != nil {
-			timeout = jobTimeout
-			s.logger.WithField("timeout", timeout).Info("parsed_timeout_from_job_config")
+		timeout = job.Model.Timeout.Dur()
+	}
+
+	maxAttempts, initialBackoff, maxBackoff, multiplier, jitter := resolveRetryPolicy(job)
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		s.setRetryState(job.Name, attempt, time.Time{})
+
+		s.logger.WithComponent("scheduler").WithField("job_name", job.Name).
+			WithField("model", job.Model.Name).
+			WithField("ctx", job.Model.NumCtx).
+			WithField("attempt", attempt).
+			Info("Executing scheduled job")
+
+		attemptStart := time.Now()
+		ctx, cancel := context.WithTimeout(jobs.WithTrigger(context.Background(), trigger), timeout)
+		err := s.executor.ExecuteJob(ctx, job)
+		cancel()
+
+		outcome := "success"
+		if err != nil {
+			outcome = "failure"
+		}
+		s.metrics.executionSeconds.WithLabelValues(job.Name, outcome).Observe(time.Since(attemptStart).Seconds())
+
+		if err == nil {
+			s.recordSuccess(job.Name)
+			s.recordBreakerSuccess(job.Name)
+			s.clearRetryState(job.Name)
+			return
 		}
+		lastErr = err
+
+		if attempt == maxAttempts || !shouldRetry(err) {
+			break
+		}
+
+		backoff := computeBackoff(attempt, initialBackoff, maxBackoff, multiplier, jitter)
+		s.setRetryState(job.Name, attempt, time.Now().Add(backoff))
+		s.logger.WithComponent("scheduler").WithFields(map[string]interface{}{
+			"job_name": job.Name,
+			"attempt":  attempt,
+			"backoff":  backoff.String(),
+		}).WithError(err).Warn("Job execution failed, retrying")
+
+		time.Sleep(backoff)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	s.clearRetryState(job.Name)
+	s.recordBreakerFailure(job.Name)
+	s.logger.WithComponent("scheduler").WithField("job_name", job.Name).WithError(lastErr).Error("Job execution failed")
+}
 
-	s.logger.WithComponent("scheduler").WithField("job_name", job.Name).
-		WithField("model", job.Model.Name).
-		WithField("ctx", job.Model.NumCtx).
-		Info("Executing scheduled job")
+// resolveRetryPolicy reads job.Retry over the package defaults. Duration
+// strings that fail to parse are ignored, leaving the default in place.
+func resolveRetryPolicy(job config.JobConfig) (maxAttempts int, initialBackoff, maxBackoff time.Duration, multiplier, jitter float64) {
+	maxAttempts = DefaultMaxAttempts
+	initialBackoff = DefaultInitialBackoff
+	maxBackoff = DefaultMaxBackoff
+	multiplier = DefaultMultiplier
+	jitter = DefaultJitter
+
+	if job.Retry == nil {
+		return
+	}
+	if job.Retry.MaxAttempts != nil {
+		maxAttempts = *job.Retry.MaxAttempts
+	}
+	if job.Retry.InitialBackoff != nil {
+		if d, err := time.ParseDuration(*job.Retry.InitialBackoff); err == nil {
+			initialBackoff = d
+		}
+	}
+	if job.Retry.MaxBackoff != nil {
+		if d, err := time.ParseDuration(*job.Retry.MaxBackoff); err == nil {
+			maxBackoff = d
+		}
+	}
+	if job.Retry.Multiplier != nil {
+		multiplier = *job.Retry.Multiplier
+	}
+	if job.Retry.Jitter != nil {
+		jitter = *job.Retry.Jitter
+	}
+	return
+}
+
+// computeBackoff returns the exponential backoff for the given (1-indexed)
+// attempt, clamped to maxBackoff, with the policy's jitter fraction applied
+// as symmetric random flex so closely-spaced failures don't all retry in
+// lockstep.
+func computeBackoff(attempt int, initialBackoff, maxBackoff time.Duration, multiplier, jitter float64) time.Duration {
+	backoff := float64(initialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if backoff > float64(maxBackoff) {
+		backoff = float64(maxBackoff)
+	}
+	if jitter > 0 {
+		backoff += backoff * jitter * (rand.Float64()*2 - 1)
+		if backoff < 0 {
+			backoff = 0
+		}
+	}
+	return time.Duration(backoff)
+}
 
-	if err := s.executor.ExecuteJob(ctx, job); err != nil {
-		s.logger.WithComponent("scheduler").WithField("job_name", job.Name).WithError(err).Error("Job execution failed")
+// shouldRetry classifies an ExecuteJob error: context cancellation/timeout
+// and anything wrapping jobs.ErrNonRetryable never retry, an error
+// implementing jobs.Retryable defers to its Retryable() result, and
+// everything else is assumed transient.
+func shouldRetry(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, jobs.ErrNonRetryable) {
+		return false
+	}
+	var retryable jobs.Retryable
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
 	}
+	return true
+}
+
+// setRetryState records the current attempt number and (if retrying) the
+// time the next attempt is scheduled for.
+func (s *Scheduler) setRetryState(jobName string, attempt int, nextRetryAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retryState[jobName] = &JobRetryState{Attempt: attempt, NextRetryAt: nextRetryAt}
+}
+
+// clearRetryState removes a job's retry bookkeeping once it succeeds or
+// gives up. Retry state is in-memory only -- a scheduler restart loses
+// track of attempts already made, and the job's next cron tick (or a
+// manual ExecuteJob call) starts a fresh attempt count.
+func (s *Scheduler) clearRetryState(jobName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.retryState, jobName)
 }
 
 // GetJobNames returns a list of all configured job names
@@ -168,19 +589,90 @@ func (s *Scheduler) GetJobNames() []string {
 	return names
 }
 
-// GetJobStatus returns the status of all jobs
-func (s *Scheduler) GetJobStatus() map[string]bool {
+// GetJobStatus returns each job's enabled flag plus any in-progress retry
+// state, so a manual ExecuteJob call (or a status command) can see a retry
+// already under way rather than firing a duplicate attempt.
+func (s *Scheduler) GetJobStatus() map[string]JobStatus {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	status := make(map[string]bool, len(s.jobs))
+	status := make(map[string]JobStatus, len(s.jobs))
 	for name, job := range s.jobs {
-		status[name] = job.Enabled
+		jobStatus := JobStatus{Enabled: job.Enabled}
+		if retry, ok := s.retryState[name]; ok {
+			state := *retry
+			jobStatus.Retry = &state
+		}
+		jobStatus.Breaker = s.breakerStatus(name)
+		status[name] = jobStatus
 	}
 	return status
 }
 
+// JobStatus returns a single job's enabled flag, in-progress retry state,
+// and circuit-breaker state, or false if no such job is configured. This is
+// the per-job counterpart to GetJobStatus, for the `!osrs status <jobName>`
+// Discord command.
+func (s *Scheduler) JobStatus(name string) (JobStatus, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, exists := s.jobs[name]
+	if !exists {
+		return JobStatus{}, false
+	}
+
+	status := JobStatus{Enabled: job.Enabled}
+	if retry, ok := s.retryState[name]; ok {
+		state := *retry
+		status.Retry = &state
+	}
+	status.Breaker = s.breakerStatus(name)
+	return status, true
+}
+
 // IsRunning returns whether the scheduler is currently running
 func (s *Scheduler) IsRunning() bool {
 	return len(s.cron.Entries()) > 0
 }
+
+// ScheduleDescription is a human-readable rendering of one job's cron
+// schedule, for the `!osrs schedules` Discord command.
+type ScheduleDescription struct {
+	JobName  string
+	Cron     string
+	Human    string
+	Warnings []string
+	Next     time.Time
+}
+
+// DescribeSchedules returns a ScheduleDescription for every job with an
+// active cron entry, so operators can confirm cron semantics (and see any
+// degenerate-step warnings) without reading config.yml.
+func (s *Scheduler) DescribeSchedules() []ScheduleDescription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	descs := make([]ScheduleDescription, 0, len(s.cronSpecs))
+	for jobName, cronSpec := range s.cronSpecs {
+		human, warnings, err := ValidateCron(cronSpec)
+		if err != nil {
+			// cronSpecs only ever holds specs that already passed
+			// ValidateCron in addSchedule, so this would mean the spec
+			// changed underneath us somehow; report it rather than panic.
+			human = fmt.Sprintf("failed to re-validate: %v", err)
+		}
+		var next time.Time
+		if entryID, ok := s.entryIDs[jobName]; ok {
+			next = s.cron.Entry(entryID).Next
+		}
+		descs = append(descs, ScheduleDescription{
+			JobName:  jobName,
+			Cron:     cronSpec,
+			Human:    human,
+			Warnings: warnings,
+			Next:     next,
+		})
+	}
+	return descs
+}
@@ -2,13 +2,19 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"osrs-flipping/pkg/config"
+	"osrs-flipping/pkg/jobs"
 	"osrs-flipping/pkg/logging"
 
 	"github.com/robfig/cron/v3"
+	"golang.org/x/time/rate"
 )
 
 // MockJobExecutor implements the JobExecutor interface for testing
@@ -289,5 +295,377 @@ func TestSchedulerDisabledJobsAndSchedules(t *testing.T) {
 }
 
 // Helper functions for test configuration
-func stringPtr(s string) *string { return &s }
-func intPtr(i int) *int          { return &i }
+func stringPtr(s string) *string  { return &s }
+func intPtr(i int) *int           { return &i }
+func floatPtr(f float64) *float64 { return &f }
+
+// flakyExecutor fails its first `failures` calls, then succeeds.
+type flakyExecutor struct {
+	failures int
+	calls    int
+}
+
+func (f *flakyExecutor) ExecuteJob(ctx context.Context, job config.JobConfig) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return fmt.Errorf("transient failure on attempt %d", f.calls)
+	}
+	return nil
+}
+
+func (f *flakyExecutor) ExecuteAllJobs(ctx context.Context) error { return nil }
+
+// alwaysFailExecutor always returns the same error, for testing that
+// non-retryable errors stop the retry loop after one attempt.
+type alwaysFailExecutor struct {
+	calls int
+	err   error
+}
+
+func (a *alwaysFailExecutor) ExecuteJob(ctx context.Context, job config.JobConfig) error {
+	a.calls++
+	return a.err
+}
+
+func (a *alwaysFailExecutor) ExecuteAllJobs(ctx context.Context) error { return nil }
+
+func testRetryPolicy() *config.RetryPolicy {
+	return &config.RetryPolicy{
+		MaxAttempts:    intPtr(5),
+		InitialBackoff: stringPtr("1ms"),
+		MaxBackoff:     stringPtr("5ms"),
+		Multiplier:     floatPtr(1),
+		Jitter:         floatPtr(0),
+	}
+}
+
+func TestSchedulerRetriesTransientFailures(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	executor := &flakyExecutor{failures: 2}
+	scheduler := NewScheduler(logger, executor)
+
+	job := config.JobConfig{Name: "Retry Job", Enabled: true, Retry: testRetryPolicy()}
+	scheduler.executeJob(job, time.Time{})
+
+	if executor.calls != 3 {
+		t.Errorf("expected 3 attempts (2 failures then a success), got %d", executor.calls)
+	}
+	if status := scheduler.GetJobStatus()["Retry Job"]; status.Retry != nil {
+		t.Errorf("expected no retry state left after an eventual success, got %+v", status.Retry)
+	}
+}
+
+func TestSchedulerGivesUpAfterMaxAttempts(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	executor := &flakyExecutor{failures: 100}
+	scheduler := NewScheduler(logger, executor)
+
+	job := config.JobConfig{Name: "Always Fails", Enabled: true, Retry: testRetryPolicy()}
+	scheduler.executeJob(job, time.Time{})
+
+	if executor.calls != 5 {
+		t.Errorf("expected exactly MaxAttempts=5 calls, got %d", executor.calls)
+	}
+}
+
+func TestSchedulerDoesNotRetryNonRetryableError(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	executor := &alwaysFailExecutor{err: fmt.Errorf("bad config: %w", jobs.ErrNonRetryable)}
+	scheduler := NewScheduler(logger, executor)
+
+	job := config.JobConfig{Name: "Non-retryable Job", Enabled: true, Retry: testRetryPolicy()}
+	scheduler.executeJob(job, time.Time{})
+
+	if executor.calls != 1 {
+		t.Errorf("expected exactly 1 attempt for a non-retryable error, got %d", executor.calls)
+	}
+}
+
+func TestComputeBackoffGrowsAndClamps(t *testing.T) {
+	first := computeBackoff(1, 10*time.Second, time.Minute, 2, 0)
+	if first != 10*time.Second {
+		t.Errorf("expected first-attempt backoff of 10s, got %v", first)
+	}
+
+	second := computeBackoff(2, 10*time.Second, time.Minute, 2, 0)
+	if second != 20*time.Second {
+		t.Errorf("expected second-attempt backoff of 20s, got %v", second)
+	}
+
+	clamped := computeBackoff(10, 10*time.Second, time.Minute, 2, 0)
+	if clamped != time.Minute {
+		t.Errorf("expected backoff clamped to the 1m max, got %v", clamped)
+	}
+}
+
+func TestLoadJobsRejectsInvalidTimezone(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	scheduler := NewScheduler(logger, &MockJobExecutor{})
+
+	cfg := &config.Config{
+		Jobs:      []config.JobConfig{{Name: "TZ Job", Enabled: true}},
+		Schedules: []config.ScheduleConfig{{JobName: "TZ Job", Cron: "0 0 */1 * * *", Enabled: true, Timezone: "Not/A_Zone"}},
+	}
+
+	if err := scheduler.LoadJobs(cfg); err == nil {
+		t.Error("expected an error for an invalid timezone, got nil")
+	}
+}
+
+func TestLoadJobsRejectsInvalidJitter(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	scheduler := NewScheduler(logger, &MockJobExecutor{})
+
+	cfg := &config.Config{
+		Jobs:      []config.JobConfig{{Name: "Jitter Job", Enabled: true}},
+		Schedules: []config.ScheduleConfig{{JobName: "Jitter Job", Cron: "0 0 */1 * * *", Enabled: true, Jitter: "not-a-duration"}},
+	}
+
+	if err := scheduler.LoadJobs(cfg); err == nil {
+		t.Error("expected an error for an invalid jitter duration, got nil")
+	}
+}
+
+func TestLoadJobsAcceptsValidTimezoneAndJitter(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	scheduler := NewScheduler(logger, &MockJobExecutor{})
+
+	cfg := &config.Config{
+		Jobs: []config.JobConfig{{Name: "TZ Job", Enabled: true}},
+		Schedules: []config.ScheduleConfig{
+			{JobName: "TZ Job", Cron: "0 0 2 * * 3", Enabled: true, Timezone: "UTC", Jitter: "30s"},
+		},
+	}
+
+	if err := scheduler.LoadJobs(cfg); err != nil {
+		t.Fatalf("expected a valid timezone/jitter schedule to load, got: %v", err)
+	}
+	if entries := scheduler.cron.Entries(); len(entries) != 1 {
+		t.Errorf("expected 1 active cron entry, got %d", len(entries))
+	}
+}
+
+func TestNextRunsReportsScheduledJobs(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	sched := NewScheduler(logger, &MockJobExecutor{})
+
+	cfg := &config.Config{
+		Jobs:      []config.JobConfig{{Name: "Next Job", Enabled: true}},
+		Schedules: []config.ScheduleConfig{{JobName: "Next Job", Cron: "0 0 */1 * * *", Enabled: true}},
+	}
+	if err := sched.LoadJobs(cfg); err != nil {
+		t.Fatalf("LoadJobs: %v", err)
+	}
+	sched.Start()
+	defer sched.Stop()
+
+	next, ok := sched.NextRun("Next Job")
+	if !ok {
+		t.Fatal("expected Next Job to have a next run time")
+	}
+	if next.Before(time.Now()) {
+		t.Errorf("expected a next run time in the future, got %v", next)
+	}
+
+	runs := sched.NextRuns()
+	if _, ok := runs["Next Job"]; !ok {
+		t.Error("expected NextRuns to include Next Job")
+	}
+
+	if _, ok := sched.NextRun("No Such Job"); ok {
+		t.Error("expected NextRun to report false for an unscheduled job")
+	}
+}
+
+func TestMetricsRecordExecutionAndSuccess(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	executor := &MockJobExecutor{}
+	scheduler := NewScheduler(logger, executor)
+
+	job := config.JobConfig{Name: "Metrics Job", Enabled: true}
+	scheduler.LoadJobs(&config.Config{Jobs: []config.JobConfig{job}})
+	scheduler.executeJob(job, time.Time{})
+
+	families, err := scheduler.metrics.registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+
+	var sawExecutionSeconds, sawLastSuccessAge bool
+	for _, family := range families {
+		switch family.GetName() {
+		case "osrs_job_execution_duration_seconds":
+			sawExecutionSeconds = true
+			if len(family.GetMetric()) != 1 {
+				t.Errorf("expected 1 execution_duration_seconds series, got %d", len(family.GetMetric()))
+			}
+		case "osrs_job_last_success_age_seconds":
+			sawLastSuccessAge = true
+		}
+	}
+
+	if !sawExecutionSeconds {
+		t.Error("expected osrs_job_execution_duration_seconds to be recorded")
+	}
+	if !sawLastSuccessAge {
+		t.Error("expected osrs_job_last_success_age_seconds to be reported after a successful run")
+	}
+}
+
+func TestMetricsHandlerServesRegistry(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	scheduler := NewScheduler(logger, &MockJobExecutor{})
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	scheduler.MetricsHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 from the metrics handler, got %d", rec.Code)
+	}
+}
+
+// fakeLeaser is an in-memory JobLeaser test double. Acquire fails for any
+// job name in deny, and every Acquire/Release call is recorded so tests can
+// assert on leasing behavior without a real Postgres instance.
+type fakeLeaser struct {
+	mu       sync.Mutex
+	deny     map[string]bool
+	acquired []string
+	released []string
+}
+
+func (f *fakeLeaser) Acquire(ctx context.Context, jobName string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.deny[jobName] {
+		return false, nil
+	}
+	f.acquired = append(f.acquired, jobName)
+	return true, nil
+}
+
+func (f *fakeLeaser) Release(ctx context.Context, jobName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.released = append(f.released, jobName)
+	return nil
+}
+
+func TestExecuteJobSkipsWhenLeaseDenied(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	executor := &MockJobExecutor{}
+	leaser := &fakeLeaser{deny: map[string]bool{"Leased Job": true}}
+	scheduler := NewScheduler(logger, executor, WithLeaser(leaser))
+
+	job := config.JobConfig{Name: "Leased Job", Enabled: true}
+	scheduler.executeJob(job, time.Time{})
+
+	if executor.executions != 0 {
+		t.Errorf("expected the job not to run when its lease is held elsewhere, got %d executions", executor.executions)
+	}
+}
+
+func TestExecuteJobReleasesLeaseAfterRun(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	executor := &MockJobExecutor{}
+	leaser := &fakeLeaser{}
+	scheduler := NewScheduler(logger, executor, WithLeaser(leaser), WithLeaseTTL(time.Minute))
+
+	job := config.JobConfig{Name: "Leased Job", Enabled: true}
+	scheduler.executeJob(job, time.Time{})
+
+	leaser.mu.Lock()
+	defer leaser.mu.Unlock()
+	if len(leaser.released) != 1 || leaser.released[0] != "Leased Job" {
+		t.Errorf("expected the lease to be released after the job finished, got %v", leaser.released)
+	}
+	if _, held := scheduler.heldLeases["Leased Job"]; held {
+		t.Error("expected the lease handle to be removed from heldLeases after release")
+	}
+}
+
+func TestStopReleasesHeldLeases(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	leaser := &fakeLeaser{}
+	scheduler := NewScheduler(logger, &MockJobExecutor{}, WithLeaser(leaser), WithLeaseTTL(time.Minute))
+
+	scheduler.mu.Lock()
+	scheduler.heldLeases["Stale Job"] = &leaseHandle{stopHeartbeat: make(chan struct{})}
+	scheduler.mu.Unlock()
+
+	scheduler.Stop()
+
+	leaser.mu.Lock()
+	defer leaser.mu.Unlock()
+	if len(leaser.released) != 1 || leaser.released[0] != "Stale Job" {
+		t.Errorf("expected Stop to release held leases, got %v", leaser.released)
+	}
+}
+
+// concurrencyTrackingExecutor records the highest number of concurrent
+// ExecuteJob calls it observed, for asserting MaxConcurrent is enforced.
+type concurrencyTrackingExecutor struct {
+	mu       sync.Mutex
+	running  int
+	observed int
+}
+
+func (c *concurrencyTrackingExecutor) ExecuteJob(ctx context.Context, job config.JobConfig) error {
+	c.mu.Lock()
+	c.running++
+	if c.running > c.observed {
+		c.observed = c.running
+	}
+	c.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	c.mu.Lock()
+	c.running--
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *concurrencyTrackingExecutor) ExecuteAllJobs(ctx context.Context) error { return nil }
+
+func TestExecuteJobEnforcesMaxConcurrent(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	executor := &concurrencyTrackingExecutor{}
+	scheduler := NewScheduler(logger, executor)
+	scheduler.limiter = rate.NewLimiter(rate.Inf, 1)
+
+	job := config.JobConfig{Name: "Capped Job", Enabled: true, MaxConcurrent: 1}
+	if err := scheduler.LoadJobs(&config.Config{Jobs: []config.JobConfig{job}}); err != nil {
+		t.Fatalf("LoadJobs failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.executeJob(job, time.Time{})
+		}()
+	}
+	wg.Wait()
+
+	if executor.observed != 1 {
+		t.Errorf("expected MaxConcurrent=1 to cap overlapping runs at 1, observed %d concurrently", executor.observed)
+	}
+}
+
+func TestShouldRetryClassification(t *testing.T) {
+	if shouldRetry(context.DeadlineExceeded) {
+		t.Error("expected context.DeadlineExceeded to not be retried")
+	}
+	if shouldRetry(context.Canceled) {
+		t.Error("expected context.Canceled to not be retried")
+	}
+	if shouldRetry(fmt.Errorf("wrapped: %w", jobs.ErrNonRetryable)) {
+		t.Error("expected a wrapped ErrNonRetryable to not be retried")
+	}
+	if !shouldRetry(fmt.Errorf("some transient error")) {
+		t.Error("expected a plain error to be retried")
+	}
+}
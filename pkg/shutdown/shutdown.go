@@ -0,0 +1,120 @@
+// Package shutdown coordinates graceful process termination: it listens for
+// SIGINT/SIGTERM, cancels a root context so in-flight work can wind down,
+// then closes registered hooks in LIFO order (last registered, first closed
+// -- mirroring defer semantics so a resource is closed before the thing it
+// depends on). SIGHUP is deliberately not included here -- it now means
+// "reload config" (see config.Config.Watch), not "shut down".
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"osrs-flipping/pkg/logging"
+)
+
+// DefaultDrainTimeout is how long Wait gives in-flight work to finish after
+// the root context is canceled, before closing hooks regardless.
+const DefaultDrainTimeout = 30 * time.Second
+
+// Hook is a named shutdown action, e.g. closing a DB pool or Discord
+// session. Name is used only for logging.
+type Hook struct {
+	Name  string
+	Close func(ctx context.Context) error
+}
+
+// Coordinator owns the root context and the ordered list of shutdown hooks.
+type Coordinator struct {
+	logger       *logging.Logger
+	drainTimeout time.Duration
+
+	mu    sync.Mutex
+	hooks []Hook
+
+	cancel context.CancelFunc
+}
+
+// New creates a Coordinator whose root context is canceled on
+// SIGINT/SIGTERM. drainTimeout <= 0 uses DefaultDrainTimeout.
+func New(logger *logging.Logger, drainTimeout time.Duration) (*Coordinator, context.Context) {
+	if drainTimeout <= 0 {
+		drainTimeout = DefaultDrainTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Coordinator{
+		logger:       logger,
+		drainTimeout: drainTimeout,
+		cancel:       cancel,
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		sig := <-sigChan
+		c.logger.WithComponent("shutdown").WithField("signal", sig.String()).Info("Shutdown signal received")
+		cancel()
+	}()
+
+	return c, ctx
+}
+
+// Register adds a Close hook. Hooks are closed in LIFO order: the most
+// recently registered hook (typically the thing with the fewest
+// dependents, e.g. the logger) is closed last, and vice versa -- so
+// register infrastructure (DB pool, Discord session) before the things that
+// use it (job runner), and it unwinds in the right order automatically.
+func (c *Coordinator) Register(name string, close func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = append(c.hooks, Hook{Name: name, Close: close})
+}
+
+// Wait blocks until the root context is canceled, waits up to drainTimeout
+// for in-flight work to notice cancellation and finish (e.g. RunJob calls
+// writing their markdown output), then closes all registered hooks in LIFO
+// order.
+func (c *Coordinator) Wait(ctx context.Context, drained <-chan struct{}) {
+	<-ctx.Done()
+
+	if drained != nil {
+		select {
+		case <-drained:
+			c.logger.WithComponent("shutdown").Info("In-flight work drained cleanly")
+		case <-time.After(c.drainTimeout):
+			c.logger.WithComponent("shutdown").Warn("Drain timeout exceeded, closing hooks anyway")
+		}
+	}
+
+	c.closeHooks()
+}
+
+func (c *Coordinator) closeHooks() {
+	c.mu.Lock()
+	hooks := make([]Hook, len(c.hooks))
+	copy(hooks, c.hooks)
+	c.mu.Unlock()
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), c.drainTimeout)
+	defer cancel()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		c.logger.WithComponent("shutdown").WithField("hook", h.Name).Info("Closing shutdown hook")
+		if err := h.Close(closeCtx); err != nil {
+			c.logger.WithComponent("shutdown").WithField("hook", h.Name).WithError(err).Error("Shutdown hook failed")
+		}
+	}
+}
+
+// Err wraps a hook error with its hook name for easier log correlation.
+func Err(name string, err error) error {
+	return fmt.Errorf("shutdown hook %q failed: %w", name, err)
+}
@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"osrs-flipping/pkg/logging"
+)
+
+// FlipCandidate is one row of the flip_candidates materialized view (see
+// migrations/000005_create_flip_candidates_view.up.sql): an item's latest
+// price, pre-tax margin (matching osrs.Analyzer.computeDerivedColumns'
+// convention of not deducting GE tax), and trailing 24h volume.
+type FlipCandidate struct {
+	ItemID           int
+	Name             string
+	Members          bool
+	BuyLimit         int
+	InstaBuyPrice    *int
+	InstaSellPrice   *int
+	MarginGP         *int
+	MarginPct        *float64
+	Volume24h        int64
+	SpreadAgeMinutes float64
+}
+
+// FlipCandidateFilter composes predicates and pagination for
+// GetFlipCandidates, the same optional-pointer-field style
+// osrs.FilterOptions uses for its min/max thresholds.
+type FlipCandidateFilter struct {
+	MinVolume24h        *int64
+	MaxSpreadAgeMinutes *float64
+	MinMarginGP         *int
+	MembersOnly         *bool
+
+	// SortBy selects a candidateRankings entry; "" defaults to "margin".
+	SortBy string
+	Limit  int
+	Offset int
+}
+
+// candidateRankings maps a FlipCandidateFilter.SortBy name to the
+// flip_candidates ORDER BY expression it ranks by, mirroring
+// osrs.Analyzer.sortItems' string-keyed switch -- just producing SQL
+// instead of a Go comparator, since ranking has to happen before
+// Limit/Offset pagination in the database rather than after loading rows.
+// JobRunner.RunJob picks a profile per job instead of each job hand-rolling
+// its own ORDER BY.
+var candidateRankings = map[string]string{
+	"margin":    "margin_gp DESC NULLS LAST",
+	"volume":    "volume_24h DESC",
+	"freshness": "spread_age_minutes ASC",
+	"weighted":  "(COALESCE(margin_gp, 0) * LOG(GREATEST(volume_24h, 1)) / GREATEST(spread_age_minutes, 1.0)) DESC",
+}
+
+// GetFlipCandidates returns flip_candidates rows matching filter, ranked by
+// filter.SortBy and paginated by filter.Limit/filter.Offset -- the ranked,
+// LLM-ingestion-ready slice jobs.JobRunner.RunJob hands to its LLM feature
+// extraction step instead of re-deriving candidates from raw bucket/
+// observation queries per job.
+func (r *QueryRepository) GetFlipCandidates(ctx context.Context, filter FlipCandidateFilter) (candidates []FlipCandidate, err error) {
+	start := time.Now()
+	statBefore := r.pool.Stat()
+	defer func() {
+		r.recordQuery("GetFlipCandidates", "", start, statBefore, len(candidates), err)
+	}()
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	addCondition := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.MinVolume24h != nil {
+		addCondition("volume_24h >= $%d", *filter.MinVolume24h)
+	}
+	if filter.MaxSpreadAgeMinutes != nil {
+		addCondition("spread_age_minutes <= $%d", *filter.MaxSpreadAgeMinutes)
+	}
+	if filter.MinMarginGP != nil {
+		addCondition("margin_gp >= $%d", *filter.MinMarginGP)
+	}
+	if filter.MembersOnly != nil {
+		addCondition("members = $%d", *filter.MembersOnly)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	orderBy, ok := candidateRankings[filter.SortBy]
+	if !ok {
+		orderBy = candidateRankings["margin"]
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			item_id, name, members, buy_limit,
+			insta_buy_price, insta_sell_price, margin_gp, margin_pct,
+			volume_24h, spread_age_minutes
+		FROM flip_candidates
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, where, orderBy, len(args)+1, len(args)+2)
+	args = append(args, limit, filter.Offset)
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query flip candidates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c FlipCandidate
+		if err := rows.Scan(
+			&c.ItemID, &c.Name, &c.Members, &c.BuyLimit,
+			&c.InstaBuyPrice, &c.InstaSellPrice, &c.MarginGP, &c.MarginPct,
+			&c.Volume24h, &c.SpreadAgeMinutes,
+		); err != nil {
+			return nil, fmt.Errorf("scan flip candidate row: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, rows.Err()
+}
+
+// CandidateRefresher periodically runs REFRESH MATERIALIZED VIEW
+// CONCURRENTLY on flip_candidates, the same ticker-driven, fire-and-forget
+// shape as AggregateRefresher -- a separate type rather than folding into
+// AggregateRefresher since flip_candidates refreshes on its own schedule,
+// independent of the price_buckets_* rollup chain.
+type CandidateRefresher struct {
+	pool     *pgxpool.Pool
+	interval time.Duration
+	logger   *logging.Logger
+}
+
+// defaultCandidateRefreshInterval is how often CandidateRefresher refreshes
+// flip_candidates unless NewCandidateRefresher is given a different
+// interval -- a materialized view of 24h volume doesn't need to be any
+// fresher than that window itself changes meaningfully.
+const defaultCandidateRefreshInterval = 5 * time.Minute
+
+// NewCandidateRefresher creates a CandidateRefresher. interval <= 0 uses
+// defaultCandidateRefreshInterval.
+func NewCandidateRefresher(pool *pgxpool.Pool, interval time.Duration, logger *logging.Logger) *CandidateRefresher {
+	if interval <= 0 {
+		interval = defaultCandidateRefreshInterval
+	}
+	return &CandidateRefresher{pool: pool, interval: interval, logger: logger}
+}
+
+// Start launches a single ticking goroutine that refreshes flip_candidates
+// until ctx is canceled. It returns immediately, mirroring
+// AggregateRefresher.Start.
+func (c *CandidateRefresher) Start(ctx context.Context) {
+	go c.run(ctx)
+}
+
+func (c *CandidateRefresher) run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.refresh(ctx)
+		}
+	}
+}
+
+func (c *CandidateRefresher) refresh(ctx context.Context) {
+	if err := RefreshFlipCandidates(ctx, c.pool); err != nil {
+		c.logger.WithComponent("candidates").WithError(err).Error("flip_candidates refresh failed")
+	}
+}
+
+// RefreshFlipCandidates runs REFRESH MATERIALIZED VIEW CONCURRENTLY on
+// flip_candidates, which the idx_flip_candidates_item_id unique index
+// (see the migration) makes possible without blocking concurrent
+// GetFlipCandidates reads.
+func RefreshFlipCandidates(ctx context.Context, pool *pgxpool.Pool) error {
+	if _, err := pool.Exec(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY flip_candidates`); err != nil {
+		return fmt.Errorf("refresh flip_candidates: %w", err)
+	}
+	return nil
+}
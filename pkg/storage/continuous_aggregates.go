@@ -0,0 +1,305 @@
+// This file implements this repo's "Go-driven equivalent" of TimescaleDB
+// continuous aggregates: price_buckets_5m/1h/24h aren't TimescaleDB
+// hypertables in this deployment (collector.Repository.InsertPriceBuckets
+// already writes each grain directly from the wiki API poller), so instead
+// of add_continuous_aggregate_policy this package runs plain
+// INSERT ... SELECT ... ON CONFLICT rollups on a schedule via
+// AggregateRefresher, and GetMultiPeriodVolumeMetrics routes each query to
+// the finest grain whose retention window still covers the requested
+// lookback instead of a hardcoded table per period.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"osrs-flipping/pkg/logging"
+)
+
+// RefreshPolicy controls how often, and over what trailing window, an
+// AggregateSpec's rollup query runs -- mirroring TimescaleDB's
+// add_continuous_aggregate_policy(start_offset, end_offset,
+// schedule_interval) parameters.
+type RefreshPolicy struct {
+	Interval    time.Duration // how often AggregateRefresher re-runs this spec
+	StartOffset time.Duration // how far back from now the rollup window starts
+	EndOffset   time.Duration // how close to now the window ends, giving the source grain time to settle before being rolled up
+}
+
+// AggregateSpec is one rollup stage in the 5m -> 1h -> 24h chain: every
+// TargetWidth-aligned window of SourceTable is volume-weight-averaged into
+// one TargetTable row.
+type AggregateSpec struct {
+	Name        string
+	SourceTable string
+	TargetTable string
+	TargetWidth time.Duration
+	Policy      RefreshPolicy
+}
+
+// DefaultAggregateChain returns the 5m->1h->24h rollup chain this
+// package's three price_buckets_* tables form.
+func DefaultAggregateChain() []AggregateSpec {
+	return []AggregateSpec{
+		{
+			Name:        "5m_to_1h",
+			SourceTable: "price_buckets_5m",
+			TargetTable: "price_buckets_1h",
+			TargetWidth: time.Hour,
+			Policy:      RefreshPolicy{Interval: 10 * time.Minute, StartOffset: 3 * time.Hour, EndOffset: 10 * time.Minute},
+		},
+		{
+			Name:        "1h_to_24h",
+			SourceTable: "price_buckets_1h",
+			TargetTable: "price_buckets_24h",
+			TargetWidth: 24 * time.Hour,
+			Policy:      RefreshPolicy{Interval: time.Hour, StartOffset: 48 * time.Hour, EndOffset: time.Hour},
+		},
+	}
+}
+
+// bucketOrder is every bucket grain this package knows about, finest
+// first -- the order selectBucketTable walks when picking a table, and the
+// fallback order PruneExpired/AggregateRefresher iterate in.
+var bucketOrder = []string{"5m", "1h", "24h"}
+
+// BucketRetention is how long each grain's rows are kept before
+// PruneExpired deletes them. It starts from the same defaults as
+// collector.RetentionPolicy (kept as this package's own copy rather than
+// importing pkg/collector for three constants -- see osrs/backtest and
+// backtest's separately-duplicated buyLimitResetEvery for the same
+// pattern), and can be overridden per grain via config.yml's storage.
+// bucket_retention section (see LoadBucketRetentionFromConfig), with
+// BUCKET_RETENTION_5M/_1H/_24H environment variables layered on top for
+// per-deployment overrides (see LoadBucketRetentionFromEnv). Zero means
+// unlimited.
+var BucketRetention = map[string]time.Duration{
+	"5m":  7 * 24 * time.Hour,
+	"1h":  365 * 24 * time.Hour,
+	"24h": 0,
+}
+
+// LoadBucketRetentionFromConfig overrides BucketRetention's defaults from
+// overrides, keyed by grain ("5m"/"1h"/"24h") the same way BucketRetention
+// itself is. Takes a plain map rather than a pkg/config type so this
+// leaf-level package doesn't have to import pkg/config (which itself
+// imports pkg/llm -> pkg/osrs -> pkg/storage, so a direct dependency here
+// would be an import cycle) -- callers such as cmd/collector are expected
+// to convert config.StorageConfig.BucketRetention into this shape
+// themselves. A grain absent from overrides keeps its existing value; call
+// this before LoadBucketRetentionFromEnv so BUCKET_RETENTION_* env vars,
+// where set, still take precedence over YAML.
+func LoadBucketRetentionFromConfig(overrides map[string]time.Duration) {
+	for size, d := range overrides {
+		BucketRetention[size] = d
+	}
+}
+
+// LoadBucketRetentionFromEnv overrides BucketRetention's defaults from
+// BUCKET_RETENTION_5M/_1H/_24H, each a time.ParseDuration string (e.g.
+// "168h"); "0" or "" leaves that grain's current value untouched. Call once
+// at startup, after LoadBucketRetentionFromConfig, mirroring cmd/collector's
+// SLOW_QUERY_THRESHOLD_MS handling of its own optional env-var override.
+func LoadBucketRetentionFromEnv() {
+	for _, size := range bucketOrder {
+		envName := "BUCKET_RETENTION_" + bucketEnvSuffix(size)
+		raw := os.Getenv(envName)
+		if raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			continue
+		}
+		BucketRetention[size] = d
+	}
+}
+
+func bucketEnvSuffix(bucketSize string) string {
+	switch bucketSize {
+	case "5m":
+		return "5M"
+	case "1h":
+		return "1H"
+	case "24h":
+		return "24H"
+	default:
+		return ""
+	}
+}
+
+// selectBucketTable picks the finest-grained bucket table whose configured
+// BucketRetention still fully covers duration, falling back to
+// progressively coarser grains when a finer one's retention window is
+// shorter than the requested lookback (so the query wouldn't see the
+// whole range). Zero retention means unlimited. The coarsest grain is
+// always returned as a last resort even if its own retention improbably
+// doesn't cover duration, since there's nothing left to fall back to.
+//
+// This intentionally favors accuracy (the finest grain available) over
+// the old hardcoded "24h window always reads the 1h table" shortcut; a
+// cost-based planner that also weighs row count could revisit that
+// trade-off later.
+func selectBucketTable(duration time.Duration) string {
+	for i, size := range bucketOrder {
+		retention := BucketRetention[size]
+		if retention == 0 || retention >= duration || i == len(bucketOrder)-1 {
+			return size
+		}
+	}
+	return bucketOrder[0]
+}
+
+// AggregateRefresher periodically rolls up each AggregateSpec in specs and
+// prunes rows past BucketRetention, replacing a TimescaleDB deployment's
+// continuous aggregate + retention policies with plain goroutines.
+type AggregateRefresher struct {
+	pool   *pgxpool.Pool
+	specs  []AggregateSpec
+	logger *logging.Logger
+}
+
+// NewAggregateRefresher creates an AggregateRefresher for specs, typically
+// DefaultAggregateChain().
+func NewAggregateRefresher(pool *pgxpool.Pool, specs []AggregateSpec, logger *logging.Logger) *AggregateRefresher {
+	return &AggregateRefresher{pool: pool, specs: specs, logger: logger}
+}
+
+// pruneInterval is how often Start's pruning goroutine checks
+// BucketRetention against every grain, independent of any single spec's
+// refresh cadence.
+const pruneInterval = time.Hour
+
+// Start launches one goroutine per spec plus one pruning goroutine, each
+// ticking until ctx is canceled. It returns immediately -- callers that
+// need to wait for shutdown should track ctx's lifetime themselves,
+// mirroring collector.BackgroundSync's fire-and-forget Start.
+func (a *AggregateRefresher) Start(ctx context.Context) {
+	for _, spec := range a.specs {
+		go a.runSpec(ctx, spec)
+	}
+	go a.runPrune(ctx)
+}
+
+func (a *AggregateRefresher) runSpec(ctx context.Context, spec AggregateSpec) {
+	ticker := time.NewTicker(spec.Policy.Interval)
+	defer ticker.Stop()
+
+	a.refresh(ctx, spec)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.refresh(ctx, spec)
+		}
+	}
+}
+
+func (a *AggregateRefresher) refresh(ctx context.Context, spec AggregateSpec) {
+	if err := RefreshAggregate(ctx, a.pool, spec); err != nil {
+		a.logger.WithComponent("continuous_aggregates").WithFields(map[string]interface{}{
+			"spec":  spec.Name,
+			"error": err.Error(),
+		}).Error("aggregate refresh failed")
+	}
+}
+
+func (a *AggregateRefresher) runPrune(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	a.prune(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.prune(ctx)
+		}
+	}
+}
+
+func (a *AggregateRefresher) prune(ctx context.Context) {
+	if err := PruneExpired(ctx, a.pool); err != nil {
+		a.logger.WithComponent("continuous_aggregates").WithError(err).Error("bucket retention pruning failed")
+	}
+}
+
+// RefreshAggregate rolls up spec.SourceTable rows in
+// [now-spec.Policy.StartOffset, now-spec.Policy.EndOffset) into
+// spec.TargetTable, bucketing each source row into its TargetWidth-aligned
+// window and volume-weight-averaging prices -- the same
+// SUM(avg*vol)/SUM(vol) math QueryRepository.GetVolumeMetrics already uses
+// for ad-hoc range queries, just materialized into rows instead of
+// computed per request.
+func RefreshAggregate(ctx context.Context, pool *pgxpool.Pool, spec AggregateSpec) error {
+	now := time.Now().UTC()
+	return refreshWindow(ctx, pool, spec, now.Add(-spec.Policy.StartOffset), now.Add(-spec.Policy.EndOffset))
+}
+
+// BackfillAggregate runs spec's rollup once over [from, to) instead of its
+// Policy's rolling window, for populating a target grain from history
+// price_observations already accumulated in the source grain before
+// AggregateRefresher started keeping it current (see
+// cmd/main.go's `backfill-aggregates` subcommand).
+func BackfillAggregate(ctx context.Context, pool *pgxpool.Pool, spec AggregateSpec, from, to time.Time) error {
+	return refreshWindow(ctx, pool, spec, from, to)
+}
+
+func refreshWindow(ctx context.Context, pool *pgxpool.Pool, spec AggregateSpec, from, to time.Time) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (item_id, bucket_start, avg_high_price, high_price_volume, avg_low_price, low_price_volume)
+		SELECT
+			item_id,
+			to_timestamp(floor(extract(epoch FROM bucket_start) / $3) * $3) AS target_bucket_start,
+			CASE WHEN SUM(high_price_volume) > 0
+				THEN (SUM(avg_high_price::bigint * high_price_volume) / SUM(high_price_volume))::int
+				ELSE NULL
+			END,
+			SUM(high_price_volume),
+			CASE WHEN SUM(low_price_volume) > 0
+				THEN (SUM(avg_low_price::bigint * low_price_volume) / SUM(low_price_volume))::int
+				ELSE NULL
+			END,
+			SUM(low_price_volume)
+		FROM %s
+		WHERE bucket_start >= $1 AND bucket_start < $2
+		GROUP BY item_id, target_bucket_start
+		ON CONFLICT (item_id, bucket_start) DO UPDATE SET
+			avg_high_price = EXCLUDED.avg_high_price,
+			high_price_volume = EXCLUDED.high_price_volume,
+			avg_low_price = EXCLUDED.avg_low_price,
+			low_price_volume = EXCLUDED.low_price_volume
+	`, spec.TargetTable, spec.SourceTable)
+
+	if _, err := pool.Exec(ctx, query, from, to, spec.TargetWidth.Seconds()); err != nil {
+		return fmt.Errorf("refresh %s: %w", spec.Name, err)
+	}
+	return nil
+}
+
+// PruneExpired deletes rows older than BucketRetention from every bucket
+// table in bucketOrder, the enforcement side of the per-bucket retention
+// policies BucketRetention configures (previously nothing actually
+// enforced collector.RetentionPolicy -- it only bounded how far back
+// gap-filling looked, see collector/gap_filler.go).
+func PruneExpired(ctx context.Context, pool *pgxpool.Pool) error {
+	for _, size := range bucketOrder {
+		retention := BucketRetention[size]
+		if retention <= 0 {
+			continue
+		}
+		cutoff := time.Now().UTC().Add(-retention)
+		query := fmt.Sprintf(`DELETE FROM %s WHERE bucket_start < $1`, bucketTableName(size))
+		if _, err := pool.Exec(ctx, query, cutoff); err != nil {
+			return fmt.Errorf("prune %s: %w", size, err)
+		}
+	}
+	return nil
+}
+
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultCursorBatchSize is how many rows IterateLatestPrices/
+// IterateVolumeMetrics FETCH per round trip unless overridden via
+// WithBatchSize.
+const defaultCursorBatchSize = 500
+
+// IterateLatestPrices calls fn once per row of GetLatestPrices' query,
+// oldest-declared cursor first, without ever holding the full result set
+// in memory -- for callers like pkg/jobs' LLM feature extraction that only
+// need one item at a time out of a universe that can run into the tens of
+// thousands. Rows are fetched r.config.BatchSize at a time over a
+// server-side cursor inside a read-only transaction; ctx cancellation
+// aborts the FETCH loop and rolls the transaction back. fn's error, like
+// ctx's, stops iteration early and is returned unwrapped.
+func (r *QueryRepository) IterateLatestPrices(ctx context.Context, fn func(LatestPrice) error) (err error) {
+	start := time.Now()
+	statBefore := r.pool.Stat()
+	rows := 0
+	defer func() { r.recordQuery("IterateLatestPrices", "", start, statBefore, rows, err) }()
+
+	return r.withCursor(ctx, "latest_prices_cursor", `
+		SELECT DISTINCT ON (item_id)
+			item_id,
+			high_price,
+			high_time,
+			low_price,
+			low_time
+		FROM price_observations
+		ORDER BY item_id, observed_at DESC
+	`, nil, func(scan cursorScanner) error {
+		var p LatestPrice
+		if err := scan(&p.ItemID, &p.HighPrice, &p.HighTime, &p.LowPrice, &p.LowTime); err != nil {
+			return fmt.Errorf("scan price row: %w", err)
+		}
+		rows++
+		return fn(p)
+	})
+}
+
+// IterateVolumeMetrics is GetVolumeMetrics streamed row by row over a
+// server-side cursor instead of accumulated into a map, for the same
+// large-itemIDs-batch memory concern IterateLatestPrices addresses.
+// bucketSize and duration mean the same thing as in GetVolumeMetrics.
+func (r *QueryRepository) IterateVolumeMetrics(ctx context.Context, itemIDs []int, bucketSize string, duration time.Duration, fn func(BucketMetrics) error) (err error) {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	statBefore := r.pool.Stat()
+	rows := 0
+	defer func() {
+		r.recordQuery("IterateVolumeMetrics", bucketSize, start, statBefore, rows, err)
+	}()
+
+	tableName := bucketTableName(bucketSize)
+	cutoff := time.Now().UTC().Add(-duration)
+
+	query := fmt.Sprintf(`
+		SELECT
+			item_id,
+			CASE WHEN SUM(high_price_volume) > 0
+				THEN SUM(avg_high_price::bigint * high_price_volume) / SUM(high_price_volume)
+				ELSE NULL
+			END as avg_high_price,
+			SUM(high_price_volume) as high_price_volume,
+			CASE WHEN SUM(low_price_volume) > 0
+				THEN SUM(avg_low_price::bigint * low_price_volume) / SUM(low_price_volume)
+				ELSE NULL
+			END as avg_low_price,
+			SUM(low_price_volume) as low_price_volume
+		FROM %s
+		WHERE item_id = ANY($1)
+		  AND bucket_start >= $2
+		GROUP BY item_id
+	`, tableName)
+
+	return r.withCursor(ctx, "volume_metrics_cursor", query, []interface{}{itemIDs, cutoff}, func(scan cursorScanner) error {
+		var m BucketMetrics
+		if err := scan(&m.ItemID, &m.AvgHighPrice, &m.HighPriceVolume, &m.AvgLowPrice, &m.LowPriceVolume); err != nil {
+			return fmt.Errorf("scan metrics row: %w", err)
+		}
+		rows++
+		return fn(m)
+	})
+}
+
+// cursorScanner scans one FETCHed row's columns, the same signature
+// pgx.Rows.Scan has -- withCursor hands it to each caller's row callback
+// so IterateLatestPrices/IterateVolumeMetrics don't need to know how the
+// underlying cursor was opened.
+type cursorScanner func(dest ...interface{}) error
+
+// withCursor declares a server-side cursor named name for query (with
+// args) inside its own read-only transaction, then FETCH FORWARDs
+// r.config.BatchSize rows at a time, calling handleRow once per row until
+// rows run out, ctx is canceled, or handleRow returns an error. The
+// transaction (and its cursor) is always rolled back on the way out --
+// this is a read path, nothing it does needs to be committed.
+func (r *QueryRepository) withCursor(ctx context.Context, name, query string, args []interface{}, handleRow func(cursorScanner) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: begin tx: %w", name, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DECLARE %s CURSOR FOR %s", name, query), args...); err != nil {
+		return fmt.Errorf("%s: declare cursor: %w", name, err)
+	}
+
+	batchSize := r.config.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultCursorBatchSize
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		rows, err := tx.Query(ctx, fmt.Sprintf("FETCH FORWARD %d FROM %s", batchSize, name))
+		if err != nil {
+			return fmt.Errorf("%s: fetch: %w", name, err)
+		}
+
+		fetched := 0
+		for rows.Next() {
+			fetched++
+			if err := handleRow(rows.Scan); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if fetched < batchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// queryMetrics is QueryRepository's self-contained Prometheus registry and
+// instrument set, following the same per-component convention as
+// collector.repositoryMetrics and pkg/osrs/metrics.Metrics -- a registry
+// owned by the component rather than one shared Registerer threaded
+// through every constructor in the binary.
+type queryMetrics struct {
+	registry *prometheus.Registry
+
+	queryDuration   *prometheus.HistogramVec
+	rowsReturned    *prometheus.HistogramVec
+	poolWaitSeconds prometheus.Histogram
+	dataFreshness   prometheus.Gauge
+}
+
+func newQueryMetrics() *queryMetrics {
+	registry := prometheus.NewRegistry()
+
+	m := &queryMetrics{
+		registry: registry,
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "osrs_storage_query_duration_seconds",
+			Help:    "Duration of QueryRepository queries, by method, bucket_size, and status (ok/error).",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "bucket_size", "status"}),
+		rowsReturned: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "osrs_storage_query_rows_returned",
+			Help:    "Rows returned per QueryRepository query, by method.",
+			Buckets: []float64{0, 1, 10, 50, 100, 500, 1000, 5000, 10000},
+		}, []string{"method"}),
+		poolWaitSeconds: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "osrs_storage_pool_wait_seconds",
+			Help:    "Estimated time each query spent waiting to acquire a pgxpool connection, derived from the pool's cumulative AcquireDuration/AcquireCount delta across the call.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		dataFreshness: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "osrs_storage_data_freshness_seconds",
+			Help: "Age of the most recent price_observations row as of the last GetDataFreshness call.",
+		}),
+	}
+
+	registry.MustRegister(m.queryDuration, m.rowsReturned, m.poolWaitSeconds, m.dataFreshness)
+	return m
+}
+
+// NewMetricsHandler returns an http.Handler serving this QueryRepository's
+// metrics in the Prometheus text exposition format, for mounting at
+// /metrics alongside collector.Repository.NewMetricsHandler.
+func (r *QueryRepository) NewMetricsHandler() http.Handler {
+	return promhttp.HandlerFor(r.metrics.registry, promhttp.HandlerOpts{})
+}
+
+// recordQuery observes one query's duration, row count, and estimated pool
+// wait, and -- if WithLogger/WithSlowQueryThreshold are set -- logs it with
+// a fingerprint when it's slower than the configured threshold, mirroring
+// collector.Repository.logSlowQuery. bucketSize is "" for queries that
+// aren't bucket-table-scoped (e.g. GetLatestPrices).
+func (r *QueryRepository) recordQuery(method, bucketSize string, start time.Time, statBefore *pgxpool.Stat, rows int, err error) {
+	duration := time.Since(start)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+
+	r.metrics.queryDuration.WithLabelValues(method, bucketSize, status).Observe(duration.Seconds())
+	if err == nil {
+		r.metrics.rowsReturned.WithLabelValues(method).Observe(float64(rows))
+	}
+
+	if wait, ok := estimatePoolWait(statBefore, r.pool.Stat()); ok {
+		r.metrics.poolWaitSeconds.Observe(wait.Seconds())
+	}
+
+	r.logSlowQuery(method, bucketSize, duration, err)
+}
+
+// estimatePoolWait derives this call's approximate acquire wait from the
+// pool's cumulative AcquireCount/AcquireDuration delta across before/after
+// snapshots. It's only an estimate -- those counters are pool-wide, so
+// concurrent queries acquiring at the same time will smear into each
+// other's delta -- but it's the only acquire-wait signal pgxpool exposes
+// without replacing QueryRepository's plain pool.Query/QueryRow calls with
+// explicit Acquire/Release pairs.
+func estimatePoolWait(before, after *pgxpool.Stat) (time.Duration, bool) {
+	acquireDelta := after.AcquireDuration() - before.AcquireDuration()
+	countDelta := after.AcquireCount() - before.AcquireCount()
+	if countDelta <= 0 {
+		return 0, false
+	}
+	return acquireDelta / time.Duration(countDelta), true
+}
+
+// logSlowQuery logs method/bucketSize/duration via r.logger, fingerprinted
+// for triage, if duration exceeds r.slowQueryThreshold. A no-op when either
+// is unset, same as collector.Repository.logSlowQuery.
+func (r *QueryRepository) logSlowQuery(method, bucketSize string, duration time.Duration, err error) {
+	if r.logger == nil || r.slowQueryThreshold == 0 || duration < r.slowQueryThreshold {
+		return
+	}
+	fields := map[string]interface{}{
+		"method":       method,
+		"fingerprint":  queryFingerprint(method, bucketSize),
+		"duration_ms":  duration.Milliseconds(),
+		"threshold_ms": r.slowQueryThreshold.Milliseconds(),
+		"bucket_size":  bucketSize,
+	}
+	entry := r.logger.WithComponent("query_repository").WithFields(fields)
+	if err != nil {
+		entry.WithError(err).Warn("slow query")
+	} else {
+		entry.Warn("slow query")
+	}
+}
+
+// queryFingerprint returns a short, stable identifier for a method+bucketSize
+// query shape, for correlating "slow query" log lines with a specific
+// osrs_storage_query_duration_seconds series without repeating the full SQL
+// text on every line.
+func queryFingerprint(method, bucketSize string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{method, bucketSize}, "|")))
+	return hex.EncodeToString(sum[:])[:8]
+}
@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"osrs-flipping/pkg/logging"
+)
+
+// QueryRepositoryConfig tunes how GetMultiPeriodVolumeMetricsConcurrent
+// shards a large itemIDs batch across pgxpool connections. There's no
+// dedicated worker-pool package in this repo to build on (the closest
+// precedent, osrs.Analyzer.LoadVolumeData, rolls its own channel-based
+// pool per call site) so QueryRepository does the same here.
+type QueryRepositoryConfig struct {
+	// MaxConcurrency caps how many shards run their queries at once.
+	MaxConcurrency int
+	// ShardSize is how many item IDs each shard's queries cover.
+	ShardSize int
+	// BatchSize is how many rows IterateLatestPrices/IterateVolumeMetrics
+	// FETCH from their cursor per round trip.
+	BatchSize int
+}
+
+// DefaultQueryRepositoryConfig returns the MaxConcurrency/ShardSize/BatchSize
+// QueryRepository uses unless overridden via
+// WithMaxConcurrency/WithShardSize/WithBatchSize.
+func DefaultQueryRepositoryConfig() QueryRepositoryConfig {
+	return QueryRepositoryConfig{
+		MaxConcurrency: 4,
+		ShardSize:      500,
+		BatchSize:      defaultCursorBatchSize,
+	}
+}
+
+// QueryRepositoryOption configures optional QueryRepository behavior not
+// needed by every caller, following the same pattern as
+// collector.RepositoryOption.
+type QueryRepositoryOption func(*QueryRepository)
+
+// WithMaxConcurrency overrides DefaultQueryRepositoryConfig's
+// MaxConcurrency, bounding how many itemIDs shards
+// GetMultiPeriodVolumeMetricsConcurrent queries at once.
+func WithMaxConcurrency(n int) QueryRepositoryOption {
+	return func(r *QueryRepository) {
+		if n > 0 {
+			r.config.MaxConcurrency = n
+		}
+	}
+}
+
+// WithShardSize overrides DefaultQueryRepositoryConfig's ShardSize, the
+// number of item IDs GetMultiPeriodVolumeMetricsConcurrent puts in each
+// shard.
+func WithShardSize(n int) QueryRepositoryOption {
+	return func(r *QueryRepository) {
+		if n > 0 {
+			r.config.ShardSize = n
+		}
+	}
+}
+
+// WithBatchSize overrides DefaultQueryRepositoryConfig's BatchSize, the
+// number of rows IterateLatestPrices/IterateVolumeMetrics FETCH from their
+// cursor per round trip.
+func WithBatchSize(n int) QueryRepositoryOption {
+	return func(r *QueryRepository) {
+		if n > 0 {
+			r.config.BatchSize = n
+		}
+	}
+}
+
+// WithLogger sets the logger used for slow-query warnings. Without it,
+// slow queries are not logged even if WithSlowQueryThreshold is set --
+// the same contract as collector.WithLogger.
+func WithLogger(logger *logging.Logger) QueryRepositoryOption {
+	return func(r *QueryRepository) {
+		r.logger = logger
+	}
+}
+
+// WithSlowQueryThreshold logs a query's method, bucket size, and
+// fingerprint via WithLogger's logger whenever it takes longer than
+// threshold. Zero (the default) disables slow-query logging.
+func WithSlowQueryThreshold(threshold time.Duration) QueryRepositoryOption {
+	return func(r *QueryRepository) {
+		r.slowQueryThreshold = threshold
+	}
+}
+
+// shardItemIDs splits itemIDs into contiguous chunks of at most size
+// items each, preserving order within a chunk.
+func shardItemIDs(itemIDs []int, size int) [][]int {
+	if size <= 0 {
+		size = len(itemIDs)
+	}
+	var shards [][]int
+	for i := 0; i < len(itemIDs); i += size {
+		end := i + size
+		if end > len(itemIDs) {
+			end = len(itemIDs)
+		}
+		shards = append(shards, itemIDs[i:end])
+	}
+	return shards
+}
+
+// GetMultiPeriodVolumeMetricsConcurrent is GetMultiPeriodVolumeMetrics for
+// large itemIDs batches: itemIDs is split into r.config.ShardSize chunks,
+// and up to r.config.MaxConcurrency chunks run their 20m/1h/24h queries
+// plus GetLatestPricesForItems concurrently across the pool, instead of
+// one big ANY($1) query per period over every item at once. Results are
+// merged into a single map keyed by item ID; if any shard's queries fail,
+// their errors are joined and returned alongside whatever other shards
+// did complete.
+func (r *QueryRepository) GetMultiPeriodVolumeMetricsConcurrent(ctx context.Context, itemIDs []int) (map[int]*MultiPeriodMetrics, map[int]LatestPrice, error) {
+	if len(itemIDs) == 0 {
+		return make(map[int]*MultiPeriodMetrics), make(map[int]LatestPrice), nil
+	}
+
+	shards := shardItemIDs(itemIDs, r.config.ShardSize)
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		sem       = make(chan struct{}, r.config.MaxConcurrency)
+		metrics   = make(map[int]*MultiPeriodMetrics, len(itemIDs))
+		latest    = make(map[int]LatestPrice, len(itemIDs))
+		shardErrs []error
+	)
+
+	for _, shard := range shards {
+		shard := shard
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				mu.Lock()
+				shardErrs = append(shardErrs, ctx.Err())
+				mu.Unlock()
+				return
+			}
+			defer func() { <-sem }()
+
+			shardMetrics, shardLatest, err := r.fetchShard(ctx, shard)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				shardErrs = append(shardErrs, fmt.Errorf("shard of %d items: %w", len(shard), err))
+				return
+			}
+			for itemID, m := range shardMetrics {
+				metrics[itemID] = m
+			}
+			for itemID, p := range shardLatest {
+				latest[itemID] = p
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(shardErrs) > 0 {
+		return metrics, latest, fmt.Errorf("%d/%d shards failed: %w", len(shardErrs), len(shards), errors.Join(shardErrs...))
+	}
+	return metrics, latest, nil
+}
+
+// fetchShard runs one shard's period metrics and latest-price lookup
+// concurrently, the same way osrs.Analyzer.LoadVolumeData fans out a
+// single item's work -- just one level up, fanning out a single shard's
+// four queries instead of one item's single request.
+func (r *QueryRepository) fetchShard(ctx context.Context, itemIDs []int) (map[int]*MultiPeriodMetrics, map[int]LatestPrice, error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		errs   []error
+		result = make(map[int]*MultiPeriodMetrics, len(itemIDs))
+		latest map[int]LatestPrice
+
+		metrics20m, metrics1h, metrics24h map[int]BucketMetrics
+	)
+	for _, id := range itemIDs {
+		result[id] = &MultiPeriodMetrics{}
+	}
+
+	collect := func(fn func() error) {
+		defer wg.Done()
+		if err := fn(); err != nil {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}
+	}
+
+	wg.Add(4)
+	go collect(func() error {
+		var err error
+		duration := 20 * time.Minute
+		metrics20m, err = r.GetVolumeMetrics(ctx, itemIDs, selectBucketTable(duration), duration)
+		return err
+	})
+	go collect(func() error {
+		var err error
+		duration := time.Hour
+		metrics1h, err = r.GetVolumeMetrics(ctx, itemIDs, selectBucketTable(duration), duration)
+		return err
+	})
+	go collect(func() error {
+		var err error
+		duration := 24 * time.Hour
+		metrics24h, err = r.GetVolumeMetrics(ctx, itemIDs, selectBucketTable(duration), duration)
+		return err
+	})
+	go collect(func() error {
+		prices, err := r.GetLatestPricesForItems(ctx, itemIDs)
+		if err != nil {
+			return err
+		}
+		latest = make(map[int]LatestPrice, len(prices))
+		for _, p := range prices {
+			latest[p.ItemID] = p
+		}
+		return nil
+	})
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	for itemID, m := range metrics20m {
+		m := m
+		result[itemID].Metrics20m = &m
+	}
+	for itemID, m := range metrics1h {
+		m := m
+		result[itemID].Metrics1h = &m
+	}
+	for itemID, m := range metrics24h {
+		m := m
+		result[itemID].Metrics24h = &m
+	}
+
+	return result, latest, nil
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"osrs-flipping/pkg/logging"
 )
 
 // bucketTableName returns the table name for a given bucket size.
@@ -43,17 +45,36 @@ type BucketMetrics struct {
 
 // QueryRepository handles read operations for price data.
 type QueryRepository struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	config  QueryRepositoryConfig
+	metrics *queryMetrics
+
+	logger             *logging.Logger
+	slowQueryThreshold time.Duration
 }
 
-// NewQueryRepository creates a new QueryRepository.
-func NewQueryRepository(pool *pgxpool.Pool) *QueryRepository {
-	return &QueryRepository{pool: pool}
+// NewQueryRepository creates a new QueryRepository. See WithMaxConcurrency
+// and WithShardSize to tune GetMultiPeriodVolumeMetricsConcurrent's
+// sharding for large itemIDs batches, and WithLogger/WithSlowQueryThreshold
+// to enable fingerprinted slow-query logging; without them it uses
+// DefaultQueryRepositoryConfig and logs nothing. Every query is always
+// instrumented on this QueryRepository's own Prometheus registry --
+// see NewMetricsHandler.
+func NewQueryRepository(pool *pgxpool.Pool, opts ...QueryRepositoryOption) *QueryRepository {
+	r := &QueryRepository{pool: pool, config: DefaultQueryRepositoryConfig(), metrics: newQueryMetrics()}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // GetLatestPrices returns the most recent price observation for each item.
 // Uses DISTINCT ON to get the latest observation per item_id.
-func (r *QueryRepository) GetLatestPrices(ctx context.Context) ([]LatestPrice, error) {
+func (r *QueryRepository) GetLatestPrices(ctx context.Context) (prices []LatestPrice, err error) {
+	start := time.Now()
+	statBefore := r.pool.Stat()
+	defer func() { r.recordQuery("GetLatestPrices", "", start, statBefore, len(prices), err) }()
+
 	rows, err := r.pool.Query(ctx, `
 		SELECT DISTINCT ON (item_id)
 			item_id,
@@ -69,7 +90,6 @@ func (r *QueryRepository) GetLatestPrices(ctx context.Context) ([]LatestPrice, e
 	}
 	defer rows.Close()
 
-	var prices []LatestPrice
 	for rows.Next() {
 		var p LatestPrice
 		if err := rows.Scan(&p.ItemID, &p.HighPrice, &p.HighTime, &p.LowPrice, &p.LowTime); err != nil {
@@ -82,11 +102,17 @@ func (r *QueryRepository) GetLatestPrices(ctx context.Context) ([]LatestPrice, e
 }
 
 // GetLatestPricesForItems returns the most recent price observation for specific items.
-func (r *QueryRepository) GetLatestPricesForItems(ctx context.Context, itemIDs []int) ([]LatestPrice, error) {
+func (r *QueryRepository) GetLatestPricesForItems(ctx context.Context, itemIDs []int) (prices []LatestPrice, err error) {
 	if len(itemIDs) == 0 {
 		return nil, nil
 	}
 
+	start := time.Now()
+	statBefore := r.pool.Stat()
+	defer func() {
+		r.recordQuery("GetLatestPricesForItems", "", start, statBefore, len(prices), err)
+	}()
+
 	rows, err := r.pool.Query(ctx, `
 		SELECT DISTINCT ON (item_id)
 			item_id,
@@ -103,7 +129,6 @@ func (r *QueryRepository) GetLatestPricesForItems(ctx context.Context, itemIDs [
 	}
 	defer rows.Close()
 
-	var prices []LatestPrice
 	for rows.Next() {
 		var p LatestPrice
 		if err := rows.Scan(&p.ItemID, &p.HighPrice, &p.HighTime, &p.LowPrice, &p.LowTime); err != nil {
@@ -118,11 +143,17 @@ func (r *QueryRepository) GetLatestPricesForItems(ctx context.Context, itemIDs [
 // GetVolumeMetrics returns aggregated volume metrics for items over a time range.
 // bucketSize should be "5m", "1h", or "24h".
 // duration specifies how far back to aggregate (e.g., 1 hour, 24 hours).
-func (r *QueryRepository) GetVolumeMetrics(ctx context.Context, itemIDs []int, bucketSize string, duration time.Duration) (map[int]BucketMetrics, error) {
+func (r *QueryRepository) GetVolumeMetrics(ctx context.Context, itemIDs []int, bucketSize string, duration time.Duration) (result map[int]BucketMetrics, err error) {
 	if len(itemIDs) == 0 {
 		return make(map[int]BucketMetrics), nil
 	}
 
+	start := time.Now()
+	statBefore := r.pool.Stat()
+	defer func() {
+		r.recordQuery("GetVolumeMetrics", bucketSize, start, statBefore, len(result), err)
+	}()
+
 	tableName := bucketTableName(bucketSize)
 	cutoff := time.Now().UTC().Add(-duration)
 
@@ -152,7 +183,7 @@ func (r *QueryRepository) GetVolumeMetrics(ctx context.Context, itemIDs []int, b
 	}
 	defer rows.Close()
 
-	result := make(map[int]BucketMetrics)
+	result = make(map[int]BucketMetrics)
 	for rows.Next() {
 		var m BucketMetrics
 		if err := rows.Scan(&m.ItemID, &m.AvgHighPrice, &m.HighPriceVolume, &m.AvgLowPrice, &m.LowPriceVolume); err != nil {
@@ -165,47 +196,47 @@ func (r *QueryRepository) GetVolumeMetrics(ctx context.Context, itemIDs []int, b
 }
 
 // GetMultiPeriodVolumeMetrics returns volume metrics for multiple time periods.
-// Returns metrics for 20m, 1h, and 24h periods.
-func (r *QueryRepository) GetMultiPeriodVolumeMetrics(ctx context.Context, itemIDs []int) (map[int]*MultiPeriodMetrics, error) {
+// Returns metrics for 20m, 1h, and 24h periods, each read from
+// selectBucketTable's pick for that period's duration rather than a table
+// hardcoded per period -- so a period only reads a coarser table once that
+// table's BucketRetention can no longer guarantee the finer one covers it.
+// Each period is streamed in via IterateVolumeMetrics rather than
+// accumulated by GetVolumeMetrics, so only one bucketTableName result row at
+// a time is in memory per period, not the whole period's worth at once.
+func (r *QueryRepository) GetMultiPeriodVolumeMetrics(ctx context.Context, itemIDs []int) (result map[int]*MultiPeriodMetrics, err error) {
 	if len(itemIDs) == 0 {
 		return make(map[int]*MultiPeriodMetrics), nil
 	}
 
-	result := make(map[int]*MultiPeriodMetrics)
+	start := time.Now()
+	statBefore := r.pool.Stat()
+	defer func() {
+		r.recordQuery("GetMultiPeriodVolumeMetrics", "", start, statBefore, len(result), err)
+	}()
+
+	result = make(map[int]*MultiPeriodMetrics)
 	for _, id := range itemIDs {
 		result[id] = &MultiPeriodMetrics{}
 	}
 
-	// Get 20-minute metrics from 5m buckets
-	metrics20m, err := r.GetVolumeMetrics(ctx, itemIDs, "5m", 20*time.Minute)
-	if err != nil {
-		return nil, fmt.Errorf("get 20m metrics: %w", err)
-	}
-	for itemID, m := range metrics20m {
-		if mp, ok := result[itemID]; ok {
-			mp.Metrics20m = &m
-		}
-	}
-
-	// Get 1-hour metrics from 1h buckets (or aggregate from 5m if more accurate)
-	metrics1h, err := r.GetVolumeMetrics(ctx, itemIDs, "5m", 1*time.Hour)
-	if err != nil {
-		return nil, fmt.Errorf("get 1h metrics: %w", err)
-	}
-	for itemID, m := range metrics1h {
-		if mp, ok := result[itemID]; ok {
-			mp.Metrics1h = &m
-		}
+	periods := []struct {
+		duration time.Duration
+		assign   func(*MultiPeriodMetrics, BucketMetrics)
+	}{
+		{20 * time.Minute, func(mp *MultiPeriodMetrics, m BucketMetrics) { mp.Metrics20m = &m }},
+		{time.Hour, func(mp *MultiPeriodMetrics, m BucketMetrics) { mp.Metrics1h = &m }},
+		{24 * time.Hour, func(mp *MultiPeriodMetrics, m BucketMetrics) { mp.Metrics24h = &m }},
 	}
 
-	// Get 24-hour metrics from 1h buckets
-	metrics24h, err := r.GetVolumeMetrics(ctx, itemIDs, "1h", 24*time.Hour)
-	if err != nil {
-		return nil, fmt.Errorf("get 24h metrics: %w", err)
-	}
-	for itemID, m := range metrics24h {
-		if mp, ok := result[itemID]; ok {
-			mp.Metrics24h = &m
+	for _, period := range periods {
+		err := r.IterateVolumeMetrics(ctx, itemIDs, selectBucketTable(period.duration), period.duration, func(m BucketMetrics) error {
+			if mp, ok := result[m.ItemID]; ok {
+				period.assign(mp, m)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("iterate %s metrics: %w", period.duration, err)
 		}
 	}
 
@@ -221,9 +252,19 @@ type MultiPeriodMetrics struct {
 
 // GetDataFreshness returns the timestamp of the most recent observation.
 // Returns nil if no data exists.
-func (r *QueryRepository) GetDataFreshness(ctx context.Context) (*time.Time, error) {
+func (r *QueryRepository) GetDataFreshness(ctx context.Context) (freshness *time.Time, err error) {
+	start := time.Now()
+	statBefore := r.pool.Stat()
+	defer func() {
+		rows := 0
+		if freshness != nil {
+			rows = 1
+		}
+		r.recordQuery("GetDataFreshness", "", start, statBefore, rows, err)
+	}()
+
 	var t time.Time
-	err := r.pool.QueryRow(ctx, `
+	err = r.pool.QueryRow(ctx, `
 		SELECT observed_at FROM price_observations
 		ORDER BY observed_at DESC
 		LIMIT 1
@@ -235,6 +276,8 @@ func (r *QueryRepository) GetDataFreshness(ctx context.Context) (*time.Time, err
 	if err != nil {
 		return nil, fmt.Errorf("query data freshness: %w", err)
 	}
+
+	r.metrics.dataFreshness.Set(time.Since(t).Seconds())
 	return &t, nil
 }
 
@@ -251,10 +294,130 @@ func (r *QueryRepository) IsDataFresh(ctx context.Context, threshold time.Durati
 	return time.Since(*freshness) <= threshold, nil
 }
 
+// SpreadStats summarizes a bucket table's high/low spread for one item over
+// a time range, for the market-making quote suggestion in
+// osrs.Analyzer.applySpreadSignal.
+type SpreadStats struct {
+	ItemID       int
+	Mid          float64
+	Spread       float64
+	SpreadStdDev float64
+}
+
+// GetSpreadStats returns each item's average midprice, average spread, and
+// the spread's population standard deviation over the given bucket table
+// and duration -- the SQL-side counterpart to osrs.Analyzer.applySpreadSignal
+// for callers that want it computed without loading the full bucket rows.
+// bucketSize should be "5m", "1h", or "24h".
+func (r *QueryRepository) GetSpreadStats(ctx context.Context, itemIDs []int, bucketSize string, duration time.Duration) (result map[int]SpreadStats, err error) {
+	if len(itemIDs) == 0 {
+		return make(map[int]SpreadStats), nil
+	}
+
+	start := time.Now()
+	statBefore := r.pool.Stat()
+	defer func() {
+		r.recordQuery("GetSpreadStats", bucketSize, start, statBefore, len(result), err)
+	}()
+
+	tableName := bucketTableName(bucketSize)
+	cutoff := time.Now().UTC().Add(-duration)
+
+	query := fmt.Sprintf(`
+		SELECT
+			item_id,
+			AVG((avg_high_price + avg_low_price) / 2.0) as mid,
+			AVG(avg_high_price - avg_low_price) as spread,
+			STDDEV_POP(avg_high_price - avg_low_price) as spread_stddev
+		FROM %s
+		WHERE item_id = ANY($1)
+		  AND bucket_start >= $2
+		  AND avg_high_price IS NOT NULL
+		  AND avg_low_price IS NOT NULL
+		GROUP BY item_id
+	`, tableName)
+
+	rows, err := r.pool.Query(ctx, query, itemIDs, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("query spread stats from %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	result = make(map[int]SpreadStats)
+	for rows.Next() {
+		var s SpreadStats
+		var stddev *float64
+		if err := rows.Scan(&s.ItemID, &s.Mid, &s.Spread, &stddev); err != nil {
+			return nil, fmt.Errorf("scan spread stats row: %w", err)
+		}
+		if stddev != nil {
+			s.SpreadStdDev = *stddev
+		}
+		result[s.ItemID] = s
+	}
+
+	return result, rows.Err()
+}
+
+// Bucket is one raw bucket row for backtest replay. Unlike BucketMetrics,
+// which GetVolumeMetrics pre-aggregates across a whole window,
+// LoadBucketsForBacktest returns one Bucket per stored row so a caller can
+// step through them in order.
+type Bucket struct {
+	ItemID          int
+	BucketStart     time.Time
+	AvgHighPrice    *int
+	HighPriceVolume *int64
+	AvgLowPrice     *int
+	LowPriceVolume  *int64
+}
+
+// LoadBucketsForBacktest returns itemID's bucket rows from bucketSize's
+// table (see bucketTableName), oldest first, between from and to
+// inclusive -- the raw replay material backtest.Strategy implementations
+// step through tick by tick.
+func (r *QueryRepository) LoadBucketsForBacktest(ctx context.Context, itemID int, bucketSize string, from, to time.Time) (buckets []Bucket, err error) {
+	start := time.Now()
+	statBefore := r.pool.Stat()
+	defer func() {
+		r.recordQuery("LoadBucketsForBacktest", bucketSize, start, statBefore, len(buckets), err)
+	}()
+
+	tableName := bucketTableName(bucketSize)
+
+	query := fmt.Sprintf(`
+		SELECT item_id, bucket_start, avg_high_price, high_price_volume, avg_low_price, low_price_volume
+		FROM %s
+		WHERE item_id = $1
+		  AND bucket_start >= $2
+		  AND bucket_start <= $3
+		ORDER BY bucket_start ASC
+	`, tableName)
+
+	rows, err := r.pool.Query(ctx, query, itemID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("query backtest buckets from %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b Bucket
+		if err := rows.Scan(&b.ItemID, &b.BucketStart, &b.AvgHighPrice, &b.HighPriceVolume, &b.AvgLowPrice, &b.LowPriceVolume); err != nil {
+			return nil, fmt.Errorf("scan backtest bucket row: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+
+	return buckets, rows.Err()
+}
+
 // GetItemCount returns the number of distinct items in observations.
-func (r *QueryRepository) GetItemCount(ctx context.Context) (int, error) {
-	var count int
-	err := r.pool.QueryRow(ctx, `
+func (r *QueryRepository) GetItemCount(ctx context.Context) (count int, err error) {
+	start := time.Now()
+	statBefore := r.pool.Stat()
+	defer func() { r.recordQuery("GetItemCount", "", start, statBefore, count, err) }()
+
+	err = r.pool.QueryRow(ctx, `
 		SELECT COUNT(DISTINCT item_id) FROM price_observations
 	`).Scan(&count)
 	if err != nil {